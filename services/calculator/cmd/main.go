@@ -2,12 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,8 +16,22 @@ import (
 	"github.com/sloweyyy/GreenLedger/services/calculator/internal/service"
 	"github.com/sloweyyy/GreenLedger/shared/config"
 	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/events"
+	"github.com/sloweyyy/GreenLedger/shared/featureflags"
 	sharedLogger "github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/sloweyyy/GreenLedger/shared/middleware"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
+	"github.com/sloweyyy/GreenLedger/shared/proto/reportdata"
+	sharedServer "github.com/sloweyyy/GreenLedger/shared/server"
+	"github.com/sloweyyy/GreenLedger/shared/version"
+	"google.golang.org/grpc"
+)
+
+// Build information, injected at build time via -ldflags "-X main.Version=..."
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
 )
 
 // @title GreenLedger Calculator Service API
@@ -56,16 +69,18 @@ func main() {
 	// Initialize logger
 	logger := sharedLogger.New(cfg.Server.LogLevel).WithService("calculator")
 
+	// Initialize metrics
+	metrics := monitoring.NewMetrics("calculator")
+
 	// Initialize database
 	db, err := database.NewPostgresDB(&cfg.Database, logger)
 	if err != nil {
 		logger.LogError(context.Background(), "failed to connect to database", err)
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer db.Close()
 
 	// Run database migrations
-	if err := db.Migrate(&models.Calculation{}, &models.Activity{}, &models.EmissionFactor{}); err != nil {
+	if err := db.Migrate(&models.Calculation{}, &models.Activity{}, &models.EmissionFactor{}, &models.EmissionFactorVersion{}, &events.DeadLetter{}); err != nil {
 		logger.LogError(context.Background(), "failed to run migrations", err)
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
@@ -74,24 +89,55 @@ func main() {
 	calculationRepo := repository.NewCalculationRepository(db, logger)
 	emissionFactorRepo := repository.NewEmissionFactorRepository(db, logger)
 
+	// Events this service's consumers give up on
+	deadLetters := events.NewDeadLetterQueue(db, logger)
+
+	// Serve the emission factor lookups of calculations from memory
+	var factorRepo repository.EmissionFactorRepositoryInterface = emissionFactorRepo
+	var factorCache *service.EmissionFactorCache
+	if cfg.Calculator.FactorCacheTTL > 0 {
+		factorCache = service.NewEmissionFactorCache(emissionFactorRepo, cfg.Calculator.FactorCacheTTL, logger)
+		factorRepo = factorCache
+	}
+
 	// Initialize services
-	calculatorService := service.NewCalculatorService(calculationRepo, emissionFactorRepo, logger)
+	calculatorService := service.NewCalculatorService(calculationRepo, factorRepo, metrics, logger)
+
+	// Seed default emission factors before serving, since every calculation
+	// looks one up
+	if err := initializeEmissionFactors(context.Background(), emissionFactorRepo, calculatorService, logger); err != nil {
+		logger.LogError(context.Background(), "failed to initialize emission factors", err)
+		log.Fatalf("Failed to initialize emission factors: %v", err)
+	}
+	if factorCache != nil {
+		// Calculations fall back to the database if this fails
+		if err := factorCache.Warm(context.Background()); err != nil {
+			logger.LogError(context.Background(), "failed to warm emission factor cache", err)
+		}
+	}
 
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(cfg.Server.JWTSecret, logger)
+	authMiddleware := middleware.NewAuthMiddleware(cfg.Server.JWTSecret, cfg.Server.JWTIssuer, cfg.Server.JWTAudience, logger)
 
 	// Initialize handlers
 	calculatorHandler := handler.NewCalculatorHandler(calculatorService, logger)
 
 	// Setup Gin router
-	if cfg.Server.Environment == "production" {
+	if cfg.Features.Enabled(featureflags.ReleaseMode) {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.LogError(context.Background(), "failed to set trusted proxies", err)
+		log.Fatalf("Failed to set trusted proxies: %v", err)
+	}
 	router.Use(gin.Recovery())
-	router.Use(middleware.RequestLogger(logger))
+	router.Use(middleware.RequestLogger(logger, cfg.Server.SlowRequestThreshold))
+	router.Use(middleware.Pagination(cfg.Pagination.DefaultLimit, cfg.Pagination.MaxLimit))
+	router.Use(middleware.BatchLimit(cfg.Pagination.MaxBatchItems))
 	router.Use(middleware.CORS())
+	router.Use(middleware.MaxBodySize(cfg.Server.MaxBodyBytes))
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -105,13 +151,20 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "healthy",
 			"service": "calculator",
-			"version": "1.0.0",
+			"version": Version,
 		})
 	})
 
+	// Prometheus metrics endpoint
+	router.GET("/metrics", monitoring.MetricsHandler())
+
+	// Build info endpoint
+	router.GET("/version", version.Handler(version.New("calculator", Version, GitCommit, BuildTime)))
+
 	// API routes
 	v1 := router.Group("/api/v1")
 	calculatorHandler.RegisterRoutes(v1, authMiddleware)
+	events.NewDeadLetterHandler(deadLetters, logger).RegisterRoutes(v1.Group("/calculator"), authMiddleware)
 
 	// Swagger documentation
 	// router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -125,53 +178,73 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	// Start server in a goroutine
-	go func() {
-		logger.LogInfo(context.Background(), "starting calculator service",
-			sharedLogger.Int("port", cfg.Server.Port),
-			sharedLogger.String("environment", cfg.Server.Environment))
+	// Cancelled on SIGINT/SIGTERM so background consumers stop before their
+	// readers, the event publisher and the database are closed
+	ctx, stop := sharedServer.NotifyContext(context.Background())
+	defer stop()
 
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.LogError(context.Background(), "failed to start server", err)
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
+	// Serve report data to the reporting service over gRPC
+	grpcServer := grpc.NewServer()
+	reportdata.RegisterCalculatorDataServer(grpcServer, handler.NewReportDataServer(db, logger))
+	grpcCloser, err := sharedServer.ServeGRPC(grpcServer, fmt.Sprintf(":%d", cfg.Server.GRPCPort), logger)
+	if err != nil {
+		logger.LogError(context.Background(), "failed to start gRPC server", err)
+		log.Fatalf("Failed to start gRPC server: %v", err)
+	}
 
-	// Initialize default emission factors
-	go func() {
-		if err := initializeEmissionFactors(context.Background(), emissionFactorRepo, logger); err != nil {
-			logger.LogError(context.Background(), "failed to initialize emission factors", err)
-		}
-	}()
+	closers := []io.Closer{grpcCloser}
 
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	// Start user event consumer for erasure requests
+	if cfg.Features.Enabled(featureflags.Kafka) {
+		userConsumer := events.NewUserEventConsumer(cfg.Kafka.Brokers, "calculator-service", deadLetters, logger)
+		closers = append(closers, userConsumer)
 
-	logger.LogInfo(context.Background(), "shutting down calculator service")
+		go func() {
+			err := userConsumer.Consume(ctx, func(ctx context.Context, event *events.UserDeletedEvent) error {
+				return calculatorService.EraseUserData(ctx, event.UserID)
+			})
+			if err != nil && !errors.Is(err, context.Canceled) {
+				logger.LogError(context.Background(), "user event consumer error", err)
+			}
+		}()
+	}
+
+	closers = append(closers, db)
 
-	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	logger.LogInfo(context.Background(), "starting calculator service",
+		sharedLogger.Int("port", cfg.Server.Port),
+		sharedLogger.Int("grpc_port", cfg.Server.GRPCPort),
+		sharedLogger.String("environment", cfg.Server.Environment),
+		sharedLogger.Any("features", cfg.Features.EnabledNames()))
 
-	if err := server.Shutdown(ctx); err != nil {
-		logger.LogError(context.Background(), "server forced to shutdown", err)
-		log.Fatalf("Server forced to shutdown: %v", err)
+	if err := sharedServer.RunWithGracefulShutdown(ctx, server, logger, closers...); err != nil {
+		log.Fatalf("calculator service stopped with error: %v", err)
 	}
 
 	logger.LogInfo(context.Background(), "calculator service stopped")
 }
 
 // initializeEmissionFactors initializes default emission factors
-func initializeEmissionFactors(ctx context.Context, repo *repository.EmissionFactorRepository, logger *sharedLogger.Logger) error {
+func initializeEmissionFactors(ctx context.Context, repo *repository.EmissionFactorRepository, calculatorService *service.CalculatorService, logger *sharedLogger.Logger) error {
 	// Check if emission factors already exist
-	factors, _, err := repo.GetAll(ctx, "", "", 1, 0)
+	factors, _, err := repo.GetAll(ctx, "", "", -1, 0)
 	if err != nil {
 		return fmt.Errorf("failed to check existing emission factors: %w", err)
 	}
 
 	if len(factors) > 0 {
+		// Flag stored factors whose unit no longer matches the expected activity input
+		for _, factor := range factors {
+			if err := factor.ValidateUnit(); err != nil {
+				logger.LogWarn(ctx, "emission factor has inconsistent unit",
+					sharedLogger.String("factor_id", factor.ID.String()),
+					sharedLogger.String("activity_type", factor.ActivityType),
+					sharedLogger.String("sub_type", factor.SubType),
+					sharedLogger.String("unit", factor.Unit),
+					sharedLogger.String("error", err.Error()))
+			}
+		}
+
 		logger.LogInfo(ctx, "emission factors already initialized")
 		return nil
 	}
@@ -219,7 +292,7 @@ func initializeEmissionFactors(ctx context.Context, repo *repository.EmissionFac
 		factor.LastUpdated = now
 	}
 
-	if err := repo.BulkCreate(ctx, defaultFactors); err != nil {
+	if err := calculatorService.ImportEmissionFactors(ctx, defaultFactors); err != nil {
 		return fmt.Errorf("failed to create default emission factors: %w", err)
 	}
 
@@ -0,0 +1,163 @@
+// Package airports looks up airport coordinates by IATA code and computes
+// great-circle distances between airports.
+package airports
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0088
+
+// ErrUnknownAirport is returned for an IATA code that is not in the database
+var ErrUnknownAirport = errors.New("unknown airport")
+
+//go:embed airports.csv
+var embeddedData []byte
+
+// defaultDatabase is loaded from the embedded data file
+var defaultDatabase = mustLoad(bytes.NewReader(embeddedData))
+
+// Airport is an airport and its location
+type Airport struct {
+	IATA      string
+	Name      string
+	Country   string
+	Latitude  float64
+	Longitude float64
+}
+
+// Database holds airports keyed by IATA code
+type Database struct {
+	airports map[string]Airport
+}
+
+// Default returns the database of major airports embedded in the binary
+func Default() *Database {
+	return defaultDatabase
+}
+
+// LoadFile reads an airport database from a CSV file; see Load for the format
+func LoadFile(path string) (*Database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open airport data: %w", err)
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+// Load reads an airport database from CSV with the header
+// iata,name,country,latitude,longitude and coordinates in decimal degrees
+func Load(r io.Reader) (*Database, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 5
+
+	if _, err := reader.Read(); err != nil {
+		return nil, fmt.Errorf("failed to read airport data header: %w", err)
+	}
+
+	db := &Database{airports: make(map[string]Airport)}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read airport data: %w", err)
+		}
+
+		airport, err := parseAirport(record)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("airport data line %d: %w", line, err)
+		}
+		if _, exists := db.airports[airport.IATA]; exists {
+			return nil, fmt.Errorf("airport data lists %s twice", airport.IATA)
+		}
+		db.airports[airport.IATA] = airport
+	}
+
+	return db, nil
+}
+
+// Lookup returns the airport with the given IATA code, ignoring case
+func (db *Database) Lookup(code string) (Airport, bool) {
+	airport, ok := db.airports[normalizeCode(code)]
+	return airport, ok
+}
+
+// Distance returns the great-circle distance in kilometers between two
+// airports given by IATA code
+func (db *Database) Distance(from, to string) (float64, error) {
+	departure, ok := db.Lookup(from)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownAirport, from)
+	}
+	arrival, ok := db.Lookup(to)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownAirport, to)
+	}
+
+	return Haversine(departure.Latitude, departure.Longitude, arrival.Latitude, arrival.Longitude), nil
+}
+
+// Haversine returns the great-circle distance in kilometers between two
+// points given in decimal degrees
+func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	dPhi := (lat2 - lat1) * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// parseAirport parses and validates one CSV record
+func parseAirport(record []string) (Airport, error) {
+	code := normalizeCode(record[0])
+	if len(code) != 3 {
+		return Airport{}, fmt.Errorf("invalid IATA code %q", record[0])
+	}
+
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return Airport{}, fmt.Errorf("invalid latitude %q for %s", record[3], code)
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(record[4]), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return Airport{}, fmt.Errorf("invalid longitude %q for %s", record[4], code)
+	}
+
+	return Airport{
+		IATA:      code,
+		Name:      strings.TrimSpace(record[1]),
+		Country:   strings.TrimSpace(record[2]),
+		Latitude:  latitude,
+		Longitude: longitude,
+	}, nil
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func mustLoad(r io.Reader) *Database {
+	db, err := Load(r)
+	if err != nil {
+		panic(fmt.Sprintf("airports: invalid embedded data: %v", err))
+	}
+	return db
+}
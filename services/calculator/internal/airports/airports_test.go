@@ -0,0 +1,96 @@
+package airports
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDistance_KnownRoutes(t *testing.T) {
+	// Great-circle distances on a spherical Earth, in km
+	tests := []struct {
+		from, to string
+		expected float64
+	}{
+		{"LAX", "JFK", 3974},
+		{"JFK", "LHR", 5540},
+		{"LHR", "CDG", 347},
+		{"NRT", "LAX", 8753},
+		{"SYD", "LAX", 12051},
+		{"SIN", "LHR", 10881},
+	}
+
+	for _, tt := range tests {
+		distance, err := Default().Distance(tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("%s-%s: unexpected error %v", tt.from, tt.to, err)
+		}
+		if math.Abs(distance-tt.expected)/tt.expected > 0.005 {
+			t.Errorf("%s-%s: expected about %.0f km, got %.1f", tt.from, tt.to, tt.expected, distance)
+		}
+	}
+}
+
+func TestDistance_IsSymmetricAndCaseInsensitive(t *testing.T) {
+	there, _ := Default().Distance("lhr", " jfk ")
+	back, _ := Default().Distance("JFK", "LHR")
+	if there == 0 || math.Abs(there-back) > 1e-9 {
+		t.Errorf("Expected the same distance both ways, got %v and %v", there, back)
+	}
+}
+
+func TestDistance_UnknownAirport(t *testing.T) {
+	if _, err := Default().Distance("LHR", "XXX"); !errors.Is(err, ErrUnknownAirport) {
+		t.Errorf("Expected ErrUnknownAirport, got %v", err)
+	}
+	if _, err := Default().Distance("", "LHR"); !errors.Is(err, ErrUnknownAirport) {
+		t.Errorf("Expected ErrUnknownAirport for an empty code, got %v", err)
+	}
+}
+
+func TestHaversine(t *testing.T) {
+	if d := Haversine(10, 20, 10, 20); d != 0 {
+		t.Errorf("Expected zero distance for the same point, got %v", d)
+	}
+
+	// A quarter of the way round the equator
+	if d := Haversine(0, 0, 0, 90); math.Abs(d-math.Pi*earthRadiusKm/2) > 1e-6 {
+		t.Errorf("Expected a quarter circumference, got %v", d)
+	}
+
+	// Antipodal points are half the circumference apart
+	if d := Haversine(0, 0, 0, 180); math.Abs(d-math.Pi*earthRadiusKm) > 1e-6 {
+		t.Errorf("Expected half the circumference, got %v", d)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	data := "iata,name,country,latitude,longitude\nabc,Test Field,XX,1.5,-2.25\n"
+
+	db, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Expected valid data to load, got %v", err)
+	}
+	airport, ok := db.Lookup("ABC")
+	if !ok || airport.Name != "Test Field" || airport.Latitude != 1.5 || airport.Longitude != -2.25 {
+		t.Errorf("Expected ABC loaded with its coordinates, got %+v", airport)
+	}
+}
+
+func TestLoad_RejectsInvalidData(t *testing.T) {
+	header := "iata,name,country,latitude,longitude\n"
+	tests := map[string]string{
+		"bad code":          "ABCD,Field,XX,1,2\n",
+		"bad latitude":      "ABC,Field,XX,91,2\n",
+		"bad longitude":     "ABC,Field,XX,1,east\n",
+		"missing column":    "ABC,Field,1,2\n",
+		"duplicate airport": "ABC,Field,XX,1,2\nabc,Other,XX,3,4\n",
+	}
+
+	for name, rows := range tests {
+		if _, err := Load(strings.NewReader(header + rows)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -2,12 +2,14 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/calculator/internal/models"
 	"github.com/sloweyyy/GreenLedger/services/calculator/internal/service"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/httperr"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/sloweyyy/GreenLedger/shared/middleware"
 )
@@ -15,6 +17,7 @@ import (
 // CalculatorHandler handles HTTP requests for carbon footprint calculations
 type CalculatorHandler struct {
 	calculatorService *service.CalculatorService
+	errMapper         *httperr.Mapper
 	logger            *logger.Logger
 }
 
@@ -22,7 +25,16 @@ type CalculatorHandler struct {
 func NewCalculatorHandler(calculatorService *service.CalculatorService, logger *logger.Logger) *CalculatorHandler {
 	return &CalculatorHandler{
 		calculatorService: calculatorService,
-		logger:            logger,
+		errMapper: httperr.New(logger,
+			httperr.Mapping{Err: service.ErrInvalidActivity, Status: http.StatusBadRequest, Message: "Invalid activity data"},
+			httperr.Mapping{Err: service.ErrUnknownActivityType, Status: http.StatusBadRequest, Message: "Unknown activity type"},
+			httperr.Mapping{Err: service.ErrEmissionFactorNotFound, Status: http.StatusNotFound, Message: "Emission factor not found"},
+			httperr.Mapping{Err: service.ErrEmissionFactorRetired, Status: http.StatusConflict, Message: "Emission factor is in use and was retired instead of deleted"},
+			httperr.Mapping{Err: service.ErrInvalidEmissionFactor, Status: http.StatusBadRequest, Message: "Invalid emission factor"},
+			httperr.Mapping{Err: service.ErrEmissionFactorConflict, Status: http.StatusConflict, Message: "Emission factor conflicts with an existing factor"},
+			httperr.Mapping{Err: database.ErrNotFound, Status: http.StatusNotFound, Message: "Calculation not found"},
+		),
+		logger: logger,
 	}
 }
 
@@ -32,6 +44,7 @@ func (h *CalculatorHandler) RegisterRoutes(router *gin.RouterGroup, authMiddlewa
 	{
 		// Public routes
 		calculator.GET("/emission-factors", h.GetEmissionFactors)
+		calculator.GET("/emission-factors/grouped", h.GetGroupedEmissionFactors)
 		calculator.GET("/emission-factors/:activity_type", h.GetEmissionFactorsByType)
 
 		// Protected routes
@@ -39,13 +52,24 @@ func (h *CalculatorHandler) RegisterRoutes(router *gin.RouterGroup, authMiddlewa
 		calculator.POST("/calculate", h.CalculateFootprint)
 		calculator.GET("/calculations", h.GetCalculationHistory)
 		calculator.GET("/calculations/:id", h.GetCalculationByID)
+		calculator.POST("/calculations/:id/recalculate", h.Recalculate)
 		calculator.GET("/stats", h.GetUserStats)
+
+		// Admin routes
+		admin := calculator.Group("/admin")
+		admin.Use(authMiddleware.RequireRole("admin"))
+		{
+			admin.POST("/emission-factors", h.UpsertEmissionFactor)
+			admin.PUT("/emission-factors/:id", h.UpdateEmissionFactor)
+			admin.GET("/emission-factors/:id/versions", h.GetEmissionFactorVersions)
+			admin.DELETE("/emission-factors/:id", h.DeleteEmissionFactor)
+		}
 	}
 }
 
 // CalculateFootprint godoc
 // @Summary Calculate carbon footprint
-// @Description Calculate carbon footprint for given activities
+// @Description Calculate carbon footprint for given activities. The number of activities is capped by MAX_BATCH_ITEMS.
 // @Tags calculator
 // @Accept json
 // @Produce json
@@ -53,6 +77,7 @@ func (h *CalculatorHandler) RegisterRoutes(router *gin.RouterGroup, authMiddlewa
 // @Success 200 {object} service.CalculateFootprintResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
 // @Router /calculator/calculate [post]
@@ -67,6 +92,9 @@ func (h *CalculatorHandler) CalculateFootprint(c *gin.Context) {
 		})
 		return
 	}
+	if !middleware.CheckBatchSize(c, len(req.Activities)) {
+		return
+	}
 
 	// Get user ID from context (set by auth middleware)
 	userID, exists := middleware.GetUserID(c)
@@ -78,12 +106,8 @@ func (h *CalculatorHandler) CalculateFootprint(c *gin.Context) {
 
 	response, err := h.calculatorService.CalculateFootprint(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to calculate footprint", err,
+		h.errMapper.Respond(c, err, "Failed to calculate footprint",
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to calculate footprint",
-			Details: err.Error(),
-		})
 		return
 	}
 
@@ -97,7 +121,8 @@ func (h *CalculatorHandler) CalculateFootprint(c *gin.Context) {
 // @Produce json
 // @Param start_date query string false "Start date (RFC3339 format)"
 // @Param end_date query string false "End date (RFC3339 format)"
-// @Param limit query int false "Limit" default(20)
+// @Param category query string false "Only return calculations in this category"
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
 // @Param offset query int false "Offset" default(0)
 // @Success 200 {object} CalculationHistoryResponse
 // @Failure 400 {object} ErrorResponse
@@ -113,8 +138,7 @@ func (h *CalculatorHandler) GetCalculationHistory(c *gin.Context) {
 	}
 
 	// Parse query parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, offset := middleware.GetPagination(c)
 
 	var startDate, endDate *time.Time
 	if startDateStr := c.Query("start_date"); startDateStr != "" {
@@ -129,22 +153,16 @@ func (h *CalculatorHandler) GetCalculationHistory(c *gin.Context) {
 	}
 
 	calculations, total, err := h.calculatorService.GetCalculationHistory(
-		c.Request.Context(), userID, startDate, endDate, limit, offset)
+		c.Request.Context(), userID, c.Query("category"), startDate, endDate, limit, offset)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to get calculation history", err,
+		h.errMapper.Respond(c, err, "Failed to get calculation history",
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to get calculation history",
-			Details: err.Error(),
-		})
 		return
 	}
 
 	response := CalculationHistoryResponse{
 		Calculations: calculations,
-		Total:        total,
-		Limit:        limit,
-		Offset:       offset,
+		PageInfo:     middleware.NewPageInfo(total, limit, offset),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -176,16 +194,63 @@ func (h *CalculatorHandler) GetCalculationByID(c *gin.Context) {
 
 	calculation, err := h.calculatorService.GetCalculationByID(c.Request.Context(), id)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to get calculation", err,
+		h.errMapper.Respond(c, err, "Failed to get calculation",
 			logger.String("calculation_id", id.String()))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to get calculation",
+		return
+	}
+
+	c.JSON(http.StatusOK, calculation)
+}
+
+// Recalculate godoc
+// @Summary Recalculate a calculation with overrides
+// @Description Recompute one of the user's calculations with activity data overrides, e.g. {"overrides": {"*": {"vehicle_type": "car_electric"}}}, and return the difference without saving it. Overrides are keyed by activity ID, or "*" to replace a field in every activity that has it.
+// @Tags calculator
+// @Accept json
+// @Produce json
+// @Param id path string true "Calculation ID"
+// @Param request body service.RecalculateRequest true "Overrides"
+// @Success 200 {object} service.RecalculationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /calculator/calculations/{id}/recalculate [post]
+func (h *CalculatorHandler) Recalculate(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid calculation ID",
 			Details: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, calculation)
+	var req service.RecalculateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.calculatorService.Recalculate(c.Request.Context(), id, userID, req.Overrides)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to recalculate",
+			logger.String("calculation_id", id.String()),
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // GetUserStats godoc
@@ -236,58 +301,257 @@ func (h *CalculatorHandler) GetUserStats(c *gin.Context) {
 
 // GetEmissionFactors godoc
 // @Summary Get emission factors
-// @Description Get all emission factors with optional filtering
+// @Description Get emission factors ordered by activity type and sub-type, optionally of one activity type. A location filter returns that location's factors and the global ones.
 // @Tags calculator
 // @Produce json
 // @Param activity_type query string false "Activity type filter"
 // @Param location query string false "Location filter"
-// @Param limit query int false "Limit" default(50)
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
 // @Param offset query int false "Offset" default(0)
 // @Success 200 {object} EmissionFactorsResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /calculator/emission-factors [get]
 func (h *CalculatorHandler) GetEmissionFactors(c *gin.Context) {
-	// This would need to be implemented in the service layer
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Emission factors endpoint - to be implemented",
+	limit, offset := middleware.GetPagination(c)
+
+	factors, total, err := h.calculatorService.ListEmissionFactors(
+		c.Request.Context(), c.Query("activity_type"), c.Query("location"), limit, offset)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get emission factors")
+		return
+	}
+
+	c.JSON(http.StatusOK, EmissionFactorsResponse{
+		Factors:  factors,
+		PageInfo: middleware.NewPageInfo(total, limit, offset),
 	})
 }
 
+// GetGroupedEmissionFactors godoc
+// @Summary Get emission factors grouped for comparison
+// @Description Get the emission factors in effect now, grouped by activity type with sub-types sorted by factor and their size relative to the lowest factor of the same unit
+// @Tags calculator
+// @Produce json
+// @Param location query string false "Prefer factors for this location over the global ones"
+// @Success 200 {object} service.GroupedEmissionFactorsResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /calculator/emission-factors/grouped [get]
+func (h *CalculatorHandler) GetGroupedEmissionFactors(c *gin.Context) {
+	response, err := h.calculatorService.GetGroupedEmissionFactors(c.Request.Context(), c.Query("location"))
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get emission factors")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // GetEmissionFactorsByType godoc
 // @Summary Get emission factors by activity type
-// @Description Get emission factors for a specific activity type
+// @Description Get every emission factor for an activity type, ordered by sub-type
 // @Tags calculator
 // @Produce json
 // @Param activity_type path string true "Activity type"
-// @Success 200 {object} EmissionFactorsResponse
+// @Success 200 {object} EmissionFactorsByTypeResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /calculator/emission-factors/{activity_type} [get]
 func (h *CalculatorHandler) GetEmissionFactorsByType(c *gin.Context) {
 	activityType := c.Param("activity_type")
-	
-	// This would need to be implemented in the service layer
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Emission factors by type endpoint - to be implemented",
-		"activity_type": activityType,
+
+	factors, err := h.calculatorService.ListEmissionFactorsByType(c.Request.Context(), activityType)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get emission factors",
+			logger.String("activity_type", activityType))
+		return
+	}
+
+	c.JSON(http.StatusOK, EmissionFactorsByTypeResponse{
+		ActivityType: activityType,
+		Factors:      factors,
 	})
 }
 
-// Response types
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Details string `json:"details,omitempty"`
+// UpsertEmissionFactor godoc
+// @Summary Create or update emission factor
+// @Description Create the emission factor for an activity type, sub-type and location, or update the one whose effective period overlaps the request's (admin only). Updates keep the replaced values as a version.
+// @Tags calculator
+// @Accept json
+// @Produce json
+// @Param request body service.EmissionFactorRequest true "Emission factor"
+// @Success 200 {object} service.EmissionFactorResponse
+// @Success 201 {object} service.EmissionFactorResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /calculator/admin/emission-factors [post]
+func (h *CalculatorHandler) UpsertEmissionFactor(c *gin.Context) {
+	var req service.EmissionFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	actorID, _ := middleware.GetUserID(c)
+
+	factor, created, err := h.calculatorService.UpsertEmissionFactor(c.Request.Context(), &req, actorID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to save emission factor",
+			logger.String("activity_type", req.ActivityType),
+			logger.String("sub_type", req.SubType))
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, factor)
+}
+
+// UpdateEmissionFactor godoc
+// @Summary Update emission factor
+// @Description Replace an emission factor's values (admin only). The replaced values are kept as a version so past calculations stay reproducible.
+// @Tags calculator
+// @Accept json
+// @Produce json
+// @Param id path string true "Emission factor ID"
+// @Param request body service.EmissionFactorRequest true "Emission factor"
+// @Success 200 {object} service.EmissionFactorResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /calculator/admin/emission-factors/{id} [put]
+func (h *CalculatorHandler) UpdateEmissionFactor(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid emission factor ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req service.EmissionFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	actorID, _ := middleware.GetUserID(c)
+
+	factor, err := h.calculatorService.UpdateEmissionFactor(c.Request.Context(), id, &req, actorID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to update emission factor",
+			logger.String("factor_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, factor)
+}
+
+// GetEmissionFactorVersions godoc
+// @Summary Get emission factor versions
+// @Description Get the values an emission factor had before each update, most recent first (admin only)
+// @Tags calculator
+// @Produce json
+// @Param id path string true "Emission factor ID"
+// @Success 200 {object} EmissionFactorVersionsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /calculator/admin/emission-factors/{id}/versions [get]
+func (h *CalculatorHandler) GetEmissionFactorVersions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid emission factor ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	versions, err := h.calculatorService.ListEmissionFactorVersions(c.Request.Context(), id)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get emission factor versions",
+			logger.String("factor_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, EmissionFactorVersionsResponse{
+		EmissionFactorID: id,
+		Versions:         versions,
+	})
+}
+
+// DeleteEmissionFactor godoc
+// @Summary Delete emission factor
+// @Description Delete an emission factor no calculation has used (admin only). A factor that past calculations reference is retired instead by ending its effective period now, and 409 is returned explaining the retirement.
+// @Tags calculator
+// @Produce json
+// @Param id path string true "Emission factor ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /calculator/admin/emission-factors/{id} [delete]
+func (h *CalculatorHandler) DeleteEmissionFactor(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid emission factor ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	actorID, _ := middleware.GetUserID(c)
+
+	if err := h.calculatorService.DeleteEmissionFactor(c.Request.Context(), id, actorID); err != nil {
+		h.errMapper.Respond(c, err, "Failed to delete emission factor",
+			logger.String("factor_id", id.String()))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
+// Response types
+type ErrorResponse = httperr.ErrorResponse
+
 type CalculationHistoryResponse struct {
 	Calculations interface{} `json:"calculations"`
-	Total        int64       `json:"total"`
-	Limit        int         `json:"limit"`
-	Offset       int         `json:"offset"`
+	middleware.PageInfo
 }
 
 type EmissionFactorsResponse struct {
-	Factors interface{} `json:"factors"`
-	Total   int64       `json:"total"`
-	Limit   int         `json:"limit"`
-	Offset  int         `json:"offset"`
+	Factors []*service.EmissionFactorResponse `json:"factors"`
+	middleware.PageInfo
+}
+
+type EmissionFactorsByTypeResponse struct {
+	ActivityType string                            `json:"activity_type"`
+	Factors      []*service.EmissionFactorResponse `json:"factors"`
+}
+
+type EmissionFactorVersionsResponse struct {
+	EmissionFactorID uuid.UUID                       `json:"emission_factor_id"`
+	Versions         []*models.EmissionFactorVersion `json:"versions"`
 }
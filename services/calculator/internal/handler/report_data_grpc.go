@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/proto/reportdata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReportDataServer serves the calculator data the reporting service needs
+// for reports over gRPC
+type ReportDataServer struct {
+	reportdata.UnimplementedCalculatorDataServer
+
+	db     *database.PostgresDB
+	logger *logger.Logger
+}
+
+// NewReportDataServer creates a new report data server
+func NewReportDataServer(db *database.PostgresDB, logger *logger.Logger) *ReportDataServer {
+	return &ReportDataServer{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetFootprint returns totals and breakdowns of a user's calculations
+func (s *ReportDataServer) GetFootprint(ctx context.Context, req *reportdata.PeriodRequest) (*reportdata.FootprintData, error) {
+	start, end, err := req.Period()
+	if err != nil {
+		return nil, err
+	}
+
+	data := &reportdata.FootprintData{
+		ByCategory:        make(map[string]float64),
+		ByMonth:           make(map[string]float64),
+		DailyCalculations: make(map[string]int64),
+	}
+
+	var totalCO2 sql.NullFloat64
+	var totalCalculations sql.NullInt64
+
+	query := `
+		SELECT 
+			COALESCE(SUM(total_co2_kg), 0) as total_co2,
+			COUNT(*) as total_calculations
+		FROM calculations 
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3
+	`
+
+	if err := s.db.WithContext(ctx).Raw(query, req.GetUserId(), start, end).Row().Scan(&totalCO2, &totalCalculations); err != nil {
+		return nil, s.internal(ctx, "failed to get total footprint", err)
+	}
+	data.TotalCo2Kg = totalCO2.Float64
+	data.TotalCalculations = totalCalculations.Int64
+
+	activityQuery := `
+		SELECT 
+			a.activity_type,
+			COALESCE(SUM(a.co2_kg), 0) as total_co2,
+			COUNT(*) as count
+		FROM activities a
+		JOIN calculations c ON a.calculation_id = c.id
+		WHERE c.user_id = $1 AND c.created_at >= $2 AND c.created_at <= $3
+		GROUP BY a.activity_type
+		ORDER BY total_co2 DESC
+	`
+
+	rows, err := s.db.WithContext(ctx).Raw(activityQuery, req.GetUserId(), start, end).Rows()
+	if err != nil {
+		return nil, s.internal(ctx, "failed to get activity breakdown", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total reportdata.ActivityTotal
+		var co2 sql.NullFloat64
+
+		if err := rows.Scan(&total.ActivityType, &co2, &total.Count); err != nil {
+			return nil, s.internal(ctx, "failed to read activity breakdown", err)
+		}
+
+		total.Co2Kg = co2.Float64
+		data.ByActivityType = append(data.ByActivityType, &total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, s.internal(ctx, "failed to read activity breakdown", err)
+	}
+
+	// Calculations saved without a category are keyed by the empty string
+	categoryQuery := `
+		SELECT 
+			COALESCE(category, '') as category,
+			COALESCE(SUM(total_co2_kg), 0) as total_co2
+		FROM calculations 
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3
+		GROUP BY 1
+	`
+
+	if err := s.scanTotals(ctx, categoryQuery, req.GetUserId(), start, end, func(category string, co2 float64) {
+		data.ByCategory[category] = co2
+	}); err != nil {
+		return nil, s.internal(ctx, "failed to get category breakdown", err)
+	}
+
+	monthQuery := `
+		SELECT 
+			TO_CHAR(DATE_TRUNC('month', created_at), 'YYYY-MM') as month,
+			COALESCE(SUM(total_co2_kg), 0) as total_co2
+		FROM calculations 
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3
+		GROUP BY 1
+	`
+
+	if err := s.scanTotals(ctx, monthQuery, req.GetUserId(), start, end, func(month string, co2 float64) {
+		data.ByMonth[month] = co2
+	}); err != nil {
+		return nil, s.internal(ctx, "failed to get monthly breakdown", err)
+	}
+
+	dailyQuery := `
+		SELECT
+			DATE(created_at AT TIME ZONE 'UTC') as day,
+			COUNT(*) as count
+		FROM calculations
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3
+		GROUP BY 1
+	`
+
+	dailyRows, err := s.db.WithContext(ctx).Raw(dailyQuery, req.GetUserId(), start, end).Rows()
+	if err != nil {
+		return nil, s.internal(ctx, "failed to count daily calculations", err)
+	}
+	defer dailyRows.Close()
+
+	for dailyRows.Next() {
+		var day time.Time
+		var count int64
+
+		if err := dailyRows.Scan(&day, &count); err != nil {
+			return nil, s.internal(ctx, "failed to count daily calculations", err)
+		}
+
+		data.DailyCalculations[day.Format(reportdata.DayFormat)] = count
+	}
+	if err := dailyRows.Err(); err != nil {
+		return nil, s.internal(ctx, "failed to count daily calculations", err)
+	}
+
+	return data, nil
+}
+
+// scanTotals runs a query for a user's period returning label and CO2 rows
+func (s *ReportDataServer) scanTotals(ctx context.Context, query, userID string, start, end time.Time, add func(string, float64)) error {
+	rows, err := s.db.WithContext(ctx).Raw(query, userID, start, end).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var label string
+		var co2 sql.NullFloat64
+
+		if err := rows.Scan(&label, &co2); err != nil {
+			return err
+		}
+
+		add(label, co2.Float64)
+	}
+
+	return rows.Err()
+}
+
+// GetAverageUserFootprint returns the average total CO2 of the users with
+// calculations in a period
+func (s *ReportDataServer) GetAverageUserFootprint(ctx context.Context, req *reportdata.PeriodRequest) (*reportdata.AverageUserFootprint, error) {
+	start, end, err := req.Period()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT COALESCE(AVG(user_total), 0)
+		FROM (
+			SELECT SUM(total_co2_kg) as user_total
+			FROM calculations
+			WHERE created_at >= $1 AND created_at <= $2
+			GROUP BY user_id
+		) user_totals
+	`
+
+	var average sql.NullFloat64
+	if err := s.db.WithContext(ctx).Raw(query, start, end).Row().Scan(&average); err != nil {
+		return nil, s.internal(ctx, "failed to get average footprint", err)
+	}
+
+	return &reportdata.AverageUserFootprint{AverageCo2Kg: average.Float64}, nil
+}
+
+// GetPlatformTotals returns totals across every user's calculations
+func (s *ReportDataServer) GetPlatformTotals(ctx context.Context, req *reportdata.PlatformTotalsRequest) (*reportdata.CalculatorPlatformTotals, error) {
+	var totalCO2 sql.NullFloat64
+	var totalCalculations sql.NullInt64
+
+	query := `
+		SELECT 
+			COALESCE(SUM(total_co2_kg), 0) as total_co2,
+			COUNT(*) as total_calculations
+		FROM calculations
+	`
+
+	if err := s.db.WithContext(ctx).Raw(query).Row().Scan(&totalCO2, &totalCalculations); err != nil {
+		return nil, s.internal(ctx, "failed to get total footprint", err)
+	}
+
+	return &reportdata.CalculatorPlatformTotals{
+		TotalCo2Kg:        totalCO2.Float64,
+		TotalCalculations: totalCalculations.Int64,
+	}, nil
+}
+
+// ExportCalculations returns every calculation of a user with its activities
+func (s *ReportDataServer) ExportCalculations(ctx context.Context, req *reportdata.UserRequest) (*reportdata.ExportRows, error) {
+	query := `
+		SELECT c.id, c.total_co2_kg, c.created_at,
+			a.activity_type, a.co2_kg, a.emission_factor, a.factor_source, a.activity_data
+		FROM calculations c
+		LEFT JOIN activities a ON a.calculation_id = c.id
+		WHERE c.user_id = $1
+		ORDER BY c.created_at
+	`
+
+	var rows []map[string]interface{}
+	if err := s.db.WithContext(ctx).Raw(query, req.GetUserId()).Scan(&rows).Error; err != nil {
+		return nil, s.internal(ctx, "failed to get calculations", err)
+	}
+
+	export, err := reportdata.NewExportRows(rows)
+	if err != nil {
+		return nil, s.internal(ctx, "failed to export calculations", err)
+	}
+	return export, nil
+}
+
+// internal logs a failed query and hides its details from the caller
+func (s *ReportDataServer) internal(ctx context.Context, message string, err error) error {
+	s.logger.LogError(ctx, message, err)
+	return status.Error(codes.Internal, message)
+}
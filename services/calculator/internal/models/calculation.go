@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +14,8 @@ type Calculation struct {
 	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	UserID      string    `gorm:"not null;index" json:"user_id"`
 	TotalCO2Kg  float64   `gorm:"not null" json:"total_co2_kg"`
+	Label       string    `gorm:"size:100" json:"label,omitempty"`
+	Category    string    `gorm:"size:50;index" json:"category,omitempty"`
 	Activities  []Activity `gorm:"foreignKey:CalculationID" json:"activities"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
@@ -25,8 +29,11 @@ type Activity struct {
 	CO2Kg          float64   `gorm:"not null" json:"co2_kg"`
 	EmissionFactor float64   `gorm:"not null" json:"emission_factor"`
 	FactorSource   string    `gorm:"not null" json:"factor_source"`
-	ActivityData   string    `gorm:"type:jsonb" json:"activity_data"` // JSON data
-	CreatedAt      time.Time `json:"created_at"`
+	// EmissionFactorID is the factor the activity was calculated with, so
+	// factors used by past calculations are retired rather than deleted
+	EmissionFactorID *uuid.UUID `gorm:"type:uuid;index" json:"emission_factor_id,omitempty"`
+	ActivityData     string     `gorm:"type:jsonb" json:"activity_data"` // JSON data
+	CreatedAt        time.Time  `json:"created_at"`
 }
 
 // EmissionFactor represents emission factors for different activities
@@ -35,12 +42,52 @@ type EmissionFactor struct {
 	ActivityType string    `gorm:"not null;index" json:"activity_type"`
 	SubType      string    `gorm:"not null;index" json:"sub_type"`
 	FactorCO2    float64   `gorm:"not null" json:"factor_co2_per_unit"`
+	// FactorCH4 and FactorN2O are the kg of methane and nitrous oxide
+	// emitted per unit, converted to CO2e with GWPCH4 and GWPN2O
+	FactorCH4    float64   `gorm:"not null;default:0" json:"factor_ch4_per_unit"`
+	FactorN2O    float64   `gorm:"not null;default:0" json:"factor_n2o_per_unit"`
 	Unit         string    `gorm:"not null" json:"unit"`
 	Source       string    `gorm:"not null" json:"source"`
 	Location     string    `gorm:"index" json:"location"`
+	// EffectiveFrom and EffectiveTo bound the period a factor vintage applies
+	// to; nil means unbounded on that side
+	EffectiveFrom *time.Time `gorm:"index" json:"effective_from,omitempty"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	LastUpdated   time.Time  `json:"last_updated"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// EmissionFactorVersion keeps the values an emission factor had before an
+// update replaced them, so calculations made with them stay reproducible
+type EmissionFactorVersion struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EmissionFactorID uuid.UUID  `gorm:"type:uuid;not null;index" json:"emission_factor_id"`
+	FactorCO2        float64    `gorm:"not null" json:"factor_co2_per_unit"`
+	FactorCH4        float64    `gorm:"not null;default:0" json:"factor_ch4_per_unit"`
+	FactorN2O        float64    `gorm:"not null;default:0" json:"factor_n2o_per_unit"`
+	Unit             string     `gorm:"not null" json:"unit"`
+	Source           string     `gorm:"not null" json:"source"`
+	EffectiveFrom    *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo      *time.Time `json:"effective_to,omitempty"`
+	// LastUpdated is when these values were set and SupersededAt when the
+	// update replacing them was made, by SupersededBy
 	LastUpdated  time.Time `json:"last_updated"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	SupersededAt time.Time `gorm:"not null;index" json:"superseded_at"`
+	SupersededBy string    `json:"superseded_by,omitempty"`
+}
+
+// Global warming potentials over 100 years (IPCC AR5), the kg of CO2 with
+// the same warming effect as 1 kg of the gas
+const (
+	GWPCH4 = 28.0
+	GWPN2O = 265.0
+)
+
+// CO2eFactor returns the factor in kg CO2-equivalent per unit, combining
+// the CO2, CH4 and N2O factors
+func (e *EmissionFactor) CO2eFactor() float64 {
+	return e.FactorCO2 + e.FactorCH4*GWPCH4 + e.FactorN2O*GWPN2O
 }
 
 // VehicleActivityData represents vehicle travel activity data
@@ -48,12 +95,16 @@ type VehicleActivityData struct {
 	VehicleType       string  `json:"vehicle_type"`
 	DistanceKm        float64 `json:"distance_km"`
 	FuelEfficiencyL   float64 `json:"fuel_efficiency_l_per_100km,omitempty"`
+	// Unit is the unit DistanceKm is given in, such as "miles"; defaults to km
+	Unit string `json:"unit,omitempty"`
 }
 
 // ElectricityActivityData represents electricity usage activity data
 type ElectricityActivityData struct {
 	KwhUsage float64 `json:"kwh_usage"`
 	Location string  `json:"location"`
+	// Date selects the grid factor in effect at the time; defaults to now
+	Date string `json:"date,omitempty"`
 }
 
 // PurchaseActivityData represents purchase activity data
@@ -75,7 +126,8 @@ type FlightActivityData struct {
 type HeatingActivityData struct {
 	FuelType    string  `json:"fuel_type"`
 	Consumption float64 `json:"consumption"`
-	Unit        string  `json:"unit"`
+	// Unit is converted to the fuel's factor unit, e.g. gallons to liters
+	Unit string `json:"unit"`
 }
 
 // BeforeCreate hook for Calculation
@@ -102,6 +154,46 @@ func (e *EmissionFactor) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// BeforeCreate hook for EmissionFactorVersion
+func (v *EmissionFactorVersion) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
+
+// ValidateUnit checks that the factor's unit matches the unit the calculator
+// expects as input for the factor's activity type and sub type
+func (e *EmissionFactor) ValidateUnit() error {
+	expected, ok := ExpectedFactorUnit(e.ActivityType, e.SubType)
+	if !ok {
+		return fmt.Errorf("unsupported emission factor %s/%s", e.ActivityType, e.SubType)
+	}
+	if !strings.EqualFold(e.Unit, expected) {
+		return fmt.Errorf("emission factor %s/%s has unit %q, expected %q",
+			e.ActivityType, e.SubType, e.Unit, expected)
+	}
+	return nil
+}
+
+// ExpectedFactorUnit returns the input unit an emission factor must be expressed
+// in for the given activity type and sub type
+func ExpectedFactorUnit(activityType, subType string) (string, bool) {
+	switch activityType {
+	case ActivityTypeVehicleTravel, ActivityTypeFlight:
+		return UnitKilometer, true
+	case ActivityTypeElectricity:
+		return UnitKilowattHour, true
+	case ActivityTypePurchase:
+		return UnitUSD, true
+	case ActivityTypeHeating:
+		unit, ok := heatingFuelUnits[subType]
+		return unit, ok
+	default:
+		return "", false
+	}
+}
+
 // TableName returns the table name for Calculation
 func (Calculation) TableName() string {
 	return "calculations"
@@ -117,6 +209,11 @@ func (EmissionFactor) TableName() string {
 	return "emission_factors"
 }
 
+// TableName returns the table name for EmissionFactorVersion
+func (EmissionFactorVersion) TableName() string {
+	return "emission_factor_versions"
+}
+
 // Activity type constants
 const (
 	ActivityTypeVehicleTravel   = "vehicle_travel"
@@ -126,6 +223,18 @@ const (
 	ActivityTypeHeating         = "heating"
 )
 
+// IsActivityType reports whether t is one of the activity types the
+// calculator supports
+func IsActivityType(t string) bool {
+	switch t {
+	case ActivityTypeVehicleTravel, ActivityTypeElectricity, ActivityTypePurchase,
+		ActivityTypeFlight, ActivityTypeHeating:
+		return true
+	default:
+		return false
+	}
+}
+
 // Vehicle type constants
 const (
 	VehicleTypeCarGasoline = "car_gasoline"
@@ -160,3 +269,20 @@ const (
 	HeatingFuelElectric   = "electric"
 	HeatingFuelPropane    = "propane"
 )
+
+// Unit constants
+const (
+	UnitKilometer    = "km"
+	UnitKilowattHour = "kWh"
+	UnitUSD          = "USD"
+	UnitCubicMeter   = "m3"
+	UnitLiter        = "L"
+)
+
+// heatingFuelUnits maps heating fuel types to their consumption unit
+var heatingFuelUnits = map[string]string{
+	HeatingFuelNaturalGas: UnitCubicMeter,
+	HeatingFuelOil:        UnitLiter,
+	HeatingFuelElectric:   UnitKilowattHour,
+	HeatingFuelPropane:    UnitLiter,
+}
@@ -65,23 +65,28 @@ func (r *CalculationRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 	return &calculation, nil
 }
 
-// GetByUserID retrieves calculations for a specific user
-func (r *CalculationRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.Calculation, int64, error) {
+// GetByUserID retrieves calculations for a specific user, optionally
+// limited to one category
+func (r *CalculationRepository) GetByUserID(ctx context.Context, userID, category string, limit, offset int) ([]*models.Calculation, int64, error) {
 	var calculations []*models.Calculation
 	var total int64
 
+	query := r.db.WithContext(ctx).Model(&models.Calculation{}).
+		Where("user_id = ?", userID)
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
 	// Get total count
-	if err := r.db.WithContext(ctx).Model(&models.Calculation{}).
-		Where("user_id = ?", userID).Count(&total).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		r.logger.LogError(ctx, "failed to count calculations", err,
 			logger.String("user_id", userID))
 		return nil, 0, fmt.Errorf("failed to count calculations: %w", err)
 	}
 
 	// Get calculations with activities
-	err := r.db.WithContext(ctx).
+	err := query.
 		Preload("Activities").
-		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -96,13 +101,17 @@ func (r *CalculationRepository) GetByUserID(ctx context.Context, userID string,
 	return calculations, total, nil
 }
 
-// GetByUserIDAndDateRange retrieves calculations for a user within a date range
-func (r *CalculationRepository) GetByUserIDAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time, limit, offset int) ([]*models.Calculation, int64, error) {
+// GetByUserIDAndDateRange retrieves calculations for a user within a date
+// range, optionally limited to one category
+func (r *CalculationRepository) GetByUserIDAndDateRange(ctx context.Context, userID, category string, startDate, endDate time.Time, limit, offset int) ([]*models.Calculation, int64, error) {
 	var calculations []*models.Calculation
 	var total int64
 
 	query := r.db.WithContext(ctx).Model(&models.Calculation{}).
 		Where("user_id = ? AND created_at >= ? AND created_at <= ?", userID, startDate, endDate)
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
 
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
@@ -114,9 +123,8 @@ func (r *CalculationRepository) GetByUserIDAndDateRange(ctx context.Context, use
 	}
 
 	// Get calculations with activities
-	err := r.db.WithContext(ctx).
+	err := query.
 		Preload("Activities").
-		Where("user_id = ? AND created_at >= ? AND created_at <= ?", userID, startDate, endDate).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -170,6 +178,29 @@ func (r *CalculationRepository) Delete(ctx context.Context, id uuid.UUID) error
 	})
 }
 
+// DeleteByUserID deletes all calculations and activities belonging to a user
+func (r *CalculationRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		calculationIDs := tx.Model(&models.Calculation{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("calculation_id IN (?)", calculationIDs).Delete(&models.Activity{}).Error; err != nil {
+			r.logger.LogError(ctx, "failed to delete user activities", err,
+				logger.String("user_id", userID))
+			return fmt.Errorf("failed to delete activities: %w", err)
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&models.Calculation{}).Error; err != nil {
+			r.logger.LogError(ctx, "failed to delete user calculations", err,
+				logger.String("user_id", userID))
+			return fmt.Errorf("failed to delete calculations: %w", err)
+		}
+
+		r.logger.LogInfo(ctx, "user calculations deleted successfully",
+			logger.String("user_id", userID))
+
+		return nil
+	})
+}
+
 // GetUserStats retrieves calculation statistics for a user
 func (r *CalculationRepository) GetUserStats(ctx context.Context, userID string, startDate, endDate time.Time) (*UserCalculationStats, error) {
 	var stats UserCalculationStats
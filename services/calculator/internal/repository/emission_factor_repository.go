@@ -3,11 +3,14 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/calculator/internal/models"
 	"github.com/sloweyyy/GreenLedger/shared/database"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // EmissionFactorRepository handles emission factor data operations
@@ -42,12 +45,34 @@ func (r *EmissionFactorRepository) GetByActivityType(ctx context.Context, activi
 	return factors, nil
 }
 
-// GetByActivityTypeAndSubType retrieves a specific emission factor
+// GetByID retrieves an emission factor by ID
+func (r *EmissionFactorRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.EmissionFactor, error) {
+	var factor models.EmissionFactor
+
+	err := r.db.WithContext(ctx).First(&factor, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		r.logger.LogError(ctx, "failed to get emission factor", err,
+			logger.String("factor_id", id.String()))
+		return nil, fmt.Errorf("failed to get emission factor: %w", err)
+	}
+
+	return &factor, nil
+}
+
+// GetByActivityTypeAndSubType retrieves the current emission factor for an
+// activity type and sub type. Retired factors are skipped.
 func (r *EmissionFactorRepository) GetByActivityTypeAndSubType(ctx context.Context, activityType, subType string) (*models.EmissionFactor, error) {
 	var factor models.EmissionFactor
 
+	now := time.Now().UTC()
 	err := r.db.WithContext(ctx).
 		Where("activity_type = ? AND sub_type = ?", activityType, subType).
+		Where("effective_from IS NULL OR effective_from <= ?", now).
+		Where("effective_to IS NULL OR effective_to > ?", now).
+		Order("effective_from DESC NULLS LAST").
 		First(&factor).Error
 
 	if err != nil {
@@ -63,25 +88,37 @@ func (r *EmissionFactorRepository) GetByActivityTypeAndSubType(ctx context.Conte
 	return &factor, nil
 }
 
-// GetByActivityTypeAndLocation retrieves emission factors by activity type and location
-func (r *EmissionFactorRepository) GetByActivityTypeAndLocation(ctx context.Context, activityType, location string) ([]*models.EmissionFactor, error) {
+// GetByActivityTypeAndLocation retrieves the emission factors by activity type
+// and location that were in effect at the given time. Location-specific
+// factors come first and, within a location, the most recent vintage first.
+func (r *EmissionFactorRepository) GetByActivityTypeAndLocation(ctx context.Context, activityType, location string, at time.Time) ([]*models.EmissionFactor, error) {
 	var factors []*models.EmissionFactor
 
-	query := r.db.WithContext(ctx).Where("activity_type = ?", activityType)
+	query := r.db.WithContext(ctx).
+		Where("activity_type = ?", activityType).
+		Where("effective_from IS NULL OR effective_from <= ?", at).
+		Where("effective_to IS NULL OR effective_to > ?", at)
 
+	// Newest vintage first within each location
+	order := "effective_from DESC NULLS LAST, sub_type"
 	if location != "" {
 		// Try to find location-specific factors first, then fall back to global
 		query = query.Where("location = ? OR location = '' OR location IS NULL", location).
-			Order(fmt.Sprintf("CASE WHEN location = '%s' THEN 0 ELSE 1 END, sub_type", location))
+			Clauses(clause.OrderBy{Expression: clause.Expr{
+				SQL:                "CASE WHEN location = ? THEN 0 ELSE 1 END, " + order,
+				Vars:               []interface{}{location},
+				WithoutParentheses: true,
+			}})
 	} else {
-		query = query.Order("sub_type")
+		query = query.Order(order)
 	}
 
 	err := query.Find(&factors).Error
 	if err != nil {
 		r.logger.LogError(ctx, "failed to get emission factors by location", err,
 			logger.String("activity_type", activityType),
-			logger.String("location", location))
+			logger.String("location", location),
+			logger.String("at", at.Format(time.RFC3339)))
 		return nil, fmt.Errorf("failed to get emission factors: %w", err)
 	}
 
@@ -121,6 +158,75 @@ func (r *EmissionFactorRepository) Update(ctx context.Context, factor *models.Em
 	return nil
 }
 
+// UpdateWithVersion updates an emission factor and records the values it
+// replaces in one transaction
+func (r *EmissionFactorRepository) UpdateWithVersion(ctx context.Context, factor *models.EmissionFactor, previous *models.EmissionFactorVersion) error {
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Create(previous).Error; err != nil {
+			return fmt.Errorf("failed to record emission factor version: %w", err)
+		}
+		if err := tx.Save(factor).Error; err != nil {
+			return fmt.Errorf("failed to update emission factor: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.LogError(ctx, "failed to update emission factor", err,
+			logger.String("factor_id", factor.ID.String()))
+		return err
+	}
+
+	r.logger.LogInfo(ctx, "emission factor updated successfully",
+		logger.String("factor_id", factor.ID.String()),
+		logger.String("version_id", previous.ID.String()))
+
+	return nil
+}
+
+// GetByKey retrieves every vintage of the emission factor for an activity
+// type, sub type and location, oldest first. An empty location is the
+// global factor.
+func (r *EmissionFactorRepository) GetByKey(ctx context.Context, activityType, subType, location string) ([]*models.EmissionFactor, error) {
+	var factors []*models.EmissionFactor
+
+	query := r.db.WithContext(ctx).
+		Where("activity_type = ? AND sub_type = ?", activityType, subType)
+	if location == "" {
+		query = query.Where("location = '' OR location IS NULL")
+	} else {
+		query = query.Where("location = ?", location)
+	}
+
+	err := query.Order("effective_from ASC NULLS FIRST, created_at ASC").Find(&factors).Error
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get emission factors by key", err,
+			logger.String("activity_type", activityType),
+			logger.String("sub_type", subType),
+			logger.String("location", location))
+		return nil, fmt.Errorf("failed to get emission factors: %w", err)
+	}
+
+	return factors, nil
+}
+
+// GetVersions retrieves the replaced values of an emission factor, most
+// recently superseded first
+func (r *EmissionFactorRepository) GetVersions(ctx context.Context, factorID uuid.UUID) ([]*models.EmissionFactorVersion, error) {
+	var versions []*models.EmissionFactorVersion
+
+	err := r.db.WithContext(ctx).
+		Where("emission_factor_id = ?", factorID).
+		Order("superseded_at DESC").
+		Find(&versions).Error
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get emission factor versions", err,
+			logger.String("factor_id", factorID.String()))
+		return nil, fmt.Errorf("failed to get emission factor versions: %w", err)
+	}
+
+	return versions, nil
+}
+
 // Delete deletes an emission factor
 func (r *EmissionFactorRepository) Delete(ctx context.Context, id string) error {
 	err := r.db.WithContext(ctx).Delete(&models.EmissionFactor{}, "id = ?", id).Error
@@ -136,6 +242,22 @@ func (r *EmissionFactorRepository) Delete(ctx context.Context, id string) error
 	return nil
 }
 
+// CountReferences counts the calculation activities calculated with an emission factor
+func (r *EmissionFactorRepository) CountReferences(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).Model(&models.Activity{}).
+		Where("emission_factor_id = ?", id).
+		Count(&count).Error
+	if err != nil {
+		r.logger.LogError(ctx, "failed to count emission factor references", err,
+			logger.String("factor_id", id.String()))
+		return 0, fmt.Errorf("failed to count emission factor references: %w", err)
+	}
+
+	return count, nil
+}
+
 // BulkCreate creates multiple emission factors
 func (r *EmissionFactorRepository) BulkCreate(ctx context.Context, factors []*models.EmissionFactor) error {
 	if len(factors) == 0 {
@@ -189,3 +311,34 @@ func (r *EmissionFactorRepository) GetAll(ctx context.Context, activityType, loc
 
 	return factors, total, nil
 }
+
+// GetEffective retrieves every emission factor in effect at the given time
+// for a location and the global defaults. Within each activity type and
+// sub-type, location-specific factors and newer vintages come first.
+func (r *EmissionFactorRepository) GetEffective(ctx context.Context, location string, at time.Time) ([]*models.EmissionFactor, error) {
+	var factors []*models.EmissionFactor
+
+	query := r.db.WithContext(ctx).
+		Where("effective_from IS NULL OR effective_from <= ?", at).
+		Where("effective_to IS NULL OR effective_to > ?", at)
+
+	order := "activity_type, sub_type, effective_from DESC NULLS LAST"
+	if location != "" {
+		query = query.Where("location = ? OR location = '' OR location IS NULL", location).
+			Clauses(clause.OrderBy{Expression: clause.Expr{
+				SQL:                "activity_type, sub_type, CASE WHEN location = ? THEN 0 ELSE 1 END, effective_from DESC NULLS LAST",
+				Vars:               []interface{}{location},
+				WithoutParentheses: true,
+			}})
+	} else {
+		query = query.Where("location = '' OR location IS NULL").Order(order)
+	}
+
+	if err := query.Find(&factors).Error; err != nil {
+		r.logger.LogError(ctx, "failed to get effective emission factors", err,
+			logger.String("location", location))
+		return nil, fmt.Errorf("failed to get emission factors: %w", err)
+	}
+
+	return factors, nil
+}
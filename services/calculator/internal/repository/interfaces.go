@@ -12,23 +12,30 @@ import (
 type CalculationRepositoryInterface interface {
 	Create(ctx context.Context, calculation *models.Calculation) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Calculation, error)
-	GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.Calculation, int64, error)
-	GetByUserIDAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time, limit, offset int) ([]*models.Calculation, int64, error)
+	GetByUserID(ctx context.Context, userID, category string, limit, offset int) ([]*models.Calculation, int64, error)
+	GetByUserIDAndDateRange(ctx context.Context, userID, category string, startDate, endDate time.Time, limit, offset int) ([]*models.Calculation, int64, error)
 	Update(ctx context.Context, calculation *models.Calculation) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteByUserID(ctx context.Context, userID string) error
 	GetUserStats(ctx context.Context, userID string, startDate, endDate time.Time) (*UserCalculationStats, error)
 }
 
 // EmissionFactorRepositoryInterface defines the interface for emission factor repository
 type EmissionFactorRepositoryInterface interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.EmissionFactor, error)
 	GetByActivityType(ctx context.Context, activityType string) ([]*models.EmissionFactor, error)
 	GetByActivityTypeAndSubType(ctx context.Context, activityType, subType string) (*models.EmissionFactor, error)
-	GetByActivityTypeAndLocation(ctx context.Context, activityType, location string) ([]*models.EmissionFactor, error)
+	GetByActivityTypeAndLocation(ctx context.Context, activityType, location string, at time.Time) ([]*models.EmissionFactor, error)
 	Create(ctx context.Context, factor *models.EmissionFactor) error
 	Update(ctx context.Context, factor *models.EmissionFactor) error
+	UpdateWithVersion(ctx context.Context, factor *models.EmissionFactor, previous *models.EmissionFactorVersion) error
+	GetByKey(ctx context.Context, activityType, subType, location string) ([]*models.EmissionFactor, error)
+	GetVersions(ctx context.Context, factorID uuid.UUID) ([]*models.EmissionFactorVersion, error)
 	Delete(ctx context.Context, id string) error
+	CountReferences(ctx context.Context, id uuid.UUID) (int64, error)
 	BulkCreate(ctx context.Context, factors []*models.EmissionFactor) error
 	GetAll(ctx context.Context, activityType, location string, limit, offset int) ([]*models.EmissionFactor, int64, error)
+	GetEffective(ctx context.Context, location string, at time.Time) ([]*models.EmissionFactor, error)
 }
 
 // Ensure concrete types implement interfaces
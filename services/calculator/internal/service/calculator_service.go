@@ -3,31 +3,63 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/calculator/internal/airports"
 	"github.com/sloweyyy/GreenLedger/services/calculator/internal/models"
 	"github.com/sloweyyy/GreenLedger/services/calculator/internal/repository"
+	"github.com/sloweyyy/GreenLedger/services/calculator/internal/units"
+	"github.com/sloweyyy/GreenLedger/shared/database"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
+)
+
+// ErrInvalidActivity is returned when activity data is missing fields, malformed
+// or refers to a sub-type with no emission factor
+var ErrInvalidActivity = errors.New("invalid activity")
+
+// ErrEmissionFactorNotFound is returned when an emission factor ID matches no factor
+var ErrEmissionFactorNotFound = errors.New("emission factor not found")
+
+// ErrEmissionFactorRetired is returned when deleting an emission factor that
+// past calculations reference; the factor is retired instead of deleted
+var ErrEmissionFactorRetired = errors.New("emission factor is in use and was retired instead of deleted")
+
+// metricsServiceName labels the business metrics recorded by the calculator
+const metricsServiceName = "calculator"
+
+// Statuses of recorded activity calculations
+const (
+	calculationStatusSuccess = "success"
+	calculationStatusFailed  = "failed"
 )
 
 // CalculatorService handles carbon footprint calculations
 type CalculatorService struct {
 	calculationRepo    repository.CalculationRepositoryInterface
 	emissionFactorRepo repository.EmissionFactorRepositoryInterface
+	metrics            *monitoring.Metrics
 	logger             *logger.Logger
 }
 
-// NewCalculatorService creates a new calculator service
+// NewCalculatorService creates a new calculator service. metrics may be nil
+// to record no business metrics.
 func NewCalculatorService(
 	calculationRepo repository.CalculationRepositoryInterface,
 	emissionFactorRepo repository.EmissionFactorRepositoryInterface,
+	metrics *monitoring.Metrics,
 	logger *logger.Logger,
 ) *CalculatorService {
 	return &CalculatorService{
 		calculationRepo:    calculationRepo,
 		emissionFactorRepo: emissionFactorRepo,
+		metrics:            metrics,
 		logger:             logger,
 	}
 }
@@ -35,6 +67,8 @@ func NewCalculatorService(
 // CalculateFootprintRequest represents a calculation request
 type CalculateFootprintRequest struct {
 	UserID     string                `json:"user_id" binding:"required"`
+	Label      string                `json:"label" binding:"omitempty,max=100"`
+	Category   string                `json:"category" binding:"omitempty,max=50"`
 	Activities []ActivityDataRequest `json:"activities" binding:"required,min=1"`
 }
 
@@ -47,18 +81,36 @@ type ActivityDataRequest struct {
 // CalculateFootprintResponse represents a calculation response
 type CalculateFootprintResponse struct {
 	CalculationID   uuid.UUID        `json:"calculation_id"`
+	Label           string           `json:"label,omitempty"`
+	Category        string           `json:"category,omitempty"`
 	TotalCO2Kg      float64          `json:"total_co2_kg"`
 	ActivityResults []ActivityResult `json:"activity_results"`
 	CalculatedAt    time.Time        `json:"calculated_at"`
 }
 
-// ActivityResult represents the result of an activity calculation
+// ActivityResult represents the result of an activity calculation. CO2Kg
+// and EmissionFactor are in CO2-equivalent, with Gases breaking the total
+// down by gas.
 type ActivityResult struct {
-	ActivityType   string                 `json:"activity_type"`
-	CO2Kg          float64                `json:"co2_kg"`
-	EmissionFactor float64                `json:"emission_factor"`
-	FactorSource   string                 `json:"factor_source"`
-	ActivityData   map[string]interface{} `json:"activity_data"`
+	ActivityType     string                 `json:"activity_type"`
+	CO2Kg            float64                `json:"co2_kg"`
+	Gases            GasEmissions           `json:"gases"`
+	EmissionFactor   float64                `json:"emission_factor"`
+	FactorSource     string                 `json:"factor_source"`
+	EmissionFactorID uuid.UUID              `json:"emission_factor_id"`
+	ActivityData     map[string]interface{} `json:"activity_data"`
+}
+
+// GasEmissions breaks an activity's CO2-equivalent emissions down by gas.
+// CH4Kg and N2OKg are masses of the gas; the CO2e fields are those masses
+// weighted by their global warming potential.
+type GasEmissions struct {
+	CO2Kg       float64 `json:"co2_kg"`
+	CH4Kg       float64 `json:"ch4_kg"`
+	CH4CO2eKg   float64 `json:"ch4_co2e_kg"`
+	N2OKg       float64 `json:"n2o_kg"`
+	N2OCO2eKg   float64 `json:"n2o_co2e_kg"`
+	TotalCO2eKg float64 `json:"total_co2e_kg"`
 }
 
 // CalculateFootprint calculates carbon footprint for given activities
@@ -77,6 +129,11 @@ func (s *CalculatorService) CalculateFootprint(ctx context.Context, req *Calcula
 	for i, activityReq := range req.Activities {
 		result, err := s.calculateActivity(ctx, activityReq)
 		if err != nil {
+			s.recordCalculation(activityReq.ActivityType, calculationStatusFailed)
+			if errors.Is(err, database.ErrNotFound) {
+				// A sub-type with no emission factor is a bad request, not a missing resource
+				err = fmt.Errorf("%w: %w", ErrInvalidActivity, err)
+			}
 			s.logger.LogError(ctx, "failed to calculate activity", err,
 				logger.String("user_id", req.UserID),
 				logger.Int("activity_index", i),
@@ -84,6 +141,8 @@ func (s *CalculatorService) CalculateFootprint(ctx context.Context, req *Calcula
 			return nil, fmt.Errorf("failed to calculate activity %d: %w", i, err)
 		}
 
+		s.recordCalculation(activityReq.ActivityType, calculationStatusSuccess)
+
 		totalCO2 += result.CO2Kg
 		activityResults = append(activityResults, *result)
 
@@ -102,6 +161,10 @@ func (s *CalculatorService) CalculateFootprint(ctx context.Context, req *Calcula
 			FactorSource:   result.FactorSource,
 			ActivityData:   string(activityDataJSON),
 		}
+		if result.EmissionFactorID != uuid.Nil {
+			factorID := result.EmissionFactorID
+			activity.EmissionFactorID = &factorID
+		}
 		activities = append(activities, activity)
 	}
 
@@ -110,6 +173,8 @@ func (s *CalculatorService) CalculateFootprint(ctx context.Context, req *Calcula
 		ID:         calculationID,
 		UserID:     req.UserID,
 		TotalCO2Kg: totalCO2,
+		Label:      strings.TrimSpace(req.Label),
+		Category:   normalizeCategory(req.Category),
 		Activities: activities,
 	}
 
@@ -123,6 +188,8 @@ func (s *CalculatorService) CalculateFootprint(ctx context.Context, req *Calcula
 
 	response := &CalculateFootprintResponse{
 		CalculationID:   calculationID,
+		Label:           calculation.Label,
+		Category:        calculation.Category,
 		TotalCO2Kg:      totalCO2,
 		ActivityResults: activityResults,
 		CalculatedAt:    time.Now().UTC(),
@@ -150,7 +217,14 @@ func (s *CalculatorService) calculateActivity(ctx context.Context, req ActivityD
 	case models.ActivityTypeHeating:
 		return s.calculateHeating(ctx, req.Data)
 	default:
-		return nil, fmt.Errorf("unsupported activity type: %s", req.ActivityType)
+		return nil, fmt.Errorf("%w: unsupported activity type %s", ErrInvalidActivity, req.ActivityType)
+	}
+}
+
+// recordCalculation counts a computed activity by type and outcome
+func (s *CalculatorService) recordCalculation(activityType, status string) {
+	if s.metrics != nil {
+		s.metrics.RecordCalculation(metricsServiceName, activityType, status)
 	}
 }
 
@@ -158,12 +232,12 @@ func (s *CalculatorService) calculateActivity(ctx context.Context, req ActivityD
 func (s *CalculatorService) calculateVehicleTravel(ctx context.Context, data map[string]interface{}) (*ActivityResult, error) {
 	vehicleType, ok := data["vehicle_type"].(string)
 	if !ok {
-		return nil, fmt.Errorf("missing or invalid vehicle_type")
+		return nil, fmt.Errorf("%w: missing or invalid vehicle_type", ErrInvalidActivity)
 	}
 
-	distanceKm, ok := data["distance_km"].(float64)
+	distanceKm, ok := getFloat(data, "distance_km")
 	if !ok {
-		return nil, fmt.Errorf("missing or invalid distance_km")
+		return nil, fmt.Errorf("%w: missing or invalid distance_km", ErrInvalidActivity)
 	}
 
 	// Get emission factor
@@ -172,57 +246,112 @@ func (s *CalculatorService) calculateVehicleTravel(ctx context.Context, data map
 		return nil, fmt.Errorf("failed to get emission factor for vehicle type %s: %w", vehicleType, err)
 	}
 
-	// Calculate CO2 emissions (factor is typically in kg CO2 per km)
-	co2Kg := distanceKm * factor.FactorCO2
+	// The distance may be given in another unit, such as miles
+	if unit, _ := data["unit"].(string); unit != "" {
+		distanceKm, err = units.Convert(distanceKm, unit, factor.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("%w: distance unit %s cannot be converted to emission factor unit %s: %w", ErrInvalidActivity, unit, factor.Unit, err)
+		}
+	}
 
-	return &ActivityResult{
-		ActivityType:   models.ActivityTypeVehicleTravel,
-		CO2Kg:          co2Kg,
-		EmissionFactor: factor.FactorCO2,
-		FactorSource:   factor.Source,
-		ActivityData:   data,
-	}, nil
+	return emissionsResult(models.ActivityTypeVehicleTravel, factor, distanceKm, data), nil
 }
 
 // calculateElectricity calculates emissions for electricity usage
 func (s *CalculatorService) calculateElectricity(ctx context.Context, data map[string]interface{}) (*ActivityResult, error) {
-	kwhUsage, ok := data["kwh_usage"].(float64)
+	kwhUsage, ok := getFloat(data, "kwh_usage")
 	if !ok {
-		return nil, fmt.Errorf("missing or invalid kwh_usage")
+		return nil, fmt.Errorf("%w: missing or invalid kwh_usage", ErrInvalidActivity)
 	}
 
 	location, _ := data["location"].(string)
 
-	// Get emission factor (try location-specific first, then default)
-	factors, err := s.emissionFactorRepo.GetByActivityTypeAndLocation(ctx, models.ActivityTypeElectricity, location)
+	activityDate, err := parseActivityDate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the factor in effect on the activity date (try location-specific first, then default)
+	factors, err := s.emissionFactorRepo.GetByActivityTypeAndLocation(ctx, models.ActivityTypeElectricity, location, activityDate)
 	if err != nil || len(factors) == 0 {
 		return nil, fmt.Errorf("failed to get emission factor for electricity in location %s: %w", location, err)
 	}
 
 	factor := factors[0] // Use the first (most specific) factor
 
-	// Calculate CO2 emissions (factor is typically in kg CO2 per kWh)
-	co2Kg := kwhUsage * factor.FactorCO2
+	return emissionsResult(models.ActivityTypeElectricity, factor, kwhUsage, data), nil
+}
 
-	return &ActivityResult{
-		ActivityType:   models.ActivityTypeElectricity,
-		CO2Kg:          co2Kg,
-		EmissionFactor: factor.FactorCO2,
-		FactorSource:   factor.Source,
-		ActivityData:   data,
-	}, nil
+// parseActivityDate reads the optional "date" field of activity data as an
+// RFC3339 timestamp or a YYYY-MM-DD date. Activities without a date are
+// treated as happening now.
+func parseActivityDate(data map[string]interface{}) (time.Time, error) {
+	raw, ok := data["date"]
+	if !ok || raw == nil {
+		return time.Now().UTC(), nil
+	}
+
+	value, ok := raw.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: invalid date, expected a string", ErrInvalidActivity)
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: invalid date %q, expected RFC3339 or YYYY-MM-DD", ErrInvalidActivity, value)
+}
+
+// getFloat reads a numeric field of activity data. Besides the float64 that
+// encoding/json produces, it accepts Go integers, json.Number and numeric
+// strings such as "100", since clients and encoders differ in how they send
+// numbers.
+func getFloat(data map[string]interface{}, key string) (float64, bool) {
+	var value float64
+	switch v := data[key].(type) {
+	case float64:
+		value = v
+	case float32:
+		value = float64(v)
+	case int:
+		value = float64(v)
+	case int32:
+		value = float64(v)
+	case int64:
+		value = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		value = f
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, false
+		}
+		value = f
+	default:
+		return 0, false
+	}
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false
+	}
+	return value, true
 }
 
 // calculatePurchase calculates emissions for purchases
 func (s *CalculatorService) calculatePurchase(ctx context.Context, data map[string]interface{}) (*ActivityResult, error) {
 	category, ok := data["category"].(string)
 	if !ok {
-		return nil, fmt.Errorf("missing or invalid category")
+		return nil, fmt.Errorf("%w: missing or invalid category", ErrInvalidActivity)
 	}
 
-	priceUSD, ok := data["price_usd"].(float64)
+	priceUSD, ok := getFloat(data, "price_usd")
 	if !ok {
-		return nil, fmt.Errorf("missing or invalid price_usd")
+		return nil, fmt.Errorf("%w: missing or invalid price_usd", ErrInvalidActivity)
 	}
 
 	// Get emission factor
@@ -231,28 +360,19 @@ func (s *CalculatorService) calculatePurchase(ctx context.Context, data map[stri
 		return nil, fmt.Errorf("failed to get emission factor for purchase category %s: %w", category, err)
 	}
 
-	// Calculate CO2 emissions (factor is typically in kg CO2 per USD)
-	co2Kg := priceUSD * factor.FactorCO2
-
-	return &ActivityResult{
-		ActivityType:   models.ActivityTypePurchase,
-		CO2Kg:          co2Kg,
-		EmissionFactor: factor.FactorCO2,
-		FactorSource:   factor.Source,
-		ActivityData:   data,
-	}, nil
+	return emissionsResult(models.ActivityTypePurchase, factor, priceUSD, data), nil
 }
 
 // calculateFlight calculates emissions for flights
 func (s *CalculatorService) calculateFlight(ctx context.Context, data map[string]interface{}) (*ActivityResult, error) {
 	departureAirport, ok := data["departure_airport"].(string)
 	if !ok {
-		return nil, fmt.Errorf("missing or invalid departure_airport")
+		return nil, fmt.Errorf("%w: missing or invalid departure_airport", ErrInvalidActivity)
 	}
 
 	arrivalAirport, ok := data["arrival_airport"].(string)
 	if !ok {
-		return nil, fmt.Errorf("missing or invalid arrival_airport")
+		return nil, fmt.Errorf("%w: missing or invalid arrival_airport", ErrInvalidActivity)
 	}
 
 	flightClass, _ := data["flight_class"].(string)
@@ -262,8 +382,10 @@ func (s *CalculatorService) calculateFlight(ctx context.Context, data map[string
 
 	isRoundTrip, _ := data["is_round_trip"].(bool)
 
-	// Calculate distance (simplified - in real implementation, use airport coordinates)
-	distance := s.calculateFlightDistance(departureAirport, arrivalAirport)
+	distance, err := flightDistance(departureAirport, arrivalAirport)
+	if err != nil {
+		return nil, err
+	}
 
 	// Get emission factor based on flight class
 	factor, err := s.emissionFactorRepo.GetByActivityTypeAndSubType(ctx, models.ActivityTypeFlight, flightClass)
@@ -271,31 +393,23 @@ func (s *CalculatorService) calculateFlight(ctx context.Context, data map[string
 		return nil, fmt.Errorf("failed to get emission factor for flight class %s: %w", flightClass, err)
 	}
 
-	// Calculate CO2 emissions (factor is typically in kg CO2 per km)
-	co2Kg := distance * factor.FactorCO2
 	if isRoundTrip {
-		co2Kg *= 2
+		distance *= 2
 	}
 
-	return &ActivityResult{
-		ActivityType:   models.ActivityTypeFlight,
-		CO2Kg:          co2Kg,
-		EmissionFactor: factor.FactorCO2,
-		FactorSource:   factor.Source,
-		ActivityData:   data,
-	}, nil
+	return emissionsResult(models.ActivityTypeFlight, factor, distance, data), nil
 }
 
 // calculateHeating calculates emissions for heating
 func (s *CalculatorService) calculateHeating(ctx context.Context, data map[string]interface{}) (*ActivityResult, error) {
 	fuelType, ok := data["fuel_type"].(string)
 	if !ok {
-		return nil, fmt.Errorf("missing or invalid fuel_type")
+		return nil, fmt.Errorf("%w: missing or invalid fuel_type", ErrInvalidActivity)
 	}
 
-	consumption, ok := data["consumption"].(float64)
+	consumption, ok := getFloat(data, "consumption")
 	if !ok {
-		return nil, fmt.Errorf("missing or invalid consumption")
+		return nil, fmt.Errorf("%w: missing or invalid consumption", ErrInvalidActivity)
 	}
 
 	// Get emission factor
@@ -304,56 +418,162 @@ func (s *CalculatorService) calculateHeating(ctx context.Context, data map[strin
 		return nil, fmt.Errorf("failed to get emission factor for heating fuel %s: %w", fuelType, err)
 	}
 
-	// Calculate CO2 emissions
-	co2Kg := consumption * factor.FactorCO2
+	// Convert consumption reported in another unit, such as gallons or
+	// therms, to the factor's unit
+	if unit, _ := data["unit"].(string); unit != "" {
+		consumption, err = units.Convert(consumption, unit, factor.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("%w: heating consumption unit %s does not match emission factor unit %s and cannot be converted: %w", ErrInvalidActivity, unit, factor.Unit, err)
+		}
+	}
+
+	return emissionsResult(models.ActivityTypeHeating, factor, consumption, data), nil
+}
+
+// emissionsResult calculates the emissions of quantity units of an activity
+// from the factor's CO2, CH4 and N2O rates
+func emissionsResult(activityType string, factor *models.EmissionFactor, quantity float64, data map[string]interface{}) *ActivityResult {
+	gases := GasEmissions{
+		CO2Kg: quantity * factor.FactorCO2,
+		CH4Kg: quantity * factor.FactorCH4,
+		N2OKg: quantity * factor.FactorN2O,
+	}
+	gases.CH4CO2eKg = gases.CH4Kg * models.GWPCH4
+	gases.N2OCO2eKg = gases.N2OKg * models.GWPN2O
+	gases.TotalCO2eKg = gases.CO2Kg + gases.CH4CO2eKg + gases.N2OCO2eKg
 
 	return &ActivityResult{
-		ActivityType:   models.ActivityTypeHeating,
-		CO2Kg:          co2Kg,
-		EmissionFactor: factor.FactorCO2,
-		FactorSource:   factor.Source,
-		ActivityData:   data,
-	}, nil
+		ActivityType:     activityType,
+		CO2Kg:            gases.TotalCO2eKg,
+		Gases:            gases,
+		EmissionFactor:   factor.CO2eFactor(),
+		FactorSource:     factor.Source,
+		EmissionFactorID: factor.ID,
+		ActivityData:     data,
+	}
+}
+
+// flightDistance returns the great-circle distance in kilometers between
+// two airports given by IATA code
+func flightDistance(departure, arrival string) (float64, error) {
+	distance, err := airports.Default().Distance(departure, arrival)
+	if err != nil {
+		if errors.Is(err, airports.ErrUnknownAirport) {
+			return 0, fmt.Errorf("%w: %w", ErrInvalidActivity, err)
+		}
+		return 0, err
+	}
+	if distance == 0 {
+		return 0, fmt.Errorf("%w: departure and arrival airports are the same", ErrInvalidActivity)
+	}
+	return distance, nil
+}
+
+// CreateEmissionFactor validates and stores a single emission factor
+func (s *CalculatorService) CreateEmissionFactor(ctx context.Context, factor *models.EmissionFactor) error {
+	if err := factor.ValidateUnit(); err != nil {
+		s.logger.LogWarn(ctx, "rejected emission factor with inconsistent unit",
+			logger.String("activity_type", factor.ActivityType),
+			logger.String("sub_type", factor.SubType),
+			logger.String("unit", factor.Unit))
+		return fmt.Errorf("invalid emission factor: %w", err)
+	}
+
+	return s.emissionFactorRepo.Create(ctx, factor)
+}
+
+// DeleteEmissionFactor deletes an emission factor no calculation has used.
+// A factor that past calculations reference is retired by ending its
+// effective period now, so their lineage stays intact, and
+// ErrEmissionFactorRetired is returned.
+func (s *CalculatorService) DeleteEmissionFactor(ctx context.Context, id uuid.UUID, actorID string) error {
+	factor, err := s.emissionFactorRepo.GetByID(ctx, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("%w: %w", ErrEmissionFactorNotFound, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get emission factor: %w", err)
+	}
+
+	count, err := s.emissionFactorRepo.CountReferences(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check emission factor usage: %w", err)
+	}
+
+	if count == 0 {
+		if err := s.emissionFactorRepo.Delete(ctx, id.String()); err != nil {
+			return fmt.Errorf("failed to delete emission factor: %w", err)
+		}
+
+		s.logger.LogInfo(ctx, "emission factor deleted",
+			logger.String("factor_id", id.String()),
+			logger.String("actor_id", actorID))
+
+		return nil
+	}
+
+	now := time.Now().UTC()
+	if factor.EffectiveTo == nil || factor.EffectiveTo.After(now) {
+		factor.EffectiveTo = &now
+		if err := s.emissionFactorRepo.Update(ctx, factor); err != nil {
+			return fmt.Errorf("failed to retire emission factor: %w", err)
+		}
+
+		s.logger.LogInfo(ctx, "emission factor retired",
+			logger.String("factor_id", id.String()),
+			logger.String("actor_id", actorID))
+	}
+
+	return fmt.Errorf("%w: %d calculation activities reference it, it no longer applies from %s",
+		ErrEmissionFactorRetired, count, factor.EffectiveTo.Format(time.RFC3339))
 }
 
-// calculateFlightDistance calculates distance between airports (simplified)
-func (s *CalculatorService) calculateFlightDistance(departure, arrival string) float64 {
-	// This is a simplified implementation
-	// In a real system, you would use actual airport coordinates and great circle distance
-
-	// Sample distances for common routes (in km)
-	routes := map[string]float64{
-		"LAX-JFK": 3944,
-		"JFK-LAX": 3944,
-		"LHR-JFK": 5541,
-		"JFK-LHR": 5541,
-		"NRT-LAX": 8815,
-		"LAX-NRT": 8815,
-		"SFO-NYC": 4139,
-		"NYC-SFO": 4139,
-		"LHR-CDG": 344,
-		"CDG-LHR": 344,
-	}
-
-	key := departure + "-" + arrival
-	if distance, exists := routes[key]; exists {
-		return distance
-	}
-
-	// Estimate based on airport codes (very simplified)
-	// In reality, you'd use a proper airport database and haversine formula
-	return 1000 // Default distance for unknown routes
+// ImportEmissionFactors validates and stores a batch of emission factors.
+// The whole batch is rejected if any factor has an inconsistent unit.
+func (s *CalculatorService) ImportEmissionFactors(ctx context.Context, factors []*models.EmissionFactor) error {
+	for i, factor := range factors {
+		if err := factor.ValidateUnit(); err != nil {
+			s.logger.LogWarn(ctx, "rejected emission factor import with inconsistent unit",
+				logger.Int("factor_index", i),
+				logger.String("activity_type", factor.ActivityType),
+				logger.String("sub_type", factor.SubType),
+				logger.String("unit", factor.Unit))
+			return fmt.Errorf("invalid emission factor %d: %w", i, err)
+		}
+	}
+
+	return s.emissionFactorRepo.BulkCreate(ctx, factors)
 }
 
-// GetCalculationHistory retrieves calculation history for a user
-func (s *CalculatorService) GetCalculationHistory(ctx context.Context, userID string, startDate, endDate *time.Time, limit, offset int) ([]*models.Calculation, int64, error) {
+// GetCalculationHistory retrieves calculation history for a user. An empty
+// category returns calculations in every category.
+func (s *CalculatorService) GetCalculationHistory(ctx context.Context, userID, category string, startDate, endDate *time.Time, limit, offset int) ([]*models.Calculation, int64, error) {
+	category = normalizeCategory(category)
 	if startDate != nil && endDate != nil {
-		return s.calculationRepo.GetByUserIDAndDateRange(ctx, userID, *startDate, *endDate, limit, offset)
+		return s.calculationRepo.GetByUserIDAndDateRange(ctx, userID, category, *startDate, *endDate, limit, offset)
 	}
-	return s.calculationRepo.GetByUserID(ctx, userID, limit, offset)
+	return s.calculationRepo.GetByUserID(ctx, userID, category, limit, offset)
+}
+
+// normalizeCategory lower-cases and trims a category so "Travel " and
+// "travel" are grouped together
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
 }
 
 // GetCalculationByID retrieves a specific calculation
 func (s *CalculatorService) GetCalculationByID(ctx context.Context, id uuid.UUID) (*models.Calculation, error) {
 	return s.calculationRepo.GetByID(ctx, id)
 }
+
+// EraseUserData deletes a deleted user's calculation history
+func (s *CalculatorService) EraseUserData(ctx context.Context, userID string) error {
+	if err := s.calculationRepo.DeleteByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("failed to erase user calculations: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "calculation data erased for deleted user",
+		logger.String("user_id", userID))
+
+	return nil
+}
@@ -2,13 +2,20 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sloweyyy/GreenLedger/services/calculator/internal/airports"
 	"github.com/sloweyyy/GreenLedger/services/calculator/internal/models"
 	"github.com/sloweyyy/GreenLedger/services/calculator/internal/repository"
+	"github.com/sloweyyy/GreenLedger/services/calculator/internal/units"
+	"github.com/sloweyyy/GreenLedger/shared/database"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -28,13 +35,13 @@ func (m *MockCalculationRepository) GetByID(ctx context.Context, id uuid.UUID) (
 	return args.Get(0).(*models.Calculation), args.Error(1)
 }
 
-func (m *MockCalculationRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.Calculation, int64, error) {
-	args := m.Called(ctx, userID, limit, offset)
+func (m *MockCalculationRepository) GetByUserID(ctx context.Context, userID, category string, limit, offset int) ([]*models.Calculation, int64, error) {
+	args := m.Called(ctx, userID, category, limit, offset)
 	return args.Get(0).([]*models.Calculation), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockCalculationRepository) GetByUserIDAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time, limit, offset int) ([]*models.Calculation, int64, error) {
-	args := m.Called(ctx, userID, startDate, endDate, limit, offset)
+func (m *MockCalculationRepository) GetByUserIDAndDateRange(ctx context.Context, userID, category string, startDate, endDate time.Time, limit, offset int) ([]*models.Calculation, int64, error) {
+	args := m.Called(ctx, userID, category, startDate, endDate, limit, offset)
 	return args.Get(0).([]*models.Calculation), args.Get(1).(int64), args.Error(2)
 }
 
@@ -48,6 +55,11 @@ func (m *MockCalculationRepository) Delete(ctx context.Context, id uuid.UUID) er
 	return args.Error(0)
 }
 
+func (m *MockCalculationRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
 func (m *MockCalculationRepository) GetUserStats(ctx context.Context, userID string, startDate, endDate time.Time) (*repository.UserCalculationStats, error) {
 	args := m.Called(ctx, userID, startDate, endDate)
 	return args.Get(0).(*repository.UserCalculationStats), args.Error(1)
@@ -58,6 +70,16 @@ type MockEmissionFactorRepository struct {
 	mock.Mock
 }
 
+func (m *MockEmissionFactorRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.EmissionFactor, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.EmissionFactor), args.Error(1)
+}
+
+func (m *MockEmissionFactorRepository) CountReferences(ctx context.Context, id uuid.UUID) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockEmissionFactorRepository) GetByActivityType(ctx context.Context, activityType string) ([]*models.EmissionFactor, error) {
 	args := m.Called(ctx, activityType)
 	return args.Get(0).([]*models.EmissionFactor), args.Error(1)
@@ -68,8 +90,8 @@ func (m *MockEmissionFactorRepository) GetByActivityTypeAndSubType(ctx context.C
 	return args.Get(0).(*models.EmissionFactor), args.Error(1)
 }
 
-func (m *MockEmissionFactorRepository) GetByActivityTypeAndLocation(ctx context.Context, activityType, location string) ([]*models.EmissionFactor, error) {
-	args := m.Called(ctx, activityType, location)
+func (m *MockEmissionFactorRepository) GetByActivityTypeAndLocation(ctx context.Context, activityType, location string, at time.Time) ([]*models.EmissionFactor, error) {
+	args := m.Called(ctx, activityType, location, at)
 	return args.Get(0).([]*models.EmissionFactor), args.Error(1)
 }
 
@@ -83,6 +105,21 @@ func (m *MockEmissionFactorRepository) Update(ctx context.Context, factor *model
 	return args.Error(0)
 }
 
+func (m *MockEmissionFactorRepository) UpdateWithVersion(ctx context.Context, factor *models.EmissionFactor, previous *models.EmissionFactorVersion) error {
+	args := m.Called(ctx, factor, previous)
+	return args.Error(0)
+}
+
+func (m *MockEmissionFactorRepository) GetByKey(ctx context.Context, activityType, subType, location string) ([]*models.EmissionFactor, error) {
+	args := m.Called(ctx, activityType, subType, location)
+	return args.Get(0).([]*models.EmissionFactor), args.Error(1)
+}
+
+func (m *MockEmissionFactorRepository) GetVersions(ctx context.Context, factorID uuid.UUID) ([]*models.EmissionFactorVersion, error) {
+	args := m.Called(ctx, factorID)
+	return args.Get(0).([]*models.EmissionFactorVersion), args.Error(1)
+}
+
 func (m *MockEmissionFactorRepository) Delete(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -93,6 +130,11 @@ func (m *MockEmissionFactorRepository) BulkCreate(ctx context.Context, factors [
 	return args.Error(0)
 }
 
+func (m *MockEmissionFactorRepository) GetEffective(ctx context.Context, location string, at time.Time) ([]*models.EmissionFactor, error) {
+	args := m.Called(ctx, location, at)
+	return args.Get(0).([]*models.EmissionFactor), args.Error(1)
+}
+
 func (m *MockEmissionFactorRepository) GetAll(ctx context.Context, activityType, location string, limit, offset int) ([]*models.EmissionFactor, int64, error) {
 	args := m.Called(ctx, activityType, location, limit, offset)
 	return args.Get(0).([]*models.EmissionFactor), args.Get(1).(int64), args.Error(2)
@@ -103,7 +145,7 @@ func TestCalculatorService_CalculateVehicleTravel(t *testing.T) {
 	mockCalcRepo := new(MockCalculationRepository)
 	mockFactorRepo := new(MockEmissionFactorRepository)
 	logger := logger.New("debug")
-	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, logger)
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
 
 	ctx := context.Background()
 
@@ -139,12 +181,127 @@ func TestCalculatorService_CalculateVehicleTravel(t *testing.T) {
 	mockFactorRepo.AssertExpectations(t)
 }
 
+func TestGetFloat_MixedRepresentations(t *testing.T) {
+	data := map[string]interface{}{
+		"float":       100.5,
+		"float32":     float32(2.5),
+		"int":         100,
+		"int64":       int64(7),
+		"json_number": json.Number("42.25"),
+		"string":      "100",
+		"padded":      " 3.5 ",
+		"word":        "lots",
+		"empty":       "",
+		"nan":         "NaN",
+		"infinity":    math.Inf(1),
+		"bad_number":  json.Number("1e999"),
+		"bool":        true,
+		"nil":         nil,
+	}
+
+	valid := map[string]float64{
+		"float":       100.5,
+		"float32":     2.5,
+		"int":         100,
+		"int64":       7,
+		"json_number": 42.25,
+		"string":      100,
+		"padded":      3.5,
+	}
+	for key, expected := range valid {
+		value, ok := getFloat(data, key)
+		assert.True(t, ok, key)
+		assert.Equal(t, expected, value, key)
+	}
+
+	for _, key := range []string{"word", "empty", "nan", "infinity", "bad_number", "bool", "nil", "missing"} {
+		_, ok := getFloat(data, key)
+		assert.False(t, ok, key)
+	}
+}
+
+func TestCalculatorService_CalculateActivity_AcceptsIntegersAndNumericStrings(t *testing.T) {
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	service := NewCalculatorService(new(MockCalculationRepository), mockFactorRepo, nil, logger.New("error"))
+	ctx := context.Background()
+
+	vehicle := &models.EmissionFactor{ActivityType: models.ActivityTypeVehicleTravel, SubType: models.VehicleTypeCarGasoline, FactorCO2: 0.2, Unit: "km"}
+	purchase := &models.EmissionFactor{ActivityType: models.ActivityTypePurchase, SubType: "electronics", FactorCO2: 0.5, Unit: "USD"}
+	heating := &models.EmissionFactor{ActivityType: models.ActivityTypeHeating, SubType: "natural_gas", FactorCO2: 2, Unit: "m3"}
+	mockFactorRepo.On("GetByActivityTypeAndSubType", ctx, models.ActivityTypeVehicleTravel, models.VehicleTypeCarGasoline).Return(vehicle, nil)
+	mockFactorRepo.On("GetByActivityTypeAndSubType", ctx, models.ActivityTypePurchase, "electronics").Return(purchase, nil)
+	mockFactorRepo.On("GetByActivityTypeAndSubType", ctx, models.ActivityTypeHeating, "natural_gas").Return(heating, nil)
+
+	result, err := service.calculateVehicleTravel(ctx, map[string]interface{}{"vehicle_type": models.VehicleTypeCarGasoline, "distance_km": 100})
+	assert.NoError(t, err)
+	assert.InDelta(t, 20.0, result.CO2Kg, 1e-9)
+
+	result, err = service.calculatePurchase(ctx, map[string]interface{}{"category": "electronics", "price_usd": "200"})
+	assert.NoError(t, err)
+	assert.InDelta(t, 100.0, result.CO2Kg, 1e-9)
+
+	result, err = service.calculateHeating(ctx, map[string]interface{}{"fuel_type": "natural_gas", "consumption": json.Number("12.5")})
+	assert.NoError(t, err)
+	assert.InDelta(t, 25.0, result.CO2Kg, 1e-9)
+
+	_, err = service.calculateVehicleTravel(ctx, map[string]interface{}{"vehicle_type": models.VehicleTypeCarGasoline, "distance_km": "far"})
+	assert.ErrorIs(t, err, ErrInvalidActivity)
+}
+
+func TestCalculatorService_CalculateHeating_CO2Equivalent(t *testing.T) {
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	service := NewCalculatorService(new(MockCalculationRepository), mockFactorRepo, nil, logger.New("error"))
+	ctx := context.Background()
+
+	factor := &models.EmissionFactor{
+		ActivityType: models.ActivityTypeHeating,
+		SubType:      models.HeatingFuelNaturalGas,
+		FactorCO2:    2.0,
+		FactorCH4:    0.001,
+		FactorN2O:    0.0001,
+		Unit:         "m3",
+		Source:       "DEFRA 2023",
+	}
+	mockFactorRepo.On("GetByActivityTypeAndSubType", ctx, models.ActivityTypeHeating, models.HeatingFuelNaturalGas).Return(factor, nil)
+
+	result, err := service.calculateHeating(ctx, map[string]interface{}{"fuel_type": models.HeatingFuelNaturalGas, "consumption": 100.0})
+
+	assert.NoError(t, err)
+	// 100 m3 emits 200 kg CO2, 0.1 kg CH4 (2.8 kg CO2e) and 0.01 kg N2O (2.65 kg CO2e)
+	assert.InDelta(t, 200.0, result.Gases.CO2Kg, 1e-9)
+	assert.InDelta(t, 0.1, result.Gases.CH4Kg, 1e-9)
+	assert.InDelta(t, 2.8, result.Gases.CH4CO2eKg, 1e-9)
+	assert.InDelta(t, 0.01, result.Gases.N2OKg, 1e-9)
+	assert.InDelta(t, 2.65, result.Gases.N2OCO2eKg, 1e-9)
+	assert.InDelta(t, 205.45, result.Gases.TotalCO2eKg, 1e-9)
+	assert.InDelta(t, 205.45, result.CO2Kg, 1e-9)
+	assert.InDelta(t, 2.0545, result.EmissionFactor, 1e-9)
+}
+
+func TestCalculatorService_CalculateVehicleTravel_CO2OnlyFactorUnchanged(t *testing.T) {
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	service := NewCalculatorService(new(MockCalculationRepository), mockFactorRepo, nil, logger.New("error"))
+	ctx := context.Background()
+
+	factor := &models.EmissionFactor{ActivityType: models.ActivityTypeVehicleTravel, SubType: models.VehicleTypeCarDiesel, FactorCO2: 0.17, Unit: "km"}
+	mockFactorRepo.On("GetByActivityTypeAndSubType", ctx, models.ActivityTypeVehicleTravel, models.VehicleTypeCarDiesel).Return(factor, nil)
+
+	result, err := service.calculateVehicleTravel(ctx, map[string]interface{}{"vehicle_type": models.VehicleTypeCarDiesel, "distance_km": 100.0})
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 17.0, result.CO2Kg, 1e-9)
+	assert.Equal(t, result.CO2Kg, result.Gases.TotalCO2eKg)
+	assert.Zero(t, result.Gases.CH4CO2eKg)
+	assert.Zero(t, result.Gases.N2OCO2eKg)
+	assert.Equal(t, 0.17, result.EmissionFactor)
+}
+
 func TestCalculatorService_CalculateElectricity(t *testing.T) {
 	// Setup
 	mockCalcRepo := new(MockCalculationRepository)
 	mockFactorRepo := new(MockEmissionFactorRepository)
 	logger := logger.New("debug")
-	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, logger)
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
 
 	ctx := context.Background()
 
@@ -158,7 +315,7 @@ func TestCalculatorService_CalculateElectricity(t *testing.T) {
 		Location:     "US",
 	}
 
-	mockFactorRepo.On("GetByActivityTypeAndLocation", ctx, models.ActivityTypeElectricity, "US").
+	mockFactorRepo.On("GetByActivityTypeAndLocation", ctx, models.ActivityTypeElectricity, "US", mock.AnythingOfType("time.Time")).
 		Return([]*models.EmissionFactor{emissionFactor}, nil)
 
 	// Test data
@@ -181,12 +338,77 @@ func TestCalculatorService_CalculateElectricity(t *testing.T) {
 	mockFactorRepo.AssertExpectations(t)
 }
 
+func TestCalculatorService_CalculateElectricity_UsesActivityDate(t *testing.T) {
+	// Setup
+	mockCalcRepo := new(MockCalculationRepository)
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	logger := logger.New("debug")
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
+
+	ctx := context.Background()
+	activityDate := time.Date(2021, time.March, 15, 0, 0, 0, 0, time.UTC)
+	effectiveFrom := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// Mock the factor vintage in effect on the activity date
+	emissionFactor := &models.EmissionFactor{
+		ActivityType:  models.ActivityTypeElectricity,
+		SubType:       "grid",
+		FactorCO2:     0.42,
+		Unit:          "kWh",
+		Source:        "IEA 2021",
+		Location:      "US",
+		EffectiveFrom: &effectiveFrom,
+	}
+
+	mockFactorRepo.On("GetByActivityTypeAndLocation", ctx, models.ActivityTypeElectricity, "US", activityDate).
+		Return([]*models.EmissionFactor{emissionFactor}, nil)
+
+	activityData := map[string]interface{}{
+		"kwh_usage": 100.0,
+		"location":  "US",
+		"date":      "2021-03-15",
+	}
+
+	// Execute
+	result, err := service.calculateElectricity(ctx, activityData)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.InDelta(t, 42.0, result.CO2Kg, 1e-9)
+	assert.Equal(t, "IEA 2021", result.FactorSource)
+
+	mockFactorRepo.AssertExpectations(t)
+}
+
+func TestCalculatorService_CalculateElectricity_InvalidDate(t *testing.T) {
+	// Setup
+	mockCalcRepo := new(MockCalculationRepository)
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	logger := logger.New("debug")
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
+
+	activityData := map[string]interface{}{
+		"kwh_usage": 100.0,
+		"location":  "US",
+		"date":      "15/03/2021",
+	}
+
+	// Execute
+	result, err := service.calculateElectricity(context.Background(), activityData)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "invalid date")
+	mockFactorRepo.AssertNotCalled(t, "GetByActivityTypeAndLocation")
+}
+
 func TestCalculatorService_CalculateFootprint(t *testing.T) {
 	// Setup
 	mockCalcRepo := new(MockCalculationRepository)
 	mockFactorRepo := new(MockEmissionFactorRepository)
 	logger := logger.New("debug")
-	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, logger)
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
 
 	ctx := context.Background()
 
@@ -210,7 +432,7 @@ func TestCalculatorService_CalculateFootprint(t *testing.T) {
 
 	mockFactorRepo.On("GetByActivityTypeAndSubType", ctx, models.ActivityTypeVehicleTravel, models.VehicleTypeCarGasoline).
 		Return(vehicleEmissionFactor, nil)
-	mockFactorRepo.On("GetByActivityTypeAndLocation", ctx, models.ActivityTypeElectricity, "US").
+	mockFactorRepo.On("GetByActivityTypeAndLocation", ctx, models.ActivityTypeElectricity, "US", mock.AnythingOfType("time.Time")).
 		Return([]*models.EmissionFactor{electricityEmissionFactor}, nil)
 
 	mockCalcRepo.On("Create", ctx, mock.AnythingOfType("*models.Calculation")).
@@ -252,12 +474,75 @@ func TestCalculatorService_CalculateFootprint(t *testing.T) {
 	mockCalcRepo.AssertExpectations(t)
 }
 
+func TestCalculatorService_CalculateFootprint_SavesLabelAndCategory(t *testing.T) {
+	// Setup
+	mockCalcRepo := new(MockCalculationRepository)
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	logger := logger.New("debug")
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
+
+	ctx := context.Background()
+
+	mockFactorRepo.On("GetByActivityTypeAndSubType", ctx, models.ActivityTypeVehicleTravel, models.VehicleTypeCarGasoline).
+		Return(&models.EmissionFactor{FactorCO2: 0.21, Unit: "km", Source: "EPA 2023"}, nil)
+	mockCalcRepo.On("Create", ctx, mock.MatchedBy(func(calculation *models.Calculation) bool {
+		return calculation.Label == "Summer road trip" && calculation.Category == "travel"
+	})).Return(nil)
+
+	req := &CalculateFootprintRequest{
+		UserID:   "test-user-123",
+		Label:    " Summer road trip ",
+		Category: " Travel",
+		Activities: []ActivityDataRequest{
+			{
+				ActivityType: models.ActivityTypeVehicleTravel,
+				Data: map[string]interface{}{
+					"vehicle_type": models.VehicleTypeCarGasoline,
+					"distance_km":  100.0,
+				},
+			},
+		},
+	}
+
+	// Execute
+	result, err := service.CalculateFootprint(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Summer road trip", result.Label)
+	assert.Equal(t, "travel", result.Category)
+	mockCalcRepo.AssertExpectations(t)
+}
+
+func TestCalculatorService_GetCalculationHistory_FiltersByCategory(t *testing.T) {
+	// Setup
+	mockCalcRepo := new(MockCalculationRepository)
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	logger := logger.New("debug")
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
+
+	ctx := context.Background()
+	calculations := []*models.Calculation{{UserID: "user-123", Category: "home_energy"}}
+
+	mockCalcRepo.On("GetByUserID", ctx, "user-123", "home_energy", 20, 0).
+		Return(calculations, int64(1), nil)
+
+	// Execute
+	result, total, err := service.GetCalculationHistory(ctx, "user-123", "Home_Energy", nil, nil, 20, 0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, calculations, result)
+	mockCalcRepo.AssertExpectations(t)
+}
+
 func TestCalculatorService_CalculateFootprint_InvalidActivityType(t *testing.T) {
 	// Setup
 	mockCalcRepo := new(MockCalculationRepository)
 	mockFactorRepo := new(MockEmissionFactorRepository)
 	logger := logger.New("debug")
-	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, logger)
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
 
 	ctx := context.Background()
 
@@ -281,4 +566,648 @@ func TestCalculatorService_CalculateFootprint_InvalidActivityType(t *testing.T)
 	assert.Error(t, err)
 	assert.Nil(t, response)
 	assert.Contains(t, err.Error(), "unsupported activity type")
+	assert.ErrorIs(t, err, ErrInvalidActivity)
+}
+
+func TestCalculatorService_CreateEmissionFactor_UnitMismatch(t *testing.T) {
+	// Setup
+	mockCalcRepo := new(MockCalculationRepository)
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	logger := logger.New("debug")
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
+
+	ctx := context.Background()
+
+	// Vehicle travel factors must be expressed per km
+	factor := &models.EmissionFactor{
+		ActivityType: models.ActivityTypeVehicleTravel,
+		SubType:      models.VehicleTypeCarGasoline,
+		FactorCO2:    0.34,
+		Unit:         "mile",
+		Source:       "EPA 2023",
+	}
+
+	// Execute
+	err := service.CreateEmissionFactor(ctx, factor)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expected \"km\"")
+	mockFactorRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCalculatorService_ImportEmissionFactors_RejectsBatchWithMismatchedUnit(t *testing.T) {
+	// Setup
+	mockCalcRepo := new(MockCalculationRepository)
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	logger := logger.New("debug")
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
+
+	ctx := context.Background()
+
+	factors := []*models.EmissionFactor{
+		{ActivityType: models.ActivityTypeElectricity, SubType: "grid", FactorCO2: 0.5, Unit: "kWh", Source: "IEA 2023"},
+		{ActivityType: models.ActivityTypeHeating, SubType: models.HeatingFuelNaturalGas, FactorCO2: 2.0, Unit: "L", Source: "EPA 2023"},
+	}
+
+	// Execute
+	err := service.ImportEmissionFactors(ctx, factors)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid emission factor 1")
+	mockFactorRepo.AssertNotCalled(t, "BulkCreate", mock.Anything, mock.Anything)
+}
+
+func TestCalculatorService_ImportEmissionFactors_ValidUnits(t *testing.T) {
+	// Setup
+	mockCalcRepo := new(MockCalculationRepository)
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	logger := logger.New("debug")
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
+
+	ctx := context.Background()
+
+	factors := []*models.EmissionFactor{
+		{ActivityType: models.ActivityTypeFlight, SubType: models.FlightClassEconomy, FactorCO2: 0.15, Unit: "km", Source: "ICAO 2023"},
+		{ActivityType: models.ActivityTypeHeating, SubType: models.HeatingFuelOil, FactorCO2: 2.7, Unit: "L", Source: "EPA 2023"},
+	}
+
+	mockFactorRepo.On("BulkCreate", ctx, factors).Return(nil)
+
+	// Execute
+	err := service.ImportEmissionFactors(ctx, factors)
+
+	// Assert
+	assert.NoError(t, err)
+	mockFactorRepo.AssertExpectations(t)
+}
+
+func TestCalculatorService_CalculateHeating_UnitMismatch(t *testing.T) {
+	// Setup
+	mockCalcRepo := new(MockCalculationRepository)
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	logger := logger.New("debug")
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
+
+	ctx := context.Background()
+
+	emissionFactor := &models.EmissionFactor{
+		ActivityType: models.ActivityTypeHeating,
+		SubType:      models.HeatingFuelNaturalGas,
+		FactorCO2:    2.0, // kg CO2 per m3
+		Unit:         "m3",
+		Source:       "EPA 2023",
+	}
+
+	mockFactorRepo.On("GetByActivityTypeAndSubType", ctx, models.ActivityTypeHeating, models.HeatingFuelNaturalGas).
+		Return(emissionFactor, nil)
+
+	// Consumption reported in kWh against a per-m3 factor
+	activityData := map[string]interface{}{
+		"fuel_type":   models.HeatingFuelNaturalGas,
+		"consumption": 100.0,
+		"unit":        "kWh",
+	}
+
+	// Execute
+	result, err := service.calculateHeating(ctx, activityData)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "does not match emission factor unit")
+
+	mockFactorRepo.AssertExpectations(t)
+}
+
+func TestCalculatorService_EraseUserData(t *testing.T) {
+	// Setup
+	mockCalcRepo := new(MockCalculationRepository)
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	logger := logger.New("debug")
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
+
+	ctx := context.Background()
+	userID := "user-123"
+
+	mockCalcRepo.On("DeleteByUserID", ctx, userID).Return(nil)
+
+	// Execute
+	err := service.EraseUserData(ctx, userID)
+
+	// Assert
+	assert.NoError(t, err)
+	mockCalcRepo.AssertExpectations(t)
+}
+
+func TestCalculatorService_GetGroupedEmissionFactors(t *testing.T) {
+	// Setup
+	mockCalcRepo := new(MockCalculationRepository)
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	logger := logger.New("debug")
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, nil, logger)
+
+	ctx := context.Background()
+
+	// Ordered as the repository returns them: location-specific first
+	factors := []*models.EmissionFactor{
+		{ActivityType: models.ActivityTypeElectricity, SubType: "grid", FactorCO2: 0.5, Unit: "kWh", Source: "IEA 2023", Location: "US"},
+		{ActivityType: models.ActivityTypeElectricity, SubType: "grid", FactorCO2: 0.45, Unit: "kWh", Source: "IEA 2023"},
+		{ActivityType: models.ActivityTypeVehicleTravel, SubType: models.VehicleTypeCarElectric, FactorCO2: 0.05, Unit: "km", Source: "EPA 2023"},
+		{ActivityType: models.ActivityTypeVehicleTravel, SubType: models.VehicleTypeCarGasoline, FactorCO2: 0.21, Unit: "km", Source: "EPA 2023"},
+		{ActivityType: models.ActivityTypeVehicleTravel, SubType: "bicycle", FactorCO2: 0, Unit: "km", Source: "EPA 2023"},
+	}
+	mockFactorRepo.On("GetEffective", ctx, "US", mock.AnythingOfType("time.Time")).Return(factors, nil)
+
+	// Execute
+	result, err := service.GetGroupedEmissionFactors(ctx, "US")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.ActivityTypes, 2)
+
+	electricity := result.ActivityTypes[models.ActivityTypeElectricity]
+	assert.Len(t, electricity.SubTypes, 1)
+	assert.Equal(t, "US", electricity.SubTypes[0].Location)
+
+	vehicles := result.ActivityTypes[models.ActivityTypeVehicleTravel].SubTypes
+	assert.Equal(t, []string{"bicycle", models.VehicleTypeCarElectric, models.VehicleTypeCarGasoline},
+		[]string{vehicles[0].SubType, vehicles[1].SubType, vehicles[2].SubType})
+	assert.Equal(t, 0.0, vehicles[0].RelativeToLowest)
+	assert.Equal(t, 1.0, vehicles[1].RelativeToLowest)
+	assert.InDelta(t, 4.2, vehicles[2].RelativeToLowest, 1e-9)
+
+	mockFactorRepo.AssertExpectations(t)
+}
+
+func TestCalculatorService_DeleteEmissionFactor_Unused(t *testing.T) {
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	service := NewCalculatorService(new(MockCalculationRepository), mockFactorRepo, nil, logger.New("error"))
+	ctx := context.Background()
+
+	factor := &models.EmissionFactor{ID: uuid.New(), ActivityType: models.ActivityTypeHeating, SubType: "natural_gas"}
+	mockFactorRepo.On("GetByID", ctx, factor.ID).Return(factor, nil)
+	mockFactorRepo.On("CountReferences", ctx, factor.ID).Return(int64(0), nil)
+	mockFactorRepo.On("Delete", ctx, factor.ID.String()).Return(nil)
+
+	err := service.DeleteEmissionFactor(ctx, factor.ID, "admin-1")
+
+	assert.NoError(t, err)
+	mockFactorRepo.AssertExpectations(t)
+}
+
+func TestCalculatorService_DeleteEmissionFactor_RetiresReferencedFactor(t *testing.T) {
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	service := NewCalculatorService(new(MockCalculationRepository), mockFactorRepo, nil, logger.New("error"))
+	ctx := context.Background()
+
+	factor := &models.EmissionFactor{ID: uuid.New(), ActivityType: models.ActivityTypeHeating, SubType: "natural_gas"}
+	mockFactorRepo.On("GetByID", ctx, factor.ID).Return(factor, nil)
+	mockFactorRepo.On("CountReferences", ctx, factor.ID).Return(int64(3), nil)
+	mockFactorRepo.On("Update", ctx, factor).Return(nil)
+
+	err := service.DeleteEmissionFactor(ctx, factor.ID, "admin-1")
+
+	assert.ErrorIs(t, err, ErrEmissionFactorRetired)
+	assert.NotNil(t, factor.EffectiveTo)
+	mockFactorRepo.AssertNotCalled(t, "Delete", ctx, factor.ID.String())
+}
+
+// seededFactorRepository serves a fixed set of emission factors, filtering
+// them the way EmissionFactorRepository does
+type seededFactorRepository struct {
+	MockEmissionFactorRepository
+	factors []*models.EmissionFactor
+}
+
+func (r *seededFactorRepository) GetByActivityType(ctx context.Context, activityType string) ([]*models.EmissionFactor, error) {
+	var factors []*models.EmissionFactor
+	for _, factor := range r.factors {
+		if factor.ActivityType == activityType {
+			factors = append(factors, factor)
+		}
+	}
+	return factors, nil
+}
+
+func (r *seededFactorRepository) GetAll(ctx context.Context, activityType, location string, limit, offset int) ([]*models.EmissionFactor, int64, error) {
+	var matched []*models.EmissionFactor
+	for _, factor := range r.factors {
+		if activityType != "" && factor.ActivityType != activityType {
+			continue
+		}
+		if location != "" && factor.Location != location && factor.Location != "" {
+			continue
+		}
+		matched = append(matched, factor)
+	}
+
+	total := int64(len(matched))
+	if offset >= len(matched) {
+		return nil, total, nil
+	}
+	matched = matched[offset:]
+	if limit < len(matched) {
+		matched = matched[:limit]
+	}
+	return matched, total, nil
+}
+
+func newSeededFactorRepository() *seededFactorRepository {
+	return &seededFactorRepository{factors: []*models.EmissionFactor{
+		{ActivityType: models.ActivityTypeElectricity, SubType: "grid", FactorCO2: 0.45, Unit: "kWh", Source: "IEA 2023"},
+		{ActivityType: models.ActivityTypeElectricity, SubType: "grid", FactorCO2: 0.39, Unit: "kWh", Source: "EPA eGRID 2023", Location: "US"},
+		{ActivityType: models.ActivityTypeElectricity, SubType: "grid", FactorCO2: 0.06, Unit: "kWh", Source: "RTE 2023", Location: "FR"},
+		{ActivityType: models.ActivityTypeVehicleTravel, SubType: models.VehicleTypeCarGasoline, FactorCO2: 0.21, Unit: "km", Source: "EPA 2023"},
+	}}
+}
+
+func TestCalculatorService_ListEmissionFactors_FiltersByLocation(t *testing.T) {
+	service := NewCalculatorService(new(MockCalculationRepository), newSeededFactorRepository(), nil, logger.New("error"))
+	ctx := context.Background()
+
+	factors, total, err := service.ListEmissionFactors(ctx, models.ActivityTypeElectricity, "US", 20, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []string{"", "US"}, []string{factors[0].Location, factors[1].Location})
+	assert.Equal(t, "grid", factors[1].SubType)
+	assert.Equal(t, 0.39, factors[1].FactorCO2)
+	assert.Equal(t, "kWh", factors[1].Unit)
+	assert.Equal(t, "EPA eGRID 2023", factors[1].Source)
+
+	// Without filters every factor is listed, a page at a time
+	factors, total, err = service.ListEmissionFactors(ctx, "", "", 3, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	assert.Len(t, factors, 3)
+}
+
+func TestCalculatorService_ListEmissionFactors_RejectsUnknownActivityType(t *testing.T) {
+	repo := newSeededFactorRepository()
+	service := NewCalculatorService(new(MockCalculationRepository), repo, nil, logger.New("error"))
+	ctx := context.Background()
+
+	_, _, err := service.ListEmissionFactors(ctx, "teleportation", "", 20, 0)
+	assert.ErrorIs(t, err, ErrUnknownActivityType)
+
+	_, err = service.ListEmissionFactorsByType(ctx, "teleportation")
+	assert.ErrorIs(t, err, ErrUnknownActivityType)
+}
+
+func TestCalculatorService_ListEmissionFactorsByType(t *testing.T) {
+	service := NewCalculatorService(new(MockCalculationRepository), newSeededFactorRepository(), nil, logger.New("error"))
+
+	factors, err := service.ListEmissionFactorsByType(context.Background(), models.ActivityTypeVehicleTravel)
+
+	assert.NoError(t, err)
+	assert.Len(t, factors, 1)
+	assert.Equal(t, models.VehicleTypeCarGasoline, factors[0].SubType)
+	assert.Equal(t, "km", factors[0].Unit)
+}
+
+func TestCalculatorService_CalculateFlight_UsesGreatCircleDistance(t *testing.T) {
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	service := NewCalculatorService(new(MockCalculationRepository), mockFactorRepo, nil, logger.New("error"))
+	ctx := context.Background()
+
+	factor := &models.EmissionFactor{ActivityType: models.ActivityTypeFlight, SubType: models.FlightClassEconomy, FactorCO2: 0.1, Unit: "km", Source: "DEFRA 2023"}
+	mockFactorRepo.On("GetByActivityTypeAndSubType", ctx, models.ActivityTypeFlight, models.FlightClassEconomy).Return(factor, nil)
+
+	result, err := service.calculateFlight(ctx, map[string]interface{}{
+		"departure_airport": "LHR",
+		"arrival_airport":   "JFK",
+		"is_round_trip":     true,
+	})
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*5540*0.1, result.CO2Kg, 5)
+}
+
+func TestCalculatorService_CalculateFlight_UnknownAirport(t *testing.T) {
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	service := NewCalculatorService(new(MockCalculationRepository), mockFactorRepo, nil, logger.New("error"))
+
+	_, err := service.calculateFlight(context.Background(), map[string]interface{}{
+		"departure_airport": "LHR",
+		"arrival_airport":   "ZZZ",
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidActivity)
+	assert.ErrorIs(t, err, airports.ErrUnknownAirport)
+	mockFactorRepo.AssertNotCalled(t, "GetByActivityTypeAndSubType", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCalculatorService_UpsertEmissionFactor_CreatesNewFactor(t *testing.T) {
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	service := NewCalculatorService(new(MockCalculationRepository), mockFactorRepo, nil, logger.New("error"))
+	ctx := context.Background()
+
+	mockFactorRepo.On("GetByKey", ctx, models.ActivityTypeElectricity, "grid", "FR").Return([]*models.EmissionFactor{}, nil)
+	mockFactorRepo.On("Create", ctx, mock.MatchedBy(func(f *models.EmissionFactor) bool {
+		return f.Location == "FR" && f.FactorCO2 == 0.06 && f.Source == "IEA 2024" && !f.LastUpdated.IsZero()
+	})).Return(nil)
+
+	factor, created, err := service.UpsertEmissionFactor(ctx, &EmissionFactorRequest{
+		ActivityType: models.ActivityTypeElectricity,
+		SubType:      "grid",
+		Location:     " FR ",
+		FactorCO2:    0.06,
+		Unit:         "kWh",
+		Source:       "IEA 2024",
+	}, "admin-1")
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, 0.06, factor.FactorCO2)
+	mockFactorRepo.AssertExpectations(t)
+}
+
+func TestCalculatorService_UpsertEmissionFactor_UpdatesAndKeepsVersion(t *testing.T) {
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	service := NewCalculatorService(new(MockCalculationRepository), mockFactorRepo, nil, logger.New("error"))
+	ctx := context.Background()
+
+	lastUpdated := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	existing := &models.EmissionFactor{
+		ID:           uuid.New(),
+		ActivityType: models.ActivityTypeVehicleTravel,
+		SubType:      models.VehicleTypeCarGasoline,
+		FactorCO2:    0.21,
+		Unit:         "km",
+		Source:       "EPA 2023",
+		LastUpdated:  lastUpdated,
+	}
+	mockFactorRepo.On("GetByKey", ctx, models.ActivityTypeVehicleTravel, models.VehicleTypeCarGasoline, "").
+		Return([]*models.EmissionFactor{existing}, nil)
+
+	var saved *models.EmissionFactor
+	var version *models.EmissionFactorVersion
+	mockFactorRepo.On("UpdateWithVersion", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*models.EmissionFactor)
+			version = args.Get(2).(*models.EmissionFactorVersion)
+		}).
+		Return(nil)
+
+	factor, created, err := service.UpsertEmissionFactor(ctx, &EmissionFactorRequest{
+		ActivityType: models.ActivityTypeVehicleTravel,
+		SubType:      models.VehicleTypeCarGasoline,
+		FactorCO2:    0.19,
+		Unit:         "km",
+		Source:       "EPA 2024",
+	}, "admin-1")
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, existing.ID, factor.ID)
+	assert.Equal(t, 0.19, saved.FactorCO2)
+	assert.True(t, saved.LastUpdated.After(lastUpdated))
+
+	assert.Equal(t, existing.ID, version.EmissionFactorID)
+	assert.Equal(t, 0.21, version.FactorCO2)
+	assert.Equal(t, "EPA 2023", version.Source)
+	assert.Equal(t, lastUpdated, version.LastUpdated)
+	assert.Equal(t, saved.LastUpdated, version.SupersededAt)
+	assert.Equal(t, "admin-1", version.SupersededBy)
+
+	// The stored factor is only changed through UpdateWithVersion
+	assert.Equal(t, 0.21, existing.FactorCO2)
+}
+
+func TestCalculatorService_UpsertEmissionFactor_UnchangedSkipsUpdate(t *testing.T) {
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	service := NewCalculatorService(new(MockCalculationRepository), mockFactorRepo, nil, logger.New("error"))
+	ctx := context.Background()
+
+	existing := &models.EmissionFactor{ID: uuid.New(), ActivityType: models.ActivityTypeFlight, SubType: models.FlightClassEconomy, FactorCO2: 0.15, Unit: "km", Source: "ICAO 2023"}
+	mockFactorRepo.On("GetByKey", ctx, models.ActivityTypeFlight, models.FlightClassEconomy, "").Return([]*models.EmissionFactor{existing}, nil)
+
+	_, created, err := service.UpsertEmissionFactor(ctx, &EmissionFactorRequest{
+		ActivityType: models.ActivityTypeFlight,
+		SubType:      models.FlightClassEconomy,
+		FactorCO2:    0.15,
+		Unit:         "km",
+		Source:       "ICAO 2023",
+	}, "admin-1")
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+	mockFactorRepo.AssertNotCalled(t, "UpdateWithVersion", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCalculatorService_UpsertEmissionFactor_RejectsInvalidFactor(t *testing.T) {
+	service := NewCalculatorService(new(MockCalculationRepository), new(MockEmissionFactorRepository), nil, logger.New("error"))
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]*EmissionFactorRequest{
+		"negative co2": {ActivityType: models.ActivityTypeFlight, SubType: models.FlightClassEconomy, FactorCO2: -0.1, Unit: "km", Source: "ICAO"},
+		"negative ch4": {ActivityType: models.ActivityTypeFlight, SubType: models.FlightClassEconomy, FactorCH4: -0.1, Unit: "km", Source: "ICAO"},
+		"wrong unit":   {ActivityType: models.ActivityTypeFlight, SubType: models.FlightClassEconomy, FactorCO2: 0.1, Unit: "mi", Source: "ICAO"},
+		"empty period": {ActivityType: models.ActivityTypeFlight, SubType: models.FlightClassEconomy, FactorCO2: 0.1, Unit: "km", Source: "ICAO", EffectiveFrom: &from, EffectiveTo: &from},
+	}
+	for name, req := range tests {
+		_, _, err := service.UpsertEmissionFactor(context.Background(), req, "admin-1")
+		assert.ErrorIs(t, err, ErrInvalidEmissionFactor, name)
+	}
+
+	_, _, err := service.UpsertEmissionFactor(context.Background(), &EmissionFactorRequest{ActivityType: "teleport", SubType: "x", Unit: "km", Source: "x"}, "admin-1")
+	assert.ErrorIs(t, err, ErrUnknownActivityType)
+}
+
+func TestCalculatorService_UpdateEmissionFactor_RejectsDuplicateKey(t *testing.T) {
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	service := NewCalculatorService(new(MockCalculationRepository), mockFactorRepo, nil, logger.New("error"))
+	ctx := context.Background()
+
+	factor := &models.EmissionFactor{ID: uuid.New(), ActivityType: models.ActivityTypeElectricity, SubType: "grid", Location: "EU", FactorCO2: 0.3, Unit: "kWh"}
+	other := &models.EmissionFactor{ID: uuid.New(), ActivityType: models.ActivityTypeElectricity, SubType: "grid", Location: "US", FactorCO2: 0.5, Unit: "kWh"}
+	mockFactorRepo.On("GetByID", ctx, factor.ID).Return(factor, nil)
+	mockFactorRepo.On("GetByKey", ctx, models.ActivityTypeElectricity, "grid", "US").Return([]*models.EmissionFactor{other}, nil)
+
+	_, err := service.UpdateEmissionFactor(ctx, factor.ID, &EmissionFactorRequest{
+		ActivityType: models.ActivityTypeElectricity,
+		SubType:      "grid",
+		Location:     "US",
+		FactorCO2:    0.4,
+		Unit:         "kWh",
+		Source:       "IEA 2024",
+	}, "admin-1")
+
+	assert.ErrorIs(t, err, ErrEmissionFactorConflict)
+	mockFactorRepo.AssertNotCalled(t, "UpdateWithVersion", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOverlappingFactors(t *testing.T) {
+	at := func(year int) *time.Time {
+		t := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	vintage2022 := &models.EmissionFactor{ID: uuid.New(), EffectiveFrom: at(2022), EffectiveTo: at(2023)}
+	vintage2023 := &models.EmissionFactor{ID: uuid.New(), EffectiveFrom: at(2023)}
+	factors := []*models.EmissionFactor{vintage2022, vintage2023}
+
+	assert.Len(t, overlappingFactors(factors, nil, nil, uuid.Nil), 2)
+	assert.Equal(t, []*models.EmissionFactor{vintage2023}, overlappingFactors(factors, at(2023), nil, uuid.Nil))
+	assert.Equal(t, []*models.EmissionFactor{vintage2022}, overlappingFactors(factors, nil, at(2023), uuid.Nil))
+	assert.Empty(t, overlappingFactors(factors, at(2020), at(2022), uuid.Nil))
+	assert.Empty(t, overlappingFactors(factors, at(2023), nil, vintage2023.ID))
+}
+
+// storedCalculation is a calculation of a 100 km gasoline car trip and 200 kWh
+// of EU electricity
+func storedCalculation(userID string) *models.Calculation {
+	return &models.Calculation{
+		ID:         uuid.New(),
+		UserID:     userID,
+		TotalCO2Kg: 81,
+		Activities: []models.Activity{
+			{ID: uuid.New(), ActivityType: models.ActivityTypeVehicleTravel, CO2Kg: 21, ActivityData: `{"vehicle_type":"car_gasoline","distance_km":100}`},
+			{ID: uuid.New(), ActivityType: models.ActivityTypeElectricity, CO2Kg: 60, ActivityData: `{"kwh_usage":200,"location":"EU"}`},
+		},
+	}
+}
+
+func TestCalculatorService_Recalculate_AppliesOverridesWithoutSaving(t *testing.T) {
+	mockCalcRepo := new(MockCalculationRepository)
+	service := NewCalculatorService(mockCalcRepo, newCountingFactorRepository(), nil, logger.New("error"))
+	ctx := context.Background()
+
+	calculation := storedCalculation("user-123")
+	original := storedCalculation("user-123")
+	original.ID, original.Activities[0].ID, original.Activities[1].ID = calculation.ID, calculation.Activities[0].ID, calculation.Activities[1].ID
+	mockCalcRepo.On("GetByID", ctx, calculation.ID).Return(calculation, nil)
+
+	response, err := service.Recalculate(ctx, calculation.ID, "user-123", map[string]interface{}{
+		AllActivities: map[string]interface{}{"vehicle_type": models.VehicleTypeBus},
+	})
+
+	assert.NoError(t, err)
+	// The bus emits 0.08 kg/km instead of 0.21; electricity is unchanged
+	assert.InDelta(t, 81.0, response.OriginalCO2Kg, 1e-9)
+	assert.InDelta(t, 68.0, response.RecalculatedCO2Kg, 1e-9)
+	assert.InDelta(t, -13.0, response.DeltaCO2Kg, 1e-9)
+	assert.Len(t, response.Activities, 2)
+	assert.InDelta(t, -13.0, response.Activities[0].DeltaCO2Kg, 1e-9)
+	assert.Equal(t, models.VehicleTypeBus, response.Activities[0].Result.ActivityData["vehicle_type"])
+	assert.InDelta(t, 0.0, response.Activities[1].DeltaCO2Kg, 1e-9)
+	assert.NotContains(t, response.Activities[1].Result.ActivityData, "vehicle_type")
+
+	// The stored calculation is neither changed nor saved
+	assert.Equal(t, original, calculation)
+	mockCalcRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockCalcRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestCalculatorService_Recalculate_PerActivityOverride(t *testing.T) {
+	mockCalcRepo := new(MockCalculationRepository)
+	service := NewCalculatorService(mockCalcRepo, newCountingFactorRepository(), nil, logger.New("error"))
+	ctx := context.Background()
+
+	calculation := storedCalculation("user-123")
+	mockCalcRepo.On("GetByID", ctx, calculation.ID).Return(calculation, nil)
+
+	response, err := service.Recalculate(ctx, calculation.ID, "user-123", map[string]interface{}{
+		calculation.Activities[1].ID.String(): map[string]interface{}{"kwh_usage": 100, "location": "US"},
+	})
+
+	assert.NoError(t, err)
+	// 100 kWh at the global 0.45 kg/kWh, since there is no US factor
+	assert.InDelta(t, 21.0+45.0, response.RecalculatedCO2Kg, 1e-9)
+	assert.InDelta(t, -15.0, response.DeltaCO2Kg, 1e-9)
+}
+
+func TestCalculatorService_Recalculate_RejectsBadOverrides(t *testing.T) {
+	mockCalcRepo := new(MockCalculationRepository)
+	service := NewCalculatorService(mockCalcRepo, newCountingFactorRepository(), nil, logger.New("error"))
+	ctx := context.Background()
+
+	calculation := storedCalculation("user-123")
+	mockCalcRepo.On("GetByID", ctx, calculation.ID).Return(calculation, nil)
+
+	for name, overrides := range map[string]map[string]interface{}{
+		"unknown activity": {uuid.New().String(): map[string]interface{}{"distance_km": 1}},
+		"not an object":    {AllActivities: "car_electric"},
+		"unknown sub-type": {AllActivities: map[string]interface{}{"vehicle_type": "hovercraft"}},
+	} {
+		_, err := service.Recalculate(ctx, calculation.ID, "user-123", overrides)
+		assert.ErrorIs(t, err, ErrInvalidActivity, name)
+	}
+
+	// Another user's calculation is not found
+	_, err := service.Recalculate(ctx, calculation.ID, "user-456", nil)
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func TestCalculatorService_CalculateVehicleTravel_ConvertsMiles(t *testing.T) {
+	service := NewCalculatorService(new(MockCalculationRepository), newCountingFactorRepository(), nil, logger.New("error"))
+
+	result, err := service.calculateVehicleTravel(context.Background(), map[string]interface{}{
+		"vehicle_type": models.VehicleTypeCarGasoline,
+		"distance_km":  100.0,
+		"unit":         "miles",
+	})
+
+	assert.NoError(t, err)
+	// 100 miles is 160.9344 km at 0.21 kg/km
+	assert.InDelta(t, 160.9344*0.21, result.CO2Kg, 1e-9)
+
+	_, err = service.calculateVehicleTravel(context.Background(), map[string]interface{}{
+		"vehicle_type": models.VehicleTypeCarGasoline,
+		"distance_km":  100.0,
+		"unit":         "gallons",
+	})
+	assert.ErrorIs(t, err, ErrInvalidActivity)
+	assert.ErrorIs(t, err, units.ErrUnsupportedConversion)
+}
+
+func TestCalculatorService_CalculateHeating_ConvertsGallons(t *testing.T) {
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	service := NewCalculatorService(new(MockCalculationRepository), mockFactorRepo, nil, logger.New("error"))
+	ctx := context.Background()
+
+	factor := &models.EmissionFactor{ActivityType: models.ActivityTypeHeating, SubType: models.HeatingFuelOil, FactorCO2: 2.7, Unit: "L", Source: "EPA 2023"}
+	mockFactorRepo.On("GetByActivityTypeAndSubType", ctx, models.ActivityTypeHeating, models.HeatingFuelOil).Return(factor, nil)
+
+	result, err := service.calculateHeating(ctx, map[string]interface{}{
+		"fuel_type":   models.HeatingFuelOil,
+		"consumption": 10.0,
+		"unit":        "gallons",
+	})
+
+	assert.NoError(t, err)
+	// 10 US gallons is 37.854 L at 2.7 kg/L
+	assert.InDelta(t, 37.85411784*2.7, result.CO2Kg, 1e-9)
+}
+
+func TestCalculateFootprint_RecordsMetrics(t *testing.T) {
+	metrics := monitoring.NewMetrics("calculator")
+	mockCalcRepo := new(MockCalculationRepository)
+	mockCalcRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Calculation")).Return(nil)
+	mockFactorRepo := new(MockEmissionFactorRepository)
+	mockFactorRepo.On("GetByActivityTypeAndSubType", mock.Anything, models.ActivityTypeVehicleTravel, models.VehicleTypeCarGasoline).
+		Return(&models.EmissionFactor{FactorCO2: 0.21, Unit: "km", Source: "EPA 2023"}, nil)
+	service := NewCalculatorService(mockCalcRepo, mockFactorRepo, metrics, logger.New("error"))
+	ctx := context.Background()
+
+	travel := ActivityDataRequest{
+		ActivityType: models.ActivityTypeVehicleTravel,
+		Data:         map[string]interface{}{"vehicle_type": models.VehicleTypeCarGasoline, "distance_km": 10.0},
+	}
+	_, err := service.CalculateFootprint(ctx, &CalculateFootprintRequest{
+		UserID:     "test-user-123",
+		Activities: []ActivityDataRequest{travel, travel},
+	})
+	assert.NoError(t, err)
+
+	_, err = service.CalculateFootprint(ctx, &CalculateFootprintRequest{
+		UserID:     "test-user-123",
+		Activities: []ActivityDataRequest{{ActivityType: "teleport"}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidActivity)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.CalculationsTotal.WithLabelValues("calculator", models.ActivityTypeVehicleTravel, calculationStatusSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.CalculationsTotal.WithLabelValues("calculator", "teleport", calculationStatusFailed)))
 }
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/calculator/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// ErrUnknownActivityType is returned when filtering emission factors by an
+// activity type the calculator does not support
+var ErrUnknownActivityType = errors.New("unknown activity type")
+
+// ErrInvalidEmissionFactor is returned for an emission factor with a
+// negative rate, a unit its activity does not use or an empty validity period
+var ErrInvalidEmissionFactor = errors.New("invalid emission factor")
+
+// ErrEmissionFactorConflict is returned when an emission factor would apply
+// at the same time as another for the same activity type, sub-type and location
+var ErrEmissionFactorConflict = errors.New("emission factor conflicts with an existing factor")
+
+// EmissionFactorResponse represents an emission factor in API responses
+type EmissionFactorResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	ActivityType  string     `json:"activity_type"`
+	SubType       string     `json:"sub_type"`
+	FactorCO2     float64    `json:"factor_co2_per_unit"`
+	FactorCH4     float64    `json:"factor_ch4_per_unit"`
+	FactorN2O     float64    `json:"factor_n2o_per_unit"`
+	FactorCO2e    float64    `json:"factor_co2e_per_unit"`
+	Unit          string     `json:"unit"`
+	Source        string     `json:"source"`
+	Location      string     `json:"location,omitempty"` // empty for global factors
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	LastUpdated   time.Time  `json:"last_updated"`
+}
+
+// EmissionFactorRequest represents a request to create or update an
+// emission factor. Rates are in kg of each gas per unit; an omitted
+// effective period is unbounded on that side.
+type EmissionFactorRequest struct {
+	ActivityType  string     `json:"activity_type" binding:"required"`
+	SubType       string     `json:"sub_type" binding:"required"`
+	Location      string     `json:"location"` // empty for a global factor
+	FactorCO2     float64    `json:"factor_co2_per_unit"`
+	FactorCH4     float64    `json:"factor_ch4_per_unit"`
+	FactorN2O     float64    `json:"factor_n2o_per_unit"`
+	Unit          string     `json:"unit" binding:"required"`
+	Source        string     `json:"source" binding:"required"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+}
+
+// ListEmissionFactors retrieves a page of emission factors ordered by
+// activity type and sub-type. Empty filters match every factor; a location
+// matches that location's factors and the global ones.
+func (s *CalculatorService) ListEmissionFactors(ctx context.Context, activityType, location string, limit, offset int) ([]*EmissionFactorResponse, int64, error) {
+	if activityType != "" {
+		if err := checkActivityType(activityType); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	factors, total, err := s.emissionFactorRepo.GetAll(ctx, activityType, location, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get emission factors: %w", err)
+	}
+
+	return emissionFactorsToResponse(factors), total, nil
+}
+
+// ListEmissionFactorsByType retrieves every emission factor of an activity
+// type, ordered by sub-type
+func (s *CalculatorService) ListEmissionFactorsByType(ctx context.Context, activityType string) ([]*EmissionFactorResponse, error) {
+	if err := checkActivityType(activityType); err != nil {
+		return nil, err
+	}
+
+	factors, err := s.emissionFactorRepo.GetByActivityType(ctx, activityType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emission factors: %w", err)
+	}
+
+	return emissionFactorsToResponse(factors), nil
+}
+
+// checkActivityType refuses activity types the calculator does not support
+func checkActivityType(activityType string) error {
+	if !models.IsActivityType(activityType) {
+		return fmt.Errorf("%w: %q", ErrUnknownActivityType, activityType)
+	}
+	return nil
+}
+
+// emissionFactorsToResponse converts factors to their API representation
+func emissionFactorsToResponse(factors []*models.EmissionFactor) []*EmissionFactorResponse {
+	responses := make([]*EmissionFactorResponse, len(factors))
+	for i, factor := range factors {
+		responses[i] = &EmissionFactorResponse{
+			ID:            factor.ID,
+			ActivityType:  factor.ActivityType,
+			SubType:       factor.SubType,
+			FactorCO2:     factor.FactorCO2,
+			FactorCH4:     factor.FactorCH4,
+			FactorN2O:     factor.FactorN2O,
+			FactorCO2e:    factor.CO2eFactor(),
+			Unit:          factor.Unit,
+			Source:        factor.Source,
+			Location:      factor.Location,
+			EffectiveFrom: factor.EffectiveFrom,
+			EffectiveTo:   factor.EffectiveTo,
+			LastUpdated:   factor.LastUpdated,
+		}
+	}
+	return responses
+}
+
+// UpsertEmissionFactor creates the emission factor for the request's
+// activity type, sub-type and location, or updates the existing one whose
+// effective period overlaps the request's (admin operation). It reports
+// whether the factor was created.
+func (s *CalculatorService) UpsertEmissionFactor(ctx context.Context, req *EmissionFactorRequest, actorID string) (*EmissionFactorResponse, bool, error) {
+	if err := validateEmissionFactorRequest(req); err != nil {
+		return nil, false, err
+	}
+
+	existing, err := s.emissionFactorRepo.GetByKey(ctx, req.ActivityType, req.SubType, strings.TrimSpace(req.Location))
+	if err != nil {
+		return nil, false, err
+	}
+
+	overlapping := overlappingFactors(existing, req.EffectiveFrom, req.EffectiveTo, uuid.Nil)
+	switch len(overlapping) {
+	case 0:
+		factor := &models.EmissionFactor{}
+		applyEmissionFactorRequest(factor, req)
+		factor.LastUpdated = time.Now().UTC()
+		if err := s.emissionFactorRepo.Create(ctx, factor); err != nil {
+			return nil, false, err
+		}
+
+		s.logger.LogInfo(ctx, "emission factor created",
+			logger.String("factor_id", factor.ID.String()),
+			logger.String("actor_id", actorID))
+
+		return emissionFactorsToResponse([]*models.EmissionFactor{factor})[0], true, nil
+	case 1:
+		factor, err := s.updateEmissionFactor(ctx, overlapping[0], req, actorID)
+		if err != nil {
+			return nil, false, err
+		}
+		return factor, false, nil
+	default:
+		return nil, false, fmt.Errorf("%w: %d factors for %s/%s apply in that period, update one of them by ID",
+			ErrEmissionFactorConflict, len(overlapping), req.ActivityType, req.SubType)
+	}
+}
+
+// UpdateEmissionFactor replaces an emission factor's values (admin
+// operation). The values it had are kept as a version.
+func (s *CalculatorService) UpdateEmissionFactor(ctx context.Context, id uuid.UUID, req *EmissionFactorRequest, actorID string) (*EmissionFactorResponse, error) {
+	factor, err := s.emissionFactorRepo.GetByID(ctx, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrEmissionFactorNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emission factor: %w", err)
+	}
+
+	if err := validateEmissionFactorRequest(req); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.emissionFactorRepo.GetByKey(ctx, req.ActivityType, req.SubType, strings.TrimSpace(req.Location))
+	if err != nil {
+		return nil, err
+	}
+	if conflicts := overlappingFactors(existing, req.EffectiveFrom, req.EffectiveTo, id); len(conflicts) > 0 {
+		return nil, fmt.Errorf("%w: factor %s for %s/%s applies in that period",
+			ErrEmissionFactorConflict, conflicts[0].ID, req.ActivityType, req.SubType)
+	}
+
+	return s.updateEmissionFactor(ctx, factor, req, actorID)
+}
+
+// ListEmissionFactorVersions retrieves the values an emission factor had
+// before each update, most recent first
+func (s *CalculatorService) ListEmissionFactorVersions(ctx context.Context, id uuid.UUID) ([]*models.EmissionFactorVersion, error) {
+	if _, err := s.emissionFactorRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %w", ErrEmissionFactorNotFound, err)
+		}
+		return nil, fmt.Errorf("failed to get emission factor: %w", err)
+	}
+
+	return s.emissionFactorRepo.GetVersions(ctx, id)
+}
+
+// updateEmissionFactor applies req to factor, keeping its previous values as
+// a version. A request that changes nothing leaves the factor untouched.
+func (s *CalculatorService) updateEmissionFactor(ctx context.Context, factor *models.EmissionFactor, req *EmissionFactorRequest, actorID string) (*EmissionFactorResponse, error) {
+	now := time.Now().UTC()
+	previous := emissionFactorVersion(factor, now, actorID)
+
+	updated := *factor
+	applyEmissionFactorRequest(&updated, req)
+	if sameEmissionFactorValues(factor, &updated) {
+		return emissionFactorsToResponse([]*models.EmissionFactor{factor})[0], nil
+	}
+	updated.LastUpdated = now
+
+	if err := s.emissionFactorRepo.UpdateWithVersion(ctx, &updated, previous); err != nil {
+		return nil, err
+	}
+
+	s.logger.LogInfo(ctx, "emission factor updated",
+		logger.String("factor_id", updated.ID.String()),
+		logger.Float64("previous_factor_co2", previous.FactorCO2),
+		logger.Float64("factor_co2", updated.FactorCO2),
+		logger.String("actor_id", actorID))
+
+	return emissionFactorsToResponse([]*models.EmissionFactor{&updated})[0], nil
+}
+
+// validateEmissionFactorRequest checks a factor's activity, unit, rates and
+// effective period
+func validateEmissionFactorRequest(req *EmissionFactorRequest) error {
+	if err := checkActivityType(req.ActivityType); err != nil {
+		return err
+	}
+
+	factor := &models.EmissionFactor{ActivityType: req.ActivityType, SubType: req.SubType, Unit: req.Unit}
+	if err := factor.ValidateUnit(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEmissionFactor, err)
+	}
+
+	for gas, rate := range map[string]float64{"co2": req.FactorCO2, "ch4": req.FactorCH4, "n2o": req.FactorN2O} {
+		if rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+			return fmt.Errorf("%w: factor_%s_per_unit must be zero or more", ErrInvalidEmissionFactor, gas)
+		}
+	}
+
+	if req.EffectiveFrom != nil && req.EffectiveTo != nil && !req.EffectiveTo.After(*req.EffectiveFrom) {
+		return fmt.Errorf("%w: effective_to must be after effective_from", ErrInvalidEmissionFactor)
+	}
+	return nil
+}
+
+// applyEmissionFactorRequest copies req onto factor
+func applyEmissionFactorRequest(factor *models.EmissionFactor, req *EmissionFactorRequest) {
+	factor.ActivityType = req.ActivityType
+	factor.SubType = req.SubType
+	factor.Location = strings.TrimSpace(req.Location)
+	factor.FactorCO2 = req.FactorCO2
+	factor.FactorCH4 = req.FactorCH4
+	factor.FactorN2O = req.FactorN2O
+	factor.Unit = req.Unit
+	factor.Source = strings.TrimSpace(req.Source)
+	factor.EffectiveFrom = utcTime(req.EffectiveFrom)
+	factor.EffectiveTo = utcTime(req.EffectiveTo)
+}
+
+// emissionFactorVersion snapshots a factor's current values as superseded
+// at now
+func emissionFactorVersion(factor *models.EmissionFactor, now time.Time, actorID string) *models.EmissionFactorVersion {
+	return &models.EmissionFactorVersion{
+		EmissionFactorID: factor.ID,
+		FactorCO2:        factor.FactorCO2,
+		FactorCH4:        factor.FactorCH4,
+		FactorN2O:        factor.FactorN2O,
+		Unit:             factor.Unit,
+		Source:           factor.Source,
+		EffectiveFrom:    factor.EffectiveFrom,
+		EffectiveTo:      factor.EffectiveTo,
+		LastUpdated:      factor.LastUpdated,
+		SupersededAt:     now,
+		SupersededBy:     actorID,
+	}
+}
+
+// overlappingFactors returns the factors other than excludeID whose
+// effective period overlaps from..to. Periods include their start and
+// exclude their end; nil is unbounded.
+func overlappingFactors(factors []*models.EmissionFactor, from, to *time.Time, excludeID uuid.UUID) []*models.EmissionFactor {
+	var overlapping []*models.EmissionFactor
+	for _, factor := range factors {
+		if factor.ID == excludeID && excludeID != uuid.Nil {
+			continue
+		}
+		startsBeforeEnd := to == nil || factor.EffectiveFrom == nil || factor.EffectiveFrom.Before(*to)
+		endsAfterStart := from == nil || factor.EffectiveTo == nil || factor.EffectiveTo.After(*from)
+		if startsBeforeEnd && endsAfterStart {
+			overlapping = append(overlapping, factor)
+		}
+	}
+	return overlapping
+}
+
+// sameEmissionFactorValues reports whether two factors have the same key,
+// rates, source and effective period
+func sameEmissionFactorValues(a, b *models.EmissionFactor) bool {
+	return a.ActivityType == b.ActivityType && a.SubType == b.SubType && a.Location == b.Location &&
+		a.FactorCO2 == b.FactorCO2 && a.FactorCH4 == b.FactorCH4 && a.FactorN2O == b.FactorN2O &&
+		a.Unit == b.Unit && a.Source == b.Source &&
+		sameTime(a.EffectiveFrom, b.EffectiveFrom) && sameTime(a.EffectiveTo, b.EffectiveTo)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/calculator/internal/models"
+	"github.com/sloweyyy/GreenLedger/services/calculator/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// DefaultFactorCacheTTL is how long cached emission factors are used when no
+// TTL is configured
+const DefaultFactorCacheTTL = 5 * time.Minute
+
+// EmissionFactorCache serves the emission factor lookups calculations make
+// from an in-memory copy of every factor, so a calculation no longer queries
+// the database once per activity. Writes made through the cache invalidate
+// it; writes made elsewhere, such as by another replica, show up once the
+// copy is older than the TTL. All other methods pass through to the
+// repository.
+type EmissionFactorCache struct {
+	repository.EmissionFactorRepositoryInterface
+	ttl    time.Duration
+	logger *logger.Logger
+
+	mu       sync.RWMutex
+	byType   map[string][]*models.EmissionFactor
+	loadedAt time.Time
+}
+
+// NewEmissionFactorCache creates an emission factor cache in front of repo.
+// A non-positive ttl uses DefaultFactorCacheTTL.
+func NewEmissionFactorCache(repo repository.EmissionFactorRepositoryInterface, ttl time.Duration, logger *logger.Logger) *EmissionFactorCache {
+	if ttl <= 0 {
+		ttl = DefaultFactorCacheTTL
+	}
+
+	return &EmissionFactorCache{
+		EmissionFactorRepositoryInterface: repo,
+		ttl:                               ttl,
+		logger:                            logger,
+	}
+}
+
+// Warm loads every emission factor into the cache
+func (c *EmissionFactorCache) Warm(ctx context.Context) error {
+	c.Invalidate()
+	_, err := c.factors(ctx)
+	return err
+}
+
+// Invalidate drops the cached factors so the next lookup reloads them
+func (c *EmissionFactorCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.byType = nil
+}
+
+// GetByActivityTypeAndSubType returns the factor in effect now for an
+// activity type and sub type, newest vintage first, like the repository
+func (c *EmissionFactorCache) GetByActivityTypeAndSubType(ctx context.Context, activityType, subType string) (*models.EmissionFactor, error) {
+	byType, err := c.factors(ctx)
+	if err != nil {
+		return c.EmissionFactorRepositoryInterface.GetByActivityTypeAndSubType(ctx, activityType, subType)
+	}
+
+	now := time.Now().UTC()
+	var best *models.EmissionFactor
+	for _, factor := range byType[activityType] {
+		if factor.SubType != subType || !factorInEffect(factor, now) {
+			continue
+		}
+		if best == nil || newerVintage(factor, best) {
+			best = factor
+		}
+	}
+	if best == nil {
+		return nil, database.ErrNotFound
+	}
+
+	found := *best
+	return &found, nil
+}
+
+// GetByActivityTypeAndLocation returns the factors of an activity type in
+// effect at the given time, location-specific factors first, like the
+// repository
+func (c *EmissionFactorCache) GetByActivityTypeAndLocation(ctx context.Context, activityType, location string, at time.Time) ([]*models.EmissionFactor, error) {
+	byType, err := c.factors(ctx)
+	if err != nil {
+		return c.EmissionFactorRepositoryInterface.GetByActivityTypeAndLocation(ctx, activityType, location, at)
+	}
+
+	var factors []*models.EmissionFactor
+	for _, factor := range byType[activityType] {
+		if !factorInEffect(factor, at) {
+			continue
+		}
+		if location != "" && factor.Location != location && factor.Location != "" {
+			continue
+		}
+		found := *factor
+		factors = append(factors, &found)
+	}
+
+	sort.SliceStable(factors, func(i, j int) bool {
+		a, b := factors[i], factors[j]
+		if location != "" && (a.Location == location) != (b.Location == location) {
+			return a.Location == location
+		}
+		if newerVintage(a, b) || newerVintage(b, a) {
+			return newerVintage(a, b)
+		}
+		return a.SubType < b.SubType
+	})
+
+	return factors, nil
+}
+
+// Create creates an emission factor and invalidates the cache
+func (c *EmissionFactorCache) Create(ctx context.Context, factor *models.EmissionFactor) error {
+	defer c.Invalidate()
+	return c.EmissionFactorRepositoryInterface.Create(ctx, factor)
+}
+
+// Update updates an emission factor and invalidates the cache
+func (c *EmissionFactorCache) Update(ctx context.Context, factor *models.EmissionFactor) error {
+	defer c.Invalidate()
+	return c.EmissionFactorRepositoryInterface.Update(ctx, factor)
+}
+
+// UpdateWithVersion updates an emission factor, keeping a version, and
+// invalidates the cache
+func (c *EmissionFactorCache) UpdateWithVersion(ctx context.Context, factor *models.EmissionFactor, previous *models.EmissionFactorVersion) error {
+	defer c.Invalidate()
+	return c.EmissionFactorRepositoryInterface.UpdateWithVersion(ctx, factor, previous)
+}
+
+// Delete deletes an emission factor and invalidates the cache
+func (c *EmissionFactorCache) Delete(ctx context.Context, id string) error {
+	defer c.Invalidate()
+	return c.EmissionFactorRepositoryInterface.Delete(ctx, id)
+}
+
+// BulkCreate creates emission factors and invalidates the cache
+func (c *EmissionFactorCache) BulkCreate(ctx context.Context, factors []*models.EmissionFactor) error {
+	defer c.Invalidate()
+	return c.EmissionFactorRepositoryInterface.BulkCreate(ctx, factors)
+}
+
+// factors returns the cached factors grouped by activity type, reloading
+// them when they are missing or older than the TTL
+func (c *EmissionFactorCache) factors(ctx context.Context) (map[string][]*models.EmissionFactor, error) {
+	c.mu.RLock()
+	byType, loadedAt := c.byType, c.loadedAt
+	c.mu.RUnlock()
+
+	if byType != nil && time.Since(loadedAt) < c.ttl {
+		return byType, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another caller may have reloaded while this one waited for the lock
+	if c.byType != nil && time.Since(c.loadedAt) < c.ttl {
+		return c.byType, nil
+	}
+
+	factors, _, err := c.EmissionFactorRepositoryInterface.GetAll(ctx, "", "", -1, 0)
+	if err != nil {
+		c.logger.LogError(ctx, "failed to load emission factor cache, querying the database instead", err)
+		return nil, fmt.Errorf("failed to load emission factors: %w", err)
+	}
+
+	c.byType = make(map[string][]*models.EmissionFactor)
+	for _, factor := range factors {
+		c.byType[factor.ActivityType] = append(c.byType[factor.ActivityType], factor)
+	}
+	c.loadedAt = time.Now()
+
+	c.logger.LogInfo(ctx, "emission factor cache loaded",
+		logger.Int("count", len(factors)))
+
+	return c.byType, nil
+}
+
+// factorInEffect reports whether at falls within the factor's effective period
+func factorInEffect(factor *models.EmissionFactor, at time.Time) bool {
+	return (factor.EffectiveFrom == nil || !factor.EffectiveFrom.After(at)) &&
+		(factor.EffectiveTo == nil || factor.EffectiveTo.After(at))
+}
+
+// newerVintage reports whether a took effect after b; factors without a
+// start date are the oldest
+func newerVintage(a, b *models.EmissionFactor) bool {
+	if a.EffectiveFrom == nil {
+		return false
+	}
+	return b.EffectiveFrom == nil || a.EffectiveFrom.After(*b.EffectiveFrom)
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/calculator/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// countingFactorRepository stores emission factors in memory and counts the
+// reads made against it
+type countingFactorRepository struct {
+	MockEmissionFactorRepository
+	mu      sync.Mutex
+	factors []*models.EmissionFactor
+	loadErr error
+	reads   atomic.Int64
+}
+
+func (r *countingFactorRepository) GetAll(ctx context.Context, activityType, location string, limit, offset int) ([]*models.EmissionFactor, int64, error) {
+	r.reads.Add(1)
+	if r.loadErr != nil {
+		return nil, 0, r.loadErr
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	factors := make([]*models.EmissionFactor, len(r.factors))
+	for i, factor := range r.factors {
+		copied := *factor
+		factors[i] = &copied
+	}
+	return factors, int64(len(factors)), nil
+}
+
+func (r *countingFactorRepository) GetByActivityTypeAndSubType(ctx context.Context, activityType, subType string) (*models.EmissionFactor, error) {
+	r.reads.Add(1)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, factor := range r.factors {
+		if factor.ActivityType == activityType && factor.SubType == subType {
+			copied := *factor
+			return &copied, nil
+		}
+	}
+	return nil, database.ErrNotFound
+}
+
+func (r *countingFactorRepository) GetByActivityTypeAndLocation(ctx context.Context, activityType, location string, at time.Time) ([]*models.EmissionFactor, error) {
+	r.reads.Add(1)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Location-specific factors first, like the repository
+	var specific, global []*models.EmissionFactor
+	for _, factor := range r.factors {
+		copied := *factor
+		switch {
+		case factor.ActivityType != activityType:
+		case factor.Location == location && location != "":
+			specific = append(specific, &copied)
+		case factor.Location == "" || location == "":
+			global = append(global, &copied)
+		}
+	}
+	return append(specific, global...), nil
+}
+
+func (r *countingFactorRepository) GetByKey(ctx context.Context, activityType, subType, location string) ([]*models.EmissionFactor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var factors []*models.EmissionFactor
+	for _, factor := range r.factors {
+		if factor.ActivityType == activityType && factor.SubType == subType && factor.Location == location {
+			factors = append(factors, factor)
+		}
+	}
+	return factors, nil
+}
+
+func (r *countingFactorRepository) UpdateWithVersion(ctx context.Context, factor *models.EmissionFactor, previous *models.EmissionFactorVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, stored := range r.factors {
+		if stored.ID == factor.ID {
+			r.factors[i] = factor
+			return nil
+		}
+	}
+	return database.ErrNotFound
+}
+
+func newCountingFactorRepository() *countingFactorRepository {
+	return &countingFactorRepository{factors: []*models.EmissionFactor{
+		{ID: uuid.New(), ActivityType: models.ActivityTypeVehicleTravel, SubType: models.VehicleTypeCarGasoline, FactorCO2: 0.21, Unit: "km", Source: "EPA 2023"},
+		{ID: uuid.New(), ActivityType: models.ActivityTypeVehicleTravel, SubType: models.VehicleTypeBus, FactorCO2: 0.08, Unit: "km", Source: "EPA 2023"},
+		{ID: uuid.New(), ActivityType: models.ActivityTypeElectricity, SubType: "grid", FactorCO2: 0.45, Unit: "kWh", Source: "IEA 2023"},
+		{ID: uuid.New(), ActivityType: models.ActivityTypeElectricity, SubType: "grid", FactorCO2: 0.3, Unit: "kWh", Source: "IEA 2023", Location: "EU"},
+		{ID: uuid.New(), ActivityType: models.ActivityTypeHeating, SubType: models.HeatingFuelNaturalGas, FactorCO2: 2.0, Unit: "m3", Source: "EPA 2023"},
+	}}
+}
+
+// tenActivityRequest is a calculation with ten activities
+func tenActivityRequest() *CalculateFootprintRequest {
+	req := &CalculateFootprintRequest{UserID: "user-123"}
+	for i := 0; i < 5; i++ {
+		req.Activities = append(req.Activities,
+			ActivityDataRequest{ActivityType: models.ActivityTypeVehicleTravel, Data: map[string]interface{}{"vehicle_type": models.VehicleTypeCarGasoline, "distance_km": 10.0}},
+			ActivityDataRequest{ActivityType: models.ActivityTypeElectricity, Data: map[string]interface{}{"kwh_usage": 100.0, "location": "EU"}},
+		)
+	}
+	return req
+}
+
+func TestEmissionFactorCache_ServesCalculationsFromMemory(t *testing.T) {
+	repo := newCountingFactorRepository()
+	cache := NewEmissionFactorCache(repo, time.Minute, logger.New("error"))
+	calcRepo := new(MockCalculationRepository)
+	calcRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	service := NewCalculatorService(calcRepo, cache, nil, logger.New("error"))
+	ctx := context.Background()
+
+	assert.NoError(t, cache.Warm(ctx))
+	for i := 0; i < 3; i++ {
+		response, err := service.CalculateFootprint(ctx, tenActivityRequest())
+		assert.NoError(t, err)
+		assert.InDelta(t, 5*(10*0.21)+5*(100*0.3), response.TotalCO2Kg, 1e-9)
+	}
+
+	// Only the warm-up read the repository
+	assert.Equal(t, int64(1), repo.reads.Load())
+}
+
+func TestEmissionFactorCache_HonorsUpdates(t *testing.T) {
+	repo := newCountingFactorRepository()
+	cache := NewEmissionFactorCache(repo, time.Hour, logger.New("error"))
+	service := NewCalculatorService(new(MockCalculationRepository), cache, nil, logger.New("error"))
+	ctx := context.Background()
+
+	data := map[string]interface{}{"vehicle_type": models.VehicleTypeCarGasoline, "distance_km": 100.0}
+	result, err := service.calculateVehicleTravel(ctx, data)
+	assert.NoError(t, err)
+	assert.InDelta(t, 21.0, result.CO2Kg, 1e-9)
+
+	_, _, err = service.UpsertEmissionFactor(ctx, &EmissionFactorRequest{
+		ActivityType: models.ActivityTypeVehicleTravel,
+		SubType:      models.VehicleTypeCarGasoline,
+		FactorCO2:    0.19,
+		Unit:         "km",
+		Source:       "EPA 2024",
+	}, "admin-1")
+	assert.NoError(t, err)
+
+	result, err = service.calculateVehicleTravel(ctx, data)
+	assert.NoError(t, err)
+	assert.InDelta(t, 19.0, result.CO2Kg, 1e-9)
+	assert.Equal(t, "EPA 2024", result.FactorSource)
+}
+
+func TestEmissionFactorCache_ReloadsAfterTTL(t *testing.T) {
+	repo := newCountingFactorRepository()
+	cache := NewEmissionFactorCache(repo, time.Minute, logger.New("error"))
+	ctx := context.Background()
+
+	assert.NoError(t, cache.Warm(ctx))
+
+	// A change made behind the cache's back, as by another replica
+	repo.mu.Lock()
+	repo.factors[1] = &models.EmissionFactor{ID: repo.factors[1].ID, ActivityType: models.ActivityTypeVehicleTravel, SubType: models.VehicleTypeBus, FactorCO2: 0.07, Unit: "km"}
+	repo.mu.Unlock()
+
+	factor, err := cache.GetByActivityTypeAndSubType(ctx, models.ActivityTypeVehicleTravel, models.VehicleTypeBus)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.08, factor.FactorCO2)
+
+	cache.mu.Lock()
+	cache.loadedAt = time.Now().Add(-2 * time.Minute)
+	cache.mu.Unlock()
+
+	factor, err = cache.GetByActivityTypeAndSubType(ctx, models.ActivityTypeVehicleTravel, models.VehicleTypeBus)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.07, factor.FactorCO2)
+	assert.Equal(t, int64(2), repo.reads.Load())
+}
+
+func TestEmissionFactorCache_MatchesRepositoryLookups(t *testing.T) {
+	at := func(year int) *time.Time {
+		t := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	repo := &countingFactorRepository{factors: []*models.EmissionFactor{
+		{ID: uuid.New(), ActivityType: models.ActivityTypeElectricity, SubType: "grid", FactorCO2: 0.45},
+		{ID: uuid.New(), ActivityType: models.ActivityTypeElectricity, SubType: "grid", FactorCO2: 0.5, Location: "US", EffectiveTo: at(2023)},
+		{ID: uuid.New(), ActivityType: models.ActivityTypeElectricity, SubType: "grid", FactorCO2: 0.4, Location: "US", EffectiveFrom: at(2023)},
+		{ID: uuid.New(), ActivityType: models.ActivityTypeElectricity, SubType: "grid", FactorCO2: 0.3, Location: "EU"},
+		{ID: uuid.New(), ActivityType: models.ActivityTypeFlight, SubType: models.FlightClassEconomy, FactorCO2: 0.2},
+		{ID: uuid.New(), ActivityType: models.ActivityTypeFlight, SubType: models.FlightClassEconomy, FactorCO2: 0.15, EffectiveFrom: at(2020)},
+		{ID: uuid.New(), ActivityType: models.ActivityTypeFlight, SubType: models.FlightClassFirst, FactorCO2: 0.35, EffectiveTo: at(2020)},
+	}}
+	cache := NewEmissionFactorCache(repo, time.Minute, logger.New("error"))
+	ctx := context.Background()
+
+	// The latest vintage in effect wins
+	factor, err := cache.GetByActivityTypeAndSubType(ctx, models.ActivityTypeFlight, models.FlightClassEconomy)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.15, factor.FactorCO2)
+
+	// Retired factors are not found
+	_, err = cache.GetByActivityTypeAndSubType(ctx, models.ActivityTypeFlight, models.FlightClassFirst)
+	assert.True(t, errors.Is(err, database.ErrNotFound))
+
+	// Location-specific factors in effect on the date come before the global one
+	factors, err := cache.GetByActivityTypeAndLocation(ctx, models.ActivityTypeElectricity, "US", *at(2022))
+	assert.NoError(t, err)
+	assert.Len(t, factors, 2)
+	assert.Equal(t, 0.5, factors[0].FactorCO2)
+	assert.Equal(t, 0.45, factors[1].FactorCO2)
+
+	factors, err = cache.GetByActivityTypeAndLocation(ctx, models.ActivityTypeElectricity, "US", *at(2024))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.4, factors[0].FactorCO2)
+
+	// Callers get copies they cannot change the cache through
+	factors[0].FactorCO2 = 99
+	factors, _ = cache.GetByActivityTypeAndLocation(ctx, models.ActivityTypeElectricity, "US", *at(2024))
+	assert.Equal(t, 0.4, factors[0].FactorCO2)
+}
+
+func TestEmissionFactorCache_FallsBackToRepository(t *testing.T) {
+	repo := newCountingFactorRepository()
+	repo.loadErr = fmt.Errorf("connection refused")
+	cache := NewEmissionFactorCache(repo, time.Minute, logger.New("error"))
+	ctx := context.Background()
+
+	assert.Error(t, cache.Warm(ctx))
+
+	factor, err := cache.GetByActivityTypeAndSubType(ctx, models.ActivityTypeHeating, models.HeatingFuelNaturalGas)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, factor.FactorCO2)
+}
+
+func TestEmissionFactorCache_ConcurrentLookups(t *testing.T) {
+	repo := newCountingFactorRepository()
+	cache := NewEmissionFactorCache(repo, time.Minute, logger.New("error"))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%5 == 0 {
+				cache.Invalidate()
+			}
+			factor, err := cache.GetByActivityTypeAndSubType(ctx, models.ActivityTypeVehicleTravel, models.VehicleTypeBus)
+			assert.NoError(t, err)
+			assert.Equal(t, 0.08, factor.FactorCO2)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func benchmarkCalculateFootprint(b *testing.B, cached bool) {
+	repo := newCountingFactorRepository()
+	calcRepo := new(MockCalculationRepository)
+	calcRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	var service *CalculatorService
+	if cached {
+		service = NewCalculatorService(calcRepo, NewEmissionFactorCache(repo, time.Minute, logger.New("error")), nil, logger.New("error"))
+	} else {
+		service = NewCalculatorService(calcRepo, repo, nil, logger.New("error"))
+	}
+	ctx := context.Background()
+	req := tenActivityRequest()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := service.CalculateFootprint(ctx, req); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(repo.reads.Load())/float64(b.N), "factor-reads/op")
+}
+
+// BenchmarkCalculateFootprint compares the emission factor reads of a
+// ten-activity calculation with and without the cache: ten per calculation
+// uncached, a single warm-up read in total cached
+func BenchmarkCalculateFootprint(b *testing.B) {
+	b.Run("uncached", func(b *testing.B) { benchmarkCalculateFootprint(b, false) })
+	b.Run("cached", func(b *testing.B) { benchmarkCalculateFootprint(b, true) })
+}
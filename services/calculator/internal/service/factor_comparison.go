@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/calculator/internal/models"
+)
+
+// GroupedEmissionFactor is one sub-type's factor within an activity type
+type GroupedEmissionFactor struct {
+	SubType   string  `json:"sub_type"`
+	FactorCO2 float64 `json:"factor_co2_per_unit"`
+	Unit      string  `json:"unit"`
+	Source    string  `json:"source"`
+	Location  string  `json:"location,omitempty"`
+
+	// RelativeToLowest is the factor divided by the lowest non-zero factor
+	// of the same unit in the group, so 4.2 reads as "4.2x the cleanest
+	// option". It is zero when the factor itself is zero.
+	RelativeToLowest float64 `json:"relative_to_lowest"`
+}
+
+// EmissionFactorGroup holds an activity type's sub-types, lowest factor first
+type EmissionFactorGroup struct {
+	ActivityType string                  `json:"activity_type"`
+	SubTypes     []GroupedEmissionFactor `json:"sub_types"`
+}
+
+// GroupedEmissionFactorsResponse is the factors in effect now, keyed by activity type
+type GroupedEmissionFactorsResponse struct {
+	Location      string                         `json:"location,omitempty"`
+	ActivityTypes map[string]EmissionFactorGroup `json:"activity_types"`
+}
+
+// GetGroupedEmissionFactors returns the factors currently in effect grouped
+// by activity type for comparison. With a location, a location-specific
+// factor replaces the global one for the same sub-type.
+func (s *CalculatorService) GetGroupedEmissionFactors(ctx context.Context, location string) (*GroupedEmissionFactorsResponse, error) {
+	factors, err := s.emissionFactorRepo.GetEffective(ctx, location, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emission factors: %w", err)
+	}
+
+	return &GroupedEmissionFactorsResponse{
+		Location:      location,
+		ActivityTypes: groupEmissionFactors(factors),
+	}, nil
+}
+
+// groupEmissionFactors keeps the first factor for each activity type and
+// sub-type, so factors must be ordered with the preferred one first
+func groupEmissionFactors(factors []*models.EmissionFactor) map[string]EmissionFactorGroup {
+	groups := make(map[string]EmissionFactorGroup)
+	seen := make(map[string]bool)
+
+	for _, factor := range factors {
+		key := factor.ActivityType + "/" + factor.SubType
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		group := groups[factor.ActivityType]
+		group.ActivityType = factor.ActivityType
+		group.SubTypes = append(group.SubTypes, GroupedEmissionFactor{
+			SubType:   factor.SubType,
+			FactorCO2: factor.FactorCO2,
+			Unit:      factor.Unit,
+			Source:    factor.Source,
+			Location:  factor.Location,
+		})
+		groups[factor.ActivityType] = group
+	}
+
+	for activityType, group := range groups {
+		sort.SliceStable(group.SubTypes, func(i, j int) bool {
+			return group.SubTypes[i].FactorCO2 < group.SubTypes[j].FactorCO2
+		})
+
+		// Only factors with the same unit are comparable
+		lowest := make(map[string]float64)
+		for _, subType := range group.SubTypes {
+			if subType.FactorCO2 > 0 && (lowest[subType.Unit] == 0 || subType.FactorCO2 < lowest[subType.Unit]) {
+				lowest[subType.Unit] = subType.FactorCO2
+			}
+		}
+		for i := range group.SubTypes {
+			if base := lowest[group.SubTypes[i].Unit]; base > 0 {
+				group.SubTypes[i].RelativeToLowest = group.SubTypes[i].FactorCO2 / base
+			}
+		}
+
+		groups[activityType] = group
+	}
+
+	return groups
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/calculator/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// AllActivities is the override key that applies to every activity of a
+// calculation
+const AllActivities = "*"
+
+// RecalculateRequest represents a what-if recalculation request. Overrides
+// maps an activity ID, or "*" for every activity, to activity data fields
+// to replace, e.g. {"*": {"vehicle_type": "car_electric"}}. Under "*" a
+// field only replaces an activity's existing field of that name.
+type RecalculateRequest struct {
+	Overrides map[string]interface{} `json:"overrides" binding:"required"`
+}
+
+// RecalculationResponse compares a stored calculation with the same
+// activities recalculated with overrides. Nothing is saved.
+type RecalculationResponse struct {
+	CalculationID     uuid.UUID              `json:"calculation_id"`
+	OriginalCO2Kg     float64                `json:"original_co2_kg"`
+	RecalculatedCO2Kg float64                `json:"recalculated_co2_kg"`
+	DeltaCO2Kg        float64                `json:"delta_co2_kg"` // negative when the overrides emit less
+	Activities        []RecalculatedActivity `json:"activities"`
+}
+
+// RecalculatedActivity is one activity of a recalculation
+type RecalculatedActivity struct {
+	ActivityID    uuid.UUID      `json:"activity_id"`
+	OriginalCO2Kg float64        `json:"original_co2_kg"`
+	DeltaCO2Kg    float64        `json:"delta_co2_kg"`
+	Result        ActivityResult `json:"result"`
+}
+
+// Recalculate recomputes a user's stored calculation with activity data
+// overrides and returns the difference, without saving anything. Factors
+// are the ones in effect now, so the delta also reflects factor updates
+// made since the calculation.
+func (s *CalculatorService) Recalculate(ctx context.Context, calculationID uuid.UUID, userID string, overrides map[string]interface{}) (*RecalculationResponse, error) {
+	calculation, err := s.calculationRepo.GetByID(ctx, calculationID)
+	if err != nil {
+		return nil, err
+	}
+	if calculation.UserID != userID {
+		return nil, database.ErrNotFound
+	}
+
+	activityOverrides, err := parseOverrides(overrides, calculation.Activities)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RecalculationResponse{
+		CalculationID: calculationID,
+		OriginalCO2Kg: calculation.TotalCO2Kg,
+	}
+	for i, activity := range calculation.Activities {
+		data, err := overriddenActivityData(activity, activityOverrides)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := s.calculateActivity(ctx, ActivityDataRequest{ActivityType: activity.ActivityType, Data: data})
+		if err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				err = fmt.Errorf("%w: %w", ErrInvalidActivity, err)
+			}
+			return nil, fmt.Errorf("failed to recalculate activity %d: %w", i, err)
+		}
+
+		response.RecalculatedCO2Kg += result.CO2Kg
+		response.Activities = append(response.Activities, RecalculatedActivity{
+			ActivityID:    activity.ID,
+			OriginalCO2Kg: activity.CO2Kg,
+			DeltaCO2Kg:    result.CO2Kg - activity.CO2Kg,
+			Result:        *result,
+		})
+	}
+	response.DeltaCO2Kg = response.RecalculatedCO2Kg - response.OriginalCO2Kg
+
+	s.logger.LogInfo(ctx, "calculation recalculated",
+		logger.String("user_id", userID),
+		logger.String("calculation_id", calculationID.String()),
+		logger.Float64("delta_co2_kg", response.DeltaCO2Kg))
+
+	return response, nil
+}
+
+// parseOverrides checks that overrides are objects keyed by "*" or the ID of
+// one of the activities
+func parseOverrides(overrides map[string]interface{}, activities []models.Activity) (map[string]map[string]interface{}, error) {
+	known := make(map[string]bool, len(activities))
+	for _, activity := range activities {
+		known[activity.ID.String()] = true
+	}
+
+	parsed := make(map[string]map[string]interface{}, len(overrides))
+	for key, value := range overrides {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: override %q must be an object of activity data fields", ErrInvalidActivity, key)
+		}
+		if key != AllActivities {
+			id, err := uuid.Parse(key)
+			if err != nil || !known[id.String()] {
+				return nil, fmt.Errorf("%w: override %q is not an activity of the calculation", ErrInvalidActivity, key)
+			}
+			key = id.String()
+		}
+		parsed[key] = fields
+	}
+
+	return parsed, nil
+}
+
+// overriddenActivityData decodes a stored activity's data and applies the
+// overrides for every activity, then those for this activity
+func overriddenActivityData(activity models.Activity, overrides map[string]map[string]interface{}) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	if activity.ActivityData != "" {
+		if err := json.Unmarshal([]byte(activity.ActivityData), &data); err != nil {
+			return nil, fmt.Errorf("failed to decode data of activity %s: %w", activity.ID, err)
+		}
+	}
+
+	for field, value := range overrides[AllActivities] {
+		if _, ok := data[field]; ok {
+			data[field] = value
+		}
+	}
+	for field, value := range overrides[activity.ID.String()] {
+		data[field] = value
+	}
+
+	return data, nil
+}
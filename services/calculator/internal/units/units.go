@@ -0,0 +1,94 @@
+// Package units converts activity quantities between the units users report
+// them in and the units emission factors are expressed in.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupportedConversion is returned for an unknown unit or a pair of
+// units with no conversion between them
+var ErrUnsupportedConversion = errors.New("unsupported unit conversion")
+
+// Canonical unit names
+const (
+	Kilometer    = "km"
+	Mile         = "mi"
+	Meter        = "m"
+	Liter        = "L"
+	Gallon       = "gal" // US liquid gallon
+	CubicMeter   = "m3"
+	CubicFoot    = "ft3"
+	CCF          = "ccf" // hundred cubic feet, as US gas meters read
+	KilowattHour = "kWh"
+	Megajoule    = "MJ"
+	Therm        = "therm"
+)
+
+// aliases maps lower-cased unit spellings to canonical names
+var aliases = map[string]string{
+	"km": Kilometer, "kilometer": Kilometer, "kilometers": Kilometer, "kilometre": Kilometer, "kilometres": Kilometer,
+	"mi": Mile, "mile": Mile, "miles": Mile,
+	"m": Meter, "meter": Meter, "meters": Meter, "metre": Meter, "metres": Meter,
+	"l": Liter, "liter": Liter, "liters": Liter, "litre": Liter, "litres": Liter,
+	"gal": Gallon, "gallon": Gallon, "gallons": Gallon,
+	"m3": CubicMeter, "cubic_meter": CubicMeter, "cubic_meters": CubicMeter,
+	"ft3": CubicFoot, "cubic_foot": CubicFoot, "cubic_feet": CubicFoot,
+	"ccf":   CCF,
+	"kwh":   KilowattHour,
+	"mj":    Megajoule,
+	"therm": Therm, "therms": Therm,
+}
+
+type pair struct{ from, to string }
+
+// conversions lists how many of the second unit make one of the first.
+// Conversions the other way divide by the same number.
+var conversions = map[pair]float64{
+	{Mile, Kilometer}:  1.609344,
+	{Meter, Kilometer}: 0.001,
+
+	{Gallon, Liter}:         3.785411784,
+	{CubicMeter, Liter}:     1000,
+	{CubicFoot, CubicMeter}: 0.028316846592,
+	{CCF, CubicMeter}:       2.8316846592,
+
+	{Therm, KilowattHour}:     29.307107,
+	{Megajoule, KilowattHour}: 1 / 3.6,
+
+	// Therms are how natural gas is billed, so they also convert to the
+	// volume of gas at the EIA average heat content of 1,037 Btu per cubic foot
+	{Therm, CubicMeter}: 2.73065,
+}
+
+// Normalize returns the canonical name of a unit, ignoring case and
+// accepting plural and spelled-out names such as "miles"
+func Normalize(unit string) (string, bool) {
+	canonical, ok := aliases[strings.ToLower(strings.TrimSpace(unit))]
+	return canonical, ok
+}
+
+// Convert converts value from one unit to another
+func Convert(value float64, from, to string) (float64, error) {
+	fromUnit, ok := Normalize(from)
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown unit %q", ErrUnsupportedConversion, from)
+	}
+	toUnit, ok := Normalize(to)
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown unit %q", ErrUnsupportedConversion, to)
+	}
+
+	if fromUnit == toUnit {
+		return value, nil
+	}
+	if factor, ok := conversions[pair{fromUnit, toUnit}]; ok {
+		return value * factor, nil
+	}
+	if factor, ok := conversions[pair{toUnit, fromUnit}]; ok {
+		return value / factor, nil
+	}
+	return 0, fmt.Errorf("%w: %s to %s", ErrUnsupportedConversion, fromUnit, toUnit)
+}
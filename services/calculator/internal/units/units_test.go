@@ -0,0 +1,66 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		expected float64
+	}{
+		{100, "miles", "km", 160.9344},
+		{160.9344, "km", "mi", 100},
+		{10, "gallons", "L", 37.85411784},
+		{37.85411784, "litres", "gal", 10},
+		{2, "m3", "L", 2000},
+		{100, "ft3", "m3", 2.8316846592},
+		{1, "therms", "kWh", 29.307107},
+		{3.6, "MJ", "kWh", 1},
+		{10, "therm", "m3", 27.3065},
+		{42, "KM", "km", 42},
+	}
+
+	for _, tt := range tests {
+		converted, err := Convert(tt.value, tt.from, tt.to)
+		if err != nil {
+			t.Errorf("%s to %s: unexpected error %v", tt.from, tt.to, err)
+			continue
+		}
+		if math.Abs(converted-tt.expected) > 1e-9 {
+			t.Errorf("%v %s to %s: expected %v, got %v", tt.value, tt.from, tt.to, tt.expected, converted)
+		}
+	}
+}
+
+func TestConvert_Unsupported(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"miles", "L"},
+		{"kWh", "m3"},
+		{"therm", "L"},
+		{"furlongs", "km"},
+		{"km", ""},
+	} {
+		if _, err := Convert(1, pair[0], pair[1]); !errors.Is(err, ErrUnsupportedConversion) {
+			t.Errorf("%s to %s: expected ErrUnsupportedConversion, got %v", pair[0], pair[1], err)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for input, expected := range map[string]string{
+		"Miles":     Mile,
+		" gal ":     Gallon,
+		"Litres":    Liter,
+		"KWH":       KilowattHour,
+		"Therms":    Therm,
+		"kilometre": Kilometer,
+	} {
+		if unit, ok := Normalize(input); !ok || unit != expected {
+			t.Errorf("Expected %q to normalize to %q, got %q", input, expected, unit)
+		}
+	}
+}
@@ -2,12 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,8 +16,24 @@ import (
 	"github.com/sloweyyy/GreenLedger/services/certifier/internal/service"
 	"github.com/sloweyyy/GreenLedger/shared/config"
 	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/events"
+	"github.com/sloweyyy/GreenLedger/shared/featureflags"
 	sharedLogger "github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/sloweyyy/GreenLedger/shared/middleware"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
+	"github.com/sloweyyy/GreenLedger/shared/proto/usererasure"
+	"github.com/sloweyyy/GreenLedger/shared/proto/walletcredits"
+	sharedServer "github.com/sloweyyy/GreenLedger/shared/server"
+	"github.com/sloweyyy/GreenLedger/shared/version"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Build information, injected at build time via -ldflags "-X main.Version=..."
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
 )
 
 // @title GreenLedger Certificate Service API
@@ -56,13 +71,15 @@ func main() {
 	// Initialize logger
 	logger := sharedLogger.New(cfg.Server.LogLevel).WithService("certifier")
 
+	// Initialize metrics
+	metrics := monitoring.NewMetrics("certifier")
+
 	// Initialize database
 	db, err := database.NewPostgresDB(&cfg.Database, logger)
 	if err != nil {
 		logger.LogError(context.Background(), "failed to connect to database", err)
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer db.Close()
 
 	// Run database migrations
 	if err := db.Migrate(
@@ -71,6 +88,7 @@ func main() {
 		&models.CertificateTransfer{},
 		&models.CertificateTemplate{},
 		&models.CertificateProject{},
+		&events.DeadLetter{},
 	); err != nil {
 		logger.LogError(context.Background(), "failed to run migrations", err)
 		log.Fatalf("Failed to run migrations: %v", err)
@@ -79,29 +97,80 @@ func main() {
 	// Initialize repositories
 	certificateRepo := repository.NewCertificateRepository(db, logger)
 	projectRepo := repository.NewProjectRepository(db, logger)
+	templateRepo := repository.NewTemplateRepository(db, logger)
+
+	// Events this service's consumers give up on
+	deadLetters := events.NewDeadLetterQueue(db, logger)
+
+	// Certificates are paid for from the user's wallet
+	walletConn, err := grpc.NewClient(cfg.Certifier.WalletGRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logger.LogError(context.Background(), "failed to create wallet gRPC client", err)
+		log.Fatalf("Failed to create wallet gRPC client: %v", err)
+	}
+	walletClient := service.NewGRPCWalletClient(walletcredits.NewWalletCreditsClient(walletConn))
+
+	// Issued certificates are minted as NFTs in the background
+	var minter service.BlockchainMinter
+	switch cfg.Certifier.Minter {
+	case "ethereum":
+		minter, err = service.NewEthereumMinter(service.EthereumMinterConfig{
+			RPCURL:          cfg.Certifier.EthereumRPCURL,
+			Network:         cfg.Certifier.BlockchainNetwork,
+			ContractAddress: cfg.Certifier.MintContractAddress,
+			FromAddress:     cfg.Certifier.MintFromAddress,
+			IPFSAPIURL:      cfg.Certifier.IPFSAPIURL,
+		}, &http.Client{Timeout: 30 * time.Second})
+		if err != nil {
+			logger.LogError(context.Background(), "failed to configure certificate minter", err)
+			log.Fatalf("Failed to configure certificate minter: %v", err)
+		}
+	case "mock":
+		minter = service.NewMockBlockchainMinter()
+	}
+
+	// Initialize event publisher
+	var eventPublisher service.EventPublisher
+	if cfg.Features.Enabled(featureflags.Kafka) {
+		eventPublisher = service.NewKafkaEventPublisher(cfg.Kafka.Brokers, logger)
+	} else {
+		eventPublisher = service.NewMockEventPublisher(logger)
+	}
 
 	// Initialize services
 	certificateService := service.NewCertificateService(
 		certificateRepo,
 		projectRepo,
+		templateRepo,
+		walletClient,
+		minter,
+		eventPublisher,
+		cfg.Certifier.PublicBaseURL,
+		metrics,
 		logger,
 	)
 
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(cfg.Server.JWTSecret, logger)
+	authMiddleware := middleware.NewAuthMiddleware(cfg.Server.JWTSecret, cfg.Server.JWTIssuer, cfg.Server.JWTAudience, logger)
 
 	// Initialize handlers
 	certificateHandler := handler.NewCertificateHandler(certificateService, logger)
 
 	// Setup Gin router
-	if cfg.Server.Environment == "production" {
+	if cfg.Features.Enabled(featureflags.ReleaseMode) {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.LogError(context.Background(), "failed to set trusted proxies", err)
+		log.Fatalf("Failed to set trusted proxies: %v", err)
+	}
 	router.Use(gin.Recovery())
-	router.Use(middleware.RequestLogger(logger))
+	router.Use(middleware.RequestLogger(logger, cfg.Server.SlowRequestThreshold))
+	router.Use(middleware.Pagination(cfg.Pagination.DefaultLimit, cfg.Pagination.MaxLimit))
 	router.Use(middleware.CORS())
+	router.Use(middleware.MaxBodySize(cfg.Server.MaxBodyBytes))
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -115,13 +184,20 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "healthy",
 			"service": "certifier",
-			"version": "1.0.0",
+			"version": Version,
 		})
 	})
 
+	// Prometheus metrics endpoint
+	router.GET("/metrics", monitoring.MetricsHandler())
+
+	// Build info endpoint
+	router.GET("/version", version.Handler(version.New("certifier", Version, GitCommit, BuildTime)))
+
 	// API routes
 	v1 := router.Group("/api/v1")
 	certificateHandler.RegisterRoutes(v1, authMiddleware)
+	events.NewDeadLetterHandler(deadLetters, logger).RegisterRoutes(v1.Group("/certificates"), authMiddleware)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -132,32 +208,62 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	// Start server in a goroutine
-	go func() {
-		logger.LogInfo(context.Background(), "starting certificate service",
-			sharedLogger.Int("port", cfg.Server.Port),
-			sharedLogger.String("environment", cfg.Server.Environment))
+	// Cancelled on SIGINT/SIGTERM so background consumers stop before their
+	// readers, the event publisher and the database are closed
+	ctx, stop := sharedServer.NotifyContext(context.Background())
+	defer stop()
 
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.LogError(context.Background(), "failed to start server", err)
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
+	// Serve erasure checks to user-auth over gRPC
+	grpcServer := grpc.NewServer()
+	usererasure.RegisterUserErasureServer(grpcServer, handler.NewErasureServer(certificateService, logger))
+	grpcCloser, err := sharedServer.ServeGRPC(grpcServer, fmt.Sprintf(":%d", cfg.Server.GRPCPort), logger)
+	if err != nil {
+		logger.LogError(context.Background(), "failed to start gRPC server", err)
+		log.Fatalf("Failed to start gRPC server: %v", err)
+	}
 
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	closers := []io.Closer{grpcCloser}
 
-	logger.LogInfo(context.Background(), "shutting down certificate service")
+	// Start user event consumer for erasure requests
+	if cfg.Features.Enabled(featureflags.Kafka) {
+		userConsumer := events.NewUserEventConsumer(cfg.Kafka.Brokers, "certifier-service", deadLetters, logger)
+		closers = append(closers, userConsumer)
+
+		go func() {
+			err := userConsumer.Consume(ctx, func(ctx context.Context, event *events.UserDeletedEvent) error {
+				return certificateService.EraseUserData(ctx, event.UserID)
+			})
+			if err != nil && !errors.Is(err, context.Canceled) {
+				logger.LogError(context.Background(), "user event consumer error", err)
+			}
+		}()
+	}
+
+	if minter != nil {
+		mintWorker := service.NewCertificateMintWorker(certificateService, logger)
+		go mintWorker.Run(ctx, cfg.Certifier.MintInterval)
+	}
+
+	expirySweeper := service.NewCertificateExpirySweeper(certificateService, logger)
+	go expirySweeper.Run(ctx, cfg.Certifier.ExpiryInterval)
+
+	pendingReconciler := service.NewPendingCertificateReconciler(certificateService, logger)
+	go pendingReconciler.Run(ctx, cfg.Certifier.PendingInterval)
+
+	// Close the event publisher once the background workers have stopped
+	if closer, ok := eventPublisher.(io.Closer); ok {
+		closers = append(closers, closer)
+	}
+	closers = append(closers, walletConn, db)
 
-	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	logger.LogInfo(context.Background(), "starting certificate service",
+		sharedLogger.Int("port", cfg.Server.Port),
+		sharedLogger.Int("grpc_port", cfg.Server.GRPCPort),
+		sharedLogger.String("environment", cfg.Server.Environment),
+		sharedLogger.Any("features", cfg.Features.EnabledNames()))
 
-	if err := server.Shutdown(ctx); err != nil {
-		logger.LogError(context.Background(), "server forced to shutdown", err)
-		log.Fatalf("Server forced to shutdown: %v", err)
+	if err := sharedServer.RunWithGracefulShutdown(ctx, server, logger, closers...); err != nil {
+		log.Fatalf("certificate service stopped with error: %v", err)
 	}
 
 	logger.LogInfo(context.Background(), "certificate service stopped")
@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"mime"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
 	"github.com/sloweyyy/GreenLedger/services/certifier/internal/service"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/httperr"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/sloweyyy/GreenLedger/shared/middleware"
 )
@@ -14,6 +17,7 @@ import (
 // CertificateHandler handles HTTP requests for certificate operations
 type CertificateHandler struct {
 	certificateService *service.CertificateService
+	errMapper          *httperr.Mapper
 	logger             *logger.Logger
 }
 
@@ -21,7 +25,21 @@ type CertificateHandler struct {
 func NewCertificateHandler(certificateService *service.CertificateService, logger *logger.Logger) *CertificateHandler {
 	return &CertificateHandler{
 		certificateService: certificateService,
-		logger:             logger,
+		errMapper: httperr.New(logger,
+			httperr.Mapping{Err: service.ErrInvalidCertificateRequest, Status: http.StatusBadRequest, Message: "Invalid certificate request"},
+			httperr.Mapping{Err: service.ErrInvalidTransferRequest, Status: http.StatusBadRequest, Message: "Invalid transfer request"},
+			httperr.Mapping{Err: service.ErrInvalidVerificationReview, Status: http.StatusBadRequest, Message: "Invalid verification review"},
+			httperr.Mapping{Err: service.ErrInvalidQuoteRequest, Status: http.StatusBadRequest, Message: "Invalid quote request"},
+			httperr.Mapping{Err: service.ErrIdempotencyKeyReused, Status: http.StatusConflict, Message: "Idempotency key already used"},
+			httperr.Mapping{Err: service.ErrInsufficientWalletCredits, Status: http.StatusConflict, Message: "Not enough wallet credits"},
+			httperr.Mapping{Err: service.ErrInsufficientProjectCredits, Status: http.StatusConflict, Message: "Not enough project credits available"},
+			httperr.Mapping{Err: service.ErrVerificationPending, Status: http.StatusConflict, Message: "Certificate verification already pending"},
+			httperr.Mapping{Err: service.ErrNoPendingVerification, Status: http.StatusConflict, Message: "Certificate has no pending verification"},
+			httperr.Mapping{Err: service.ErrCertificateNotActive, Status: http.StatusConflict, Message: "Certificate is not active"},
+			httperr.Mapping{Err: service.ErrTransferNotPending, Status: http.StatusConflict, Message: "Transfer is not a pending sale offer"},
+			httperr.Mapping{Err: database.ErrNotFound, Status: http.StatusNotFound, Message: "Certificate not found"},
+		),
+		logger: logger,
 	}
 }
 
@@ -35,9 +53,15 @@ func (h *CertificateHandler) RegisterRoutes(router *gin.RouterGroup, authMiddlew
 		// Protected routes
 		certificates.Use(authMiddleware.RequireAuth())
 		certificates.POST("/", h.IssueCertificate)
+		certificates.POST("/quote", h.QuoteOffset)
 		certificates.GET("/", h.GetUserCertificates)
 		certificates.GET("/:id", h.GetCertificate)
 		certificates.POST("/:id/retire", h.RetireCertificate)
+		certificates.POST("/:id/transfer", h.TransferCertificate)
+		certificates.POST("/transfers/:transfer_id/accept", h.AcceptCertificateSale)
+		certificates.GET("/:id/pdf", h.DownloadCertificatePDF)
+		certificates.GET("/:id/qr", h.GetCertificateQRCode)
+		certificates.POST("/:id/verification", h.SubmitForVerification)
 
 		// Admin routes
 		admin := certificates.Group("/admin")
@@ -45,13 +69,16 @@ func (h *CertificateHandler) RegisterRoutes(router *gin.RouterGroup, authMiddlew
 		{
 			admin.GET("/all", h.GetAllCertificates)
 			admin.GET("/pending", h.GetPendingCertificates)
+			admin.GET("/:id", h.AdminGetCertificate)
+			admin.POST("/:id/retire", h.AdminRetireCertificate)
+			admin.POST("/:id/verification/review", h.ReviewVerification)
 		}
 	}
 }
 
 // IssueCertificate godoc
 // @Summary Issue a new certificate
-// @Description Issue a new carbon offset certificate
+// @Description Issue a new carbon offset certificate. Repeating a request with the same idempotency_key returns the originally issued certificate.
 // @Tags certificates
 // @Accept json
 // @Produce json
@@ -59,6 +86,7 @@ func (h *CertificateHandler) RegisterRoutes(router *gin.RouterGroup, authMiddlew
 // @Success 201 {object} service.CertificateResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
 // @Router /certificates [post]
@@ -84,16 +112,45 @@ func (h *CertificateHandler) IssueCertificate(c *gin.Context) {
 
 	response, err := h.certificateService.IssueCertificate(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to issue certificate", err,
+		h.errMapper.Respond(c, err, "Failed to issue certificate",
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to issue certificate",
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// QuoteOffset godoc
+// @Summary Quote the cost of offsetting CO2
+// @Description Price offsetting an amount of CO2 using the cheapest active projects with credits available. Nothing is issued or reserved.
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Param request body service.OffsetQuoteRequest true "Offset quote request"
+// @Success 200 {object} service.OffsetQuoteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/quote [post]
+func (h *CertificateHandler) QuoteOffset(c *gin.Context) {
+	var req service.OffsetQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
 			Details: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, response)
+	response, err := h.certificateService.QuoteOffset(c.Request.Context(), &req)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to quote offset")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // GetCertificate godoc
@@ -127,13 +184,9 @@ func (h *CertificateHandler) GetCertificate(c *gin.Context) {
 
 	response, err := h.certificateService.GetCertificate(c.Request.Context(), id, userID)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to get certificate", err,
+		h.errMapper.Respond(c, err, "Failed to get certificate",
 			logger.String("certificate_id", id.String()),
 			logger.String("user_id", userID))
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error:   "Certificate not found",
-			Details: err.Error(),
-		})
 		return
 	}
 
@@ -145,7 +198,7 @@ func (h *CertificateHandler) GetCertificate(c *gin.Context) {
 // @Description Get certificates for the authenticated user
 // @Tags certificates
 // @Produce json
-// @Param limit query int false "Limit" default(20)
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
 // @Param offset query int false "Offset" default(0)
 // @Success 200 {object} CertificateListResponse
 // @Failure 401 {object} ErrorResponse
@@ -159,25 +212,18 @@ func (h *CertificateHandler) GetUserCertificates(c *gin.Context) {
 		return
 	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, offset := middleware.GetPagination(c)
 
 	certificates, total, err := h.certificateService.GetUserCertificates(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to get user certificates", err,
+		h.errMapper.Respond(c, err, "Failed to get certificates",
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to get certificates",
-			Details: err.Error(),
-		})
 		return
 	}
 
 	response := CertificateListResponse{
 		Certificates: certificates,
-		Total:        total,
-		Limit:        limit,
-		Offset:       offset,
+		PageInfo:     middleware.NewPageInfo(total, limit, offset),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -185,11 +231,11 @@ func (h *CertificateHandler) GetUserCertificates(c *gin.Context) {
 
 // VerifyCertificate godoc
 // @Summary Verify certificate
-// @Description Verify a certificate by certificate number
+// @Description Verify a certificate by certificate number. The response includes the project standard and methodology, the verification history and a validity of active, retired, expired or invalid. The owner is never included.
 // @Tags certificates
 // @Produce json
 // @Param certificate_number path string true "Certificate Number"
-// @Success 200 {object} service.CertificateResponse
+// @Success 200 {object} service.CertificateVerificationResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /certificates/verify/{certificate_number} [get]
@@ -198,12 +244,8 @@ func (h *CertificateHandler) VerifyCertificate(c *gin.Context) {
 
 	response, err := h.certificateService.VerifyCertificate(c.Request.Context(), certificateNumber)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to verify certificate", err,
+		h.errMapper.Respond(c, err, "Certificate verification failed",
 			logger.String("certificate_number", certificateNumber))
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error:   "Certificate verification failed",
-			Details: err.Error(),
-		})
 		return
 	}
 
@@ -219,6 +261,7 @@ func (h *CertificateHandler) VerifyCertificate(c *gin.Context) {
 // @Success 200 {object} SuccessResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
 // @Router /certificates/{id}/retire [post]
@@ -241,43 +284,443 @@ func (h *CertificateHandler) RetireCertificate(c *gin.Context) {
 
 	err = h.certificateService.RetireCertificate(c.Request.Context(), id, userID)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to retire certificate", err,
+		h.errMapper.Respond(c, err, "Failed to retire certificate",
+			logger.String("certificate_id", id.String()),
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Certificate retired successfully",
+	})
+}
+
+// TransferCertificate godoc
+// @Summary Transfer certificate
+// @Description Give a certificate to another user, or offer it to them for a price in credits. A sale stays pending, and nothing is paid, until the buyer accepts it. Retired and expired certificates cannot be transferred.
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Param id path string true "Certificate ID"
+// @Param request body service.TransferCertificateRequest true "Transfer request"
+// @Success 200 {object} service.CertificateTransferResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/{id}/transfer [post]
+func (h *CertificateHandler) TransferCertificate(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid certificate ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req service.TransferCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.certificateService.TransferCertificate(c.Request.Context(), id, userID, req.ToUserID, req.TransferType, req.Price)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to transfer certificate",
+			logger.String("certificate_id", id.String()),
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AcceptCertificateSale godoc
+// @Summary Accept certificate sale
+// @Description Accept a certificate sale offered to the current user, paying the listed price from their wallet to the seller's
+// @Tags certificates
+// @Produce json
+// @Param transfer_id path string true "Transfer ID"
+// @Success 200 {object} service.CertificateTransferResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/transfers/{transfer_id}/accept [post]
+func (h *CertificateHandler) AcceptCertificateSale(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("transfer_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid transfer ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.certificateService.AcceptCertificateSale(c.Request.Context(), id, userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to accept certificate sale",
+			logger.String("transfer_id", id.String()),
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DownloadCertificatePDF godoc
+// @Summary Download certificate PDF
+// @Description Download a printable PDF of an issued or retired certificate, rendered from the active template for its type or the default one
+// @Tags certificates
+// @Produce application/pdf
+// @Param id path string true "Certificate ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/{id}/pdf [get]
+func (h *CertificateHandler) DownloadCertificatePDF(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid certificate ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	document, err := h.certificateService.RenderCertificatePDF(c.Request.Context(), id, userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to render certificate PDF",
+			logger.String("certificate_id", id.String()),
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.Filename}))
+	c.Data(http.StatusOK, "application/pdf", document.Content)
+}
+
+// GetCertificateQRCode godoc
+// @Summary Get certificate QR code
+// @Description Get a PNG QR code of the public verification URL of an issued or retired certificate, sized for print
+// @Tags certificates
+// @Produce image/png
+// @Param id path string true "Certificate ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/{id}/qr [get]
+func (h *CertificateHandler) GetCertificateQRCode(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid certificate ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	qrCode, err := h.certificateService.CertificateQRCode(c.Request.Context(), id, userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to render certificate QR code",
+			logger.String("certificate_id", id.String()),
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", qrCode)
+}
+
+// SubmitForVerification godoc
+// @Summary Submit certificate for verification
+// @Description Ask an admin to verify an issued certificate. Only one verification may be pending at a time.
+// @Tags certificates
+// @Produce json
+// @Param id path string true "Certificate ID"
+// @Success 201 {object} service.VerificationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/{id}/verification [post]
+func (h *CertificateHandler) SubmitForVerification(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid certificate ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.certificateService.SubmitForVerification(c.Request.Context(), id, userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to submit certificate for verification",
 			logger.String("certificate_id", id.String()),
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to retire certificate",
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// AdminGetCertificate godoc
+// @Summary Get any certificate (admin)
+// @Description Get a certificate by ID regardless of its owner, for support
+// @Tags admin
+// @Produce json
+// @Param id path string true "Certificate ID"
+// @Success 200 {object} service.CertificateResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/admin/{id} [get]
+func (h *CertificateHandler) AdminGetCertificate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid certificate ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.certificateService.GetAnyCertificate(c.Request.Context(), id)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get certificate",
+			logger.String("certificate_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AdminRetireCertificate godoc
+// @Summary Retire any certificate (admin)
+// @Description Retire a certificate on its owner's behalf (permanent action)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Certificate ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/admin/{id}/retire [post]
+func (h *CertificateHandler) AdminRetireCertificate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid certificate ID",
 			Details: err.Error(),
 		})
 		return
 	}
 
+	actorID, _ := middleware.GetUserID(c)
+	if err := h.certificateService.RetireAnyCertificate(c.Request.Context(), id, actorID); err != nil {
+		h.errMapper.Respond(c, err, "Failed to retire certificate",
+			logger.String("certificate_id", id.String()),
+			logger.String("actor_id", actorID))
+		return
+	}
+
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "Certificate retired successfully",
 	})
 }
 
-// Placeholder implementations for admin endpoints
+// ReviewVerification godoc
+// @Summary Review certificate verification (admin)
+// @Description Approve or reject a certificate's pending verification. Approved certificates become verified; rejected ones can no longer be retired or transferred. Rejections must include comments.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Certificate ID"
+// @Param request body service.ReviewVerificationRequest true "Verification review"
+// @Success 200 {object} service.VerificationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/admin/{id}/verification/review [post]
+func (h *CertificateHandler) ReviewVerification(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid certificate ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req service.ReviewVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	verifierID, _ := middleware.GetUserID(c)
+	response, err := h.certificateService.ReviewVerification(c.Request.Context(), id, verifierID, *req.Approve, req.Comments, string(req.Evidence))
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to review certificate verification",
+			logger.String("certificate_id", id.String()),
+			logger.String("verifier_id", verifierID))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAllCertificates godoc
+// @Summary List all certificates (admin)
+// @Description List certificates of every user, newest first, optionally filtered by status, certificate type and project type
+// @Tags admin
+// @Produce json
+// @Param status query string false "Certificate status"
+// @Param type query string false "Certificate type"
+// @Param project_type query string false "Project type"
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} CertificateListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/admin/all [get]
 func (h *CertificateHandler) GetAllCertificates(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Get all certificates - to be implemented"})
+	if status := c.Query("status"); status != "" {
+		h.listCertificatesByStatus(c, status)
+		return
+	}
+
+	limit, offset := middleware.GetPagination(c)
+
+	certificates, total, err := h.certificateService.ListAll(c.Request.Context(), certificateListFilter(c), limit, offset)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to list certificates")
+		return
+	}
+
+	c.JSON(http.StatusOK, CertificateListResponse{
+		Certificates: certificates,
+		PageInfo:     middleware.NewPageInfo(total, limit, offset),
+	})
 }
 
+// GetPendingCertificates godoc
+// @Summary List pending certificates (admin)
+// @Description List certificates awaiting issuance, newest first, optionally filtered by certificate type and project type
+// @Tags admin
+// @Produce json
+// @Param type query string false "Certificate type"
+// @Param project_type query string false "Project type"
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} CertificateListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/admin/pending [get]
 func (h *CertificateHandler) GetPendingCertificates(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Get pending certificates - to be implemented"})
+	h.listCertificatesByStatus(c, models.CertificateStatusPending)
 }
 
-// Response types
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Details string `json:"details,omitempty"`
+func (h *CertificateHandler) listCertificatesByStatus(c *gin.Context, status string) {
+	limit, offset := middleware.GetPagination(c)
+
+	certificates, total, err := h.certificateService.ListByStatus(c.Request.Context(), status, certificateListFilter(c), limit, offset)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to list certificates",
+			logger.String("status", status))
+		return
+	}
+
+	c.JSON(http.StatusOK, CertificateListResponse{
+		Certificates: certificates,
+		PageInfo:     middleware.NewPageInfo(total, limit, offset),
+	})
 }
 
+// certificateListFilter reads the optional admin listing filters from the
+// query string
+func certificateListFilter(c *gin.Context) service.CertificateListFilter {
+	return service.CertificateListFilter{
+		Type:        c.Query("type"),
+		ProjectType: c.Query("project_type"),
+	}
+}
+
+// Response types
+type ErrorResponse = httperr.ErrorResponse
+
 type SuccessResponse struct {
 	Message string `json:"message"`
 }
 
 type CertificateListResponse struct {
 	Certificates []*service.CertificateResponse `json:"certificates"`
-	Total        int64                          `json:"total"`
-	Limit        int                            `json:"limit"`
-	Offset       int                            `json:"offset"`
+	middleware.PageInfo
 }
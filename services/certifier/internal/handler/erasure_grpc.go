@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/service"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/proto/usererasure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErasureServer tells the user-auth service over gRPC whether a user still
+// holds active certificates that block erasing their account
+type ErasureServer struct {
+	usererasure.UnimplementedUserErasureServer
+
+	certificateService *service.CertificateService
+	logger             *logger.Logger
+}
+
+// NewErasureServer creates a new erasure server
+func NewErasureServer(certificateService *service.CertificateService, logger *logger.Logger) *ErasureServer {
+	return &ErasureServer{
+		certificateService: certificateService,
+		logger:             logger,
+	}
+}
+
+// CheckErasable fails with FailedPrecondition while the user holds active certificates
+func (s *ErasureServer) CheckErasable(ctx context.Context, req *usererasure.CheckErasableRequest) (*usererasure.CheckErasableResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	err := s.certificateService.CheckErasable(ctx, req.GetUserId())
+	switch {
+	case errors.Is(err, service.ErrActiveCertificates):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		s.logger.LogError(ctx, "failed to check certificate erasure", err,
+			logger.String("user_id", req.GetUserId()))
+		return nil, status.Error(codes.Internal, "failed to check certificates")
+	}
+
+	return &usererasure.CheckErasableResponse{}, nil
+}
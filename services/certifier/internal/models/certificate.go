@@ -11,7 +11,9 @@ import (
 // Certificate represents a carbon offset certificate
 type Certificate struct {
 	ID                uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID            string          `gorm:"not null;index" json:"user_id"`
+	UserID            string          `gorm:"not null;index;uniqueIndex:idx_certificates_user_idempotency_key" json:"user_id"`
+	IdempotencyKey    *string         `gorm:"uniqueIndex:idx_certificates_user_idempotency_key" json:"-"`
+	HolderName        string          `json:"holder_name"`
 	CertificateNumber string          `gorm:"uniqueIndex;not null" json:"certificate_number"`
 	Type              string          `gorm:"not null;index" json:"type"`
 	Status            string          `gorm:"not null;index;default:'pending'" json:"status"`
@@ -28,8 +30,11 @@ type Certificate struct {
 	BlockchainNetwork string          `json:"blockchain_network"`
 	TokenID           string          `gorm:"index" json:"token_id"`
 	MetadataURI       string          `json:"metadata_uri"`
+	MintStatus        string          `gorm:"index" json:"mint_status,omitempty"`
+	MintAttempts      int             `gorm:"not null;default:0" json:"-"`
+	MintError         string          `json:"mint_error,omitempty"`
 	IssuedAt          *time.Time      `json:"issued_at"`
-	ExpiresAt         *time.Time      `json:"expires_at"`
+	ExpiresAt         *time.Time      `gorm:"index" json:"expires_at"`
 	RetiredAt         *time.Time      `json:"retired_at"`
 	CreatedAt         time.Time       `json:"created_at"`
 	UpdatedAt         time.Time       `json:"updated_at"`
@@ -127,6 +132,10 @@ func (cv *CertificateVerification) BeforeCreate(tx *gorm.DB) error {
 	if cv.ID == uuid.Nil {
 		cv.ID = uuid.New()
 	}
+	// An empty string is not valid jsonb
+	if cv.Evidence == "" {
+		cv.Evidence = "{}"
+	}
 	return nil
 }
 
@@ -174,6 +183,19 @@ const (
 	CertificateStatusRetired   = "retired"
 	CertificateStatusCancelled = "cancelled"
 	CertificateStatusExpired   = "expired"
+	// CertificateStatusRejected marks a certificate whose verification was
+	// rejected. It can no longer be retired or transferred.
+	CertificateStatusRejected = "rejected"
+)
+
+// Mint statuses track a certificate's token from being queued for minting
+// until its mint transaction is confirmed or given up on. Certificates issued
+// while minting is disabled have no mint status.
+const (
+	MintStatusPending   = "pending"
+	MintStatusSubmitted = "submitted"
+	MintStatusMinted    = "minted"
+	MintStatusFailed    = "failed"
 )
 
 // Transfer types
@@ -190,6 +212,9 @@ const (
 	TransferStatusCompleted = "completed"
 	TransferStatusFailed    = "failed"
 	TransferStatusCancelled = "cancelled"
+	// TransferStatusRefundPending marks a paid sale that could not complete
+	// and whose payment still has to be returned to the buyer
+	TransferStatusRefundPending = "refund_pending"
 )
 
 // Verification statuses
@@ -2,15 +2,39 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
 	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/events"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrCertificateOwnerChanged is returned when transferring a certificate that
+// no longer belongs to the transfer's sender
+var ErrCertificateOwnerChanged = errors.New("certificate owner changed")
+
+// ErrProjectCreditsUnavailable is returned when a project is inactive or has
+// fewer available credits than a certificate uses
+var ErrProjectCreditsUnavailable = errors.New("project credits unavailable")
+
+// ErrVerificationNotPending is returned when reviewing a verification that
+// has already been reviewed
+var ErrVerificationNotPending = errors.New("verification not pending")
+
+// ErrTransferNotPending is returned when completing or failing a sale offer
+// that was already accepted or failed
+var ErrTransferNotPending = errors.New("transfer not pending")
+
+// ErrCertificateStatusChanged is returned when a certificate's status changed
+// before a verification review or expiry is saved
+var ErrCertificateStatusChanged = errors.New("certificate status changed")
+
 // CertificateRepository handles certificate data operations
 type CertificateRepository struct {
 	db     *database.PostgresDB
@@ -40,6 +64,83 @@ func (r *CertificateRepository) Create(ctx context.Context, certificate *models.
 	return nil
 }
 
+// IssueWithProjectUpdate creates a certificate and takes its credits from the
+// project's available credits in one database transaction. The project row is
+// locked first, so concurrent issues cannot take more credits than it has.
+// Returns ErrProjectCreditsUnavailable if the project cannot cover them.
+func (r *CertificateRepository) IssueWithProjectUpdate(ctx context.Context, certificate *models.Certificate, projectID uuid.UUID) error {
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var project models.CertificateProject
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&project, "id = ?", projectID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return database.ErrNotFound
+			}
+			return fmt.Errorf("failed to lock project: %w", err)
+		}
+
+		if !project.CanIssueCredits(certificate.CreditsUsed) {
+			return ErrProjectCreditsUnavailable
+		}
+
+		if err := tx.Create(certificate).Error; err != nil {
+			return fmt.Errorf("failed to create certificate: %w", err)
+		}
+
+		if err := tx.Model(&project).
+			Update("available_credits", project.AvailableCredits.Sub(certificate.CreditsUsed)).Error; err != nil {
+			return fmt.Errorf("failed to update available credits: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, ErrProjectCreditsUnavailable) && !errors.Is(err, database.ErrNotFound) {
+			r.logger.LogError(ctx, "failed to issue certificate", err,
+				logger.String("user_id", certificate.UserID),
+				logger.String("project_id", projectID.String()))
+		}
+		return err
+	}
+
+	r.logger.LogInfo(ctx, "certificate created",
+		logger.String("certificate_id", certificate.ID.String()),
+		logger.String("user_id", certificate.UserID),
+		logger.String("project_id", projectID.String()))
+
+	return nil
+}
+
+// DeleteWithProjectRestore deletes a certificate created by
+// IssueWithProjectUpdate and returns its credits to the project in one
+// database transaction
+func (r *CertificateRepository) DeleteWithProjectRestore(ctx context.Context, certificate *models.Certificate, projectID uuid.UUID) error {
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Certificate{}, "id = ?", certificate.ID).Error; err != nil {
+			return fmt.Errorf("failed to delete certificate: %w", err)
+		}
+
+		if err := tx.Model(&models.CertificateProject{}).
+			Where("id = ?", projectID).
+			Update("available_credits", gorm.Expr("available_credits + ?", certificate.CreditsUsed)).Error; err != nil {
+			return fmt.Errorf("failed to restore available credits: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		r.logger.LogError(ctx, "failed to delete certificate", err,
+			logger.String("certificate_id", certificate.ID.String()))
+		return err
+	}
+
+	r.logger.LogInfo(ctx, "certificate deleted",
+		logger.String("certificate_id", certificate.ID.String()),
+		logger.String("project_id", projectID.String()))
+
+	return nil
+}
+
 // GetByID retrieves a certificate by ID
 func (r *CertificateRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Certificate, error) {
 	var certificate models.Certificate
@@ -48,7 +149,7 @@ func (r *CertificateRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 		Preload("Transfers").
 		First(&certificate, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("certificate not found")
+			return nil, database.ErrNotFound
 		}
 		r.logger.LogError(ctx, "failed to get certificate", err,
 			logger.String("certificate_id", id.String()))
@@ -87,6 +188,22 @@ func (r *CertificateRepository) GetByUserID(ctx context.Context, userID string,
 	return certificates, total, nil
 }
 
+// GetByIdempotencyKey retrieves the certificate a user issued with the given idempotency key
+func (r *CertificateRepository) GetByIdempotencyKey(ctx context.Context, userID, idempotencyKey string) (*models.Certificate, error) {
+	var certificate models.Certificate
+	if err := r.db.WithContext(ctx).
+		First(&certificate, "user_id = ? AND idempotency_key = ?", userID, idempotencyKey).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		r.logger.LogError(ctx, "failed to get certificate by idempotency key", err,
+			logger.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get certificate: %w", err)
+	}
+
+	return &certificate, nil
+}
+
 // GetByCertificateNumber retrieves a certificate by certificate number
 func (r *CertificateRepository) GetByCertificateNumber(ctx context.Context, certificateNumber string) (*models.Certificate, error) {
 	var certificate models.Certificate
@@ -95,7 +212,7 @@ func (r *CertificateRepository) GetByCertificateNumber(ctx context.Context, cert
 		Preload("Transfers").
 		First(&certificate, "certificate_number = ?", certificateNumber).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("certificate not found")
+			return nil, database.ErrNotFound
 		}
 		r.logger.LogError(ctx, "failed to get certificate by number", err,
 			logger.String("certificate_number", certificateNumber))
@@ -133,29 +250,186 @@ func (r *CertificateRepository) Delete(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
-// GetByStatus retrieves certificates by status
+// CountByUserIDAndStatus counts a user's certificates in any of the given statuses
+func (r *CertificateRepository) CountByUserIDAndStatus(ctx context.Context, userID string, statuses []string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Certificate{}).
+		Where("user_id = ? AND status IN ?", userID, statuses).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count certificates: %w", err)
+	}
+
+	return count, nil
+}
+
+// AnonymizeUser replaces a user's id on their certificates and transfers.
+// Certificates are registry records, so they are kept rather than deleted.
+func (r *CertificateRepository) AnonymizeUser(ctx context.Context, userID string) error {
+	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		// The holder name printed on certificates is personal data too
+		if err := tx.Model(&models.Certificate{}).
+			Where("user_id = ?", userID).
+			Updates(map[string]interface{}{"user_id": events.ErasedUserID, "holder_name": ""}).Error; err != nil {
+			r.logger.LogError(ctx, "failed to anonymize certificates", err,
+				logger.String("user_id", userID))
+			return fmt.Errorf("failed to anonymize certificates: %w", err)
+		}
+
+		updates := []struct {
+			model  interface{}
+			column string
+		}{
+			{&models.CertificateTransfer{}, "from_user_id"},
+			{&models.CertificateTransfer{}, "to_user_id"},
+		}
+		for _, u := range updates {
+			if err := tx.Model(u.model).
+				Where(u.column+" = ?", userID).
+				Update(u.column, events.ErasedUserID).Error; err != nil {
+				r.logger.LogError(ctx, "failed to anonymize certificates", err,
+					logger.String("user_id", userID))
+				return fmt.Errorf("failed to anonymize certificates: %w", err)
+			}
+		}
+
+		r.logger.LogInfo(ctx, "certificates anonymized",
+			logger.String("user_id", userID))
+
+		return nil
+	})
+}
+
+// UpdateMint saves a certificate's mint status and blockchain fields,
+// leaving the rest of the row to concurrent updates
+func (r *CertificateRepository) UpdateMint(ctx context.Context, certificate *models.Certificate) error {
+	if err := r.db.WithContext(ctx).Model(certificate).
+		Select("mint_status", "mint_attempts", "mint_error", "blockchain_network", "blockchain_tx_hash", "metadata_uri", "token_id").
+		Updates(certificate).Error; err != nil {
+		r.logger.LogError(ctx, "failed to update certificate mint", err,
+			logger.String("certificate_id", certificate.ID.String()))
+		return fmt.Errorf("failed to update certificate mint: %w", err)
+	}
+
+	return nil
+}
+
+// GetByMintStatus retrieves up to limit certificates in a mint status, the
+// least recently updated first so certificates that keep failing do not
+// starve the rest
+func (r *CertificateRepository) GetByMintStatus(ctx context.Context, mintStatus string, limit int) ([]*models.Certificate, error) {
+	var certificates []*models.Certificate
+	if err := r.db.WithContext(ctx).
+		Where("mint_status = ?", mintStatus).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&certificates).Error; err != nil {
+		r.logger.LogError(ctx, "failed to get certificates by mint status", err,
+			logger.String("mint_status", mintStatus))
+		return nil, fmt.Errorf("failed to get certificates: %w", err)
+	}
+
+	return certificates, nil
+}
+
+// GetExpired retrieves up to limit issued or verified certificates whose
+// expiry is before now, the longest expired first
+func (r *CertificateRepository) GetExpired(ctx context.Context, now time.Time, limit int) ([]*models.Certificate, error) {
+	var certificates []*models.Certificate
+	if err := r.db.WithContext(ctx).
+		Where("status IN ? AND expires_at < ?",
+			[]string{models.CertificateStatusIssued, models.CertificateStatusVerified}, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&certificates).Error; err != nil {
+		r.logger.LogError(ctx, "failed to get expired certificates", err)
+		return nil, fmt.Errorf("failed to get certificates: %w", err)
+	}
+
+	return certificates, nil
+}
+
+// GetStalePending retrieves up to limit pending certificates created before
+// createdBefore, oldest first
+func (r *CertificateRepository) GetStalePending(ctx context.Context, createdBefore time.Time, limit int) ([]*models.Certificate, error) {
+	var certificates []*models.Certificate
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND created_at < ?", models.CertificateStatusPending, createdBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&certificates).Error; err != nil {
+		r.logger.LogError(ctx, "failed to get stale pending certificates", err)
+		return nil, fmt.Errorf("failed to get certificates: %w", err)
+	}
+
+	return certificates, nil
+}
+
+// Expire moves an issued or verified certificate to expired. It returns
+// ErrCertificateStatusChanged if the certificate is no longer issued or
+// verified, e.g. because it was retired or another sweep expired it first.
+func (r *CertificateRepository) Expire(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&models.Certificate{}).
+		Where("id = ? AND status IN ?", id,
+			[]string{models.CertificateStatusIssued, models.CertificateStatusVerified}).
+		Update("status", models.CertificateStatusExpired)
+	if result.Error != nil {
+		r.logger.LogError(ctx, "failed to expire certificate", result.Error,
+			logger.String("certificate_id", id.String()))
+		return fmt.Errorf("failed to expire certificate: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrCertificateStatusChanged
+	}
+
+	return nil
+}
+
+// CertificateFilter selects certificates to list. Empty fields match any
+// value.
+type CertificateFilter struct {
+	Status      string
+	Type        string
+	ProjectType string
+}
+
+// GetByStatus retrieves certificates by status with pagination
 func (r *CertificateRepository) GetByStatus(ctx context.Context, status string, limit, offset int) ([]*models.Certificate, int64, error) {
+	return r.List(ctx, CertificateFilter{Status: status}, limit, offset)
+}
+
+// List retrieves certificates matching filter, newest first, with pagination
+func (r *CertificateRepository) List(ctx context.Context, filter CertificateFilter, limit, offset int) ([]*models.Certificate, int64, error) {
 	var certificates []*models.Certificate
 	var total int64
 
+	query := r.db.WithContext(ctx).Model(&models.Certificate{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.ProjectType != "" {
+		query = query.Where("project_type = ?", filter.ProjectType)
+	}
+
 	// Get total count
-	if err := r.db.WithContext(ctx).Model(&models.Certificate{}).
-		Where("status = ?", status).
-		Count(&total).Error; err != nil {
-		r.logger.LogError(ctx, "failed to count certificates by status", err,
-			logger.String("status", status))
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		r.logger.LogError(ctx, "failed to count certificates", err,
+			logger.String("status", filter.Status))
 		return nil, 0, fmt.Errorf("failed to count certificates: %w", err)
 	}
 
 	// Get certificates with pagination
-	if err := r.db.WithContext(ctx).
-		Where("status = ?", status).
+	if err := query.
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
 		Find(&certificates).Error; err != nil {
-		r.logger.LogError(ctx, "failed to get certificates by status", err,
-			logger.String("status", status))
+		r.logger.LogError(ctx, "failed to list certificates", err,
+			logger.String("status", filter.Status))
 		return nil, 0, fmt.Errorf("failed to get certificates: %w", err)
 	}
 
@@ -177,6 +451,57 @@ func (r *CertificateRepository) CreateVerification(ctx context.Context, verifica
 	return nil
 }
 
+// ReviewVerification saves the outcome of a pending verification and moves
+// its certificate to certificateStatus in one database transaction. Nothing
+// is saved if the verification was already reviewed or the certificate is
+// no longer issued.
+func (r *CertificateRepository) ReviewVerification(ctx context.Context, verification *models.CertificateVerification, certificateStatus string) error {
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		result := tx.Model(&models.CertificateVerification{}).
+			Where("id = ? AND status = ?", verification.ID, models.VerificationStatusPending).
+			Updates(map[string]interface{}{
+				"verifier_id":   verification.VerifierID,
+				"verifier_name": verification.VerifierName,
+				"status":        verification.Status,
+				"comments":      verification.Comments,
+				"evidence":      verification.Evidence,
+				"verified_at":   verification.VerifiedAt,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update verification: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrVerificationNotPending
+		}
+
+		result = tx.Model(&models.Certificate{}).
+			Where("id = ? AND status = ?", verification.CertificateID, models.CertificateStatusIssued).
+			Update("status", certificateStatus)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update certificate status: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrCertificateStatusChanged
+		}
+
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, ErrVerificationNotPending) && !errors.Is(err, ErrCertificateStatusChanged) {
+			r.logger.LogError(ctx, "failed to review verification", err,
+				logger.String("verification_id", verification.ID.String()))
+		}
+		return err
+	}
+
+	r.logger.LogInfo(ctx, "verification reviewed",
+		logger.String("verification_id", verification.ID.String()),
+		logger.String("certificate_id", verification.CertificateID.String()),
+		logger.String("status", verification.Status))
+
+	return nil
+}
+
 // CreateTransfer creates a certificate transfer
 func (r *CertificateRepository) CreateTransfer(ctx context.Context, transfer *models.CertificateTransfer) error {
 	if err := r.db.WithContext(ctx).Create(transfer).Error; err != nil {
@@ -192,6 +517,146 @@ func (r *CertificateRepository) CreateTransfer(ctx context.Context, transfer *mo
 	return nil
 }
 
+// TransferOwnership gives the certificate to the transfer's recipient and
+// records the transfer in one database transaction, clearing the previous
+// holder's name. The certificate is only reassigned while the sender still
+// owns it; otherwise nothing is saved and ErrCertificateOwnerChanged is
+// returned.
+func (r *CertificateRepository) TransferOwnership(ctx context.Context, transfer *models.CertificateTransfer) error {
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		result := tx.Model(&models.Certificate{}).
+			Where("id = ? AND user_id = ?", transfer.CertificateID, transfer.FromUserID).
+			Updates(map[string]interface{}{"user_id": transfer.ToUserID, "holder_name": ""})
+		if result.Error != nil {
+			return fmt.Errorf("failed to reassign certificate: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrCertificateOwnerChanged
+		}
+
+		if err := tx.Create(transfer).Error; err != nil {
+			return fmt.Errorf("failed to create transfer: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, ErrCertificateOwnerChanged) {
+			r.logger.LogError(ctx, "failed to transfer certificate", err,
+				logger.String("certificate_id", transfer.CertificateID.String()))
+		}
+		return err
+	}
+
+	r.logger.LogInfo(ctx, "certificate transferred",
+		logger.String("transfer_id", transfer.ID.String()),
+		logger.String("certificate_id", transfer.CertificateID.String()),
+		logger.String("to_user_id", transfer.ToUserID))
+
+	return nil
+}
+
+// GetTransfer retrieves a certificate transfer by ID
+func (r *CertificateRepository) GetTransfer(ctx context.Context, id uuid.UUID) (*models.CertificateTransfer, error) {
+	var transfer models.CertificateTransfer
+	if err := r.db.WithContext(ctx).First(&transfer, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		r.logger.LogError(ctx, "failed to get transfer", err,
+			logger.String("transfer_id", id.String()))
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	return &transfer, nil
+}
+
+// CompleteSale completes a pending sale offer and gives the certificate to
+// the buyer in one database transaction, clearing the seller's holder name.
+// It returns ErrTransferNotPending if the offer was already accepted or
+// failed, and ErrCertificateOwnerChanged if the seller no longer owns the
+// certificate; either way nothing is saved.
+func (r *CertificateRepository) CompleteSale(ctx context.Context, transfer *models.CertificateTransfer) error {
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		result := tx.Model(&models.CertificateTransfer{}).
+			Where("id = ? AND status = ?", transfer.ID, models.TransferStatusPending).
+			Updates(map[string]interface{}{
+				"status":         transfer.Status,
+				"transferred_at": transfer.TransferredAt,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update transfer: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrTransferNotPending
+		}
+
+		result = tx.Model(&models.Certificate{}).
+			Where("id = ? AND user_id = ?", transfer.CertificateID, transfer.FromUserID).
+			Updates(map[string]interface{}{"user_id": transfer.ToUserID, "holder_name": ""})
+		if result.Error != nil {
+			return fmt.Errorf("failed to reassign certificate: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrCertificateOwnerChanged
+		}
+
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, ErrTransferNotPending) && !errors.Is(err, ErrCertificateOwnerChanged) {
+			r.logger.LogError(ctx, "failed to complete certificate sale", err,
+				logger.String("transfer_id", transfer.ID.String()))
+		}
+		return err
+	}
+
+	r.logger.LogInfo(ctx, "certificate sold",
+		logger.String("transfer_id", transfer.ID.String()),
+		logger.String("certificate_id", transfer.CertificateID.String()),
+		logger.String("to_user_id", transfer.ToUserID))
+
+	return nil
+}
+
+// FailTransfer marks a pending or refund pending sale offer failed. It
+// returns ErrTransferNotPending if the offer was already accepted or failed.
+func (r *CertificateRepository) FailTransfer(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&models.CertificateTransfer{}).
+		Where("id = ? AND status IN ?", id,
+			[]string{models.TransferStatusPending, models.TransferStatusRefundPending}).
+		Update("status", models.TransferStatusFailed)
+	if result.Error != nil {
+		r.logger.LogError(ctx, "failed to fail transfer", result.Error,
+			logger.String("transfer_id", id.String()))
+		return fmt.Errorf("failed to update transfer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrTransferNotPending
+	}
+
+	return nil
+}
+
+// MarkTransferRefundPending marks a pending sale offer whose payment must
+// still be refunded. It returns ErrTransferNotPending if the offer is no
+// longer pending.
+func (r *CertificateRepository) MarkTransferRefundPending(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&models.CertificateTransfer{}).
+		Where("id = ? AND status = ?", id, models.TransferStatusPending).
+		Update("status", models.TransferStatusRefundPending)
+	if result.Error != nil {
+		r.logger.LogError(ctx, "failed to mark transfer refund pending", result.Error,
+			logger.String("transfer_id", id.String()))
+		return fmt.Errorf("failed to update transfer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrTransferNotPending
+	}
+
+	return nil
+}
+
 // GetTransfersByUserID retrieves transfers for a user
 func (r *CertificateRepository) GetTransfersByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.CertificateTransfer, int64, error) {
 	var transfers []*models.CertificateTransfer
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+)
+
+// CertificateRepositoryInterface defines the interface for certificate repository
+type CertificateRepositoryInterface interface {
+	Create(ctx context.Context, certificate *models.Certificate) error
+	IssueWithProjectUpdate(ctx context.Context, certificate *models.Certificate, projectID uuid.UUID) error
+	DeleteWithProjectRestore(ctx context.Context, certificate *models.Certificate, projectID uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Certificate, error)
+	GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.Certificate, int64, error)
+	GetByIdempotencyKey(ctx context.Context, userID, idempotencyKey string) (*models.Certificate, error)
+	GetByCertificateNumber(ctx context.Context, certificateNumber string) (*models.Certificate, error)
+	List(ctx context.Context, filter CertificateFilter, limit, offset int) ([]*models.Certificate, int64, error)
+	GetByMintStatus(ctx context.Context, mintStatus string, limit int) ([]*models.Certificate, error)
+	UpdateMint(ctx context.Context, certificate *models.Certificate) error
+	GetExpired(ctx context.Context, now time.Time, limit int) ([]*models.Certificate, error)
+	Expire(ctx context.Context, id uuid.UUID) error
+	GetStalePending(ctx context.Context, createdBefore time.Time, limit int) ([]*models.Certificate, error)
+	Update(ctx context.Context, certificate *models.Certificate) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CountByUserIDAndStatus(ctx context.Context, userID string, statuses []string) (int64, error)
+	AnonymizeUser(ctx context.Context, userID string) error
+	CreateVerification(ctx context.Context, verification *models.CertificateVerification) error
+	ReviewVerification(ctx context.Context, verification *models.CertificateVerification, certificateStatus string) error
+	CreateTransfer(ctx context.Context, transfer *models.CertificateTransfer) error
+	TransferOwnership(ctx context.Context, transfer *models.CertificateTransfer) error
+	GetTransfer(ctx context.Context, id uuid.UUID) (*models.CertificateTransfer, error)
+	CompleteSale(ctx context.Context, transfer *models.CertificateTransfer) error
+	FailTransfer(ctx context.Context, id uuid.UUID) error
+	MarkTransferRefundPending(ctx context.Context, id uuid.UUID) error
+}
+
+// ProjectRepositoryInterface defines the interface for project repository
+type ProjectRepositoryInterface interface {
+	GetByName(ctx context.Context, name string) (*models.CertificateProject, error)
+	GetAvailable(ctx context.Context, currency string) ([]*models.CertificateProject, error)
+}
+
+// TemplateRepositoryInterface defines the interface for certificate template repository
+type TemplateRepositoryInterface interface {
+	GetActiveByType(ctx context.Context, certificateType string) (*models.CertificateTemplate, error)
+}
+
+var _ CertificateRepositoryInterface = (*CertificateRepository)(nil)
+var _ ProjectRepositoryInterface = (*ProjectRepository)(nil)
+var _ TemplateRepositoryInterface = (*TemplateRepository)(nil)
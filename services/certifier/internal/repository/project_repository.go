@@ -47,7 +47,7 @@ func (r *ProjectRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 		Preload("Certificates").
 		First(&project, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("project not found")
+			return nil, database.ErrNotFound
 		}
 		r.logger.LogError(ctx, "failed to get project", err,
 			logger.String("project_id", id.String()))
@@ -63,7 +63,7 @@ func (r *ProjectRepository) GetByName(ctx context.Context, name string) (*models
 	if err := r.db.WithContext(ctx).
 		First(&project, "name = ?", name).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("project not found")
+			return nil, database.ErrNotFound
 		}
 		r.logger.LogError(ctx, "failed to get project by name", err,
 			logger.String("project_name", name))
@@ -125,6 +125,22 @@ func (r *ProjectRepository) GetActive(ctx context.Context, limit, offset int) ([
 	return projects, total, nil
 }
 
+// GetAvailable retrieves active projects priced in currency that still have
+// credits available, cheapest first
+func (r *ProjectRepository) GetAvailable(ctx context.Context, currency string) ([]*models.CertificateProject, error) {
+	var projects []*models.CertificateProject
+	if err := r.db.WithContext(ctx).
+		Where("is_active = ? AND currency = ? AND available_credits > 0", true, currency).
+		Order("price_per_credit ASC").
+		Find(&projects).Error; err != nil {
+		r.logger.LogError(ctx, "failed to get available projects", err,
+			logger.String("currency", currency))
+		return nil, fmt.Errorf("failed to get available projects: %w", err)
+	}
+
+	return projects, nil
+}
+
 // GetByType retrieves projects by type with pagination
 func (r *ProjectRepository) GetByType(ctx context.Context, projectType string, limit, offset int) ([]*models.CertificateProject, int64, error) {
 	var projects []*models.CertificateProject
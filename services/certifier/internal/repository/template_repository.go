@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm"
+)
+
+// TemplateRepository handles certificate template data operations
+type TemplateRepository struct {
+	db     *database.PostgresDB
+	logger *logger.Logger
+}
+
+// NewTemplateRepository creates a new template repository
+func NewTemplateRepository(db *database.PostgresDB, logger *logger.Logger) *TemplateRepository {
+	return &TemplateRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetActiveByType retrieves the most recently updated active template for a
+// certificate type
+func (r *TemplateRepository) GetActiveByType(ctx context.Context, certificateType string) (*models.CertificateTemplate, error) {
+	var template models.CertificateTemplate
+	if err := r.db.WithContext(ctx).
+		Where("type = ? AND is_active = ?", certificateType, true).
+		Order("updated_at DESC").
+		First(&template).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		r.logger.LogError(ctx, "failed to get certificate template", err,
+			logger.String("certificate_type", certificateType))
+		return nil, fmt.Errorf("failed to get certificate template: %w", err)
+	}
+
+	return &template, nil
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// Minting processes at most this many certificates per status per run
+const mintBatchSize = 50
+
+// maxMintAttempts is how many times submitting a certificate's mint may fail
+// before the certificate is marked failed
+const maxMintAttempts = 5
+
+// BlockchainMinter mints certificates as NFTs on a blockchain
+type BlockchainMinter interface {
+	// Network names the chain tokens are minted on
+	Network() string
+	// Mint publishes the certificate's metadata and submits a transaction
+	// minting its token. It returns once the transaction is sent, without
+	// waiting for it to be mined.
+	Mint(ctx context.Context, certificate *models.Certificate, metadata *models.CertificateMetadata) (*MintSubmission, error)
+	// MintReceipt reports the outcome of a submitted mint transaction
+	MintReceipt(ctx context.Context, txHash string) (*MintReceipt, error)
+}
+
+// MintSubmission is a sent mint transaction
+type MintSubmission struct {
+	TxHash      string
+	MetadataURI string
+}
+
+// Mint transaction states reported by MintReceipt
+const (
+	MintTxPending   = "pending"
+	MintTxConfirmed = "confirmed"
+	MintTxFailed    = "failed"
+)
+
+// MintReceipt is the outcome of a mint transaction; TokenID is set once it
+// is confirmed
+type MintReceipt struct {
+	Status  string
+	TokenID string
+}
+
+// MockBlockchainMinter mints tokens in memory, confirming every mint on its
+// first receipt check. It stands in for a chain in development and tests.
+type MockBlockchainMinter struct {
+	mu          sync.Mutex
+	nextTokenID int
+	tokens      map[string]string // token ID by tx hash
+}
+
+// NewMockBlockchainMinter creates an in-memory minter
+func NewMockBlockchainMinter() *MockBlockchainMinter {
+	return &MockBlockchainMinter{tokens: make(map[string]string)}
+}
+
+// Network names the mock chain
+func (m *MockBlockchainMinter) Network() string {
+	return "mock"
+}
+
+// Mint records a token for the certificate under a fake transaction hash
+func (m *MockBlockchainMinter) Mint(ctx context.Context, certificate *models.Certificate, metadata *models.CertificateMetadata) (*MintSubmission, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hash := sha256.Sum256([]byte(certificate.ID.String()))
+	txHash := "0x" + hex.EncodeToString(hash[:])
+	if _, exists := m.tokens[txHash]; !exists {
+		m.nextTokenID++
+		m.tokens[txHash] = strconv.Itoa(m.nextTokenID)
+	}
+
+	return &MintSubmission{
+		TxHash:      txHash,
+		MetadataURI: fmt.Sprintf("mock://certificates/%s.json", certificate.ID),
+	}, nil
+}
+
+// MintReceipt confirms transactions sent through Mint
+func (m *MockBlockchainMinter) MintReceipt(ctx context.Context, txHash string) (*MintReceipt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tokenID, exists := m.tokens[txHash]
+	if !exists {
+		return &MintReceipt{Status: MintTxFailed}, nil
+	}
+	return &MintReceipt{Status: MintTxConfirmed, TokenID: tokenID}, nil
+}
+
+// SubmitPendingMints submits the mint transactions of certificates queued
+// for minting and returns how many were submitted. A certificate whose
+// submission fails stays queued until it has failed maxMintAttempts times.
+func (s *CertificateService) SubmitPendingMints(ctx context.Context) (int, error) {
+	certificates, err := s.certificateRepo.GetByMintStatus(ctx, models.MintStatusPending, mintBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	submitted := 0
+	for _, certificate := range certificates {
+		submission, mintErr := s.minter.Mint(ctx, certificate, certificateMetadata(certificate))
+		if mintErr != nil {
+			if ctx.Err() != nil {
+				return submitted, ctx.Err()
+			}
+			certificate.MintAttempts++
+			certificate.MintError = mintErr.Error()
+			if certificate.MintAttempts >= maxMintAttempts {
+				certificate.MintStatus = models.MintStatusFailed
+			}
+			s.logger.LogWarn(ctx, "failed to submit certificate mint",
+				logger.String("certificate_id", certificate.ID.String()),
+				logger.Int("attempts", certificate.MintAttempts),
+				logger.String("error", mintErr.Error()))
+		} else {
+			certificate.MintStatus = models.MintStatusSubmitted
+			certificate.MintError = ""
+			certificate.BlockchainNetwork = s.minter.Network()
+			certificate.BlockchainTxHash = submission.TxHash
+			certificate.MetadataURI = submission.MetadataURI
+			submitted++
+		}
+
+		if err := s.certificateRepo.UpdateMint(ctx, certificate); err != nil {
+			return submitted, err
+		}
+	}
+
+	return submitted, nil
+}
+
+// ConfirmSubmittedMints records the outcome of submitted mint transactions
+// that have been mined and returns how many were minted
+func (s *CertificateService) ConfirmSubmittedMints(ctx context.Context) (int, error) {
+	certificates, err := s.certificateRepo.GetByMintStatus(ctx, models.MintStatusSubmitted, mintBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	minted := 0
+	for _, certificate := range certificates {
+		receipt, receiptErr := s.minter.MintReceipt(ctx, certificate.BlockchainTxHash)
+		if receiptErr != nil {
+			if ctx.Err() != nil {
+				return minted, ctx.Err()
+			}
+			s.logger.LogWarn(ctx, "failed to check certificate mint",
+				logger.String("certificate_id", certificate.ID.String()),
+				logger.String("tx_hash", certificate.BlockchainTxHash),
+				logger.String("error", receiptErr.Error()))
+			continue
+		}
+
+		switch receipt.Status {
+		case MintTxConfirmed:
+			certificate.MintStatus = models.MintStatusMinted
+			certificate.TokenID = receipt.TokenID
+			minted++
+		case MintTxFailed:
+			certificate.MintStatus = models.MintStatusFailed
+			certificate.MintError = "mint transaction failed"
+		default:
+			continue
+		}
+
+		if err := s.certificateRepo.UpdateMint(ctx, certificate); err != nil {
+			return minted, err
+		}
+	}
+
+	return minted, nil
+}
+
+// certificateMetadata builds the token metadata published for a certificate
+func certificateMetadata(certificate *models.Certificate) *models.CertificateMetadata {
+	properties := map[string]interface{}{
+		"certificate_id":     certificate.ID.String(),
+		"certificate_number": certificate.CertificateNumber,
+		"serial_number":      certificate.SerialNumber,
+	}
+	if certificate.IssuedAt != nil {
+		properties["issued_at"] = certificate.IssuedAt.UTC().Format(time.RFC3339)
+	}
+
+	return &models.CertificateMetadata{
+		Name: fmt.Sprintf("GreenLedger Certificate %s", certificate.CertificateNumber),
+		Description: fmt.Sprintf("Carbon %s certificate from %s, vintage %d",
+			certificate.Type, certificate.ProjectName, certificate.VintageYear),
+		Attributes: []models.MetadataAttribute{
+			{TraitType: "Type", Value: certificate.Type},
+			{TraitType: "Project", Value: certificate.ProjectName},
+			{TraitType: "Project Type", Value: certificate.ProjectType},
+			{TraitType: "Location", Value: certificate.ProjectLocation},
+			{TraitType: "Standard", Value: certificate.Standard},
+			{TraitType: "Verification Body", Value: certificate.VerificationBody},
+			{TraitType: "Vintage Year", Value: certificate.VintageYear},
+			{TraitType: "Carbon Offset", Value: certificate.CarbonOffset.String()},
+			{TraitType: "Credits Used", Value: certificate.CreditsUsed.String()},
+		},
+		Properties: properties,
+	}
+}
+
+// CertificateMintWorker submits queued certificate mints and confirms
+// submitted ones in the background, so issuing a certificate never waits on
+// the chain
+type CertificateMintWorker struct {
+	certificateService *CertificateService
+	logger             *logger.Logger
+}
+
+// NewCertificateMintWorker creates a new certificate mint worker
+func NewCertificateMintWorker(certificateService *CertificateService, logger *logger.Logger) *CertificateMintWorker {
+	return &CertificateMintWorker{
+		certificateService: certificateService,
+		logger:             logger,
+	}
+}
+
+// Run processes mints once immediately and then every interval until ctx is
+// cancelled
+func (w *CertificateMintWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		submitted, err := w.certificateService.SubmitPendingMints(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.logger.LogError(ctx, "certificate mint submission failed", err)
+		}
+		minted, err := w.certificateService.ConfirmSubmittedMints(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.logger.LogError(ctx, "certificate mint confirmation failed", err)
+		}
+		if submitted > 0 || minted > 0 {
+			w.logger.LogInfo(ctx, "certificate mints processed",
+				logger.Int("submitted", submitted),
+				logger.Int("minted", minted))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+)
+
+// stubMinter fails every mint with mintErr, or reports receipt for every
+// submitted transaction
+type stubMinter struct {
+	mintErr error
+	receipt *MintReceipt
+}
+
+func (m *stubMinter) Network() string {
+	return "stub"
+}
+
+func (m *stubMinter) Mint(ctx context.Context, certificate *models.Certificate, metadata *models.CertificateMetadata) (*MintSubmission, error) {
+	if m.mintErr != nil {
+		return nil, m.mintErr
+	}
+	return &MintSubmission{TxHash: "0xabc", MetadataURI: "ipfs://stub"}, nil
+}
+
+func (m *stubMinter) MintReceipt(ctx context.Context, txHash string) (*MintReceipt, error) {
+	return m.receipt, nil
+}
+
+// issueMintedTestCertificate issues a certificate through a service minting
+// with minter
+func issueMintedTestCertificate(t *testing.T, minter BlockchainMinter) (*CertificateService, *models.Certificate) {
+	t.Helper()
+
+	service, certificateRepo, _, walletClient := newIssueTestService()
+	service.minter = minter
+	walletClient.balances["user-1"] = decimal.NewFromInt(15)
+
+	response, err := service.IssueCertificate(context.Background(), newIssueRequest())
+	if err != nil {
+		t.Fatalf("Expected certificate to be issued, got %v", err)
+	}
+	if response.MintStatus != models.MintStatusPending {
+		t.Fatalf("Expected issued certificate to be queued for minting, got %q", response.MintStatus)
+	}
+
+	return service, certificateRepo.certificates[response.ID]
+}
+
+func TestMinting_StatusLifecycle(t *testing.T) {
+	service, certificate := issueMintedTestCertificate(t, NewMockBlockchainMinter())
+	ctx := context.Background()
+
+	submitted, err := service.SubmitPendingMints(ctx)
+	if err != nil || submitted != 1 {
+		t.Fatalf("Expected 1 mint submitted, got %d (%v)", submitted, err)
+	}
+	if certificate.MintStatus != models.MintStatusSubmitted {
+		t.Errorf("Expected status %s, got %s", models.MintStatusSubmitted, certificate.MintStatus)
+	}
+	if certificate.BlockchainTxHash == "" || certificate.MetadataURI == "" || certificate.BlockchainNetwork != "mock" {
+		t.Errorf("Expected tx hash, metadata URI and network to be recorded, got %q %q %q",
+			certificate.BlockchainTxHash, certificate.MetadataURI, certificate.BlockchainNetwork)
+	}
+	if certificate.TokenID != "" {
+		t.Errorf("Expected no token ID before the mint is confirmed, got %s", certificate.TokenID)
+	}
+
+	minted, err := service.ConfirmSubmittedMints(ctx)
+	if err != nil || minted != 1 {
+		t.Fatalf("Expected 1 mint confirmed, got %d (%v)", minted, err)
+	}
+	if certificate.MintStatus != models.MintStatusMinted || certificate.TokenID != "1" {
+		t.Errorf("Expected minted token 1, got %s token %q", certificate.MintStatus, certificate.TokenID)
+	}
+
+	// Nothing is left to process
+	if submitted, _ := service.SubmitPendingMints(ctx); submitted != 0 {
+		t.Errorf("Expected no further submissions, got %d", submitted)
+	}
+}
+
+func TestIssueCertificate_NotQueuedWithoutMinter(t *testing.T) {
+	service, _, _, walletClient := newIssueTestService()
+	walletClient.balances["user-1"] = decimal.NewFromInt(15)
+
+	response, err := service.IssueCertificate(context.Background(), newIssueRequest())
+	if err != nil {
+		t.Fatalf("Expected certificate to be issued, got %v", err)
+	}
+	if response.MintStatus != "" {
+		t.Errorf("Expected no mint status without a minter, got %q", response.MintStatus)
+	}
+}
+
+func TestSubmitPendingMints_FailsAfterMaxAttempts(t *testing.T) {
+	service, certificate := issueMintedTestCertificate(t, &stubMinter{mintErr: errors.New("node unavailable")})
+
+	for attempt := 1; attempt < maxMintAttempts; attempt++ {
+		service.SubmitPendingMints(context.Background())
+		if certificate.MintStatus != models.MintStatusPending {
+			t.Fatalf("Expected certificate to stay queued after %d failures, got %s", attempt, certificate.MintStatus)
+		}
+	}
+
+	service.SubmitPendingMints(context.Background())
+	if certificate.MintStatus != models.MintStatusFailed {
+		t.Errorf("Expected status %s after %d failures, got %s", models.MintStatusFailed, maxMintAttempts, certificate.MintStatus)
+	}
+	if certificate.MintError != "node unavailable" {
+		t.Errorf("Expected the last error to be recorded, got %q", certificate.MintError)
+	}
+}
+
+func TestConfirmSubmittedMints_WaitsForReceipt(t *testing.T) {
+	minter := &stubMinter{receipt: &MintReceipt{Status: MintTxPending}}
+	service, certificate := issueMintedTestCertificate(t, minter)
+	service.SubmitPendingMints(context.Background())
+
+	if minted, _ := service.ConfirmSubmittedMints(context.Background()); minted != 0 || certificate.MintStatus != models.MintStatusSubmitted {
+		t.Fatalf("Expected an unmined mint to stay submitted, got %s", certificate.MintStatus)
+	}
+
+	minter.receipt = &MintReceipt{Status: MintTxFailed}
+	service.ConfirmSubmittedMints(context.Background())
+	if certificate.MintStatus != models.MintStatusFailed {
+		t.Errorf("Expected a failed transaction to fail the mint, got %s", certificate.MintStatus)
+	}
+}
+
+func TestEthereumMinter_MintAndReceipt(t *testing.T) {
+	const (
+		contract = "0x00000000000000000000000000000000000000c0"
+		from     = "0x00000000000000000000000000000000000000f0"
+		txHash   = "0x1234"
+	)
+	certificateID := uuid.MustParse("00000000-0000-0000-0000-00000000002a")
+
+	// The well-known ERC-721 Transfer event topic
+	transferTopic := "0x" + hex.EncodeToString(keccak256([]byte(transferSignature)))
+	if transferTopic != "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" {
+		t.Fatalf("Unexpected Transfer topic %s", transferTopic)
+	}
+
+	var sentData string
+	mined := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v0/add" {
+			w.Write([]byte(`{"Name":"metadata.json","Hash":"bafymetadata","Size":"42"}`))
+			return
+		}
+
+		var request struct {
+			ID     int64             `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		var result interface{}
+		switch request.Method {
+		case "eth_sendTransaction":
+			var transaction map[string]string
+			json.Unmarshal(request.Params[0], &transaction)
+			if transaction["from"] != from || transaction["to"] != contract {
+				t.Errorf("Expected transaction from %s to %s, got %v", from, contract, transaction)
+			}
+			sentData = transaction["data"]
+			result = txHash
+		case "eth_getTransactionReceipt":
+			if mined {
+				result = map[string]interface{}{
+					"status": "0x1",
+					"logs": []map[string]interface{}{{
+						"address": contract,
+						"topics":  []string{transferTopic, "0x0", "0x0", "0x000000000000000000000000000000000000000000000000000000000000002a"},
+					}},
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	}))
+	defer server.Close()
+
+	minter, err := NewEthereumMinter(EthereumMinterConfig{
+		RPCURL:          server.URL,
+		Network:         "testnet",
+		ContractAddress: contract,
+		FromAddress:     from,
+		IPFSAPIURL:      server.URL,
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("Expected minter to be created, got %v", err)
+	}
+
+	certificate := &models.Certificate{ID: certificateID, CertificateNumber: "CERT-1"}
+	submission, err := minter.Mint(context.Background(), certificate, certificateMetadata(certificate))
+	if err != nil {
+		t.Fatalf("Expected mint to be sent, got %v", err)
+	}
+	if submission.TxHash != txHash || submission.MetadataURI != "ipfs://bafymetadata" {
+		t.Errorf("Expected tx %s with ipfs://bafymetadata, got %+v", txHash, submission)
+	}
+
+	// Selector, recipient, token ID 42, string offset, then the URI
+	selector := hex.EncodeToString(keccak256([]byte(mintSignature))[:4])
+	data := strings.TrimPrefix(sentData, "0x")
+	if !strings.HasPrefix(data, selector) {
+		t.Fatalf("Expected call data to start with selector %s, got %s", selector, data)
+	}
+	words := data[len(selector):]
+	if got := words[64:128]; got != strings.Repeat("0", 62)+"2a" {
+		t.Errorf("Expected token ID 42 derived from the certificate ID, got %s", got)
+	}
+	if got := words[192:256]; got != strings.Repeat("0", 62)+"13" {
+		t.Errorf("Expected a URI length of 19, got %s", got)
+	}
+	if uri, _ := hex.DecodeString(words[256:294]); string(uri) != "ipfs://bafymetadata" {
+		t.Errorf("Expected the metadata URI in the call data, got %q", uri)
+	}
+
+	receipt, err := minter.MintReceipt(context.Background(), txHash)
+	if err != nil || receipt.Status != MintTxPending {
+		t.Fatalf("Expected an unmined transaction to be pending, got %+v (%v)", receipt, err)
+	}
+
+	mined = true
+	receipt, err = minter.MintReceipt(context.Background(), txHash)
+	if err != nil {
+		t.Fatalf("Expected receipt, got %v", err)
+	}
+	if receipt.Status != MintTxConfirmed || receipt.TokenID != "42" {
+		t.Errorf("Expected confirmed token 42, got %+v", receipt)
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// The document printed for certificate types without an active
+// CertificateTemplate
+const (
+	defaultCertificateTitle    = "Certificate of Carbon Offset"
+	defaultCertificateTemplate = `This certifies that
+
+{{.HolderName}}
+
+has offset {{.CarbonOffset}} kg of CO2 through the {{.ProjectName}} project in {{.ProjectLocation}}, verified under {{.Standard}}, vintage {{.VintageYear}}.
+
+Certificate number {{.CertificateNumber}}
+Serial number {{.SerialNumber}}
+Issued on {{.IssueDate}}`
+)
+
+// CertificateDocument is a rendered certificate ready for download
+type CertificateDocument struct {
+	Filename string
+	Content  []byte
+}
+
+// certificateDocumentFields are the placeholders available to certificate
+// templates, written as Go text/template actions such as {{.HolderName}}
+type certificateDocumentFields struct {
+	HolderName        string
+	CertificateNumber string
+	SerialNumber      string
+	CarbonOffset      string
+	ProjectName       string
+	ProjectLocation   string
+	Standard          string
+	VintageYear       int
+	IssueDate         string
+}
+
+// RenderCertificatePDF renders a printable PDF of a certificate userID owns,
+// using the active template for its type or the default one. Only issued
+// and retired certificates can be printed.
+func (s *CertificateService) RenderCertificatePDF(ctx context.Context, certificateID uuid.UUID, userID string) (*CertificateDocument, error) {
+	certificate, err := s.getPrintableCertificate(ctx, certificateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	title, body, err := s.certificateDocumentText(ctx, certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	verificationURL := s.VerificationURL(certificate.CertificateNumber)
+	qrCode, err := verificationQRCode(verificationURL)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := renderCertificatePDF(certificate, title, body, verificationURL, qrCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render certificate PDF: %w", err)
+	}
+
+	return &CertificateDocument{
+		Filename: fmt.Sprintf("certificate-%s.pdf", certificate.CertificateNumber),
+		Content:  content,
+	}, nil
+}
+
+// getPrintableCertificate gets a certificate userID owns that is issued or
+// retired, the states in which it backs an offset claim
+func (s *CertificateService) getPrintableCertificate(ctx context.Context, certificateID uuid.UUID, userID string) (*models.Certificate, error) {
+	certificate, err := s.certificateRepo.GetByID(ctx, certificateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate: %w", err)
+	}
+
+	if err := checkCertificateOwner(certificate, userID); err != nil {
+		return nil, err
+	}
+
+	if !certificate.IsIssued() && !certificate.IsRetired() {
+		return nil, fmt.Errorf("%w: only issued certificates can be printed", ErrCertificateNotActive)
+	}
+
+	return certificate, nil
+}
+
+// certificateDocumentText fills in the title and body of the certificate's
+// template. A template that fails to render is logged and replaced by the
+// default, so one bad template does not stop certificates being printed.
+func (s *CertificateService) certificateDocumentText(ctx context.Context, certificate *models.Certificate) (string, string, error) {
+	fields := documentFields(certificate)
+
+	certificateTemplate, err := s.templateRepo.GetActiveByType(ctx, certificate.Type)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return "", "", fmt.Errorf("failed to get certificate template: %w", err)
+	}
+
+	if certificateTemplate != nil {
+		body, renderErr := fillCertificateTemplate(certificateTemplate.Template, fields)
+		if renderErr == nil {
+			return certificateTemplate.Title, body, nil
+		}
+		s.logger.LogWarn(ctx, "certificate template failed to render, using the default",
+			logger.String("template_id", certificateTemplate.ID.String()),
+			logger.String("error", renderErr.Error()))
+	}
+
+	body, err := fillCertificateTemplate(defaultCertificateTemplate, fields)
+	if err != nil {
+		return "", "", err
+	}
+	return defaultCertificateTitle, body, nil
+}
+
+// documentFields gathers a certificate's template placeholders. Certificates
+// without a holder name are made out to the owning user.
+func documentFields(certificate *models.Certificate) certificateDocumentFields {
+	fields := certificateDocumentFields{
+		HolderName:        certificate.HolderName,
+		CertificateNumber: certificate.CertificateNumber,
+		SerialNumber:      certificate.SerialNumber,
+		CarbonOffset:      certificate.CarbonOffset.StringFixed(3),
+		ProjectName:       certificate.ProjectName,
+		ProjectLocation:   certificate.ProjectLocation,
+		Standard:          certificate.Standard,
+		VintageYear:       certificate.VintageYear,
+	}
+	if fields.HolderName == "" {
+		fields.HolderName = certificate.UserID
+	}
+	if certificate.IssuedAt != nil {
+		fields.IssueDate = certificate.IssuedAt.UTC().Format("2 January 2006")
+	}
+	return fields
+}
+
+func fillCertificateTemplate(text string, fields certificateDocumentFields) (string, error) {
+	tmpl, err := template.New("certificate").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid certificate template: %w", err)
+	}
+
+	var body strings.Builder
+	if err := tmpl.Execute(&body, fields); err != nil {
+		return "", fmt.Errorf("failed to fill certificate template: %w", err)
+	}
+	return body.String(), nil
+}
+
+// renderCertificatePDF lays out a certificate on a landscape A4 page, with
+// its number, serial number and status always printed in the footer and a
+// QR code of its verification URL in the corner
+func renderCertificatePDF(certificate *models.Certificate, title, body, verificationURL string, qrCode []byte) ([]byte, error) {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	// The core fonts are not Unicode; translate names and places to cp1252
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("GreenLedger", true)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+
+	pdf.SetDrawColor(34, 139, 34)
+	pdf.SetLineWidth(1.5)
+	pdf.Rect(10, 10, 277, 190, "D")
+	pdf.SetLineWidth(0.5)
+	pdf.Rect(14, 14, 269, 182, "D")
+
+	pdf.SetY(30)
+	pdf.SetFont("Arial", "B", 28)
+	pdf.CellFormat(0, 14, tr(title), "", 1, "C", false, 0, "")
+	pdf.Ln(8)
+
+	pdf.SetFont("Arial", "", 13)
+	pdf.SetX(30)
+	pdf.MultiCell(237, 7, tr(body), "", "C", false)
+
+	qrOptions := gofpdf.ImageOptions{ImageType: "PNG"}
+	pdf.RegisterImageOptionsReader("verification-qr", qrOptions, bytes.NewReader(qrCode))
+	pdf.ImageOptions("verification-qr", 249, 150, 30, 30, false, qrOptions, 0, "")
+
+	pdf.SetY(176)
+	pdf.SetFont("Arial", "", 9)
+	pdf.SetTextColor(90, 90, 90)
+	footer := fmt.Sprintf("Certificate %s  |  Serial %s  |  Status: %s",
+		certificate.CertificateNumber, certificate.SerialNumber, certificate.Status)
+	pdf.CellFormat(0, 5, tr(footer), "", 1, "C", false, 0, "")
+	pdf.CellFormat(0, 5, tr("Verify at "+verificationURL), "", 1, "C", false, 0, "")
+
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
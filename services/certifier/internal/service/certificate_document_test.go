@@ -0,0 +1,117 @@
+package service
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+)
+
+// pdfText returns a PDF's content streams, inflating compressed ones, so the
+// text drawn on its pages can be searched
+func pdfText(t *testing.T, content []byte) string {
+	t.Helper()
+
+	var text strings.Builder
+	for {
+		start := bytes.Index(content, []byte("stream\n"))
+		if start < 0 {
+			break
+		}
+		content = content[start+len("stream\n"):]
+		end := bytes.Index(content, []byte("endstream"))
+		if end < 0 {
+			t.Fatal("Expected every PDF stream to end")
+		}
+
+		stream := content[:end]
+		if reader, err := zlib.NewReader(bytes.NewReader(stream)); err == nil {
+			if inflated, err := io.ReadAll(reader); err == nil {
+				stream = inflated
+			}
+		}
+		text.Write(stream)
+		content = content[end+len("endstream"):]
+	}
+	return text.String()
+}
+
+func TestRenderCertificatePDF_DefaultTemplate(t *testing.T) {
+	service, certificateRepo, _, certificateID := issueTransferTestCertificate(t)
+	certificate := certificateRepo.certificates[certificateID]
+
+	document, err := service.RenderCertificatePDF(context.Background(), certificateID, "user-1")
+	if err != nil {
+		t.Fatalf("Expected certificate PDF to render, got %v", err)
+	}
+
+	if !bytes.HasPrefix(document.Content, []byte("%PDF-")) {
+		t.Fatalf("Expected a PDF document, got %q", document.Content[:min(len(document.Content), 16)])
+	}
+	if document.Filename != "certificate-"+certificate.CertificateNumber+".pdf" {
+		t.Errorf("Expected the filename to name the certificate, got %s", document.Filename)
+	}
+
+	text := pdfText(t, document.Content)
+	for _, want := range []string{certificate.CertificateNumber, certificate.SerialNumber, defaultCertificateTitle} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected the PDF to contain %q", want)
+		}
+	}
+}
+
+func TestRenderCertificatePDF_ConfiguredTemplate(t *testing.T) {
+	service, certificateRepo, _, certificateID := issueTransferTestCertificate(t)
+	certificate := certificateRepo.certificates[certificateID]
+	certificate.HolderName = "Acme Ltd"
+
+	templateRepo := NewMockTemplateRepository()
+	templateRepo.templates[certificate.Type] = &models.CertificateTemplate{
+		Type:     certificate.Type,
+		Title:    "Offset Award",
+		Template: "Awarded to {{.HolderName}} for {{.CarbonOffset}} kg ({{.SerialNumber}})",
+		IsActive: true,
+	}
+	service.templateRepo = templateRepo
+
+	document, err := service.RenderCertificatePDF(context.Background(), certificateID, "user-1")
+	if err != nil {
+		t.Fatalf("Expected certificate PDF to render, got %v", err)
+	}
+
+	text := pdfText(t, document.Content)
+	for _, want := range []string{"Offset Award", "Awarded to Acme Ltd", certificate.SerialNumber, certificate.CertificateNumber} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected the PDF to contain %q", want)
+		}
+	}
+
+	// A template with an unknown placeholder falls back to the default
+	templateRepo.templates[certificate.Type].Template = "Awarded to {{.Recipient}}"
+	document, err = service.RenderCertificatePDF(context.Background(), certificateID, "user-1")
+	if err != nil {
+		t.Fatalf("Expected certificate PDF to render with the default template, got %v", err)
+	}
+	if text := pdfText(t, document.Content); !strings.Contains(text, defaultCertificateTitle) || !strings.Contains(text, "Acme Ltd") {
+		t.Error("Expected a broken template to be replaced by the default")
+	}
+}
+
+func TestRenderCertificatePDF_Errors(t *testing.T) {
+	service, certificateRepo, _, certificateID := issueTransferTestCertificate(t)
+
+	if _, err := service.RenderCertificatePDF(context.Background(), certificateID, "user-2"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for another user's certificate, got %v", err)
+	}
+
+	certificateRepo.certificates[certificateID].Status = models.CertificateStatusCancelled
+	if _, err := service.RenderCertificatePDF(context.Background(), certificateID, "user-1"); !errors.Is(err, ErrCertificateNotActive) {
+		t.Errorf("Expected ErrCertificateNotActive for a cancelled certificate, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// Expiry sweeps load and expire at most this many certificates at a time,
+// so no query or update holds locks on a large part of the table
+const expirySweepBatchSize = 100
+
+// CertificateExpiredEvent announces that a certificate passed its expiry
+// and can no longer be retired or transferred
+type CertificateExpiredEvent struct {
+	CertificateID     string    `json:"certificate_id"`
+	CertificateNumber string    `json:"certificate_number"`
+	UserID            string    `json:"user_id"`
+	ExpiresAt         time.Time `json:"expires_at"`
+	Timestamp         time.Time `json:"timestamp"`
+}
+
+// EventPublisher interface for publishing events
+type EventPublisher interface {
+	PublishCertificateExpired(ctx context.Context, event *CertificateExpiredEvent) error
+}
+
+// ExpireCertificates moves every issued or verified certificate whose expiry
+// is before now to expired, publishing an event for each, and returns how
+// many were expired. Certificates are expired one at a time behind a status
+// check, so overlapping sweeps expire and announce each certificate once.
+func (s *CertificateService) ExpireCertificates(ctx context.Context, now time.Time) (int, error) {
+	expired := 0
+	for {
+		certificates, err := s.certificateRepo.GetExpired(ctx, now, expirySweepBatchSize)
+		if err != nil {
+			return expired, err
+		}
+
+		for _, certificate := range certificates {
+			err := s.certificateRepo.Expire(ctx, certificate.ID)
+			if errors.Is(err, repository.ErrCertificateStatusChanged) {
+				// Retired, or expired by another sweep, since the query ran
+				continue
+			}
+			if err != nil {
+				return expired, err
+			}
+			certificate.Status = models.CertificateStatusExpired
+			expired++
+
+			s.publishCertificateExpired(ctx, certificate, now)
+		}
+
+		if len(certificates) < expirySweepBatchSize {
+			return expired, nil
+		}
+	}
+}
+
+// publishCertificateExpired announces an expired certificate. The stored
+// status is the record of the expiry, so a failed publish is only logged.
+func (s *CertificateService) publishCertificateExpired(ctx context.Context, certificate *models.Certificate, now time.Time) {
+	event := &CertificateExpiredEvent{
+		CertificateID:     certificate.ID.String(),
+		CertificateNumber: certificate.CertificateNumber,
+		UserID:            certificate.UserID,
+		ExpiresAt:         *certificate.ExpiresAt,
+		Timestamp:         now,
+	}
+	if err := s.eventPublisher.PublishCertificateExpired(ctx, event); err != nil {
+		s.logger.LogError(ctx, "failed to publish certificate expired event", err,
+			logger.String("certificate_id", event.CertificateID))
+	}
+}
+
+// CertificateExpirySweeper expires certificates past their expiry in the
+// background, so stored statuses and the counts built on them stay current
+type CertificateExpirySweeper struct {
+	certificateService *CertificateService
+	logger             *logger.Logger
+}
+
+// NewCertificateExpirySweeper creates a new certificate expiry sweeper
+func NewCertificateExpirySweeper(certificateService *CertificateService, logger *logger.Logger) *CertificateExpirySweeper {
+	return &CertificateExpirySweeper{
+		certificateService: certificateService,
+		logger:             logger,
+	}
+}
+
+// Run sweeps once immediately and then every interval until ctx is cancelled
+func (w *CertificateExpirySweeper) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		expired, err := w.certificateService.ExpireCertificates(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			w.logger.LogError(ctx, "certificate expiry sweep failed", err)
+		}
+		if expired > 0 {
+			w.logger.LogInfo(ctx, "expired certificates",
+				logger.Int("expired", expired))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
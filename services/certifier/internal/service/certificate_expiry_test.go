@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+)
+
+// addExpiryTestCertificate adds a certificate in status that expires at
+// expiresAt, or never when it is nil
+func addExpiryTestCertificate(certificateRepo *MockCertificateRepository, status string, expiresAt *time.Time) *models.Certificate {
+	certificate := &models.Certificate{
+		ID:                uuid.New(),
+		CertificateNumber: fmt.Sprintf("CERT-%d", len(certificateRepo.certificates)+1),
+		UserID:            "user-1",
+		Status:            status,
+		ExpiresAt:         expiresAt,
+	}
+	certificateRepo.certificates[certificate.ID] = certificate
+	return certificate
+}
+
+func TestExpireCertificates_ExpiryBoundary(t *testing.T) {
+	service, certificateRepo, _, _ := newIssueTestService()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) *time.Time {
+		expiresAt := now.Add(offset)
+		return &expiresAt
+	}
+
+	tests := []struct {
+		name       string
+		status     string
+		expiresAt  *time.Time
+		wantStatus string
+	}{
+		{"expired a nanosecond ago", models.CertificateStatusIssued, at(-time.Nanosecond), models.CertificateStatusExpired},
+		{"expired last year", models.CertificateStatusIssued, at(-365 * 24 * time.Hour), models.CertificateStatusExpired},
+		{"verified and expired", models.CertificateStatusVerified, at(-time.Minute), models.CertificateStatusExpired},
+		{"expiring now", models.CertificateStatusIssued, at(0), models.CertificateStatusIssued},
+		{"expiring in a nanosecond", models.CertificateStatusIssued, at(time.Nanosecond), models.CertificateStatusIssued},
+		{"never expiring", models.CertificateStatusIssued, nil, models.CertificateStatusIssued},
+		{"retired after expiry", models.CertificateStatusRetired, at(-time.Hour), models.CertificateStatusRetired},
+		{"pending after expiry", models.CertificateStatusPending, at(-time.Hour), models.CertificateStatusPending},
+	}
+
+	certificates := make([]*models.Certificate, len(tests))
+	for i, tt := range tests {
+		certificates[i] = addExpiryTestCertificate(certificateRepo, tt.status, tt.expiresAt)
+	}
+
+	expired, err := service.ExpireCertificates(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Expected certificates to be expired, got %v", err)
+	}
+	if expired != 3 {
+		t.Errorf("Expected 3 certificates to be expired, got %d", expired)
+	}
+
+	for i, tt := range tests {
+		if got := certificates[i].Status; got != tt.wantStatus {
+			t.Errorf("%s: expected status %s, got %s", tt.name, tt.wantStatus, got)
+		}
+	}
+}
+
+func TestExpireCertificates_PublishesEventsOnce(t *testing.T) {
+	service, certificateRepo, _, _ := newIssueTestService()
+	publisher := service.eventPublisher.(*MockEventPublisher)
+	now := time.Now()
+	expiresAt := now.Add(-time.Hour)
+	certificate := addExpiryTestCertificate(certificateRepo, models.CertificateStatusIssued, &expiresAt)
+
+	if _, err := service.ExpireCertificates(context.Background(), now); err != nil {
+		t.Fatalf("Expected certificates to be expired, got %v", err)
+	}
+	if len(publisher.Events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(publisher.Events))
+	}
+	event, ok := publisher.Events[0].(*CertificateExpiredEvent)
+	if !ok {
+		t.Fatalf("Expected a certificate expired event, got %T", publisher.Events[0])
+	}
+	if event.CertificateID != certificate.ID.String() || event.CertificateNumber != certificate.CertificateNumber ||
+		event.UserID != "user-1" || !event.ExpiresAt.Equal(expiresAt) || !event.Timestamp.Equal(now) {
+		t.Errorf("Expected the event to describe the expired certificate, got %+v", event)
+	}
+
+	expired, err := service.ExpireCertificates(context.Background(), now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected a second sweep to succeed, got %v", err)
+	}
+	if expired != 0 || len(publisher.Events) != 1 {
+		t.Errorf("Expected a second sweep to do nothing, got %d expired and %d events", expired, len(publisher.Events))
+	}
+}
+
+func TestExpireCertificates_SweepsEveryBatch(t *testing.T) {
+	service, certificateRepo, _, _ := newIssueTestService()
+	now := time.Now()
+	expiresAt := now.Add(-time.Hour)
+	total := 2*expirySweepBatchSize + 1
+	for i := 0; i < total; i++ {
+		addExpiryTestCertificate(certificateRepo, models.CertificateStatusIssued, &expiresAt)
+	}
+
+	expired, err := service.ExpireCertificates(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Expected certificates to be expired, got %v", err)
+	}
+	if expired != total {
+		t.Errorf("Expected all %d certificates to be expired, got %d", total, expired)
+	}
+	for _, certificate := range certificateRepo.certificates {
+		if certificate.Status != models.CertificateStatusExpired {
+			t.Fatalf("Expected every certificate to be expired, got %s", certificate.Status)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+)
+
+// seedListTestCertificates adds certificates of mixed statuses, types and
+// project types, created a minute apart in the order given
+func seedListTestCertificates() *CertificateService {
+	service, certificateRepo, _, _ := newIssueTestService()
+
+	seeds := []struct {
+		status, certType, projectType string
+	}{
+		{models.CertificateStatusPending, models.CertificateTypeOffset, models.ProjectTypeForestry},
+		{models.CertificateStatusIssued, models.CertificateTypeOffset, models.ProjectTypeForestry},
+		{models.CertificateStatusIssued, models.CertificateTypeRemoval, models.ProjectTypeRenewable},
+		{models.CertificateStatusRetired, models.CertificateTypeOffset, models.ProjectTypeRenewable},
+		{models.CertificateStatusPending, models.CertificateTypeRemoval, models.ProjectTypeForestry},
+		{models.CertificateStatusPending, models.CertificateTypeOffset, models.ProjectTypeRenewable},
+	}
+
+	createdAt := time.Now().Add(-time.Hour)
+	for i, seed := range seeds {
+		certificate := &models.Certificate{
+			ID:          uuid.New(),
+			UserID:      "user-1",
+			Status:      seed.status,
+			Type:        seed.certType,
+			ProjectType: seed.projectType,
+			CreatedAt:   createdAt.Add(time.Duration(i) * time.Minute),
+		}
+		certificateRepo.certificates[certificate.ID] = certificate
+	}
+
+	return service
+}
+
+func TestListAll_Filters(t *testing.T) {
+	service := seedListTestCertificates()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		filter CertificateListFilter
+		want   int64
+	}{
+		{"unfiltered", CertificateListFilter{}, 6},
+		{"by type", CertificateListFilter{Type: models.CertificateTypeRemoval}, 2},
+		{"by project type", CertificateListFilter{ProjectType: models.ProjectTypeRenewable}, 3},
+		{"by both", CertificateListFilter{Type: models.CertificateTypeOffset, ProjectType: models.ProjectTypeForestry}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certificates, total, err := service.ListAll(ctx, tt.filter, 20, 0)
+			if err != nil {
+				t.Fatalf("Expected certificates to be listed, got %v", err)
+			}
+			if total != tt.want || int64(len(certificates)) != tt.want {
+				t.Errorf("Expected %d certificates, got %d of %d", tt.want, len(certificates), total)
+			}
+			for _, certificate := range certificates {
+				if tt.filter.Type != "" && certificate.Type != tt.filter.Type ||
+					tt.filter.ProjectType != "" && certificate.ProjectType != tt.filter.ProjectType {
+					t.Errorf("Expected only matching certificates, got %s/%s", certificate.Type, certificate.ProjectType)
+				}
+			}
+		})
+	}
+}
+
+func TestListByStatus_FiltersAndPaginates(t *testing.T) {
+	service := seedListTestCertificates()
+	ctx := context.Background()
+
+	pending, total, err := service.ListByStatus(ctx, models.CertificateStatusPending, CertificateListFilter{}, 2, 0)
+	if err != nil {
+		t.Fatalf("Expected pending certificates to be listed, got %v", err)
+	}
+	if total != 3 || len(pending) != 2 {
+		t.Fatalf("Expected the first 2 of 3 pending certificates, got %d of %d", len(pending), total)
+	}
+	if !pending[0].CreatedAt.After(pending[1].CreatedAt) {
+		t.Error("Expected the newest certificates first")
+	}
+	for _, certificate := range pending {
+		if certificate.Status != models.CertificateStatusPending {
+			t.Errorf("Expected only pending certificates, got %s", certificate.Status)
+		}
+	}
+
+	rest, _, err := service.ListByStatus(ctx, models.CertificateStatusPending, CertificateListFilter{}, 2, 2)
+	if err != nil || len(rest) != 1 {
+		t.Errorf("Expected the last pending certificate on the second page, got %d (%v)", len(rest), err)
+	}
+
+	filtered, total, err := service.ListByStatus(ctx, models.CertificateStatusPending, CertificateListFilter{Type: models.CertificateTypeRemoval}, 20, 0)
+	if err != nil || total != 1 || filtered[0].Type != models.CertificateTypeRemoval {
+		t.Errorf("Expected 1 pending removal certificate, got %d (%v)", total, err)
+	}
+
+	if _, _, err := service.ListByStatus(ctx, "archived", CertificateListFilter{}, 20, 0); !errors.Is(err, ErrInvalidCertificateRequest) {
+		t.Errorf("Expected ErrInvalidCertificateRequest for an unknown status, got %v", err)
+	}
+}
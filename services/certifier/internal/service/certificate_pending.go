@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// pendingCertificateTimeout is how long a certificate may stay pending before
+// it is taken to be abandoned by a failed issuance rather than still being
+// paid for by the request that created it
+const pendingCertificateTimeout = 15 * time.Minute
+
+// pendingSweepBatchSize bounds how many pending certificates are loaded at a
+// time
+const pendingSweepBatchSize = 100
+
+// ReconcilePendingCertificates settles every certificate created pending
+// before createdBefore, and returns how many were issued and how many were
+// rolled back. Each is paid for again with its ID as the debit reference, so
+// a debit that went through before its issuance failed is not taken twice;
+// one the wallet cannot cover is deleted and its project credits returned.
+// Certificates that still cannot be settled, e.g. because the wallet is
+// unreachable, stay pending for the next sweep.
+func (s *CertificateService) ReconcilePendingCertificates(ctx context.Context, createdBefore time.Time) (issued, rolledBack int, err error) {
+	for {
+		certificates, err := s.certificateRepo.GetStalePending(ctx, createdBefore, pendingSweepBatchSize)
+		if err != nil {
+			return issued, rolledBack, err
+		}
+
+		settled := 0
+		for _, certificate := range certificates {
+			_, err := s.resumePendingCertificate(ctx, certificate)
+			switch {
+			case err == nil:
+				issued++
+				settled++
+			case errors.Is(err, ErrInsufficientWalletCredits):
+				rolledBack++
+				settled++
+			case ctx.Err() != nil:
+				return issued, rolledBack, ctx.Err()
+			default:
+				s.logger.LogError(ctx, "failed to reconcile pending certificate", err,
+					logger.String("certificate_id", certificate.ID.String()),
+					logger.String("user_id", certificate.UserID))
+			}
+		}
+
+		// A full batch that could not be settled would be loaded again
+		if len(certificates) < pendingSweepBatchSize || settled == 0 {
+			return issued, rolledBack, nil
+		}
+	}
+}
+
+// PendingCertificateReconciler resumes or rolls back certificates left
+// pending by failed issuances in the background, so their project credits
+// are not held forever when the client never retries
+type PendingCertificateReconciler struct {
+	certificateService *CertificateService
+	logger             *logger.Logger
+}
+
+// NewPendingCertificateReconciler creates a new pending certificate reconciler
+func NewPendingCertificateReconciler(certificateService *CertificateService, logger *logger.Logger) *PendingCertificateReconciler {
+	return &PendingCertificateReconciler{
+		certificateService: certificateService,
+		logger:             logger,
+	}
+}
+
+// Run sweeps once immediately and then every interval until ctx is cancelled
+func (w *PendingCertificateReconciler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		createdBefore := time.Now().Add(-pendingCertificateTimeout)
+		issued, rolledBack, err := w.certificateService.ReconcilePendingCertificates(ctx, createdBefore)
+		if err != nil && ctx.Err() == nil {
+			w.logger.LogError(ctx, "pending certificate sweep failed", err)
+		}
+		if issued > 0 || rolledBack > 0 {
+			w.logger.LogInfo(ctx, "reconciled pending certificates",
+				logger.Int("issued", issued),
+				logger.Int("rolled_back", rolledBack))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+)
+
+// issuePendingTestCertificate issues a certificate while the wallet is
+// unreachable, leaving it pending with its project credits taken
+func issuePendingTestCertificate(t *testing.T, service *CertificateService, walletClient *MockWalletClient) {
+	t.Helper()
+	walletClient.debitErr = errors.New("wallet unavailable")
+	if _, err := service.IssueCertificate(context.Background(), newIssueRequest()); err == nil {
+		t.Fatal("Expected the wallet error to fail the request")
+	}
+	walletClient.debitErr = nil
+}
+
+func TestReconcilePendingCertificates_IssuesPaidCertificate(t *testing.T) {
+	service, certificateRepo, projectRepo, walletClient := newIssueTestService()
+	walletClient.balances["user-1"] = decimal.NewFromInt(20)
+	issuePendingTestCertificate(t, service, walletClient)
+
+	issued, rolledBack, err := service.ReconcilePendingCertificates(context.Background(), time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("Expected pending certificates to be reconciled, got %v", err)
+	}
+	if issued != 1 || rolledBack != 0 {
+		t.Errorf("Expected 1 issued and 0 rolled back, got %d and %d", issued, rolledBack)
+	}
+
+	for _, certificate := range certificateRepo.certificates {
+		if certificate.Status != models.CertificateStatusIssued {
+			t.Errorf("Expected the certificate to be issued, got %s", certificate.Status)
+		}
+	}
+	if !walletClient.balances["user-1"].Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Expected 15 credits to be debited, got %s left", walletClient.balances["user-1"])
+	}
+	project, _ := projectRepo.GetByName(context.Background(), "Amazon Reforestation")
+	if !project.AvailableCredits.Equal(decimal.NewFromInt(85)) {
+		t.Errorf("Expected 85 project credits left, got %s", project.AvailableCredits)
+	}
+}
+
+func TestReconcilePendingCertificates_RollsBackUnpaidCertificate(t *testing.T) {
+	service, certificateRepo, projectRepo, walletClient := newIssueTestService()
+	walletClient.balances["user-1"] = decimal.NewFromInt(10)
+	issuePendingTestCertificate(t, service, walletClient)
+
+	issued, rolledBack, err := service.ReconcilePendingCertificates(context.Background(), time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("Expected pending certificates to be reconciled, got %v", err)
+	}
+	if issued != 0 || rolledBack != 1 {
+		t.Errorf("Expected 0 issued and 1 rolled back, got %d and %d", issued, rolledBack)
+	}
+
+	if len(certificateRepo.certificates) != 0 {
+		t.Errorf("Expected the unpaid certificate to be deleted, got %d certificates", len(certificateRepo.certificates))
+	}
+	project, _ := projectRepo.GetByName(context.Background(), "Amazon Reforestation")
+	if !project.AvailableCredits.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected the project credits to be returned, got %s", project.AvailableCredits)
+	}
+}
+
+func TestReconcilePendingCertificates_LeavesCertificatesItCannotSettle(t *testing.T) {
+	service, certificateRepo, _, walletClient := newIssueTestService()
+	walletClient.balances["user-1"] = decimal.NewFromInt(20)
+	issuePendingTestCertificate(t, service, walletClient)
+
+	// Certificates created after the cutoff may still be paid for by their request
+	issued, rolledBack, err := service.ReconcilePendingCertificates(context.Background(), time.Now().Add(-time.Minute))
+	if err != nil || issued != 0 || rolledBack != 0 {
+		t.Errorf("Expected recent certificates to be left alone, got %d issued, %d rolled back and %v", issued, rolledBack, err)
+	}
+
+	walletClient.debitErr = errors.New("wallet unavailable")
+	issued, rolledBack, err = service.ReconcilePendingCertificates(context.Background(), time.Now().Add(time.Second))
+	if err != nil || issued != 0 || rolledBack != 0 {
+		t.Errorf("Expected the sweep to skip the certificate, got %d issued, %d rolled back and %v", issued, rolledBack, err)
+	}
+
+	for _, certificate := range certificateRepo.certificates {
+		if certificate.Status != models.CertificateStatusPending {
+			t.Errorf("Expected the certificate to stay pending, got %s", certificate.Status)
+		}
+	}
+	if !walletClient.balances["user-1"].Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Expected the wallet to be untouched, got %s", walletClient.balances["user-1"])
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+)
+
+// decodeQRCode reads the text encoded in a QR code image
+func decodeQRCode(t *testing.T, img image.Image) string {
+	t.Helper()
+
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		t.Fatalf("Expected a binary bitmap, got %v", err)
+	}
+	result, err := qrcode.NewQRCodeReader().Decode(bitmap, nil)
+	if err != nil {
+		t.Fatalf("Expected the QR code to decode, got %v", err)
+	}
+	return result.GetText()
+}
+
+func TestCertificateQRCode_EncodesVerificationURL(t *testing.T) {
+	service, certificateRepo, _, certificateID := issueTransferTestCertificate(t)
+	certificate := certificateRepo.certificates[certificateID]
+
+	content, err := service.CertificateQRCode(context.Background(), certificateID, "user-1")
+	if err != nil {
+		t.Fatalf("Expected QR code to render, got %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Expected a PNG, got %v", err)
+	}
+	if size := img.Bounds().Dx(); size != qrCodeSize {
+		t.Errorf("Expected a %dpx QR code, got %dpx", qrCodeSize, size)
+	}
+
+	want := testPublicBaseURL + "/certificates/verify/" + certificate.CertificateNumber
+	if got := decodeQRCode(t, img); got != want {
+		t.Errorf("Expected QR code to encode %s, got %s", want, got)
+	}
+}
+
+func TestCertificateQRCode_Errors(t *testing.T) {
+	service, certificateRepo, _, certificateID := issueTransferTestCertificate(t)
+
+	if _, err := service.CertificateQRCode(context.Background(), certificateID, "user-2"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for another user's certificate, got %v", err)
+	}
+
+	certificateRepo.certificates[certificateID].Status = models.CertificateStatusPending
+	if _, err := service.CertificateQRCode(context.Background(), certificateID, "user-1"); !errors.Is(err, ErrCertificateNotActive) {
+		t.Errorf("Expected ErrCertificateNotActive for a pending certificate, got %v", err)
+	}
+}
+
+func TestRenderCertificatePDF_EmbedsQRCode(t *testing.T) {
+	service, certificateRepo, _, certificateID := issueTransferTestCertificate(t)
+	certificate := certificateRepo.certificates[certificateID]
+
+	document, err := service.RenderCertificatePDF(context.Background(), certificateID, "user-1")
+	if err != nil {
+		t.Fatalf("Expected certificate PDF to render, got %v", err)
+	}
+
+	if !bytes.Contains(document.Content, []byte("/Subtype /Image")) {
+		t.Error("Expected the PDF to embed the QR code image")
+	}
+	if text := pdfText(t, document.Content); !strings.Contains(text, service.VerificationURL(certificate.CertificateNumber)) {
+		t.Error("Expected the PDF to print the verification URL")
+	}
+}
+
+func TestVerificationURL_EscapesCertificateNumber(t *testing.T) {
+	service, _, _, _ := newIssueTestService()
+
+	if got := service.VerificationURL("CERT 1/2"); got != testPublicBaseURL+"/certificates/verify/CERT%201%2F2" {
+		t.Errorf("Expected the certificate number to be path escaped, got %s", got)
+	}
+}
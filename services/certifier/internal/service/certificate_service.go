@@ -2,32 +2,85 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
 	"github.com/sloweyyy/GreenLedger/services/certifier/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/database"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
 )
 
+// ErrActiveCertificates is returned when erasing a user who still holds
+// issued or verified certificates
+var ErrActiveCertificates = errors.New("user has active certificates")
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is replayed
+// with a request that differs from the one that issued the certificate
+var ErrIdempotencyKeyReused = errors.New("idempotency key reused with different request")
+
+// ErrInvalidCertificateRequest is returned when an issue request fails
+// validation or names a project that does not exist
+var ErrInvalidCertificateRequest = errors.New("invalid certificate request")
+
+// ErrCertificateNotActive is returned when retiring or transferring a
+// certificate that has expired or was already retired
+var ErrCertificateNotActive = errors.New("certificate is not active")
+
+// activeCertificateStatuses are the statuses reported by Certificate.IsIssued
+var activeCertificateStatuses = []string{
+	models.CertificateStatusIssued,
+	models.CertificateStatusVerified,
+}
+
+// metricsServiceName labels the business metrics recorded by the certifier
+const metricsServiceName = "certifier"
+
 // CertificateService handles certificate business logic
 type CertificateService struct {
-	certificateRepo *repository.CertificateRepository
-	projectRepo     *repository.ProjectRepository
+	certificateRepo repository.CertificateRepositoryInterface
+	projectRepo     repository.ProjectRepositoryInterface
+	templateRepo    repository.TemplateRepositoryInterface
+	walletClient    WalletClient
+	minter          BlockchainMinter
+	eventPublisher  EventPublisher
+	publicBaseURL   string
+	metrics         *monitoring.Metrics
 	logger          *logger.Logger
 }
 
-// NewCertificateService creates a new certificate service
+// NewCertificateService creates a new certificate service. Issued
+// certificates are queued for minting with minter, or not minted when it is
+// nil. Certificate lifecycle events are sent through eventPublisher.
+// publicBaseURL is where the API is reachable from outside, e.g.
+// https://api.example.com/api/v1, and prefixes verification links. metrics
+// may be nil to record no business metrics.
 func NewCertificateService(
-	certificateRepo *repository.CertificateRepository,
-	projectRepo *repository.ProjectRepository,
+	certificateRepo repository.CertificateRepositoryInterface,
+	projectRepo repository.ProjectRepositoryInterface,
+	templateRepo repository.TemplateRepositoryInterface,
+	walletClient WalletClient,
+	minter BlockchainMinter,
+	eventPublisher EventPublisher,
+	publicBaseURL string,
+	metrics *monitoring.Metrics,
 	logger *logger.Logger,
 ) *CertificateService {
 	return &CertificateService{
 		certificateRepo: certificateRepo,
 		projectRepo:     projectRepo,
+		templateRepo:    templateRepo,
+		walletClient:    walletClient,
+		minter:          minter,
+		eventPublisher:  eventPublisher,
+		publicBaseURL:   strings.TrimSuffix(publicBaseURL, "/"),
+		metrics:         metrics,
 		logger:          logger,
 	}
 }
@@ -42,12 +95,19 @@ type IssueCertificateRequest struct {
 	Description    string          `json:"description"`
 	VintageYear    int             `json:"vintage_year"`
 	ExpirationDays int             `json:"expiration_days"`
+	// HolderName is printed on the certificate document, e.g. a company
+	// claiming the offset. It is cleared when the certificate is transferred.
+	HolderName string `json:"holder_name" binding:"omitempty,max=200"`
+	// IdempotencyKey makes retries safe: repeating a request with the same key
+	// returns the originally issued certificate instead of issuing another
+	IdempotencyKey string `json:"idempotency_key" binding:"omitempty,max=128"`
 }
 
 // CertificateResponse represents a certificate in API responses
 type CertificateResponse struct {
 	ID                uuid.UUID       `json:"id"`
 	UserID            string          `json:"user_id"`
+	HolderName        string          `json:"holder_name,omitempty"`
 	CertificateNumber string          `json:"certificate_number"`
 	Type              string          `json:"type"`
 	Status            string          `json:"status"`
@@ -62,12 +122,21 @@ type CertificateResponse struct {
 	SerialNumber      string          `json:"serial_number"`
 	BlockchainTxHash  string          `json:"blockchain_tx_hash"`
 	TokenID           string          `json:"token_id"`
+	MetadataURI       string          `json:"metadata_uri"`
+	MintStatus        string          `json:"mint_status,omitempty"`
 	IssuedAt          *time.Time      `json:"issued_at"`
 	ExpiresAt         *time.Time      `json:"expires_at"`
 	CreatedAt         time.Time       `json:"created_at"`
 }
 
-// IssueCertificate issues a new carbon offset certificate
+// IssueCertificate issues a new carbon offset certificate, paid for by
+// debiting CreditsUsed from the user's wallet. The certificate is saved as
+// pending together with the project credits it takes, so its ID can be the
+// debit's reference; if the wallet cannot cover the credits both are undone.
+// If the wallet cannot be reached, or the issued status cannot be saved, the
+// certificate stays pending and keeps its project credits, since the debit
+// may still have gone through. Retrying with the same idempotency key resumes
+// it from the debit, as does ReconcilePendingCertificates once it is stale.
 func (s *CertificateService) IssueCertificate(ctx context.Context, req *IssueCertificateRequest) (*CertificateResponse, error) {
 	s.logger.LogInfo(ctx, "issuing certificate",
 		logger.String("user_id", req.UserID),
@@ -75,20 +144,28 @@ func (s *CertificateService) IssueCertificate(ctx context.Context, req *IssueCer
 
 	// Validate request
 	if err := s.validateIssueRequest(req); err != nil {
-		return nil, fmt.Errorf("invalid request: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCertificateRequest, err)
+	}
+
+	if req.IdempotencyKey != "" {
+		existing, err := s.findIdempotentCertificate(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return s.resumePendingCertificate(ctx, existing)
+		}
 	}
 
 	// Get project information
 	project, err := s.projectRepo.GetByName(ctx, req.ProjectName)
 	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, fmt.Errorf("%w: project %q not found", ErrInvalidCertificateRequest, req.ProjectName)
+		}
 		return nil, fmt.Errorf("failed to get project: %w", err)
 	}
 
-	// Check if project has enough available credits
-	if !project.CanIssueCredits(req.CreditsUsed) {
-		return nil, fmt.Errorf("insufficient credits available in project")
-	}
-
 	// Generate certificate number and serial number
 	certificateNumber := s.generateCertificateNumber(req.Type, project.Type)
 	serialNumber := s.generateSerialNumber(project.Name, req.VintageYear)
@@ -96,6 +173,7 @@ func (s *CertificateService) IssueCertificate(ctx context.Context, req *IssueCer
 	// Create certificate
 	certificate := &models.Certificate{
 		UserID:            req.UserID,
+		HolderName:        req.HolderName,
 		CertificateNumber: certificateNumber,
 		Type:              req.Type,
 		Status:            models.CertificateStatusPending,
@@ -109,32 +187,69 @@ func (s *CertificateService) IssueCertificate(ctx context.Context, req *IssueCer
 		VintageYear:       req.VintageYear,
 		SerialNumber:      serialNumber,
 	}
+	if req.IdempotencyKey != "" {
+		certificate.IdempotencyKey = &req.IdempotencyKey
+	}
 
 	// Set expiration if specified
 	if req.ExpirationDays > 0 {
-		expiresAt := time.Now().AddDate(0, 0, req.ExpirationDays)
+		expiresAt := time.Now().UTC().AddDate(0, 0, req.ExpirationDays)
 		certificate.ExpiresAt = &expiresAt
 	}
 
-	// Save certificate
-	if err := s.certificateRepo.Create(ctx, certificate); err != nil {
+	// Save certificate and take its credits from the project. A concurrent
+	// retry with the same idempotency key loses on the unique index and
+	// returns the winner's certificate.
+	if err := s.certificateRepo.IssueWithProjectUpdate(ctx, certificate, project.ID); err != nil {
+		if req.IdempotencyKey != "" {
+			existing, lookupErr := s.findIdempotentCertificate(ctx, req)
+			if lookupErr != nil {
+				return nil, lookupErr
+			}
+			if existing != nil {
+				return s.resumePendingCertificate(ctx, existing)
+			}
+		}
+		if errors.Is(err, repository.ErrProjectCreditsUnavailable) {
+			return nil, fmt.Errorf("%w: project %s", ErrInsufficientProjectCredits, project.Name)
+		}
 		return nil, fmt.Errorf("failed to create certificate: %w", err)
 	}
 
-	// Update project available credits
-	if err := s.projectRepo.UpdateAvailableCredits(ctx, project.ID, req.CreditsUsed.InexactFloat64()); err != nil {
-		s.logger.LogError(ctx, "failed to update project credits", err)
-		// Note: In a real system, this should be handled with a transaction
+	return s.payForCertificate(ctx, certificate, project.ID)
+}
+
+// payForCertificate debits a pending certificate's credits from its owner's
+// wallet and issues it. The certificate ID is the debit's reference, so paying
+// for a certificate again after a failure cannot debit it twice.
+func (s *CertificateService) payForCertificate(ctx context.Context, certificate *models.Certificate, projectID uuid.UUID) (*CertificateResponse, error) {
+	description := fmt.Sprintf("Certificate %s", certificate.CertificateNumber)
+	if err := s.walletClient.DebitCredits(ctx, certificate.UserID, certificate.CreditsUsed, certificate.ID.String(), description); err != nil {
+		if errors.Is(err, ErrInsufficientWalletCredits) {
+			if deleteErr := s.certificateRepo.DeleteWithProjectRestore(ctx, certificate, projectID); deleteErr != nil {
+				s.logger.LogError(ctx, "failed to roll back unpaid certificate", deleteErr,
+					logger.String("certificate_id", certificate.ID.String()))
+			}
+			return nil, err
+		}
+		s.logger.LogError(ctx, "failed to debit certificate credits", err,
+			logger.String("certificate_id", certificate.ID.String()),
+			logger.String("user_id", certificate.UserID))
+		return nil, fmt.Errorf("failed to debit certificate credits: %w", err)
 	}
 
 	// Issue the certificate (update status)
 	now := time.Now().UTC()
 	certificate.Status = models.CertificateStatusIssued
 	certificate.IssuedAt = &now
+	if s.minter != nil {
+		certificate.MintStatus = models.MintStatusPending
+	}
 
 	if err := s.certificateRepo.Update(ctx, certificate); err != nil {
 		return nil, fmt.Errorf("failed to issue certificate: %w", err)
 	}
+	s.recordCertificateIssued(certificate)
 
 	s.logger.LogInfo(ctx, "certificate issued successfully",
 		logger.String("certificate_id", certificate.ID.String()),
@@ -143,16 +258,79 @@ func (s *CertificateService) IssueCertificate(ctx context.Context, req *IssueCer
 	return s.certificateToResponse(certificate), nil
 }
 
-// GetCertificate retrieves a certificate by ID
+// findIdempotentCertificate returns the certificate already created for the
+// request's idempotency key, or nil if the key has not been used
+func (s *CertificateService) findIdempotentCertificate(ctx context.Context, req *IssueCertificateRequest) (*models.Certificate, error) {
+	certificate, err := s.certificateRepo.GetByIdempotencyKey(ctx, req.UserID, req.IdempotencyKey)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to check idempotency key: %w", err)
+	}
+
+	if !matchesIssueRequest(certificate, req) {
+		return nil, ErrIdempotencyKeyReused
+	}
+
+	s.logger.LogInfo(ctx, "returning certificate for repeated idempotency key",
+		logger.String("certificate_id", certificate.ID.String()),
+		logger.String("user_id", req.UserID))
+
+	return certificate, nil
+}
+
+// resumePendingCertificate returns a certificate found again by a replayed
+// idempotency key or the pending reconciler. A certificate still pending was
+// not paid for or not issued when the first request failed, so paying for it
+// is retried.
+func (s *CertificateService) resumePendingCertificate(ctx context.Context, certificate *models.Certificate) (*CertificateResponse, error) {
+	if certificate.Status != models.CertificateStatusPending {
+		return s.certificateToResponse(certificate), nil
+	}
+
+	project, err := s.projectRepo.GetByName(ctx, certificate.ProjectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "resuming pending certificate",
+		logger.String("certificate_id", certificate.ID.String()),
+		logger.String("user_id", certificate.UserID))
+
+	return s.payForCertificate(ctx, certificate, project.ID)
+}
+
+// matchesIssueRequest reports whether a certificate was issued for the same
+// request, so a replayed idempotency key can be told apart from a reused one
+func matchesIssueRequest(certificate *models.Certificate, req *IssueCertificateRequest) bool {
+	return certificate.Type == req.Type &&
+		certificate.ProjectName == req.ProjectName &&
+		certificate.CarbonOffset.Equal(req.CarbonOffset) &&
+		certificate.CreditsUsed.Equal(req.CreditsUsed) &&
+		certificate.VintageYear == req.VintageYear
+}
+
+// GetCertificate retrieves a certificate by ID if the user owns it
 func (s *CertificateService) GetCertificate(ctx context.Context, certificateID uuid.UUID, userID string) (*CertificateResponse, error) {
 	certificate, err := s.certificateRepo.GetByID(ctx, certificateID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get certificate: %w", err)
 	}
 
-	// Check if user owns the certificate (or is admin)
-	if certificate.UserID != userID {
-		return nil, fmt.Errorf("certificate not found")
+	if err := checkCertificateOwner(certificate, userID); err != nil {
+		return nil, err
+	}
+
+	return s.certificateToResponse(certificate), nil
+}
+
+// GetAnyCertificate retrieves a certificate by ID regardless of its owner.
+// It is for admin support routes only.
+func (s *CertificateService) GetAnyCertificate(ctx context.Context, certificateID uuid.UUID) (*CertificateResponse, error) {
+	certificate, err := s.certificateRepo.GetByID(ctx, certificateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate: %w", err)
 	}
 
 	return s.certificateToResponse(certificate), nil
@@ -173,40 +351,91 @@ func (s *CertificateService) GetUserCertificates(ctx context.Context, userID str
 	return responses, total, nil
 }
 
-// VerifyCertificate verifies a certificate by certificate number
-func (s *CertificateService) VerifyCertificate(ctx context.Context, certificateNumber string) (*CertificateResponse, error) {
-	certificate, err := s.certificateRepo.GetByCertificateNumber(ctx, certificateNumber)
-	if err != nil {
-		return nil, fmt.Errorf("failed to verify certificate: %w", err)
+// CertificateListFilter narrows admin certificate listings. Empty fields
+// match any value.
+type CertificateListFilter struct {
+	Type        string
+	ProjectType string
+}
+
+// certificateStatuses are the statuses ListByStatus accepts
+var certificateStatuses = []string{
+	models.CertificateStatusPending,
+	models.CertificateStatusIssued,
+	models.CertificateStatusVerified,
+	models.CertificateStatusRetired,
+	models.CertificateStatusCancelled,
+	models.CertificateStatusExpired,
+	models.CertificateStatusRejected,
+}
+
+// ListAll retrieves certificates of every user and status, newest first.
+// It is for admin routes only.
+func (s *CertificateService) ListAll(ctx context.Context, filter CertificateListFilter, limit, offset int) ([]*CertificateResponse, int64, error) {
+	return s.listCertificates(ctx, repository.CertificateFilter{
+		Type:        filter.Type,
+		ProjectType: filter.ProjectType,
+	}, limit, offset)
+}
+
+// ListByStatus retrieves certificates of every user with the given status,
+// newest first. It is for admin routes only.
+func (s *CertificateService) ListByStatus(ctx context.Context, status string, filter CertificateListFilter, limit, offset int) ([]*CertificateResponse, int64, error) {
+	if !slices.Contains(certificateStatuses, status) {
+		return nil, 0, fmt.Errorf("%w: unknown status %q", ErrInvalidCertificateRequest, status)
 	}
 
-	// Check if certificate is valid
-	if certificate.IsExpired() {
-		return nil, fmt.Errorf("certificate has expired")
+	return s.listCertificates(ctx, repository.CertificateFilter{
+		Status:      status,
+		Type:        filter.Type,
+		ProjectType: filter.ProjectType,
+	}, limit, offset)
+}
+
+func (s *CertificateService) listCertificates(ctx context.Context, filter repository.CertificateFilter, limit, offset int) ([]*CertificateResponse, int64, error) {
+	certificates, total, err := s.certificateRepo.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list certificates: %w", err)
 	}
 
-	if certificate.IsRetired() {
-		return nil, fmt.Errorf("certificate has been retired")
+	responses := make([]*CertificateResponse, len(certificates))
+	for i, cert := range certificates {
+		responses[i] = s.certificateToResponse(cert)
 	}
 
-	return s.certificateToResponse(certificate), nil
+	return responses, total, nil
 }
 
-// RetireCertificate retires a certificate
+// RetireCertificate retires a certificate the user owns
 func (s *CertificateService) RetireCertificate(ctx context.Context, certificateID uuid.UUID, userID string) error {
 	certificate, err := s.certificateRepo.GetByID(ctx, certificateID)
 	if err != nil {
 		return fmt.Errorf("failed to get certificate: %w", err)
 	}
 
-	// Check if user owns the certificate
-	if certificate.UserID != userID {
-		return fmt.Errorf("certificate not found")
+	if err := checkCertificateOwner(certificate, userID); err != nil {
+		return err
+	}
+
+	return s.retireCertificate(ctx, certificate, userID)
+}
+
+// RetireAnyCertificate retires a certificate on its owner's behalf. actorID
+// is the admin making the change and is logged alongside the owner.
+func (s *CertificateService) RetireAnyCertificate(ctx context.Context, certificateID uuid.UUID, actorID string) error {
+	certificate, err := s.certificateRepo.GetByID(ctx, certificateID)
+	if err != nil {
+		return fmt.Errorf("failed to get certificate: %w", err)
 	}
 
+	return s.retireCertificate(ctx, certificate, actorID)
+}
+
+// retireCertificate retires an already authorized certificate
+func (s *CertificateService) retireCertificate(ctx context.Context, certificate *models.Certificate, actorID string) error {
 	// Check if certificate can be retired
 	if !certificate.CanTransfer() {
-		return fmt.Errorf("certificate cannot be retired")
+		return fmt.Errorf("%w: certificate cannot be retired", ErrCertificateNotActive)
 	}
 
 	// Update certificate status
@@ -220,6 +449,58 @@ func (s *CertificateService) RetireCertificate(ctx context.Context, certificateI
 
 	s.logger.LogInfo(ctx, "certificate retired",
 		logger.String("certificate_id", certificate.ID.String()),
+		logger.String("user_id", certificate.UserID),
+		logger.String("actor_id", actorID))
+
+	return nil
+}
+
+// recordCertificateIssued counts an issued certificate by type and project type
+func (s *CertificateService) recordCertificateIssued(certificate *models.Certificate) {
+	if s.metrics != nil {
+		s.metrics.RecordCertificateIssued(metricsServiceName, certificate.Type, certificate.ProjectType)
+	}
+}
+
+// checkCertificateOwner hides certificates the user does not own behind a
+// not found error, so their existence is not leaked
+func checkCertificateOwner(certificate *models.Certificate, userID string) error {
+	if certificate.UserID != userID {
+		return fmt.Errorf("certificate not found: %w", database.ErrNotFound)
+	}
+	return nil
+}
+
+// CheckErasable returns ErrActiveCertificates while the user still holds
+// issued or verified certificates
+func (s *CertificateService) CheckErasable(ctx context.Context, userID string) error {
+	active, err := s.certificateRepo.CountByUserIDAndStatus(ctx, userID, activeCertificateStatuses)
+	if err != nil {
+		return fmt.Errorf("failed to check active certificates: %w", err)
+	}
+	if active > 0 {
+		return fmt.Errorf("%w: %d still active", ErrActiveCertificates, active)
+	}
+	return nil
+}
+
+// EraseUserData anonymizes a deleted user's certificates. Erasure is refused
+// while the user still holds issued or verified certificates.
+func (s *CertificateService) EraseUserData(ctx context.Context, userID string) error {
+	if err := s.CheckErasable(ctx, userID); err != nil {
+		if errors.Is(err, ErrActiveCertificates) {
+			s.logger.LogWarn(ctx, "certificate erasure refused",
+				logger.String("user_id", userID),
+				logger.String("reason", err.Error()))
+		}
+		return err
+	}
+
+	if err := s.certificateRepo.AnonymizeUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to erase certificate data: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "certificate data anonymized for deleted user",
 		logger.String("user_id", userID))
 
 	return nil
@@ -281,6 +562,7 @@ func (s *CertificateService) certificateToResponse(cert *models.Certificate) *Ce
 	return &CertificateResponse{
 		ID:                cert.ID,
 		UserID:            cert.UserID,
+		HolderName:        cert.HolderName,
 		CertificateNumber: cert.CertificateNumber,
 		Type:              cert.Type,
 		Status:            cert.Status,
@@ -295,6 +577,8 @@ func (s *CertificateService) certificateToResponse(cert *models.Certificate) *Ce
 		SerialNumber:      cert.SerialNumber,
 		BlockchainTxHash:  cert.BlockchainTxHash,
 		TokenID:           cert.TokenID,
+		MetadataURI:       cert.MetadataURI,
+		MintStatus:        cert.MintStatus,
 		IssuedAt:          cert.IssuedAt,
 		ExpiresAt:         cert.ExpiresAt,
 		CreatedAt:         cert.CreatedAt,
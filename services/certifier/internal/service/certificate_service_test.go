@@ -2,17 +2,31 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/shopspring/decimal"
 	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
 )
 
-// MockCertificateRepository implements the repository interface for testing
+// MockCertificateRepository implements the repository interface for testing.
+// Its mutex stands in for the database transaction and project row lock.
 type MockCertificateRepository struct {
+	mu           sync.Mutex
 	certificates map[uuid.UUID]*models.Certificate
+	transfers    []*models.CertificateTransfer
+	projects     *MockProjectRepository
+	updateErr    error // returned by Update instead of saving when set
 }
 
 func NewMockCertificateRepository() *MockCertificateRepository {
@@ -22,12 +36,56 @@ func NewMockCertificateRepository() *MockCertificateRepository {
 }
 
 func (m *MockCertificateRepository) Create(ctx context.Context, certificate *models.Certificate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.create(certificate)
+}
+
+func (m *MockCertificateRepository) IssueWithProjectUpdate(ctx context.Context, certificate *models.Certificate, projectID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	project, exists := m.projects.projects[projectID]
+	if !exists {
+		return database.ErrNotFound
+	}
+	if !project.CanIssueCredits(certificate.CreditsUsed) {
+		return repository.ErrProjectCreditsUnavailable
+	}
+	if err := m.create(certificate); err != nil {
+		return err
+	}
+	project.AvailableCredits = project.AvailableCredits.Sub(certificate.CreditsUsed)
+	return nil
+}
+
+func (m *MockCertificateRepository) DeleteWithProjectRestore(ctx context.Context, certificate *models.Certificate, projectID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.certificates, certificate.ID)
+	if project, exists := m.projects.projects[projectID]; exists {
+		project.AvailableCredits = project.AvailableCredits.Add(certificate.CreditsUsed)
+	}
+	return nil
+}
+
+func (m *MockCertificateRepository) create(certificate *models.Certificate) error {
+	for _, cert := range m.certificates {
+		if cert.UserID == certificate.UserID && cert.IdempotencyKey != nil && certificate.IdempotencyKey != nil &&
+			*cert.IdempotencyKey == *certificate.IdempotencyKey {
+			return errors.New("duplicate idempotency key")
+		}
+	}
 	if certificate.ID == uuid.Nil {
 		certificate.ID = uuid.New()
 	}
 	certificate.CreatedAt = time.Now()
 	certificate.UpdatedAt = time.Now()
-	m.certificates[certificate.ID] = certificate
+	// Store a copy, like a database row, so later changes to certificate
+	// are only saved by Update
+	stored := *certificate
+	m.certificates[certificate.ID] = &stored
 	return nil
 }
 
@@ -49,12 +107,19 @@ func (m *MockCertificateRepository) GetByUserID(ctx context.Context, userID stri
 }
 
 func (m *MockCertificateRepository) Update(ctx context.Context, certificate *models.Certificate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.updateErr != nil {
+		return m.updateErr
+	}
 	certificate.UpdatedAt = time.Now()
 	m.certificates[certificate.ID] = certificate
 	return nil
 }
 
 func (m *MockCertificateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	delete(m.certificates, id)
 	return nil
 }
@@ -68,6 +133,99 @@ func (m *MockCertificateRepository) GetByCertificateNumber(ctx context.Context,
 	return nil, nil
 }
 
+func (m *MockCertificateRepository) GetByIdempotencyKey(ctx context.Context, userID, idempotencyKey string) (*models.Certificate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, cert := range m.certificates {
+		if cert.UserID == userID && cert.IdempotencyKey != nil && *cert.IdempotencyKey == idempotencyKey {
+			return cert, nil
+		}
+	}
+	return nil, database.ErrNotFound
+}
+
+func (m *MockCertificateRepository) CountByUserIDAndStatus(ctx context.Context, userID string, statuses []string) (int64, error) {
+	var count int64
+	for _, cert := range m.certificates {
+		for _, status := range statuses {
+			if cert.UserID == userID && cert.Status == status {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+func (m *MockCertificateRepository) AnonymizeUser(ctx context.Context, userID string) error {
+	return nil
+}
+
+func (m *MockCertificateRepository) GetByMintStatus(ctx context.Context, mintStatus string, limit int) ([]*models.Certificate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.Certificate
+	for _, cert := range m.certificates {
+		if cert.MintStatus == mintStatus && len(result) < limit {
+			result = append(result, cert)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockCertificateRepository) UpdateMint(ctx context.Context, certificate *models.Certificate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.certificates[certificate.ID] = certificate
+	return nil
+}
+
+func (m *MockCertificateRepository) GetExpired(ctx context.Context, now time.Time, limit int) ([]*models.Certificate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.Certificate
+	for _, cert := range m.certificates {
+		if cert.IsIssued() && cert.ExpiresAt != nil && cert.ExpiresAt.Before(now) {
+			result = append(result, cert)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ExpiresAt.Before(*result[j].ExpiresAt)
+	})
+	if limit < len(result) {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *MockCertificateRepository) GetStalePending(ctx context.Context, createdBefore time.Time, limit int) ([]*models.Certificate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.Certificate
+	for _, cert := range m.certificates {
+		if cert.Status == models.CertificateStatusPending && cert.CreatedAt.Before(createdBefore) {
+			result = append(result, cert)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	if limit < len(result) {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *MockCertificateRepository) Expire(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	certificate, exists := m.certificates[id]
+	if !exists || !certificate.IsIssued() {
+		return repository.ErrCertificateStatusChanged
+	}
+	certificate.Status = models.CertificateStatusExpired
+	return nil
+}
+
 func (m *MockCertificateRepository) GetByStatus(ctx context.Context, status string, limit, offset int) ([]*models.Certificate, int64, error) {
 	var result []*models.Certificate
 	for _, cert := range m.certificates {
@@ -78,11 +236,146 @@ func (m *MockCertificateRepository) GetByStatus(ctx context.Context, status stri
 	return result, int64(len(result)), nil
 }
 
+func (m *MockCertificateRepository) List(ctx context.Context, filter repository.CertificateFilter, limit, offset int) ([]*models.Certificate, int64, error) {
+	var matched []*models.Certificate
+	for _, cert := range m.certificates {
+		if (filter.Status == "" || cert.Status == filter.Status) &&
+			(filter.Type == "" || cert.Type == filter.Type) &&
+			(filter.ProjectType == "" || cert.ProjectType == filter.ProjectType) {
+			matched = append(matched, cert)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+
+	total := int64(len(matched))
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	matched = matched[offset:]
+	if limit < len(matched) {
+		matched = matched[:limit]
+	}
+	return matched, total, nil
+}
+
 func (m *MockCertificateRepository) CreateVerification(ctx context.Context, verification *models.CertificateVerification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	certificate, exists := m.certificates[verification.CertificateID]
+	if !exists {
+		return database.ErrNotFound
+	}
+	verification.CreatedAt = time.Now()
+	certificate.Verifications = append(certificate.Verifications, *verification)
 	return nil
 }
 
+func (m *MockCertificateRepository) ReviewVerification(ctx context.Context, verification *models.CertificateVerification, certificateStatus string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	certificate, exists := m.certificates[verification.CertificateID]
+	if !exists {
+		return repository.ErrCertificateStatusChanged
+	}
+	for i := range certificate.Verifications {
+		stored := &certificate.Verifications[i]
+		if stored.ID != verification.ID {
+			continue
+		}
+		if stored.Status != models.VerificationStatusPending {
+			return repository.ErrVerificationNotPending
+		}
+		if certificate.Status != models.CertificateStatusIssued {
+			return repository.ErrCertificateStatusChanged
+		}
+		*stored = *verification
+		certificate.Status = certificateStatus
+		return nil
+	}
+	return repository.ErrVerificationNotPending
+}
+
 func (m *MockCertificateRepository) CreateTransfer(ctx context.Context, transfer *models.CertificateTransfer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transfers = append(m.transfers, transfer)
+	return nil
+}
+
+func (m *MockCertificateRepository) TransferOwnership(ctx context.Context, transfer *models.CertificateTransfer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	certificate, exists := m.certificates[transfer.CertificateID]
+	if !exists || certificate.UserID != transfer.FromUserID {
+		return repository.ErrCertificateOwnerChanged
+	}
+	certificate.UserID = transfer.ToUserID
+	certificate.HolderName = ""
+	m.transfers = append(m.transfers, transfer)
+	return nil
+}
+
+func (m *MockCertificateRepository) GetTransfer(ctx context.Context, id uuid.UUID) (*models.CertificateTransfer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, transfer := range m.transfers {
+		if transfer.ID == id {
+			stored := *transfer
+			return &stored, nil
+		}
+	}
+	return nil, database.ErrNotFound
+}
+
+func (m *MockCertificateRepository) CompleteSale(ctx context.Context, transfer *models.CertificateTransfer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := m.pendingTransfer(transfer.ID)
+	if stored == nil {
+		return repository.ErrTransferNotPending
+	}
+	certificate, exists := m.certificates[transfer.CertificateID]
+	if !exists || certificate.UserID != transfer.FromUserID {
+		return repository.ErrCertificateOwnerChanged
+	}
+	stored.Status = transfer.Status
+	stored.TransferredAt = transfer.TransferredAt
+	certificate.UserID = transfer.ToUserID
+	certificate.HolderName = ""
+	return nil
+}
+
+func (m *MockCertificateRepository) FailTransfer(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, transfer := range m.transfers {
+		if transfer.ID == id && (transfer.Status == models.TransferStatusPending || transfer.Status == models.TransferStatusRefundPending) {
+			transfer.Status = models.TransferStatusFailed
+			return nil
+		}
+	}
+	return repository.ErrTransferNotPending
+}
+
+func (m *MockCertificateRepository) MarkTransferRefundPending(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := m.pendingTransfer(id)
+	if stored == nil {
+		return repository.ErrTransferNotPending
+	}
+	stored.Status = models.TransferStatusRefundPending
+	return nil
+}
+
+func (m *MockCertificateRepository) pendingTransfer(id uuid.UUID) *models.CertificateTransfer {
+	for _, transfer := range m.transfers {
+		if transfer.ID == id && transfer.Status == models.TransferStatusPending {
+			return transfer
+		}
+	}
 	return nil
 }
 
@@ -145,6 +438,16 @@ func (m *MockProjectRepository) GetActive(ctx context.Context, limit, offset int
 	return result, int64(len(result)), nil
 }
 
+func (m *MockProjectRepository) GetAvailable(ctx context.Context, currency string) ([]*models.CertificateProject, error) {
+	var result []*models.CertificateProject
+	for _, project := range m.projects {
+		if project.IsActive && project.Currency == currency && project.AvailableCredits.IsPositive() {
+			result = append(result, project)
+		}
+	}
+	return result, nil
+}
+
 func (m *MockProjectRepository) GetByType(ctx context.Context, projectType string, limit, offset int) ([]*models.CertificateProject, int64, error) {
 	var result []*models.CertificateProject
 	for _, project := range m.projects {
@@ -174,6 +477,303 @@ func (m *MockProjectRepository) UpdateAvailableCredits(ctx context.Context, proj
 	return nil
 }
 
+// MockTemplateRepository implements the template repository interface for testing
+type MockTemplateRepository struct {
+	templates map[string]*models.CertificateTemplate // by certificate type
+}
+
+func NewMockTemplateRepository() *MockTemplateRepository {
+	return &MockTemplateRepository{
+		templates: make(map[string]*models.CertificateTemplate),
+	}
+}
+
+func (m *MockTemplateRepository) GetActiveByType(ctx context.Context, certificateType string) (*models.CertificateTemplate, error) {
+	if template, exists := m.templates[certificateType]; exists && template.IsActive {
+		return template, nil
+	}
+	return nil, database.ErrNotFound
+}
+
+// MockWalletClient records debits against in-memory balances
+type MockWalletClient struct {
+	mu       sync.Mutex
+	balances map[string]decimal.Decimal
+	debits   map[string]decimal.Decimal // by reference ID
+	debitErr error                      // returned by DebitCredits instead of debiting when set
+
+	transferErrs map[string]error // returned by TransferCredits for a reference instead of transferring
+}
+
+func NewMockWalletClient() *MockWalletClient {
+	return &MockWalletClient{
+		balances: make(map[string]decimal.Decimal),
+		debits:   make(map[string]decimal.Decimal),
+	}
+}
+
+func (m *MockWalletClient) DebitCredits(ctx context.Context, userID string, amount decimal.Decimal, referenceID, description string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.debitErr != nil {
+		return m.debitErr
+	}
+	if _, exists := m.debits[referenceID]; exists {
+		return nil
+	}
+	if m.balances[userID].LessThan(amount) {
+		return ErrInsufficientWalletCredits
+	}
+	m.balances[userID] = m.balances[userID].Sub(amount)
+	m.debits[referenceID] = amount
+	return nil
+}
+
+func (m *MockWalletClient) TransferCredits(ctx context.Context, fromUserID, toUserID string, amount decimal.Decimal, referenceID, description string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.transferErrs[referenceID]; err != nil {
+		return err
+	}
+	if _, exists := m.debits[referenceID]; exists {
+		return nil
+	}
+	if m.balances[fromUserID].LessThan(amount) {
+		return ErrInsufficientWalletCredits
+	}
+	m.balances[fromUserID] = m.balances[fromUserID].Sub(amount)
+	m.balances[toUserID] = m.balances[toUserID].Add(amount)
+	m.debits[referenceID] = amount
+	return nil
+}
+
+// testPublicBaseURL is the public API base test services build links with
+const testPublicBaseURL = "https://greenledger.example/api/v1"
+
+// newIssueTestService returns a service with one project holding 100 credits
+func newIssueTestService() (*CertificateService, *MockCertificateRepository, *MockProjectRepository, *MockWalletClient) {
+	certificateRepo := NewMockCertificateRepository()
+	projectRepo := NewMockProjectRepository()
+	certificateRepo.projects = projectRepo
+	walletClient := NewMockWalletClient()
+
+	projectRepo.Create(context.Background(), &models.CertificateProject{
+		Name:             "Amazon Reforestation",
+		Type:             "reforestation",
+		IsActive:         true,
+		TotalCredits:     decimal.NewFromInt(100),
+		AvailableCredits: decimal.NewFromInt(100),
+	})
+
+	log := logger.New("error")
+	service := NewCertificateService(certificateRepo, projectRepo, NewMockTemplateRepository(), walletClient, nil, NewMockEventPublisher(log), testPublicBaseURL, nil, log)
+	return service, certificateRepo, projectRepo, walletClient
+}
+
+func newIssueRequest() *IssueCertificateRequest {
+	return &IssueCertificateRequest{
+		UserID:       "user-1",
+		Type:         models.CertificateTypeOffset,
+		CarbonOffset: decimal.NewFromFloat(1.5),
+		CreditsUsed:  decimal.NewFromInt(15),
+		ProjectName:  "Amazon Reforestation",
+		VintageYear:  2024,
+	}
+}
+
+func TestIssueCertificate_DebitsWallet(t *testing.T) {
+	service, certificateRepo, projectRepo, walletClient := newIssueTestService()
+	walletClient.balances["user-1"] = decimal.NewFromInt(20)
+
+	response, err := service.IssueCertificate(context.Background(), newIssueRequest())
+	if err != nil {
+		t.Fatalf("Expected certificate to be issued, got %v", err)
+	}
+
+	if response.Status != models.CertificateStatusIssued {
+		t.Errorf("Expected status %s, got %s", models.CertificateStatusIssued, response.Status)
+	}
+	if !walletClient.balances["user-1"].Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Expected 5 credits left in the wallet, got %s", walletClient.balances["user-1"])
+	}
+	if debit, exists := walletClient.debits[response.ID.String()]; !exists || !debit.Equal(decimal.NewFromInt(15)) {
+		t.Errorf("Expected a 15 credit debit referencing the certificate, got %v", walletClient.debits)
+	}
+	if len(certificateRepo.certificates) != 1 {
+		t.Errorf("Expected 1 certificate, got %d", len(certificateRepo.certificates))
+	}
+
+	project, _ := projectRepo.GetByName(context.Background(), "Amazon Reforestation")
+	if !project.AvailableCredits.Equal(decimal.NewFromInt(85)) {
+		t.Errorf("Expected 85 project credits left, got %s", project.AvailableCredits)
+	}
+}
+
+func TestIssueCertificate_RecordsMetrics(t *testing.T) {
+	service, _, _, walletClient := newIssueTestService()
+	metrics := monitoring.NewMetrics("certifier")
+	service.metrics = metrics
+	walletClient.balances["user-1"] = decimal.NewFromInt(20)
+
+	req := newIssueRequest()
+	if _, err := service.IssueCertificate(context.Background(), req); err != nil {
+		t.Fatalf("Expected certificate to be issued, got %v", err)
+	}
+
+	// Insufficient credits, so nothing is issued
+	if _, err := service.IssueCertificate(context.Background(), newIssueRequest()); err == nil {
+		t.Fatal("Expected the second certificate to fail")
+	}
+
+	if got := testutil.ToFloat64(metrics.CertificatesIssued.WithLabelValues("certifier", req.Type, "reforestation")); got != 1 {
+		t.Errorf("Expected 1 issued certificate, got %v", got)
+	}
+}
+
+func TestIssueCertificate_InsufficientWalletCreditsRollsBack(t *testing.T) {
+	service, certificateRepo, projectRepo, walletClient := newIssueTestService()
+	walletClient.balances["user-1"] = decimal.NewFromInt(10)
+
+	req := newIssueRequest()
+	req.IdempotencyKey = "retry-key"
+
+	if _, err := service.IssueCertificate(context.Background(), req); !errors.Is(err, ErrInsufficientWalletCredits) {
+		t.Fatalf("Expected ErrInsufficientWalletCredits, got %v", err)
+	}
+
+	if len(certificateRepo.certificates) != 0 {
+		t.Errorf("Expected the unpaid certificate to be deleted, got %d certificates", len(certificateRepo.certificates))
+	}
+	if !walletClient.balances["user-1"].Equal(decimal.NewFromInt(10)) {
+		t.Errorf("Expected the wallet to be untouched, got %s", walletClient.balances["user-1"])
+	}
+	project, _ := projectRepo.GetByName(context.Background(), "Amazon Reforestation")
+	if !project.AvailableCredits.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected project credits to be untouched, got %s", project.AvailableCredits)
+	}
+
+	// Once the wallet is topped up, retrying with the same key issues the certificate
+	walletClient.balances["user-1"] = decimal.NewFromInt(15)
+	response, err := service.IssueCertificate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected retry to issue the certificate, got %v", err)
+	}
+	if response.Status != models.CertificateStatusIssued {
+		t.Errorf("Expected status %s, got %s", models.CertificateStatusIssued, response.Status)
+	}
+}
+
+func TestIssueCertificate_RetryAfterWalletErrorIssuesCertificate(t *testing.T) {
+	service, certificateRepo, projectRepo, walletClient := newIssueTestService()
+	walletClient.balances["user-1"] = decimal.NewFromInt(20)
+	walletClient.debitErr = errors.New("wallet unavailable")
+
+	req := newIssueRequest()
+	req.IdempotencyKey = "retry-key"
+
+	if _, err := service.IssueCertificate(context.Background(), req); err == nil {
+		t.Fatal("Expected the wallet error to fail the request")
+	}
+
+	walletClient.debitErr = nil
+	response, err := service.IssueCertificate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected retry to issue the certificate, got %v", err)
+	}
+
+	if response.Status != models.CertificateStatusIssued || response.IssuedAt == nil {
+		t.Errorf("Expected status %s, got %s", models.CertificateStatusIssued, response.Status)
+	}
+	if stored := certificateRepo.certificates[response.ID]; stored.Status != models.CertificateStatusIssued {
+		t.Errorf("Expected the stored certificate to be issued, got %s", stored.Status)
+	}
+	if !walletClient.balances["user-1"].Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Expected the retry to debit 15 credits, got %s left", walletClient.balances["user-1"])
+	}
+	if len(certificateRepo.certificates) != 1 {
+		t.Errorf("Expected 1 certificate, got %d", len(certificateRepo.certificates))
+	}
+	project, _ := projectRepo.GetByName(context.Background(), "Amazon Reforestation")
+	if !project.AvailableCredits.Equal(decimal.NewFromInt(85)) {
+		t.Errorf("Expected 85 project credits left, got %s", project.AvailableCredits)
+	}
+}
+
+func TestIssueCertificate_RetryAfterUpdateErrorDoesNotDebitTwice(t *testing.T) {
+	service, certificateRepo, _, walletClient := newIssueTestService()
+	walletClient.balances["user-1"] = decimal.NewFromInt(40)
+	certificateRepo.updateErr = errors.New("database unavailable")
+
+	req := newIssueRequest()
+	req.IdempotencyKey = "retry-key"
+
+	if _, err := service.IssueCertificate(context.Background(), req); err == nil {
+		t.Fatal("Expected the update error to fail the request")
+	}
+
+	certificateRepo.updateErr = nil
+	response, err := service.IssueCertificate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected retry to issue the certificate, got %v", err)
+	}
+
+	if stored := certificateRepo.certificates[response.ID]; stored.Status != models.CertificateStatusIssued {
+		t.Errorf("Expected the stored certificate to be issued, got %s", stored.Status)
+	}
+	if !walletClient.balances["user-1"].Equal(decimal.NewFromInt(25)) {
+		t.Errorf("Expected the credits to be debited once, got %s left", walletClient.balances["user-1"])
+	}
+}
+
+func TestIssueCertificate_ConcurrentIssuesDoNotOversell(t *testing.T) {
+	service, certificateRepo, projectRepo, walletClient := newIssueTestService()
+
+	// 20 users each ask for 15 of the project's 100 credits
+	const requests = 20
+	for i := 0; i < requests; i++ {
+		walletClient.balances[fmt.Sprintf("user-%d", i)] = decimal.NewFromInt(15)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := newIssueRequest()
+			req.UserID = fmt.Sprintf("user-%d", i)
+			_, err := service.IssueCertificate(context.Background(), req)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	issued := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			issued++
+		case !errors.Is(err, ErrInsufficientProjectCredits):
+			t.Errorf("Expected ErrInsufficientProjectCredits, got %v", err)
+		}
+	}
+
+	if issued != 6 {
+		t.Errorf("Expected 6 certificates to fit in 100 credits, got %d", issued)
+	}
+	if len(certificateRepo.certificates) != issued {
+		t.Errorf("Expected %d saved certificates, got %d", issued, len(certificateRepo.certificates))
+	}
+	project, _ := projectRepo.GetByName(context.Background(), "Amazon Reforestation")
+	if !project.AvailableCredits.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("Expected 10 project credits left, got %s", project.AvailableCredits)
+	}
+	if len(walletClient.debits) != issued {
+		t.Errorf("Expected %d wallet debits, got %d", issued, len(walletClient.debits))
+	}
+}
+
 func TestCertificateModel_Creation(t *testing.T) {
 	certificate := &models.Certificate{
 		ID:                uuid.New(),
@@ -304,3 +904,158 @@ func TestCertificateProjectModel_CanIssueCredits(t *testing.T) {
 		t.Error("Expected project with insufficient credits to not allow issuance")
 	}
 }
+
+func TestActiveCertificateStatuses_MatchIsIssued(t *testing.T) {
+	statuses := []string{
+		models.CertificateStatusPending,
+		models.CertificateStatusIssued,
+		models.CertificateStatusVerified,
+		models.CertificateStatusRetired,
+		models.CertificateStatusCancelled,
+		models.CertificateStatusExpired,
+	}
+
+	for _, status := range statuses {
+		certificate := &models.Certificate{Status: status}
+
+		blocksErasure := false
+		for _, active := range activeCertificateStatuses {
+			if active == status {
+				blocksErasure = true
+			}
+		}
+
+		if blocksErasure != certificate.IsIssued() {
+			t.Errorf("Status %s: expected erasure blocked=%v to match IsIssued=%v", status, blocksErasure, certificate.IsIssued())
+		}
+	}
+}
+
+func TestMatchesIssueRequest(t *testing.T) {
+	certificate := &models.Certificate{
+		Type:         models.CertificateTypeOffset,
+		ProjectName:  "Amazon Reforestation",
+		CarbonOffset: decimal.NewFromFloat(1.5),
+		CreditsUsed:  decimal.NewFromFloat(15),
+		VintageYear:  2024,
+	}
+	req := &IssueCertificateRequest{
+		Type:           models.CertificateTypeOffset,
+		ProjectName:    "Amazon Reforestation",
+		CarbonOffset:   decimal.NewFromFloat(1.50),
+		CreditsUsed:    decimal.NewFromFloat(15),
+		VintageYear:    2024,
+		IdempotencyKey: "retry-key",
+	}
+
+	if !matchesIssueRequest(certificate, req) {
+		t.Error("Expected replayed request to match the issued certificate")
+	}
+
+	req.CreditsUsed = decimal.NewFromFloat(30)
+	if matchesIssueRequest(certificate, req) {
+		t.Error("Expected request with different credits not to match")
+	}
+}
+
+func TestBuildOffsetQuote_UsesCheapestProjectsFirst(t *testing.T) {
+	projects := []*models.CertificateProject{
+		{Name: "Expensive", IsActive: true, AvailableCredits: decimal.NewFromInt(100), PricePerCredit: decimal.NewFromInt(30)},
+		{Name: "Cheap", IsActive: true, AvailableCredits: decimal.NewFromInt(2), PricePerCredit: decimal.NewFromInt(10)},
+		{Name: "Inactive", IsActive: false, AvailableCredits: decimal.NewFromInt(100), PricePerCredit: decimal.NewFromInt(1)},
+		{Name: "Empty", IsActive: true, AvailableCredits: decimal.Zero, PricePerCredit: decimal.NewFromInt(5)},
+	}
+
+	items, err := buildOffsetQuote(projects, decimal.NewFromFloat(3.5))
+	if err != nil {
+		t.Fatalf("Expected quote to be covered, got %v", err)
+	}
+	if len(items) != 2 || items[0].ProjectName != "Cheap" || items[1].ProjectName != "Expensive" {
+		t.Fatalf("Expected Cheap then Expensive, got %+v", items)
+	}
+	if !items[0].Credits.Equal(decimal.NewFromInt(2)) || !items[1].Credits.Equal(decimal.NewFromFloat(1.5)) {
+		t.Errorf("Expected 2 + 1.5 credits, got %s + %s", items[0].Credits, items[1].Credits)
+	}
+	if !items[1].Cost.Equal(decimal.NewFromInt(45)) {
+		t.Errorf("Expected cost of 45 from the expensive project, got %s", items[1].Cost)
+	}
+}
+
+func TestBuildOffsetQuote_InsufficientInventory(t *testing.T) {
+	projects := []*models.CertificateProject{
+		{Name: "Small", IsActive: true, AvailableCredits: decimal.NewFromInt(1), PricePerCredit: decimal.NewFromInt(10)},
+	}
+
+	if _, err := buildOffsetQuote(projects, decimal.NewFromInt(2)); !errors.Is(err, ErrInsufficientProjectCredits) {
+		t.Errorf("Expected ErrInsufficientProjectCredits, got %v", err)
+	}
+}
+
+func TestCheckCertificateOwner(t *testing.T) {
+	certificate := &models.Certificate{UserID: "user-1"}
+
+	if err := checkCertificateOwner(certificate, "user-1"); err != nil {
+		t.Errorf("Expected owner to have access, got %v", err)
+	}
+
+	if err := checkCertificateOwner(certificate, "user-2"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for another user, got %v", err)
+	}
+}
+
+func TestCertificateValidity(t *testing.T) {
+	now := time.Now().UTC()
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		certificate *models.Certificate
+		want        string
+	}{
+		{"issued", &models.Certificate{Status: models.CertificateStatusIssued}, CertificateValidityActive},
+		{"verified", &models.Certificate{Status: models.CertificateStatusVerified}, CertificateValidityActive},
+		{"retired", &models.Certificate{Status: models.CertificateStatusRetired, ExpiresAt: &past}, CertificateValidityRetired},
+		{"past expiry", &models.Certificate{Status: models.CertificateStatusIssued, ExpiresAt: &past}, CertificateValidityExpired},
+		{"pending", &models.Certificate{Status: models.CertificateStatusPending}, CertificateValidityInvalid},
+		{"cancelled", &models.Certificate{Status: models.CertificateStatusCancelled}, CertificateValidityInvalid},
+	}
+
+	for _, tt := range tests {
+		if got := certificateValidity(tt.certificate, now); got != tt.want {
+			t.Errorf("%s: expected validity %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestCertificateToVerificationResponse(t *testing.T) {
+	now := time.Now().UTC()
+	certificate := &models.Certificate{
+		UserID:            "owner-1",
+		CertificateNumber: "GL-offset-forestry-1",
+		Status:            models.CertificateStatusRetired,
+		ProjectName:       "Amazon Reforestation",
+		Standard:          models.StandardVCS,
+		RetiredAt:         &now,
+		Verifications: []models.CertificateVerification{
+			{VerifierID: "admin-2", VerifierName: "Second", Status: models.VerificationStatusApproved, VerifiedAt: now},
+			{VerifierID: "admin-1", VerifierName: "First", Status: models.VerificationStatusPending, VerifiedAt: now.Add(-time.Hour)},
+		},
+	}
+	project := &models.CertificateProject{Name: "Amazon Reforestation", Methodology: "VM0007"}
+
+	response := certificateToVerificationResponse(certificate, project, now)
+
+	if response.Validity != CertificateValidityRetired {
+		t.Errorf("Expected a retired certificate to verify as retired, got %q", response.Validity)
+	}
+	if response.Project.Methodology != "VM0007" || response.Project.Standard != models.StandardVCS {
+		t.Errorf("Expected project standard and methodology, got %+v", response.Project)
+	}
+	if len(response.Verifications) != 2 || response.Verifications[0].VerifierName != "First" {
+		t.Errorf("Expected verification history oldest first, got %+v", response.Verifications)
+	}
+
+	if response := certificateToVerificationResponse(certificate, nil, now); response.Project.Name != "Amazon Reforestation" {
+		t.Errorf("Expected certificate project details without a project, got %+v", response.Project)
+	}
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// TransferCurrencyCredits is the currency of sales, which are paid in wallet credits
+const TransferCurrencyCredits = "credits"
+
+// ErrInvalidTransferRequest is returned when a transfer request fails validation
+var ErrInvalidTransferRequest = errors.New("invalid transfer request")
+
+// ErrTransferNotPending is returned when accepting a transfer that is not a
+// pending sale offer
+var ErrTransferNotPending = errors.New("transfer not pending")
+
+// TransferCertificateRequest represents a request to transfer a certificate
+type TransferCertificateRequest struct {
+	ToUserID     string          `json:"to_user_id" binding:"required"`
+	TransferType string          `json:"transfer_type" binding:"required"`
+	Price        decimal.Decimal `json:"price"` // in credits, sales only
+}
+
+// CertificateTransferResponse represents a certificate transfer in API responses
+type CertificateTransferResponse struct {
+	ID            uuid.UUID       `json:"id"`
+	CertificateID uuid.UUID       `json:"certificate_id"`
+	FromUserID    string          `json:"from_user_id"`
+	ToUserID      string          `json:"to_user_id"`
+	TransferType  string          `json:"transfer_type"`
+	Price         decimal.Decimal `json:"price"`
+	Currency      string          `json:"currency,omitempty"`
+	Status        string          `json:"status"`
+	TransferredAt *time.Time      `json:"transferred_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// TransferCertificate gives a certificate fromUserID owns to toUserID. Gifts
+// are free and change ownership immediately. Sales only list the certificate
+// for toUserID at price and return the pending offer; nothing is paid until
+// the buyer accepts it with AcceptCertificateSale. Retired and expired
+// certificates cannot be transferred.
+func (s *CertificateService) TransferCertificate(ctx context.Context, certificateID uuid.UUID, fromUserID, toUserID, transferType string, price decimal.Decimal) (*CertificateTransferResponse, error) {
+	if err := validateTransfer(fromUserID, toUserID, transferType, price); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransferRequest, err)
+	}
+
+	certificate, err := s.certificateRepo.GetByID(ctx, certificateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate: %w", err)
+	}
+
+	if err := checkCertificateOwner(certificate, fromUserID); err != nil {
+		return nil, err
+	}
+
+	if !certificate.CanTransfer() {
+		return nil, fmt.Errorf("%w: certificate cannot be transferred", ErrCertificateNotActive)
+	}
+
+	transfer := &models.CertificateTransfer{
+		ID:            uuid.New(),
+		CertificateID: certificate.ID,
+		FromUserID:    fromUserID,
+		ToUserID:      toUserID,
+		TransferType:  transferType,
+		Price:         price,
+	}
+
+	if transferType == models.TransferTypeSale {
+		transfer.Currency = TransferCurrencyCredits
+		transfer.Status = models.TransferStatusPending
+
+		if err := s.certificateRepo.CreateTransfer(ctx, transfer); err != nil {
+			return nil, fmt.Errorf("failed to create sale offer: %w", err)
+		}
+
+		s.logger.LogInfo(ctx, "certificate offered for sale",
+			logger.String("certificate_id", certificate.ID.String()),
+			logger.String("transfer_id", transfer.ID.String()),
+			logger.String("from_user_id", fromUserID),
+			logger.String("to_user_id", toUserID))
+
+		return transferToResponse(transfer), nil
+	}
+
+	now := time.Now().UTC()
+	transfer.Status = models.TransferStatusCompleted
+	transfer.TransferredAt = &now
+
+	if err := s.certificateRepo.TransferOwnership(ctx, transfer); err != nil {
+		if errors.Is(err, repository.ErrCertificateOwnerChanged) {
+			return nil, fmt.Errorf("certificate not found: %w", database.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to transfer certificate: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "certificate transferred",
+		logger.String("certificate_id", certificate.ID.String()),
+		logger.String("transfer_id", transfer.ID.String()),
+		logger.String("transfer_type", transferType),
+		logger.String("from_user_id", fromUserID),
+		logger.String("to_user_id", toUserID))
+
+	return transferToResponse(transfer), nil
+}
+
+// AcceptCertificateSale completes a sale offered to buyerID: the listed price
+// moves from the buyer's wallet to the seller's, with the transfer ID as the
+// wallet reference, and the certificate passes to the buyer. Offers made to
+// other users are reported as not found. If the seller no longer owns the
+// certificate, the payment is refunded and the offer fails; if the refund
+// cannot be made the offer is left refund pending, and accepting it again
+// retries the refund.
+func (s *CertificateService) AcceptCertificateSale(ctx context.Context, transferID uuid.UUID, buyerID string) (*CertificateTransferResponse, error) {
+	transfer, err := s.certificateRepo.GetTransfer(ctx, transferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	if transfer.ToUserID != buyerID {
+		return nil, fmt.Errorf("transfer not found: %w", database.ErrNotFound)
+	}
+
+	if transfer.TransferType == models.TransferTypeSale && transfer.Status == models.TransferStatusRefundPending {
+		if err := s.refundSale(ctx, transfer); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: seller no longer owns the certificate", ErrTransferNotPending)
+	}
+
+	if transfer.TransferType != models.TransferTypeSale || transfer.Status != models.TransferStatusPending {
+		return nil, fmt.Errorf("%w: transfer is %s", ErrTransferNotPending, transfer.Status)
+	}
+
+	certificate, err := s.certificateRepo.GetByID(ctx, transfer.CertificateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate: %w", err)
+	}
+
+	if !certificate.CanTransfer() {
+		return nil, fmt.Errorf("%w: certificate cannot be transferred", ErrCertificateNotActive)
+	}
+
+	description := fmt.Sprintf("Purchase of certificate %s", certificate.CertificateNumber)
+	if err := s.walletClient.TransferCredits(ctx, buyerID, transfer.FromUserID, transfer.Price, transfer.ID.String(), description); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	transfer.Status = models.TransferStatusCompleted
+	transfer.TransferredAt = &now
+
+	if err := s.certificateRepo.CompleteSale(ctx, transfer); err != nil {
+		if errors.Is(err, repository.ErrTransferNotPending) {
+			// Accepted concurrently; the wallet took one payment for the
+			// shared reference, which the winning accept accounted for
+			return nil, fmt.Errorf("%w: transfer already accepted", ErrTransferNotPending)
+		}
+		if errors.Is(err, repository.ErrCertificateOwnerChanged) {
+			if err := s.refundSale(ctx, transfer); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: seller no longer owns the certificate", ErrTransferNotPending)
+		}
+		return nil, fmt.Errorf("failed to complete certificate sale: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "certificate transferred",
+		logger.String("certificate_id", certificate.ID.String()),
+		logger.String("transfer_id", transfer.ID.String()),
+		logger.String("transfer_type", transfer.TransferType),
+		logger.String("from_user_id", transfer.FromUserID),
+		logger.String("to_user_id", buyerID))
+
+	return transferToResponse(transfer), nil
+}
+
+// refundSale returns a sale's payment to the buyer after the certificate
+// could not be handed over, and marks the offer failed. If the wallet cannot
+// make the refund the offer is marked refund pending instead, so it can be
+// retried; the refund's own wallet reference keeps a retry from paying twice.
+func (s *CertificateService) refundSale(ctx context.Context, transfer *models.CertificateTransfer) error {
+	transfer.TransferredAt = nil
+
+	description := fmt.Sprintf("Refund of certificate transfer %s", transfer.ID)
+	if err := s.walletClient.TransferCredits(ctx, transfer.FromUserID, transfer.ToUserID, transfer.Price, transfer.ID.String()+":refund", description); err != nil {
+		s.logger.LogError(ctx, "failed to refund certificate sale", err,
+			logger.String("transfer_id", transfer.ID.String()),
+			logger.String("buyer_id", transfer.ToUserID))
+
+		transfer.Status = models.TransferStatusRefundPending
+		if markErr := s.certificateRepo.MarkTransferRefundPending(ctx, transfer.ID); markErr != nil &&
+			!errors.Is(markErr, repository.ErrTransferNotPending) {
+			// Still pending, so accepting again pays idempotently and
+			// reaches the refund the same way
+			s.logger.LogError(ctx, "failed to record refund pending certificate transfer", markErr,
+				logger.String("transfer_id", transfer.ID.String()))
+		}
+		return fmt.Errorf("failed to refund certificate sale: %w", err)
+	}
+
+	transfer.Status = models.TransferStatusFailed
+	if err := s.certificateRepo.FailTransfer(ctx, transfer.ID); err != nil {
+		s.logger.LogError(ctx, "failed to record failed certificate transfer", err,
+			logger.String("transfer_id", transfer.ID.String()))
+	}
+	return nil
+}
+
+// validateTransfer checks the parties and price of a transfer. Gifts are free
+// and sales must have a price.
+func validateTransfer(fromUserID, toUserID, transferType string, price decimal.Decimal) error {
+	if toUserID == "" || toUserID == fromUserID {
+		return fmt.Errorf("recipient must be another user")
+	}
+
+	switch transferType {
+	case models.TransferTypeGift:
+		if !price.IsZero() {
+			return fmt.Errorf("gifts cannot have a price")
+		}
+	case models.TransferTypeSale:
+		if !price.IsPositive() {
+			return fmt.Errorf("sales must have a positive price")
+		}
+	default:
+		return fmt.Errorf("transfer type must be %s or %s", models.TransferTypeGift, models.TransferTypeSale)
+	}
+
+	return nil
+}
+
+func transferToResponse(transfer *models.CertificateTransfer) *CertificateTransferResponse {
+	return &CertificateTransferResponse{
+		ID:            transfer.ID,
+		CertificateID: transfer.CertificateID,
+		FromUserID:    transfer.FromUserID,
+		ToUserID:      transfer.ToUserID,
+		TransferType:  transfer.TransferType,
+		Price:         transfer.Price,
+		Currency:      transfer.Currency,
+		Status:        transfer.Status,
+		TransferredAt: transfer.TransferredAt,
+		CreatedAt:     transfer.CreatedAt,
+	}
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+)
+
+// issueTransferTestCertificate issues a certificate to user-1
+func issueTransferTestCertificate(t *testing.T) (*CertificateService, *MockCertificateRepository, *MockWalletClient, uuid.UUID) {
+	t.Helper()
+
+	service, certificateRepo, _, walletClient := newIssueTestService()
+	walletClient.balances["user-1"] = decimal.NewFromInt(15)
+
+	response, err := service.IssueCertificate(context.Background(), newIssueRequest())
+	if err != nil {
+		t.Fatalf("Expected certificate to be issued, got %v", err)
+	}
+
+	return service, certificateRepo, walletClient, response.ID
+}
+
+func TestTransferCertificate_Gift(t *testing.T) {
+	service, certificateRepo, walletClient, certificateID := issueTransferTestCertificate(t)
+	certificateRepo.certificates[certificateID].HolderName = "User One"
+
+	transfer, err := service.TransferCertificate(context.Background(), certificateID, "user-1", "user-2", models.TransferTypeGift, decimal.Zero)
+	if err != nil {
+		t.Fatalf("Expected gift to succeed, got %v", err)
+	}
+
+	if transfer.Status != models.TransferStatusCompleted || transfer.TransferredAt == nil {
+		t.Errorf("Expected a completed transfer, got %s", transfer.Status)
+	}
+	if owner := certificateRepo.certificates[certificateID].UserID; owner != "user-2" {
+		t.Errorf("Expected user-2 to own the certificate, got %s", owner)
+	}
+	if holder := certificateRepo.certificates[certificateID].HolderName; holder != "" {
+		t.Errorf("Expected the previous holder's name to be cleared, got %q", holder)
+	}
+	if len(certificateRepo.transfers) != 1 || certificateRepo.transfers[0].ID != transfer.ID {
+		t.Errorf("Expected the transfer to be recorded, got %d transfers", len(certificateRepo.transfers))
+	}
+	if _, paid := walletClient.debits[transfer.ID.String()]; paid {
+		t.Error("Expected a gift not to move any credits")
+	}
+
+	// The previous owner can no longer transfer it
+	if _, err := service.TransferCertificate(context.Background(), certificateID, "user-1", "user-3", models.TransferTypeGift, decimal.Zero); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for the previous owner, got %v", err)
+	}
+}
+
+func TestTransferCertificate_Sale(t *testing.T) {
+	service, certificateRepo, walletClient, certificateID := issueTransferTestCertificate(t)
+	walletClient.balances["user-2"] = decimal.NewFromInt(20)
+
+	offer, err := service.TransferCertificate(context.Background(), certificateID, "user-1", "user-2", models.TransferTypeSale, decimal.NewFromInt(12))
+	if err != nil {
+		t.Fatalf("Expected sale offer to succeed, got %v", err)
+	}
+
+	if offer.Status != models.TransferStatusPending || offer.TransferredAt != nil {
+		t.Errorf("Expected a pending offer, got %s", offer.Status)
+	}
+	if offer.Currency != TransferCurrencyCredits || !offer.Price.Equal(decimal.NewFromInt(12)) {
+		t.Errorf("Expected a price of 12 credits, got %s %s", offer.Price, offer.Currency)
+	}
+	if owner := certificateRepo.certificates[certificateID].UserID; owner != "user-1" {
+		t.Errorf("Expected user-1 to keep the certificate until the offer is accepted, got %s", owner)
+	}
+	if !walletClient.balances["user-2"].Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Expected offering a sale not to charge the buyer, got balance %s", walletClient.balances["user-2"])
+	}
+
+	transfer, err := service.AcceptCertificateSale(context.Background(), offer.ID, "user-2")
+	if err != nil {
+		t.Fatalf("Expected sale to be accepted, got %v", err)
+	}
+
+	if transfer.Status != models.TransferStatusCompleted || transfer.TransferredAt == nil {
+		t.Errorf("Expected a completed transfer, got %s", transfer.Status)
+	}
+	if owner := certificateRepo.certificates[certificateID].UserID; owner != "user-2" {
+		t.Errorf("Expected user-2 to own the certificate, got %s", owner)
+	}
+	if !walletClient.balances["user-2"].Equal(decimal.NewFromInt(8)) || !walletClient.balances["user-1"].Equal(decimal.NewFromInt(12)) {
+		t.Errorf("Expected the buyer to pay the seller 12 credits, got buyer %s seller %s",
+			walletClient.balances["user-2"], walletClient.balances["user-1"])
+	}
+	if _, paid := walletClient.debits[transfer.ID.String()]; !paid {
+		t.Error("Expected the payment to reference the transfer")
+	}
+
+	// An accepted offer cannot be accepted again
+	if _, err := service.AcceptCertificateSale(context.Background(), offer.ID, "user-2"); !errors.Is(err, ErrTransferNotPending) {
+		t.Errorf("Expected ErrTransferNotPending, got %v", err)
+	}
+	if !walletClient.balances["user-2"].Equal(decimal.NewFromInt(8)) {
+		t.Errorf("Expected the buyer to pay once, got balance %s", walletClient.balances["user-2"])
+	}
+}
+
+func TestAcceptCertificateSale_OnlyBuyerCanAccept(t *testing.T) {
+	service, certificateRepo, walletClient, certificateID := issueTransferTestCertificate(t)
+	walletClient.balances["user-2"] = decimal.NewFromInt(20)
+	walletClient.balances["user-3"] = decimal.NewFromInt(20)
+
+	offer, err := service.TransferCertificate(context.Background(), certificateID, "user-1", "user-2", models.TransferTypeSale, decimal.NewFromInt(12))
+	if err != nil {
+		t.Fatalf("Expected sale offer to succeed, got %v", err)
+	}
+
+	for _, userID := range []string{"user-3", "user-1"} {
+		if _, err := service.AcceptCertificateSale(context.Background(), offer.ID, userID); !errors.Is(err, database.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for %s, got %v", userID, err)
+		}
+	}
+
+	if !walletClient.balances["user-2"].Equal(decimal.NewFromInt(20)) || !walletClient.balances["user-3"].Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Expected no wallet to be charged, got user-2 %s user-3 %s",
+			walletClient.balances["user-2"], walletClient.balances["user-3"])
+	}
+	if owner := certificateRepo.certificates[certificateID].UserID; owner != "user-1" {
+		t.Errorf("Expected user-1 to keep the certificate, got %s", owner)
+	}
+}
+
+func TestAcceptCertificateSale_BuyerCannotPay(t *testing.T) {
+	service, certificateRepo, walletClient, certificateID := issueTransferTestCertificate(t)
+	walletClient.balances["user-2"] = decimal.NewFromInt(5)
+
+	offer, err := service.TransferCertificate(context.Background(), certificateID, "user-1", "user-2", models.TransferTypeSale, decimal.NewFromInt(12))
+	if err != nil {
+		t.Fatalf("Expected sale offer to succeed, got %v", err)
+	}
+
+	_, err = service.AcceptCertificateSale(context.Background(), offer.ID, "user-2")
+	if !errors.Is(err, ErrInsufficientWalletCredits) {
+		t.Fatalf("Expected ErrInsufficientWalletCredits, got %v", err)
+	}
+
+	if owner := certificateRepo.certificates[certificateID].UserID; owner != "user-1" {
+		t.Errorf("Expected user-1 to keep the certificate, got %s", owner)
+	}
+	if status := certificateRepo.transfers[0].Status; status != models.TransferStatusPending {
+		t.Errorf("Expected the offer to stay pending, got %s", status)
+	}
+}
+
+func TestAcceptCertificateSale_SellerNoLongerOwnsCertificate(t *testing.T) {
+	service, certificateRepo, walletClient, certificateID := issueTransferTestCertificate(t)
+	walletClient.balances["user-2"] = decimal.NewFromInt(20)
+
+	offer, err := service.TransferCertificate(context.Background(), certificateID, "user-1", "user-2", models.TransferTypeSale, decimal.NewFromInt(12))
+	if err != nil {
+		t.Fatalf("Expected sale offer to succeed, got %v", err)
+	}
+	if _, err := service.TransferCertificate(context.Background(), certificateID, "user-1", "user-3", models.TransferTypeGift, decimal.Zero); err != nil {
+		t.Fatalf("Expected gift to succeed, got %v", err)
+	}
+
+	if _, err := service.AcceptCertificateSale(context.Background(), offer.ID, "user-2"); !errors.Is(err, ErrTransferNotPending) {
+		t.Fatalf("Expected ErrTransferNotPending, got %v", err)
+	}
+
+	if owner := certificateRepo.certificates[certificateID].UserID; owner != "user-3" {
+		t.Errorf("Expected user-3 to keep the certificate, got %s", owner)
+	}
+	if !walletClient.balances["user-2"].Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Expected the buyer to be refunded, got balance %s", walletClient.balances["user-2"])
+	}
+	if status := certificateRepo.transfers[0].Status; status != models.TransferStatusFailed {
+		t.Errorf("Expected the offer to fail, got %s", status)
+	}
+}
+
+func TestAcceptCertificateSale_RefundFailureIsRetried(t *testing.T) {
+	service, certificateRepo, walletClient, certificateID := issueTransferTestCertificate(t)
+	walletClient.balances["user-2"] = decimal.NewFromInt(20)
+
+	offer, err := service.TransferCertificate(context.Background(), certificateID, "user-1", "user-2", models.TransferTypeSale, decimal.NewFromInt(12))
+	if err != nil {
+		t.Fatalf("Expected sale offer to succeed, got %v", err)
+	}
+	if _, err := service.TransferCertificate(context.Background(), certificateID, "user-1", "user-3", models.TransferTypeGift, decimal.Zero); err != nil {
+		t.Fatalf("Expected gift to succeed, got %v", err)
+	}
+
+	walletUnavailable := errors.New("wallet unavailable")
+	walletClient.transferErrs = map[string]error{offer.ID.String() + ":refund": walletUnavailable}
+	if _, err := service.AcceptCertificateSale(context.Background(), offer.ID, "user-2"); !errors.Is(err, walletUnavailable) {
+		t.Fatalf("Expected the refund error, got %v", err)
+	}
+	if status := certificateRepo.transfers[0].Status; status != models.TransferStatusRefundPending {
+		t.Errorf("Expected the offer to wait for its refund, got %s", status)
+	}
+	if !walletClient.balances["user-2"].Equal(decimal.NewFromInt(8)) {
+		t.Errorf("Expected the buyer to have paid, got balance %s", walletClient.balances["user-2"])
+	}
+
+	// Accepting again retries only the refund
+	walletClient.transferErrs = nil
+	for i := 0; i < 2; i++ {
+		if _, err := service.AcceptCertificateSale(context.Background(), offer.ID, "user-2"); !errors.Is(err, ErrTransferNotPending) {
+			t.Fatalf("Expected ErrTransferNotPending, got %v", err)
+		}
+	}
+
+	if !walletClient.balances["user-2"].Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Expected the buyer to be refunded once, got balance %s", walletClient.balances["user-2"])
+	}
+	if !walletClient.balances["user-1"].IsZero() {
+		t.Errorf("Expected the seller to keep nothing, got balance %s", walletClient.balances["user-1"])
+	}
+	if status := certificateRepo.transfers[0].Status; status != models.TransferStatusFailed {
+		t.Errorf("Expected the offer to fail, got %s", status)
+	}
+}
+
+func TestTransferCertificate_RejectsInactiveCertificates(t *testing.T) {
+	past := time.Now().UTC().Add(-time.Hour)
+	tests := []struct {
+		name   string
+		modify func(*models.Certificate)
+	}{
+		{"retired", func(c *models.Certificate) { c.Status = models.CertificateStatusRetired }},
+		{"expired", func(c *models.Certificate) { c.ExpiresAt = &past }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, certificateRepo, _, certificateID := issueTransferTestCertificate(t)
+			tt.modify(certificateRepo.certificates[certificateID])
+
+			_, err := service.TransferCertificate(context.Background(), certificateID, "user-1", "user-2", models.TransferTypeGift, decimal.Zero)
+			if !errors.Is(err, ErrCertificateNotActive) {
+				t.Errorf("Expected ErrCertificateNotActive, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateTransfer(t *testing.T) {
+	tests := []struct {
+		name         string
+		toUserID     string
+		transferType string
+		price        decimal.Decimal
+		valid        bool
+	}{
+		{"gift", "user-2", models.TransferTypeGift, decimal.Zero, true},
+		{"sale", "user-2", models.TransferTypeSale, decimal.NewFromInt(10), true},
+		{"gift with price", "user-2", models.TransferTypeGift, decimal.NewFromInt(10), false},
+		{"free sale", "user-2", models.TransferTypeSale, decimal.Zero, false},
+		{"to self", "user-1", models.TransferTypeGift, decimal.Zero, false},
+		{"unsupported type", "user-2", models.TransferTypeRetire, decimal.Zero, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTransfer("user-1", tt.toUserID, tt.transferType, tt.price)
+			if (err == nil) != tt.valid {
+				t.Errorf("Expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// Certificate validity reported by public verification
+const (
+	CertificateValidityActive  = "active"
+	CertificateValidityRetired = "retired"
+	CertificateValidityExpired = "expired"
+	// CertificateValidityInvalid covers certificates that were never issued
+	// or were cancelled
+	CertificateValidityInvalid = "invalid"
+)
+
+// CertificateVerificationResponse is the public view of a certificate used by
+// third parties to check an offset claim. It never includes the owner.
+type CertificateVerificationResponse struct {
+	CertificateNumber string               `json:"certificate_number"`
+	Type              string               `json:"type"`
+	Status            string               `json:"status"`
+	Validity          string               `json:"validity"`
+	CarbonOffset      decimal.Decimal      `json:"carbon_offset"`
+	VintageYear       int                  `json:"vintage_year"`
+	SerialNumber      string               `json:"serial_number"`
+	Project           VerifiedProject      `json:"project"`
+	BlockchainTxHash  string               `json:"blockchain_tx_hash"`
+	BlockchainNetwork string               `json:"blockchain_network"`
+	TokenID           string               `json:"token_id"`
+	IssuedAt          *time.Time           `json:"issued_at"`
+	ExpiresAt         *time.Time           `json:"expires_at"`
+	RetiredAt         *time.Time           `json:"retired_at"`
+	Verifications     []VerificationRecord `json:"verifications"`
+	VerifiedAt        time.Time            `json:"verified_at"`
+}
+
+// VerifiedProject describes the project behind a verified certificate
+type VerifiedProject struct {
+	Name             string `json:"name"`
+	Type             string `json:"type"`
+	Location         string `json:"location"`
+	Country          string `json:"country,omitempty"`
+	Developer        string `json:"developer,omitempty"`
+	Standard         string `json:"standard"`
+	Methodology      string `json:"methodology,omitempty"`
+	VerificationBody string `json:"verification_body"`
+}
+
+// VerificationRecord is a public entry in a certificate's verification history
+type VerificationRecord struct {
+	VerifierName string    `json:"verifier_name"`
+	Status       string    `json:"status"`
+	Comments     string    `json:"comments,omitempty"`
+	VerifiedAt   time.Time `json:"verified_at"`
+}
+
+// VerifyCertificate looks up a certificate by number for public verification.
+// Retired and expired certificates are reported with their validity rather
+// than as errors, since a retirement is what an offset claim relies on.
+func (s *CertificateService) VerifyCertificate(ctx context.Context, certificateNumber string) (*CertificateVerificationResponse, error) {
+	certificate, err := s.certificateRepo.GetByCertificateNumber(ctx, certificateNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify certificate: %w", err)
+	}
+
+	// The certificate keeps a copy of the project details it was issued
+	// under; the methodology and developer are only held on the project
+	project, err := s.projectRepo.GetByName(ctx, certificate.ProjectName)
+	if err != nil {
+		if !errors.Is(err, database.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get certificate project: %w", err)
+		}
+		s.logger.LogWarn(ctx, "verified certificate has no project",
+			logger.String("certificate_number", certificateNumber),
+			logger.String("project_name", certificate.ProjectName))
+	}
+
+	return certificateToVerificationResponse(certificate, project, time.Now().UTC()), nil
+}
+
+// certificateValidity reports whether a certificate can currently back an
+// offset claim, as of now
+func certificateValidity(certificate *models.Certificate, now time.Time) string {
+	switch {
+	case certificate.IsRetired():
+		return CertificateValidityRetired
+	case certificate.Status == models.CertificateStatusExpired,
+		certificate.ExpiresAt != nil && now.After(*certificate.ExpiresAt):
+		return CertificateValidityExpired
+	case certificate.IsIssued():
+		return CertificateValidityActive
+	default:
+		return CertificateValidityInvalid
+	}
+}
+
+// certificateToVerificationResponse builds the public verification view of a
+// certificate. project may be nil if the project no longer exists.
+func certificateToVerificationResponse(certificate *models.Certificate, project *models.CertificateProject, now time.Time) *CertificateVerificationResponse {
+	verifications := make([]VerificationRecord, len(certificate.Verifications))
+	for i, verification := range certificate.Verifications {
+		verifications[i] = VerificationRecord{
+			VerifierName: verification.VerifierName,
+			Status:       verification.Status,
+			Comments:     verification.Comments,
+			VerifiedAt:   verification.VerifiedAt,
+		}
+	}
+	sort.SliceStable(verifications, func(i, j int) bool {
+		return verifications[i].VerifiedAt.Before(verifications[j].VerifiedAt)
+	})
+
+	verifiedProject := VerifiedProject{
+		Name:             certificate.ProjectName,
+		Type:             certificate.ProjectType,
+		Location:         certificate.ProjectLocation,
+		Standard:         certificate.Standard,
+		VerificationBody: certificate.VerificationBody,
+	}
+	if project != nil {
+		verifiedProject.Country = project.Country
+		verifiedProject.Developer = project.Developer
+		verifiedProject.Methodology = project.Methodology
+	}
+
+	return &CertificateVerificationResponse{
+		CertificateNumber: certificate.CertificateNumber,
+		Type:              certificate.Type,
+		Status:            certificate.Status,
+		Validity:          certificateValidity(certificate, now),
+		CarbonOffset:      certificate.CarbonOffset,
+		VintageYear:       certificate.VintageYear,
+		SerialNumber:      certificate.SerialNumber,
+		Project:           verifiedProject,
+		BlockchainTxHash:  certificate.BlockchainTxHash,
+		BlockchainNetwork: certificate.BlockchainNetwork,
+		TokenID:           certificate.TokenID,
+		IssuedAt:          certificate.IssuedAt,
+		ExpiresAt:         certificate.ExpiresAt,
+		RetiredAt:         certificate.RetiredAt,
+		Verifications:     verifications,
+		VerifiedAt:        now,
+	}
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"mime/multipart"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"golang.org/x/crypto/sha3"
+)
+
+// Signatures of the certificate contract's mint function and the ERC-721
+// Transfer event it emits
+const (
+	mintSignature     = "mint(address,uint256,string)"
+	transferSignature = "Transfer(address,address,uint256)"
+)
+
+var hexAddressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// EthereumMinterConfig holds the node, contract and IPFS endpoints used to
+// mint certificates on an Ethereum-compatible chain
+type EthereumMinterConfig struct {
+	// RPCURL is the JSON-RPC endpoint of a node that holds the unlocked
+	// FromAddress account and signs mint transactions for it
+	RPCURL string
+	// Network names the chain, e.g. celo-alfajores, and is recorded on
+	// minted certificates
+	Network string
+	// ContractAddress is the ERC-721 certificate contract
+	ContractAddress string
+	// FromAddress sends the mint transactions and holds the minted tokens
+	FromAddress string
+	// IPFSAPIURL is the HTTP API of the IPFS node metadata is added to
+	IPFSAPIURL string
+}
+
+// EthereumMinter mints certificates through an ERC-721 contract exposing
+// mint(address to, uint256 tokenId, string tokenURI). Token IDs are derived
+// from certificate IDs, so a mint resent after a lost response is rejected
+// by the contract instead of minting a second token.
+type EthereumMinter struct {
+	config    EthereumMinterConfig
+	ipfs      *IPFSClient
+	client    *http.Client
+	requestID atomic.Int64
+}
+
+// NewEthereumMinter creates a minter for an Ethereum-compatible chain
+func NewEthereumMinter(config EthereumMinterConfig, client *http.Client) (*EthereumMinter, error) {
+	if config.RPCURL == "" || config.IPFSAPIURL == "" {
+		return nil, fmt.Errorf("ethereum minter requires an RPC URL and an IPFS API URL")
+	}
+	if !hexAddressPattern.MatchString(config.ContractAddress) || !hexAddressPattern.MatchString(config.FromAddress) {
+		return nil, fmt.Errorf("ethereum minter requires hex contract and from addresses")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &EthereumMinter{
+		config: config,
+		ipfs:   NewIPFSClient(config.IPFSAPIURL, client),
+		client: client,
+	}, nil
+}
+
+// Network names the chain tokens are minted on
+func (m *EthereumMinter) Network() string {
+	return m.config.Network
+}
+
+// Mint adds the metadata to IPFS and sends the certificate's mint transaction
+func (m *EthereumMinter) Mint(ctx context.Context, certificate *models.Certificate, metadata *models.CertificateMetadata) (*MintSubmission, error) {
+	content, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode certificate metadata: %w", err)
+	}
+
+	cid, err := m.ipfs.Add(ctx, certificate.ID.String()+".json", content)
+	if err != nil {
+		return nil, err
+	}
+	metadataURI := "ipfs://" + cid
+
+	transaction := map[string]string{
+		"from": m.config.FromAddress,
+		"to":   m.config.ContractAddress,
+		"data": "0x" + hex.EncodeToString(mintCallData(m.config.FromAddress, certificateTokenID(certificate.ID), metadataURI)),
+	}
+
+	var txHash string
+	if err := m.call(ctx, "eth_sendTransaction", []interface{}{transaction}, &txHash); err != nil {
+		return nil, fmt.Errorf("failed to send mint transaction: %w", err)
+	}
+
+	return &MintSubmission{TxHash: txHash, MetadataURI: metadataURI}, nil
+}
+
+// MintReceipt looks up the mint transaction's receipt, which the node
+// returns as null until the transaction is mined
+func (m *EthereumMinter) MintReceipt(ctx context.Context, txHash string) (*MintReceipt, error) {
+	var receipt *struct {
+		Status string `json:"status"`
+		Logs   []struct {
+			Address string   `json:"address"`
+			Topics  []string `json:"topics"`
+		} `json:"logs"`
+	}
+	if err := m.call(ctx, "eth_getTransactionReceipt", []interface{}{txHash}, &receipt); err != nil {
+		return nil, fmt.Errorf("failed to get mint receipt: %w", err)
+	}
+
+	if receipt == nil {
+		return &MintReceipt{Status: MintTxPending}, nil
+	}
+	if receipt.Status != "0x1" {
+		return &MintReceipt{Status: MintTxFailed}, nil
+	}
+
+	transferTopic := "0x" + hex.EncodeToString(keccak256([]byte(transferSignature)))
+	for _, log := range receipt.Logs {
+		if !strings.EqualFold(log.Address, m.config.ContractAddress) || len(log.Topics) != 4 ||
+			!strings.EqualFold(log.Topics[0], transferTopic) {
+			continue
+		}
+		tokenID, ok := new(big.Int).SetString(strings.TrimPrefix(log.Topics[3], "0x"), 16)
+		if !ok {
+			return nil, fmt.Errorf("invalid token ID in mint receipt: %s", log.Topics[3])
+		}
+		return &MintReceipt{Status: MintTxConfirmed, TokenID: tokenID.String()}, nil
+	}
+
+	return nil, fmt.Errorf("mint receipt for %s has no Transfer event", txHash)
+}
+
+// call makes a JSON-RPC request to the node and decodes its result into result
+func (m *EthereumMinter) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      m.requestID.Add(1),
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.config.RPCURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("node returned %s", resp.Status)
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("invalid JSON-RPC response: %w", err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s (code %d)", response.Error.Message, response.Error.Code)
+	}
+
+	return json.Unmarshal(response.Result, result)
+}
+
+// certificateTokenID derives a certificate's token ID from its UUID
+func certificateTokenID(id uuid.UUID) *big.Int {
+	return new(big.Int).SetBytes(id[:])
+}
+
+// mintCallData ABI-encodes a call to mint(address,uint256,string)
+func mintCallData(to string, tokenID *big.Int, tokenURI string) []byte {
+	address, _ := hex.DecodeString(strings.TrimPrefix(to, "0x"))
+
+	data := keccak256([]byte(mintSignature))[:4]
+	data = append(data, abiWord(new(big.Int).SetBytes(address))...)
+	data = append(data, abiWord(tokenID)...)
+	// The string is dynamic: its offset from the start of the arguments,
+	// then its length and its bytes padded to a whole word
+	data = append(data, abiWord(big.NewInt(3*32))...)
+	data = append(data, abiWord(big.NewInt(int64(len(tokenURI))))...)
+	padded := make([]byte, (len(tokenURI)+31)/32*32)
+	copy(padded, tokenURI)
+	return append(data, padded...)
+}
+
+// abiWord encodes an unsigned integer as a 32-byte big-endian ABI word
+func abiWord(value *big.Int) []byte {
+	return value.FillBytes(make([]byte, 32))
+}
+
+func keccak256(data []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+// IPFSClient adds files through the HTTP API of an IPFS node
+type IPFSClient struct {
+	apiURL string
+	client *http.Client
+}
+
+// NewIPFSClient creates a client for the IPFS node at apiURL
+func NewIPFSClient(apiURL string, client *http.Client) *IPFSClient {
+	return &IPFSClient{apiURL: strings.TrimSuffix(apiURL, "/"), client: client}
+}
+
+// Add adds and pins a file, returning its content identifier
+func (c *IPFSClient) Add(ctx context.Context, name string, content []byte) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(content); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/api/v0/add?pin=true&cid-version=1", &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to add metadata to IPFS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("failed to add metadata to IPFS: %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	var added struct {
+		Hash string `json:"Hash"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&added); err != nil {
+		return "", fmt.Errorf("invalid IPFS response: %w", err)
+	}
+	if added.Hash == "" {
+		return "", errors.New("IPFS response has no content identifier")
+	}
+
+	return added.Hash, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// KafkaEventPublisher implements EventPublisher using Kafka
+type KafkaEventPublisher struct {
+	writer *kafka.Writer
+	logger *logger.Logger
+}
+
+// NewKafkaEventPublisher creates a new Kafka event publisher
+func NewKafkaEventPublisher(brokers []string, logger *logger.Logger) *KafkaEventPublisher {
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        "greenledger-events",
+		Balancer:     &kafka.LeastBytes{},
+		RequiredAcks: kafka.RequireOne,
+		Async:        false,
+	}
+
+	return &KafkaEventPublisher{
+		writer: writer,
+		logger: logger,
+	}
+}
+
+// PublishCertificateExpired publishes a certificate expired event
+func (p *KafkaEventPublisher) PublishCertificateExpired(ctx context.Context, event *CertificateExpiredEvent) error {
+	// Add event metadata. A certificate expires once, so its ID identifies
+	// the event and consumers can drop redeliveries.
+	eventWithMetadata := struct {
+		*CertificateExpiredEvent
+		EventType string    `json:"event_type"`
+		EventID   string    `json:"event_id"`
+		Source    string    `json:"source"`
+		Version   string    `json:"version"`
+		Timestamp time.Time `json:"timestamp"`
+	}{
+		CertificateExpiredEvent: event,
+		EventType:               "certificate_expired",
+		EventID:                 fmt.Sprintf("expire_%s", event.CertificateID),
+		Source:                  "certifier-service",
+		Version:                 "1.0",
+		Timestamp:               event.Timestamp,
+	}
+
+	// Serialize event
+	eventData, err := json.Marshal(eventWithMetadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	// Create Kafka message
+	message := kafka.Message{
+		Key:   []byte(event.UserID),
+		Value: eventData,
+		Headers: []kafka.Header{
+			{Key: "event-type", Value: []byte("certificate_expired")},
+			{Key: "user-id", Value: []byte(event.UserID)},
+			{Key: "source", Value: []byte("certifier-service")},
+		},
+	}
+
+	// Publish message
+	err = p.writer.WriteMessages(ctx, message)
+	if err != nil {
+		p.logger.LogError(ctx, "failed to publish certificate expired event", err,
+			logger.String("user_id", event.UserID),
+			logger.String("certificate_id", event.CertificateID))
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	p.logger.LogInfo(ctx, "certificate expired event published",
+		logger.String("user_id", event.UserID),
+		logger.String("certificate_id", event.CertificateID))
+
+	return nil
+}
+
+// Close closes the event publisher
+func (p *KafkaEventPublisher) Close() error {
+	return p.writer.Close()
+}
+
+// MockEventPublisher is a mock implementation for testing
+type MockEventPublisher struct {
+	Events []interface{}
+	logger *logger.Logger
+}
+
+// NewMockEventPublisher creates a new mock event publisher
+func NewMockEventPublisher(logger *logger.Logger) *MockEventPublisher {
+	return &MockEventPublisher{
+		Events: make([]interface{}, 0),
+		logger: logger,
+	}
+}
+
+// PublishCertificateExpired publishes a certificate expired event (mock)
+func (p *MockEventPublisher) PublishCertificateExpired(ctx context.Context, event *CertificateExpiredEvent) error {
+	p.Events = append(p.Events, event)
+	p.logger.LogInfo(ctx, "mock: certificate expired event published",
+		logger.String("user_id", event.UserID),
+		logger.String("certificate_id", event.CertificateID))
+	return nil
+}
+
+// GetEvents returns all published events
+func (p *MockEventPublisher) GetEvents() []interface{} {
+	return p.Events
+}
+
+// Clear clears all events
+func (p *MockEventPublisher) Clear() {
+	p.Events = make([]interface{}, 0)
+}
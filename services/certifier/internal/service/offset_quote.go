@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// KgCO2PerCredit is the amount of CO2 one project credit offsets (one tonne)
+var KgCO2PerCredit = decimal.NewFromInt(1000)
+
+// DefaultQuoteCurrency is used when a quote request does not name a currency
+const DefaultQuoteCurrency = "USD"
+
+// ErrInvalidQuoteRequest is returned when a quote request fails validation
+var ErrInvalidQuoteRequest = errors.New("invalid quote request")
+
+// ErrInsufficientProjectCredits is returned when the active projects cannot cover a quote
+var ErrInsufficientProjectCredits = errors.New("not enough project credits available")
+
+// OffsetQuoteRequest represents a request to price offsetting an amount of CO2
+type OffsetQuoteRequest struct {
+	CO2Kg    decimal.Decimal `json:"co2_kg" binding:"required"`
+	Currency string          `json:"currency"`
+}
+
+// OffsetQuoteItem is the share of a quote covered by one project
+type OffsetQuoteItem struct {
+	ProjectID      uuid.UUID       `json:"project_id"`
+	ProjectName    string          `json:"project_name"`
+	ProjectType    string          `json:"project_type"`
+	Credits        decimal.Decimal `json:"credits"`
+	PricePerCredit decimal.Decimal `json:"price_per_credit"`
+	Cost           decimal.Decimal `json:"cost"`
+}
+
+// OffsetQuoteResponse is the cheapest way to offset the requested CO2
+type OffsetQuoteResponse struct {
+	CO2Kg        decimal.Decimal   `json:"co2_kg"`
+	TotalCredits decimal.Decimal   `json:"total_credits"`
+	TotalCost    decimal.Decimal   `json:"total_cost"`
+	Currency     string            `json:"currency"`
+	Projects     []OffsetQuoteItem `json:"projects"`
+}
+
+// QuoteOffset prices offsetting req.CO2Kg using the cheapest active projects
+// with credits left. Nothing is reserved or issued.
+func (s *CertificateService) QuoteOffset(ctx context.Context, req *OffsetQuoteRequest) (*OffsetQuoteResponse, error) {
+	if req.CO2Kg.LessThanOrEqual(decimal.Zero) {
+		return nil, fmt.Errorf("%w: co2_kg must be positive", ErrInvalidQuoteRequest)
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = DefaultQuoteCurrency
+	}
+
+	projects, err := s.projectRepo.GetAvailable(ctx, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	credits := req.CO2Kg.Div(KgCO2PerCredit).RoundCeil(3)
+	items, err := buildOffsetQuote(projects, credits)
+	if err != nil {
+		return nil, err
+	}
+
+	totalCost := decimal.Zero
+	for _, item := range items {
+		totalCost = totalCost.Add(item.Cost)
+	}
+
+	s.logger.LogInfo(ctx, "offset quote calculated",
+		logger.String("co2_kg", req.CO2Kg.String()),
+		logger.String("credits", credits.String()),
+		logger.String("total_cost", totalCost.String()),
+		logger.Int("projects", len(items)))
+
+	return &OffsetQuoteResponse{
+		CO2Kg:        req.CO2Kg,
+		TotalCredits: credits,
+		TotalCost:    totalCost,
+		Currency:     currency,
+		Projects:     items,
+	}, nil
+}
+
+// buildOffsetQuote covers credits from the cheapest projects first. Credits
+// are divisible, so taking the cheapest inventory first gives the lowest cost.
+func buildOffsetQuote(projects []*models.CertificateProject, credits decimal.Decimal) ([]OffsetQuoteItem, error) {
+	candidates := make([]*models.CertificateProject, 0, len(projects))
+	for _, project := range projects {
+		if project.IsActive && project.HasAvailableCredits() {
+			candidates = append(candidates, project)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].PricePerCredit.LessThan(candidates[j].PricePerCredit)
+	})
+
+	items := make([]OffsetQuoteItem, 0)
+	remaining := credits
+	for _, project := range candidates {
+		if !remaining.IsPositive() {
+			break
+		}
+
+		take := decimal.Min(remaining, project.AvailableCredits)
+		items = append(items, OffsetQuoteItem{
+			ProjectID:      project.ID,
+			ProjectName:    project.Name,
+			ProjectType:    project.Type,
+			Credits:        take,
+			PricePerCredit: project.PricePerCredit,
+			Cost:           take.Mul(project.PricePerCredit).Round(2),
+		})
+		remaining = remaining.Sub(take)
+	}
+
+	if remaining.IsPositive() {
+		return nil, fmt.Errorf("%w: %s credits short", ErrInsufficientProjectCredits, remaining)
+	}
+	return items, nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// platformVerifierName is published as the verifier of reviews, so public
+// verification never exposes which admin reviewed a certificate
+const platformVerifierName = "GreenLedger"
+
+// ErrVerificationPending is returned when submitting a certificate that
+// already awaits review
+var ErrVerificationPending = errors.New("certificate verification already pending")
+
+// ErrNoPendingVerification is returned when reviewing a certificate that has
+// not been submitted for verification
+var ErrNoPendingVerification = errors.New("certificate has no pending verification")
+
+// ErrInvalidVerificationReview is returned when a review fails validation
+var ErrInvalidVerificationReview = errors.New("invalid verification review")
+
+// ReviewVerificationRequest represents an admin's decision on a verification
+type ReviewVerificationRequest struct {
+	Approve  *bool           `json:"approve" binding:"required"`
+	Comments string          `json:"comments"` // required when rejecting
+	Evidence json.RawMessage `json:"evidence"`
+}
+
+// VerificationResponse represents a certificate verification in API responses
+type VerificationResponse struct {
+	ID            uuid.UUID       `json:"id"`
+	CertificateID uuid.UUID       `json:"certificate_id"`
+	VerifierID    string          `json:"verifier_id,omitempty"`
+	Status        string          `json:"status"`
+	Comments      string          `json:"comments,omitempty"`
+	Evidence      json.RawMessage `json:"evidence,omitempty"`
+	VerifiedAt    *time.Time      `json:"verified_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// SubmitForVerification queues an issued certificate userID owns for review
+// by an admin. Only one verification may be pending at a time.
+func (s *CertificateService) SubmitForVerification(ctx context.Context, certificateID uuid.UUID, userID string) (*VerificationResponse, error) {
+	certificate, err := s.certificateRepo.GetByID(ctx, certificateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate: %w", err)
+	}
+
+	if err := checkCertificateOwner(certificate, userID); err != nil {
+		return nil, err
+	}
+
+	if certificate.Status != models.CertificateStatusIssued || certificate.IsExpired() {
+		return nil, fmt.Errorf("%w: only issued certificates can be verified", ErrCertificateNotActive)
+	}
+	if pendingVerification(certificate) != nil {
+		return nil, ErrVerificationPending
+	}
+
+	// VerifiedAt holds the submission time until the verification is reviewed
+	verification := &models.CertificateVerification{
+		ID:            uuid.New(),
+		CertificateID: certificate.ID,
+		Status:        models.VerificationStatusPending,
+		VerifiedAt:    time.Now().UTC(),
+	}
+	if err := s.certificateRepo.CreateVerification(ctx, verification); err != nil {
+		return nil, fmt.Errorf("failed to submit certificate for verification: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "certificate submitted for verification",
+		logger.String("certificate_id", certificate.ID.String()),
+		logger.String("verification_id", verification.ID.String()),
+		logger.String("user_id", userID))
+
+	return verificationToResponse(verification), nil
+}
+
+// ReviewVerification records verifierID's decision on a certificate's pending
+// verification. Approving moves the certificate to verified; rejecting moves
+// it to rejected, after which it can no longer be retired or transferred.
+// evidence, if given, must be a JSON document.
+func (s *CertificateService) ReviewVerification(ctx context.Context, certificateID uuid.UUID, verifierID string, approve bool, comments, evidence string) (*VerificationResponse, error) {
+	if !approve && comments == "" {
+		return nil, fmt.Errorf("%w: rejections must include comments", ErrInvalidVerificationReview)
+	}
+	if evidence != "" && !json.Valid([]byte(evidence)) {
+		return nil, fmt.Errorf("%w: evidence must be JSON", ErrInvalidVerificationReview)
+	}
+
+	certificate, err := s.certificateRepo.GetByID(ctx, certificateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate: %w", err)
+	}
+
+	pending := pendingVerification(certificate)
+	if pending == nil {
+		return nil, ErrNoPendingVerification
+	}
+	if certificate.Status != models.CertificateStatusIssued {
+		return nil, fmt.Errorf("%w: only issued certificates can be verified", ErrCertificateNotActive)
+	}
+
+	verification := *pending
+
+	certificateStatus := models.CertificateStatusVerified
+	verification.Status = models.VerificationStatusApproved
+	if !approve {
+		certificateStatus = models.CertificateStatusRejected
+		verification.Status = models.VerificationStatusRejected
+	}
+	verification.VerifierID = verifierID
+	verification.VerifierName = platformVerifierName
+	verification.Comments = comments
+	verification.Evidence = evidence
+	if verification.Evidence == "" {
+		verification.Evidence = "{}"
+	}
+	verification.VerifiedAt = time.Now().UTC()
+
+	if err := s.certificateRepo.ReviewVerification(ctx, &verification, certificateStatus); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrVerificationNotPending):
+			return nil, ErrNoPendingVerification
+		case errors.Is(err, repository.ErrCertificateStatusChanged):
+			return nil, fmt.Errorf("%w: certificate changed during review", ErrCertificateNotActive)
+		}
+		return nil, fmt.Errorf("failed to review verification: %w", err)
+	}
+	*pending = verification
+	certificate.Status = certificateStatus
+
+	s.logger.LogInfo(ctx, "certificate verification reviewed",
+		logger.String("certificate_id", certificate.ID.String()),
+		logger.String("verification_id", verification.ID.String()),
+		logger.String("verifier_id", verifierID),
+		logger.String("status", verification.Status))
+
+	return verificationToResponse(&verification), nil
+}
+
+// pendingVerification returns the certificate's verification awaiting
+// review, if any
+func pendingVerification(certificate *models.Certificate) *models.CertificateVerification {
+	for i := range certificate.Verifications {
+		if certificate.Verifications[i].Status == models.VerificationStatusPending {
+			return &certificate.Verifications[i]
+		}
+	}
+	return nil
+}
+
+func verificationToResponse(verification *models.CertificateVerification) *VerificationResponse {
+	response := &VerificationResponse{
+		ID:            verification.ID,
+		CertificateID: verification.CertificateID,
+		VerifierID:    verification.VerifierID,
+		Status:        verification.Status,
+		Comments:      verification.Comments,
+		CreatedAt:     verification.CreatedAt,
+	}
+	if verification.Evidence != "" {
+		response.Evidence = json.RawMessage(verification.Evidence)
+	}
+	if verification.Status != models.VerificationStatusPending {
+		verifiedAt := verification.VerifiedAt
+		response.VerifiedAt = &verifiedAt
+	}
+	return response
+}
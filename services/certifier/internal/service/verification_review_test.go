@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/certifier/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+)
+
+func TestReviewVerification_Approve(t *testing.T) {
+	service, certificateRepo, _, certificateID := issueTransferTestCertificate(t)
+	ctx := context.Background()
+
+	submitted, err := service.SubmitForVerification(ctx, certificateID, "user-1")
+	if err != nil {
+		t.Fatalf("Expected certificate to be submitted, got %v", err)
+	}
+	if submitted.Status != models.VerificationStatusPending || submitted.VerifiedAt != nil {
+		t.Errorf("Expected an unreviewed pending verification, got %+v", submitted)
+	}
+
+	reviewed, err := service.ReviewVerification(ctx, certificateID, "admin-1", true, "Registry records match", `{"registry_id":"VCS-1"}`)
+	if err != nil {
+		t.Fatalf("Expected verification to be approved, got %v", err)
+	}
+	if reviewed.ID != submitted.ID || reviewed.Status != models.VerificationStatusApproved || reviewed.VerifiedAt == nil {
+		t.Errorf("Expected the submitted verification to be approved, got %+v", reviewed)
+	}
+
+	certificate := certificateRepo.certificates[certificateID]
+	if certificate.Status != models.CertificateStatusVerified {
+		t.Errorf("Expected status %s, got %s", models.CertificateStatusVerified, certificate.Status)
+	}
+	if len(certificate.Verifications) != 1 || certificate.Verifications[0].VerifierID != "admin-1" ||
+		certificate.Verifications[0].Evidence != `{"registry_id":"VCS-1"}` {
+		t.Errorf("Expected the review to be recorded, got %+v", certificate.Verifications)
+	}
+
+	// A verified certificate can still be transferred
+	if _, err := service.TransferCertificate(ctx, certificateID, "user-1", "user-2", models.TransferTypeGift, decimal.Zero); err != nil {
+		t.Errorf("Expected verified certificate to be transferable, got %v", err)
+	}
+}
+
+func TestReviewVerification_RejectBlocksRetirementAndTransfer(t *testing.T) {
+	service, certificateRepo, _, certificateID := issueTransferTestCertificate(t)
+	ctx := context.Background()
+
+	if _, err := service.SubmitForVerification(ctx, certificateID, "user-1"); err != nil {
+		t.Fatalf("Expected certificate to be submitted, got %v", err)
+	}
+
+	reviewed, err := service.ReviewVerification(ctx, certificateID, "admin-1", false, "Serial number not found in registry", "")
+	if err != nil {
+		t.Fatalf("Expected verification to be rejected, got %v", err)
+	}
+	if reviewed.Status != models.VerificationStatusRejected {
+		t.Errorf("Expected status %s, got %s", models.VerificationStatusRejected, reviewed.Status)
+	}
+	if status := certificateRepo.certificates[certificateID].Status; status != models.CertificateStatusRejected {
+		t.Errorf("Expected status %s, got %s", models.CertificateStatusRejected, status)
+	}
+
+	if err := service.RetireCertificate(ctx, certificateID, "user-1"); !errors.Is(err, ErrCertificateNotActive) {
+		t.Errorf("Expected ErrCertificateNotActive retiring a rejected certificate, got %v", err)
+	}
+	if _, err := service.TransferCertificate(ctx, certificateID, "user-1", "user-2", models.TransferTypeGift, decimal.Zero); !errors.Is(err, ErrCertificateNotActive) {
+		t.Errorf("Expected ErrCertificateNotActive transferring a rejected certificate, got %v", err)
+	}
+	if _, err := service.SubmitForVerification(ctx, certificateID, "user-1"); !errors.Is(err, ErrCertificateNotActive) {
+		t.Errorf("Expected ErrCertificateNotActive resubmitting a rejected certificate, got %v", err)
+	}
+}
+
+func TestVerificationWorkflow_Errors(t *testing.T) {
+	service, _, _, certificateID := issueTransferTestCertificate(t)
+	ctx := context.Background()
+
+	if _, err := service.ReviewVerification(ctx, certificateID, "admin-1", true, "", ""); !errors.Is(err, ErrNoPendingVerification) {
+		t.Errorf("Expected ErrNoPendingVerification before submission, got %v", err)
+	}
+	if _, err := service.SubmitForVerification(ctx, certificateID, "user-2"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for another user's certificate, got %v", err)
+	}
+
+	if _, err := service.SubmitForVerification(ctx, certificateID, "user-1"); err != nil {
+		t.Fatalf("Expected certificate to be submitted, got %v", err)
+	}
+	if _, err := service.SubmitForVerification(ctx, certificateID, "user-1"); !errors.Is(err, ErrVerificationPending) {
+		t.Errorf("Expected ErrVerificationPending on a second submission, got %v", err)
+	}
+
+	if _, err := service.ReviewVerification(ctx, certificateID, "admin-1", false, "", ""); !errors.Is(err, ErrInvalidVerificationReview) {
+		t.Errorf("Expected ErrInvalidVerificationReview rejecting without comments, got %v", err)
+	}
+	if _, err := service.ReviewVerification(ctx, certificateID, "admin-1", true, "", "not json"); !errors.Is(err, ErrInvalidVerificationReview) {
+		t.Errorf("Expected ErrInvalidVerificationReview for non-JSON evidence, got %v", err)
+	}
+
+	if _, err := service.ReviewVerification(ctx, certificateID, "admin-1", true, "", ""); err != nil {
+		t.Fatalf("Expected verification to be approved, got %v", err)
+	}
+	if _, err := service.ReviewVerification(ctx, certificateID, "admin-2", false, "Second opinion", ""); !errors.Is(err, ErrNoPendingVerification) {
+		t.Errorf("Expected ErrNoPendingVerification once reviewed, got %v", err)
+	}
+}
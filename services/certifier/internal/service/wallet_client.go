@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/shared/proto/walletcredits"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInsufficientWalletCredits is returned when the user's wallet cannot
+// cover the credits a certificate uses
+var ErrInsufficientWalletCredits = errors.New("insufficient wallet credits")
+
+// WalletClient debits credits from users' wallets
+type WalletClient interface {
+	// DebitCredits debits amount from the user's wallet. Debits are
+	// idempotent per referenceID, so a retry never spends the credits twice.
+	// It returns ErrInsufficientWalletCredits when the balance is too low.
+	DebitCredits(ctx context.Context, userID string, amount decimal.Decimal, referenceID, description string) error
+	// TransferCredits moves amount from one user's wallet to another's,
+	// idempotent per referenceID like debits. It returns
+	// ErrInsufficientWalletCredits when the sender's balance is too low.
+	TransferCredits(ctx context.Context, fromUserID, toUserID string, amount decimal.Decimal, referenceID, description string) error
+}
+
+// GRPCWalletClient debits credits through the wallet service's gRPC API
+type GRPCWalletClient struct {
+	client walletcredits.WalletCreditsClient
+}
+
+// NewGRPCWalletClient creates a wallet client calling the wallet service
+func NewGRPCWalletClient(client walletcredits.WalletCreditsClient) *GRPCWalletClient {
+	return &GRPCWalletClient{client: client}
+}
+
+// DebitCredits debits amount from the user's wallet
+func (c *GRPCWalletClient) DebitCredits(ctx context.Context, userID string, amount decimal.Decimal, referenceID, description string) error {
+	_, err := c.client.DebitCredits(ctx, &walletcredits.DebitCreditsRequest{
+		UserId:      userID,
+		Amount:      amount.String(),
+		ReferenceId: referenceID,
+		Description: description,
+	})
+	if status.Code(err) == codes.FailedPrecondition {
+		return fmt.Errorf("%w: %s", ErrInsufficientWalletCredits, status.Convert(err).Message())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to debit wallet: %w", err)
+	}
+	return nil
+}
+
+// TransferCredits moves amount between users' wallets
+func (c *GRPCWalletClient) TransferCredits(ctx context.Context, fromUserID, toUserID string, amount decimal.Decimal, referenceID, description string) error {
+	_, err := c.client.TransferCredits(ctx, &walletcredits.TransferCreditsRequest{
+		FromUserId:  fromUserID,
+		ToUserId:    toUserID,
+		Amount:      amount.String(),
+		ReferenceId: referenceID,
+		Description: description,
+	})
+	if status.Code(err) == codes.FailedPrecondition {
+		return fmt.Errorf("%w: %s", ErrInsufficientWalletCredits, status.Convert(err).Message())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to transfer wallet credits: %w", err)
+	}
+	return nil
+}
@@ -2,12 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,8 +16,23 @@ import (
 	"github.com/sloweyyy/GreenLedger/services/reporting/internal/service"
 	"github.com/sloweyyy/GreenLedger/shared/config"
 	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/events"
+	"github.com/sloweyyy/GreenLedger/shared/featureflags"
 	sharedLogger "github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/sloweyyy/GreenLedger/shared/middleware"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
+	"github.com/sloweyyy/GreenLedger/shared/proto/reportdata"
+	sharedServer "github.com/sloweyyy/GreenLedger/shared/server"
+	"github.com/sloweyyy/GreenLedger/shared/version"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Build information, injected at build time via -ldflags "-X main.Version=..."
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
 )
 
 // @title GreenLedger Reporting Service API
@@ -56,40 +70,65 @@ func main() {
 	// Initialize logger
 	logger := sharedLogger.New(cfg.Server.LogLevel).WithService("reporting")
 
+	// Initialize metrics
+	metrics := monitoring.NewMetrics("reporting")
+
 	// Initialize main database
 	db, err := database.NewPostgresDB(&cfg.Database, logger)
 	if err != nil {
 		logger.LogError(context.Background(), "failed to connect to database", err)
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer db.Close()
 
-	// Initialize external service databases for data collection
-	calculatorDBConfig := cfg.Database
-	calculatorDBConfig.DBName = "calculator_db"
-	calculatorDB, err := database.NewPostgresDB(&calculatorDBConfig, logger)
-	if err != nil {
-		logger.LogWarn(context.Background(), "failed to connect to calculator database",
-			sharedLogger.String("error", err.Error()))
-		calculatorDB = nil
+	// Initialize external service databases for data collection. With the
+	// grpc data source calculator, tracker and wallet data comes from their
+	// gRPC APIs instead.
+	var calculatorDB, trackerDB, walletDB *database.PostgresDB
+	if cfg.Reporting.DataSource == "database" {
+		calculatorDBConfig := cfg.Database
+		calculatorDBConfig.DBName = "calculator_db"
+		calculatorDB, err = database.NewPostgresDB(&calculatorDBConfig, logger)
+		if err != nil {
+			logger.LogWarn(context.Background(), "failed to connect to calculator database",
+				sharedLogger.String("error", err.Error()))
+			calculatorDB = nil
+		}
+
+		trackerDBConfig := cfg.Database
+		trackerDBConfig.DBName = "tracker_db"
+		trackerDB, err = database.NewPostgresDB(&trackerDBConfig, logger)
+		if err != nil {
+			logger.LogWarn(context.Background(), "failed to connect to tracker database",
+				sharedLogger.String("error", err.Error()))
+			trackerDB = nil
+		}
+
+		walletDBConfig := cfg.Database
+		walletDBConfig.DBName = "wallet_db"
+		walletDB, err = database.NewPostgresDB(&walletDBConfig, logger)
+		if err != nil {
+			logger.LogWarn(context.Background(), "failed to connect to wallet database",
+				sharedLogger.String("error", err.Error()))
+			walletDB = nil
+		}
 	}
 
-	trackerDBConfig := cfg.Database
-	trackerDBConfig.DBName = "tracker_db"
-	trackerDB, err := database.NewPostgresDB(&trackerDBConfig, logger)
+	userAuthDBConfig := cfg.Database
+	userAuthDBConfig.DBName = "userauth_db"
+	userAuthDB, err := database.NewPostgresDB(&userAuthDBConfig, logger)
 	if err != nil {
-		logger.LogWarn(context.Background(), "failed to connect to tracker database",
+		logger.LogWarn(context.Background(), "failed to connect to user-auth database",
 			sharedLogger.String("error", err.Error()))
-		trackerDB = nil
+		userAuthDB = nil
 	}
 
-	walletDBConfig := cfg.Database
-	walletDBConfig.DBName = "wallet_db"
-	walletDB, err := database.NewPostgresDB(&walletDBConfig, logger)
+	certifierDBConfig := cfg.Database
+	certifierDBConfig.DBName = "certifier_db"
+	certifierDB, err := database.NewPostgresDB(&certifierDBConfig, logger)
 	if err != nil {
-		logger.LogWarn(context.Background(), "failed to connect to wallet database",
+		logger.LogWarn(context.Background(), "failed to connect to certifier database",
 			sharedLogger.String("error", err.Error()))
-		walletDB = nil
+		certifierDB = nil
 	}
 
 	// Run database migrations
@@ -98,6 +137,7 @@ func main() {
 		&models.ReportSchedule{},
 		&models.ReportTemplate{},
 		&models.ReportData{},
+		&events.DeadLetter{},
 	); err != nil {
 		logger.LogError(context.Background(), "failed to run migrations", err)
 		log.Fatalf("Failed to run migrations: %v", err)
@@ -106,38 +146,100 @@ func main() {
 	// Initialize repositories
 	reportRepo := repository.NewReportRepository(db, logger)
 
+	// Events this service's consumers give up on
+	deadLetters := events.NewDeadLetterQueue(db, logger)
+
 	// Initialize services
-	dataCollector := service.NewDatabaseDataCollector(
-		calculatorDB,
-		trackerDB,
-		walletDB,
-		logger,
-	)
+	var dataCollector service.DataCollector
+	var grpcConns []io.Closer
+	switch cfg.Reporting.DataSource {
+	case "grpc":
+		// Connections are made lazily, so a service that is down only fails
+		// the reports that need it
+		dial := func(name, addr string) *grpc.ClientConn {
+			conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				logger.LogError(context.Background(), "failed to create gRPC client", err,
+					sharedLogger.String("service", name))
+				log.Fatalf("Failed to create %s gRPC client: %v", name, err)
+			}
+			grpcConns = append(grpcConns, conn)
+			return conn
+		}
+
+		dataCollector = service.NewGRPCDataCollector(
+			reportdata.NewCalculatorDataClient(dial("calculator", cfg.Reporting.CalculatorGRPCAddr)),
+			reportdata.NewTrackerDataClient(dial("tracker", cfg.Reporting.TrackerGRPCAddr)),
+			reportdata.NewWalletDataClient(dial("wallet", cfg.Reporting.WalletGRPCAddr)),
+			userAuthDB,
+			certifierDB,
+			cfg.Reporting.QueryTimeout,
+			logger,
+		)
+	default:
+		dataCollector = service.NewDatabaseDataCollector(
+			calculatorDB,
+			trackerDB,
+			walletDB,
+			userAuthDB,
+			certifierDB,
+			cfg.Reporting.QueryTimeout,
+			logger,
+		)
+	}
 
 	reportRenderer := service.NewPDFReportRenderer(logger)
 
+	var reportStorage service.ReportStorage
+	switch cfg.Reporting.Storage {
+	case "s3":
+		reportStorage, err = service.NewS3ReportStorage(service.S3Config{
+			Bucket:          cfg.Reporting.S3Bucket,
+			Region:          cfg.Reporting.S3Region,
+			Endpoint:        cfg.Reporting.S3Endpoint,
+			AccessKeyID:     cfg.Reporting.S3AccessKeyID,
+			SecretAccessKey: cfg.Reporting.S3SecretAccessKey,
+			SessionToken:    cfg.Reporting.S3SessionToken,
+		}, nil)
+		if err != nil {
+			logger.LogError(context.Background(), "failed to configure report storage", err)
+			log.Fatalf("Failed to configure report storage: %v", err)
+		}
+	default:
+		reportStorage = service.NewLocalReportStorage(cfg.Reporting.StoragePath)
+	}
+
 	reportingService := service.NewReportingService(
 		reportRepo,
 		dataCollector,
 		reportRenderer,
+		reportStorage,
+		cfg.Reporting.MaxDateRange,
+		metrics,
 		logger,
 	)
 
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(cfg.Server.JWTSecret, logger)
+	authMiddleware := middleware.NewAuthMiddleware(cfg.Server.JWTSecret, cfg.Server.JWTIssuer, cfg.Server.JWTAudience, logger)
 
 	// Initialize handlers
 	reportingHandler := handler.NewReportingHandler(reportingService, logger)
 
 	// Setup Gin router
-	if cfg.Server.Environment == "production" {
+	if cfg.Features.Enabled(featureflags.ReleaseMode) {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.LogError(context.Background(), "failed to set trusted proxies", err)
+		log.Fatalf("Failed to set trusted proxies: %v", err)
+	}
 	router.Use(gin.Recovery())
-	router.Use(middleware.RequestLogger(logger))
+	router.Use(middleware.RequestLogger(logger, cfg.Server.SlowRequestThreshold))
+	router.Use(middleware.Pagination(cfg.Pagination.DefaultLimit, cfg.Pagination.MaxLimit))
 	router.Use(middleware.CORS())
+	router.Use(middleware.MaxBodySize(cfg.Server.MaxBodyBytes))
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -151,13 +253,20 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "healthy",
 			"service": "reporting",
-			"version": "1.0.0",
+			"version": Version,
 		})
 	})
 
+	// Prometheus metrics endpoint
+	router.GET("/metrics", monitoring.MetricsHandler())
+
+	// Build info endpoint
+	router.GET("/version", version.Handler(version.New("reporting", Version, GitCommit, BuildTime)))
+
 	// API routes
 	v1 := router.Group("/api/v1")
 	reportingHandler.RegisterRoutes(v1, authMiddleware)
+	events.NewDeadLetterHandler(deadLetters, logger).RegisterRoutes(v1.Group("/reports"), authMiddleware)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -168,32 +277,39 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	// Start server in a goroutine
-	go func() {
-		logger.LogInfo(context.Background(), "starting reporting service",
-			sharedLogger.Int("port", cfg.Server.Port),
-			sharedLogger.String("environment", cfg.Server.Environment))
+	// Cancelled on SIGINT/SIGTERM so background consumers stop before their
+	// readers, the event publisher and the database are closed
+	ctx, stop := sharedServer.NotifyContext(context.Background())
+	defer stop()
 
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.LogError(context.Background(), "failed to start server", err)
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
+	var closers []io.Closer
 
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	// Start user event consumer for erasure requests
+	if cfg.Features.Enabled(featureflags.Kafka) {
+		userConsumer := events.NewUserEventConsumer(cfg.Kafka.Brokers, "reporting-service", deadLetters, logger)
+		closers = append(closers, userConsumer)
+
+		go func() {
+			err := userConsumer.Consume(ctx, func(ctx context.Context, event *events.UserDeletedEvent) error {
+				return reportingService.EraseUserData(ctx, event.UserID)
+			})
+			if err != nil && !errors.Is(err, context.Canceled) {
+				logger.LogError(context.Background(), "user event consumer error", err)
+			}
+		}()
+	}
 
-	logger.LogInfo(context.Background(), "shutting down reporting service")
+	closers = append(closers, grpcConns...)
+	closers = append(closers, db)
 
-	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	logger.LogInfo(context.Background(), "starting reporting service",
+		sharedLogger.Int("port", cfg.Server.Port),
+		sharedLogger.String("data_source", cfg.Reporting.DataSource),
+		sharedLogger.String("environment", cfg.Server.Environment),
+		sharedLogger.Any("features", cfg.Features.EnabledNames()))
 
-	if err := server.Shutdown(ctx); err != nil {
-		logger.LogError(context.Background(), "server forced to shutdown", err)
-		log.Fatalf("Server forced to shutdown: %v", err)
+	if err := sharedServer.RunWithGracefulShutdown(ctx, server, logger, closers...); err != nil {
+		log.Fatalf("reporting service stopped with error: %v", err)
 	}
 
 	logger.LogInfo(context.Background(), "reporting service stopped")
@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"mime"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/reporting/internal/service"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/httperr"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/sloweyyy/GreenLedger/shared/middleware"
 )
@@ -14,6 +17,7 @@ import (
 // ReportingHandler handles HTTP requests for reporting operations
 type ReportingHandler struct {
 	reportingService *service.ReportingService
+	errMapper        *httperr.Mapper
 	logger           *logger.Logger
 }
 
@@ -21,7 +25,14 @@ type ReportingHandler struct {
 func NewReportingHandler(reportingService *service.ReportingService, logger *logger.Logger) *ReportingHandler {
 	return &ReportingHandler{
 		reportingService: reportingService,
-		logger:           logger,
+		errMapper: httperr.New(logger,
+			httperr.Mapping{Err: service.ErrInvalidReportRequest, Status: http.StatusBadRequest, Message: "Invalid report request"},
+			httperr.Mapping{Err: service.ErrReportNotReady, Status: http.StatusConflict, Message: "Report not ready"},
+			httperr.Mapping{Err: service.ErrReportExpired, Status: http.StatusGone, Message: "Report expired"},
+			httperr.Mapping{Err: service.ErrReportFileNotFound, Status: http.StatusNotFound, Message: "Report file not found"},
+			httperr.Mapping{Err: database.ErrNotFound, Status: http.StatusNotFound, Message: "Report not found"},
+		),
+		logger: logger,
 	}
 }
 
@@ -29,11 +40,19 @@ func NewReportingHandler(reportingService *service.ReportingService, logger *log
 func (h *ReportingHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
 	reports := router.Group("/reports")
 	{
+		// Public routes
+		reports.GET("/impact", h.GetPlatformImpact)
+
 		// Protected routes
 		reports.Use(authMiddleware.RequireAuth())
 		reports.POST("/", h.GenerateReport)
+		reports.POST("/export", h.RequestDataExport)
+		reports.GET("/preview", h.PreviewReport)
+		reports.GET("/neutrality", h.GetCarbonNeutrality)
+		reports.GET("/feed", h.GetActivityFeed)
 		reports.GET("/", h.GetUserReports)
 		reports.GET("/:id", h.GetReport)
+		reports.GET("/:id/download", h.DownloadReport)
 		reports.DELETE("/:id", h.DeleteReport)
 
 		// Admin routes
@@ -46,6 +65,25 @@ func (h *ReportingHandler) RegisterRoutes(router *gin.RouterGroup, authMiddlewar
 	}
 }
 
+// GetPlatformImpact godoc
+// @Summary Get platform impact
+// @Description Get platform-wide totals across all users for public impact pages. Totals are cached for a few minutes.
+// @Tags reports
+// @Produce json
+// @Success 200 {object} models.PlatformImpactData
+// @Failure 500 {object} ErrorResponse
+// @Router /reports/impact [get]
+func (h *ReportingHandler) GetPlatformImpact(c *gin.Context) {
+	impact, err := h.reportingService.GetPlatformImpact(c.Request.Context())
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get platform impact")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, impact)
+}
+
 // GenerateReport godoc
 // @Summary Generate a new report
 // @Description Generate a new report for the authenticated user
@@ -81,19 +119,199 @@ func (h *ReportingHandler) GenerateReport(c *gin.Context) {
 
 	response, err := h.reportingService.GenerateReport(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to generate report", err,
+		h.errMapper.Respond(c, err, "Failed to generate report",
 			logger.String("user_id", userID),
 			logger.String("report_type", req.Type))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to generate report",
-			Details: err.Error(),
-		})
 		return
 	}
 
 	c.JSON(http.StatusCreated, response)
 }
 
+// PreviewReport godoc
+// @Summary Preview report data
+// @Description Collect report data synchronously and return it as JSON without storing a report
+// @Tags reports
+// @Produce json
+// @Param type query string true "Report type (footprint, credits or summary)"
+// @Param start query string false "Start date (RFC3339 format), defaults to 30 days before end"
+// @Param end query string false "End date (RFC3339 format), defaults to now"
+// @Success 200 {object} service.ReportPreviewResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /reports/preview [get]
+func (h *ReportingHandler) PreviewReport(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	startDate, endDate, ok := parseDateWindow(c)
+	if !ok {
+		return
+	}
+
+	req := service.PreviewReportRequest{
+		UserID:    userID,
+		Type:      c.Query("type"),
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+
+	response, err := h.reportingService.PreviewReport(c.Request.Context(), &req)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to preview report",
+			logger.String("user_id", userID),
+			logger.String("report_type", req.Type))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCarbonNeutrality godoc
+// @Summary Get carbon neutrality status
+// @Description Compare the CO2 calculated for the authenticated user with the certificate offsets they retired over a period. Status is neutral, surplus or deficit, and gap_co2_kg is the difference.
+// @Tags reports
+// @Produce json
+// @Param start query string false "Start date (RFC3339 format), defaults to 30 days before end"
+// @Param end query string false "End date (RFC3339 format), defaults to now"
+// @Success 200 {object} models.CarbonNeutralityData
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /reports/neutrality [get]
+func (h *ReportingHandler) GetCarbonNeutrality(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	startDate, endDate, ok := parseDateWindow(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.reportingService.GetCarbonNeutrality(c.Request.Context(), userID, startDate, endDate)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get carbon neutrality",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetActivityFeed godoc
+// @Summary Get activity feed
+// @Description Get the authenticated user's activities, earned credits, transfers and issued certificates merged into one list, newest first. Pass next_before from a response as before to get the next page. Services that could not be read are listed in unavailable_sources.
+// @Tags reports
+// @Produce json
+// @Param before query string false "Only entries before this time (RFC3339 format), defaults to now"
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
+// @Success 200 {object} models.ActivityFeedData
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /reports/feed [get]
+func (h *ReportingHandler) GetActivityFeed(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	before := time.Now().UTC()
+	if beforeStr := c.Query("before"); beforeStr != "" {
+		parsed, err := time.Parse(time.RFC3339, beforeStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid before date format",
+				Details: err.Error(),
+			})
+			return
+		}
+		before = parsed
+	}
+
+	limit, _ := middleware.GetPagination(c)
+
+	feed, err := h.reportingService.GetActivityFeed(c.Request.Context(), userID, before, limit)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get activity feed",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// parseDateWindow reads the optional start and end query parameters. end
+// defaults to now and start to 30 days before end. On a malformed date it
+// writes a 400 response and returns false.
+func parseDateWindow(c *gin.Context) (time.Time, time.Time, bool) {
+	endDate := time.Now().UTC()
+	if endStr := c.Query("end"); endStr != "" {
+		parsed, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid end date format",
+				Details: err.Error(),
+			})
+			return time.Time{}, time.Time{}, false
+		}
+		endDate = parsed
+	}
+
+	startDate := endDate.AddDate(0, 0, -30)
+	if startStr := c.Query("start"); startStr != "" {
+		parsed, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid start date format",
+				Details: err.Error(),
+			})
+			return time.Time{}, time.Time{}, false
+		}
+		startDate = parsed
+	}
+
+	return startDate, endDate, true
+}
+
+// RequestDataExport godoc
+// @Summary Request a personal data export
+// @Description Start an asynchronous export of all data held about the authenticated user. The archive is downloaded like any other report once completed.
+// @Tags reports
+// @Produce json
+// @Success 202 {object} service.ReportResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /reports/export [post]
+func (h *ReportingHandler) RequestDataExport(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	response, err := h.reportingService.RequestDataExport(c.Request.Context(), userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to request data export",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
 // GetReport godoc
 // @Summary Get report by ID
 // @Description Get a specific report by ID
@@ -125,17 +343,62 @@ func (h *ReportingHandler) GetReport(c *gin.Context) {
 
 	response, err := h.reportingService.GetReport(c.Request.Context(), id, userID)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to get report", err,
+		h.errMapper.Respond(c, err, "Failed to get report",
 			logger.String("report_id", id.String()),
 			logger.String("user_id", userID))
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error:   "Report not found",
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DownloadReport godoc
+// @Summary Download report
+// @Description Download the file of a completed report as an attachment
+// @Tags reports
+// @Produce application/pdf
+// @Produce application/json
+// @Produce text/csv
+// @Produce application/zip
+// @Param id path string true "Report ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /reports/{id}/download [get]
+func (h *ReportingHandler) DownloadReport(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid report ID",
 			Details: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	file, err := h.reportingService.DownloadReport(c.Request.Context(), id, userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to download report",
+			logger.String("report_id", id.String()),
+			logger.String("user_id", userID))
+		return
+	}
+	defer file.Content.Close()
+
+	c.DataFromReader(http.StatusOK, file.Size, file.ContentType, file.Content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": file.Filename}),
+	})
 }
 
 // GetUserReports godoc
@@ -143,7 +406,7 @@ func (h *ReportingHandler) GetReport(c *gin.Context) {
 // @Description Get reports for the authenticated user
 // @Tags reports
 // @Produce json
-// @Param limit query int false "Limit" default(20)
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
 // @Param offset query int false "Offset" default(0)
 // @Success 200 {object} ReportListResponse
 // @Failure 401 {object} ErrorResponse
@@ -157,25 +420,18 @@ func (h *ReportingHandler) GetUserReports(c *gin.Context) {
 		return
 	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, offset := middleware.GetPagination(c)
 
 	reports, total, err := h.reportingService.GetUserReports(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to get user reports", err,
+		h.errMapper.Respond(c, err, "Failed to get reports",
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to get reports",
-			Details: err.Error(),
-		})
 		return
 	}
 
 	response := ReportListResponse{
-		Reports: reports,
-		Total:   total,
-		Limit:   limit,
-		Offset:  offset,
+		Reports:  reports,
+		PageInfo: middleware.NewPageInfo(total, limit, offset),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -212,13 +468,9 @@ func (h *ReportingHandler) DeleteReport(c *gin.Context) {
 
 	err = h.reportingService.DeleteReport(c.Request.Context(), id, userID)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to delete report", err,
+		h.errMapper.Respond(c, err, "Failed to delete report",
 			logger.String("report_id", id.String()),
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to delete report",
-			Details: err.Error(),
-		})
 		return
 	}
 
@@ -237,10 +489,7 @@ func (h *ReportingHandler) GetReportTemplates(c *gin.Context) {
 }
 
 // Response types
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Details string `json:"details,omitempty"`
-}
+type ErrorResponse = httperr.ErrorResponse
 
 type SuccessResponse struct {
 	Message string `json:"message"`
@@ -248,7 +497,5 @@ type SuccessResponse struct {
 
 type ReportListResponse struct {
 	Reports []*service.ReportResponse `json:"reports"`
-	Total   int64                     `json:"total"`
-	Limit   int                       `json:"limit"`
-	Offset  int                       `json:"offset"`
+	middleware.PageInfo
 }
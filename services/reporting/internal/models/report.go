@@ -118,6 +118,7 @@ const (
 	ReportTypeSummary      = "summary"
 	ReportTypeComparison   = "comparison"
 	ReportTypeLeaderboard  = "leaderboard"
+	ReportTypeDataExport   = "data_export"
 )
 
 // Report formats
@@ -126,6 +127,7 @@ const (
 	ReportFormatJSON = "json"
 	ReportFormatCSV  = "csv"
 	ReportFormatXLSX = "xlsx"
+	ReportFormatZIP  = "zip"
 )
 
 // Report statuses
@@ -137,6 +139,41 @@ const (
 	ReportStatusExpired    = "expired"
 )
 
+// Wallet transaction types relevant to credit reports
+const (
+	TransactionTypeCreditEarned = "credit_earned"
+	TransactionTypeTransferIn   = "transfer_in"
+	TransactionTypeTransferOut  = "transfer_out"
+	TransactionTypeRefund       = "refund"
+	TransactionTypeBonus        = "bonus"
+)
+
+// CreditSourceKey returns the key a transaction is grouped under in a credits
+// report. Transfers are keyed by direction rather than by their shared
+// "transfer" source so gifted credits are reported apart from earned ones.
+func CreditSourceKey(txType, source string) string {
+	switch txType {
+	case TransactionTypeTransferIn, TransactionTypeTransferOut:
+		return txType
+	default:
+		return source
+	}
+}
+
+// CountsTowardCreditSource reports whether a transaction is included in the
+// credits report's breakdown by source: credits received, plus transfers sent
+// out under their own key so gifts can be told apart in both directions.
+// Other debits, such as spends, are left out.
+func CountsTowardCreditSource(txType string) bool {
+	switch txType {
+	case TransactionTypeCreditEarned, TransactionTypeTransferIn, TransactionTypeRefund,
+		TransactionTypeBonus, TransactionTypeTransferOut:
+		return true
+	default:
+		return false
+	}
+}
+
 // Helper methods for Report
 func (r *Report) IsCompleted() bool {
 	return r.Status == ReportStatusCompleted
@@ -161,6 +198,9 @@ func (rs *ReportSchedule) ShouldRun() bool {
 	return time.Now().After(*rs.NextRun)
 }
 
+// UncategorizedCalculations is the footprint report category for calculations saved without one
+const UncategorizedCalculations = "uncategorized"
+
 // FootprintReportData represents carbon footprint report data
 type FootprintReportData struct {
 	UserID              string                     `json:"user_id"`
@@ -168,9 +208,11 @@ type FootprintReportData struct {
 	TotalCalculations   int64                      `json:"total_calculations"`
 	AveragePerDay       decimal.Decimal            `json:"average_per_day"`
 	ByActivityType      map[string]decimal.Decimal `json:"by_activity_type"`
+	ByCategory          map[string]decimal.Decimal `json:"by_category"`
 	ByMonth             map[string]decimal.Decimal `json:"by_month"`
 	TopActivities       []ActivitySummary          `json:"top_activities"`
-	ComparisonToAverage decimal.Decimal            `json:"comparison_to_average"`
+	AverageUserCO2Kg    decimal.Decimal            `json:"average_user_co2_kg"`   // across users with calculations in the period
+	ComparisonToAverage decimal.Decimal            `json:"comparison_to_average"` // percent above (+) or below (-) AverageUserCO2Kg
 	StartDate           time.Time                  `json:"start_date"`
 	EndDate             time.Time                  `json:"end_date"`
 }
@@ -201,11 +243,12 @@ type ActivitySummary struct {
 
 // TransactionSummary represents a summary of a transaction
 type TransactionSummary struct {
-	ID          uuid.UUID       `json:"id"`
-	Type        string          `json:"type"`
-	Amount      decimal.Decimal `json:"amount"`
-	Description string          `json:"description"`
-	CreatedAt   time.Time       `json:"created_at"`
+	ID           uuid.UUID       `json:"id"`
+	Type         string          `json:"type"`
+	Amount       decimal.Decimal `json:"amount"`
+	Description  string          `json:"description"`
+	Counterparty string          `json:"counterparty,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
 }
 
 // SummaryReportData represents overall summary report data
@@ -226,6 +269,132 @@ type SummaryReportData struct {
 	EndDate              time.Time       `json:"end_date"`
 }
 
+// ActiveDays returns the days between start and end, inclusive and in UTC,
+// with the most and the fewest activities in counts, which is keyed by UTC
+// midnight. Days missing from counts had no activity and can be the least
+// active day. Ties go to the earliest day.
+func ActiveDays(counts map[time.Time]int64, start, end time.Time) (most, least time.Time) {
+	first := start.UTC().Truncate(24 * time.Hour)
+	last := end.UTC().Truncate(24 * time.Hour)
+
+	mostCount, leastCount := int64(-1), int64(-1)
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		count := counts[day]
+		if count > mostCount {
+			most, mostCount = day, count
+		}
+		if leastCount < 0 || count < leastCount {
+			least, leastCount = day, count
+		}
+	}
+
+	return most, least
+}
+
+// Carbon neutrality statuses
+const (
+	NeutralityStatusNeutral = "neutral"
+	NeutralityStatusSurplus = "surplus"
+	NeutralityStatusDeficit = "deficit"
+)
+
+// CarbonNeutralityData compares a user's calculated emissions with the
+// certificate offsets they retired over a period
+type CarbonNeutralityData struct {
+	UserID              string          `json:"user_id"`
+	EmissionsCO2Kg      decimal.Decimal `json:"emissions_co2_kg"`
+	RetiredOffsetKg     decimal.Decimal `json:"retired_offset_kg"`
+	RetiredCertificates int64           `json:"retired_certificates"`
+	// NetCO2Kg is emissions minus retired offsets; negative means a surplus
+	NetCO2Kg decimal.Decimal `json:"net_co2_kg"`
+	// GapCO2Kg is the size of the deficit or surplus
+	GapCO2Kg  decimal.Decimal `json:"gap_co2_kg"`
+	Status    string          `json:"status"`
+	StartDate time.Time       `json:"start_date"`
+	EndDate   time.Time       `json:"end_date"`
+}
+
+// NetCarbon returns emissions minus offsets and the neutrality status it
+// implies. Amounts are compared at the three decimal places they are stored with.
+func NetCarbon(emissionsKg, offsetKg decimal.Decimal) (decimal.Decimal, string) {
+	net := emissionsKg.Round(3).Sub(offsetKg.Round(3))
+	switch net.Sign() {
+	case 1:
+		return net, NeutralityStatusDeficit
+	case -1:
+		return net, NeutralityStatusSurplus
+	default:
+		return net, NeutralityStatusNeutral
+	}
+}
+
+// CompareToAverage returns how far total is above (positive) or below
+// (negative) average as a percentage of average, to two decimal places.
+// Without an average to compare against it returns zero.
+func CompareToAverage(total, average decimal.Decimal) decimal.Decimal {
+	if !average.IsPositive() {
+		return decimal.Zero
+	}
+	return total.Sub(average).Div(average).Mul(decimal.NewFromInt(100)).Round(2)
+}
+
+// PlatformImpactData represents platform-wide impact totals across all users
+type PlatformImpactData struct {
+	TotalCO2CalculatedKg decimal.Decimal `json:"total_co2_calculated_kg"`
+	TotalCalculations    int64           `json:"total_calculations"`
+	TotalCreditsEarned   decimal.Decimal `json:"total_credits_earned"`
+	TotalCertificates    int64           `json:"total_certificates"`
+	TotalCarbonOffset    decimal.Decimal `json:"total_carbon_offset"`
+	GeneratedAt          time.Time       `json:"generated_at"`
+}
+
+// Activity feed entry types
+const (
+	FeedEntryActivityLogged    = "activity_logged"
+	FeedEntryCreditsEarned     = "credits_earned"
+	FeedEntryTransferIn        = "transfer_in"
+	FeedEntryTransferOut       = "transfer_out"
+	FeedEntryCertificateIssued = "certificate_issued"
+)
+
+// Activity feed sources, one per service the feed reads from
+const (
+	FeedSourceTracker   = "tracker"
+	FeedSourceWallet    = "wallet"
+	FeedSourceCertifier = "certifier"
+)
+
+// FeedEntry is a single item in a user's activity feed
+type FeedEntry struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Source      string          `json:"source"`
+	Description string          `json:"description"`
+	Amount      decimal.Decimal `json:"amount"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
+
+// ActivityFeedData is a page of a user's activity feed, newest first. Entries
+// older than NextBefore make up the next page; it is nil on the last page.
+// UnavailableSources lists services whose entries could not be read.
+type ActivityFeedData struct {
+	UserID             string      `json:"user_id"`
+	Entries            []FeedEntry `json:"entries"`
+	NextBefore         *time.Time  `json:"next_before"`
+	UnavailableSources []string    `json:"unavailable_sources"`
+}
+
+// DataExportData represents all personal data held about a user across services
+type DataExportData struct {
+	UserID       string                   `json:"user_id"`
+	ExportedAt   time.Time                `json:"exported_at"`
+	Profile      map[string]interface{}   `json:"profile"`
+	Calculations []map[string]interface{} `json:"calculations"`
+	Activities   []map[string]interface{} `json:"activities"`
+	Transactions []map[string]interface{} `json:"transactions"`
+	Certificates []map[string]interface{} `json:"certificates"`
+}
+
 // ReportStats represents report statistics for a user
 type ReportStats struct {
 	UserID           string `json:"user_id"`
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/reporting/internal/models"
+)
+
+// ReportRepositoryInterface defines the interface for report repository
+type ReportRepositoryInterface interface {
+	Create(ctx context.Context, report *models.Report) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Report, error)
+	GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.Report, int64, error)
+	Update(ctx context.Context, report *models.Report) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteByUserID(ctx context.Context, userID string) error
+}
+
+// Ensure concrete types implement interfaces
+var _ ReportRepositoryInterface = (*ReportRepository)(nil)
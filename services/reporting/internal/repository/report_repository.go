@@ -49,7 +49,7 @@ func (r *ReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.R
 	err := r.db.DB.WithContext(ctx).First(&report, "id = ?", id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("report not found")
+			return nil, database.ErrNotFound
 		}
 		r.logger.LogError(ctx, "failed to get report by ID", err,
 			logger.String("report_id", id.String()))
@@ -113,6 +113,29 @@ func (r *ReportRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// DeleteByUserID deletes all reports, report data and schedules belonging to a user
+func (r *ReportRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		reportIDs := tx.Model(&models.Report{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("report_id IN (?)", reportIDs).Delete(&models.ReportData{}).Error; err != nil {
+			return fmt.Errorf("failed to delete report data: %w", err)
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&models.Report{}).Error; err != nil {
+			return fmt.Errorf("failed to delete reports: %w", err)
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&models.ReportSchedule{}).Error; err != nil {
+			return fmt.Errorf("failed to delete report schedules: %w", err)
+		}
+
+		r.logger.LogInfo(ctx, "user reports deleted",
+			logger.String("user_id", userID))
+
+		return nil
+	})
+}
+
 // GetExpiredReports retrieves reports that have expired
 func (r *ReportRepository) GetExpiredReports(ctx context.Context, limit int) ([]*models.Report, error) {
 	var reports []*models.Report
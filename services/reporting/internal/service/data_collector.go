@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,6 +12,7 @@ import (
 	"github.com/sloweyyy/GreenLedger/services/reporting/internal/models"
 	"github.com/sloweyyy/GreenLedger/shared/database"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/timeout"
 )
 
 // DatabaseDataCollector implements DataCollector using database queries
@@ -18,26 +20,111 @@ type DatabaseDataCollector struct {
 	calculatorDB *database.PostgresDB
 	trackerDB    *database.PostgresDB
 	walletDB     *database.PostgresDB
+	userAuthDB   *database.PostgresDB
+	certifierDB  *database.PostgresDB
+	queryTimeout time.Duration
 	logger       *logger.Logger
+
+	averages globalAverageCache
+}
+
+// globalAverageTTL is how long per-user CO2 averages are cached
+const globalAverageTTL = 10 * time.Minute
+
+// globalAverageKey identifies the period an average was computed over
+type globalAverageKey struct {
+	start, end time.Time
 }
 
-// NewDatabaseDataCollector creates a new database data collector
+// newGlobalAverageKey truncates period bounds to the minute so reports
+// ending "now" share an entry
+func newGlobalAverageKey(startDate, endDate time.Time) globalAverageKey {
+	return globalAverageKey{
+		start: startDate.UTC().Truncate(time.Minute),
+		end:   endDate.UTC().Truncate(time.Minute),
+	}
+}
+
+type cachedAverage struct {
+	value    decimal.Decimal
+	cachedAt time.Time
+}
+
+// globalAverageCache holds recently computed per-user averages by period
+type globalAverageCache struct {
+	mu      sync.Mutex
+	entries map[globalAverageKey]cachedAverage
+	now     func() time.Time
+}
+
+func (c *globalAverageCache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// get returns the cached average for a period if it is still fresh
+func (c *globalAverageCache) get(key globalAverageKey) (decimal.Decimal, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || c.clock().Sub(entry.cachedAt) >= globalAverageTTL {
+		return decimal.Zero, false
+	}
+	return entry.value, true
+}
+
+// put caches the average for a period, dropping stale entries so periods
+// that are never asked for again do not accumulate
+func (c *globalAverageCache) put(key globalAverageKey, value decimal.Decimal) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock()
+	if c.entries == nil {
+		c.entries = make(map[globalAverageKey]cachedAverage)
+	}
+	for k, entry := range c.entries {
+		if now.Sub(entry.cachedAt) >= globalAverageTTL {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cachedAverage{value: value, cachedAt: now}
+}
+
+// NewDatabaseDataCollector creates a new database data collector. Each
+// Collect call is cancelled after queryTimeout; zero disables the limit.
 func NewDatabaseDataCollector(
 	calculatorDB *database.PostgresDB,
 	trackerDB *database.PostgresDB,
 	walletDB *database.PostgresDB,
+	userAuthDB *database.PostgresDB,
+	certifierDB *database.PostgresDB,
+	queryTimeout time.Duration,
 	logger *logger.Logger,
 ) *DatabaseDataCollector {
 	return &DatabaseDataCollector{
 		calculatorDB: calculatorDB,
 		trackerDB:    trackerDB,
 		walletDB:     walletDB,
+		userAuthDB:   userAuthDB,
+		certifierDB:  certifierDB,
+		queryTimeout: queryTimeout,
 		logger:       logger,
 	}
 }
 
 // CollectFootprintData collects carbon footprint data for a user
 func (c *DatabaseDataCollector) CollectFootprintData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.FootprintReportData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.FootprintReportData, error) {
+		return c.collectFootprintData(ctx, userID, startDate, endDate)
+	})
+}
+
+// collectFootprintData implements CollectFootprintData under the query timeout
+func (c *DatabaseDataCollector) collectFootprintData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.FootprintReportData, error) {
 	c.logger.LogInfo(ctx, "collecting footprint data",
 		logger.String("user_id", userID),
 		logger.String("start_date", startDate.Format("2006-01-02")),
@@ -48,6 +135,7 @@ func (c *DatabaseDataCollector) CollectFootprintData(ctx context.Context, userID
 		StartDate:      startDate,
 		EndDate:        endDate,
 		ByActivityType: make(map[string]decimal.Decimal),
+		ByCategory:     make(map[string]decimal.Decimal),
 		ByMonth:        make(map[string]decimal.Decimal),
 		TopActivities:  make([]models.ActivitySummary, 0),
 	}
@@ -121,6 +209,35 @@ func (c *DatabaseDataCollector) CollectFootprintData(ctx context.Context, userID
 		}
 	}
 
+	// Get CO2 by calculation category
+	categoryQuery := `
+		SELECT 
+			COALESCE(NULLIF(category, ''), $4) as category,
+			COALESCE(SUM(total_co2_kg), 0) as total_co2
+		FROM calculations 
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3
+		GROUP BY 1
+		ORDER BY total_co2 DESC
+	`
+
+	categoryRows, err := c.calculatorDB.WithContext(ctx).
+		Raw(categoryQuery, userID, startDate, endDate, models.UncategorizedCalculations).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category breakdown: %w", err)
+	}
+	defer categoryRows.Close()
+
+	for categoryRows.Next() {
+		var category string
+		var totalCO2 sql.NullFloat64
+
+		if err := categoryRows.Scan(&category, &totalCO2); err != nil {
+			continue
+		}
+
+		data.ByCategory[category] = decimal.NewFromFloat(totalCO2.Float64)
+	}
+
 	// Get CO2 by month
 	monthQuery := `
 		SELECT 
@@ -150,14 +267,205 @@ func (c *DatabaseDataCollector) CollectFootprintData(ctx context.Context, userID
 		data.ByMonth[monthKey] = decimal.NewFromFloat(totalCO2.Float64)
 	}
 
-	// TODO: Calculate comparison to average (would need global statistics)
-	data.ComparisonToAverage = decimal.Zero
+	average, err := c.averageUserCO2(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	data.AverageUserCO2Kg = average
+	data.ComparisonToAverage = models.CompareToAverage(data.TotalCO2Kg, average)
+
+	return data, nil
+}
+
+// averageUserCO2 returns the average total CO2 of users with calculations in
+// a period. Averages aggregate every user's calculations, so they are cached
+// for globalAverageTTL per period.
+func (c *DatabaseDataCollector) averageUserCO2(ctx context.Context, startDate, endDate time.Time) (decimal.Decimal, error) {
+	key := newGlobalAverageKey(startDate, endDate)
+	if average, ok := c.averages.get(key); ok {
+		return average, nil
+	}
+
+	query := `
+		SELECT COALESCE(AVG(user_total), 0)
+		FROM (
+			SELECT SUM(total_co2_kg) as user_total
+			FROM calculations
+			WHERE created_at >= $1 AND created_at <= $2
+			GROUP BY user_id
+		) user_totals
+	`
+
+	var average sql.NullFloat64
+	if err := c.calculatorDB.WithContext(ctx).Raw(query, startDate, endDate).Row().Scan(&average); err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get average footprint: %w", err)
+	}
+
+	result := decimal.NewFromFloat(average.Float64)
+	c.averages.put(key, result)
+
+	return result, nil
+}
+
+// CollectPlatformImpact collects impact totals across all users. Totals from
+// a service whose database is unavailable are left at zero.
+func (c *DatabaseDataCollector) CollectPlatformImpact(ctx context.Context) (*models.PlatformImpactData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.PlatformImpactData, error) {
+		return c.collectPlatformImpact(ctx)
+	})
+}
+
+// collectPlatformImpact implements CollectPlatformImpact under the query timeout
+func (c *DatabaseDataCollector) collectPlatformImpact(ctx context.Context) (*models.PlatformImpactData, error) {
+	c.logger.LogInfo(ctx, "collecting platform impact data")
+
+	data := &models.PlatformImpactData{
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	if c.calculatorDB != nil {
+		var totalCO2 sql.NullFloat64
+		var totalCalculations sql.NullInt64
+
+		query := `
+			SELECT 
+				COALESCE(SUM(total_co2_kg), 0) as total_co2,
+				COUNT(*) as total_calculations
+			FROM calculations
+		`
+
+		err := c.calculatorDB.WithContext(ctx).Raw(query).
+			Row().Scan(&totalCO2, &totalCalculations)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get total footprint: %w", err)
+		}
+
+		data.TotalCO2CalculatedKg = decimal.NewFromFloat(totalCO2.Float64)
+		data.TotalCalculations = totalCalculations.Int64
+	}
+
+	if c.walletDB != nil {
+		var totalEarned sql.NullFloat64
+
+		query := `SELECT COALESCE(SUM(total_earned), 0) as total_earned FROM wallets`
+
+		if err := c.walletDB.WithContext(ctx).Raw(query).Row().Scan(&totalEarned); err != nil {
+			return nil, fmt.Errorf("failed to get total credits earned: %w", err)
+		}
+
+		data.TotalCreditsEarned = decimal.NewFromFloat(totalEarned.Float64)
+	}
+
+	if c.certifierDB != nil {
+		if err := c.collectCertificateTotals(ctx, data); err != nil {
+			return nil, err
+		}
+	}
 
 	return data, nil
 }
 
+// collectCertificateTotals sets the platform's certificate totals from the
+// certifier database
+func (c *DatabaseDataCollector) collectCertificateTotals(ctx context.Context, data *models.PlatformImpactData) error {
+	var totalCertificates sql.NullInt64
+	var totalOffset sql.NullFloat64
+
+	// Only certificates that were actually issued count towards offsets
+	query := `
+		SELECT 
+			COUNT(*) as total_certificates,
+			COALESCE(SUM(carbon_offset), 0) as total_offset
+		FROM certificates
+		WHERE status IN ('issued', 'verified', 'retired')
+	`
+
+	err := c.certifierDB.WithContext(ctx).Raw(query).
+		Row().Scan(&totalCertificates, &totalOffset)
+	if err != nil {
+		return fmt.Errorf("failed to get certificate totals: %w", err)
+	}
+
+	data.TotalCertificates = totalCertificates.Int64
+	data.TotalCarbonOffset = decimal.NewFromFloat(totalOffset.Float64)
+	return nil
+}
+
+// CollectCarbonNeutrality collects a user's calculated emissions and the
+// certificate offsets they retired over a period. Status is left to the caller.
+func (c *DatabaseDataCollector) CollectCarbonNeutrality(ctx context.Context, userID string, startDate, endDate time.Time) (*models.CarbonNeutralityData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.CarbonNeutralityData, error) {
+		return c.collectCarbonNeutrality(ctx, userID, startDate, endDate)
+	})
+}
+
+// collectCarbonNeutrality implements CollectCarbonNeutrality under the query timeout
+func (c *DatabaseDataCollector) collectCarbonNeutrality(ctx context.Context, userID string, startDate, endDate time.Time) (*models.CarbonNeutralityData, error) {
+	data := &models.CarbonNeutralityData{
+		UserID:    userID,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+
+	var totalCO2 sql.NullFloat64
+
+	emissionsQuery := `
+		SELECT COALESCE(SUM(total_co2_kg), 0) as total_co2
+		FROM calculations
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3
+	`
+
+	err := c.calculatorDB.WithContext(ctx).Raw(emissionsQuery, userID, startDate, endDate).
+		Row().Scan(&totalCO2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total footprint: %w", err)
+	}
+	data.EmissionsCO2Kg = decimal.NewFromFloat(totalCO2.Float64)
+
+	if c.certifierDB != nil {
+		if err := c.collectRetiredOffsets(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// collectRetiredOffsets sets the certificates a user retired over the
+// data's period from the certifier database
+func (c *DatabaseDataCollector) collectRetiredOffsets(ctx context.Context, data *models.CarbonNeutralityData) error {
+	var retiredCertificates sql.NullInt64
+	var retiredOffset sql.NullFloat64
+
+	// Only retiring a certificate claims its offset
+	offsetQuery := `
+		SELECT 
+			COUNT(*) as retired_certificates,
+			COALESCE(SUM(carbon_offset), 0) as retired_offset
+		FROM certificates
+		WHERE user_id = $1 AND status = 'retired' AND retired_at >= $2 AND retired_at <= $3
+	`
+
+	err := c.certifierDB.WithContext(ctx).Raw(offsetQuery, data.UserID, data.StartDate, data.EndDate).
+		Row().Scan(&retiredCertificates, &retiredOffset)
+	if err != nil {
+		return fmt.Errorf("failed to get retired offsets: %w", err)
+	}
+
+	data.RetiredCertificates = retiredCertificates.Int64
+	data.RetiredOffsetKg = decimal.NewFromFloat(retiredOffset.Float64)
+	return nil
+}
+
 // CollectCreditsData collects carbon credits data for a user
 func (c *DatabaseDataCollector) CollectCreditsData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.CreditsReportData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.CreditsReportData, error) {
+		return c.collectCreditsData(ctx, userID, startDate, endDate)
+	})
+}
+
+// collectCreditsData implements CollectCreditsData under the query timeout
+func (c *DatabaseDataCollector) collectCreditsData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.CreditsReportData, error) {
 	c.logger.LogInfo(ctx, "collecting credits data",
 		logger.String("user_id", userID))
 
@@ -195,15 +503,16 @@ func (c *DatabaseDataCollector) CollectCreditsData(ctx context.Context, userID s
 	data.TotalCreditsEarned = decimal.NewFromFloat(totalEarned.Float64)
 	data.TotalCreditsSpent = decimal.NewFromFloat(totalSpent.Float64)
 
-	// Get transaction count and breakdown by source
+	// Get transaction count and breakdown by source, keeping transfer directions apart
 	transactionQuery := `
 		SELECT 
 			COUNT(*) as total_transactions,
 			source,
-			COALESCE(SUM(CASE WHEN type IN ('credit_earned', 'transfer_in', 'refund', 'bonus') THEN amount ELSE 0 END), 0) as credits_earned
+			type,
+			COALESCE(SUM(amount), 0) as total_amount
 		FROM transactions 
 		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3 AND status = 'completed'
-		GROUP BY source
+		GROUP BY source, type
 	`
 
 	rows, err := c.walletDB.WithContext(ctx).Raw(transactionQuery, userID, startDate, endDate).Rows()
@@ -216,16 +525,21 @@ func (c *DatabaseDataCollector) CollectCreditsData(ctx context.Context, userID s
 	for rows.Next() {
 		var count sql.NullInt64
 		var source sql.NullString
-		var creditsEarned sql.NullFloat64
+		var txType string
+		var totalAmount sql.NullFloat64
 
-		if err := rows.Scan(&count, &source, &creditsEarned); err != nil {
+		if err := rows.Scan(&count, &source, &txType, &totalAmount); err != nil {
 			continue
 		}
 
 		totalTransactions += count.Int64
-		if source.Valid {
-			data.BySource[source.String] = decimal.NewFromFloat(creditsEarned.Float64)
+		// Transfers sent out get their own key; spends are left out
+		if !source.Valid || !models.CountsTowardCreditSource(txType) {
+			continue
 		}
+
+		key := models.CreditSourceKey(txType, source.String)
+		data.BySource[key] = data.BySource[key].Add(decimal.NewFromFloat(totalAmount.Float64))
 	}
 	data.TotalTransactions = totalTransactions
 
@@ -299,7 +613,9 @@ func (c *DatabaseDataCollector) CollectCreditsData(ctx context.Context, userID s
 
 	// Get recent transactions
 	recentQuery := `
-		SELECT id, type, amount, description, created_at
+		SELECT id, type, amount, description,
+			COALESCE(NULLIF(from_user_id, ''), NULLIF(to_user_id, ''), '') as counterparty,
+			created_at
 		FROM transactions 
 		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3 AND status = 'completed'
 		ORDER BY created_at DESC
@@ -317,19 +633,21 @@ func (c *DatabaseDataCollector) CollectCreditsData(ctx context.Context, userID s
 		var txType string
 		var amount sql.NullFloat64
 		var description string
+		var counterparty string
 		var createdAt time.Time
 
-		if err := recentRows.Scan(&id, &txType, &amount, &description, &createdAt); err != nil {
+		if err := recentRows.Scan(&id, &txType, &amount, &description, &counterparty, &createdAt); err != nil {
 			continue
 		}
 
 		txID, _ := uuid.Parse(id)
 		data.RecentTransactions = append(data.RecentTransactions, models.TransactionSummary{
-			ID:          txID,
-			Type:        txType,
-			Amount:      decimal.NewFromFloat(amount.Float64),
-			Description: description,
-			CreatedAt:   createdAt,
+			ID:           txID,
+			Type:         txType,
+			Amount:       decimal.NewFromFloat(amount.Float64),
+			Description:  description,
+			Counterparty: counterparty,
+			CreatedAt:    createdAt,
 		})
 	}
 
@@ -338,16 +656,23 @@ func (c *DatabaseDataCollector) CollectCreditsData(ctx context.Context, userID s
 
 // CollectSummaryData collects summary data for a user
 func (c *DatabaseDataCollector) CollectSummaryData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.SummaryReportData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.SummaryReportData, error) {
+		return c.collectSummaryData(ctx, userID, startDate, endDate)
+	})
+}
+
+// collectSummaryData implements CollectSummaryData under the query timeout
+func (c *DatabaseDataCollector) collectSummaryData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.SummaryReportData, error) {
 	c.logger.LogInfo(ctx, "collecting summary data",
 		logger.String("user_id", userID))
 
 	// Collect footprint and credits data
-	footprintData, err := c.CollectFootprintData(ctx, userID, startDate, endDate)
+	footprintData, err := c.collectFootprintData(ctx, userID, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to collect footprint data: %w", err)
 	}
 
-	creditsData, err := c.CollectCreditsData(ctx, userID, startDate, endDate)
+	creditsData, err := c.collectCreditsData(ctx, userID, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to collect credits data: %w", err)
 	}
@@ -387,9 +712,314 @@ func (c *DatabaseDataCollector) CollectSummaryData(ctx context.Context, userID s
 		EndDate:              endDate,
 	}
 
-	// TODO: Calculate most/least active days
-	data.MostActiveDay = startDate
-	data.LeastActiveDay = endDate
+	// Activities and calculations both count towards a day's activity
+	dailyCounts, err := c.dailyCounts(ctx, c.calculatorDB, "calculations", userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count daily calculations: %w", err)
+	}
+	if c.trackerDB != nil {
+		activityCounts, err := c.dailyCounts(ctx, c.trackerDB, "eco_activities", userID, startDate, endDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count daily activities: %w", err)
+		}
+		for day, count := range activityCounts {
+			dailyCounts[day] += count
+		}
+	}
+	data.MostActiveDay, data.LeastActiveDay = models.ActiveDays(dailyCounts, startDate, endDate)
+
+	return data, nil
+}
+
+// dailyCounts returns how many rows of a user's table were created on each
+// UTC day of a period, keyed by UTC midnight. Days without rows are absent.
+func (c *DatabaseDataCollector) dailyCounts(ctx context.Context, db *database.PostgresDB, table, userID string, startDate, endDate time.Time) (map[time.Time]int64, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			DATE(created_at AT TIME ZONE 'UTC') as day,
+			COUNT(*) as count
+		FROM %s
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3
+		GROUP BY 1
+	`, table)
+
+	rows, err := db.WithContext(ctx).Raw(query, userID, startDate, endDate).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[time.Time]int64)
+	for rows.Next() {
+		var day time.Time
+		var count int64
+
+		if err := rows.Scan(&day, &count); err != nil {
+			return nil, err
+		}
+
+		counts[time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// CollectActivityFeed collects up to limit of a user's most recent entries
+// before the given time from each service, unordered. A service whose
+// database is unavailable is listed in UnavailableSources instead of failing
+// the whole feed.
+func (c *DatabaseDataCollector) CollectActivityFeed(ctx context.Context, userID string, before time.Time, limit int) (*models.ActivityFeedData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.ActivityFeedData, error) {
+		return c.collectActivityFeed(ctx, userID, before, limit)
+	})
+}
+
+// collectActivityFeed implements CollectActivityFeed under the query timeout
+func (c *DatabaseDataCollector) collectActivityFeed(ctx context.Context, userID string, before time.Time, limit int) (*models.ActivityFeedData, error) {
+	data := &models.ActivityFeedData{
+		UserID:             userID,
+		Entries:            make([]models.FeedEntry, 0),
+		UnavailableSources: make([]string, 0),
+	}
+
+	sources := []struct {
+		name  string
+		db    *database.PostgresDB
+		query string
+	}{
+		{
+			name: models.FeedSourceTracker,
+			db:   c.trackerDB,
+			query: `
+				SELECT ea.id::text, $4 as type,
+					COALESCE(NULLIF(ea.description, ''), at.name) as description,
+					ea.credits_earned as amount, ea.created_at as occurred_at
+				FROM eco_activities ea
+				JOIN activity_types at ON ea.activity_type_id = at.id
+				WHERE ea.user_id = $1 AND ea.created_at < $2
+				ORDER BY ea.created_at DESC
+				LIMIT $3
+			`,
+		},
+		{
+			name: models.FeedSourceWallet,
+			db:   c.walletDB,
+			query: `
+				SELECT id::text,
+					CASE WHEN type IN ('transfer_in', 'transfer_out') THEN type ELSE $4 END as type,
+					description, amount, created_at as occurred_at
+				FROM transactions
+				WHERE user_id = $1 AND created_at < $2 AND status = 'completed'
+					AND type IN ('credit_earned', 'bonus', 'transfer_in', 'transfer_out')
+				ORDER BY created_at DESC
+				LIMIT $3
+			`,
+		},
+		{
+			name:  models.FeedSourceCertifier,
+			db:    c.certifierDB,
+			query: certifierFeedQuery,
+		},
+	}
+
+	entryTypes := map[string]string{
+		models.FeedSourceTracker:   models.FeedEntryActivityLogged,
+		models.FeedSourceWallet:    models.FeedEntryCreditsEarned,
+		models.FeedSourceCertifier: models.FeedEntryCertificateIssued,
+	}
+
+	for _, source := range sources {
+		if source.db == nil {
+			data.UnavailableSources = append(data.UnavailableSources, source.name)
+			continue
+		}
+
+		entries, err := c.queryFeedEntries(ctx, source.db, source.query, userID, before, limit, entryTypes[source.name])
+		if err != nil {
+			c.logger.LogWarn(ctx, "activity feed source unavailable",
+				logger.String("source", source.name),
+				logger.String("user_id", userID),
+				logger.String("error", err.Error()))
+			data.UnavailableSources = append(data.UnavailableSources, source.name)
+			continue
+		}
+
+		for i := range entries {
+			entries[i].Source = source.name
+		}
+		data.Entries = append(data.Entries, entries...)
+	}
+
+	return data, nil
+}
+
+// certifierFeedQuery selects a user's issued certificates for the activity feed
+const certifierFeedQuery = `
+	SELECT id::text, $4 as type,
+		'Certificate ' || certificate_number as description,
+		carbon_offset as amount, issued_at as occurred_at
+	FROM certificates
+	WHERE user_id = $1 AND issued_at IS NOT NULL AND issued_at < $2
+	ORDER BY issued_at DESC
+	LIMIT $3
+`
+
+// queryFeedEntries runs a feed query taking the user ID, cutoff time, limit
+// and default entry type as parameters
+func (c *DatabaseDataCollector) queryFeedEntries(ctx context.Context, db *database.PostgresDB, query, userID string, before time.Time, limit int, entryType string) ([]models.FeedEntry, error) {
+	rows, err := db.WithContext(ctx).Raw(query, userID, before, limit, entryType).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.FeedEntry, 0)
+	for rows.Next() {
+		var entry models.FeedEntry
+		var amount sql.NullFloat64
+
+		if err := rows.Scan(&entry.ID, &entry.Type, &entry.Description, &amount, &entry.OccurredAt); err != nil {
+			return nil, err
+		}
+
+		entry.Amount = decimal.NewFromFloat(amount.Float64)
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// CollectDataExport collects every record held about a user across services
+func (c *DatabaseDataCollector) CollectDataExport(ctx context.Context, userID string) (*models.DataExportData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.DataExportData, error) {
+		return c.collectDataExport(ctx, userID)
+	})
+}
+
+// collectDataExport implements CollectDataExport under the query timeout
+func (c *DatabaseDataCollector) collectDataExport(ctx context.Context, userID string) (*models.DataExportData, error) {
+	c.logger.LogInfo(ctx, "collecting data export",
+		logger.String("user_id", userID))
+
+	data := &models.DataExportData{
+		UserID:       userID,
+		ExportedAt:   time.Now().UTC(),
+		Profile:      make(map[string]interface{}),
+		Calculations: make([]map[string]interface{}, 0),
+		Activities:   make([]map[string]interface{}, 0),
+		Transactions: make([]map[string]interface{}, 0),
+		Certificates: make([]map[string]interface{}, 0),
+	}
+
+	if c.userAuthDB != nil {
+		if err := c.collectProfile(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get calculations with their activities from calculator service
+	if c.calculatorDB != nil {
+		calculationQuery := `
+			SELECT c.id, c.total_co2_kg, c.created_at,
+				a.activity_type, a.co2_kg, a.emission_factor, a.factor_source, a.activity_data
+			FROM calculations c
+			LEFT JOIN activities a ON a.calculation_id = c.id
+			WHERE c.user_id = $1
+			ORDER BY c.created_at
+		`
+
+		if err := c.calculatorDB.WithContext(ctx).Raw(calculationQuery, userID).Scan(&data.Calculations).Error; err != nil {
+			return nil, fmt.Errorf("failed to get calculations: %w", err)
+		}
+	}
+
+	// Get eco activities from tracker service, leaving out which moderators
+	// reviewed them
+	if c.trackerDB != nil {
+		activityQuery := `
+			SELECT
+				id, user_id, activity_type_id, description, duration, distance,
+				quantity, unit, location, credits_earned, is_verified, verified_at,
+				verification_status, rejection_reason, rejected_at, source,
+				source_data, occurred_at, outside_credit_window, created_at, updated_at
+			FROM eco_activities
+			WHERE user_id = $1
+			ORDER BY created_at
+		`
+
+		if err := c.trackerDB.WithContext(ctx).Raw(activityQuery, userID).Scan(&data.Activities).Error; err != nil {
+			return nil, fmt.Errorf("failed to get activities: %w", err)
+		}
+	}
+
+	// Get transactions from wallet service, leaving out idempotency keys and
+	// the admins who made changes
+	if c.walletDB != nil {
+		transactionQuery := `
+			SELECT
+				id, user_id, type, status, amount, balance_after, source,
+				description, reference_id, from_user_id, to_user_id, metadata,
+				processed_at, reversed_at, created_at, updated_at
+			FROM transactions
+			WHERE user_id = $1
+			ORDER BY created_at
+		`
+
+		if err := c.walletDB.WithContext(ctx).Raw(transactionQuery, userID).Scan(&data.Transactions).Error; err != nil {
+			return nil, fmt.Errorf("failed to get transactions: %w", err)
+		}
+	}
+
+	if c.certifierDB != nil {
+		if err := c.collectCertificates(ctx, data); err != nil {
+			return nil, err
+		}
+	}
 
 	return data, nil
 }
+
+// collectProfile sets the user's profile from the user-auth database, never
+// exporting the password hash
+func (c *DatabaseDataCollector) collectProfile(ctx context.Context, data *models.DataExportData) error {
+	profileQuery := `
+		SELECT 
+			u.id, u.email, u.username, u.first_name, u.last_name,
+			u.is_active, u.is_verified, u.last_login_at, u.created_at, u.updated_at,
+			p.bio, p.location, p.website, p.date_of_birth, p.phone_number
+		FROM users u
+		LEFT JOIN user_profiles p ON p.user_id = u.id
+		WHERE u.id::text = $1
+	`
+
+	var profiles []map[string]interface{}
+	if err := c.userAuthDB.WithContext(ctx).Raw(profileQuery, data.UserID).Scan(&profiles).Error; err != nil {
+		return fmt.Errorf("failed to get user profile: %w", err)
+	}
+	if len(profiles) > 0 {
+		data.Profile = profiles[0]
+	}
+	return nil
+}
+
+// collectCertificates sets the user's certificates from the certifier
+// database, leaving out idempotency keys and minting internals
+func (c *DatabaseDataCollector) collectCertificates(ctx context.Context, data *models.DataExportData) error {
+	certificateQuery := `
+		SELECT
+			id, user_id, holder_name, certificate_number, type, status,
+			carbon_offset, credits_used, project_name, project_type,
+			project_location, verification_body, standard, vintage_year,
+			serial_number, blockchain_tx_hash, blockchain_network, token_id,
+			metadata_uri, mint_status, issued_at, expires_at, retired_at,
+			created_at, updated_at
+		FROM certificates
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	if err := c.certifierDB.WithContext(ctx).Raw(certificateQuery, data.UserID).Scan(&data.Certificates).Error; err != nil {
+		return fmt.Errorf("failed to get certificates: %w", err)
+	}
+	return nil
+}
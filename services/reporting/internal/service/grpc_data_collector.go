@@ -0,0 +1,528 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/reporting/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/proto/reportdata"
+	"github.com/sloweyyy/GreenLedger/shared/timeout"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCDataCollector implements DataCollector by calling the calculator,
+// tracker and wallet services' report data APIs instead of reading their
+// databases. Certificates and profiles are still read from the certifier
+// and user-auth databases, which have no such API yet.
+type GRPCDataCollector struct {
+	calculator   reportdata.CalculatorDataClient
+	tracker      reportdata.TrackerDataClient
+	wallet       reportdata.WalletDataClient
+	databases    *DatabaseDataCollector
+	queryTimeout time.Duration
+	logger       *logger.Logger
+
+	averages globalAverageCache
+}
+
+// NewGRPCDataCollector creates a new gRPC data collector. userAuthDB and
+// certifierDB may be nil, leaving profiles and certificates out of reports.
+// Each Collect call is cancelled after queryTimeout; zero disables the limit.
+func NewGRPCDataCollector(
+	calculator reportdata.CalculatorDataClient,
+	tracker reportdata.TrackerDataClient,
+	wallet reportdata.WalletDataClient,
+	userAuthDB *database.PostgresDB,
+	certifierDB *database.PostgresDB,
+	queryTimeout time.Duration,
+	logger *logger.Logger,
+) *GRPCDataCollector {
+	return &GRPCDataCollector{
+		calculator:   calculator,
+		tracker:      tracker,
+		wallet:       wallet,
+		databases:    NewDatabaseDataCollector(nil, nil, nil, userAuthDB, certifierDB, 0, logger),
+		queryTimeout: queryTimeout,
+		logger:       logger,
+	}
+}
+
+// CollectFootprintData collects carbon footprint data for a user
+func (c *GRPCDataCollector) CollectFootprintData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.FootprintReportData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.FootprintReportData, error) {
+		data, _, err := c.collectFootprintData(ctx, userID, startDate, endDate)
+		return data, err
+	})
+}
+
+// collectFootprintData implements CollectFootprintData under the query
+// timeout, also returning how many calculations were made on each day
+func (c *GRPCDataCollector) collectFootprintData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.FootprintReportData, map[time.Time]int64, error) {
+	c.logger.LogInfo(ctx, "collecting footprint data",
+		logger.String("user_id", userID),
+		logger.String("start_date", startDate.Format("2006-01-02")),
+		logger.String("end_date", endDate.Format("2006-01-02")))
+
+	footprint, err := c.calculator.GetFootprint(ctx, reportdata.NewPeriodRequest(userID, startDate, endDate))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get footprint: %w", err)
+	}
+
+	dailyCounts, err := parseDailyCounts(footprint.GetDailyCalculations())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read daily calculations: %w", err)
+	}
+
+	data := &models.FootprintReportData{
+		UserID:            userID,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		TotalCO2Kg:        decimal.NewFromFloat(footprint.GetTotalCo2Kg()),
+		TotalCalculations: footprint.GetTotalCalculations(),
+		ByActivityType:    make(map[string]decimal.Decimal),
+		ByCategory:        make(map[string]decimal.Decimal),
+		ByMonth:           make(map[string]decimal.Decimal),
+		TopActivities:     make([]models.ActivitySummary, 0),
+	}
+
+	days := endDate.Sub(startDate).Hours() / 24
+	if days > 0 {
+		data.AveragePerDay = data.TotalCO2Kg.Div(decimal.NewFromFloat(days))
+	}
+
+	for _, total := range footprint.GetByActivityType() {
+		co2Amount := decimal.NewFromFloat(total.GetCo2Kg())
+		data.ByActivityType[total.GetActivityType()] = co2Amount
+
+		if len(data.TopActivities) < 10 && total.GetCount() > 0 {
+			data.TopActivities = append(data.TopActivities, models.ActivitySummary{
+				ActivityType:       total.GetActivityType(),
+				Count:              total.GetCount(),
+				TotalCO2:           co2Amount,
+				AveragePerActivity: co2Amount.Div(decimal.NewFromInt(total.GetCount())),
+			})
+		}
+	}
+
+	for category, co2 := range footprint.GetByCategory() {
+		if category == "" {
+			category = models.UncategorizedCalculations
+		}
+		data.ByCategory[category] = data.ByCategory[category].Add(decimal.NewFromFloat(co2))
+	}
+
+	for month, co2 := range footprint.GetByMonth() {
+		data.ByMonth[month] = decimal.NewFromFloat(co2)
+	}
+
+	average, err := c.averageUserCO2(ctx, startDate, endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	data.AverageUserCO2Kg = average
+	data.ComparisonToAverage = models.CompareToAverage(data.TotalCO2Kg, average)
+
+	return data, dailyCounts, nil
+}
+
+// averageUserCO2 returns the average total CO2 of users with calculations in
+// a period, cached for globalAverageTTL per period
+func (c *GRPCDataCollector) averageUserCO2(ctx context.Context, startDate, endDate time.Time) (decimal.Decimal, error) {
+	key := newGlobalAverageKey(startDate, endDate)
+	if average, ok := c.averages.get(key); ok {
+		return average, nil
+	}
+
+	resp, err := c.calculator.GetAverageUserFootprint(ctx, reportdata.NewPeriodRequest("", startDate, endDate))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get average footprint: %w", err)
+	}
+
+	result := decimal.NewFromFloat(resp.GetAverageCo2Kg())
+	c.averages.put(key, result)
+
+	return result, nil
+}
+
+// CollectPlatformImpact collects impact totals across all users. Certificate
+// totals are left at zero when the certifier database is unavailable.
+func (c *GRPCDataCollector) CollectPlatformImpact(ctx context.Context) (*models.PlatformImpactData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.PlatformImpactData, error) {
+		return c.collectPlatformImpact(ctx)
+	})
+}
+
+// collectPlatformImpact implements CollectPlatformImpact under the query timeout
+func (c *GRPCDataCollector) collectPlatformImpact(ctx context.Context) (*models.PlatformImpactData, error) {
+	c.logger.LogInfo(ctx, "collecting platform impact data")
+
+	data := &models.PlatformImpactData{
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	footprint, err := c.calculator.GetPlatformTotals(ctx, &reportdata.PlatformTotalsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total footprint: %w", err)
+	}
+	data.TotalCO2CalculatedKg = decimal.NewFromFloat(footprint.GetTotalCo2Kg())
+	data.TotalCalculations = footprint.GetTotalCalculations()
+
+	credits, err := c.wallet.GetPlatformTotals(ctx, &reportdata.PlatformTotalsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total credits earned: %w", err)
+	}
+	data.TotalCreditsEarned = decimal.NewFromFloat(credits.GetTotalCreditsEarned())
+
+	if c.databases.certifierDB != nil {
+		if err := c.databases.collectCertificateTotals(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// CollectCarbonNeutrality collects a user's calculated emissions and the
+// certificate offsets they retired over a period. Status is left to the caller.
+func (c *GRPCDataCollector) CollectCarbonNeutrality(ctx context.Context, userID string, startDate, endDate time.Time) (*models.CarbonNeutralityData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.CarbonNeutralityData, error) {
+		return c.collectCarbonNeutrality(ctx, userID, startDate, endDate)
+	})
+}
+
+// collectCarbonNeutrality implements CollectCarbonNeutrality under the query timeout
+func (c *GRPCDataCollector) collectCarbonNeutrality(ctx context.Context, userID string, startDate, endDate time.Time) (*models.CarbonNeutralityData, error) {
+	data := &models.CarbonNeutralityData{
+		UserID:    userID,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+
+	footprint, err := c.calculator.GetFootprint(ctx, reportdata.NewPeriodRequest(userID, startDate, endDate))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total footprint: %w", err)
+	}
+	data.EmissionsCO2Kg = decimal.NewFromFloat(footprint.GetTotalCo2Kg())
+
+	if c.databases.certifierDB != nil {
+		if err := c.databases.collectRetiredOffsets(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// CollectCreditsData collects carbon credits data for a user
+func (c *GRPCDataCollector) CollectCreditsData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.CreditsReportData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.CreditsReportData, error) {
+		activities := c.activitySummary(ctx, userID, startDate, endDate)
+		return c.collectCreditsData(ctx, userID, startDate, endDate, activities)
+	})
+}
+
+// collectCreditsData implements CollectCreditsData under the query timeout,
+// taking top earning activities from the tracker's summary, which may be nil
+func (c *GRPCDataCollector) collectCreditsData(ctx context.Context, userID string, startDate, endDate time.Time, activities *reportdata.ActivitySummaryData) (*models.CreditsReportData, error) {
+	c.logger.LogInfo(ctx, "collecting credits data",
+		logger.String("user_id", userID))
+
+	credits, err := c.wallet.GetCredits(ctx, reportdata.NewPeriodRequest(userID, startDate, endDate))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credits: %w", err)
+	}
+
+	data := &models.CreditsReportData{
+		UserID:               userID,
+		StartDate:            startDate,
+		EndDate:              endDate,
+		CurrentBalance:       decimal.NewFromFloat(credits.GetCurrentBalance()),
+		TotalCreditsEarned:   decimal.NewFromFloat(credits.GetTotalEarned()),
+		TotalCreditsSpent:    decimal.NewFromFloat(credits.GetTotalSpent()),
+		BySource:             make(map[string]decimal.Decimal),
+		ByMonth:              make(map[string]decimal.Decimal),
+		TopEarningActivities: make([]models.ActivitySummary, 0),
+		RecentTransactions:   make([]models.TransactionSummary, 0),
+	}
+
+	// Keep transfer directions apart and spends out of the breakdown, as the
+	// database collector does
+	for _, group := range credits.GetTransactionGroups() {
+		data.TotalTransactions += group.GetCount()
+		if group.Source == nil || !models.CountsTowardCreditSource(group.GetType()) {
+			continue
+		}
+
+		key := models.CreditSourceKey(group.GetType(), group.GetSource())
+		data.BySource[key] = data.BySource[key].Add(decimal.NewFromFloat(group.GetAmount()))
+	}
+
+	for month, earned := range credits.GetEarnedByMonth() {
+		data.ByMonth[month] = decimal.NewFromFloat(earned)
+	}
+
+	for _, activity := range activities.GetTopEarningActivities() {
+		if activity.GetCount() == 0 {
+			continue
+		}
+		total := decimal.NewFromFloat(activity.GetCredits())
+		data.TopEarningActivities = append(data.TopEarningActivities, models.ActivitySummary{
+			ActivityType:       activity.GetActivityType(),
+			Count:              activity.GetCount(),
+			TotalCredits:       total,
+			AveragePerActivity: total.Div(decimal.NewFromInt(activity.GetCount())),
+		})
+	}
+
+	for _, record := range credits.GetRecentTransactions() {
+		txID, _ := uuid.Parse(record.GetId())
+		data.RecentTransactions = append(data.RecentTransactions, models.TransactionSummary{
+			ID:           txID,
+			Type:         record.GetType(),
+			Amount:       decimal.NewFromFloat(record.GetAmount()),
+			Description:  record.GetDescription(),
+			Counterparty: record.GetCounterparty(),
+			CreatedAt:    record.GetCreatedAt().AsTime(),
+		})
+	}
+
+	return data, nil
+}
+
+// activitySummary returns the tracker's summary of a user's eco activities,
+// or nil when the tracker cannot be reached. Reports still render without
+// activity data, as they do when the tracker database is unavailable.
+func (c *GRPCDataCollector) activitySummary(ctx context.Context, userID string, startDate, endDate time.Time) *reportdata.ActivitySummaryData {
+	summary, err := c.tracker.GetActivitySummary(ctx, reportdata.NewPeriodRequest(userID, startDate, endDate))
+	if err != nil {
+		c.logger.LogWarn(ctx, "tracker activity summary unavailable",
+			logger.String("user_id", userID),
+			logger.String("error", err.Error()))
+		return nil
+	}
+	return summary
+}
+
+// CollectSummaryData collects summary data for a user
+func (c *GRPCDataCollector) CollectSummaryData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.SummaryReportData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.SummaryReportData, error) {
+		return c.collectSummaryData(ctx, userID, startDate, endDate)
+	})
+}
+
+// collectSummaryData implements CollectSummaryData under the query timeout
+func (c *GRPCDataCollector) collectSummaryData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.SummaryReportData, error) {
+	c.logger.LogInfo(ctx, "collecting summary data",
+		logger.String("user_id", userID))
+
+	footprintData, dailyCounts, err := c.collectFootprintData(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect footprint data: %w", err)
+	}
+
+	activities := c.activitySummary(ctx, userID, startDate, endDate)
+
+	creditsData, err := c.collectCreditsData(ctx, userID, startDate, endDate, activities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect credits data: %w", err)
+	}
+
+	// Activities and calculations both count towards a day's activity
+	activityCounts, err := parseDailyCounts(activities.GetDailyActivities())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read daily activities: %w", err)
+	}
+	for day, count := range activityCounts {
+		dailyCounts[day] += count
+	}
+
+	days := endDate.Sub(startDate).Hours() / 24
+	var averageCO2PerDay, averageCreditsPerDay decimal.Decimal
+	if days > 0 {
+		averageCO2PerDay = footprintData.TotalCO2Kg.Div(decimal.NewFromFloat(days))
+		averageCreditsPerDay = creditsData.TotalCreditsEarned.Div(decimal.NewFromFloat(days))
+	}
+
+	data := &models.SummaryReportData{
+		UserID:               userID,
+		TotalCO2Kg:           footprintData.TotalCO2Kg,
+		TotalCreditsEarned:   creditsData.TotalCreditsEarned,
+		TotalCreditsSpent:    creditsData.TotalCreditsSpent,
+		CurrentBalance:       creditsData.CurrentBalance,
+		TotalActivities:      activities.GetTotalActivities(),
+		TotalCalculations:    footprintData.TotalCalculations,
+		TotalTransactions:    creditsData.TotalTransactions,
+		AverageCO2PerDay:     averageCO2PerDay,
+		AverageCreditsPerDay: averageCreditsPerDay,
+		StartDate:            startDate,
+		EndDate:              endDate,
+	}
+	data.MostActiveDay, data.LeastActiveDay = models.ActiveDays(dailyCounts, startDate, endDate)
+
+	return data, nil
+}
+
+// parseDailyCounts converts counts keyed by day to counts keyed by UTC midnight
+func parseDailyCounts(byDay map[string]int64) (map[time.Time]int64, error) {
+	counts := make(map[time.Time]int64, len(byDay))
+	for day, count := range byDay {
+		at, err := time.Parse(reportdata.DayFormat, day)
+		if err != nil {
+			return nil, fmt.Errorf("invalid day %q: %w", day, err)
+		}
+		counts[at] += count
+	}
+	return counts, nil
+}
+
+// CollectActivityFeed collects up to limit of a user's most recent entries
+// before the given time from each service, unordered. A service that cannot
+// be reached is listed in UnavailableSources instead of failing the whole feed.
+func (c *GRPCDataCollector) CollectActivityFeed(ctx context.Context, userID string, before time.Time, limit int) (*models.ActivityFeedData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.ActivityFeedData, error) {
+		return c.collectActivityFeed(ctx, userID, before, limit)
+	})
+}
+
+// collectActivityFeed implements CollectActivityFeed under the query timeout
+func (c *GRPCDataCollector) collectActivityFeed(ctx context.Context, userID string, before time.Time, limit int) (*models.ActivityFeedData, error) {
+	data := &models.ActivityFeedData{
+		UserID:             userID,
+		Entries:            make([]models.FeedEntry, 0),
+		UnavailableSources: make([]string, 0),
+	}
+
+	req := &reportdata.FeedRequest{
+		UserId: userID,
+		Before: timestamppb.New(before),
+		Limit:  int32(limit),
+	}
+
+	sources := []struct {
+		name string
+		list func() (*reportdata.FeedEntries, error)
+		// entryType maps the type a service reports to a feed entry type
+		entryType func(string) string
+	}{
+		{
+			name: models.FeedSourceTracker,
+			list: func() (*reportdata.FeedEntries, error) { return c.tracker.ListFeedEntries(ctx, req) },
+			entryType: func(string) string {
+				return models.FeedEntryActivityLogged
+			},
+		},
+		{
+			name: models.FeedSourceWallet,
+			list: func() (*reportdata.FeedEntries, error) { return c.wallet.ListFeedEntries(ctx, req) },
+			entryType: func(txType string) string {
+				if txType == models.FeedEntryTransferIn || txType == models.FeedEntryTransferOut {
+					return txType
+				}
+				return models.FeedEntryCreditsEarned
+			},
+		},
+	}
+
+	for _, source := range sources {
+		resp, err := source.list()
+		if err != nil {
+			c.logger.LogWarn(ctx, "activity feed source unavailable",
+				logger.String("source", source.name),
+				logger.String("user_id", userID),
+				logger.String("error", err.Error()))
+			data.UnavailableSources = append(data.UnavailableSources, source.name)
+			continue
+		}
+
+		for _, entry := range resp.GetEntries() {
+			data.Entries = append(data.Entries, models.FeedEntry{
+				ID:          entry.GetId(),
+				Type:        source.entryType(entry.GetType()),
+				Source:      source.name,
+				Description: entry.GetDescription(),
+				Amount:      decimal.NewFromFloat(entry.GetAmount()),
+				OccurredAt:  entry.GetOccurredAt().AsTime(),
+			})
+		}
+	}
+
+	if c.databases.certifierDB == nil {
+		data.UnavailableSources = append(data.UnavailableSources, models.FeedSourceCertifier)
+		return data, nil
+	}
+
+	entries, err := c.databases.queryFeedEntries(ctx, c.databases.certifierDB, certifierFeedQuery, userID, before, limit, models.FeedEntryCertificateIssued)
+	if err != nil {
+		c.logger.LogWarn(ctx, "activity feed source unavailable",
+			logger.String("source", models.FeedSourceCertifier),
+			logger.String("user_id", userID),
+			logger.String("error", err.Error()))
+		data.UnavailableSources = append(data.UnavailableSources, models.FeedSourceCertifier)
+		return data, nil
+	}
+	for i := range entries {
+		entries[i].Source = models.FeedSourceCertifier
+	}
+	data.Entries = append(data.Entries, entries...)
+
+	return data, nil
+}
+
+// CollectDataExport collects every record held about a user across services
+func (c *GRPCDataCollector) CollectDataExport(ctx context.Context, userID string) (*models.DataExportData, error) {
+	return timeout.Do(ctx, c.queryTimeout, func(ctx context.Context) (*models.DataExportData, error) {
+		return c.collectDataExport(ctx, userID)
+	})
+}
+
+// collectDataExport implements CollectDataExport under the query timeout
+func (c *GRPCDataCollector) collectDataExport(ctx context.Context, userID string) (*models.DataExportData, error) {
+	c.logger.LogInfo(ctx, "collecting data export",
+		logger.String("user_id", userID))
+
+	data := &models.DataExportData{
+		UserID:       userID,
+		ExportedAt:   time.Now().UTC(),
+		Profile:      make(map[string]interface{}),
+		Certificates: make([]map[string]interface{}, 0),
+	}
+
+	if c.databases.userAuthDB != nil {
+		if err := c.databases.collectProfile(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	req := &reportdata.UserRequest{UserId: userID}
+
+	calculations, err := c.calculator.ExportCalculations(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calculations: %w", err)
+	}
+	data.Calculations = calculations.Maps()
+
+	activities, err := c.tracker.ExportActivities(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activities: %w", err)
+	}
+	data.Activities = activities.Maps()
+
+	transactions, err := c.wallet.ExportTransactions(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	data.Transactions = transactions.Maps()
+
+	if c.databases.certifierDB != nil {
+		if err := c.databases.collectCertificates(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/reporting/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/proto/reportdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	grpcTestStart = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	grpcTestEnd   = time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+)
+
+// fakeCalculatorData serves canned calculator data for user-1
+type fakeCalculatorData struct {
+	reportdata.UnimplementedCalculatorDataServer
+	averageCalls atomic.Int32
+}
+
+func (f *fakeCalculatorData) GetFootprint(ctx context.Context, req *reportdata.PeriodRequest) (*reportdata.FootprintData, error) {
+	if _, _, err := req.Period(); err != nil {
+		return nil, err
+	}
+	if req.GetUserId() != "user-1" {
+		return &reportdata.FootprintData{}, nil
+	}
+	return &reportdata.FootprintData{
+		TotalCo2Kg:        120,
+		TotalCalculations: 4,
+		ByActivityType: []*reportdata.ActivityTotal{
+			{ActivityType: "flight", Count: 1, Co2Kg: 100},
+			{ActivityType: "electricity", Count: 4, Co2Kg: 20},
+		},
+		ByCategory:        map[string]float64{"travel": 100, "": 20},
+		ByMonth:           map[string]float64{"2024-03": 120},
+		DailyCalculations: map[string]int64{"2024-03-01": 1, "2024-03-03": 3},
+	}, nil
+}
+
+func (f *fakeCalculatorData) GetAverageUserFootprint(ctx context.Context, req *reportdata.PeriodRequest) (*reportdata.AverageUserFootprint, error) {
+	f.averageCalls.Add(1)
+	return &reportdata.AverageUserFootprint{AverageCo2Kg: 80}, nil
+}
+
+func (f *fakeCalculatorData) GetPlatformTotals(ctx context.Context, req *reportdata.PlatformTotalsRequest) (*reportdata.CalculatorPlatformTotals, error) {
+	return &reportdata.CalculatorPlatformTotals{TotalCo2Kg: 5000, TotalCalculations: 250}, nil
+}
+
+func (f *fakeCalculatorData) ExportCalculations(ctx context.Context, req *reportdata.UserRequest) (*reportdata.ExportRows, error) {
+	return reportdata.NewExportRows([]map[string]interface{}{
+		{"id": "calc-1", "total_co2_kg": 100.0, "created_at": grpcTestStart},
+	})
+}
+
+// fakeTrackerData serves canned eco activity data, or fails every call
+type fakeTrackerData struct {
+	reportdata.UnimplementedTrackerDataServer
+	down bool
+}
+
+func (f *fakeTrackerData) GetActivitySummary(ctx context.Context, req *reportdata.PeriodRequest) (*reportdata.ActivitySummaryData, error) {
+	if f.down {
+		return nil, status.Error(codes.Unavailable, "tracker down")
+	}
+	return &reportdata.ActivitySummaryData{
+		TotalActivities: 5,
+		TopEarningActivities: []*reportdata.ActivityTotal{
+			{ActivityType: "cycling", Count: 4, Credits: 20},
+		},
+		DailyActivities: map[string]int64{"2024-03-02": 2, "2024-03-03": 3},
+	}, nil
+}
+
+func (f *fakeTrackerData) ListFeedEntries(ctx context.Context, req *reportdata.FeedRequest) (*reportdata.FeedEntries, error) {
+	if f.down {
+		return nil, status.Error(codes.Unavailable, "tracker down")
+	}
+	return &reportdata.FeedEntries{Entries: []*reportdata.FeedEntry{
+		{Id: "act-1", Type: "cycling", Description: "Ride to work", Amount: 5, OccurredAt: timestamppb.New(grpcTestStart)},
+	}}, nil
+}
+
+func (f *fakeTrackerData) ExportActivities(ctx context.Context, req *reportdata.UserRequest) (*reportdata.ExportRows, error) {
+	return &reportdata.ExportRows{}, nil
+}
+
+// fakeWalletData serves canned wallet data
+type fakeWalletData struct {
+	reportdata.UnimplementedWalletDataServer
+}
+
+func (f *fakeWalletData) GetCredits(ctx context.Context, req *reportdata.PeriodRequest) (*reportdata.CreditsData, error) {
+	activity := "eco_activity"
+	transfer := "transfer"
+	spend := "purchase"
+	return &reportdata.CreditsData{
+		CurrentBalance: 30,
+		TotalEarned:    50,
+		TotalSpent:     20,
+		TransactionGroups: []*reportdata.TransactionGroup{
+			{Source: &activity, Type: models.TransactionTypeCreditEarned, Count: 3, Amount: 40},
+			{Source: &transfer, Type: models.TransactionTypeTransferIn, Count: 1, Amount: 10},
+			{Source: &transfer, Type: models.TransactionTypeTransferOut, Count: 1, Amount: 5},
+			{Source: &spend, Type: "credit_spent", Count: 2, Amount: 15},
+		},
+		EarnedByMonth: map[string]float64{"2024-03": 50},
+		RecentTransactions: []*reportdata.TransactionRecord{
+			{
+				Id:           "7b7e1c1e-8c9a-4f43-9a4f-7d1f3b0c2a11",
+				Type:         models.TransactionTypeTransferIn,
+				Amount:       10,
+				Description:  "Gift",
+				Counterparty: "user-2",
+				CreatedAt:    timestamppb.New(grpcTestStart),
+			},
+		},
+	}, nil
+}
+
+func (f *fakeWalletData) GetPlatformTotals(ctx context.Context, req *reportdata.PlatformTotalsRequest) (*reportdata.WalletPlatformTotals, error) {
+	return &reportdata.WalletPlatformTotals{TotalCreditsEarned: 900}, nil
+}
+
+func (f *fakeWalletData) ListFeedEntries(ctx context.Context, req *reportdata.FeedRequest) (*reportdata.FeedEntries, error) {
+	return &reportdata.FeedEntries{Entries: []*reportdata.FeedEntry{
+		{Id: "tx-1", Type: models.TransactionTypeTransferOut, Description: "Gift", Amount: 5, OccurredAt: timestamppb.New(grpcTestStart)},
+		{Id: "tx-2", Type: models.TransactionTypeBonus, Description: "Streak bonus", Amount: 2, OccurredAt: timestamppb.New(grpcTestStart)},
+	}}, nil
+}
+
+func (f *fakeWalletData) ExportTransactions(ctx context.Context, req *reportdata.UserRequest) (*reportdata.ExportRows, error) {
+	row, err := structpb.NewStruct(map[string]interface{}{"id": "tx-1", "amount": 5})
+	if err != nil {
+		return nil, err
+	}
+	return &reportdata.ExportRows{Rows: []*structpb.Struct{row}}, nil
+}
+
+// newTestGRPCDataCollector serves the fakes in process and returns a
+// collector connected to them
+func newTestGRPCDataCollector(t *testing.T, calculator *fakeCalculatorData, tracker *fakeTrackerData) *GRPCDataCollector {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	reportdata.RegisterCalculatorDataServer(server, calculator)
+	reportdata.RegisterTrackerDataServer(server, tracker)
+	reportdata.RegisterWalletDataServer(server, &fakeWalletData{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Expected no error connecting, got %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewGRPCDataCollector(
+		reportdata.NewCalculatorDataClient(conn),
+		reportdata.NewTrackerDataClient(conn),
+		reportdata.NewWalletDataClient(conn),
+		nil,
+		nil,
+		5*time.Second,
+		logger.New("error"),
+	)
+}
+
+func TestGRPCDataCollector_CollectFootprintData(t *testing.T) {
+	calculator := &fakeCalculatorData{}
+	collector := newTestGRPCDataCollector(t, calculator, &fakeTrackerData{})
+	ctx := context.Background()
+
+	data, err := collector.CollectFootprintData(ctx, "user-1", grpcTestStart, grpcTestEnd)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !data.TotalCO2Kg.Equal(decimal.NewFromInt(120)) || data.TotalCalculations != 4 {
+		t.Errorf("Expected 120 kg over 4 calculations, got %s over %d", data.TotalCO2Kg, data.TotalCalculations)
+	}
+	if !data.AveragePerDay.Equal(decimal.NewFromInt(30)) {
+		t.Errorf("Expected 30 kg per day, got %s", data.AveragePerDay)
+	}
+	if len(data.TopActivities) != 2 || data.TopActivities[0].ActivityType != "flight" {
+		t.Fatalf("Expected activities in the order served, got %+v", data.TopActivities)
+	}
+	if !data.TopActivities[1].AveragePerActivity.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Expected 5 kg per electricity activity, got %s", data.TopActivities[1].AveragePerActivity)
+	}
+	if !data.ByCategory[models.UncategorizedCalculations].Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Expected calculations without a category to be uncategorized, got %v", data.ByCategory)
+	}
+	if !data.ByMonth["2024-03"].Equal(decimal.NewFromInt(120)) {
+		t.Errorf("Expected March total, got %v", data.ByMonth)
+	}
+	if !data.AverageUserCO2Kg.Equal(decimal.NewFromInt(80)) || !data.ComparisonToAverage.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("Expected 50%% above an 80 kg average, got %s%% of %s", data.ComparisonToAverage, data.AverageUserCO2Kg)
+	}
+
+	// The platform average is cached per period
+	if _, err := collector.CollectFootprintData(ctx, "user-2", grpcTestStart, grpcTestEnd); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls := calculator.averageCalls.Load(); calls != 1 {
+		t.Errorf("Expected the average to be fetched once, got %d calls", calls)
+	}
+}
+
+func TestGRPCDataCollector_CollectCreditsData(t *testing.T) {
+	collector := newTestGRPCDataCollector(t, &fakeCalculatorData{}, &fakeTrackerData{})
+
+	data, err := collector.CollectCreditsData(context.Background(), "user-1", grpcTestStart, grpcTestEnd)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !data.CurrentBalance.Equal(decimal.NewFromInt(30)) || !data.TotalCreditsEarned.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("Expected wallet totals, got balance %s earned %s", data.CurrentBalance, data.TotalCreditsEarned)
+	}
+	if data.TotalTransactions != 7 {
+		t.Errorf("Expected every completed transaction counted, got %d", data.TotalTransactions)
+	}
+	if len(data.BySource) != 3 ||
+		!data.BySource["eco_activity"].Equal(decimal.NewFromInt(40)) ||
+		!data.BySource[models.TransactionTypeTransferIn].Equal(decimal.NewFromInt(10)) ||
+		!data.BySource[models.TransactionTypeTransferOut].Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Expected sources with transfer directions apart and no spends, got %v", data.BySource)
+	}
+	if len(data.TopEarningActivities) != 1 || !data.TopEarningActivities[0].AveragePerActivity.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Expected top earners from the tracker, got %+v", data.TopEarningActivities)
+	}
+	if len(data.RecentTransactions) != 1 || data.RecentTransactions[0].Counterparty != "user-2" ||
+		data.RecentTransactions[0].ID.String() != "7b7e1c1e-8c9a-4f43-9a4f-7d1f3b0c2a11" {
+		t.Errorf("Expected the recent transfer, got %+v", data.RecentTransactions)
+	}
+}
+
+func TestGRPCDataCollector_CollectSummaryData(t *testing.T) {
+	collector := newTestGRPCDataCollector(t, &fakeCalculatorData{}, &fakeTrackerData{})
+
+	data, err := collector.CollectSummaryData(context.Background(), "user-1", grpcTestStart, grpcTestEnd)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if data.TotalActivities != 5 || data.TotalCalculations != 4 || data.TotalTransactions != 7 {
+		t.Errorf("Expected totals from every service, got %+v", data)
+	}
+	// Calculations and activities are added up per day: the 3rd has 6
+	if !data.MostActiveDay.Equal(time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected March 3rd most active, got %s", data.MostActiveDay)
+	}
+	if !data.LeastActiveDay.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected March 4th least active, got %s", data.LeastActiveDay)
+	}
+}
+
+func TestGRPCDataCollector_SummaryWithoutTracker(t *testing.T) {
+	collector := newTestGRPCDataCollector(t, &fakeCalculatorData{}, &fakeTrackerData{down: true})
+
+	data, err := collector.CollectSummaryData(context.Background(), "user-1", grpcTestStart, grpcTestEnd)
+	if err != nil {
+		t.Fatalf("Expected the summary without activity data, got %v", err)
+	}
+	if data.TotalActivities != 0 || !data.TotalCO2Kg.Equal(decimal.NewFromInt(120)) {
+		t.Errorf("Expected calculator data only, got %+v", data)
+	}
+}
+
+func TestGRPCDataCollector_CollectPlatformImpact(t *testing.T) {
+	collector := newTestGRPCDataCollector(t, &fakeCalculatorData{}, &fakeTrackerData{})
+
+	data, err := collector.CollectPlatformImpact(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !data.TotalCO2CalculatedKg.Equal(decimal.NewFromInt(5000)) || data.TotalCalculations != 250 ||
+		!data.TotalCreditsEarned.Equal(decimal.NewFromInt(900)) {
+		t.Errorf("Expected calculator and wallet totals, got %+v", data)
+	}
+}
+
+func TestGRPCDataCollector_CollectActivityFeed(t *testing.T) {
+	collector := newTestGRPCDataCollector(t, &fakeCalculatorData{}, &fakeTrackerData{})
+
+	data, err := collector.CollectActivityFeed(context.Background(), "user-1", grpcTestEnd, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	types := map[string]string{}
+	for _, entry := range data.Entries {
+		types[entry.ID] = entry.Source + "/" + entry.Type
+	}
+	expected := map[string]string{
+		"act-1": models.FeedSourceTracker + "/" + models.FeedEntryActivityLogged,
+		"tx-1":  models.FeedSourceWallet + "/" + models.FeedEntryTransferOut,
+		"tx-2":  models.FeedSourceWallet + "/" + models.FeedEntryCreditsEarned,
+	}
+	if len(types) != len(expected) {
+		t.Fatalf("Expected %d entries, got %v", len(expected), types)
+	}
+	for id, want := range expected {
+		if types[id] != want {
+			t.Errorf("Expected %s to be %s, got %s", id, want, types[id])
+		}
+	}
+	if len(data.UnavailableSources) != 1 || data.UnavailableSources[0] != models.FeedSourceCertifier {
+		t.Errorf("Expected only the unconfigured certifier unavailable, got %v", data.UnavailableSources)
+	}
+}
+
+func TestGRPCDataCollector_FeedWithoutTracker(t *testing.T) {
+	collector := newTestGRPCDataCollector(t, &fakeCalculatorData{}, &fakeTrackerData{down: true})
+
+	data, err := collector.CollectActivityFeed(context.Background(), "user-1", grpcTestEnd, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data.Entries) != 2 {
+		t.Errorf("Expected the wallet entries, got %+v", data.Entries)
+	}
+	if len(data.UnavailableSources) != 2 || data.UnavailableSources[0] != models.FeedSourceTracker {
+		t.Errorf("Expected the tracker listed as unavailable, got %v", data.UnavailableSources)
+	}
+}
+
+func TestGRPCDataCollector_CollectDataExport(t *testing.T) {
+	collector := newTestGRPCDataCollector(t, &fakeCalculatorData{}, &fakeTrackerData{})
+
+	data, err := collector.CollectDataExport(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(data.Calculations) != 1 || data.Calculations[0]["created_at"] != "2024-03-01T00:00:00Z" {
+		t.Errorf("Expected the calculation with its time as RFC 3339, got %v", data.Calculations)
+	}
+	if data.Activities == nil || len(data.Activities) != 0 {
+		t.Errorf("Expected no activities, got %v", data.Activities)
+	}
+	if len(data.Transactions) != 1 || data.Transactions[0]["amount"] != 5.0 {
+		t.Errorf("Expected the transaction, got %v", data.Transactions)
+	}
+}
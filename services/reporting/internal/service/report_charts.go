@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/wcharczuk/go-chart/v2"
+)
+
+// Chart images are rendered at this size in pixels and scaled to the
+// printable page width
+const (
+	chartWidth  = 800
+	chartHeight = 400
+)
+
+// chartValues returns the positive values of a breakdown sorted by label.
+// Charts cannot show negative or zero slices, so those are left out.
+func chartValues(breakdown map[string]decimal.Decimal) []chart.Value {
+	values := make([]chart.Value, 0, len(breakdown))
+	for label, amount := range breakdown {
+		if !amount.IsPositive() {
+			continue
+		}
+		value, _ := amount.Float64()
+		values = append(values, chart.Value{Label: label, Value: value})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Label < values[j].Label
+	})
+
+	return values
+}
+
+// barChartPNG renders a bar chart, or returns nil when there is nothing to plot
+func barChartPNG(breakdown map[string]decimal.Decimal) ([]byte, error) {
+	values := chartValues(breakdown)
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	barWidth := chartWidth / (2 * len(values))
+	if barWidth > 80 {
+		barWidth = 80
+	}
+
+	graph := chart.BarChart{
+		Width:    chartWidth,
+		Height:   chartHeight,
+		BarWidth: barWidth,
+		Background: chart.Style{
+			Padding: chart.Box{Top: 20},
+		},
+		Bars: values,
+	}
+
+	var buffer bytes.Buffer
+	if err := graph.Render(chart.PNG, &buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// monthlyLineChartPNG renders a line chart of amounts keyed by month
+// ("2006-01"), or returns nil when there are fewer than two months to join
+func monthlyLineChartPNG(byMonth map[string]decimal.Decimal) ([]byte, error) {
+	months := make([]string, 0, len(byMonth))
+	for month := range byMonth {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+
+	series := chart.TimeSeries{}
+	for _, month := range months {
+		at, err := time.Parse("2006-01", month)
+		if err != nil {
+			return nil, fmt.Errorf("invalid month %q: %w", month, err)
+		}
+		value, _ := byMonth[month].Float64()
+		series.XValues = append(series.XValues, at)
+		series.YValues = append(series.YValues, value)
+	}
+	if len(series.XValues) < 2 {
+		return nil, nil
+	}
+
+	graph := chart.Chart{
+		Width:  chartWidth,
+		Height: chartHeight,
+		XAxis: chart.XAxis{
+			ValueFormatter: chart.TimeValueFormatterWithFormat("2006-01"),
+		},
+		Series: []chart.Series{series},
+	}
+
+	var buffer bytes.Buffer
+	if err := graph.Render(chart.PNG, &buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// pieChartPNG renders a pie chart, or returns nil when there is nothing to plot
+func pieChartPNG(breakdown map[string]decimal.Decimal) ([]byte, error) {
+	values := chartValues(breakdown)
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	graph := chart.PieChart{
+		Width:  chartHeight,
+		Height: chartHeight,
+		Values: values,
+	}
+
+	var buffer bytes.Buffer
+	if err := graph.Render(chart.PNG, &buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// embedChart renders a chart and places it at the current position of the
+// PDF, width mm wide. A chart without data is skipped, and one that fails
+// to render is logged and skipped so the rest of the report still renders.
+func (r *PDFReportRenderer) embedChart(ctx context.Context, pdf *gofpdf.Fpdf, name string, width float64, render func() ([]byte, error)) {
+	if !pdf.Ok() {
+		return
+	}
+
+	image, err := render()
+	if err == nil && image == nil {
+		return
+	}
+	if err == nil {
+		pdf.RegisterImageOptionsReader(name, gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(image))
+		err = pdf.Error()
+	}
+	if err != nil {
+		// Drop the error so it does not fail the whole document
+		pdf.ClearError()
+		if r.logger != nil {
+			r.logger.LogWarn(ctx, "skipping report chart",
+				logger.String("chart", name),
+				logger.String("error", err.Error()))
+		}
+		return
+	}
+
+	pdf.ImageOptions(name, pdf.GetX(), pdf.GetY(), width, 0, true, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
 	"encoding/csv"
@@ -33,9 +34,9 @@ func (r *PDFReportRenderer) RenderPDF(ctx context.Context, reportType string, da
 
 	switch reportType {
 	case models.ReportTypeFootprint:
-		return r.renderFootprintPDF(pdf, data.(*models.FootprintReportData))
+		return r.renderFootprintPDF(ctx, pdf, data.(*models.FootprintReportData))
 	case models.ReportTypeCredits:
-		return r.renderCreditsPDF(pdf, data.(*models.CreditsReportData))
+		return r.renderCreditsPDF(ctx, pdf, data.(*models.CreditsReportData))
 	case models.ReportTypeSummary:
 		return r.renderSummaryPDF(pdf, data.(*models.SummaryReportData))
 	default:
@@ -65,8 +66,64 @@ func (r *PDFReportRenderer) RenderCSV(ctx context.Context, reportType string, da
 	}
 }
 
+// RenderArchive renders a report as a ZIP archive with one JSON file per section
+func (r *PDFReportRenderer) RenderArchive(ctx context.Context, reportType string, data interface{}) ([]byte, error) {
+	switch reportType {
+	case models.ReportTypeDataExport:
+		return r.renderDataExportArchive(data.(*models.DataExportData))
+	default:
+		return nil, fmt.Errorf("unsupported report type for archive: %s", reportType)
+	}
+}
+
+// renderDataExportArchive renders a user's data export as a ZIP archive
+func (r *PDFReportRenderer) renderDataExportArchive(data *models.DataExportData) ([]byte, error) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	sections := []struct {
+		name    string
+		content interface{}
+	}{
+		{"profile.json", data.Profile},
+		{"calculations.json", data.Calculations},
+		{"activities.json", data.Activities},
+		{"transactions.json", data.Transactions},
+		{"certificates.json", data.Certificates},
+		{"manifest.json", map[string]interface{}{
+			"user_id":      data.UserID,
+			"exported_at":  data.ExportedAt,
+			"calculations": len(data.Calculations),
+			"activities":   len(data.Activities),
+			"transactions": len(data.Transactions),
+			"certificates": len(data.Certificates),
+		}},
+	}
+
+	for _, section := range sections {
+		content, err := json.MarshalIndent(section.content, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", section.name, err)
+		}
+
+		file, err := archive.Create(section.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s to archive: %w", section.name, err)
+		}
+		if _, err := file.Write(content); err != nil {
+			return nil, fmt.Errorf("failed to write %s to archive: %w", section.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize archive: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
 // renderFootprintPDF renders carbon footprint data as PDF
-func (r *PDFReportRenderer) renderFootprintPDF(pdf *gofpdf.Fpdf, data *models.FootprintReportData) ([]byte, error) {
+func (r *PDFReportRenderer) renderFootprintPDF(ctx context.Context, pdf *gofpdf.Fpdf, data *models.FootprintReportData) ([]byte, error) {
 	// Title
 	pdf.Cell(190, 10, "Carbon Footprint Report")
 	pdf.Ln(15)
@@ -91,7 +148,14 @@ func (r *PDFReportRenderer) renderFootprintPDF(pdf *gofpdf.Fpdf, data *models.Fo
 	pdf.Cell(190, 6, fmt.Sprintf("Total Calculations: %d", data.TotalCalculations))
 	pdf.Ln(6)
 	pdf.Cell(190, 6, fmt.Sprintf("Average per Day: %.2f kg", avgPerDay))
-	pdf.Ln(15)
+	pdf.Ln(6)
+	if data.AverageUserCO2Kg.IsPositive() {
+		averageCO2, _ := data.AverageUserCO2Kg.Float64()
+		comparison, _ := data.ComparisonToAverage.Float64()
+		pdf.Cell(190, 6, fmt.Sprintf("Compared to Average User: %+.2f%% (average %.2f kg)", comparison, averageCO2))
+		pdf.Ln(6)
+	}
+	pdf.Ln(9)
 
 	// Activity breakdown
 	if len(data.ByActivityType) > 0 {
@@ -105,6 +169,38 @@ func (r *PDFReportRenderer) renderFootprintPDF(pdf *gofpdf.Fpdf, data *models.Fo
 			pdf.Cell(190, 6, fmt.Sprintf("%s: %.2f kg CO2", activityType, co2Float))
 			pdf.Ln(6)
 		}
+		pdf.Ln(4)
+
+		r.embedChart(ctx, pdf, "co2-by-activity-type", 190, func() ([]byte, error) {
+			return barChartPNG(data.ByActivityType)
+		})
+		pdf.Ln(6)
+	}
+
+	// Monthly trend, which needs at least two months to draw a line
+	if len(data.ByMonth) > 1 {
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(190, 8, "Emissions by Month")
+		pdf.Ln(10)
+
+		r.embedChart(ctx, pdf, "co2-by-month", 190, func() ([]byte, error) {
+			return monthlyLineChartPNG(data.ByMonth)
+		})
+		pdf.Ln(6)
+	}
+
+	// Category breakdown
+	if len(data.ByCategory) > 0 {
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(190, 8, "Breakdown by Category")
+		pdf.Ln(10)
+
+		pdf.SetFont("Arial", "", 11)
+		for category, co2 := range data.ByCategory {
+			co2Float, _ := co2.Float64()
+			pdf.Cell(190, 6, fmt.Sprintf("%s: %.2f kg CO2", category, co2Float))
+			pdf.Ln(6)
+		}
 		pdf.Ln(10)
 	}
 
@@ -132,7 +228,7 @@ func (r *PDFReportRenderer) renderFootprintPDF(pdf *gofpdf.Fpdf, data *models.Fo
 }
 
 // renderCreditsPDF renders carbon credits data as PDF
-func (r *PDFReportRenderer) renderCreditsPDF(pdf *gofpdf.Fpdf, data *models.CreditsReportData) ([]byte, error) {
+func (r *PDFReportRenderer) renderCreditsPDF(ctx context.Context, pdf *gofpdf.Fpdf, data *models.CreditsReportData) ([]byte, error) {
 	// Title
 	pdf.Cell(190, 10, "Carbon Credits Report")
 	pdf.Ln(15)
@@ -174,7 +270,12 @@ func (r *PDFReportRenderer) renderCreditsPDF(pdf *gofpdf.Fpdf, data *models.Cred
 			pdf.Cell(190, 6, fmt.Sprintf("%s: %.2f credits", source, creditsFloat))
 			pdf.Ln(6)
 		}
-		pdf.Ln(10)
+		pdf.Ln(4)
+
+		r.embedChart(ctx, pdf, "credits-by-source", 95, func() ([]byte, error) {
+			return pieChartPNG(data.BySource)
+		})
+		pdf.Ln(6)
 	}
 
 	// Top earning activities
@@ -282,6 +383,15 @@ func (r *PDFReportRenderer) renderFootprintCSV(writer *csv.Writer, data *models.
 		writer.Write([]string{activityType, co2.String(), "kg"})
 	}
 
+	// Write category breakdown
+	if len(data.ByCategory) > 0 {
+		writer.Write([]string{})
+		writer.Write([]string{"Category", "CO2 Emissions", "Unit"})
+		for category, co2 := range data.ByCategory {
+			writer.Write([]string{category, co2.String(), "kg"})
+		}
+	}
+
 	writer.Flush()
 	return []byte{}, writer.Error()
 }
@@ -301,11 +411,26 @@ func (r *PDFReportRenderer) renderCreditsCSV(writer *csv.Writer, data *models.Cr
 	writer.Write([]string{})
 
 	// Write credits by source
-	writer.Write([]string{"Source", "Credits Earned", "Unit"})
+	writer.Write([]string{"Source", "Credits", "Unit"})
 	for source, credits := range data.BySource {
 		writer.Write([]string{source, credits.String(), "credits"})
 	}
 
+	// Write recent transactions
+	if len(data.RecentTransactions) > 0 {
+		writer.Write([]string{})
+		writer.Write([]string{"Date", "Type", "Amount", "Counterparty", "Description"})
+		for _, tx := range data.RecentTransactions {
+			writer.Write([]string{
+				tx.CreatedAt.Format("2006-01-02"),
+				tx.Type,
+				tx.Amount.String(),
+				tx.Counterparty,
+				tx.Description,
+			})
+		}
+	}
+
 	writer.Flush()
 	return []byte{}, writer.Error()
 }
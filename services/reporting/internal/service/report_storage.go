@@ -0,0 +1,288 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrReportFileNotFound is returned when a report's file is missing from storage
+var ErrReportFileNotFound = errors.New("report file not found")
+
+// ReportStorage stores generated report files under slash-separated keys
+type ReportStorage interface {
+	Save(ctx context.Context, key string, content []byte) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// LocalReportStorage stores report files in a directory on the local filesystem
+type LocalReportStorage struct {
+	root string
+}
+
+// NewLocalReportStorage creates a report storage rooted at dir
+func NewLocalReportStorage(dir string) *LocalReportStorage {
+	return &LocalReportStorage{root: dir}
+}
+
+// Save writes content to the key's file, replacing it atomically so a
+// download never sees a partly written report
+func (s *LocalReportStorage) Save(ctx context.Context, key string, content []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".report-*")
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store report file: %w", err)
+	}
+
+	return nil
+}
+
+// Open opens the key's file for reading
+func (s *LocalReportStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrReportFileNotFound, key)
+		}
+		return nil, fmt.Errorf("failed to open report file: %w", err)
+	}
+
+	return file, nil
+}
+
+// Delete removes the key's file; a missing file is not an error
+func (s *LocalReportStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete report file: %w", err)
+	}
+
+	return nil
+}
+
+// path resolves a key inside the storage directory, rejecting keys that
+// would escape it
+func (s *LocalReportStorage) path(key string) (string, error) {
+	local := filepath.FromSlash(key)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("invalid report file key %q", key)
+	}
+	return filepath.Join(s.root, local), nil
+}
+
+// S3Config holds the bucket and credentials for S3 report storage
+type S3Config struct {
+	Bucket string
+	Region string
+	// Endpoint overrides the AWS endpoint for S3-compatible stores such as
+	// MinIO; objects are addressed path-style as {endpoint}/{bucket}/{key}
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3ReportStorage stores report files as objects in an S3 bucket, signing
+// requests with AWS Signature Version 4
+type S3ReportStorage struct {
+	config S3Config
+	client *http.Client
+}
+
+// NewS3ReportStorage creates a report storage backed by an S3 bucket
+func NewS3ReportStorage(config S3Config, client *http.Client) (*S3ReportStorage, error) {
+	if config.Bucket == "" || config.Region == "" {
+		return nil, fmt.Errorf("S3 report storage requires a bucket and region")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("S3 report storage requires access credentials")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", config.Region)
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &S3ReportStorage{config: config, client: client}, nil
+}
+
+// Save uploads content as the key's object
+func (s *S3ReportStorage) Save(ctx context.Context, key string, content []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, content)
+	if err != nil {
+		return fmt.Errorf("failed to upload report file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to upload report file: %s", s3Error(resp))
+	}
+
+	return nil
+}
+
+// Open downloads the key's object; the caller closes the returned body
+func (s *S3ReportStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download report file: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrReportFileNotFound, key)
+	default:
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("failed to download report file: %s", s3Error(resp))
+	}
+}
+
+// Delete removes the key's object; S3 treats a missing object as deleted
+func (s *S3ReportStorage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete report file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to delete report file: %s", s3Error(resp))
+	}
+
+	return nil
+}
+
+// do sends a signed request for the key's object
+func (s *S3ReportStorage) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	target, err := url.Parse(s.config.Endpoint + "/" + s.config.Bucket + "/" + strings.TrimLeft(key, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 object URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+
+	s.sign(req, body, time.Now().UTC())
+
+	return s.client.Do(req)
+}
+
+// sign adds AWS Signature Version 4 headers to req
+func (s *S3ReportStorage) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if s.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.config.SessionToken)
+		headers = append(headers, "x-amz-security-token")
+		values["x-amz-security-token"] = s.config.SessionToken
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headers {
+		canonicalHeaders.WriteString(name + ":" + values[name] + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), day)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// s3Error describes an unexpected S3 response
+func s3Error(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Sprintf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
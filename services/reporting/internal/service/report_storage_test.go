@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLocalReportStorage_SaveOpenDelete(t *testing.T) {
+	storage := NewLocalReportStorage(t.TempDir())
+	ctx := context.Background()
+	key := "reports/user-1/report.csv"
+
+	if err := storage.Save(ctx, key, []byte("a,b\n1,2\n")); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+	// Saving again replaces the file
+	if err := storage.Save(ctx, key, []byte("a,b\n3,4\n")); err != nil {
+		t.Fatalf("Expected no error saving again, got %v", err)
+	}
+
+	file, err := storage.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Expected no error opening, got %v", err)
+	}
+	content, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		t.Fatalf("Expected no error reading, got %v", err)
+	}
+	if string(content) != "a,b\n3,4\n" {
+		t.Errorf("Expected saved content, got %q", content)
+	}
+
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Fatalf("Expected no error deleting, got %v", err)
+	}
+	if _, err := storage.Open(ctx, key); !errors.Is(err, ErrReportFileNotFound) {
+		t.Errorf("Expected ErrReportFileNotFound after delete, got %v", err)
+	}
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Errorf("Expected deleting a missing file to succeed, got %v", err)
+	}
+}
+
+func TestLocalReportStorage_RejectsKeysOutsideRoot(t *testing.T) {
+	storage := NewLocalReportStorage(t.TempDir())
+
+	for _, key := range []string{"../escape.pdf", "/etc/passwd", "reports/../../escape.pdf"} {
+		if err := storage.Save(context.Background(), key, []byte("x")); err == nil {
+			t.Errorf("Expected key %q to be rejected", key)
+		}
+	}
+}
+
+// fakeS3 stores objects in memory and rejects unsigned requests
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3ReportStorage_SaveOpenDelete(t *testing.T) {
+	s3 := &fakeS3{objects: map[string][]byte{}}
+	server := httptest.NewServer(s3)
+	defer server.Close()
+
+	storage, err := NewS3ReportStorage(S3Config{
+		Bucket:          "reports-bucket",
+		Region:          "eu-west-1",
+		Endpoint:        server.URL,
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ctx := context.Background()
+	key := "reports/user-1/report.json"
+
+	if err := storage.Save(ctx, key, []byte(`{"ok":true}`)); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+	if _, ok := s3.objects["/reports-bucket/"+key]; !ok {
+		t.Fatalf("Expected object stored path-style under the bucket, got %v", s3.objects)
+	}
+
+	file, err := storage.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Expected no error opening, got %v", err)
+	}
+	content, _ := io.ReadAll(file)
+	file.Close()
+	if string(content) != `{"ok":true}` {
+		t.Errorf("Expected saved content, got %q", content)
+	}
+
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Fatalf("Expected no error deleting, got %v", err)
+	}
+	if _, err := storage.Open(ctx, key); !errors.Is(err, ErrReportFileNotFound) {
+		t.Errorf("Expected ErrReportFileNotFound after delete, got %v", err)
+	}
+}
+
+func TestNewS3ReportStorage_RequiresBucketAndCredentials(t *testing.T) {
+	if _, err := NewS3ReportStorage(S3Config{Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil); err == nil {
+		t.Error("Expected an error without a bucket")
+	}
+	if _, err := NewS3ReportStorage(S3Config{Bucket: "b", Region: "eu-west-1"}, nil); err == nil {
+		t.Error("Expected an error without credentials")
+	}
+}
@@ -3,34 +3,77 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/reporting/internal/models"
 	"github.com/sloweyyy/GreenLedger/services/reporting/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/database"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
 )
 
+// ErrInvalidReportRequest is returned for report requests that fail validation
+var ErrInvalidReportRequest = errors.New("invalid report request")
+
+// ErrReportNotReady is returned when downloading a report that has not
+// been generated yet or failed to generate
+var ErrReportNotReady = errors.New("report not ready")
+
+// ErrReportExpired is returned when downloading a report past its expiry
+var ErrReportExpired = errors.New("report expired")
+
+// platformImpactTTL is how long platform-wide impact totals are cached
+const platformImpactTTL = 5 * time.Minute
+
+// DefaultMaxDateRange is the longest report period accepted when none is configured
+const DefaultMaxDateRange = 365 * 24 * time.Hour
+
+// metricsServiceName labels the business metrics recorded by reporting
+const metricsServiceName = "reporting"
+
 // ReportingService handles report generation and management
 type ReportingService struct {
-	reportRepo     *repository.ReportRepository
+	reportRepo     repository.ReportRepositoryInterface
 	dataCollector  DataCollector
 	reportRenderer ReportRenderer
+	storage        ReportStorage
+	maxDateRange   time.Duration
+	metrics        *monitoring.Metrics
 	logger         *logger.Logger
+
+	impactMu       sync.Mutex
+	impact         *models.PlatformImpactData
+	impactCachedAt time.Time
 }
 
-// NewReportingService creates a new reporting service
+// NewReportingService creates a new reporting service. metrics may be nil to
+// record no business metrics.
 func NewReportingService(
-	reportRepo *repository.ReportRepository,
+	reportRepo repository.ReportRepositoryInterface,
 	dataCollector DataCollector,
 	reportRenderer ReportRenderer,
+	storage ReportStorage,
+	maxDateRange time.Duration,
+	metrics *monitoring.Metrics,
 	logger *logger.Logger,
 ) *ReportingService {
+	if maxDateRange <= 0 {
+		maxDateRange = DefaultMaxDateRange
+	}
+
 	return &ReportingService{
 		reportRepo:     reportRepo,
 		dataCollector:  dataCollector,
 		reportRenderer: reportRenderer,
+		storage:        storage,
+		maxDateRange:   maxDateRange,
+		metrics:        metrics,
 		logger:         logger,
 	}
 }
@@ -47,6 +90,23 @@ type GenerateReportRequest struct {
 	Parameters  map[string]interface{} `json:"parameters"`
 }
 
+// PreviewReportRequest represents a request for report data without a stored report
+type PreviewReportRequest struct {
+	UserID    string    `json:"user_id"`
+	Type      string    `json:"type"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+// ReportPreviewResponse carries report data collected on demand
+type ReportPreviewResponse struct {
+	Type        string      `json:"type"`
+	StartDate   time.Time   `json:"start_date"`
+	EndDate     time.Time   `json:"end_date"`
+	GeneratedAt time.Time   `json:"generated_at"`
+	Data        interface{} `json:"data"`
+}
+
 // ReportResponse represents a report in API responses
 type ReportResponse struct {
 	ID          uuid.UUID  `json:"id"`
@@ -65,11 +125,23 @@ type ReportResponse struct {
 	CreatedAt   time.Time  `json:"created_at"`
 }
 
+// ReportFile is a stored report opened for download; the caller closes Content
+type ReportFile struct {
+	Content     io.ReadCloser
+	ContentType string
+	Filename    string
+	Size        int64
+}
+
 // DataCollector interface for collecting report data
 type DataCollector interface {
 	CollectFootprintData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.FootprintReportData, error)
 	CollectCreditsData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.CreditsReportData, error)
 	CollectSummaryData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.SummaryReportData, error)
+	CollectDataExport(ctx context.Context, userID string) (*models.DataExportData, error)
+	CollectPlatformImpact(ctx context.Context) (*models.PlatformImpactData, error)
+	CollectCarbonNeutrality(ctx context.Context, userID string, startDate, endDate time.Time) (*models.CarbonNeutralityData, error)
+	CollectActivityFeed(ctx context.Context, userID string, before time.Time, limit int) (*models.ActivityFeedData, error)
 }
 
 // ReportRenderer interface for rendering reports
@@ -77,6 +149,32 @@ type ReportRenderer interface {
 	RenderPDF(ctx context.Context, reportType string, data interface{}) ([]byte, error)
 	RenderJSON(ctx context.Context, data interface{}) ([]byte, error)
 	RenderCSV(ctx context.Context, reportType string, data interface{}) ([]byte, error)
+	RenderArchive(ctx context.Context, reportType string, data interface{}) ([]byte, error)
+}
+
+// dataExportExpiry is how long a generated personal data export stays available
+const dataExportExpiry = 7 * 24 * time.Hour
+
+// GetPlatformImpact returns platform-wide impact totals for public impact
+// pages. Totals are cached for platformImpactTTL since they aggregate every
+// user's data and only need to be approximately current.
+func (s *ReportingService) GetPlatformImpact(ctx context.Context) (*models.PlatformImpactData, error) {
+	s.impactMu.Lock()
+	defer s.impactMu.Unlock()
+
+	if s.impact != nil && time.Since(s.impactCachedAt) < platformImpactTTL {
+		return s.impact, nil
+	}
+
+	impact, err := s.dataCollector.CollectPlatformImpact(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect platform impact: %w", err)
+	}
+
+	s.impact = impact
+	s.impactCachedAt = time.Now()
+
+	return impact, nil
 }
 
 // GenerateReport generates a new report
@@ -88,7 +186,7 @@ func (s *ReportingService) GenerateReport(ctx context.Context, req *GenerateRepo
 
 	// Validate request
 	if err := s.validateReportRequest(req); err != nil {
-		return nil, fmt.Errorf("invalid request: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReportRequest, err)
 	}
 
 	// Create report record
@@ -108,7 +206,7 @@ func (s *ReportingService) GenerateReport(ctx context.Context, req *GenerateRepo
 	}
 
 	// Set expiration (30 days from now)
-	expiresAt := time.Now().AddDate(0, 0, 30)
+	expiresAt := time.Now().UTC().AddDate(0, 0, 30)
 	report.ExpiresAt = &expiresAt
 
 	// Serialize parameters
@@ -131,6 +229,109 @@ func (s *ReportingService) GenerateReport(ctx context.Context, req *GenerateRepo
 	return s.reportToResponse(report), nil
 }
 
+// PreviewReport collects report data synchronously and returns it without
+// storing a report. Only report types backed by a collector can be previewed.
+func (s *ReportingService) PreviewReport(ctx context.Context, req *PreviewReportRequest) (*ReportPreviewResponse, error) {
+	switch req.Type {
+	case models.ReportTypeFootprint, models.ReportTypeCredits, models.ReportTypeSummary:
+	default:
+		return nil, fmt.Errorf("%w: report type %q cannot be previewed", ErrInvalidReportRequest, req.Type)
+	}
+
+	if err := s.validateDateRange(req.StartDate, req.EndDate); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReportRequest, err)
+	}
+
+	data, err := s.collectReportData(ctx, req.Type, req.UserID, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect report data: %w", err)
+	}
+
+	return &ReportPreviewResponse{
+		Type:        req.Type,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		GeneratedAt: time.Now().UTC(),
+		Data:        data,
+	}, nil
+}
+
+// GetCarbonNeutrality reports whether the offsets a user retired over a
+// period cover the emissions they calculated, and by how much
+func (s *ReportingService) GetCarbonNeutrality(ctx context.Context, userID string, startDate, endDate time.Time) (*models.CarbonNeutralityData, error) {
+	if err := s.validateDateRange(startDate, endDate); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReportRequest, err)
+	}
+
+	data, err := s.dataCollector.CollectCarbonNeutrality(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect carbon neutrality data: %w", err)
+	}
+
+	data.NetCO2Kg, data.Status = models.NetCarbon(data.EmissionsCO2Kg, data.RetiredOffsetKg)
+	data.GapCO2Kg = data.NetCO2Kg.Abs()
+
+	return data, nil
+}
+
+// GetActivityFeed returns up to limit of a user's most recent activities,
+// earned credits, transfers and issued certificates before the given time,
+// merged newest first. Sources that cannot be read are reported rather than
+// failing the feed.
+func (s *ReportingService) GetActivityFeed(ctx context.Context, userID string, before time.Time, limit int) (*models.ActivityFeedData, error) {
+	// One extra entry per source tells whether another page follows
+	feed, err := s.dataCollector.CollectActivityFeed(ctx, userID, before, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect activity feed: %w", err)
+	}
+
+	sort.SliceStable(feed.Entries, func(i, j int) bool {
+		return feed.Entries[i].OccurredAt.After(feed.Entries[j].OccurredAt)
+	})
+
+	feed.NextBefore = nil
+	if len(feed.Entries) > limit {
+		feed.Entries = feed.Entries[:limit]
+		if limit > 0 {
+			nextBefore := feed.Entries[limit-1].OccurredAt
+			feed.NextBefore = &nextBefore
+		}
+	}
+
+	return feed, nil
+}
+
+// RequestDataExport starts an asynchronous export of all data held about a user.
+// The resulting archive is delivered through the regular report download flow.
+func (s *ReportingService) RequestDataExport(ctx context.Context, userID string) (*ReportResponse, error) {
+	s.logger.LogInfo(ctx, "requesting data export",
+		logger.String("user_id", userID))
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(dataExportExpiry)
+
+	report := &models.Report{
+		UserID:      userID,
+		Type:        models.ReportTypeDataExport,
+		Title:       fmt.Sprintf("Personal Data Export (%s)", now.Format("2006-01-02")),
+		Description: "Profile, calculations, activities, transactions and certificates",
+		Format:      models.ReportFormatZIP,
+		Status:      models.ReportStatusPending,
+		StartDate:   time.Unix(0, 0).UTC(),
+		EndDate:     now,
+		ExpiresAt:   &expiresAt,
+	}
+
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, fmt.Errorf("failed to create data export: %w", err)
+	}
+
+	// Generate export asynchronously
+	go s.generateReportAsync(context.Background(), report)
+
+	return s.reportToResponse(report), nil
+}
+
 // GetReport retrieves a report by ID
 func (s *ReportingService) GetReport(ctx context.Context, reportID uuid.UUID, userID string) (*ReportResponse, error) {
 	report, err := s.reportRepo.GetByID(ctx, reportID)
@@ -140,7 +341,7 @@ func (s *ReportingService) GetReport(ctx context.Context, reportID uuid.UUID, us
 
 	// Check if user owns the report
 	if report.UserID != userID {
-		return nil, fmt.Errorf("report not found")
+		return nil, fmt.Errorf("report not found: %w", database.ErrNotFound)
 	}
 
 	return s.reportToResponse(report), nil
@@ -170,13 +371,12 @@ func (s *ReportingService) DeleteReport(ctx context.Context, reportID uuid.UUID,
 
 	// Check if user owns the report
 	if report.UserID != userID {
-		return fmt.Errorf("report not found")
+		return fmt.Errorf("report not found: %w", database.ErrNotFound)
 	}
 
-	// Delete report file if exists
-	if report.FilePath != "" {
-		// TODO: Delete file from storage
-	}
+	// A file left behind is only wasted space, so it does not stop the
+	// report from being deleted
+	s.deleteReportFile(ctx, report)
 
 	// Delete report record
 	if err := s.reportRepo.Delete(ctx, reportID); err != nil {
@@ -190,6 +390,78 @@ func (s *ReportingService) DeleteReport(ctx context.Context, reportID uuid.UUID,
 	return nil
 }
 
+// DownloadReport opens a user's completed report file for download
+func (s *ReportingService) DownloadReport(ctx context.Context, reportID uuid.UUID, userID string) (*ReportFile, error) {
+	report, err := s.reportRepo.GetByID(ctx, reportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+
+	// Check if user owns the report
+	if report.UserID != userID {
+		return nil, fmt.Errorf("report not found: %w", database.ErrNotFound)
+	}
+
+	if report.Status == models.ReportStatusExpired || report.IsExpired() {
+		return nil, fmt.Errorf("report %s: %w", reportID, ErrReportExpired)
+	}
+	if !report.IsCompleted() || report.FilePath == "" {
+		return nil, fmt.Errorf("%w: report is %s", ErrReportNotReady, report.Status)
+	}
+
+	content, err := s.storage.Open(ctx, report.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open report file: %w", err)
+	}
+
+	return &ReportFile{
+		Content:     content,
+		ContentType: reportContentType(report.Format),
+		Filename:    reportFilename(report),
+		Size:        report.FileSize,
+	}, nil
+}
+
+// EraseUserData deletes a deleted user's reports, their files and schedules
+func (s *ReportingService) EraseUserData(ctx context.Context, userID string) error {
+	reports, _, err := s.reportRepo.GetByUserID(ctx, userID, -1, 0)
+	if err != nil {
+		s.logger.LogError(ctx, "failed to list user reports for erasure", err,
+			logger.String("user_id", userID))
+		return fmt.Errorf("failed to list user reports: %w", err)
+	}
+	for _, report := range reports {
+		s.deleteReportFile(ctx, report)
+	}
+
+	if err := s.reportRepo.DeleteByUserID(ctx, userID); err != nil {
+		s.logger.LogError(ctx, "failed to erase user reports", err,
+			logger.String("user_id", userID))
+		return fmt.Errorf("failed to erase user reports: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "report data erased for deleted user",
+		logger.String("user_id", userID))
+
+	return nil
+}
+
+// collectReportData runs the data collector for a report type
+func (s *ReportingService) collectReportData(ctx context.Context, reportType, userID string, startDate, endDate time.Time) (interface{}, error) {
+	switch reportType {
+	case models.ReportTypeFootprint:
+		return s.dataCollector.CollectFootprintData(ctx, userID, startDate, endDate)
+	case models.ReportTypeCredits:
+		return s.dataCollector.CollectCreditsData(ctx, userID, startDate, endDate)
+	case models.ReportTypeSummary:
+		return s.dataCollector.CollectSummaryData(ctx, userID, startDate, endDate)
+	case models.ReportTypeDataExport:
+		return s.dataCollector.CollectDataExport(ctx, userID)
+	default:
+		return nil, fmt.Errorf("unsupported report type: %s", reportType)
+	}
+}
+
 // generateReportAsync generates the report content asynchronously
 func (s *ReportingService) generateReportAsync(ctx context.Context, report *models.Report) {
 	s.logger.LogInfo(ctx, "starting async report generation",
@@ -203,25 +475,13 @@ func (s *ReportingService) generateReportAsync(ctx context.Context, report *mode
 	}
 
 	// Collect data based on report type
-	var data interface{}
-	var err error
-
-	switch report.Type {
-	case models.ReportTypeFootprint:
-		data, err = s.dataCollector.CollectFootprintData(ctx, report.UserID, report.StartDate, report.EndDate)
-	case models.ReportTypeCredits:
-		data, err = s.dataCollector.CollectCreditsData(ctx, report.UserID, report.StartDate, report.EndDate)
-	case models.ReportTypeSummary:
-		data, err = s.dataCollector.CollectSummaryData(ctx, report.UserID, report.StartDate, report.EndDate)
-	default:
-		err = fmt.Errorf("unsupported report type: %s", report.Type)
-	}
-
+	data, err := s.collectReportData(ctx, report.Type, report.UserID, report.StartDate, report.EndDate)
 	if err != nil {
 		s.logger.LogError(ctx, "failed to collect report data", err,
 			logger.String("report_id", report.ID.String()))
 		report.Status = models.ReportStatusFailed
 		s.reportRepo.Update(ctx, report)
+		s.recordReportGenerated(report)
 		return
 	}
 
@@ -234,6 +494,8 @@ func (s *ReportingService) generateReportAsync(ctx context.Context, report *mode
 		content, err = s.reportRenderer.RenderJSON(ctx, data)
 	case models.ReportFormatCSV:
 		content, err = s.reportRenderer.RenderCSV(ctx, report.Type, data)
+	case models.ReportFormatZIP:
+		content, err = s.reportRenderer.RenderArchive(ctx, report.Type, data)
 	default:
 		err = fmt.Errorf("unsupported report format: %s", report.Format)
 	}
@@ -243,12 +505,20 @@ func (s *ReportingService) generateReportAsync(ctx context.Context, report *mode
 			logger.String("report_id", report.ID.String()))
 		report.Status = models.ReportStatusFailed
 		s.reportRepo.Update(ctx, report)
+		s.recordReportGenerated(report)
 		return
 	}
 
 	// Save report file
 	filePath := fmt.Sprintf("reports/%s/%s.%s", report.UserID, report.ID.String(), report.Format)
-	// TODO: Save content to file storage (S3, local filesystem, etc.)
+	if err := s.storage.Save(ctx, filePath, content); err != nil {
+		s.logger.LogError(ctx, "failed to store report file", err,
+			logger.String("report_id", report.ID.String()))
+		report.Status = models.ReportStatusFailed
+		s.reportRepo.Update(ctx, report)
+		s.recordReportGenerated(report)
+		return
+	}
 
 	// Update report with file information
 	now := time.Now().UTC()
@@ -261,6 +531,7 @@ func (s *ReportingService) generateReportAsync(ctx context.Context, report *mode
 		s.logger.LogError(ctx, "failed to update report", err)
 		return
 	}
+	s.recordReportGenerated(report)
 
 	s.logger.LogInfo(ctx, "report generated successfully",
 		logger.String("report_id", report.ID.String()),
@@ -268,6 +539,55 @@ func (s *ReportingService) generateReportAsync(ctx context.Context, report *mode
 		logger.Int("file_size", len(content)))
 }
 
+// recordReportGenerated counts a finished report generation by type, format
+// and whether it completed or failed
+func (s *ReportingService) recordReportGenerated(report *models.Report) {
+	if s.metrics != nil {
+		s.metrics.RecordReportGenerated(metricsServiceName, report.Type, report.Format, report.Status)
+	}
+}
+
+// deleteReportFile removes a report's file from storage, logging failures
+func (s *ReportingService) deleteReportFile(ctx context.Context, report *models.Report) {
+	if report.FilePath == "" {
+		return
+	}
+
+	if err := s.storage.Delete(ctx, report.FilePath); err != nil {
+		s.logger.LogError(ctx, "failed to delete report file", err,
+			logger.String("report_id", report.ID.String()),
+			logger.String("file_path", report.FilePath))
+	}
+}
+
+// reportContentType returns the media type of a report format
+func reportContentType(format string) string {
+	switch format {
+	case models.ReportFormatPDF:
+		return "application/pdf"
+	case models.ReportFormatJSON:
+		return "application/json"
+	case models.ReportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case models.ReportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case models.ReportFormatZIP:
+		return "application/zip"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// reportFilename names a downloaded report after its type and generation
+// date, e.g. footprint-2024-01-31.pdf
+func reportFilename(report *models.Report) string {
+	generated := report.CreatedAt
+	if report.GeneratedAt != nil {
+		generated = *report.GeneratedAt
+	}
+	return fmt.Sprintf("%s-%s.%s", report.Type, generated.UTC().Format("2006-01-02"), report.Format)
+}
+
 // validateReportRequest validates a report generation request
 func (s *ReportingService) validateReportRequest(req *GenerateReportRequest) error {
 	// Validate report type
@@ -308,14 +628,26 @@ func (s *ReportingService) validateReportRequest(req *GenerateReportRequest) err
 		return fmt.Errorf("invalid report format: %s", req.Format)
 	}
 
-	// Validate date range
-	if req.EndDate.Before(req.StartDate) {
+	return s.validateDateRange(req.StartDate, req.EndDate)
+}
+
+// validateDateRange checks that a report period is set, ordered and no
+// longer than the configured maximum
+func (s *ReportingService) validateDateRange(startDate, endDate time.Time) error {
+	// A zero time satisfies binding:"required" but means the date was omitted
+	if startDate.IsZero() {
+		return fmt.Errorf("start date is required")
+	}
+	if endDate.IsZero() {
+		return fmt.Errorf("end date is required")
+	}
+
+	if endDate.Before(startDate) {
 		return fmt.Errorf("end date must be after start date")
 	}
 
-	// Validate date range is not too large (max 1 year)
-	if req.EndDate.Sub(req.StartDate) > 365*24*time.Hour {
-		return fmt.Errorf("date range cannot exceed 1 year")
+	if endDate.Sub(startDate) > s.maxDateRange {
+		return fmt.Errorf("date range cannot exceed %s", s.maxDateRange)
 	}
 
 	return nil
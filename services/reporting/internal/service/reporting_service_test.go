@@ -1,12 +1,21 @@
 package service
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/shopspring/decimal"
 	"github.com/sloweyyy/GreenLedger/services/reporting/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
 )
 
 func TestReportModel_IsCompleted(t *testing.T) {
@@ -77,3 +86,635 @@ func TestFootprintReportData_Creation(t *testing.T) {
 		t.Errorf("Expected TotalCalculations 10, got %d", data.TotalCalculations)
 	}
 }
+
+func TestCreditSourceKey_SeparatesTransferDirections(t *testing.T) {
+	if key := models.CreditSourceKey(models.TransactionTypeTransferIn, "transfer"); key != models.TransactionTypeTransferIn {
+		t.Errorf("Expected key %s for incoming transfer, got %s", models.TransactionTypeTransferIn, key)
+	}
+
+	if key := models.CreditSourceKey(models.TransactionTypeTransferOut, "transfer"); key != models.TransactionTypeTransferOut {
+		t.Errorf("Expected key %s for outgoing transfer, got %s", models.TransactionTypeTransferOut, key)
+	}
+
+	if key := models.CreditSourceKey(models.TransactionTypeCreditEarned, "activity"); key != "activity" {
+		t.Errorf("Expected key 'activity' for earned credits, got %s", key)
+	}
+}
+
+func TestCountsTowardCreditSource(t *testing.T) {
+	if !models.CountsTowardCreditSource(models.TransactionTypeTransferIn) {
+		t.Error("Expected incoming transfers to count toward their source")
+	}
+
+	if !models.CountsTowardCreditSource(models.TransactionTypeTransferOut) {
+		t.Error("Expected outgoing transfers to count toward their source")
+	}
+
+	if models.CountsTowardCreditSource("credit_spent") {
+		t.Error("Expected spent credits to not count toward their source")
+	}
+}
+
+func TestPDFReportRenderer_RenderDataExportArchive(t *testing.T) {
+	renderer := NewPDFReportRenderer(nil)
+	data := &models.DataExportData{
+		UserID:       "test-user-123",
+		ExportedAt:   time.Now().UTC(),
+		Profile:      map[string]interface{}{"email": "user@example.com"},
+		Transactions: []map[string]interface{}{{"type": "credit_earned", "amount": "10"}},
+	}
+
+	content, err := renderer.RenderArchive(context.Background(), models.ReportTypeDataExport, data)
+	if err != nil {
+		t.Fatalf("Expected archive to render, got %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("Expected a valid ZIP archive, got %v", err)
+	}
+
+	files := make(map[string]bool)
+	for _, file := range archive.File {
+		files[file.Name] = true
+	}
+
+	for _, name := range []string{"profile.json", "calculations.json", "activities.json", "transactions.json", "certificates.json", "manifest.json"} {
+		if !files[name] {
+			t.Errorf("Expected archive to contain %s", name)
+		}
+	}
+}
+
+func TestPDFReportRenderer_FootprintCharts(t *testing.T) {
+	renderer := NewPDFReportRenderer(logger.New("error"))
+	period := func() *models.FootprintReportData {
+		return &models.FootprintReportData{
+			UserID:         "test-user-123",
+			TotalCO2Kg:     decimal.NewFromFloat(310),
+			ByActivityType: map[string]decimal.Decimal{},
+			ByMonth:        map[string]decimal.Decimal{},
+			StartDate:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:        time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		}
+	}
+
+	// A breakdown of zeros has nothing to chart
+	empty := period()
+	empty.ByActivityType["vehicle_travel"] = decimal.Zero
+	withoutCharts, err := renderer.RenderPDF(context.Background(), models.ReportTypeFootprint, empty)
+	if err != nil {
+		t.Fatalf("Expected PDF without chart data to render, got %v", err)
+	}
+
+	charted := period()
+	charted.ByActivityType["vehicle_travel"] = decimal.NewFromFloat(200)
+	charted.ByActivityType["electricity_usage"] = decimal.NewFromFloat(110)
+	charted.ByMonth["2024-01"] = decimal.NewFromFloat(90)
+	charted.ByMonth["2024-02"] = decimal.NewFromFloat(120)
+	charted.ByMonth["2024-03"] = decimal.NewFromFloat(100)
+	withCharts, err := renderer.RenderPDF(context.Background(), models.ReportTypeFootprint, charted)
+	if err != nil {
+		t.Fatalf("Expected PDF with charts to render, got %v", err)
+	}
+
+	if len(withCharts) <= len(withoutCharts)+1000 {
+		t.Errorf("Expected embedded charts to enlarge the PDF, got %d bytes with and %d without", len(withCharts), len(withoutCharts))
+	}
+	if !bytes.Contains(withCharts, []byte("/Subtype /Image")) {
+		t.Error("Expected the PDF to contain images")
+	}
+	if bytes.Contains(withoutCharts, []byte("/Subtype /Image")) {
+		t.Error("Expected no images without chart data")
+	}
+}
+
+func TestPDFReportRenderer_CreditsChart(t *testing.T) {
+	renderer := NewPDFReportRenderer(logger.New("error"))
+	data := func(bySource map[string]decimal.Decimal) *models.CreditsReportData {
+		return &models.CreditsReportData{
+			UserID:    "test-user-123",
+			BySource:  bySource,
+			StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		}
+	}
+
+	withoutChart, err := renderer.RenderPDF(context.Background(), models.ReportTypeCredits, data(nil))
+	if err != nil {
+		t.Fatalf("Expected PDF without chart data to render, got %v", err)
+	}
+	withChart, err := renderer.RenderPDF(context.Background(), models.ReportTypeCredits, data(map[string]decimal.Decimal{
+		"eco_activity": decimal.NewFromFloat(12.5),
+		"transfer_in":  decimal.NewFromFloat(4),
+	}))
+	if err != nil {
+		t.Fatalf("Expected PDF with chart to render, got %v", err)
+	}
+
+	if len(withChart) <= len(withoutChart)+1000 {
+		t.Errorf("Expected an embedded chart to enlarge the PDF, got %d bytes with and %d without", len(withChart), len(withoutChart))
+	}
+}
+
+func TestMonthlyLineChartPNG_NeedsTwoMonths(t *testing.T) {
+	image, err := monthlyLineChartPNG(map[string]decimal.Decimal{"2024-01": decimal.NewFromFloat(5)})
+	if err != nil || image != nil {
+		t.Errorf("Expected a single month to be skipped, got %d bytes and %v", len(image), err)
+	}
+}
+
+type stubDataCollector struct {
+	summaryCalls int
+	impactCalls  int
+}
+
+func (c *stubDataCollector) CollectFootprintData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.FootprintReportData, error) {
+	return &models.FootprintReportData{UserID: userID}, nil
+}
+
+func (c *stubDataCollector) CollectCreditsData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.CreditsReportData, error) {
+	return &models.CreditsReportData{UserID: userID}, nil
+}
+
+func (c *stubDataCollector) CollectSummaryData(ctx context.Context, userID string, startDate, endDate time.Time) (*models.SummaryReportData, error) {
+	c.summaryCalls++
+	return &models.SummaryReportData{UserID: userID}, nil
+}
+
+func (c *stubDataCollector) CollectDataExport(ctx context.Context, userID string) (*models.DataExportData, error) {
+	return &models.DataExportData{UserID: userID}, nil
+}
+
+func (c *stubDataCollector) CollectPlatformImpact(ctx context.Context) (*models.PlatformImpactData, error) {
+	c.impactCalls++
+	return &models.PlatformImpactData{TotalCalculations: 42}, nil
+}
+
+func (c *stubDataCollector) CollectCarbonNeutrality(ctx context.Context, userID string, startDate, endDate time.Time) (*models.CarbonNeutralityData, error) {
+	return &models.CarbonNeutralityData{
+		UserID:          userID,
+		EmissionsCO2Kg:  decimal.NewFromFloat(120.5),
+		RetiredOffsetKg: decimal.NewFromFloat(100),
+	}, nil
+}
+
+func (c *stubDataCollector) CollectActivityFeed(ctx context.Context, userID string, before time.Time, limit int) (*models.ActivityFeedData, error) {
+	entries := []models.FeedEntry{
+		{ID: "a1", Type: models.FeedEntryActivityLogged, Source: models.FeedSourceTracker, OccurredAt: before.Add(-3 * time.Hour)},
+		{ID: "a2", Type: models.FeedEntryActivityLogged, Source: models.FeedSourceTracker, OccurredAt: before.Add(-1 * time.Hour)},
+		{ID: "t1", Type: models.FeedEntryCreditsEarned, Source: models.FeedSourceWallet, OccurredAt: before.Add(-2 * time.Hour)},
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return &models.ActivityFeedData{
+		UserID:             userID,
+		Entries:            entries,
+		UnavailableSources: []string{models.FeedSourceCertifier},
+	}, nil
+}
+
+func TestReportingService_GetActivityFeed(t *testing.T) {
+	service := NewReportingService(nil, &stubDataCollector{}, nil, nil, 0, nil, nil)
+	before := time.Now().UTC()
+
+	feed, err := service.GetActivityFeed(context.Background(), "test-user-123", before, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(feed.Entries) != 2 || feed.Entries[0].ID != "a2" || feed.Entries[1].ID != "t1" {
+		t.Errorf("Expected the two newest entries across sources, got %#v", feed.Entries)
+	}
+	if feed.NextBefore == nil || !feed.NextBefore.Equal(before.Add(-2*time.Hour)) {
+		t.Errorf("Expected the next page to start before the last entry, got %v", feed.NextBefore)
+	}
+	if len(feed.UnavailableSources) != 1 || feed.UnavailableSources[0] != models.FeedSourceCertifier {
+		t.Errorf("Expected the certifier to be reported unavailable, got %v", feed.UnavailableSources)
+	}
+
+	feed, err = service.GetActivityFeed(context.Background(), "test-user-123", before, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(feed.Entries) != 3 || feed.NextBefore != nil {
+		t.Errorf("Expected every entry and no next page, got %d entries and %v", len(feed.Entries), feed.NextBefore)
+	}
+}
+
+func TestReportingService_GetPlatformImpact_Cached(t *testing.T) {
+	collector := &stubDataCollector{}
+	service := NewReportingService(nil, collector, nil, nil, 0, nil, nil)
+
+	for i := 0; i < 3; i++ {
+		impact, err := service.GetPlatformImpact(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if impact.TotalCalculations != 42 {
+			t.Errorf("Expected 42 calculations, got %d", impact.TotalCalculations)
+		}
+	}
+
+	if collector.impactCalls != 1 {
+		t.Errorf("Expected totals to be collected once within the TTL, got %d", collector.impactCalls)
+	}
+
+	service.impactCachedAt = time.Now().Add(-platformImpactTTL)
+	if _, err := service.GetPlatformImpact(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if collector.impactCalls != 2 {
+		t.Errorf("Expected totals to be recollected after the TTL, got %d", collector.impactCalls)
+	}
+}
+
+func TestReportingService_PreviewReport(t *testing.T) {
+	collector := &stubDataCollector{}
+	// No repository: a preview must never store a report
+	service := NewReportingService(nil, collector, nil, nil, 0, nil, nil)
+
+	end := time.Now().UTC()
+	preview, err := service.PreviewReport(context.Background(), &PreviewReportRequest{
+		UserID:    "test-user-123",
+		Type:      models.ReportTypeSummary,
+		StartDate: end.AddDate(0, -1, 0),
+		EndDate:   end,
+	})
+	if err != nil {
+		t.Fatalf("Expected preview to succeed, got %v", err)
+	}
+
+	data, ok := preview.Data.(*models.SummaryReportData)
+	if !ok || data.UserID != "test-user-123" {
+		t.Errorf("Expected summary data for the user, got %#v", preview.Data)
+	}
+	if collector.summaryCalls != 1 {
+		t.Errorf("Expected summary collector to run once, ran %d times", collector.summaryCalls)
+	}
+}
+
+func TestReportingService_PreviewReport_RejectsInvalidRequests(t *testing.T) {
+	service := NewReportingService(nil, &stubDataCollector{}, nil, nil, 0, nil, nil)
+	end := time.Now().UTC()
+
+	requests := map[string]*PreviewReportRequest{
+		"data export":    {UserID: "u", Type: models.ReportTypeDataExport, StartDate: end.AddDate(0, -1, 0), EndDate: end},
+		"reversed range": {UserID: "u", Type: models.ReportTypeSummary, StartDate: end, EndDate: end.AddDate(0, -1, 0)},
+		"range too long": {UserID: "u", Type: models.ReportTypeCredits, StartDate: end.AddDate(-2, 0, 0), EndDate: end},
+	}
+
+	for name, req := range requests {
+		if _, err := service.PreviewReport(context.Background(), req); !errors.Is(err, ErrInvalidReportRequest) {
+			t.Errorf("%s: expected ErrInvalidReportRequest, got %v", name, err)
+		}
+	}
+}
+
+func TestReportingService_GenerateReport_RejectsZeroStartDate(t *testing.T) {
+	// No repository: an invalid request must be rejected before anything is stored
+	service := NewReportingService(nil, &stubDataCollector{}, nil, nil, 0, nil, logger.New("error"))
+
+	_, err := service.GenerateReport(context.Background(), &GenerateReportRequest{
+		UserID:  "test-user-123",
+		Type:    models.ReportTypeSummary,
+		Format:  models.ReportFormatJSON,
+		EndDate: time.Now().UTC(),
+	})
+	if !errors.Is(err, ErrInvalidReportRequest) {
+		t.Errorf("Expected ErrInvalidReportRequest for a zero start date, got %v", err)
+	}
+}
+
+func TestReportingService_ValidateDateRange_ConfiguredMax(t *testing.T) {
+	service := NewReportingService(nil, nil, nil, nil, 30*24*time.Hour, nil, nil)
+	end := time.Now().UTC()
+
+	if err := service.validateDateRange(end.AddDate(0, 0, -30), end); err != nil {
+		t.Errorf("Expected a 30 day range to be accepted, got %v", err)
+	}
+	if err := service.validateDateRange(end.AddDate(0, 0, -31), end); err == nil {
+		t.Error("Expected a range longer than the configured max to be rejected")
+	}
+}
+
+func TestNetCarbon(t *testing.T) {
+	tests := []struct {
+		name      string
+		emissions float64
+		offsets   float64
+		net       float64
+		status    string
+	}{
+		{"deficit", 120.5, 100, 20.5, models.NeutralityStatusDeficit},
+		{"surplus", 80, 100, -20, models.NeutralityStatusSurplus},
+		{"exactly covered", 100, 100, 0, models.NeutralityStatusNeutral},
+		{"difference below storage precision", 100.0004, 100, 0, models.NeutralityStatusNeutral},
+		{"nothing to offset", 0, 0, 0, models.NeutralityStatusNeutral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			net, status := models.NetCarbon(decimal.NewFromFloat(tt.emissions), decimal.NewFromFloat(tt.offsets))
+			if !net.Equal(decimal.NewFromFloat(tt.net)) {
+				t.Errorf("Expected net %v, got %s", tt.net, net)
+			}
+			if status != tt.status {
+				t.Errorf("Expected status %q, got %q", tt.status, status)
+			}
+		})
+	}
+}
+
+func TestCompareToAverage(t *testing.T) {
+	// Seeded users' totals for a period, averaging 200 kg
+	totals := map[string]float64{"user-low": 100, "user-mid": 200, "user-high": 300}
+	sum := decimal.Zero
+	for _, total := range totals {
+		sum = sum.Add(decimal.NewFromFloat(total))
+	}
+	average := sum.Div(decimal.NewFromInt(int64(len(totals))))
+
+	tests := []struct {
+		user     string
+		expected string
+	}{
+		{"user-low", "-50"},
+		{"user-mid", "0"},
+		{"user-high", "50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			comparison := models.CompareToAverage(decimal.NewFromFloat(totals[tt.user]), average)
+			if !comparison.Equal(decimal.RequireFromString(tt.expected)) {
+				t.Errorf("Expected %s%%, got %s%%", tt.expected, comparison)
+			}
+		})
+	}
+
+	if got := models.CompareToAverage(decimal.NewFromInt(250), decimal.NewFromInt(300)); !got.Equal(decimal.RequireFromString("-16.67")) {
+		t.Errorf("Expected -16.67%%, got %s%%", got)
+	}
+	if got := models.CompareToAverage(decimal.NewFromInt(50), decimal.Zero); !got.IsZero() {
+		t.Errorf("Expected no comparison without an average, got %s%%", got)
+	}
+}
+
+func TestGlobalAverageCache_Expires(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := &globalAverageCache{now: func() time.Time { return now }}
+	key := globalAverageKey{start: now.AddDate(0, -1, 0), end: now}
+
+	if _, ok := cache.get(key); ok {
+		t.Fatal("Expected an empty cache to miss")
+	}
+
+	cache.put(key, decimal.NewFromInt(200))
+	now = now.Add(globalAverageTTL - time.Second)
+	if average, ok := cache.get(key); !ok || !average.Equal(decimal.NewFromInt(200)) {
+		t.Errorf("Expected the cached average within the TTL, got %s (hit %v)", average, ok)
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := cache.get(key); ok {
+		t.Error("Expected the average to expire after the TTL")
+	}
+
+	// Stale entries are dropped when another period is cached
+	cache.put(globalAverageKey{start: now, end: now.Add(time.Hour)}, decimal.NewFromInt(10))
+	if len(cache.entries) != 1 {
+		t.Errorf("Expected the stale entry to be dropped, got %d entries", len(cache.entries))
+	}
+}
+
+func TestActiveDays(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+
+	// Combined calculation and activity counts for a user; March 3 had none
+	counts := map[time.Time]int64{
+		day(1): 2,
+		day(2): 5,
+		day(4): 1,
+		day(5): 5,
+		day(9): 20, // outside the period
+	}
+
+	most, least := models.ActiveDays(counts, start, end)
+	if !most.Equal(day(2)) {
+		t.Errorf("Expected most active day %s, got %s", day(2), most)
+	}
+	if !least.Equal(day(3)) {
+		t.Errorf("Expected least active day %s (no activity), got %s", day(3), least)
+	}
+
+	// With activity every day the quietest recorded day is least active
+	counts[day(3)] = 3
+	if _, least := models.ActiveDays(counts, start, end); !least.Equal(day(4)) {
+		t.Errorf("Expected least active day %s, got %s", day(4), least)
+	}
+
+	// Without any activity both fall on the first day of the period
+	most, least = models.ActiveDays(nil, start, end)
+	if !most.Equal(day(1)) || !least.Equal(day(1)) {
+		t.Errorf("Expected both days to be %s without activity, got %s and %s", day(1), most, least)
+	}
+}
+
+func TestReportingService_GetCarbonNeutrality(t *testing.T) {
+	service := NewReportingService(nil, &stubDataCollector{}, nil, nil, 0, nil, nil)
+	end := time.Now().UTC()
+
+	data, err := service.GetCarbonNeutrality(context.Background(), "test-user-123", end.AddDate(0, -1, 0), end)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if data.Status != models.NeutralityStatusDeficit {
+		t.Errorf("Expected deficit, got %q", data.Status)
+	}
+	if !data.GapCO2Kg.Equal(decimal.NewFromFloat(20.5)) {
+		t.Errorf("Expected a 20.5 kg gap, got %s", data.GapCO2Kg)
+	}
+
+	if _, err := service.GetCarbonNeutrality(context.Background(), "test-user-123", end, end.AddDate(0, -1, 0)); !errors.Is(err, ErrInvalidReportRequest) {
+		t.Errorf("Expected ErrInvalidReportRequest for a reversed range, got %v", err)
+	}
+}
+
+// memReportRepository keeps reports in memory
+type memReportRepository struct {
+	reports map[uuid.UUID]*models.Report
+}
+
+func newMemReportRepository() *memReportRepository {
+	return &memReportRepository{reports: map[uuid.UUID]*models.Report{}}
+}
+
+func (r *memReportRepository) Create(ctx context.Context, report *models.Report) error {
+	if report.ID == uuid.Nil {
+		report.ID = uuid.New()
+	}
+	r.reports[report.ID] = report
+	return nil
+}
+
+func (r *memReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Report, error) {
+	report, ok := r.reports[id]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	found := *report
+	return &found, nil
+}
+
+func (r *memReportRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.Report, int64, error) {
+	var reports []*models.Report
+	for _, report := range r.reports {
+		if report.UserID == userID {
+			reports = append(reports, report)
+		}
+	}
+	return reports, int64(len(reports)), nil
+}
+
+func (r *memReportRepository) Update(ctx context.Context, report *models.Report) error {
+	saved := *report
+	r.reports[report.ID] = &saved
+	return nil
+}
+
+func (r *memReportRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.reports, id)
+	return nil
+}
+
+func (r *memReportRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	for id, report := range r.reports {
+		if report.UserID == userID {
+			delete(r.reports, id)
+		}
+	}
+	return nil
+}
+
+// generatedReport runs report generation synchronously with local storage
+// and returns the service, its storage and the completed report
+func generatedReport(t *testing.T, format string) (*ReportingService, *LocalReportStorage, *models.Report) {
+	t.Helper()
+
+	repo := newMemReportRepository()
+	storage := NewLocalReportStorage(t.TempDir())
+	log := logger.New("error")
+	service := NewReportingService(repo, &stubDataCollector{}, NewPDFReportRenderer(log), storage, 0, nil, log)
+
+	report := &models.Report{
+		UserID:    "test-user-123",
+		Type:      models.ReportTypeFootprint,
+		Format:    format,
+		Status:    models.ReportStatusPending,
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+	repo.Create(context.Background(), report)
+	service.generateReportAsync(context.Background(), report)
+
+	stored, _ := repo.GetByID(context.Background(), report.ID)
+	if stored.Status != models.ReportStatusCompleted {
+		t.Fatalf("Expected report to complete, got status %s", stored.Status)
+	}
+	return service, storage, stored
+}
+
+func TestGenerateReport_RecordsMetrics(t *testing.T) {
+	metrics := monitoring.NewMetrics("reporting")
+	repo := newMemReportRepository()
+	log := logger.New("error")
+	service := NewReportingService(repo, &stubDataCollector{}, NewPDFReportRenderer(log), NewLocalReportStorage(t.TempDir()), 0, metrics, log)
+
+	for _, format := range []string{models.ReportFormatJSON, models.ReportFormatJSON, "docx"} {
+		report := &models.Report{
+			UserID:    "test-user-123",
+			Type:      models.ReportTypeFootprint,
+			Format:    format,
+			Status:    models.ReportStatusPending,
+			StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		}
+		repo.Create(context.Background(), report)
+		service.generateReportAsync(context.Background(), report)
+	}
+
+	completed := metrics.ReportsGenerated.WithLabelValues("reporting", models.ReportTypeFootprint, models.ReportFormatJSON, models.ReportStatusCompleted)
+	if got := testutil.ToFloat64(completed); got != 2 {
+		t.Errorf("Expected 2 completed reports, got %v", got)
+	}
+	failed := metrics.ReportsGenerated.WithLabelValues("reporting", models.ReportTypeFootprint, "docx", models.ReportStatusFailed)
+	if got := testutil.ToFloat64(failed); got != 1 {
+		t.Errorf("Expected 1 failed report, got %v", got)
+	}
+}
+
+func TestReportingService_DownloadReport(t *testing.T) {
+	service, _, report := generatedReport(t, models.ReportFormatJSON)
+
+	file, err := service.DownloadReport(context.Background(), report.ID, report.UserID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer file.Content.Close()
+
+	content, err := io.ReadAll(file.Content)
+	if err != nil {
+		t.Fatalf("Expected no error reading, got %v", err)
+	}
+	if int64(len(content)) != report.FileSize || file.Size != report.FileSize {
+		t.Errorf("Expected %d bytes, got %d (size %d)", report.FileSize, len(content), file.Size)
+	}
+	if !bytes.Contains(content, []byte(`"user_id": "test-user-123"`)) {
+		t.Errorf("Expected the rendered report, got %s", content)
+	}
+	if file.ContentType != "application/json" {
+		t.Errorf("Expected application/json, got %s", file.ContentType)
+	}
+	expectedName := "footprint-" + report.GeneratedAt.UTC().Format("2006-01-02") + ".json"
+	if file.Filename != expectedName {
+		t.Errorf("Expected filename %s, got %s", expectedName, file.Filename)
+	}
+}
+
+func TestReportingService_DownloadReport_Unavailable(t *testing.T) {
+	service, _, report := generatedReport(t, models.ReportFormatCSV)
+	repo := service.reportRepo.(*memReportRepository)
+
+	if _, err := service.DownloadReport(context.Background(), report.ID, "other-user"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for another user's report, got %v", err)
+	}
+
+	pending := &models.Report{UserID: report.UserID, Format: models.ReportFormatCSV, Status: models.ReportStatusGenerating}
+	repo.Create(context.Background(), pending)
+	if _, err := service.DownloadReport(context.Background(), pending.ID, report.UserID); !errors.Is(err, ErrReportNotReady) {
+		t.Errorf("Expected ErrReportNotReady while generating, got %v", err)
+	}
+
+	expiredAt := time.Now().Add(-time.Hour)
+	report.ExpiresAt = &expiredAt
+	repo.Update(context.Background(), report)
+	if _, err := service.DownloadReport(context.Background(), report.ID, report.UserID); !errors.Is(err, ErrReportExpired) {
+		t.Errorf("Expected ErrReportExpired, got %v", err)
+	}
+}
+
+func TestReportingService_DeleteReport_RemovesFile(t *testing.T) {
+	service, storage, report := generatedReport(t, models.ReportFormatPDF)
+
+	if err := service.DeleteReport(context.Background(), report.ID, report.UserID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := storage.Open(context.Background(), report.FilePath); !errors.Is(err, ErrReportFileNotFound) {
+		t.Errorf("Expected the report file to be deleted, got %v", err)
+	}
+}
@@ -2,12 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,8 +16,25 @@ import (
 	"github.com/sloweyyy/GreenLedger/services/tracker/internal/service"
 	"github.com/sloweyyy/GreenLedger/shared/config"
 	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/events"
+	"github.com/sloweyyy/GreenLedger/shared/featureflags"
 	sharedLogger "github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/sloweyyy/GreenLedger/shared/middleware"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
+	"github.com/sloweyyy/GreenLedger/shared/proto/reportdata"
+	sharedServer "github.com/sloweyyy/GreenLedger/shared/server"
+	"github.com/sloweyyy/GreenLedger/shared/version"
+	"google.golang.org/grpc"
+)
+
+// outboxRelayInterval is how often unpublished outbox events are published
+const outboxRelayInterval = 5 * time.Second
+
+// Build information, injected at build time via -ldflags "-X main.Version=..."
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
 )
 
 // @title GreenLedger Activity Tracker Service API
@@ -56,13 +72,15 @@ func main() {
 	// Initialize logger
 	logger := sharedLogger.New(cfg.Server.LogLevel).WithService("tracker")
 
+	// Initialize metrics
+	metrics := monitoring.NewMetrics("tracker")
+
 	// Initialize database
 	db, err := database.NewPostgresDB(&cfg.Database, logger)
 	if err != nil {
 		logger.LogError(context.Background(), "failed to connect to database", err)
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer db.Close()
 
 	// Run database migrations
 	if err := db.Migrate(
@@ -72,6 +90,10 @@ func main() {
 		&models.ActivityChallenge{},
 		&models.ChallengeParticipant{},
 		&models.IoTDevice{},
+		&models.WebhookEvent{},
+		&models.StreakReward{},
+		&events.DeadLetter{},
+		&events.OutboxEvent{},
 	); err != nil {
 		logger.LogError(context.Background(), "failed to run migrations", err)
 		log.Fatalf("Failed to run migrations: %v", err)
@@ -81,39 +103,85 @@ func main() {
 	activityRepo := repository.NewActivityRepository(db, logger)
 	activityTypeRepo := repository.NewActivityTypeRepository(db, logger)
 	creditRuleRepo := repository.NewCreditRuleRepository(db, logger)
+	deviceRepo := repository.NewIoTDeviceRepository(db, logger)
+	webhookEventRepo := repository.NewWebhookEventRepository(db, logger)
+	streakRewardRepo := repository.NewStreakRewardRepository(db, logger)
+
+	// Events this service's consumers give up on
+	deadLetters := events.NewDeadLetterQueue(db, logger)
 
 	// Initialize event publisher
 	var eventPublisher service.EventPublisher
-	if cfg.Server.Environment == "production" {
+	if cfg.Features.Enabled(featureflags.Kafka) {
 		eventPublisher = service.NewKafkaEventPublisher(cfg.Kafka.Brokers, logger)
 	} else {
 		eventPublisher = service.NewMockEventPublisher(logger)
 	}
 
+	creditRounding, err := cfg.Credits.RoundingPolicy()
+	if err != nil {
+		log.Fatalf("Invalid credit rounding policy: %v", err)
+	}
+
 	// Initialize services
+	challengeRepo := repository.NewChallengeRepository(db, logger)
+	challengeRewarder := service.NewChallengeRewarder(challengeRepo, eventPublisher, creditRounding, logger)
+	challengeService := service.NewChallengeService(challengeRepo, activityTypeRepo, challengeRewarder, logger)
+	creditRuleService := service.NewCreditRuleService(creditRuleRepo, activityTypeRepo, logger)
+
 	trackerService := service.NewTrackerService(
 		activityRepo,
 		activityTypeRepo,
 		creditRuleRepo,
+		streakRewardRepo,
 		eventPublisher,
+		cfg.Tracker.UserActivitySources,
+		cfg.Tracker.MaxBackdating,
+		creditRounding,
+		metrics,
+		challengeService,
 		logger,
 	)
 
+	deviceService := service.NewDeviceService(deviceRepo, trackerService, logger)
+
+	// Accept webhooks only from providers with a signing secret configured
+	webhookRegistry := service.NewWebhookRegistry()
+	for _, provider := range []service.WebhookProvider{service.StravaProvider{}} {
+		if secret, ok := cfg.Tracker.WebhookSecrets[provider.Name()]; ok {
+			webhookRegistry.Register(provider, secret)
+		}
+	}
+	webhookService := service.NewWebhookService(webhookRegistry, webhookEventRepo, trackerService, cfg.Tracker.WebhookTolerance, logger)
+
+	// Initialize default activity types before serving so no request sees a
+	// half-seeded database
+	if err := initializeActivityTypes(context.Background(), activityTypeRepo, creditRuleRepo, logger); err != nil {
+		logger.LogError(context.Background(), "failed to initialize activity types", err)
+		log.Fatalf("Failed to initialize activity types: %v", err)
+	}
+
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(cfg.Server.JWTSecret, logger)
+	authMiddleware := middleware.NewAuthMiddleware(cfg.Server.JWTSecret, cfg.Server.JWTIssuer, cfg.Server.JWTAudience, logger)
 
 	// Initialize handlers
-	trackerHandler := handler.NewTrackerHandler(trackerService, logger)
+	trackerHandler := handler.NewTrackerHandler(trackerService, deviceService, webhookService, challengeService, creditRuleService, logger)
 
 	// Setup Gin router
-	if cfg.Server.Environment == "production" {
+	if cfg.Features.Enabled(featureflags.ReleaseMode) {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.LogError(context.Background(), "failed to set trusted proxies", err)
+		log.Fatalf("Failed to set trusted proxies: %v", err)
+	}
 	router.Use(gin.Recovery())
-	router.Use(middleware.RequestLogger(logger))
+	router.Use(middleware.RequestLogger(logger, cfg.Server.SlowRequestThreshold))
+	router.Use(middleware.Pagination(cfg.Pagination.DefaultLimit, cfg.Pagination.MaxLimit))
 	router.Use(middleware.CORS())
+	router.Use(middleware.MaxBodySize(cfg.Server.MaxBodyBytes))
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -127,13 +195,20 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "healthy",
 			"service": "tracker",
-			"version": "1.0.0",
+			"version": Version,
 		})
 	})
 
+	// Prometheus metrics endpoint
+	router.GET("/metrics", monitoring.MetricsHandler())
+
+	// Build info endpoint
+	router.GET("/version", version.Handler(version.New("tracker", Version, GitCommit, BuildTime)))
+
 	// API routes
 	v1 := router.Group("/api/v1")
 	trackerHandler.RegisterRoutes(v1, authMiddleware)
+	events.NewDeadLetterHandler(deadLetters, logger).RegisterRoutes(v1.Group("/tracker"), authMiddleware)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -144,44 +219,57 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	// Start server in a goroutine
-	go func() {
-		logger.LogInfo(context.Background(), "starting tracker service",
-			sharedLogger.Int("port", cfg.Server.Port),
-			sharedLogger.String("environment", cfg.Server.Environment))
+	// Cancelled on SIGINT/SIGTERM so background consumers stop before their
+	// readers, the event publisher and the database are closed
+	ctx, stop := sharedServer.NotifyContext(context.Background())
+	defer stop()
 
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.LogError(context.Background(), "failed to start server", err)
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
+	// Serve report data to the reporting service over gRPC
+	grpcServer := grpc.NewServer()
+	reportdata.RegisterTrackerDataServer(grpcServer, handler.NewReportDataServer(db, logger))
+	grpcCloser, err := sharedServer.ServeGRPC(grpcServer, fmt.Sprintf(":%d", cfg.Server.GRPCPort), logger)
+	if err != nil {
+		logger.LogError(context.Background(), "failed to start gRPC server", err)
+		log.Fatalf("Failed to start gRPC server: %v", err)
+	}
 
-	// Initialize default activity types
-	go func() {
-		if err := initializeActivityTypes(context.Background(), activityTypeRepo, creditRuleRepo, logger); err != nil {
-			logger.LogError(context.Background(), "failed to initialize activity types", err)
-		}
-	}()
+	closers := []io.Closer{grpcCloser}
 
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	// Credit revoked events are written to the outbox with the activity
+	// delete and published from it in the background
+	outboxRelay := events.NewOutboxRelay(db, logger)
+	outboxRelay.Register(service.EventTypeCreditRevoked, service.PublishCreditRevokedFromOutbox(eventPublisher))
+	go outboxRelay.Run(ctx, outboxRelayInterval)
 
-	logger.LogInfo(context.Background(), "shutting down tracker service")
+	// Start user event consumer for erasure requests
+	if cfg.Features.Enabled(featureflags.Kafka) {
+		userConsumer := events.NewUserEventConsumer(cfg.Kafka.Brokers, "tracker-service", deadLetters, logger)
+		closers = append(closers, userConsumer)
 
-	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+		go func() {
+			err := userConsumer.Consume(ctx, func(ctx context.Context, event *events.UserDeletedEvent) error {
+				return trackerService.EraseUserData(ctx, event.UserID)
+			})
+			if err != nil && !errors.Is(err, context.Canceled) {
+				logger.LogError(context.Background(), "user event consumer error", err)
+			}
+		}()
+	}
 
-	if err := server.Shutdown(ctx); err != nil {
-		logger.LogError(context.Background(), "server forced to shutdown", err)
-		log.Fatalf("Server forced to shutdown: %v", err)
+	// Close the event publisher once the consumers and outbox relay have stopped
+	if closer, ok := eventPublisher.(io.Closer); ok {
+		closers = append(closers, closer)
 	}
+	closers = append(closers, db)
+
+	logger.LogInfo(context.Background(), "starting tracker service",
+		sharedLogger.Int("port", cfg.Server.Port),
+		sharedLogger.Int("grpc_port", cfg.Server.GRPCPort),
+		sharedLogger.String("environment", cfg.Server.Environment),
+		sharedLogger.Any("features", cfg.Features.EnabledNames()))
 
-	// Close event publisher
-	if kafkaPublisher, ok := eventPublisher.(*service.KafkaEventPublisher); ok {
-		kafkaPublisher.Close()
+	if err := sharedServer.RunWithGracefulShutdown(ctx, server, logger, closers...); err != nil {
+		log.Fatalf("tracker service stopped with error: %v", err)
 	}
 
 	logger.LogInfo(context.Background(), "tracker service stopped")
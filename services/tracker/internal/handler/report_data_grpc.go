@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/proto/reportdata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReportDataServer serves the eco activity data the reporting service needs
+// for reports over gRPC
+type ReportDataServer struct {
+	reportdata.UnimplementedTrackerDataServer
+
+	db     *database.PostgresDB
+	logger *logger.Logger
+}
+
+// NewReportDataServer creates a new report data server
+func NewReportDataServer(db *database.PostgresDB, logger *logger.Logger) *ReportDataServer {
+	return &ReportDataServer{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetActivitySummary returns counts and the top credit earners of a user's
+// eco activities
+func (s *ReportDataServer) GetActivitySummary(ctx context.Context, req *reportdata.PeriodRequest) (*reportdata.ActivitySummaryData, error) {
+	start, end, err := req.Period()
+	if err != nil {
+		return nil, err
+	}
+
+	data := &reportdata.ActivitySummaryData{
+		DailyActivities: make(map[string]int64),
+	}
+
+	dailyQuery := `
+		SELECT
+			DATE(created_at AT TIME ZONE 'UTC') as day,
+			COUNT(*) as count
+		FROM eco_activities
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3
+		GROUP BY 1
+	`
+
+	dailyRows, err := s.db.WithContext(ctx).Raw(dailyQuery, req.GetUserId(), start, end).Rows()
+	if err != nil {
+		return nil, s.internal(ctx, "failed to count daily activities", err)
+	}
+	defer dailyRows.Close()
+
+	for dailyRows.Next() {
+		var day time.Time
+		var count int64
+
+		if err := dailyRows.Scan(&day, &count); err != nil {
+			return nil, s.internal(ctx, "failed to count daily activities", err)
+		}
+
+		data.DailyActivities[day.Format(reportdata.DayFormat)] = count
+		data.TotalActivities += count
+	}
+	if err := dailyRows.Err(); err != nil {
+		return nil, s.internal(ctx, "failed to count daily activities", err)
+	}
+
+	// Only verified activities earn credits
+	topQuery := `
+		SELECT 
+			at.name as activity_type,
+			COUNT(*) as count,
+			COALESCE(SUM(ea.credits_earned), 0) as total_credits
+		FROM eco_activities ea
+		JOIN activity_types at ON ea.activity_type_id = at.id
+		WHERE ea.user_id = $1 AND ea.created_at >= $2 AND ea.created_at <= $3 AND ea.is_verified = true
+		GROUP BY at.name
+		ORDER BY total_credits DESC
+		LIMIT 10
+	`
+
+	rows, err := s.db.WithContext(ctx).Raw(topQuery, req.GetUserId(), start, end).Rows()
+	if err != nil {
+		return nil, s.internal(ctx, "failed to get top earning activities", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total reportdata.ActivityTotal
+		var credits sql.NullFloat64
+
+		if err := rows.Scan(&total.ActivityType, &total.Count, &credits); err != nil {
+			return nil, s.internal(ctx, "failed to read top earning activities", err)
+		}
+
+		total.Credits = credits.Float64
+		data.TopEarningActivities = append(data.TopEarningActivities, &total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, s.internal(ctx, "failed to read top earning activities", err)
+	}
+
+	return data, nil
+}
+
+// ListFeedEntries returns a user's most recent eco activities before a
+// time, typed by activity type name
+func (s *ReportDataServer) ListFeedEntries(ctx context.Context, req *reportdata.FeedRequest) (*reportdata.FeedEntries, error) {
+	if req.GetBefore() == nil || req.GetLimit() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "before and a positive limit are required")
+	}
+
+	query := `
+		SELECT ea.id::text, at.name as type,
+			COALESCE(NULLIF(ea.description, ''), at.name) as description,
+			ea.credits_earned as amount, ea.created_at as occurred_at
+		FROM eco_activities ea
+		JOIN activity_types at ON ea.activity_type_id = at.id
+		WHERE ea.user_id = $1 AND ea.created_at < $2
+		ORDER BY ea.created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := s.db.WithContext(ctx).Raw(query, req.GetUserId(), req.GetBefore().AsTime(), req.GetLimit()).Rows()
+	if err != nil {
+		return nil, s.internal(ctx, "failed to list feed entries", err)
+	}
+	defer rows.Close()
+
+	entries, err := reportdata.ScanFeedEntries(rows)
+	if err != nil {
+		return nil, s.internal(ctx, "failed to read feed entries", err)
+	}
+	return entries, nil
+}
+
+// ExportActivities returns every eco activity of a user, leaving out which
+// moderators reviewed them
+func (s *ReportDataServer) ExportActivities(ctx context.Context, req *reportdata.UserRequest) (*reportdata.ExportRows, error) {
+	query := `
+		SELECT
+			id, user_id, activity_type_id, description, duration, distance,
+			quantity, unit, location, credits_earned, is_verified, verified_at,
+			verification_status, rejection_reason, rejected_at, source,
+			source_data, occurred_at, outside_credit_window, created_at, updated_at
+		FROM eco_activities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	var rows []map[string]interface{}
+	if err := s.db.WithContext(ctx).Raw(query, req.GetUserId()).Scan(&rows).Error; err != nil {
+		return nil, s.internal(ctx, "failed to get activities", err)
+	}
+
+	export, err := reportdata.NewExportRows(rows)
+	if err != nil {
+		return nil, s.internal(ctx, "failed to export activities", err)
+	}
+	return export, nil
+}
+
+// internal logs a failed query and hides its details from the caller
+func (s *ReportDataServer) internal(ctx context.Context, message string, err error) error {
+	s.logger.LogError(ctx, message, err)
+	return status.Error(codes.Internal, message)
+}
@@ -1,28 +1,83 @@
 package handler
 
 import (
+	"fmt"
+	"io"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
 	"github.com/sloweyyy/GreenLedger/services/tracker/internal/service"
+	"github.com/sloweyyy/GreenLedger/shared/httperr"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/sloweyyy/GreenLedger/shared/middleware"
 )
 
+// DeviceKeyHeader carries the API key IoT devices authenticate with
+const DeviceKeyHeader = "X-Device-Key"
+
+// Webhook deliveries are signed over the timestamp and body; see
+// service.WebhookService for the scheme
+const (
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// maxWebhookBodyBytes bounds the webhook payloads read into memory
+const maxWebhookBodyBytes = 1 << 20
+
 // TrackerHandler handles HTTP requests for activity tracking
 type TrackerHandler struct {
-	trackerService *service.TrackerService
-	logger         *logger.Logger
+	trackerService    *service.TrackerService
+	deviceService     *service.DeviceService
+	webhookService    *service.WebhookService
+	challengeService  *service.ChallengeService
+	creditRuleService *service.CreditRuleService
+	errMapper         *httperr.Mapper
+	logger            *logger.Logger
 }
 
 // NewTrackerHandler creates a new tracker handler
-func NewTrackerHandler(trackerService *service.TrackerService, logger *logger.Logger) *TrackerHandler {
+func NewTrackerHandler(
+	trackerService *service.TrackerService,
+	deviceService *service.DeviceService,
+	webhookService *service.WebhookService,
+	challengeService *service.ChallengeService,
+	creditRuleService *service.CreditRuleService,
+	logger *logger.Logger,
+) *TrackerHandler {
 	return &TrackerHandler{
-		trackerService: trackerService,
-		logger:         logger,
+		trackerService:    trackerService,
+		deviceService:     deviceService,
+		webhookService:    webhookService,
+		challengeService:  challengeService,
+		creditRuleService: creditRuleService,
+		errMapper: httperr.New(logger,
+			httperr.Mapping{Err: service.ErrSourceNotAllowed, Status: http.StatusBadRequest, Message: "Activity source not allowed"},
+			httperr.Mapping{Err: service.ErrInvalidSourceData, Status: http.StatusBadRequest, Message: "Invalid source data"},
+			httperr.Mapping{Err: service.ErrInvalidOccurredAt, Status: http.StatusBadRequest, Message: "Invalid occurred_at"},
+			httperr.Mapping{Err: service.ErrActivityTypeUnavailable, Status: http.StatusBadRequest, Message: "Activity type not available"},
+			httperr.Mapping{Err: service.ErrActivityAlreadyVerified, Status: http.StatusConflict, Message: "Activity already verified"},
+			httperr.Mapping{Err: service.ErrActivityRejected, Status: http.StatusConflict, Message: "Activity is rejected"},
+			httperr.Mapping{Err: service.ErrActivityTypeInUse, Status: http.StatusConflict, Message: "Activity type is in use"},
+			httperr.Mapping{Err: service.ErrDeviceUnauthorized, Status: http.StatusUnauthorized, Message: "Invalid or deactivated device API key"},
+			httperr.Mapping{Err: service.ErrDeviceExists, Status: http.StatusConflict, Message: "Device already registered"},
+			httperr.Mapping{Err: service.ErrInvalidDeviceType, Status: http.StatusBadRequest, Message: "Invalid device type"},
+			httperr.Mapping{Err: service.ErrDeviceNotFound, Status: http.StatusNotFound, Message: "Device not found"},
+			httperr.Mapping{Err: service.ErrUnknownWebhookProvider, Status: http.StatusNotFound, Message: "Unknown webhook provider"},
+			httperr.Mapping{Err: service.ErrInvalidWebhookSignature, Status: http.StatusUnauthorized, Message: "Invalid webhook signature"},
+			httperr.Mapping{Err: service.ErrInvalidWebhookPayload, Status: http.StatusBadRequest, Message: "Invalid webhook payload"},
+			httperr.Mapping{Err: service.ErrChallengeNotFound, Status: http.StatusNotFound, Message: "Challenge not found"},
+			httperr.Mapping{Err: service.ErrInvalidChallenge, Status: http.StatusBadRequest, Message: "Invalid challenge"},
+			httperr.Mapping{Err: service.ErrChallengeClosed, Status: http.StatusConflict, Message: "Challenge is closed"},
+			httperr.Mapping{Err: service.ErrAlreadyJoined, Status: http.StatusConflict, Message: "Already joined challenge"},
+			httperr.Mapping{Err: service.ErrInvalidLeaderboard, Status: http.StatusBadRequest, Message: "Invalid leaderboard period or metric"},
+			httperr.Mapping{Err: service.ErrInvalidTimezone, Status: http.StatusBadRequest, Message: "Invalid timezone"},
+			httperr.Mapping{Err: service.ErrInvalidCreditRule, Status: http.StatusBadRequest, Message: "Invalid credit rule"},
+		),
+		logger: logger,
 	}
 }
 
@@ -31,17 +86,28 @@ func (h *TrackerHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware
 	tracker := router.Group("/tracker")
 	{
 		// Public routes (for webhooks and IoT devices)
-		tracker.POST("/webhook", h.HandleWebhook)
+		tracker.POST("/webhook/:provider", h.HandleWebhook)
 		tracker.POST("/iot", h.HandleIoTData)
 
 		// Protected routes
 		tracker.Use(authMiddleware.RequireAuth())
 		tracker.POST("/activities", h.LogActivity)
 		tracker.GET("/activities", h.GetUserActivities)
+		tracker.GET("/activities/pending-evidence", h.GetPendingEvidenceActivities)
 		tracker.GET("/activities/:id", h.GetActivityByID)
+		tracker.PUT("/activities/:id", h.UpdateActivity)
+		tracker.DELETE("/activities/:id", h.DeleteActivity)
 		tracker.GET("/stats", h.GetUserStats)
+		tracker.GET("/leaderboard", h.GetLeaderboard)
+		tracker.GET("/streak", h.GetStreak)
 		tracker.GET("/activity-types", h.GetActivityTypes)
 		tracker.GET("/activity-types/:category", h.GetActivityTypesByCategory)
+		tracker.POST("/devices", h.RegisterDevice)
+		tracker.GET("/devices", h.GetDevices)
+		tracker.DELETE("/devices/:id", h.DeactivateDevice)
+		tracker.GET("/challenges", h.GetActiveChallenges)
+		tracker.POST("/challenges/:id/join", h.JoinChallenge)
+		tracker.GET("/challenges/:id/leaderboard", h.GetChallengeLeaderboard)
 
 		// Admin/Moderator routes
 		admin := tracker.Group("/admin")
@@ -49,14 +115,22 @@ func (h *TrackerHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware
 		{
 			admin.GET("/activities/unverified", h.GetUnverifiedActivities)
 			admin.PUT("/activities/:id/verify", h.VerifyActivity)
+			admin.PUT("/activities/:id/reject", h.RejectActivity)
 			admin.GET("/activities/recent", h.GetRecentActivities)
+			admin.PUT("/activity-types/:id/deactivate", h.DeactivateActivityType)
+			admin.DELETE("/activity-types/:id", h.DeleteActivityType)
+			admin.GET("/activity-types/:id/credit-rules", h.GetCreditRules)
+			admin.POST("/activity-types/:id/credit-rules", h.CreateCreditRule)
+			admin.PUT("/credit-rules/:id", h.UpdateCreditRule)
+			admin.PUT("/credit-rules/:id/deactivate", h.DeactivateCreditRule)
+			admin.POST("/challenges", h.CreateChallenge)
 		}
 	}
 }
 
 // LogActivity godoc
 // @Summary Log eco-friendly activity
-// @Description Log a new eco-friendly activity for the authenticated user
+// @Description Log a new eco-friendly activity for the authenticated user. Source defaults to manual; iot and webhook are reserved for device and webhook routes.
 // @Tags tracker
 // @Accept json
 // @Produce json
@@ -87,19 +161,10 @@ func (h *TrackerHandler) LogActivity(c *gin.Context) {
 	}
 	req.UserID = userID
 
-	// Set source if not provided
-	if req.Source == "" {
-		req.Source = "manual"
-	}
-
-	response, err := h.trackerService.LogActivity(c.Request.Context(), &req)
+	response, err := h.trackerService.LogUserActivity(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to log activity", err,
+		h.errMapper.Respond(c, err, "Failed to log activity",
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to log activity",
-			Details: err.Error(),
-		})
 		return
 	}
 
@@ -111,7 +176,7 @@ func (h *TrackerHandler) LogActivity(c *gin.Context) {
 // @Description Get activities for the authenticated user
 // @Tags tracker
 // @Produce json
-// @Param limit query int false "Limit" default(20)
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
 // @Param offset query int false "Offset" default(0)
 // @Success 200 {object} ActivityListResponse
 // @Failure 401 {object} ErrorResponse
@@ -125,25 +190,54 @@ func (h *TrackerHandler) GetUserActivities(c *gin.Context) {
 		return
 	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, offset := middleware.GetPagination(c)
 
 	activities, total, err := h.trackerService.GetUserActivities(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to get user activities", err,
+		h.errMapper.Respond(c, err, "Failed to get activities",
+			logger.String("user_id", userID))
+		return
+	}
+
+	response := ActivityListResponse{
+		Activities: activities,
+		PageInfo:   middleware.NewPageInfo(total, limit, offset),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPendingEvidenceActivities godoc
+// @Summary Get activities awaiting evidence
+// @Description Get the authenticated user's activities that require verification and have not been credited yet
+// @Tags tracker
+// @Produce json
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} ActivityListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/activities/pending-evidence [get]
+func (h *TrackerHandler) GetPendingEvidenceActivities(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	limit, offset := middleware.GetPagination(c)
+
+	activities, total, err := h.trackerService.GetPendingEvidenceActivities(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get activities",
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to get activities",
-			Details: err.Error(),
-		})
 		return
 	}
 
 	response := ActivityListResponse{
 		Activities: activities,
-		Total:      total,
-		Limit:      limit,
-		Offset:     offset,
+		PageInfo:   middleware.NewPageInfo(total, limit, offset),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -151,12 +245,14 @@ func (h *TrackerHandler) GetUserActivities(c *gin.Context) {
 
 // GetActivityByID godoc
 // @Summary Get activity by ID
-// @Description Get a specific activity by ID
+// @Description Get a specific activity by ID. With include=source_data the payload the activity was logged with is returned too; only the owner may request it.
 // @Tags tracker
 // @Produce json
 // @Param id path string true "Activity ID"
+// @Param include query string false "Set to source_data to include the submitted source data"
 // @Success 200 {object} service.ActivityResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
@@ -172,23 +268,125 @@ func (h *TrackerHandler) GetActivityByID(c *gin.Context) {
 		return
 	}
 
-	activity, err := h.trackerService.GetActivityByID(c.Request.Context(), id)
+	var activity *service.ActivityResponse
+	switch include := c.Query("include"); include {
+	case "":
+		activity, err = h.trackerService.GetActivityByID(c.Request.Context(), id)
+	case "source_data":
+		userID, exists := middleware.GetUserID(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+			return
+		}
+		activity, err = h.trackerService.GetActivityWithSourceData(c.Request.Context(), id, userID)
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid include parameter",
+			Details: fmt.Sprintf("unsupported include %q", include),
+		})
+		return
+	}
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get activity",
+			logger.String("activity_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, activity)
+}
+
+// UpdateActivity godoc
+// @Summary Update an activity
+// @Description Correct one of the authenticated user's activities. Credits are recomputed, and for a verified activity the wallet is adjusted by the difference.
+// @Tags tracker
+// @Accept json
+// @Produce json
+// @Param id path string true "Activity ID"
+// @Param request body service.UpdateActivityRequest true "Fields to change"
+// @Success 200 {object} service.ActivityResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/activities/{id} [put]
+func (h *TrackerHandler) UpdateActivity(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid activity ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req service.UpdateActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.trackerService.UpdateActivity(c.Request.Context(), id, userID, &req)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to get activity", err,
+		h.errMapper.Respond(c, err, "Failed to update activity",
 			logger.String("activity_id", id.String()))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to get activity",
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteActivity godoc
+// @Summary Delete an activity
+// @Description Delete one of the authenticated user's activities. Credits already earned from it are reversed in the wallet.
+// @Tags tracker
+// @Produce json
+// @Param id path string true "Activity ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/activities/{id} [delete]
+func (h *TrackerHandler) DeleteActivity(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid activity ID",
 			Details: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, activity)
+	if err := h.trackerService.DeleteActivity(c.Request.Context(), id, userID); err != nil {
+		h.errMapper.Respond(c, err, "Failed to delete activity",
+			logger.String("activity_id", id.String()))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
 // GetUserStats godoc
 // @Summary Get user activity statistics
-// @Description Get activity statistics for the authenticated user
+// @Description Get activity statistics for the authenticated user, with counts and credits split by source
 // @Tags tracker
 // @Produce json
 // @Param start_date query string false "Start date (RFC3339 format)"
@@ -223,12 +421,8 @@ func (h *TrackerHandler) GetUserStats(c *gin.Context) {
 
 	stats, err := h.trackerService.GetUserStats(c.Request.Context(), userID, startDate, endDate)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to get user stats", err,
+		h.errMapper.Respond(c, err, "Failed to get stats",
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to get stats",
-			Details: err.Error(),
-		})
 		return
 	}
 
@@ -280,6 +474,8 @@ func (h *TrackerHandler) GetActivityTypesByCategory(c *gin.Context) {
 // @Success 200 {object} SuccessResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
 // @Router /tracker/admin/activities/{id}/verify [put]
@@ -301,12 +497,8 @@ func (h *TrackerHandler) VerifyActivity(c *gin.Context) {
 	}
 
 	if err := h.trackerService.VerifyActivity(c.Request.Context(), id, verifiedBy); err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to verify activity", err,
+		h.errMapper.Respond(c, err, "Failed to verify activity",
 			logger.String("activity_id", id.String()))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to verify activity",
-			Details: err.Error(),
-		})
 		return
 	}
 
@@ -315,41 +507,790 @@ func (h *TrackerHandler) VerifyActivity(c *gin.Context) {
 	})
 }
 
-// Placeholder implementations for remaining endpoints
-func (h *TrackerHandler) HandleWebhook(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Webhook handler - to be implemented"})
-}
+// RejectActivity godoc
+// @Summary Reject activity
+// @Description Reject a suspicious activity with a reason (admin only). A rejected activity earns no credits; credits it already earned are debited back.
+// @Tags tracker
+// @Accept json
+// @Produce json
+// @Param id path string true "Activity ID"
+// @Param request body RejectActivityRequest true "Rejection reason"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/admin/activities/{id}/reject [put]
+func (h *TrackerHandler) RejectActivity(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid activity ID",
+			Details: err.Error(),
+		})
+		return
+	}
 
-func (h *TrackerHandler) HandleIoTData(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "IoT data handler - to be implemented"})
-}
+	moderatorID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
 
-func (h *TrackerHandler) GetUnverifiedActivities(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Get unverified activities - to be implemented"})
+	var req RejectActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.trackerService.RejectActivity(c.Request.Context(), id, moderatorID, req.Reason); err != nil {
+		h.errMapper.Respond(c, err, "Failed to reject activity",
+			logger.String("activity_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Activity rejected successfully",
+	})
 }
 
-func (h *TrackerHandler) GetRecentActivities(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Get recent activities - to be implemented"})
+// DeactivateActivityType godoc
+// @Summary Deactivate activity type
+// @Description Stop an activity type from accepting new activities (admin only). Existing activities and stats are kept.
+// @Tags tracker
+// @Produce json
+// @Param id path string true "Activity type ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/admin/activity-types/{id}/deactivate [put]
+func (h *TrackerHandler) DeactivateActivityType(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid activity type ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	actorID, _ := middleware.GetUserID(c)
+
+	if err := h.trackerService.DeactivateActivityType(c.Request.Context(), id, actorID); err != nil {
+		h.errMapper.Respond(c, err, "Failed to deactivate activity type",
+			logger.String("activity_type_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Activity type deactivated successfully",
+	})
 }
 
-// Response types
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Details string `json:"details,omitempty"`
+// DeleteActivityType godoc
+// @Summary Delete activity type
+// @Description Delete an unused activity type (admin only). Types with logged activities must be deactivated instead.
+// @Tags tracker
+// @Produce json
+// @Param id path string true "Activity type ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/admin/activity-types/{id} [delete]
+func (h *TrackerHandler) DeleteActivityType(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid activity type ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	actorID, _ := middleware.GetUserID(c)
+
+	if err := h.trackerService.DeleteActivityType(c.Request.Context(), id, actorID); err != nil {
+		h.errMapper.Respond(c, err, "Failed to delete activity type",
+			logger.String("activity_type_id", id.String()))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
-type SuccessResponse struct {
-	Message string `json:"message"`
+// HandleIoTData godoc
+// @Summary Report IoT telemetry
+// @Description Log a reading from a registered device, such as kWh generated or km cycled, as an activity of the device's owner. Devices authenticate with the API key issued at registration in the X-Device-Key header.
+// @Tags iot
+// @Accept json
+// @Produce json
+// @Param X-Device-Key header string true "Device API key"
+// @Param request body service.IoTDataRequest true "Telemetry"
+// @Success 201 {object} service.ActivityResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracker/iot [post]
+func (h *TrackerHandler) HandleIoTData(c *gin.Context) {
+	apiKey := c.GetHeader(DeviceKeyHeader)
+	if apiKey == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Device API key required"})
+		return
+	}
+
+	var req service.IoTDataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.deviceService.LogDeviceActivity(c.Request.Context(), apiKey, &req)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to log device activity")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
 }
 
-type ActivityListResponse struct {
-	Activities interface{} `json:"activities"`
-	Total      int64       `json:"total"`
-	Limit      int         `json:"limit"`
-	Offset     int         `json:"offset"`
+// RegisterDevice godoc
+// @Summary Register IoT device
+// @Description Register a device for the authenticated user. The response holds the device's API key, which is not shown again.
+// @Tags iot
+// @Accept json
+// @Produce json
+// @Param request body service.RegisterDeviceRequest true "Device"
+// @Success 201 {object} service.RegisteredDeviceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/devices [post]
+func (h *TrackerHandler) RegisterDevice(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	var req service.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.deviceService.RegisterDevice(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to register device",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
 }
 
-type ActivityTypesResponse struct {
-	ActivityTypes interface{} `json:"activity_types"`
-	Total         int64       `json:"total"`
+// GetDevices godoc
+// @Summary List IoT devices
+// @Description List the authenticated user's devices
+// @Tags iot
+// @Produce json
+// @Success 200 {object} DeviceListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/devices [get]
+func (h *TrackerHandler) GetDevices(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	devices, err := h.deviceService.ListDevices(c.Request.Context(), userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get devices",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, DeviceListResponse{Devices: devices})
+}
+
+// DeactivateDevice godoc
+// @Summary Deactivate IoT device
+// @Description Stop one of the authenticated user's devices from reporting; its API key is rejected from then on
+// @Tags iot
+// @Produce json
+// @Param id path string true "Device ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/devices/{id} [delete]
+func (h *TrackerHandler) DeactivateDevice(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.deviceService.DeactivateDevice(c.Request.Context(), userID, id); err != nil {
+		h.errMapper.Respond(c, err, "Failed to deactivate device",
+			logger.String("device_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Message: "Device deactivated successfully"})
+}
+
+// HandleWebhook godoc
+// @Summary Receive fitness app webhook
+// @Description Log an activity reported by a third-party fitness app. Deliveries are signed with the provider's shared secret: X-Webhook-Signature is the hex HMAC-SHA256 of the X-Webhook-Timestamp value (Unix seconds), a dot, and the raw body. Each provider event is logged once; redeliveries are accepted and flagged as duplicates.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name, e.g. strava"
+// @Param X-Webhook-Timestamp header string true "Unix timestamp the delivery was signed at"
+// @Param X-Webhook-Signature header string true "Hex HMAC-SHA256 signature"
+// @Success 202 {object} service.WebhookResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracker/webhook/{provider} [post]
+func (h *TrackerHandler) HandleWebhook(c *gin.Context) {
+	provider := c.Param("provider")
+
+	// The signature covers the exact bytes sent, so the body is read raw
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodyBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.webhookService.HandleWebhook(c.Request.Context(), provider,
+		c.GetHeader(WebhookTimestampHeader), c.GetHeader(WebhookSignatureHeader), payload)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to handle webhook",
+			logger.String("provider", provider))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
+
+// GetLeaderboard godoc
+// @Summary Get leaderboard
+// @Description Rank users by the credits earned or distance covered in their verified activities over a rolling period
+// @Tags tracker
+// @Produce json
+// @Param period query string false "daily, weekly, monthly or all_time" default(weekly)
+// @Param metric query string false "credits or distance" default(credits)
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
+// @Success 200 {object} UserLeaderboardResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/leaderboard [get]
+func (h *TrackerHandler) GetLeaderboard(c *gin.Context) {
+	period := c.DefaultQuery("period", service.LeaderboardWeekly)
+	metric := c.DefaultQuery("metric", models.LeaderboardMetricCredits)
+	limit, _ := middleware.GetPagination(c)
+
+	rankings, err := h.trackerService.GetLeaderboard(c.Request.Context(), period, metric, limit)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get leaderboard",
+			logger.String("period", period),
+			logger.String("metric", metric))
+		return
+	}
+
+	c.JSON(http.StatusOK, UserLeaderboardResponse{
+		Period:   period,
+		Metric:   metric,
+		Rankings: rankings,
+	})
+}
+
+// GetStreak godoc
+// @Summary Get activity streak
+// @Description Get the authenticated user's current and longest streaks of consecutive days with an activity. Reaching 7, 30 and 100 days earns a one-off bonus.
+// @Tags tracker
+// @Produce json
+// @Param timezone query string false "IANA timezone days are counted in, e.g. Europe/Paris" default(UTC)
+// @Success 200 {object} service.StreakResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/streak [get]
+func (h *TrackerHandler) GetStreak(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	timezone := c.Query("timezone")
+
+	streak, err := h.trackerService.GetUserStreak(c.Request.Context(), userID, timezone)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get streak",
+			logger.String("user_id", userID),
+			logger.String("timezone", timezone))
+		return
+	}
+
+	c.JSON(http.StatusOK, streak)
+}
+
+// GetActiveChallenges godoc
+// @Summary List active challenges
+// @Description List the challenges that have not ended and can be joined, soonest ending first
+// @Tags challenges
+// @Produce json
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} ChallengeListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/challenges [get]
+func (h *TrackerHandler) GetActiveChallenges(c *gin.Context) {
+	limit, offset := middleware.GetPagination(c)
+
+	challenges, total, err := h.challengeService.ListActiveChallenges(c.Request.Context(), limit, offset)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get challenges")
+		return
+	}
+
+	c.JSON(http.StatusOK, ChallengeListResponse{
+		Challenges: challenges,
+		PageInfo:   middleware.NewPageInfo(total, limit, offset),
+	})
+}
+
+// JoinChallenge godoc
+// @Summary Join challenge
+// @Description Join a challenge. Verified activities logged within its window from now on count towards its target.
+// @Tags challenges
+// @Produce json
+// @Param id path string true "Challenge ID"
+// @Success 201 {object} service.ParticipantResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/challenges/{id}/join [post]
+func (h *TrackerHandler) JoinChallenge(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid challenge ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	participant, err := h.challengeService.JoinChallenge(c.Request.Context(), id, userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to join challenge",
+			logger.String("challenge_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, participant)
+}
+
+// GetChallengeLeaderboard godoc
+// @Summary Get challenge leaderboard
+// @Description Get a challenge's participants ranked by progress, highest first
+// @Tags challenges
+// @Produce json
+// @Param id path string true "Challenge ID"
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} LeaderboardResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/challenges/{id}/leaderboard [get]
+func (h *TrackerHandler) GetChallengeLeaderboard(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid challenge ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	limit, offset := middleware.GetPagination(c)
+
+	entries, total, err := h.challengeService.GetLeaderboard(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get leaderboard",
+			logger.String("challenge_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, LeaderboardResponse{
+		Entries:  entries,
+		PageInfo: middleware.NewPageInfo(total, limit, offset),
+	})
+}
+
+// GetCreditRules godoc
+// @Summary List credit rules
+// @Description List the credit rules of an activity type (admin only), lowest range first. Deactivated rules are included with include_inactive=true.
+// @Tags tracker
+// @Produce json
+// @Param id path string true "Activity type ID"
+// @Param include_inactive query bool false "Include deactivated rules"
+// @Success 200 {object} CreditRuleListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/admin/activity-types/{id}/credit-rules [get]
+func (h *TrackerHandler) GetCreditRules(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid activity type ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	rules, err := h.creditRuleService.ListRules(c.Request.Context(), id, c.Query("include_inactive") == "true")
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get credit rules",
+			logger.String("activity_type_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, CreditRuleListResponse{CreditRules: rules})
+}
+
+// CreateCreditRule godoc
+// @Summary Create credit rule
+// @Description Add a credit rule to an activity type (admin only). Activities whose value falls in [min_value, max_value] earn credits_per_unit x multiplier per unit; a zero max_value is open-ended and the highest matching rate applies. New activities use the rule immediately.
+// @Tags tracker
+// @Accept json
+// @Produce json
+// @Param id path string true "Activity type ID"
+// @Param request body service.CreditRuleRequest true "Credit rule"
+// @Success 201 {object} models.CreditRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/admin/activity-types/{id}/credit-rules [post]
+func (h *TrackerHandler) CreateCreditRule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid activity type ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req service.CreditRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	actorID, _ := middleware.GetUserID(c)
+
+	rule, err := h.creditRuleService.CreateRule(c.Request.Context(), id, &req, actorID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to create credit rule",
+			logger.String("activity_type_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateCreditRule godoc
+// @Summary Update credit rule
+// @Description Replace a credit rule's range and rates (admin only). New activities use the updated rule immediately; activities already logged keep their credits.
+// @Tags tracker
+// @Accept json
+// @Produce json
+// @Param id path string true "Credit rule ID"
+// @Param request body service.CreditRuleRequest true "Credit rule"
+// @Success 200 {object} models.CreditRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/admin/credit-rules/{id} [put]
+func (h *TrackerHandler) UpdateCreditRule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid credit rule ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req service.CreditRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	actorID, _ := middleware.GetUserID(c)
+
+	rule, err := h.creditRuleService.UpdateRule(c.Request.Context(), id, &req, actorID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to update credit rule",
+			logger.String("credit_rule_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeactivateCreditRule godoc
+// @Summary Deactivate credit rule
+// @Description Stop a credit rule from applying to new activities (admin only). Activities already credited by it keep their credits.
+// @Tags tracker
+// @Produce json
+// @Param id path string true "Credit rule ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/admin/credit-rules/{id}/deactivate [put]
+func (h *TrackerHandler) DeactivateCreditRule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid credit rule ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	actorID, _ := middleware.GetUserID(c)
+
+	if err := h.creditRuleService.DeactivateRule(c.Request.Context(), id, actorID); err != nil {
+		h.errMapper.Respond(c, err, "Failed to deactivate credit rule",
+			logger.String("credit_rule_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Credit rule deactivated successfully",
+	})
+}
+
+// CreateChallenge godoc
+// @Summary Create challenge
+// @Description Create a challenge (admin only). target_unit is the metric progress is measured in: activities, km, minutes or credits. Participants who reach target_value receive reward_credits once.
+// @Tags challenges
+// @Accept json
+// @Produce json
+// @Param request body service.CreateChallengeRequest true "Challenge"
+// @Success 201 {object} service.ChallengeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/admin/challenges [post]
+func (h *TrackerHandler) CreateChallenge(c *gin.Context) {
+	createdBy, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	var req service.CreateChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	challenge, err := h.challengeService.CreateChallenge(c.Request.Context(), &req, createdBy)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to create challenge")
+		return
+	}
+
+	c.JSON(http.StatusCreated, challenge)
+}
+
+// GetUnverifiedActivities godoc
+// @Summary Get moderation queue
+// @Description Get activities awaiting verification across all users, oldest first (admin only). Rejected activities are excluded.
+// @Tags tracker
+// @Produce json
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} ActivityListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/admin/activities/unverified [get]
+func (h *TrackerHandler) GetUnverifiedActivities(c *gin.Context) {
+	limit, offset := middleware.GetPagination(c)
+
+	activities, total, err := h.trackerService.GetUnverifiedActivities(c.Request.Context(), limit, offset)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get activities")
+		return
+	}
+
+	response := ActivityListResponse{
+		Activities: activities,
+		PageInfo:   middleware.NewPageInfo(total, limit, offset),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRecentActivities godoc
+// @Summary Get recent activities
+// @Description Get activities across all users, newest first (admin only)
+// @Tags tracker
+// @Produce json
+// @Param activity_type query string false "Only activities of this type, e.g. biking"
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} ActivityListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tracker/admin/activities/recent [get]
+func (h *TrackerHandler) GetRecentActivities(c *gin.Context) {
+	activityType := c.Query("activity_type")
+	limit, offset := middleware.GetPagination(c)
+
+	activities, total, err := h.trackerService.GetRecentActivities(c.Request.Context(), activityType, limit, offset)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get activities",
+			logger.String("activity_type", activityType))
+		return
+	}
+
+	response := ActivityListResponse{
+		Activities: activities,
+		PageInfo:   middleware.NewPageInfo(total, limit, offset),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Response types
+type ErrorResponse = httperr.ErrorResponse
+
+type SuccessResponse struct {
+	Message string `json:"message"`
+}
+
+type ActivityListResponse struct {
+	Activities interface{} `json:"activities"`
+	middleware.PageInfo
+}
+
+type ActivityTypesResponse struct {
+	ActivityTypes interface{} `json:"activity_types"`
+	Total         int64       `json:"total"`
+}
+
+type DeviceListResponse struct {
+	Devices []*service.DeviceResponse `json:"devices"`
+}
+
+type RejectActivityRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type ChallengeListResponse struct {
+	Challenges []*service.ChallengeResponse `json:"challenges"`
+	middleware.PageInfo
+}
+
+type LeaderboardResponse struct {
+	Entries []*service.LeaderboardEntry `json:"entries"`
+	middleware.PageInfo
+}
+
+type CreditRuleListResponse struct {
+	CreditRules []*models.CreditRule `json:"credit_rules"`
+}
+
+type UserLeaderboardResponse struct {
+	Period   string                `json:"period"`
+	Metric   string                `json:"metric"`
+	Rankings []*models.UserRanking `json:"rankings"`
 }
@@ -10,7 +10,7 @@ import (
 // EcoActivity represents an eco-friendly activity
 type EcoActivity struct {
 	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID         string     `gorm:"not null;index" json:"user_id"`
+	UserID         string     `gorm:"not null;index;index:idx_eco_activities_leaderboard,priority:1" json:"user_id"`
 	ActivityTypeID uuid.UUID  `gorm:"type:uuid;not null;index" json:"activity_type_id"`
 	Description    string     `gorm:"not null" json:"description"`
 	Duration       int        `gorm:"not null" json:"duration"` // in minutes
@@ -19,13 +19,24 @@ type EcoActivity struct {
 	Unit           string     `json:"unit"`
 	Location       string     `json:"location"`
 	CreditsEarned  float64    `gorm:"not null;default:0" json:"credits_earned"`
-	IsVerified     bool       `gorm:"default:false" json:"is_verified"`
+	IsVerified     bool       `gorm:"default:false;index:idx_eco_activities_leaderboard,priority:3" json:"is_verified"`
 	VerifiedAt     *time.Time `json:"verified_at"`
 	VerifiedBy     string     `json:"verified_by"`
-	Source         string     `gorm:"not null" json:"source"`        // manual, iot, webhook, etc.
-	SourceData     string     `gorm:"type:jsonb" json:"source_data"` // Original data from source
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
+	// VerificationStatus is pending, approved or rejected; see the
+	// Verification* constants
+	VerificationStatus string     `gorm:"not null;default:'pending';index" json:"verification_status"`
+	RejectionReason    string     `json:"rejection_reason,omitempty"`
+	RejectedAt         *time.Time `json:"rejected_at,omitempty"`
+	RejectedBy         string     `json:"rejected_by,omitempty"`
+	Source             string     `gorm:"not null" json:"source"`        // manual, iot, webhook, etc.
+	SourceData         string     `gorm:"type:jsonb" json:"source_data"` // Original data from source
+	OccurredAt         *time.Time `gorm:"index" json:"occurred_at"`      // When the activity happened, if given
+	CreatedAt          time.Time  `gorm:"index:idx_eco_activities_leaderboard,priority:2" json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	// OutsideCreditWindow marks activities logged too long after they
+	// occurred to earn credits
+	OutsideCreditWindow bool `gorm:"default:false" json:"outside_credit_window"`
 
 	// Relationships
 	ActivityType ActivityType `gorm:"foreignKey:ActivityTypeID" json:"activity_type,omitempty"`
@@ -41,6 +52,7 @@ type ActivityType struct {
 	BaseCreditsPerUnit   float64   `gorm:"not null" json:"base_credits_per_unit"`
 	Unit                 string    `gorm:"not null" json:"unit"`
 	IsActive             bool      `gorm:"default:true" json:"is_active"`
+	DeactivatedBy        string    `json:"deactivated_by,omitempty"` // Admin who deactivated the type
 	RequiresVerification bool      `gorm:"default:false" json:"requires_verification"`
 	CreatedAt            time.Time `json:"created_at"`
 	UpdatedAt            time.Time `json:"updated_at"`
@@ -77,8 +89,9 @@ type ActivityChallenge struct {
 	StartDate     time.Time `gorm:"not null" json:"start_date"`
 	EndDate       time.Time `gorm:"not null" json:"end_date"`
 	TargetValue   float64   `gorm:"not null" json:"target_value"`
-	TargetUnit    string    `gorm:"not null" json:"target_unit"`
-	RewardCredits float64   `gorm:"not null" json:"reward_credits"`
+	TargetUnit    string    `gorm:"not null" json:"target_unit"`    // Metric progress is measured in; see the ChallengeMetric* constants
+	ActivityType  string    `json:"activity_type"`                  // Only activities of this type count; empty counts all
+	RewardCredits float64   `gorm:"not null" json:"reward_credits"` // Bonus credited once per participant who meets the target; zero disables it
 	IsActive      bool      `gorm:"default:true" json:"is_active"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
@@ -90,11 +103,12 @@ type ActivityChallenge struct {
 // ChallengeParticipant represents a user's participation in a challenge
 type ChallengeParticipant struct {
 	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	ChallengeID uuid.UUID  `gorm:"type:uuid;not null;index" json:"challenge_id"`
-	UserID      string     `gorm:"not null;index" json:"user_id"`
+	ChallengeID uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_challenge_participants_user" json:"challenge_id"`
+	UserID      string     `gorm:"not null;index;uniqueIndex:idx_challenge_participants_user" json:"user_id"`
 	Progress    float64    `gorm:"default:0" json:"progress"`
 	IsCompleted bool       `gorm:"default:false" json:"is_completed"`
 	CompletedAt *time.Time `json:"completed_at"`
+	RewardedAt  *time.Time `json:"rewarded_at"` // When the challenge bonus was published
 	JoinedAt    time.Time  `gorm:"default:now()" json:"joined_at"`
 
 	// Relationship
@@ -110,17 +124,41 @@ type IoTDevice struct {
 	Type      string     `gorm:"not null" json:"type"` // bike_sensor, smart_meter, etc.
 	IsActive  bool       `gorm:"default:true" json:"is_active"`
 	LastSeen  *time.Time `json:"last_seen"`
-	APIKey    string     `gorm:"uniqueIndex;not null" json:"api_key"`
+	APIKey    string     `gorm:"uniqueIndex;not null" json:"-"` // SHA-256 hash of the key
 	Settings  string     `gorm:"type:jsonb" json:"settings"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 }
 
+// WebhookEvent records a webhook delivery that was processed, so a retried
+// or replayed delivery is not logged twice
+type WebhookEvent struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Provider   string    `gorm:"not null;uniqueIndex:idx_webhook_events_provider_event" json:"provider"`
+	EventID    string    `gorm:"not null;uniqueIndex:idx_webhook_events_provider_event" json:"event_id"`
+	ReceivedAt time.Time `gorm:"not null" json:"received_at"`
+}
+
+// StreakReward records a streak milestone bonus awarded to a user, so each
+// milestone of a streak is paid once
+type StreakReward struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID          string    `gorm:"not null;uniqueIndex:idx_streak_rewards_milestone" json:"user_id"`
+	Milestone       int       `gorm:"not null;uniqueIndex:idx_streak_rewards_milestone" json:"milestone"`         // streak length in days
+	StreakStartedAt time.Time `gorm:"not null;uniqueIndex:idx_streak_rewards_milestone" json:"streak_started_at"` // first activity of the streak
+	Credits         float64   `gorm:"not null" json:"credits"`
+	AwardedAt       time.Time `gorm:"not null" json:"awarded_at"`
+}
+
 // BeforeCreate hooks
 func (e *EcoActivity) BeforeCreate(tx *gorm.DB) error {
 	if e.ID == uuid.Nil {
 		e.ID = uuid.New()
 	}
+	// An empty string is not valid jsonb
+	if e.SourceData == "" {
+		e.SourceData = "{}"
+	}
 	return nil
 }
 
@@ -159,6 +197,20 @@ func (i *IoTDevice) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+func (w *WebhookEvent) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *StreakReward) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
 // Table names
 func (EcoActivity) TableName() string          { return "eco_activities" }
 func (ActivityType) TableName() string         { return "activity_types" }
@@ -166,6 +218,8 @@ func (CreditRule) TableName() string           { return "credit_rules" }
 func (ActivityChallenge) TableName() string    { return "activity_challenges" }
 func (ChallengeParticipant) TableName() string { return "challenge_participants" }
 func (IoTDevice) TableName() string            { return "iot_devices" }
+func (WebhookEvent) TableName() string         { return "webhook_events" }
+func (StreakReward) TableName() string         { return "streak_rewards" }
 
 // Activity categories
 const (
@@ -185,6 +239,19 @@ const (
 	SourceImport  = "import"
 )
 
+// Activity verification statuses
+const (
+	VerificationPending  = "pending"
+	VerificationApproved = "approved"
+	VerificationRejected = "rejected"
+)
+
+// HasCredited reports whether the activity has already credited the user's
+// wallet. Credits are only published once an activity is verified.
+func (e *EcoActivity) HasCredited() bool {
+	return e.IsVerified && e.CreditsEarned > 0
+}
+
 // Common activity types
 const (
 	ActivityBiking         = "biking"
@@ -199,6 +266,24 @@ const (
 	ActivityVegetarianMeal = "vegetarian_meal"
 )
 
+// Challenge metrics, the units a challenge's target is measured in
+const (
+	ChallengeMetricActivities = "activities"
+	ChallengeMetricDistance   = "km"
+	ChallengeMetricDuration   = "minutes"
+	ChallengeMetricCredits    = "credits"
+)
+
+// IsChallengeMetric reports whether m is a known challenge metric
+func IsChallengeMetric(m string) bool {
+	switch m {
+	case ChallengeMetricActivities, ChallengeMetricDistance, ChallengeMetricDuration, ChallengeMetricCredits:
+		return true
+	default:
+		return false
+	}
+}
+
 // Device types
 const (
 	DeviceTypeBikeSensor     = "bike_sensor"
@@ -208,6 +293,17 @@ const (
 	DeviceTypeWeatherStation = "weather_station"
 )
 
+// IsDeviceType reports whether t is a known IoT device type
+func IsDeviceType(t string) bool {
+	switch t {
+	case DeviceTypeBikeSensor, DeviceTypeSmartMeter, DeviceTypeFitnessTracker,
+		DeviceTypeSmartScale, DeviceTypeWeatherStation:
+		return true
+	default:
+		return false
+	}
+}
+
 // UserActivityStats represents activity statistics for a user
 type UserActivityStats struct {
 	UserID             string    `json:"user_id"`
@@ -217,4 +313,27 @@ type UserActivityStats struct {
 	TotalDistance      float64   `json:"total_distance"`
 	StartDate          time.Time `json:"start_date"`
 	EndDate            time.Time `json:"end_date"`
+
+	// BySource splits the totals by where activities came from, keyed by
+	// source (manual, iot, webhook, ...). Sources with no activities are omitted.
+	BySource map[string]SourceActivityStats `json:"by_source"`
+}
+
+// SourceActivityStats are a user's activity totals for one source
+type SourceActivityStats struct {
+	Activities    int64   `json:"activities"`
+	CreditsEarned float64 `json:"credits_earned"`
+}
+
+// Leaderboard metrics, what users are ranked by
+const (
+	LeaderboardMetricCredits  = "credits"
+	LeaderboardMetricDistance = "distance"
+)
+
+// UserRanking is a user's standing on the activity leaderboard
+type UserRanking struct {
+	Rank   int     `json:"rank"`
+	UserID string  `json:"user_id"`
+	Total  float64 `json:"total"` // in the leaderboard's metric
 }
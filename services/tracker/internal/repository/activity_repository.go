@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
 	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/events"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"gorm.io/gorm"
 )
@@ -138,16 +139,55 @@ func (r *ActivityRepository) Update(ctx context.Context, activity *models.EcoAct
 	return nil
 }
 
-// Delete deletes an activity
-func (r *ActivityRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	err := r.db.WithContext(ctx).Delete(&models.EcoActivity{}, "id = ?", id).Error
-	if err != nil {
-		r.logger.LogError(ctx, "failed to delete activity", err,
-			logger.String("activity_id", id.String()))
-		return fmt.Errorf("failed to delete activity: %w", err)
-	}
+// Delete deletes an activity and, when event is not nil, writes it to the
+// outbox in the same transaction, so the event is published if and only if
+// the activity was deleted
+func (r *ActivityRepository) Delete(ctx context.Context, id uuid.UUID, event *events.OutboxEvent) error {
+	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		result := tx.Delete(&models.EcoActivity{}, "id = ?", id)
+		if result.Error != nil {
+			r.logger.LogError(ctx, "failed to delete activity", result.Error,
+				logger.String("activity_id", id.String()))
+			return fmt.Errorf("failed to delete activity: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return database.ErrNotFound
+		}
 
-	return nil
+		if event != nil {
+			if err := tx.Create(event).Error; err != nil {
+				r.logger.LogError(ctx, "failed to write activity event", err,
+					logger.String("activity_id", id.String()))
+				return fmt.Errorf("failed to write activity event: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// EraseUserData deletes all activities, challenge participations and IoT
+// devices belonging to a user
+func (r *ActivityRepository) EraseUserData(ctx context.Context, userID string) error {
+	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		owned := []interface{}{
+			&models.EcoActivity{},
+			&models.ChallengeParticipant{},
+			&models.IoTDevice{},
+		}
+		for _, model := range owned {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				r.logger.LogError(ctx, "failed to erase user activity data", err,
+					logger.String("user_id", userID))
+				return fmt.Errorf("failed to erase user activity data: %w", err)
+			}
+		}
+
+		r.logger.LogInfo(ctx, "user activity data erased",
+			logger.String("user_id", userID))
+
+		return nil
+	})
 }
 
 // GetUnverifiedActivities retrieves activities that require verification
@@ -179,6 +219,181 @@ func (r *ActivityRepository) GetUnverifiedActivities(ctx context.Context, limit,
 	return activities, total, nil
 }
 
+// GetPendingEvidenceByUserID retrieves a user's unverified activities whose
+// type requires verification, oldest first
+func (r *ActivityRepository) GetPendingEvidenceByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.EcoActivity, int64, error) {
+	var activities []*models.EcoActivity
+	var total int64
+
+	pending := func() *gorm.DB {
+		return r.db.WithContext(ctx).
+			Model(&models.EcoActivity{}).
+			Joins("JOIN activity_types ON activity_types.id = eco_activities.activity_type_id").
+			Where("eco_activities.user_id = ? AND eco_activities.is_verified = false AND activity_types.requires_verification = true", userID)
+	}
+
+	// Get total count
+	if err := pending().Count(&total).Error; err != nil {
+		r.logger.LogError(ctx, "failed to count pending evidence activities", err,
+			logger.String("user_id", userID))
+		return nil, 0, fmt.Errorf("failed to count pending evidence activities: %w", err)
+	}
+
+	// Get activities
+	err := pending().
+		Preload("ActivityType").
+		Order("eco_activities.created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&activities).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get pending evidence activities", err,
+			logger.String("user_id", userID))
+		return nil, 0, fmt.Errorf("failed to get pending evidence activities: %w", err)
+	}
+
+	return activities, total, nil
+}
+
+// GetUnverified retrieves the moderation queue: activities across all users
+// whose type requires verification and that are neither verified nor
+// rejected, oldest first
+func (r *ActivityRepository) GetUnverified(ctx context.Context, limit, offset int) ([]*models.EcoActivity, int64, error) {
+	var activities []*models.EcoActivity
+	var total int64
+
+	unverified := func() *gorm.DB {
+		return r.db.WithContext(ctx).
+			Model(&models.EcoActivity{}).
+			Joins("JOIN activity_types ON activity_types.id = eco_activities.activity_type_id").
+			Where("eco_activities.is_verified = false AND eco_activities.verification_status <> ? AND activity_types.requires_verification = true",
+				models.VerificationRejected)
+	}
+
+	// Get total count
+	if err := unverified().Count(&total).Error; err != nil {
+		r.logger.LogError(ctx, "failed to count unverified activities", err)
+		return nil, 0, fmt.Errorf("failed to count unverified activities: %w", err)
+	}
+
+	// Get activities; the ID breaks ties so pages never overlap
+	err := unverified().
+		Preload("ActivityType").
+		Order("eco_activities.created_at ASC, eco_activities.id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&activities).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get unverified activities", err)
+		return nil, 0, fmt.Errorf("failed to get unverified activities: %w", err)
+	}
+
+	return activities, total, nil
+}
+
+// GetRecent retrieves activities across all users, newest first. A non-empty
+// activityType limits them to activities of the type with that name.
+func (r *ActivityRepository) GetRecent(ctx context.Context, activityType string, limit, offset int) ([]*models.EcoActivity, int64, error) {
+	var activities []*models.EcoActivity
+	var total int64
+
+	recent := func() *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&models.EcoActivity{})
+		if activityType != "" {
+			query = query.
+				Joins("JOIN activity_types ON activity_types.id = eco_activities.activity_type_id").
+				Where("activity_types.name = ?", activityType)
+		}
+		return query
+	}
+
+	// Get total count
+	if err := recent().Count(&total).Error; err != nil {
+		r.logger.LogError(ctx, "failed to count recent activities", err,
+			logger.String("activity_type", activityType))
+		return nil, 0, fmt.Errorf("failed to count recent activities: %w", err)
+	}
+
+	// Get activities; the ID breaks ties so pages never overlap
+	err := recent().
+		Preload("ActivityType").
+		Order("eco_activities.created_at DESC, eco_activities.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&activities).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get recent activities", err,
+			logger.String("activity_type", activityType))
+		return nil, 0, fmt.Errorf("failed to get recent activities: %w", err)
+	}
+
+	return activities, total, nil
+}
+
+// GetActivityTimes retrieves when each of the user's activities happened,
+// earliest first, falling back to when it was logged. Rejected activities
+// are left out.
+func (r *ActivityRepository) GetActivityTimes(ctx context.Context, userID string) ([]time.Time, error) {
+	var times []time.Time
+
+	err := r.db.WithContext(ctx).
+		Model(&models.EcoActivity{}).
+		Where("user_id = ? AND verification_status <> ?", userID, models.VerificationRejected).
+		Order("COALESCE(occurred_at, created_at) ASC").
+		Pluck("COALESCE(occurred_at, created_at)", &times).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get activity times", err,
+			logger.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get activity times: %w", err)
+	}
+
+	return times, nil
+}
+
+// leaderboardColumns are the columns summed for each leaderboard metric
+var leaderboardColumns = map[string]string{
+	models.LeaderboardMetricCredits:  "credits_earned",
+	models.LeaderboardMetricDistance: "distance",
+}
+
+// GetLeaderboard retrieves the users with the highest totals of a metric
+// over their verified activities created since since, highest first. A nil
+// since covers all time. Ranks are left for the caller to assign.
+func (r *ActivityRepository) GetLeaderboard(ctx context.Context, metric string, since *time.Time, limit int) ([]*models.UserRanking, error) {
+	column, ok := leaderboardColumns[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown leaderboard metric %q", metric)
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&models.EcoActivity{}).
+		Select(fmt.Sprintf("user_id, COALESCE(SUM(%s), 0) AS total", column)).
+		Where("is_verified = ?", true)
+	if since != nil {
+		query = query.Where("created_at >= ?", *since)
+	}
+
+	var rankings []*models.UserRanking
+	err := query.
+		Group("user_id").
+		Having(fmt.Sprintf("SUM(%s) > 0", column)).
+		Order("total DESC, user_id ASC").
+		Limit(limit).
+		Find(&rankings).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get leaderboard", err,
+			logger.String("metric", metric))
+		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+
+	return rankings, nil
+}
+
 // GetUserStats retrieves activity statistics for a user
 func (r *ActivityRepository) GetUserStats(ctx context.Context, userID string, startDate, endDate time.Time) (*models.UserActivityStats, error) {
 	var result struct {
@@ -205,6 +420,29 @@ func (r *ActivityRepository) GetUserStats(ctx context.Context, userID string, st
 		return nil, fmt.Errorf("failed to get user stats: %w", err)
 	}
 
+	var sourceRows []struct {
+		Source        string  `gorm:"column:source"`
+		Activities    int64   `gorm:"column:activities"`
+		CreditsEarned float64 `gorm:"column:credits_earned"`
+	}
+
+	err = r.db.WithContext(ctx).
+		Model(&models.EcoActivity{}).
+		Select(`
+			source,
+			COUNT(*) as activities,
+			COALESCE(SUM(credits_earned), 0) as credits_earned
+		`).
+		Where("user_id = ? AND created_at >= ? AND created_at <= ?", userID, startDate, endDate).
+		Group("source").
+		Scan(&sourceRows).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get user stats by source", err,
+			logger.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get user stats by source: %w", err)
+	}
+
 	stats := &models.UserActivityStats{
 		UserID:             userID,
 		TotalActivities:    result.TotalActivities,
@@ -213,11 +451,36 @@ func (r *ActivityRepository) GetUserStats(ctx context.Context, userID string, st
 		TotalDistance:      result.TotalDistance,
 		StartDate:          startDate,
 		EndDate:            endDate,
+		BySource:           make(map[string]models.SourceActivityStats, len(sourceRows)),
+	}
+	for _, row := range sourceRows {
+		stats.BySource[row.Source] = models.SourceActivityStats{
+			Activities:    row.Activities,
+			CreditsEarned: row.CreditsEarned,
+		}
 	}
 
 	return stats, nil
 }
 
+// CountByActivityType counts the activities logged against an activity type
+func (r *ActivityRepository) CountByActivityType(ctx context.Context, activityTypeID uuid.UUID) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.EcoActivity{}).
+		Where("activity_type_id = ?", activityTypeID).
+		Count(&count).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to count activities by type", err,
+			logger.String("activity_type_id", activityTypeID.String()))
+		return 0, fmt.Errorf("failed to count activities: %w", err)
+	}
+
+	return count, nil
+}
+
 // GetActivitiesByType retrieves activities by activity type
 func (r *ActivityRepository) GetActivitiesByType(ctx context.Context, activityTypeID uuid.UUID, limit, offset int) ([]*models.EcoActivity, int64, error) {
 	var activities []*models.EcoActivity
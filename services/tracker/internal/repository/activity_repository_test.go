@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newDryRunActivityRepository returns an activity repository whose queries
+// are built but never sent to a database, and the SQL of each of them
+func newDryRunActivityRepository(t *testing.T) (*ActivityRepository, *[]string) {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open dry run database: %v", err)
+	}
+
+	var queries []string
+	db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		queries = append(queries, tx.Statement.SQL.String())
+	})
+
+	return NewActivityRepository(&database.PostgresDB{DB: db}, logger.New("error")), &queries
+}
+
+// pageQuery returns the query that fetched the page, which follows the count
+func pageQuery(t *testing.T, queries []string) string {
+	t.Helper()
+
+	for _, query := range queries {
+		if strings.Contains(query, "ORDER BY") {
+			return query
+		}
+	}
+	t.Fatalf("Expected a paged query, got %v", queries)
+	return ""
+}
+
+func TestActivityRepository_GetUnverifiedIsFIFO(t *testing.T) {
+	repo, queries := newDryRunActivityRepository(t)
+
+	if _, _, err := repo.GetUnverified(context.Background(), 20, 40); err != nil {
+		t.Fatalf("GetUnverified failed: %v", err)
+	}
+
+	query := pageQuery(t, *queries)
+	if !strings.Contains(query, "ORDER BY eco_activities.created_at ASC, eco_activities.id ASC") {
+		t.Errorf("Expected oldest activities first with a stable tie-break, got %s", query)
+	}
+	if !strings.Contains(query, "LIMIT 20 OFFSET 40") {
+		t.Errorf("Expected the requested page, got %s", query)
+	}
+	for _, condition := range []string{"is_verified = false", "verification_status <>", "requires_verification = true"} {
+		if !strings.Contains(query, condition) {
+			t.Errorf("Expected the queue to filter on %q, got %s", condition, query)
+		}
+	}
+}
+
+func TestActivityRepository_GetRecentIsNewestFirst(t *testing.T) {
+	repo, queries := newDryRunActivityRepository(t)
+
+	if _, _, err := repo.GetRecent(context.Background(), "", 10, 0); err != nil {
+		t.Fatalf("GetRecent failed: %v", err)
+	}
+
+	query := pageQuery(t, *queries)
+	if !strings.Contains(query, "ORDER BY eco_activities.created_at DESC, eco_activities.id DESC") {
+		t.Errorf("Expected newest activities first with a stable tie-break, got %s", query)
+	}
+	if !strings.Contains(query, "LIMIT 10") {
+		t.Errorf("Expected the requested page size, got %s", query)
+	}
+	if strings.Contains(query, "activity_types.name") {
+		t.Errorf("Expected no activity type filter, got %s", query)
+	}
+}
+
+func TestActivityRepository_GetRecentFiltersByActivityType(t *testing.T) {
+	repo, queries := newDryRunActivityRepository(t)
+
+	if _, _, err := repo.GetRecent(context.Background(), "biking", 10, 0); err != nil {
+		t.Fatalf("GetRecent failed: %v", err)
+	}
+
+	for _, query := range *queries {
+		if strings.HasPrefix(query, "SELECT") && strings.Contains(query, "eco_activities") &&
+			!strings.Contains(query, "activity_types.name = $1") {
+			t.Errorf("Expected every activity query filtered by type, got %s", query)
+		}
+	}
+}
+
+func TestChallengeRepository_LeaderboardRanksByProgress(t *testing.T) {
+	activities, queries := newDryRunActivityRepository(t)
+	repo := NewChallengeRepository(activities.db, logger.New("error"))
+
+	if _, _, err := repo.GetLeaderboard(context.Background(), uuid.New(), 10, 20); err != nil {
+		t.Fatalf("GetLeaderboard failed: %v", err)
+	}
+
+	query := pageQuery(t, *queries)
+	if !strings.Contains(query, "ORDER BY progress DESC, completed_at ASC NULLS LAST, joined_at ASC") {
+		t.Errorf("Expected highest progress first, ties to whoever finished first, got %s", query)
+	}
+	if !strings.Contains(query, "LIMIT 10 OFFSET 20") {
+		t.Errorf("Expected the requested page, got %s", query)
+	}
+}
+
+func TestActivityRepository_GetLeaderboardRanksVerifiedTotals(t *testing.T) {
+	repo, queries := newDryRunActivityRepository(t)
+	since := time.Date(2024, 6, 8, 12, 0, 0, 0, time.UTC)
+
+	if _, err := repo.GetLeaderboard(context.Background(), models.LeaderboardMetricDistance, &since, 10); err != nil {
+		t.Fatalf("GetLeaderboard failed: %v", err)
+	}
+
+	query := pageQuery(t, *queries)
+	for _, part := range []string{
+		"COALESCE(SUM(distance), 0) AS total",
+		"is_verified = $1",
+		"created_at >= $",
+		"GROUP BY \"user_id\"",
+		"ORDER BY total DESC, user_id ASC",
+		"LIMIT 10",
+	} {
+		if !strings.Contains(query, part) {
+			t.Errorf("Expected the leaderboard query to contain %q, got %s", part, query)
+		}
+	}
+
+	if _, err := repo.GetLeaderboard(context.Background(), "co2; DROP TABLE eco_activities", nil, 10); err == nil {
+		t.Error("Expected an unknown metric to be rejected")
+	}
+}
@@ -203,6 +203,25 @@ func (r *CreditRuleRepository) GetActiveRulesByActivityType(ctx context.Context,
 	return rules, nil
 }
 
+// GetByActivityType retrieves an activity type's credit rules, only the
+// active ones unless includeInactive is set
+func (r *CreditRuleRepository) GetByActivityType(ctx context.Context, activityTypeID uuid.UUID, includeInactive bool) ([]*models.CreditRule, error) {
+	var rules []*models.CreditRule
+
+	query := r.db.WithContext(ctx).Where("activity_type_id = ?", activityTypeID)
+	if !includeInactive {
+		query = query.Where("is_active = true")
+	}
+
+	if err := query.Order("min_value ASC, created_at ASC").Find(&rules).Error; err != nil {
+		r.logger.LogError(ctx, "failed to get credit rules", err,
+			logger.String("activity_type_id", activityTypeID.String()))
+		return nil, fmt.Errorf("failed to get credit rules: %w", err)
+	}
+
+	return rules, nil
+}
+
 // Update updates a credit rule
 func (r *CreditRuleRepository) Update(ctx context.Context, rule *models.CreditRule) error {
 	err := r.db.WithContext(ctx).Save(rule).Error
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ChallengeRepository handles challenge and participant data operations
+type ChallengeRepository struct {
+	db     *database.PostgresDB
+	logger *logger.Logger
+}
+
+// NewChallengeRepository creates a new challenge repository
+func NewChallengeRepository(db *database.PostgresDB, logger *logger.Logger) *ChallengeRepository {
+	return &ChallengeRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateChallenge creates a new challenge
+func (r *ChallengeRepository) CreateChallenge(ctx context.Context, challenge *models.ActivityChallenge) error {
+	if err := r.db.WithContext(ctx).Create(challenge).Error; err != nil {
+		r.logger.LogError(ctx, "failed to create challenge", err,
+			logger.String("name", challenge.Name))
+		return fmt.Errorf("failed to create challenge: %w", err)
+	}
+
+	return nil
+}
+
+// GetChallenge retrieves a challenge by ID
+func (r *ChallengeRepository) GetChallenge(ctx context.Context, id uuid.UUID) (*models.ActivityChallenge, error) {
+	var challenge models.ActivityChallenge
+
+	err := r.db.WithContext(ctx).First(&challenge, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+
+	return &challenge, nil
+}
+
+// ListActive retrieves the active challenges that have not ended by now,
+// soonest ending first
+func (r *ChallengeRepository) ListActive(ctx context.Context, now time.Time, limit, offset int) ([]*models.ActivityChallenge, int64, error) {
+	var challenges []*models.ActivityChallenge
+	var total int64
+
+	active := func() *gorm.DB {
+		return r.db.WithContext(ctx).
+			Model(&models.ActivityChallenge{}).
+			Where("is_active = ? AND end_date >= ?", true, now)
+	}
+
+	// Get total count
+	if err := active().Count(&total).Error; err != nil {
+		r.logger.LogError(ctx, "failed to count active challenges", err)
+		return nil, 0, fmt.Errorf("failed to count challenges: %w", err)
+	}
+
+	// Get challenges
+	err := active().
+		Order("end_date ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&challenges).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get active challenges", err)
+		return nil, 0, fmt.Errorf("failed to get challenges: %w", err)
+	}
+
+	return challenges, total, nil
+}
+
+// IsParticipant reports whether the user has joined the challenge
+func (r *ChallengeRepository) IsParticipant(ctx context.Context, challengeID uuid.UUID, userID string) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.ChallengeParticipant{}).
+		Where("challenge_id = ? AND user_id = ?", challengeID, userID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check challenge participant: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// AddParticipant adds a user to a challenge
+func (r *ChallengeRepository) AddParticipant(ctx context.Context, participant *models.ChallengeParticipant) error {
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Create(participant).Error; err != nil {
+		r.logger.LogError(ctx, "failed to add challenge participant", err,
+			logger.String("challenge_id", participant.ChallengeID.String()),
+			logger.String("user_id", participant.UserID))
+		return fmt.Errorf("failed to add challenge participant: %w", err)
+	}
+
+	return nil
+}
+
+// GetLeaderboard retrieves a challenge's participants by progress, highest
+// first. Ties go to whoever completed, then joined, first.
+func (r *ChallengeRepository) GetLeaderboard(ctx context.Context, challengeID uuid.UUID, limit, offset int) ([]*models.ChallengeParticipant, int64, error) {
+	var participants []*models.ChallengeParticipant
+	var total int64
+
+	if err := r.db.WithContext(ctx).
+		Model(&models.ChallengeParticipant{}).
+		Where("challenge_id = ?", challengeID).
+		Count(&total).Error; err != nil {
+		r.logger.LogError(ctx, "failed to count challenge participants", err,
+			logger.String("challenge_id", challengeID.String()))
+		return nil, 0, fmt.Errorf("failed to count challenge participants: %w", err)
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("challenge_id = ?", challengeID).
+		Order("progress DESC, completed_at ASC NULLS LAST, joined_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&participants).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get challenge leaderboard", err,
+			logger.String("challenge_id", challengeID.String()))
+		return nil, 0, fmt.Errorf("failed to get challenge leaderboard: %w", err)
+	}
+
+	return participants, total, nil
+}
+
+// GetOpenParticipations retrieves the user's participations, with their
+// challenges, in active challenges whose window includes at and that count
+// activities of the given type
+func (r *ChallengeRepository) GetOpenParticipations(ctx context.Context, userID string, at time.Time, activityType string) ([]*models.ChallengeParticipant, error) {
+	var participants []*models.ChallengeParticipant
+
+	err := r.db.WithContext(ctx).
+		Preload("Challenge").
+		Joins("JOIN activity_challenges ON activity_challenges.id = challenge_participants.challenge_id").
+		Where("challenge_participants.user_id = ?", userID).
+		Where("activity_challenges.is_active = ? AND activity_challenges.start_date <= ? AND activity_challenges.end_date >= ?", true, at, at).
+		Where("activity_challenges.activity_type = '' OR activity_challenges.activity_type IS NULL OR activity_challenges.activity_type = ?", activityType).
+		Find(&participants).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get open challenge participations", err,
+			logger.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get challenge participations: %w", err)
+	}
+
+	return participants, nil
+}
+
+// AddProgress adds amount to a participant's progress and returns the new
+// progress. The addition happens in the database so concurrent activities
+// are all counted.
+func (r *ChallengeRepository) AddProgress(ctx context.Context, id uuid.UUID, amount float64) (float64, error) {
+	var participant models.ChallengeParticipant
+	err := r.db.WithContext(ctx).
+		Model(&participant).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "progress"}}}).
+		Where("id = ?", id).
+		UpdateColumn("progress", gorm.Expr("progress + ?", amount)).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to add challenge progress", err,
+			logger.String("participant_id", id.String()))
+		return 0, fmt.Errorf("failed to add challenge progress: %w", err)
+	}
+
+	return participant.Progress, nil
+}
+
+// MarkCompleted records that a participant met the challenge's target. It
+// returns false when the participant had already completed it.
+func (r *ChallengeRepository) MarkCompleted(ctx context.Context, id uuid.UUID, completedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ChallengeParticipant{}).
+		Where("id = ? AND is_completed = ?", id, false).
+		UpdateColumns(map[string]interface{}{
+			"is_completed": true,
+			"completed_at": completedAt,
+		})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark challenge completed: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// GetParticipant retrieves a challenge participant with its challenge
+func (r *ChallengeRepository) GetParticipant(ctx context.Context, id uuid.UUID) (*models.ChallengeParticipant, error) {
+	var participant models.ChallengeParticipant
+
+	err := r.db.WithContext(ctx).
+		Preload("Challenge").
+		First(&participant, "id = ?", id).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get challenge participant: %w", err)
+	}
+
+	return &participant, nil
+}
+
+// MarkRewarded records that a participant completed the challenge and was
+// rewarded at the given time. It returns false when the participant was
+// already rewarded, so concurrent callers cannot both award the bonus.
+func (r *ChallengeRepository) MarkRewarded(ctx context.Context, id uuid.UUID, rewardedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ChallengeParticipant{}).
+		Where("id = ? AND rewarded_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"is_completed": true,
+			"completed_at": gorm.Expr("COALESCE(completed_at, ?)", rewardedAt),
+			"rewarded_at":  rewardedAt,
+		})
+
+	if result.Error != nil {
+		r.logger.LogError(ctx, "failed to mark challenge participant rewarded", result.Error,
+			logger.String("participant_id", id.String()))
+		return false, fmt.Errorf("failed to mark challenge participant rewarded: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// ClearReward undoes MarkRewarded so a reward that could not be published
+// can be awarded again. Completion is kept.
+func (r *ChallengeRepository) ClearReward(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.ChallengeParticipant{}).
+		Where("id = ?", id).
+		Update("rewarded_at", nil).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to clear challenge reward: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm"
+)
+
+// IoTDeviceRepository handles IoT device data operations
+type IoTDeviceRepository struct {
+	db     *database.PostgresDB
+	logger *logger.Logger
+}
+
+// NewIoTDeviceRepository creates a new IoT device repository
+func NewIoTDeviceRepository(db *database.PostgresDB, logger *logger.Logger) *IoTDeviceRepository {
+	return &IoTDeviceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// HashAPIKey returns the form a device API key is stored and looked up in
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create registers a device with the API key in device.APIKey. Only the
+// key's hash is stored, and device.APIKey is replaced with it.
+func (r *IoTDeviceRepository) Create(ctx context.Context, device *models.IoTDevice) error {
+	device.APIKey = HashAPIKey(device.APIKey)
+
+	if err := r.db.WithContext(ctx).Create(device).Error; err != nil {
+		r.logger.LogError(ctx, "failed to create IoT device", err,
+			logger.String("user_id", device.UserID),
+			logger.String("device_id", device.DeviceID))
+		return fmt.Errorf("failed to create IoT device: %w", err)
+	}
+
+	return nil
+}
+
+// GetByAPIKey retrieves the device an API key was issued to
+func (r *IoTDeviceRepository) GetByAPIKey(ctx context.Context, apiKey string) (*models.IoTDevice, error) {
+	var device models.IoTDevice
+
+	err := r.db.WithContext(ctx).First(&device, "api_key = ?", HashAPIKey(apiKey)).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		r.logger.LogError(ctx, "failed to get IoT device by API key", err)
+		return nil, fmt.Errorf("failed to get IoT device: %w", err)
+	}
+
+	return &device, nil
+}
+
+// GetByUserID retrieves a user's devices, newest first
+func (r *IoTDeviceRepository) GetByUserID(ctx context.Context, userID string) ([]*models.IoTDevice, error) {
+	var devices []*models.IoTDevice
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&devices).Error
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get IoT devices", err,
+			logger.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get IoT devices: %w", err)
+	}
+
+	return devices, nil
+}
+
+// DeviceIDExists reports whether a device with the given hardware ID is registered
+func (r *IoTDeviceRepository) DeviceIDExists(ctx context.Context, deviceID string) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.IoTDevice{}).
+		Where("device_id = ?", deviceID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check device ID: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// UpdateLastSeen records when the device last reported
+func (r *IoTDeviceRepository) UpdateLastSeen(ctx context.Context, id uuid.UUID, at time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.IoTDevice{}).
+		Where("id = ?", id).
+		UpdateColumn("last_seen", at).Error
+	if err != nil {
+		return fmt.Errorf("failed to update device last seen: %w", err)
+	}
+
+	return nil
+}
+
+// Deactivate stops one of a user's devices from reporting. It returns
+// database.ErrNotFound if the device does not belong to userID or is
+// already inactive.
+func (r *IoTDeviceRepository) Deactivate(ctx context.Context, id uuid.UUID, userID string) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.IoTDevice{}).
+		Where("id = ? AND user_id = ? AND is_active = ?", id, userID, true).
+		Update("is_active", false)
+
+	if result.Error != nil {
+		r.logger.LogError(ctx, "failed to deactivate IoT device", result.Error,
+			logger.String("device_id", id.String()))
+		return fmt.Errorf("failed to deactivate IoT device: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return database.ErrNotFound
+	}
+
+	return nil
+}
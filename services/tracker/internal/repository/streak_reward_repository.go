@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm/clause"
+)
+
+// StreakRewardRepository records the streak milestone bonuses users were awarded
+type StreakRewardRepository struct {
+	db     *database.PostgresDB
+	logger *logger.Logger
+}
+
+// NewStreakRewardRepository creates a new streak reward repository
+func NewStreakRewardRepository(db *database.PostgresDB, logger *logger.Logger) *StreakRewardRepository {
+	return &StreakRewardRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Record stores a milestone bonus and returns false if the same milestone of
+// the same streak was already recorded, so it cannot be awarded twice
+func (r *StreakRewardRepository) Record(ctx context.Context, reward *models.StreakReward) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(reward)
+	if result.Error != nil {
+		r.logger.LogError(ctx, "failed to record streak reward", result.Error,
+			logger.String("user_id", reward.UserID),
+			logger.Int("milestone", reward.Milestone))
+		return false, fmt.Errorf("failed to record streak reward: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Delete forgets a recorded bonus so it can be awarded again
+func (r *StreakRewardRepository) Delete(ctx context.Context, userID string, milestone int, streakStartedAt time.Time) error {
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND milestone = ? AND streak_started_at = ?", userID, milestone, streakStartedAt).
+		Delete(&models.StreakReward{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete streak reward: %w", err)
+	}
+
+	return nil
+}
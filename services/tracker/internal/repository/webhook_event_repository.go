@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookEventRepository records processed webhook deliveries
+type WebhookEventRepository struct {
+	db     *database.PostgresDB
+	logger *logger.Logger
+}
+
+// NewWebhookEventRepository creates a new webhook event repository
+func NewWebhookEventRepository(db *database.PostgresDB, logger *logger.Logger) *WebhookEventRepository {
+	return &WebhookEventRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Record stores a provider's event ID and returns false if it was already
+// recorded, so concurrent deliveries of one event cannot both be processed
+func (r *WebhookEventRepository) Record(ctx context.Context, provider, eventID string, receivedAt time.Time) (bool, error) {
+	event := &models.WebhookEvent{
+		Provider:   provider,
+		EventID:    eventID,
+		ReceivedAt: receivedAt,
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(event)
+	if result.Error != nil {
+		r.logger.LogError(ctx, "failed to record webhook event", result.Error,
+			logger.String("provider", provider),
+			logger.String("event_id", eventID))
+		return false, fmt.Errorf("failed to record webhook event: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Delete forgets a recorded event so a retried delivery is processed again
+func (r *WebhookEventRepository) Delete(ctx context.Context, provider, eventID string) error {
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND event_id = ?", provider, eventID).
+		Delete(&models.WebhookEvent{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook event: %w", err)
+	}
+
+	return nil
+}
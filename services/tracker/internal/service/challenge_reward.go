@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/credits"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// ChallengeBonusSource is the wallet credit source of challenge rewards
+const ChallengeBonusSource = "challenge_bonus"
+
+// ChallengeRewarder credits participants who meet a challenge's target with
+// the challenge's reward through the credit earned event pipeline
+type ChallengeRewarder struct {
+	challengeRepo  *repository.ChallengeRepository
+	eventPublisher EventPublisher
+	rounding       credits.RoundingPolicy
+	logger         *logger.Logger
+}
+
+// NewChallengeRewarder creates a new challenge rewarder. A zero rounding
+// policy falls back to credits.DefaultRoundingPolicy.
+func NewChallengeRewarder(
+	challengeRepo *repository.ChallengeRepository,
+	eventPublisher EventPublisher,
+	rounding credits.RoundingPolicy,
+	logger *logger.Logger,
+) *ChallengeRewarder {
+	if rounding.Mode == "" {
+		rounding = credits.DefaultRoundingPolicy
+	}
+
+	return &ChallengeRewarder{
+		challengeRepo:  challengeRepo,
+		eventPublisher: eventPublisher,
+		rounding:       rounding,
+		logger:         logger,
+	}
+}
+
+// AwardReward publishes the challenge bonus for a participant who has met
+// the target and reports whether this call awarded it. The participant is
+// marked rewarded first, so the bonus is published at most once; the wallet
+// also skips credits it has already applied for the same reference.
+func (r *ChallengeRewarder) AwardReward(ctx context.Context, participantID uuid.UUID) (bool, error) {
+	participant, err := r.challengeRepo.GetParticipant(ctx, participantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get challenge participant: %w", err)
+	}
+
+	if !challengeRewardDue(&participant.Challenge, participant) {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	marked, err := r.challengeRepo.MarkRewarded(ctx, participant.ID, now)
+	if err != nil {
+		return false, err
+	}
+	if !marked {
+		// Another caller rewarded the participant first
+		return false, nil
+	}
+
+	reward := r.rounding.RoundFloat(participant.Challenge.RewardCredits)
+	event := &CreditEarnedEvent{
+		UserID:        participant.UserID,
+		ActivityID:    challengeRewardReference(participant),
+		ActivityType:  "challenge",
+		CreditsEarned: reward,
+		Description:   fmt.Sprintf("Completed challenge %s", participant.Challenge.Name),
+		Timestamp:     now,
+		Source:        ChallengeBonusSource,
+	}
+
+	if err := r.eventPublisher.PublishCreditEarned(ctx, event); err != nil {
+		// Leave the participant unrewarded so the bonus can be retried
+		if clearErr := r.challengeRepo.ClearReward(ctx, participant.ID); clearErr != nil {
+			r.logger.LogError(ctx, "failed to clear challenge reward", clearErr,
+				logger.String("participant_id", participant.ID.String()))
+		}
+		return false, fmt.Errorf("failed to publish challenge reward: %w", err)
+	}
+
+	r.logger.LogInfo(ctx, "challenge reward awarded",
+		logger.String("challenge_id", participant.ChallengeID.String()),
+		logger.String("user_id", participant.UserID),
+		logger.Float64("credits", reward))
+
+	return true, nil
+}
+
+// challengeRewardDue reports whether a participant has met an active
+// challenge's target, the challenge has a reward and it is still unpaid
+func challengeRewardDue(challenge *models.ActivityChallenge, participant *models.ChallengeParticipant) bool {
+	return challenge.IsActive &&
+		challenge.RewardCredits > 0 &&
+		participant.RewardedAt == nil &&
+		participant.Progress >= challenge.TargetValue
+}
+
+// challengeRewardReference is the wallet reference of a challenge reward. It
+// is the same for every participation of a user in a challenge, so the
+// wallet credits each user at most once per challenge.
+func challengeRewardReference(participant *models.ChallengeParticipant) string {
+	return fmt.Sprintf("challenge:%s:%s", participant.ChallengeID, participant.UserID)
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// ErrChallengeNotFound is returned when a challenge does not exist
+var ErrChallengeNotFound = errors.New("challenge not found")
+
+// ErrInvalidChallenge is returned when creating a challenge with an unknown
+// metric or activity type, or an empty window
+var ErrInvalidChallenge = errors.New("invalid challenge")
+
+// ErrChallengeClosed is returned when joining a challenge that is inactive
+// or has ended
+var ErrChallengeClosed = errors.New("challenge is closed")
+
+// ErrAlreadyJoined is returned when a user joins a challenge twice
+var ErrAlreadyJoined = errors.New("already joined challenge")
+
+// ChallengeService runs activity challenges: admins create them, users join
+// them, and activities logged within a challenge's window count towards its
+// target. Participants who meet the target receive the challenge's reward.
+type ChallengeService struct {
+	challengeRepo    *repository.ChallengeRepository
+	activityTypeRepo *repository.ActivityTypeRepository
+	rewarder         *ChallengeRewarder
+	logger           *logger.Logger
+}
+
+// NewChallengeService creates a new challenge service
+func NewChallengeService(
+	challengeRepo *repository.ChallengeRepository,
+	activityTypeRepo *repository.ActivityTypeRepository,
+	rewarder *ChallengeRewarder,
+	logger *logger.Logger,
+) *ChallengeService {
+	return &ChallengeService{
+		challengeRepo:    challengeRepo,
+		activityTypeRepo: activityTypeRepo,
+		rewarder:         rewarder,
+		logger:           logger,
+	}
+}
+
+// CreateChallengeRequest represents a request to create a challenge
+type CreateChallengeRequest struct {
+	Name          string    `json:"name" binding:"required,max=100"`
+	Description   string    `json:"description"`
+	StartDate     time.Time `json:"start_date" binding:"required"`
+	EndDate       time.Time `json:"end_date" binding:"required"`
+	TargetValue   float64   `json:"target_value" binding:"required,gt=0"`
+	TargetUnit    string    `json:"target_unit" binding:"required"` // activities, km, minutes or credits
+	ActivityType  string    `json:"activity_type"`                  // empty counts every activity type
+	RewardCredits float64   `json:"reward_credits" binding:"min=0"`
+}
+
+// ChallengeResponse represents a challenge in API responses
+type ChallengeResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	StartDate     time.Time `json:"start_date"`
+	EndDate       time.Time `json:"end_date"`
+	TargetValue   float64   `json:"target_value"`
+	TargetUnit    string    `json:"target_unit"`
+	ActivityType  string    `json:"activity_type,omitempty"`
+	RewardCredits float64   `json:"reward_credits"`
+	IsActive      bool      `json:"is_active"`
+}
+
+// ParticipantResponse represents a user's participation in a challenge
+type ParticipantResponse struct {
+	ChallengeID uuid.UUID  `json:"challenge_id"`
+	UserID      string     `json:"user_id"`
+	Progress    float64    `json:"progress"`
+	IsCompleted bool       `json:"is_completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	JoinedAt    time.Time  `json:"joined_at"`
+}
+
+// LeaderboardEntry is a participant's standing in a challenge
+type LeaderboardEntry struct {
+	Rank int `json:"rank"`
+	ParticipantResponse
+}
+
+// CreateChallenge creates a challenge (admin operation)
+func (s *ChallengeService) CreateChallenge(ctx context.Context, req *CreateChallengeRequest, createdBy string) (*ChallengeResponse, error) {
+	if err := validateChallenge(req); err != nil {
+		return nil, err
+	}
+
+	if req.ActivityType != "" {
+		if _, err := s.activityTypeRepo.GetByName(ctx, req.ActivityType); err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				return nil, fmt.Errorf("%w: activity type %q does not exist", ErrInvalidChallenge, req.ActivityType)
+			}
+			return nil, fmt.Errorf("failed to get activity type: %w", err)
+		}
+	}
+
+	challenge := &models.ActivityChallenge{
+		Name:          req.Name,
+		Description:   req.Description,
+		StartDate:     req.StartDate.UTC(),
+		EndDate:       req.EndDate.UTC(),
+		TargetValue:   req.TargetValue,
+		TargetUnit:    req.TargetUnit,
+		ActivityType:  req.ActivityType,
+		RewardCredits: req.RewardCredits,
+		IsActive:      true,
+	}
+	if err := s.challengeRepo.CreateChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	s.logger.LogInfo(ctx, "challenge created",
+		logger.String("challenge_id", challenge.ID.String()),
+		logger.String("created_by", createdBy))
+
+	return challengeToResponse(challenge), nil
+}
+
+// ListActiveChallenges retrieves the challenges users can still join,
+// soonest ending first
+func (s *ChallengeService) ListActiveChallenges(ctx context.Context, limit, offset int) ([]*ChallengeResponse, int64, error) {
+	challenges, total, err := s.challengeRepo.ListActive(ctx, time.Now().UTC(), limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*ChallengeResponse, len(challenges))
+	for i, challenge := range challenges {
+		responses[i] = challengeToResponse(challenge)
+	}
+
+	return responses, total, nil
+}
+
+// JoinChallenge adds the user to a challenge. Only activities logged after
+// joining count towards the user's progress.
+func (s *ChallengeService) JoinChallenge(ctx context.Context, challengeID uuid.UUID, userID string) (*ParticipantResponse, error) {
+	challenge, err := s.getChallenge(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := checkJoinable(challenge, now); err != nil {
+		return nil, err
+	}
+
+	joined, err := s.challengeRepo.IsParticipant(ctx, challengeID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if joined {
+		return nil, ErrAlreadyJoined
+	}
+
+	participant := &models.ChallengeParticipant{
+		ChallengeID: challengeID,
+		UserID:      userID,
+		JoinedAt:    now,
+	}
+	if err := s.challengeRepo.AddParticipant(ctx, participant); err != nil {
+		return nil, err
+	}
+
+	s.logger.LogInfo(ctx, "user joined challenge",
+		logger.String("challenge_id", challengeID.String()),
+		logger.String("user_id", userID))
+
+	return participantToResponse(participant), nil
+}
+
+// GetLeaderboard retrieves a challenge's participants ranked by progress
+func (s *ChallengeService) GetLeaderboard(ctx context.Context, challengeID uuid.UUID, limit, offset int) ([]*LeaderboardEntry, int64, error) {
+	if _, err := s.getChallenge(ctx, challengeID); err != nil {
+		return nil, 0, err
+	}
+
+	participants, total, err := s.challengeRepo.GetLeaderboard(ctx, challengeID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]*LeaderboardEntry, len(participants))
+	for i, participant := range participants {
+		entries[i] = &LeaderboardEntry{
+			Rank:                offset + i + 1,
+			ParticipantResponse: *participantToResponse(participant),
+		}
+	}
+
+	return entries, total, nil
+}
+
+// RecordActivity adds a credited activity to the user's progress in the
+// open challenges it counts towards, completing and rewarding those whose
+// target it meets. Failures are logged rather than returned so they never
+// fail logging the activity itself.
+func (s *ChallengeService) RecordActivity(ctx context.Context, activity *models.EcoActivity, activityType string) {
+	occurredAt := activity.CreatedAt
+	if activity.OccurredAt != nil {
+		occurredAt = *activity.OccurredAt
+	}
+
+	participations, err := s.challengeRepo.GetOpenParticipations(ctx, activity.UserID, occurredAt, activityType)
+	if err != nil {
+		s.logger.LogError(ctx, "failed to get challenge participations", err,
+			logger.String("activity_id", activity.ID.String()))
+		return
+	}
+
+	for _, participant := range participations {
+		// Activities from before the user joined do not count
+		if occurredAt.Before(participant.JoinedAt) {
+			continue
+		}
+
+		amount := challengeContribution(&participant.Challenge, activity)
+		if amount <= 0 {
+			continue
+		}
+
+		progress, err := s.challengeRepo.AddProgress(ctx, participant.ID, amount)
+		if err != nil {
+			s.logger.LogError(ctx, "failed to record challenge progress", err,
+				logger.String("participant_id", participant.ID.String()),
+				logger.String("activity_id", activity.ID.String()))
+			continue
+		}
+
+		if progress < participant.Challenge.TargetValue {
+			continue
+		}
+		s.completeChallenge(ctx, participant)
+	}
+}
+
+// completeChallenge marks a participant who met the target as completed
+// and awards the challenge's reward
+func (s *ChallengeService) completeChallenge(ctx context.Context, participant *models.ChallengeParticipant) {
+	completed, err := s.challengeRepo.MarkCompleted(ctx, participant.ID, time.Now().UTC())
+	if err != nil {
+		s.logger.LogError(ctx, "failed to complete challenge", err,
+			logger.String("participant_id", participant.ID.String()))
+		return
+	}
+	if completed {
+		s.logger.LogInfo(ctx, "challenge completed",
+			logger.String("challenge_id", participant.ChallengeID.String()),
+			logger.String("user_id", participant.UserID))
+	}
+
+	if participant.Challenge.RewardCredits <= 0 {
+		return
+	}
+	if _, err := s.rewarder.AwardReward(ctx, participant.ID); err != nil {
+		s.logger.LogError(ctx, "failed to award challenge reward", err,
+			logger.String("participant_id", participant.ID.String()))
+	}
+}
+
+// getChallenge retrieves a challenge, mapping a missing one to ErrChallengeNotFound
+func (s *ChallengeService) getChallenge(ctx context.Context, challengeID uuid.UUID) (*models.ActivityChallenge, error) {
+	challenge, err := s.challengeRepo.GetChallenge(ctx, challengeID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, ErrChallengeNotFound
+		}
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// validateChallenge checks a challenge's metric and window
+func validateChallenge(req *CreateChallengeRequest) error {
+	if !models.IsChallengeMetric(req.TargetUnit) {
+		return fmt.Errorf("%w: unknown target unit %q", ErrInvalidChallenge, req.TargetUnit)
+	}
+	if !req.EndDate.After(req.StartDate) {
+		return fmt.Errorf("%w: end_date must be after start_date", ErrInvalidChallenge)
+	}
+	return nil
+}
+
+// checkJoinable refuses joining a challenge that is inactive or has ended.
+// Challenges that have not started yet can be joined.
+func checkJoinable(challenge *models.ActivityChallenge, now time.Time) error {
+	if !challenge.IsActive {
+		return fmt.Errorf("%w: challenge is inactive", ErrChallengeClosed)
+	}
+	if now.After(challenge.EndDate) {
+		return fmt.Errorf("%w: challenge ended at %s", ErrChallengeClosed, challenge.EndDate.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// challengeContribution is how much an activity adds to progress in a
+// challenge, in the challenge's metric
+func challengeContribution(challenge *models.ActivityChallenge, activity *models.EcoActivity) float64 {
+	switch challenge.TargetUnit {
+	case models.ChallengeMetricActivities:
+		return 1
+	case models.ChallengeMetricDistance:
+		return activity.Distance
+	case models.ChallengeMetricDuration:
+		return float64(activity.Duration)
+	case models.ChallengeMetricCredits:
+		return activity.CreditsEarned
+	default:
+		return 0
+	}
+}
+
+// challengeToResponse converts a challenge model to response format
+func challengeToResponse(challenge *models.ActivityChallenge) *ChallengeResponse {
+	return &ChallengeResponse{
+		ID:            challenge.ID,
+		Name:          challenge.Name,
+		Description:   challenge.Description,
+		StartDate:     challenge.StartDate,
+		EndDate:       challenge.EndDate,
+		TargetValue:   challenge.TargetValue,
+		TargetUnit:    challenge.TargetUnit,
+		ActivityType:  challenge.ActivityType,
+		RewardCredits: challenge.RewardCredits,
+		IsActive:      challenge.IsActive,
+	}
+}
+
+// participantToResponse converts a participant model to response format
+func participantToResponse(participant *models.ChallengeParticipant) *ParticipantResponse {
+	return &ParticipantResponse{
+		ChallengeID: participant.ChallengeID,
+		UserID:      participant.UserID,
+		Progress:    participant.Progress,
+		IsCompleted: participant.IsCompleted,
+		CompletedAt: participant.CompletedAt,
+		JoinedAt:    participant.JoinedAt,
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// ErrInvalidCreditRule is returned for a credit rule with an empty value
+// range or a non-positive rate
+var ErrInvalidCreditRule = errors.New("invalid credit rule")
+
+// CreditRuleService manages the credit rules activities are credited by.
+// Rules are read on every activity log, so changes apply to the next one.
+type CreditRuleService struct {
+	creditRuleRepo   *repository.CreditRuleRepository
+	activityTypeRepo *repository.ActivityTypeRepository
+	logger           *logger.Logger
+}
+
+// NewCreditRuleService creates a new credit rule service
+func NewCreditRuleService(
+	creditRuleRepo *repository.CreditRuleRepository,
+	activityTypeRepo *repository.ActivityTypeRepository,
+	logger *logger.Logger,
+) *CreditRuleService {
+	return &CreditRuleService{
+		creditRuleRepo:   creditRuleRepo,
+		activityTypeRepo: activityTypeRepo,
+		logger:           logger,
+	}
+}
+
+// CreditRuleRequest represents a request to create or replace a credit rule.
+// The rule applies to activity values from MinValue to MaxValue inclusive; a
+// zero MaxValue leaves the range open-ended.
+type CreditRuleRequest struct {
+	Name           string     `json:"name" binding:"required,max=100"`
+	Description    string     `json:"description"`
+	MinValue       float64    `json:"min_value" binding:"min=0"`
+	MaxValue       float64    `json:"max_value" binding:"min=0"`
+	CreditsPerUnit float64    `json:"credits_per_unit" binding:"required"`
+	Multiplier     float64    `json:"multiplier"` // defaults to 1
+	ValidFrom      *time.Time `json:"valid_from"` // defaults to now
+	ValidTo        *time.Time `json:"valid_to"`
+}
+
+// CreateRule adds a credit rule to an activity type (admin operation)
+func (s *CreditRuleService) CreateRule(ctx context.Context, activityTypeID uuid.UUID, req *CreditRuleRequest, actorID string) (*models.CreditRule, error) {
+	if _, err := s.activityTypeRepo.GetByID(ctx, activityTypeID); err != nil {
+		return nil, fmt.Errorf("failed to get activity type: %w", err)
+	}
+
+	rule := &models.CreditRule{
+		ActivityTypeID: activityTypeID,
+		IsActive:       true,
+	}
+	applyCreditRuleRequest(rule, req, time.Now().UTC())
+	if err := validateCreditRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.creditRuleRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	s.logger.LogInfo(ctx, "credit rule created",
+		logger.String("credit_rule_id", rule.ID.String()),
+		logger.String("activity_type_id", activityTypeID.String()),
+		logger.String("actor_id", actorID))
+
+	return rule, nil
+}
+
+// ListRules retrieves an activity type's credit rules, including deactivated
+// ones if includeInactive is set
+func (s *CreditRuleService) ListRules(ctx context.Context, activityTypeID uuid.UUID, includeInactive bool) ([]*models.CreditRule, error) {
+	if _, err := s.activityTypeRepo.GetByID(ctx, activityTypeID); err != nil {
+		return nil, fmt.Errorf("failed to get activity type: %w", err)
+	}
+
+	return s.creditRuleRepo.GetByActivityType(ctx, activityTypeID, includeInactive)
+}
+
+// UpdateRule replaces a credit rule's range and rates (admin operation)
+func (s *CreditRuleService) UpdateRule(ctx context.Context, id uuid.UUID, req *CreditRuleRequest, actorID string) (*models.CreditRule, error) {
+	rule, err := s.creditRuleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit rule: %w", err)
+	}
+
+	applyCreditRuleRequest(rule, req, rule.ValidFrom)
+	if err := validateCreditRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.creditRuleRepo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	s.logger.LogInfo(ctx, "credit rule updated",
+		logger.String("credit_rule_id", id.String()),
+		logger.String("actor_id", actorID))
+
+	return rule, nil
+}
+
+// DeactivateRule stops a credit rule from applying to new activities.
+// Activities already credited by it keep their credits.
+func (s *CreditRuleService) DeactivateRule(ctx context.Context, id uuid.UUID, actorID string) error {
+	rule, err := s.creditRuleRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get credit rule: %w", err)
+	}
+
+	if !rule.IsActive {
+		return nil
+	}
+
+	rule.IsActive = false
+	if err := s.creditRuleRepo.Update(ctx, rule); err != nil {
+		return err
+	}
+
+	s.logger.LogInfo(ctx, "credit rule deactivated",
+		logger.String("credit_rule_id", id.String()),
+		logger.String("actor_id", actorID))
+
+	return nil
+}
+
+// applyCreditRuleRequest copies req onto rule. An omitted multiplier is 1
+// and an omitted start is validFrom.
+func applyCreditRuleRequest(rule *models.CreditRule, req *CreditRuleRequest, validFrom time.Time) {
+	rule.Name = req.Name
+	rule.Description = req.Description
+	rule.MinValue = req.MinValue
+	rule.MaxValue = req.MaxValue
+	rule.CreditsPerUnit = req.CreditsPerUnit
+	rule.Multiplier = req.Multiplier
+	if rule.Multiplier == 0 {
+		rule.Multiplier = 1
+	}
+
+	rule.ValidFrom = validFrom
+	if req.ValidFrom != nil {
+		rule.ValidFrom = req.ValidFrom.UTC()
+	}
+	rule.ValidTo = nil
+	if req.ValidTo != nil {
+		validTo := req.ValidTo.UTC()
+		rule.ValidTo = &validTo
+	}
+}
+
+// validateCreditRule checks a rule's value range, rates and validity period
+func validateCreditRule(rule *models.CreditRule) error {
+	if rule.MaxValue != 0 && rule.MinValue >= rule.MaxValue {
+		return fmt.Errorf("%w: min_value %v must be below max_value %v", ErrInvalidCreditRule, rule.MinValue, rule.MaxValue)
+	}
+	if rule.CreditsPerUnit <= 0 {
+		return fmt.Errorf("%w: credits_per_unit must be positive", ErrInvalidCreditRule)
+	}
+	if rule.Multiplier <= 0 {
+		return fmt.Errorf("%w: multiplier must be positive", ErrInvalidCreditRule)
+	}
+	if rule.ValidTo != nil && !rule.ValidTo.After(rule.ValidFrom) {
+		return fmt.Errorf("%w: valid_to must be after valid_from", ErrInvalidCreditRule)
+	}
+	return nil
+}
+
+// bestCreditRule returns the rule with the highest rate among those whose
+// range contains value, or nil if none does. Ranges may overlap; the most
+// generous rule wins.
+func bestCreditRule(rules []*models.CreditRule, value float64) *models.CreditRule {
+	var best *models.CreditRule
+	for _, rule := range rules {
+		if value >= rule.MinValue && (rule.MaxValue == 0 || value <= rule.MaxValue) {
+			if best == nil || rule.CreditsPerUnit > best.CreditsPerUnit {
+				best = rule
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// ErrDeviceUnauthorized is returned when an IoT request carries an API key
+// that is unknown or belongs to a deactivated device
+var ErrDeviceUnauthorized = errors.New("device not authorized")
+
+// ErrDeviceExists is returned when registering a hardware device ID that is
+// already registered
+var ErrDeviceExists = errors.New("device already registered")
+
+// ErrInvalidDeviceType is returned when registering a device of an unknown type
+var ErrInvalidDeviceType = errors.New("invalid device type")
+
+// ErrDeviceNotFound is returned when a device does not exist or belongs to
+// another user
+var ErrDeviceNotFound = errors.New("device not found")
+
+// DeviceService manages users' IoT devices and turns the telemetry they
+// report into eco-activities
+type DeviceService struct {
+	deviceRepo     *repository.IoTDeviceRepository
+	trackerService *TrackerService
+	logger         *logger.Logger
+}
+
+// NewDeviceService creates a new device service
+func NewDeviceService(deviceRepo *repository.IoTDeviceRepository, trackerService *TrackerService, logger *logger.Logger) *DeviceService {
+	return &DeviceService{
+		deviceRepo:     deviceRepo,
+		trackerService: trackerService,
+		logger:         logger,
+	}
+}
+
+// RegisterDeviceRequest represents a request to register an IoT device
+type RegisterDeviceRequest struct {
+	DeviceID string `json:"device_id" binding:"required,max=100"`
+	Name     string `json:"name" binding:"required,max=100"`
+	Type     string `json:"type" binding:"required"`
+}
+
+// DeviceResponse represents an IoT device in API responses
+type DeviceResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	DeviceID  string     `json:"device_id"`
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	IsActive  bool       `json:"is_active"`
+	LastSeen  *time.Time `json:"last_seen"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RegisteredDeviceResponse is returned once, at registration, and is the
+// only time the device's API key is shown
+type RegisteredDeviceResponse struct {
+	DeviceResponse
+	APIKey string `json:"api_key"`
+}
+
+// IoTDataRequest represents telemetry reported by a device, such as the kWh
+// a solar panel generated or the km a bike sensor recorded
+type IoTDataRequest struct {
+	ActivityType string                 `json:"activity_type" binding:"required"`
+	Description  string                 `json:"description"`
+	Duration     int                    `json:"duration"` // in minutes
+	Distance     float64                `json:"distance"` // in kilometers
+	Quantity     float64                `json:"quantity"`
+	Unit         string                 `json:"unit"`
+	OccurredAt   *time.Time             `json:"occurred_at"`
+	Data         map[string]interface{} `json:"data"` // raw readings, stored with the activity
+}
+
+// RegisterDevice registers a device for the user and issues its API key
+func (s *DeviceService) RegisterDevice(ctx context.Context, userID string, req *RegisterDeviceRequest) (*RegisteredDeviceResponse, error) {
+	if !models.IsDeviceType(req.Type) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidDeviceType, req.Type)
+	}
+
+	exists, err := s.deviceRepo.DeviceIDExists(ctx, req.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceExists, req.DeviceID)
+	}
+
+	apiKey, err := generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	device := &models.IoTDevice{
+		UserID:   userID,
+		DeviceID: req.DeviceID,
+		Name:     req.Name,
+		Type:     req.Type,
+		IsActive: true,
+		APIKey:   apiKey,
+		Settings: "{}",
+	}
+	if err := s.deviceRepo.Create(ctx, device); err != nil {
+		return nil, err
+	}
+
+	s.logger.LogInfo(ctx, "IoT device registered",
+		logger.String("user_id", userID),
+		logger.String("device_id", device.ID.String()),
+		logger.String("type", device.Type))
+
+	return &RegisteredDeviceResponse{
+		DeviceResponse: *deviceToResponse(device),
+		APIKey:         apiKey,
+	}, nil
+}
+
+// ListDevices retrieves the user's devices, newest first
+func (s *DeviceService) ListDevices(ctx context.Context, userID string) ([]*DeviceResponse, error) {
+	devices, err := s.deviceRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*DeviceResponse, len(devices))
+	for i, device := range devices {
+		responses[i] = deviceToResponse(device)
+	}
+
+	return responses, nil
+}
+
+// DeactivateDevice stops one of the user's devices from reporting
+func (s *DeviceService) DeactivateDevice(ctx context.Context, userID string, deviceID uuid.UUID) error {
+	if err := s.deviceRepo.Deactivate(ctx, deviceID, userID); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return ErrDeviceNotFound
+		}
+		return err
+	}
+
+	s.logger.LogInfo(ctx, "IoT device deactivated",
+		logger.String("user_id", userID),
+		logger.String("device_id", deviceID.String()))
+
+	return nil
+}
+
+// LogDeviceActivity authenticates a device by its API key and logs the
+// telemetry it reported as an activity of the device's owner
+func (s *DeviceService) LogDeviceActivity(ctx context.Context, apiKey string, req *IoTDataRequest) (*ActivityResponse, error) {
+	device, err := s.deviceRepo.GetByAPIKey(ctx, apiKey)
+	if err := authenticateDevice(device, err); err != nil {
+		return nil, err
+	}
+
+	// A failed timestamp update must not lose the reading
+	if err := s.deviceRepo.UpdateLastSeen(ctx, device.ID, time.Now().UTC()); err != nil {
+		s.logger.LogError(ctx, "failed to update device last seen", err,
+			logger.String("device_id", device.ID.String()))
+	}
+
+	return s.trackerService.LogActivity(ctx, deviceActivityRequest(device, req))
+}
+
+// authenticateDevice checks the result of looking a device up by API key
+func authenticateDevice(device *models.IoTDevice, err error) error {
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return fmt.Errorf("%w: unknown API key", ErrDeviceUnauthorized)
+		}
+		return err
+	}
+	if !device.IsActive {
+		return fmt.Errorf("%w: device %s is deactivated", ErrDeviceUnauthorized, device.ID)
+	}
+	return nil
+}
+
+// deviceActivityRequest builds the activity a device's reading is logged as
+func deviceActivityRequest(device *models.IoTDevice, req *IoTDataRequest) *LogActivityRequest {
+	description := req.Description
+	if description == "" {
+		description = fmt.Sprintf("Reported by %s", device.Name)
+	}
+
+	sourceData := map[string]interface{}{
+		"device_id":   device.DeviceID,
+		"device_type": device.Type,
+	}
+	if len(req.Data) > 0 {
+		sourceData["data"] = req.Data
+	}
+
+	return &LogActivityRequest{
+		UserID:       device.UserID,
+		ActivityType: req.ActivityType,
+		Description:  description,
+		Duration:     req.Duration,
+		Distance:     req.Distance,
+		Quantity:     req.Quantity,
+		Unit:         req.Unit,
+		Source:       models.SourceIoT,
+		SourceData:   sourceData,
+		OccurredAt:   req.OccurredAt,
+	}
+}
+
+// deviceToResponse converts a device model to response format
+func deviceToResponse(device *models.IoTDevice) *DeviceResponse {
+	return &DeviceResponse{
+		ID:        device.ID,
+		DeviceID:  device.DeviceID,
+		Name:      device.Name,
+		Type:      device.Type,
+		IsActive:  device.IsActive,
+		LastSeen:  device.LastSeen,
+		CreatedAt: device.CreatedAt,
+	}
+}
+
+// generateAPIKey generates a random device API key
+func generateAPIKey() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/sloweyyy/GreenLedger/shared/events"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/segmentio/kafka-go"
 )
@@ -85,6 +86,128 @@ func (p *KafkaEventPublisher) PublishCreditEarned(ctx context.Context, event *Cr
 	return nil
 }
 
+// EventTypeCreditRevoked is the type of credit revoked events, including
+// those written to the outbox
+const EventTypeCreditRevoked = "credit_revoked"
+
+// PublishCreditRevokedFromOutbox returns an OutboxPublishFunc that publishes
+// credit revoked outbox entries with publisher
+func PublishCreditRevokedFromOutbox(publisher EventPublisher) events.OutboxPublishFunc {
+	return func(ctx context.Context, entry *events.OutboxEvent) error {
+		var event CreditRevokedEvent
+		if err := json.Unmarshal([]byte(entry.Payload), &event); err != nil {
+			return fmt.Errorf("failed to unmarshal credit revoked event: %w", err)
+		}
+		return publisher.PublishCreditRevoked(ctx, &event)
+	}
+}
+
+// PublishCreditRevoked publishes a credit revoked event
+func (p *KafkaEventPublisher) PublishCreditRevoked(ctx context.Context, event *CreditRevokedEvent) error {
+	// Add event metadata
+	eventWithMetadata := struct {
+		*CreditRevokedEvent
+		EventType string    `json:"event_type"`
+		EventID   string    `json:"event_id"`
+		Source    string    `json:"source"`
+		Version   string    `json:"version"`
+		Timestamp time.Time `json:"timestamp"`
+	}{
+		CreditRevokedEvent: event,
+		EventType:          EventTypeCreditRevoked,
+		EventID:            fmt.Sprintf("revoke_%s", event.ActivityID),
+		Source:             "tracker-service",
+		Version:            "1.0",
+		Timestamp:          event.Timestamp,
+	}
+
+	// Serialize event
+	eventData, err := json.Marshal(eventWithMetadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	// Create Kafka message
+	message := kafka.Message{
+		Key:   []byte(event.UserID),
+		Value: eventData,
+		Headers: []kafka.Header{
+			{Key: "event-type", Value: []byte(EventTypeCreditRevoked)},
+			{Key: "user-id", Value: []byte(event.UserID)},
+			{Key: "source", Value: []byte("tracker-service")},
+		},
+	}
+
+	// Publish message
+	err = p.writer.WriteMessages(ctx, message)
+	if err != nil {
+		p.logger.LogError(ctx, "failed to publish credit revoked event", err,
+			logger.String("user_id", event.UserID),
+			logger.String("activity_id", event.ActivityID))
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	p.logger.LogInfo(ctx, "credit revoked event published",
+		logger.String("user_id", event.UserID),
+		logger.String("activity_id", event.ActivityID),
+		logger.Float64("credits", event.CreditsRevoked))
+
+	return nil
+}
+
+// PublishCreditAdjusted publishes a credit adjusted event
+func (p *KafkaEventPublisher) PublishCreditAdjusted(ctx context.Context, event *CreditAdjustedEvent) error {
+	// Add event metadata
+	eventWithMetadata := struct {
+		*CreditAdjustedEvent
+		EventType string    `json:"event_type"`
+		EventID   string    `json:"event_id"`
+		Source    string    `json:"source"`
+		Version   string    `json:"version"`
+		Timestamp time.Time `json:"timestamp"`
+	}{
+		CreditAdjustedEvent: event,
+		EventType:           "credit_adjusted",
+		EventID:             fmt.Sprintf("adjust_%s", event.AdjustmentID),
+		Source:              "tracker-service",
+		Version:             "1.0",
+		Timestamp:           event.Timestamp,
+	}
+
+	// Serialize event
+	eventData, err := json.Marshal(eventWithMetadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	// Create Kafka message
+	message := kafka.Message{
+		Key:   []byte(event.UserID),
+		Value: eventData,
+		Headers: []kafka.Header{
+			{Key: "event-type", Value: []byte("credit_adjusted")},
+			{Key: "user-id", Value: []byte(event.UserID)},
+			{Key: "source", Value: []byte("tracker-service")},
+		},
+	}
+
+	// Publish message
+	err = p.writer.WriteMessages(ctx, message)
+	if err != nil {
+		p.logger.LogError(ctx, "failed to publish credit adjusted event", err,
+			logger.String("user_id", event.UserID),
+			logger.String("activity_id", event.ActivityID))
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	p.logger.LogInfo(ctx, "credit adjusted event published",
+		logger.String("user_id", event.UserID),
+		logger.String("activity_id", event.ActivityID),
+		logger.Float64("credits_delta", event.CreditsDelta))
+
+	return nil
+}
+
 // Close closes the event publisher
 func (p *KafkaEventPublisher) Close() error {
 	return p.writer.Close()
@@ -113,6 +236,24 @@ func (p *MockEventPublisher) PublishCreditEarned(ctx context.Context, event *Cre
 	return nil
 }
 
+// PublishCreditRevoked publishes a credit revoked event (mock)
+func (p *MockEventPublisher) PublishCreditRevoked(ctx context.Context, event *CreditRevokedEvent) error {
+	p.Events = append(p.Events, event)
+	p.logger.LogInfo(ctx, "mock: credit revoked event published",
+		logger.String("user_id", event.UserID),
+		logger.String("activity_id", event.ActivityID))
+	return nil
+}
+
+// PublishCreditAdjusted publishes a credit adjusted event (mock)
+func (p *MockEventPublisher) PublishCreditAdjusted(ctx context.Context, event *CreditAdjustedEvent) error {
+	p.Events = append(p.Events, event)
+	p.logger.LogInfo(ctx, "mock: credit adjusted event published",
+		logger.String("user_id", event.UserID),
+		logger.String("activity_id", event.ActivityID))
+	return nil
+}
+
 // GetEvents returns all published events
 func (p *MockEventPublisher) GetEvents() []interface{} {
 	return p.Events
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+)
+
+// ErrInvalidLeaderboard is returned for an unknown leaderboard period or metric
+var ErrInvalidLeaderboard = errors.New("invalid leaderboard")
+
+// Leaderboard periods, the rolling windows users are ranked over
+const (
+	LeaderboardDaily   = "daily"
+	LeaderboardWeekly  = "weekly"
+	LeaderboardMonthly = "monthly"
+	LeaderboardAllTime = "all_time"
+)
+
+// leaderboardWindows are the lengths of the rolling leaderboard periods
+var leaderboardWindows = map[string]time.Duration{
+	LeaderboardDaily:   24 * time.Hour,
+	LeaderboardWeekly:  7 * 24 * time.Hour,
+	LeaderboardMonthly: 30 * 24 * time.Hour,
+}
+
+// GetLeaderboard ranks users by the total of metric (credits or distance)
+// over their verified activities in the rolling period, highest first
+func (s *TrackerService) GetLeaderboard(ctx context.Context, period, metric string, limit int) ([]*models.UserRanking, error) {
+	since, err := leaderboardSince(period, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if metric != models.LeaderboardMetricCredits && metric != models.LeaderboardMetricDistance {
+		return nil, fmt.Errorf("%w: unknown metric %q", ErrInvalidLeaderboard, metric)
+	}
+
+	rankings, err := s.activityRepo.GetLeaderboard(ctx, metric, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	rankUsers(rankings)
+	return rankings, nil
+}
+
+// leaderboardSince returns the start of a rolling leaderboard period ending
+// at now, or nil for all time
+func leaderboardSince(period string, now time.Time) (*time.Time, error) {
+	if period == LeaderboardAllTime {
+		return nil, nil
+	}
+
+	window, ok := leaderboardWindows[period]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown period %q", ErrInvalidLeaderboard, period)
+	}
+
+	since := now.Add(-window)
+	return &since, nil
+}
+
+// rankUsers assigns ranks to rankings sorted highest total first. Users
+// with equal totals share a rank and the next rank skips past them.
+func rankUsers(rankings []*models.UserRanking) {
+	for i, ranking := range rankings {
+		if i > 0 && ranking.Total == rankings[i-1].Total {
+			ranking.Rank = rankings[i-1].Rank
+			continue
+		}
+		ranking.Rank = i + 1
+	}
+}
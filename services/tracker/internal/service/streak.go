@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// StreakBonusSource is the wallet credit source of streak milestone bonuses
+const StreakBonusSource = "streak_bonus"
+
+// ErrInvalidTimezone is returned for a timezone that is not an IANA name
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// StreakMilestone is a streak length that earns a one-off bonus
+type StreakMilestone struct {
+	Days    int
+	Credits float64
+}
+
+// StreakMilestones are the streak lengths that earn a bonus, shortest first
+var StreakMilestones = []StreakMilestone{
+	{Days: 7, Credits: 5},
+	{Days: 30, Credits: 25},
+	{Days: 100, Credits: 100},
+}
+
+// StreakResponse reports a user's run of consecutive days with activities
+type StreakResponse struct {
+	UserID        string `json:"user_id"`
+	CurrentStreak int    `json:"current_streak"` // in days, including today or yesterday
+	LongestStreak int    `json:"longest_streak"`
+	// LastActiveDate is the last day with an activity, in Timezone
+	LastActiveDate string `json:"last_active_date,omitempty"`
+	Timezone       string `json:"timezone"`
+	// BonusesAwarded lists the milestones this request awarded bonuses for
+	BonusesAwarded []int `json:"bonuses_awarded,omitempty"`
+}
+
+// streak is a user's streak computed from their activity times
+type streak struct {
+	current int
+	longest int
+	// startedAt is the first activity of the current streak
+	startedAt time.Time
+	// lastActive is the last day with an activity, at midnight in the
+	// user's timezone
+	lastActive time.Time
+}
+
+// GetUserStreak computes the user's current and longest streaks of days
+// with at least one activity. Days are calendar days in timezone, an IANA
+// name such as Europe/Paris; empty means UTC. Streak milestones the current
+// streak has reached are awarded a bonus once per streak.
+func (s *TrackerService) GetUserStreak(ctx context.Context, userID, timezone string) (*StreakResponse, error) {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimezone, timezone)
+	}
+
+	times, err := s.activityRepo.GetActivityTimes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	st := computeStreak(times, time.Now(), loc)
+	response := &StreakResponse{
+		UserID:        userID,
+		CurrentStreak: st.current,
+		LongestStreak: st.longest,
+		Timezone:      loc.String(),
+	}
+	if !st.lastActive.IsZero() {
+		response.LastActiveDate = st.lastActive.Format("2006-01-02")
+	}
+
+	for _, milestone := range reachedMilestones(st.current) {
+		awarded, err := s.awardStreakBonus(ctx, userID, milestone, st.startedAt)
+		if err != nil {
+			// The bonus is retried the next time the streak is checked
+			s.logger.LogError(ctx, "failed to award streak bonus", err,
+				logger.String("user_id", userID),
+				logger.Int("milestone", milestone.Days))
+			continue
+		}
+		if awarded {
+			response.BonusesAwarded = append(response.BonusesAwarded, milestone.Days)
+		}
+	}
+
+	return response, nil
+}
+
+// awardStreakBonus publishes a milestone bonus unless it was already awarded
+// for the streak that started at startedAt, and reports whether it did
+func (s *TrackerService) awardStreakBonus(ctx context.Context, userID string, milestone StreakMilestone, startedAt time.Time) (bool, error) {
+	now := time.Now().UTC()
+	reward := &models.StreakReward{
+		UserID:          userID,
+		Milestone:       milestone.Days,
+		StreakStartedAt: startedAt.UTC(),
+		Credits:         s.rounding.RoundFloat(milestone.Credits),
+		AwardedAt:       now,
+	}
+
+	recorded, err := s.streakRewardRepo.Record(ctx, reward)
+	if err != nil {
+		return false, err
+	}
+	if !recorded {
+		return false, nil
+	}
+
+	if err := s.eventPublisher.PublishCreditEarned(ctx, streakBonusEvent(reward)); err != nil {
+		// Forget the reward so the bonus can be retried
+		if deleteErr := s.streakRewardRepo.Delete(ctx, userID, reward.Milestone, reward.StreakStartedAt); deleteErr != nil {
+			s.logger.LogError(ctx, "failed to release streak reward", deleteErr,
+				logger.String("user_id", userID),
+				logger.Int("milestone", reward.Milestone))
+		}
+		return false, fmt.Errorf("failed to publish streak bonus: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "streak bonus awarded",
+		logger.String("user_id", userID),
+		logger.Int("milestone", reward.Milestone),
+		logger.Float64("credits", reward.Credits))
+
+	return true, nil
+}
+
+// streakBonusEvent builds the credit earned event for a streak reward. Its
+// reference identifies the milestone of one streak, so the wallet credits it
+// at most once.
+func streakBonusEvent(reward *models.StreakReward) *CreditEarnedEvent {
+	return &CreditEarnedEvent{
+		UserID:        reward.UserID,
+		ActivityID:    fmt.Sprintf("streak:%s:%d:%d", reward.UserID, reward.Milestone, reward.StreakStartedAt.Unix()),
+		ActivityType:  "streak",
+		CreditsEarned: reward.Credits,
+		Description:   fmt.Sprintf("Reached a %d-day activity streak", reward.Milestone),
+		Timestamp:     reward.AwardedAt,
+		Source:        StreakBonusSource,
+	}
+}
+
+// reachedMilestones returns the milestones a streak of the given length has
+// reached
+func reachedMilestones(current int) []StreakMilestone {
+	var reached []StreakMilestone
+	for _, milestone := range StreakMilestones {
+		if current >= milestone.Days {
+			reached = append(reached, milestone)
+		}
+	}
+	return reached
+}
+
+// computeStreak finds the runs of consecutive calendar days in loc with at
+// least one activity, given activity times in ascending order. The latest
+// run is the current streak while its last day is today or yesterday, so a
+// streak is not broken before the user has had a chance to log today.
+func computeStreak(times []time.Time, now time.Time, loc *time.Location) streak {
+	var st streak
+	var run int
+	var runStartedAt, lastDay time.Time
+
+	for _, t := range times {
+		day := localDay(t, loc)
+		switch {
+		case run > 0 && day.Equal(lastDay):
+			continue
+		case run > 0 && day.Equal(lastDay.AddDate(0, 0, 1)):
+			run++
+		default:
+			run = 1
+			runStartedAt = t
+		}
+		lastDay = day
+
+		if run > st.longest {
+			st.longest = run
+		}
+	}
+
+	if run == 0 {
+		return st
+	}
+
+	st.lastActive = lastDay
+	today := localDay(now, loc)
+	if !lastDay.Before(today.AddDate(0, 0, -1)) {
+		st.current = run
+		st.startedAt = runStartedAt
+	}
+
+	return st
+}
+
+// localDay returns midnight of the calendar day t falls on in loc
+func localDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
@@ -3,37 +3,112 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
 	"github.com/sloweyyy/GreenLedger/services/tracker/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/credits"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/events"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
 )
 
+// ErrActivityTypeInUse is returned when deleting an activity type that
+// still has activities logged against it
+var ErrActivityTypeInUse = errors.New("activity type has logged activities")
+
+// ErrSourceNotAllowed is returned when a user submits an activity with a
+// source reserved for devices or webhooks
+var ErrSourceNotAllowed = errors.New("activity source not allowed")
+
+// ErrInvalidSourceData is returned when an activity's source data cannot be stored
+var ErrInvalidSourceData = errors.New("invalid source data")
+
+// ErrInvalidOccurredAt is returned when an activity claims to have occurred in the future
+var ErrInvalidOccurredAt = errors.New("invalid occurred_at")
+
+// ErrActivityTypeUnavailable is returned when logging an activity against an
+// activity type that does not exist or has been deactivated
+var ErrActivityTypeUnavailable = errors.New("activity type unavailable")
+
+// ErrActivityAlreadyVerified is returned when verifying an activity twice
+var ErrActivityAlreadyVerified = errors.New("activity is already verified")
+
+// ErrActivityRejected is returned when verifying or rejecting an activity a
+// moderator has already rejected
+var ErrActivityRejected = errors.New("activity is rejected")
+
+// occurredAtClockSkew tolerates clients whose clocks run slightly ahead
+const occurredAtClockSkew = time.Minute
+
+// MaxSourceDataBytes bounds the encoded source data stored with an activity
+const MaxSourceDataBytes = 64 << 10
+
+// DefaultUserActivitySources are the sources users may set when none are configured
+var DefaultUserActivitySources = []string{models.SourceManual}
+
+// metricsServiceName labels the business metrics recorded by the tracker
+const metricsServiceName = "tracker"
+
 // TrackerService handles eco-activity tracking operations
 type TrackerService struct {
 	activityRepo     *repository.ActivityRepository
 	activityTypeRepo *repository.ActivityTypeRepository
 	creditRuleRepo   *repository.CreditRuleRepository
+	streakRewardRepo *repository.StreakRewardRepository
 	eventPublisher   EventPublisher
+	userSources      map[string]bool
+	maxBackdating    time.Duration
+	rounding         credits.RoundingPolicy
+	metrics          *monitoring.Metrics
+	challenges       *ChallengeService
 	logger           *logger.Logger
 }
 
-// NewTrackerService creates a new tracker service
+// NewTrackerService creates a new tracker service. metrics and challenges
+// may be nil, a zero maxBackdating allows activities of any age to earn
+// credits and a zero rounding policy falls back to
+// credits.DefaultRoundingPolicy.
 func NewTrackerService(
 	activityRepo *repository.ActivityRepository,
 	activityTypeRepo *repository.ActivityTypeRepository,
 	creditRuleRepo *repository.CreditRuleRepository,
+	streakRewardRepo *repository.StreakRewardRepository,
 	eventPublisher EventPublisher,
+	userSources []string,
+	maxBackdating time.Duration,
+	rounding credits.RoundingPolicy,
+	metrics *monitoring.Metrics,
+	challenges *ChallengeService,
 	logger *logger.Logger,
 ) *TrackerService {
+	if len(userSources) == 0 {
+		userSources = DefaultUserActivitySources
+	}
+	if rounding.Mode == "" {
+		rounding = credits.DefaultRoundingPolicy
+	}
+
+	allowed := make(map[string]bool, len(userSources))
+	for _, source := range userSources {
+		allowed[source] = true
+	}
+
 	return &TrackerService{
 		activityRepo:     activityRepo,
 		activityTypeRepo: activityTypeRepo,
 		creditRuleRepo:   creditRuleRepo,
+		streakRewardRepo: streakRewardRepo,
 		eventPublisher:   eventPublisher,
+		userSources:      allowed,
+		maxBackdating:    maxBackdating,
+		rounding:         rounding,
+		metrics:          metrics,
+		challenges:       challenges,
 		logger:           logger,
 	}
 }
@@ -50,6 +125,7 @@ type LogActivityRequest struct {
 	Location     string                 `json:"location"`
 	Source       string                 `json:"source"`
 	SourceData   map[string]interface{} `json:"source_data"`
+	OccurredAt   *time.Time             `json:"occurred_at"` // defaults to when the activity is logged
 }
 
 // ActivityResponse represents an activity in API responses
@@ -65,8 +141,27 @@ type ActivityResponse struct {
 	Location      string    `json:"location"`
 	CreditsEarned float64   `json:"credits_earned"`
 	IsVerified    bool      `json:"is_verified"`
+	VerifiedBy    string    `json:"verified_by,omitempty"`
 	Source        string    `json:"source"`
+	OccurredAt    time.Time `json:"occurred_at"`
 	CreatedAt     time.Time `json:"created_at"`
+
+	// OutsideCreditWindow is set when the activity was logged too long
+	// after it occurred and so earned no credits
+	OutsideCreditWindow bool `json:"outside_credit_window"`
+
+	// VerificationStatus is pending, approved or rejected. RejectionReason
+	// is the moderator's reason for a rejection.
+	VerificationStatus string `json:"verification_status"`
+	RejectionReason    string `json:"rejection_reason,omitempty"`
+
+	// SourceData is the payload submitted with the activity, included only
+	// when explicitly requested
+	SourceData map[string]interface{} `json:"source_data,omitempty"`
+
+	// ActivityTypeDeactivated marks historical activities whose type no
+	// longer accepts new logs
+	ActivityTypeDeactivated bool `json:"activity_type_deactivated"`
 }
 
 // CreditEarnedEvent represents an event when credits are earned
@@ -77,11 +172,59 @@ type CreditEarnedEvent struct {
 	CreditsEarned float64   `json:"credits_earned"`
 	Description   string    `json:"description"`
 	Timestamp     time.Time `json:"timestamp"`
+
+	// Source is the wallet credit source; empty means an eco activity
+	Source string `json:"source,omitempty"`
+}
+
+// CreditRevokedEvent asks the wallet to reverse the credit earned by a deleted
+// activity. An activity's credit is revoked at most once, so ActivityID also
+// identifies the revocation and redeliveries are applied once.
+type CreditRevokedEvent struct {
+	UserID         string    `json:"user_id"`
+	ActivityID     string    `json:"activity_id"`
+	ActivityType   string    `json:"activity_type"`
+	CreditsRevoked float64   `json:"credits_revoked"`
+	Reason         string    `json:"reason"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// CreditAdjustedEvent asks the wallet to correct the credit earned by an
+// edited activity by CreditsDelta, which is negative when credits are taken
+// back. AdjustmentID identifies one edit so redeliveries apply it once.
+type CreditAdjustedEvent struct {
+	UserID       string    `json:"user_id"`
+	ActivityID   string    `json:"activity_id"`
+	ActivityType string    `json:"activity_type"`
+	AdjustmentID string    `json:"adjustment_id"`
+	CreditsDelta float64   `json:"credits_delta"`
+	Reason       string    `json:"reason"`
+	Timestamp    time.Time `json:"timestamp"`
 }
 
 // EventPublisher interface for publishing events
 type EventPublisher interface {
 	PublishCreditEarned(ctx context.Context, event *CreditEarnedEvent) error
+	PublishCreditRevoked(ctx context.Context, event *CreditRevokedEvent) error
+	PublishCreditAdjusted(ctx context.Context, event *CreditAdjustedEvent) error
+}
+
+// LogUserActivity logs an activity submitted by a user through the
+// authenticated API. Only the configured user sources are accepted so a
+// manual submission cannot claim to come from a device or webhook.
+func (s *TrackerService) LogUserActivity(ctx context.Context, req *LogActivityRequest) (*ActivityResponse, error) {
+	if req.Source == "" {
+		req.Source = models.SourceManual
+	}
+
+	if !s.userSources[req.Source] {
+		s.logger.LogWarn(ctx, "rejected activity with reserved source",
+			logger.String("user_id", req.UserID),
+			logger.String("source", req.Source))
+		return nil, fmt.Errorf("%w: %q", ErrSourceNotAllowed, req.Source)
+	}
+
+	return s.LogActivity(ctx, req)
 }
 
 // LogActivity logs a new eco-friendly activity
@@ -93,11 +236,23 @@ func (s *TrackerService) LogActivity(ctx context.Context, req *LogActivityReques
 	// Get activity type
 	activityType, err := s.activityTypeRepo.GetByName(ctx, req.ActivityType)
 	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %q does not exist", ErrActivityTypeUnavailable, req.ActivityType)
+		}
 		return nil, fmt.Errorf("failed to get activity type: %w", err)
 	}
 
 	if !activityType.IsActive {
-		return nil, fmt.Errorf("activity type is not active")
+		return nil, fmt.Errorf("%w: %q is not active", ErrActivityTypeUnavailable, req.ActivityType)
+	}
+
+	now := time.Now().UTC()
+	occurredAt := now
+	if req.OccurredAt != nil {
+		if req.OccurredAt.After(now.Add(occurredAtClockSkew)) {
+			return nil, fmt.Errorf("%w: %s is in the future", ErrInvalidOccurredAt, req.OccurredAt.Format(time.RFC3339))
+		}
+		occurredAt = req.OccurredAt.UTC()
 	}
 
 	// Calculate credits earned
@@ -105,15 +260,23 @@ func (s *TrackerService) LogActivity(ctx context.Context, req *LogActivityReques
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate credits: %w", err)
 	}
+	// Round with the policy the wallet uses so both record the same amount
+	creditsEarned = s.rounding.RoundFloat(creditsEarned)
+
+	// Activities logged too long after they happened are kept but earn nothing
+	outsideWindow := outsideCreditWindow(occurredAt, now, s.maxBackdating)
+	if outsideWindow {
+		s.logger.LogWarn(ctx, "activity logged outside the credit window, no credits awarded",
+			logger.String("user_id", req.UserID),
+			logger.String("occurred_at", occurredAt.Format(time.RFC3339)),
+			logger.String("max_backdating", s.maxBackdating.String()))
+		creditsEarned = 0
+	}
 
 	// Convert source data to JSON
-	sourceDataJSON := ""
-	if req.SourceData != nil {
-		sourceDataBytes, err := json.Marshal(req.SourceData)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal source data: %w", err)
-		}
-		sourceDataJSON = string(sourceDataBytes)
+	sourceDataJSON, err := encodeSourceData(req.SourceData)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create activity
@@ -130,17 +293,26 @@ func (s *TrackerService) LogActivity(ctx context.Context, req *LogActivityReques
 		IsVerified:     !activityType.RequiresVerification,
 		Source:         req.Source,
 		SourceData:     sourceDataJSON,
+		OccurredAt:     &occurredAt,
+
+		OutsideCreditWindow: outsideWindow,
 	}
 
 	if req.Source == "" {
 		activity.Source = models.SourceManual
 	}
+	activity.VerificationStatus = models.VerificationPending
+	if activity.IsVerified {
+		activity.VerificationStatus = models.VerificationApproved
+	}
 
 	// Save activity
 	if err := s.activityRepo.Create(ctx, activity); err != nil {
 		return nil, fmt.Errorf("failed to save activity: %w", err)
 	}
 
+	s.recordActivity(activityType.Name, activity.Source, activity.IsVerified)
+
 	// Publish credit earned event if verified
 	if activity.IsVerified && creditsEarned > 0 {
 		event := &CreditEarnedEvent{
@@ -151,6 +323,7 @@ func (s *TrackerService) LogActivity(ctx context.Context, req *LogActivityReques
 			Description:   activity.Description,
 			Timestamp:     time.Now().UTC(),
 		}
+		s.recordCreditsEarned(activityType.Name, creditsEarned)
 
 		if err := s.eventPublisher.PublishCreditEarned(ctx, event); err != nil {
 			s.logger.LogError(ctx, "failed to publish credit earned event", err,
@@ -159,6 +332,8 @@ func (s *TrackerService) LogActivity(ctx context.Context, req *LogActivityReques
 		}
 	}
 
+	s.recordChallengeProgress(ctx, activity, activityType.Name)
+
 	s.logger.LogInfo(ctx, "eco-activity logged successfully",
 		logger.String("activity_id", activity.ID.String()),
 		logger.String("user_id", req.UserID),
@@ -182,6 +357,54 @@ func (s *TrackerService) GetUserActivities(ctx context.Context, userID string, l
 	return responses, total, nil
 }
 
+// GetPendingEvidenceActivities retrieves a user's activities that are still
+// waiting for verification evidence before they are credited
+func (s *TrackerService) GetPendingEvidenceActivities(ctx context.Context, userID string, limit, offset int) ([]*ActivityResponse, int64, error) {
+	activities, total, err := s.activityRepo.GetPendingEvidenceByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get pending evidence activities: %w", err)
+	}
+
+	responses := make([]*ActivityResponse, len(activities))
+	for i, activity := range activities {
+		responses[i] = s.activityToResponse(activity, &activity.ActivityType)
+	}
+
+	return responses, total, nil
+}
+
+// GetUnverifiedActivities retrieves the moderation queue of activities
+// awaiting verification, oldest first
+func (s *TrackerService) GetUnverifiedActivities(ctx context.Context, limit, offset int) ([]*ActivityResponse, int64, error) {
+	activities, total, err := s.activityRepo.GetUnverified(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get unverified activities: %w", err)
+	}
+
+	responses := make([]*ActivityResponse, len(activities))
+	for i, activity := range activities {
+		responses[i] = s.activityToResponse(activity, &activity.ActivityType)
+	}
+
+	return responses, total, nil
+}
+
+// GetRecentActivities retrieves activities across all users, newest first,
+// optionally only those of one activity type
+func (s *TrackerService) GetRecentActivities(ctx context.Context, activityType string, limit, offset int) ([]*ActivityResponse, int64, error) {
+	activities, total, err := s.activityRepo.GetRecent(ctx, activityType, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get recent activities: %w", err)
+	}
+
+	responses := make([]*ActivityResponse, len(activities))
+	for i, activity := range activities {
+		responses[i] = s.activityToResponse(activity, &activity.ActivityType)
+	}
+
+	return responses, total, nil
+}
+
 // GetActivityByID retrieves a specific activity
 func (s *TrackerService) GetActivityByID(ctx context.Context, id uuid.UUID) (*ActivityResponse, error) {
 	activity, err := s.activityRepo.GetByID(ctx, id)
@@ -192,6 +415,29 @@ func (s *TrackerService) GetActivityByID(ctx context.Context, id uuid.UUID) (*Ac
 	return s.activityToResponse(activity, &activity.ActivityType), nil
 }
 
+// GetActivityWithSourceData retrieves one of the user's activities together
+// with the source data it was logged with
+func (s *TrackerService) GetActivityWithSourceData(ctx context.Context, id uuid.UUID, userID string) (*ActivityResponse, error) {
+	activity, err := s.activityRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity: %w", err)
+	}
+
+	// Source data can hold device or webhook details, so only the owner sees it
+	if activity.UserID != userID {
+		return nil, database.ErrNotFound
+	}
+
+	sourceData, err := decodeSourceData(activity.SourceData)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.activityToResponse(activity, &activity.ActivityType)
+	response.SourceData = sourceData
+	return response, nil
+}
+
 // VerifyActivity verifies an activity (admin/moderator operation)
 func (s *TrackerService) VerifyActivity(ctx context.Context, activityID uuid.UUID, verifiedBy string) error {
 	activity, err := s.activityRepo.GetByID(ctx, activityID)
@@ -199,14 +445,15 @@ func (s *TrackerService) VerifyActivity(ctx context.Context, activityID uuid.UUI
 		return fmt.Errorf("failed to get activity: %w", err)
 	}
 
-	if activity.IsVerified {
-		return fmt.Errorf("activity is already verified")
+	if err := checkVerifiable(activity); err != nil {
+		return err
 	}
 
 	now := time.Now().UTC()
 	activity.IsVerified = true
 	activity.VerifiedAt = &now
 	activity.VerifiedBy = verifiedBy
+	activity.VerificationStatus = models.VerificationApproved
 
 	if err := s.activityRepo.Update(ctx, activity); err != nil {
 		return fmt.Errorf("failed to update activity: %w", err)
@@ -222,6 +469,7 @@ func (s *TrackerService) VerifyActivity(ctx context.Context, activityID uuid.UUI
 			Description:   activity.Description,
 			Timestamp:     now,
 		}
+		s.recordCreditsEarned(activity.ActivityType.Name, activity.CreditsEarned)
 
 		if err := s.eventPublisher.PublishCreditEarned(ctx, event); err != nil {
 			s.logger.LogError(ctx, "failed to publish credit earned event", err,
@@ -229,6 +477,8 @@ func (s *TrackerService) VerifyActivity(ctx context.Context, activityID uuid.UUI
 		}
 	}
 
+	s.recordChallengeProgress(ctx, activity, activity.ActivityType.Name)
+
 	s.logger.LogInfo(ctx, "activity verified",
 		logger.String("activity_id", activityID.String()),
 		logger.String("verified_by", verifiedBy))
@@ -236,6 +486,186 @@ func (s *TrackerService) VerifyActivity(ctx context.Context, activityID uuid.UUI
 	return nil
 }
 
+// RejectActivity rejects a suspicious activity (admin/moderator operation).
+// A rejected activity never earns credits: it can no longer be verified, and
+// if it had already credited the wallet a credit revoked event is published
+// first so the wallet debits them back. The activity is left unchanged if
+// that publish fails.
+func (s *TrackerService) RejectActivity(ctx context.Context, activityID uuid.UUID, moderatorID, reason string) error {
+	activity, err := s.activityRepo.GetByID(ctx, activityID)
+	if err != nil {
+		return fmt.Errorf("failed to get activity: %w", err)
+	}
+
+	if activity.VerificationStatus == models.VerificationRejected {
+		return ErrActivityRejected
+	}
+
+	now := time.Now().UTC()
+	credited := activity.HasCredited()
+	if credited {
+		event := &CreditRevokedEvent{
+			UserID:         activity.UserID,
+			ActivityID:     activity.ID.String(),
+			ActivityType:   activity.ActivityType.Name,
+			CreditsRevoked: activity.CreditsEarned,
+			Reason:         "activity rejected: " + reason,
+			Timestamp:      now,
+		}
+
+		if err := s.eventPublisher.PublishCreditRevoked(ctx, event); err != nil {
+			return fmt.Errorf("failed to revoke activity credits: %w", err)
+		}
+	}
+
+	rejectActivity(activity, moderatorID, reason, now)
+
+	if err := s.activityRepo.Update(ctx, activity); err != nil {
+		return fmt.Errorf("failed to update activity: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "activity rejected",
+		logger.String("activity_id", activityID.String()),
+		logger.String("rejected_by", moderatorID),
+		logger.String("reason", reason),
+		logger.Bool("credits_revoked", credited))
+
+	return nil
+}
+
+// UpdateActivityRequest corrects a logged activity. Omitted fields are left
+// unchanged; the activity type cannot be changed.
+type UpdateActivityRequest struct {
+	Description *string    `json:"description"`
+	Duration    *int       `json:"duration"` // in minutes
+	Distance    *float64   `json:"distance"` // in kilometers
+	Quantity    *float64   `json:"quantity"`
+	Unit        *string    `json:"unit"`
+	Location    *string    `json:"location"`
+	OccurredAt  *time.Time `json:"occurred_at"`
+}
+
+// UpdateActivity corrects one of the user's activities and recomputes its
+// credits. If the activity is verified and its credits change, the wallet is
+// told before the activity is saved: a credit adjusted event carries the
+// difference, or a credit earned event if it had credited nothing yet. The
+// activity is left unchanged if that publish fails.
+func (s *TrackerService) UpdateActivity(ctx context.Context, activityID uuid.UUID, userID string, req *UpdateActivityRequest) (*ActivityResponse, error) {
+	activity, err := s.activityRepo.GetByID(ctx, activityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity: %w", err)
+	}
+
+	// Check if user owns the activity
+	if activity.UserID != userID {
+		return nil, database.ErrNotFound
+	}
+
+	if activity.VerificationStatus == models.VerificationRejected {
+		return nil, ErrActivityRejected
+	}
+
+	now := time.Now().UTC()
+	if req.OccurredAt != nil && req.OccurredAt.After(now.Add(occurredAtClockSkew)) {
+		return nil, fmt.Errorf("%w: %s is in the future", ErrInvalidOccurredAt, req.OccurredAt.Format(time.RFC3339))
+	}
+
+	previousCredits := activity.CreditsEarned
+	applyActivityUpdate(activity, req)
+
+	creditsEarned, err := s.calculateCredits(ctx, &activity.ActivityType, activityCreditRequest(activity))
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate credits: %w", err)
+	}
+	creditsEarned = s.rounding.RoundFloat(creditsEarned)
+
+	// The credit window runs from when the activity was logged, not edited
+	occurredAt := activity.CreatedAt
+	if activity.OccurredAt != nil {
+		occurredAt = *activity.OccurredAt
+	}
+	activity.OutsideCreditWindow = outsideCreditWindow(occurredAt, activity.CreatedAt, s.maxBackdating)
+	if activity.OutsideCreditWindow {
+		creditsEarned = 0
+	}
+	activity.CreditsEarned = creditsEarned
+
+	if activity.IsVerified && creditsEarned != previousCredits {
+		if previousCredits > 0 {
+			if err := s.eventPublisher.PublishCreditAdjusted(ctx, s.creditAdjustment(activity, previousCredits, now)); err != nil {
+				return nil, fmt.Errorf("failed to adjust activity credits: %w", err)
+			}
+		} else {
+			// Nothing was credited before, so there is no credit to adjust
+			event := &CreditEarnedEvent{
+				UserID:        activity.UserID,
+				ActivityID:    activity.ID.String(),
+				ActivityType:  activity.ActivityType.Name,
+				CreditsEarned: creditsEarned,
+				Description:   activity.Description,
+				Timestamp:     now,
+			}
+			if err := s.eventPublisher.PublishCreditEarned(ctx, event); err != nil {
+				return nil, fmt.Errorf("failed to publish activity credits: %w", err)
+			}
+			s.recordCreditsEarned(activity.ActivityType.Name, creditsEarned)
+		}
+	}
+
+	if err := s.activityRepo.Update(ctx, activity); err != nil {
+		return nil, fmt.Errorf("failed to update activity: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "activity updated",
+		logger.String("activity_id", activityID.String()),
+		logger.String("user_id", userID),
+		logger.Float64("previous_credits", previousCredits),
+		logger.Float64("credits_earned", creditsEarned))
+
+	return s.activityToResponse(activity, &activity.ActivityType), nil
+}
+
+// DeleteActivity deletes a user's activity. If the activity already credited
+// the wallet, a credit revoked event is written to the outbox with the delete,
+// so the wallet records a compensating debit once the delete is committed.
+func (s *TrackerService) DeleteActivity(ctx context.Context, activityID uuid.UUID, userID string) error {
+	activity, err := s.activityRepo.GetByID(ctx, activityID)
+	if err != nil {
+		return fmt.Errorf("failed to get activity: %w", err)
+	}
+
+	// Check if user owns the activity
+	if activity.UserID != userID {
+		return database.ErrNotFound
+	}
+
+	var revoked *events.OutboxEvent
+	if activity.HasCredited() {
+		revoked, err = events.NewOutboxEvent(EventTypeCreditRevoked, activity.UserID, &CreditRevokedEvent{
+			UserID:         activity.UserID,
+			ActivityID:     activity.ID.String(),
+			ActivityType:   activity.ActivityType.Name,
+			CreditsRevoked: activity.CreditsEarned,
+			Reason:         "activity deleted",
+			Timestamp:      time.Now().UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to revoke activity credits: %w", err)
+		}
+	}
+
+	if err := s.activityRepo.Delete(ctx, activityID, revoked); err != nil {
+		return fmt.Errorf("failed to delete activity: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "activity deleted",
+		logger.String("activity_id", activityID.String()),
+		logger.String("user_id", userID),
+		logger.Bool("credits_revoked", revoked != nil))
+
+	return nil
+}
+
 // GetUserStats retrieves activity statistics for a user
 func (s *TrackerService) GetUserStats(ctx context.Context, userID string, startDate, endDate time.Time) (*models.UserActivityStats, error) {
 	stats, err := s.activityRepo.GetUserStats(ctx, userID, startDate, endDate)
@@ -246,6 +676,168 @@ func (s *TrackerService) GetUserStats(ctx context.Context, userID string, startD
 	return stats, nil
 }
 
+// EraseUserData erases a deleted user's activity data
+func (s *TrackerService) EraseUserData(ctx context.Context, userID string) error {
+	if err := s.activityRepo.EraseUserData(ctx, userID); err != nil {
+		return fmt.Errorf("failed to erase user activity data: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "activity data erased for deleted user",
+		logger.String("user_id", userID))
+
+	return nil
+}
+
+// DeactivateActivityType stops an activity type from accepting new logs.
+// Existing activities keep referencing it and still count towards stats.
+func (s *TrackerService) DeactivateActivityType(ctx context.Context, activityTypeID uuid.UUID, actorID string) error {
+	activityType, err := s.activityTypeRepo.GetByID(ctx, activityTypeID)
+	if err != nil {
+		return fmt.Errorf("failed to get activity type: %w", err)
+	}
+
+	if !activityType.IsActive {
+		return nil
+	}
+
+	activityType.IsActive = false
+	activityType.DeactivatedBy = actorID
+	if err := s.activityTypeRepo.Update(ctx, activityType); err != nil {
+		return fmt.Errorf("failed to deactivate activity type: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "activity type deactivated",
+		logger.String("activity_type_id", activityTypeID.String()),
+		logger.String("name", activityType.Name),
+		logger.String("actor_id", actorID))
+
+	return nil
+}
+
+// DeleteActivityType deletes an activity type that has never been used.
+// Types with logged activities must be deactivated instead so history is kept.
+func (s *TrackerService) DeleteActivityType(ctx context.Context, activityTypeID uuid.UUID, actorID string) error {
+	if _, err := s.activityTypeRepo.GetByID(ctx, activityTypeID); err != nil {
+		return fmt.Errorf("failed to get activity type: %w", err)
+	}
+
+	count, err := s.activityRepo.CountByActivityType(ctx, activityTypeID)
+	if err != nil {
+		return fmt.Errorf("failed to check activity type usage: %w", err)
+	}
+
+	if err := checkActivityTypeDeletable(count); err != nil {
+		return err
+	}
+
+	if err := s.activityTypeRepo.Delete(ctx, activityTypeID); err != nil {
+		return fmt.Errorf("failed to delete activity type: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "activity type deleted",
+		logger.String("activity_type_id", activityTypeID.String()),
+		logger.String("actor_id", actorID))
+
+	return nil
+}
+
+// outsideCreditWindow reports whether an activity that occurred at
+// occurredAt is logged too late at now to earn credits. A zero window never
+// expires.
+func outsideCreditWindow(occurredAt, now time.Time, window time.Duration) bool {
+	return window > 0 && now.Sub(occurredAt) > window
+}
+
+// recordChallengeProgress counts a verified activity towards the user's
+// challenges. Unverified activities count once a moderator verifies them,
+// and activities outside the credit window never count.
+func (s *TrackerService) recordChallengeProgress(ctx context.Context, activity *models.EcoActivity, activityType string) {
+	if s.challenges == nil || !activity.IsVerified || activity.OutsideCreditWindow {
+		return
+	}
+	s.challenges.RecordActivity(ctx, activity, activityType)
+}
+
+// checkVerifiable refuses verification of an activity that is already
+// verified or was rejected, so a rejected activity never publishes credits
+func checkVerifiable(activity *models.EcoActivity) error {
+	if activity.VerificationStatus == models.VerificationRejected {
+		return ErrActivityRejected
+	}
+	if activity.IsVerified {
+		return ErrActivityAlreadyVerified
+	}
+	return nil
+}
+
+// rejectActivity marks an activity rejected by moderatorID. It is no longer
+// verified, so it is not counted as having credited the wallet.
+func rejectActivity(activity *models.EcoActivity, moderatorID, reason string, now time.Time) {
+	activity.IsVerified = false
+	activity.VerificationStatus = models.VerificationRejected
+	activity.RejectionReason = reason
+	activity.RejectedAt = &now
+	activity.RejectedBy = moderatorID
+}
+
+// applyActivityUpdate copies the fields set in req onto activity
+func applyActivityUpdate(activity *models.EcoActivity, req *UpdateActivityRequest) {
+	if req.Description != nil {
+		activity.Description = *req.Description
+	}
+	if req.Duration != nil {
+		activity.Duration = *req.Duration
+	}
+	if req.Distance != nil {
+		activity.Distance = *req.Distance
+	}
+	if req.Quantity != nil {
+		activity.Quantity = *req.Quantity
+	}
+	if req.Unit != nil {
+		activity.Unit = *req.Unit
+	}
+	if req.Location != nil {
+		activity.Location = *req.Location
+	}
+	if req.OccurredAt != nil {
+		occurredAt := req.OccurredAt.UTC()
+		activity.OccurredAt = &occurredAt
+	}
+}
+
+// activityCreditRequest returns the measurements credits are calculated from
+// for a logged activity
+func activityCreditRequest(activity *models.EcoActivity) *LogActivityRequest {
+	return &LogActivityRequest{
+		Duration: activity.Duration,
+		Distance: activity.Distance,
+		Quantity: activity.Quantity,
+	}
+}
+
+// creditAdjustment builds the event correcting the wallet credit of an
+// edited activity from previousCredits to its current credits
+func (s *TrackerService) creditAdjustment(activity *models.EcoActivity, previousCredits float64, now time.Time) *CreditAdjustedEvent {
+	return &CreditAdjustedEvent{
+		UserID:       activity.UserID,
+		ActivityID:   activity.ID.String(),
+		ActivityType: activity.ActivityType.Name,
+		AdjustmentID: uuid.New().String(),
+		CreditsDelta: s.rounding.RoundFloat(activity.CreditsEarned - previousCredits),
+		Reason:       "activity edited",
+		Timestamp:    now,
+	}
+}
+
+// checkActivityTypeDeletable refuses deletion of a type with logged activities
+func checkActivityTypeDeletable(activityCount int64) error {
+	if activityCount > 0 {
+		return fmt.Errorf("%w: %d activities reference it, deactivate it instead", ErrActivityTypeInUse, activityCount)
+	}
+	return nil
+}
+
 // calculateCredits calculates credits earned for an activity
 func (s *TrackerService) calculateCredits(ctx context.Context, activityType *models.ActivityType, req *LogActivityRequest) (float64, error) {
 	// Get applicable credit rules
@@ -259,8 +851,6 @@ func (s *TrackerService) calculateCredits(ctx context.Context, activityType *mod
 		return s.calculateBaseCredits(activityType, req), nil
 	}
 
-	// Find the best matching rule
-	var bestRule *models.CreditRule
 	var value float64
 
 	// Determine the value to use for rule matching
@@ -275,15 +865,8 @@ func (s *TrackerService) calculateCredits(ctx context.Context, activityType *mod
 		value = req.Quantity
 	}
 
-	for _, rule := range rules {
-		if value >= rule.MinValue && (rule.MaxValue == 0 || value <= rule.MaxValue) {
-			if bestRule == nil || rule.CreditsPerUnit > bestRule.CreditsPerUnit {
-				bestRule = rule
-			}
-		}
-	}
-
-	if bestRule != nil {
+	// Find the best matching rule
+	if bestRule := bestCreditRule(rules, value); bestRule != nil {
 		return value * bestRule.CreditsPerUnit * bestRule.Multiplier, nil
 	}
 
@@ -305,8 +888,61 @@ func (s *TrackerService) calculateBaseCredits(activityType *models.ActivityType,
 	}
 }
 
+// encodeSourceData serializes source data for storage, rejecting payloads
+// larger than MaxSourceDataBytes
+func encodeSourceData(sourceData map[string]interface{}) (string, error) {
+	if len(sourceData) == 0 {
+		return "", nil
+	}
+
+	encoded, err := json.Marshal(sourceData)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidSourceData, err)
+	}
+	if len(encoded) > MaxSourceDataBytes {
+		return "", fmt.Errorf("%w: %d bytes exceeds the limit of %d", ErrInvalidSourceData, len(encoded), MaxSourceDataBytes)
+	}
+	return string(encoded), nil
+}
+
+// decodeSourceData parses stored source data, returning nil when there is none
+func decodeSourceData(sourceData string) (map[string]interface{}, error) {
+	if sourceData == "" {
+		return nil, nil
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(sourceData), &decoded); err != nil {
+		return nil, fmt.Errorf("failed to parse stored source data: %w", err)
+	}
+	if len(decoded) == 0 {
+		return nil, nil
+	}
+	return decoded, nil
+}
+
+// recordActivity counts a logged activity by type, source and verification state
+func (s *TrackerService) recordActivity(activityType, source string, verified bool) {
+	if s.metrics != nil {
+		s.metrics.RecordActivity(metricsServiceName, activityType, source, verified)
+	}
+}
+
+// recordCreditsEarned adds credits awarded for an activity type
+func (s *TrackerService) recordCreditsEarned(activityType string, credits float64) {
+	if s.metrics != nil {
+		s.metrics.RecordCreditsEarned(metricsServiceName, activityType, credits)
+	}
+}
+
 // activityToResponse converts an activity model to response format
 func (s *TrackerService) activityToResponse(activity *models.EcoActivity, activityType *models.ActivityType) *ActivityResponse {
+	// Activities logged before occurred_at was recorded happened when logged
+	occurredAt := activity.CreatedAt
+	if activity.OccurredAt != nil {
+		occurredAt = *activity.OccurredAt
+	}
+
 	return &ActivityResponse{
 		ID:            activity.ID,
 		UserID:        activity.UserID,
@@ -319,7 +955,16 @@ func (s *TrackerService) activityToResponse(activity *models.EcoActivity, activi
 		Location:      activity.Location,
 		CreditsEarned: activity.CreditsEarned,
 		IsVerified:    activity.IsVerified,
+		VerifiedBy:    activity.VerifiedBy,
 		Source:        activity.Source,
+		OccurredAt:    occurredAt,
 		CreatedAt:     activity.CreatedAt,
+
+		OutsideCreditWindow: activity.OutsideCreditWindow,
+
+		VerificationStatus: activity.VerificationStatus,
+		RejectionReason:    activity.RejectionReason,
+
+		ActivityTypeDeactivated: !activityType.IsActive,
 	}
 }
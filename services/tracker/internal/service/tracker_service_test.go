@@ -1,11 +1,20 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/credits"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/events"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
 )
 
 func TestEcoActivityModel_Creation(t *testing.T) {
@@ -131,3 +140,796 @@ func TestIoTDeviceModel_Creation(t *testing.T) {
 		t.Error("Expected device to be active")
 	}
 }
+
+func TestEcoActivityModel_HasCredited(t *testing.T) {
+	tests := []struct {
+		name     string
+		activity models.EcoActivity
+		expected bool
+	}{
+		{"verified with credits", models.EcoActivity{IsVerified: true, CreditsEarned: 2.5}, true},
+		{"awaiting verification", models.EcoActivity{IsVerified: false, CreditsEarned: 2.5}, false},
+		{"verified without credits", models.EcoActivity{IsVerified: true, CreditsEarned: 0}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.activity.HasCredited(); got != tt.expected {
+			t.Errorf("%s: expected HasCredited %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestPublishCreditRevokedFromOutbox(t *testing.T) {
+	revoked := &CreditRevokedEvent{
+		UserID:         "user-1",
+		ActivityID:     uuid.New().String(),
+		ActivityType:   "biking",
+		CreditsRevoked: 2.5,
+		Reason:         "activity deleted",
+		Timestamp:      time.Now().UTC(),
+	}
+	entry, err := events.NewOutboxEvent(EventTypeCreditRevoked, revoked.UserID, revoked)
+	if err != nil {
+		t.Fatalf("Failed to create outbox event: %v", err)
+	}
+
+	publisher := NewMockEventPublisher(logger.New("error"))
+	if err := PublishCreditRevokedFromOutbox(publisher)(context.Background(), entry); err != nil {
+		t.Fatalf("Expected the outbox entry to be published, got %v", err)
+	}
+	if len(publisher.Events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(publisher.Events))
+	}
+	published, ok := publisher.Events[0].(*CreditRevokedEvent)
+	if !ok {
+		t.Fatalf("Expected a credit revoked event, got %T", publisher.Events[0])
+	}
+	if published.ActivityID != revoked.ActivityID || published.CreditsRevoked != revoked.CreditsRevoked ||
+		!published.Timestamp.Equal(revoked.Timestamp) {
+		t.Errorf("Expected the stored event to be published, got %+v", published)
+	}
+}
+
+func TestActivityToResponse_FlagsDeactivatedType(t *testing.T) {
+	s := &TrackerService{}
+	activity := &models.EcoActivity{ID: uuid.New(), UserID: "test-user-123"}
+
+	active := s.activityToResponse(activity, &models.ActivityType{Name: "Biking", IsActive: true})
+	if active.ActivityTypeDeactivated {
+		t.Error("Expected activity of an active type not to be flagged")
+	}
+
+	retired := s.activityToResponse(activity, &models.ActivityType{Name: "Biking", IsActive: false})
+	if !retired.ActivityTypeDeactivated {
+		t.Error("Expected activity of a deactivated type to be flagged")
+	}
+}
+
+func TestActivityToResponse_IncludesVerifier(t *testing.T) {
+	s := &TrackerService{}
+	activity := &models.EcoActivity{ID: uuid.New(), UserID: "test-user-123", IsVerified: true, VerifiedBy: "admin-1"}
+
+	response := s.activityToResponse(activity, &models.ActivityType{Name: "Biking", IsActive: true})
+	if response.VerifiedBy != "admin-1" {
+		t.Errorf("Expected verified_by admin-1, got %q", response.VerifiedBy)
+	}
+}
+
+func TestOutsideCreditWindow(t *testing.T) {
+	now := time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)
+	window := 72 * time.Hour
+
+	tests := []struct {
+		name       string
+		occurredAt time.Time
+		window     time.Duration
+		expected   bool
+	}{
+		{"logged as it happens", now, window, false},
+		{"inside the window", now.Add(-71 * time.Hour), window, false},
+		{"older than the window", now.Add(-73 * time.Hour), window, true},
+		{"no window configured", now.AddDate(-1, 0, 0), 0, false},
+	}
+
+	for _, tt := range tests {
+		if got := outsideCreditWindow(tt.occurredAt, now, tt.window); got != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestCheckActivityTypeDeletable(t *testing.T) {
+	if err := checkActivityTypeDeletable(0); err != nil {
+		t.Errorf("Expected unused activity type to be deletable, got %v", err)
+	}
+
+	if err := checkActivityTypeDeletable(3); !errors.Is(err, ErrActivityTypeInUse) {
+		t.Errorf("Expected ErrActivityTypeInUse, got %v", err)
+	}
+}
+
+func TestLogUserActivity_RejectsReservedSources(t *testing.T) {
+	s := NewTrackerService(nil, nil, nil, nil, nil, nil, 0, credits.RoundingPolicy{}, nil, nil, logger.New("error"))
+
+	for _, source := range []string{models.SourceIoT, models.SourceWebhook} {
+		req := &LogActivityRequest{UserID: "test-user-123", ActivityType: "Biking", Source: source}
+		if _, err := s.LogUserActivity(context.Background(), req); !errors.Is(err, ErrSourceNotAllowed) {
+			t.Errorf("Expected ErrSourceNotAllowed for source %s, got %v", source, err)
+		}
+	}
+}
+
+func TestRecordActivityMetrics(t *testing.T) {
+	metrics := monitoring.NewMetrics("tracker")
+	s := NewTrackerService(nil, nil, nil, nil, nil, nil, 0, credits.RoundingPolicy{}, metrics, nil, logger.New("error"))
+
+	s.recordActivity("Biking", models.SourceManual, true)
+	s.recordCreditsEarned("Biking", 2.5)
+
+	if got := testutil.ToFloat64(metrics.ActivitiesTotal.WithLabelValues("tracker", "Biking", models.SourceManual, "true")); got != 1 {
+		t.Errorf("Expected 1 recorded activity, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.CreditsEarned.WithLabelValues("tracker", "Biking")); got != 2.5 {
+		t.Errorf("Expected 2.5 credits earned, got %v", got)
+	}
+
+	// A service without metrics must not panic
+	NewTrackerService(nil, nil, nil, nil, nil, nil, 0, credits.RoundingPolicy{}, nil, nil, logger.New("error")).recordActivity("Biking", models.SourceManual, false)
+}
+
+func TestSourceData_RoundTrip(t *testing.T) {
+	encoded, err := encodeSourceData(map[string]interface{}{"device_id": "meter-1", "reading": 42.5})
+	if err != nil {
+		t.Fatalf("Expected source data to encode, got %v", err)
+	}
+
+	decoded, err := decodeSourceData(encoded)
+	if err != nil {
+		t.Fatalf("Expected source data to decode, got %v", err)
+	}
+	if decoded["device_id"] != "meter-1" || decoded["reading"] != 42.5 {
+		t.Errorf("Expected source data to round-trip, got %v", decoded)
+	}
+
+	if decoded, err := decodeSourceData("{}"); err != nil || decoded != nil {
+		t.Errorf("Expected empty source data to be omitted, got %v (%v)", decoded, err)
+	}
+}
+
+func TestEncodeSourceData_RejectsOversizedPayload(t *testing.T) {
+	payload := map[string]interface{}{"raw": strings.Repeat("x", MaxSourceDataBytes)}
+	if _, err := encodeSourceData(payload); !errors.Is(err, ErrInvalidSourceData) {
+		t.Errorf("Expected ErrInvalidSourceData, got %v", err)
+	}
+}
+
+func TestChallengeRewardDue(t *testing.T) {
+	rewardedAt := time.Now()
+	challenge := &models.ActivityChallenge{TargetValue: 30, RewardCredits: 50, IsActive: true}
+
+	tests := []struct {
+		name        string
+		challenge   *models.ActivityChallenge
+		participant *models.ChallengeParticipant
+		expected    bool
+	}{
+		{"target met", challenge, &models.ChallengeParticipant{Progress: 30}, true},
+		{"target not met", challenge, &models.ChallengeParticipant{Progress: 29.5}, false},
+		{"already rewarded", challenge, &models.ChallengeParticipant{Progress: 40, RewardedAt: &rewardedAt}, false},
+		{"no reward configured", &models.ActivityChallenge{TargetValue: 30, IsActive: true}, &models.ChallengeParticipant{Progress: 30}, false},
+		{"inactive challenge", &models.ActivityChallenge{TargetValue: 30, RewardCredits: 50}, &models.ChallengeParticipant{Progress: 30}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := challengeRewardDue(tt.challenge, tt.participant); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestChallengeRewardReference_SamePerUserAndChallenge(t *testing.T) {
+	challengeID := uuid.New()
+	first := &models.ChallengeParticipant{ID: uuid.New(), ChallengeID: challengeID, UserID: "test-user-123"}
+	second := &models.ChallengeParticipant{ID: uuid.New(), ChallengeID: challengeID, UserID: "test-user-123"}
+	other := &models.ChallengeParticipant{ID: uuid.New(), ChallengeID: challengeID, UserID: "test-user-456"}
+
+	if challengeRewardReference(first) != challengeRewardReference(second) {
+		t.Error("Expected repeated participations to share a wallet reference")
+	}
+	if challengeRewardReference(first) == challengeRewardReference(other) {
+		t.Error("Expected different users to have different wallet references")
+	}
+}
+
+func TestAuthenticateDevice(t *testing.T) {
+	active := &models.IoTDevice{ID: uuid.New(), IsActive: true}
+	if err := authenticateDevice(active, nil); err != nil {
+		t.Errorf("Expected active device to authenticate, got %v", err)
+	}
+
+	inactive := &models.IoTDevice{ID: uuid.New(), IsActive: false}
+	if err := authenticateDevice(inactive, nil); !errors.Is(err, ErrDeviceUnauthorized) {
+		t.Errorf("Expected ErrDeviceUnauthorized for a deactivated device, got %v", err)
+	}
+
+	if err := authenticateDevice(nil, database.ErrNotFound); !errors.Is(err, ErrDeviceUnauthorized) {
+		t.Errorf("Expected ErrDeviceUnauthorized for an unknown API key, got %v", err)
+	}
+
+	lookupErr := errors.New("connection reset")
+	if err := authenticateDevice(nil, lookupErr); !errors.Is(err, lookupErr) || errors.Is(err, ErrDeviceUnauthorized) {
+		t.Errorf("Expected lookup failures to pass through, got %v", err)
+	}
+}
+
+func TestDeviceActivityRequest(t *testing.T) {
+	device := &models.IoTDevice{
+		UserID:   "test-user-123",
+		DeviceID: "meter-001",
+		Name:     "Rooftop Meter",
+		Type:     models.DeviceTypeSmartMeter,
+	}
+	req := &IoTDataRequest{
+		ActivityType: models.ActivitySolarEnergy,
+		Quantity:     4.2,
+		Unit:         "kWh",
+		Data:         map[string]interface{}{"panel_voltage": 31.5},
+	}
+
+	activity := deviceActivityRequest(device, req)
+
+	if activity.UserID != device.UserID {
+		t.Errorf("Expected activity logged for the device owner, got %s", activity.UserID)
+	}
+	if activity.Source != models.SourceIoT {
+		t.Errorf("Expected source %s, got %s", models.SourceIoT, activity.Source)
+	}
+	if activity.Quantity != 4.2 || activity.Unit != "kWh" || activity.ActivityType != models.ActivitySolarEnergy {
+		t.Errorf("Expected reading copied to the activity, got %+v", activity)
+	}
+	if activity.Description != "Reported by Rooftop Meter" {
+		t.Errorf("Expected default description, got %q", activity.Description)
+	}
+	if activity.SourceData["device_id"] != "meter-001" || activity.SourceData["data"] == nil {
+		t.Errorf("Expected device and raw readings in source data, got %v", activity.SourceData)
+	}
+}
+
+func TestIsDeviceType(t *testing.T) {
+	if !models.IsDeviceType(models.DeviceTypeBikeSensor) {
+		t.Error("Expected bike_sensor to be a device type")
+	}
+	if models.IsDeviceType("toaster") {
+		t.Error("Expected toaster not to be a device type")
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	secret := []byte("webhook-secret")
+	payload := []byte(`{"event_id":"evt-1"}`)
+	now := time.Unix(1717243200, 0)
+	timestamp := "1717243200"
+	signature := webhookSignature(secret, timestamp, payload)
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		timestamp string
+		signature string
+		payload   []byte
+		wantErr   bool
+	}{
+		{"valid", secret, timestamp, signature, payload, false},
+		{"wrong secret", []byte("other-secret"), timestamp, signature, payload, true},
+		{"tampered body", secret, timestamp, signature, []byte(`{"event_id":"evt-2"}`), true},
+		{"tampered timestamp", secret, "1717243260", signature, payload, true},
+		{"missing signature", secret, timestamp, "", payload, true},
+		{"stale timestamp", secret, "1717242000", webhookSignature(secret, "1717242000", payload), payload, true},
+		{"future timestamp", secret, "1717244400", webhookSignature(secret, "1717244400", payload), payload, true},
+		{"malformed timestamp", secret, "yesterday", webhookSignature(secret, "yesterday", payload), payload, true},
+	}
+
+	for _, tt := range tests {
+		err := verifyWebhook(tt.secret, tt.timestamp, tt.signature, tt.payload, now, 5*time.Minute)
+		if tt.wantErr && !errors.Is(err, ErrInvalidWebhookSignature) {
+			t.Errorf("%s: expected ErrInvalidWebhookSignature, got %v", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: expected no error, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestWebhookService_RejectsBeforeRecordingEvent(t *testing.T) {
+	registry := NewWebhookRegistry()
+	registry.Register(StravaProvider{}, "webhook-secret")
+	// No event repository: rejected deliveries must not reach it
+	s := NewWebhookService(registry, nil, nil, 5*time.Minute, logger.New("error"))
+	ctx := context.Background()
+	payload := []byte(`{"event_id":"evt-1"}`)
+	timestamp := "1717243200"
+
+	_, err := s.HandleWebhook(ctx, "fitbit", timestamp, webhookSignature([]byte("webhook-secret"), timestamp, payload), payload)
+	if !errors.Is(err, ErrUnknownWebhookProvider) {
+		t.Errorf("Expected ErrUnknownWebhookProvider, got %v", err)
+	}
+
+	_, err = s.HandleWebhook(ctx, "strava", timestamp, webhookSignature([]byte("guessed"), timestamp, payload), payload)
+	if !errors.Is(err, ErrInvalidWebhookSignature) {
+		t.Errorf("Expected ErrInvalidWebhookSignature, got %v", err)
+	}
+}
+
+func TestStravaProvider_ParseEvent(t *testing.T) {
+	payload := []byte(`{
+		"event_id": "evt-42",
+		"user_id": "test-user-123",
+		"activity": {
+			"id": 987654,
+			"type": "Ride",
+			"name": "Commute",
+			"distance": 12500,
+			"moving_time": 2700,
+			"start_date": "2024-06-01T07:30:00Z"
+		}
+	}`)
+
+	eventID, req, err := StravaProvider{}.ParseEvent(payload)
+	if err != nil {
+		t.Fatalf("ParseEvent failed: %v", err)
+	}
+	if eventID != "evt-42" || req.UserID != "test-user-123" {
+		t.Errorf("Expected event evt-42 for test-user-123, got %s for %s", eventID, req.UserID)
+	}
+	if req.ActivityType != models.ActivityBiking {
+		t.Errorf("Expected a ride logged as %s, got %s", models.ActivityBiking, req.ActivityType)
+	}
+	if req.Distance != 12.5 || req.Duration != 45 {
+		t.Errorf("Expected 12.5 km over 45 minutes, got %v km over %d minutes", req.Distance, req.Duration)
+	}
+	if req.OccurredAt == nil || !req.OccurredAt.Equal(time.Date(2024, 6, 1, 7, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected the start date as occurred_at, got %v", req.OccurredAt)
+	}
+
+	_, _, err = StravaProvider{}.ParseEvent([]byte(`{"event_id":"evt-43","user_id":"u","activity":{"type":"Swim"}}`))
+	if err == nil {
+		t.Error("Expected unsupported activity types to be rejected")
+	}
+}
+
+func TestRejectActivity_BlocksCredits(t *testing.T) {
+	activity := &models.EcoActivity{
+		ID:                 uuid.New(),
+		CreditsEarned:      3.5,
+		IsVerified:         false,
+		VerificationStatus: models.VerificationPending,
+	}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	rejectActivity(activity, "moderator-1", "duplicate submission", now)
+
+	if activity.VerificationStatus != models.VerificationRejected || activity.RejectionReason != "duplicate submission" {
+		t.Errorf("Expected activity rejected with its reason, got %+v", activity)
+	}
+	if activity.RejectedBy != "moderator-1" || activity.RejectedAt == nil || !activity.RejectedAt.Equal(now) {
+		t.Errorf("Expected rejection attributed to moderator-1 at %v, got %+v", now, activity)
+	}
+	if activity.HasCredited() {
+		t.Error("Expected a rejected activity not to count as credited")
+	}
+	if err := checkVerifiable(activity); !errors.Is(err, ErrActivityRejected) {
+		t.Errorf("Expected verifying a rejected activity to fail with ErrActivityRejected, got %v", err)
+	}
+}
+
+func TestRejectActivity_UncreditsVerifiedActivity(t *testing.T) {
+	activity := &models.EcoActivity{
+		CreditsEarned:      3.5,
+		IsVerified:         true,
+		VerificationStatus: models.VerificationApproved,
+	}
+	if !activity.HasCredited() {
+		t.Fatal("Expected a verified activity with credits to have credited")
+	}
+
+	rejectActivity(activity, "moderator-1", "fabricated distance", time.Now().UTC())
+
+	if activity.IsVerified || activity.HasCredited() {
+		t.Errorf("Expected rejection to clear verification, got %+v", activity)
+	}
+}
+
+func TestUpdateActivity_RecomputesCredits(t *testing.T) {
+	svc := NewTrackerService(nil, nil, nil, nil, nil, nil, 0, credits.RoundingPolicy{}, nil, nil, logger.New("error"))
+	activityType := models.ActivityType{Name: models.ActivityBiking, Unit: "km", BaseCreditsPerUnit: 0.25}
+	activity := &models.EcoActivity{
+		ID:            uuid.New(),
+		UserID:        "user-1",
+		ActivityType:  activityType,
+		Description:   "Ride to work",
+		Distance:      10,
+		CreditsEarned: 2.5,
+		IsVerified:    true,
+	}
+
+	distance := 14.5
+	description := "Ride to work and back"
+	applyActivityUpdate(activity, &UpdateActivityRequest{Distance: &distance, Description: &description})
+
+	if activity.Distance != 14.5 || activity.Description != description {
+		t.Errorf("Expected distance and description updated, got %+v", activity)
+	}
+
+	previousCredits := activity.CreditsEarned
+	activity.CreditsEarned = svc.calculateBaseCredits(&activity.ActivityType, activityCreditRequest(activity))
+	if activity.CreditsEarned != 3.625 {
+		t.Fatalf("Expected 3.625 credits for 14.5 km, got %v", activity.CreditsEarned)
+	}
+
+	event := svc.creditAdjustment(activity, previousCredits, time.Now().UTC())
+	if event.CreditsDelta != 1.125 {
+		t.Errorf("Expected the wallet adjusted by the difference 1.125, got %v", event.CreditsDelta)
+	}
+	if event.ActivityID != activity.ID.String() || event.UserID != "user-1" || event.AdjustmentID == "" {
+		t.Errorf("Expected the adjustment to reference the activity, got %+v", event)
+	}
+
+	// Correcting the distance downwards takes credits back
+	distance = 4
+	applyActivityUpdate(activity, &UpdateActivityRequest{Distance: &distance})
+	previousCredits = activity.CreditsEarned
+	activity.CreditsEarned = svc.calculateBaseCredits(&activity.ActivityType, activityCreditRequest(activity))
+
+	event = svc.creditAdjustment(activity, previousCredits, time.Now().UTC())
+	if event.CreditsDelta != -2.625 {
+		t.Errorf("Expected a negative adjustment of -2.625, got %v", event.CreditsDelta)
+	}
+}
+
+func TestApplyActivityUpdate_LeavesOmittedFields(t *testing.T) {
+	occurredAt := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	activity := &models.EcoActivity{
+		Description: "Walk",
+		Duration:    30,
+		Location:    "Park",
+		OccurredAt:  &occurredAt,
+	}
+
+	corrected := time.Date(2024, 6, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	applyActivityUpdate(activity, &UpdateActivityRequest{OccurredAt: &corrected})
+
+	if activity.Description != "Walk" || activity.Duration != 30 || activity.Location != "Park" {
+		t.Errorf("Expected omitted fields unchanged, got %+v", activity)
+	}
+	if !activity.OccurredAt.Equal(corrected) || activity.OccurredAt.Location() != time.UTC {
+		t.Errorf("Expected occurred_at corrected and stored in UTC, got %v", activity.OccurredAt)
+	}
+}
+
+func TestValidateCreditRule(t *testing.T) {
+	validFrom := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	before := validFrom.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		rule    models.CreditRule
+		wantErr bool
+	}{
+		{"bounded range", models.CreditRule{MinValue: 0, MaxValue: 10, CreditsPerUnit: 0.5, Multiplier: 1}, false},
+		{"open-ended range", models.CreditRule{MinValue: 10, CreditsPerUnit: 0.5, Multiplier: 1}, false},
+		{"empty range", models.CreditRule{MinValue: 10, MaxValue: 10, CreditsPerUnit: 0.5, Multiplier: 1}, true},
+		{"inverted range", models.CreditRule{MinValue: 20, MaxValue: 10, CreditsPerUnit: 0.5, Multiplier: 1}, true},
+		{"zero rate", models.CreditRule{MaxValue: 10, Multiplier: 1}, true},
+		{"negative multiplier", models.CreditRule{MaxValue: 10, CreditsPerUnit: 0.5, Multiplier: -1}, true},
+		{"ends before it starts", models.CreditRule{CreditsPerUnit: 0.5, Multiplier: 1, ValidFrom: validFrom, ValidTo: &before}, true},
+	}
+
+	for _, tt := range tests {
+		err := validateCreditRule(&tt.rule)
+		if tt.wantErr && !errors.Is(err, ErrInvalidCreditRule) {
+			t.Errorf("%s: expected ErrInvalidCreditRule, got %v", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: expected valid rule, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestApplyCreditRuleRequest_DefaultsMultiplier(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	rule := &models.CreditRule{}
+
+	applyCreditRuleRequest(rule, &CreditRuleRequest{Name: "Long rides", MinValue: 20, CreditsPerUnit: 0.8}, now)
+
+	if rule.Multiplier != 1 {
+		t.Errorf("Expected an omitted multiplier to default to 1, got %v", rule.Multiplier)
+	}
+	if !rule.ValidFrom.Equal(now) || rule.ValidTo != nil {
+		t.Errorf("Expected the rule valid from now with no end, got %v to %v", rule.ValidFrom, rule.ValidTo)
+	}
+	if err := validateCreditRule(rule); err != nil {
+		t.Errorf("Expected the defaulted rule to be valid, got %v", err)
+	}
+}
+
+func TestBestCreditRule_OverlappingRanges(t *testing.T) {
+	short := &models.CreditRule{Name: "short", MinValue: 0, MaxValue: 10, CreditsPerUnit: 0.5}
+	medium := &models.CreditRule{Name: "medium", MinValue: 5, MaxValue: 20, CreditsPerUnit: 0.7}
+	long := &models.CreditRule{Name: "long", MinValue: 15, CreditsPerUnit: 0.6}
+	rules := []*models.CreditRule{short, medium, long}
+
+	tests := []struct {
+		value    float64
+		expected *models.CreditRule
+	}{
+		{3, short},   // only short matches
+		{5, medium},  // lower bound is inclusive
+		{10, medium}, // short and medium overlap, medium pays more
+		{18, medium}, // medium and long overlap, medium pays more
+		{20, medium}, // upper bound is inclusive
+		{25, long},   // only the open-ended rule matches
+	}
+
+	for _, tt := range tests {
+		if got := bestCreditRule(rules, tt.value); got != tt.expected {
+			t.Errorf("Value %v: expected rule %q, got %+v", tt.value, tt.expected.Name, got)
+		}
+	}
+
+	if got := bestCreditRule([]*models.CreditRule{short}, 11); got != nil {
+		t.Errorf("Expected no rule beyond every range, got %q", got.Name)
+	}
+}
+
+func TestCheckJoinable(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	challenge := func(start, end time.Time, active bool) *models.ActivityChallenge {
+		return &models.ActivityChallenge{StartDate: start, EndDate: end, IsActive: active}
+	}
+
+	tests := []struct {
+		name      string
+		challenge *models.ActivityChallenge
+		wantErr   bool
+	}{
+		{"running", challenge(now.AddDate(0, 0, -1), now.AddDate(0, 0, 1), true), false},
+		{"not started", challenge(now.AddDate(0, 0, 1), now.AddDate(0, 0, 2), true), false},
+		{"ended", challenge(now.AddDate(0, 0, -2), now.AddDate(0, 0, -1), true), true},
+		{"inactive", challenge(now.AddDate(0, 0, -1), now.AddDate(0, 0, 1), false), true},
+	}
+
+	for _, tt := range tests {
+		err := checkJoinable(tt.challenge, now)
+		if tt.wantErr && !errors.Is(err, ErrChallengeClosed) {
+			t.Errorf("%s: expected ErrChallengeClosed, got %v", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: expected no error, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestValidateChallenge(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	valid := CreateChallengeRequest{
+		Name:        "Bike to Work Month",
+		StartDate:   start,
+		EndDate:     start.AddDate(0, 1, 0),
+		TargetValue: 100,
+		TargetUnit:  models.ChallengeMetricDistance,
+	}
+	if err := validateChallenge(&valid); err != nil {
+		t.Errorf("Expected a valid challenge, got %v", err)
+	}
+
+	unknownUnit := valid
+	unknownUnit.TargetUnit = "days"
+	if err := validateChallenge(&unknownUnit); !errors.Is(err, ErrInvalidChallenge) {
+		t.Errorf("Expected an unknown target unit to be rejected, got %v", err)
+	}
+
+	emptyWindow := valid
+	emptyWindow.EndDate = start
+	if err := validateChallenge(&emptyWindow); !errors.Is(err, ErrInvalidChallenge) {
+		t.Errorf("Expected an empty window to be rejected, got %v", err)
+	}
+}
+
+func TestChallengeContribution(t *testing.T) {
+	activity := &models.EcoActivity{Distance: 12.5, Duration: 45, CreditsEarned: 3.75}
+
+	tests := []struct {
+		unit string
+		want float64
+	}{
+		{models.ChallengeMetricActivities, 1},
+		{models.ChallengeMetricDistance, 12.5},
+		{models.ChallengeMetricDuration, 45},
+		{models.ChallengeMetricCredits, 3.75},
+		{"days", 0},
+	}
+
+	for _, tt := range tests {
+		challenge := &models.ActivityChallenge{TargetUnit: tt.unit}
+		if got := challengeContribution(challenge, activity); got != tt.want {
+			t.Errorf("%s: expected contribution %v, got %v", tt.unit, tt.want, got)
+		}
+	}
+}
+
+func TestChallengeProgress_RewardDueOnceTargetMet(t *testing.T) {
+	challenge := &models.ActivityChallenge{
+		TargetValue:   30,
+		TargetUnit:    models.ChallengeMetricDistance,
+		RewardCredits: 50,
+		IsActive:      true,
+	}
+	participant := &models.ChallengeParticipant{}
+
+	for _, distance := range []float64{12, 10} {
+		participant.Progress += challengeContribution(challenge, &models.EcoActivity{Distance: distance})
+	}
+	if challengeRewardDue(challenge, participant) {
+		t.Errorf("Expected no reward at %v of %v km", participant.Progress, challenge.TargetValue)
+	}
+
+	participant.Progress += challengeContribution(challenge, &models.EcoActivity{Distance: 8})
+	if !challengeRewardDue(challenge, participant) {
+		t.Errorf("Expected the reward due at %v of %v km", participant.Progress, challenge.TargetValue)
+	}
+
+	rewardedAt := time.Now().UTC()
+	participant.RewardedAt = &rewardedAt
+	participant.Progress += challengeContribution(challenge, &models.EcoActivity{Distance: 5})
+	if challengeRewardDue(challenge, participant) {
+		t.Error("Expected the reward paid only once")
+	}
+}
+
+func TestRecordChallengeProgress_OnlyCountsCreditedActivities(t *testing.T) {
+	// A challenge service without repositories fails loudly if it is used
+	s := NewTrackerService(nil, nil, nil, nil, nil, nil, 0, credits.RoundingPolicy{}, nil, &ChallengeService{}, logger.New("error"))
+
+	for name, activity := range map[string]*models.EcoActivity{
+		"unverified":            {IsVerified: false},
+		"outside credit window": {IsVerified: true, OutsideCreditWindow: true},
+	} {
+		func() {
+			defer func() {
+				if recover() != nil {
+					t.Errorf("%s: expected the activity not to count towards challenges", name)
+				}
+			}()
+			s.recordChallengeProgress(context.Background(), activity, models.ActivityBiking)
+		}()
+	}
+}
+
+func TestRankUsers(t *testing.T) {
+	rankings := []*models.UserRanking{
+		{UserID: "alice", Total: 42},
+		{UserID: "bob", Total: 30},
+		{UserID: "carol", Total: 30},
+		{UserID: "dave", Total: 12.5},
+	}
+
+	rankUsers(rankings)
+
+	want := map[string]int{"alice": 1, "bob": 2, "carol": 2, "dave": 4}
+	for _, ranking := range rankings {
+		if ranking.Rank != want[ranking.UserID] {
+			t.Errorf("Expected %s ranked %d, got %d", ranking.UserID, want[ranking.UserID], ranking.Rank)
+		}
+	}
+}
+
+func TestLeaderboardSince(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		period string
+		want   time.Time
+	}{
+		{LeaderboardDaily, now.AddDate(0, 0, -1)},
+		{LeaderboardWeekly, now.AddDate(0, 0, -7)},
+		{LeaderboardMonthly, now.AddDate(0, 0, -30)},
+	}
+
+	for _, tt := range tests {
+		since, err := leaderboardSince(tt.period, now)
+		if err != nil || since == nil || !since.Equal(tt.want) {
+			t.Errorf("%s: expected window from %v, got %v (%v)", tt.period, tt.want, since, err)
+		}
+	}
+
+	if since, err := leaderboardSince(LeaderboardAllTime, now); err != nil || since != nil {
+		t.Errorf("Expected all_time to be unbounded, got %v (%v)", since, err)
+	}
+	if _, err := leaderboardSince("yearly", now); !errors.Is(err, ErrInvalidLeaderboard) {
+		t.Errorf("Expected ErrInvalidLeaderboard for an unknown period, got %v", err)
+	}
+}
+
+func TestComputeStreak(t *testing.T) {
+	now := time.Date(2024, 6, 15, 18, 0, 0, 0, time.UTC)
+	day := func(offset int) time.Time {
+		return time.Date(2024, 6, 15, 9, 0, 0, 0, time.UTC).AddDate(0, 0, offset)
+	}
+
+	tests := []struct {
+		name    string
+		times   []time.Time
+		current int
+		longest int
+	}{
+		{"no activities", nil, 0, 0},
+		{"active today", []time.Time{day(-2), day(-1), day(0)}, 3, 3},
+		{"several a day", []time.Time{day(-1), day(-1), day(0), day(0)}, 2, 2},
+		{"last active yesterday", []time.Time{day(-3), day(-2), day(-1)}, 3, 3},
+		{"gap breaks the streak", []time.Time{day(-6), day(-5), day(-4), day(-3), day(-1), day(0)}, 2, 4},
+		{"lapsed", []time.Time{day(-5), day(-4), day(-3)}, 0, 3},
+	}
+
+	for _, tt := range tests {
+		st := computeStreak(tt.times, now, time.UTC)
+		if st.current != tt.current || st.longest != tt.longest {
+			t.Errorf("%s: expected current %d and longest %d, got %d and %d",
+				tt.name, tt.current, tt.longest, st.current, st.longest)
+		}
+	}
+
+	st := computeStreak([]time.Time{day(-6), day(-5), day(-1), day(0)}, now, time.UTC)
+	if !st.startedAt.Equal(day(-1)) {
+		t.Errorf("Expected the current streak to start at %v, got %v", day(-1), st.startedAt)
+	}
+}
+
+func TestComputeStreak_UsesUserTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+
+	// 20:00 and 23:00 UTC on June 13 fall on June 14 in Tokyo, and 16:00 UTC
+	// on June 14 falls on June 15 there
+	times := []time.Time{
+		time.Date(2024, 6, 13, 20, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 13, 23, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 14, 16, 0, 0, 0, time.UTC),
+	}
+	now := time.Date(2024, 6, 14, 17, 0, 0, 0, time.UTC)
+
+	if st := computeStreak(times, now, time.UTC); st.current != 2 {
+		t.Errorf("Expected a 2-day streak in UTC, got %d", st.current)
+	}
+	st := computeStreak(times, now, tokyo)
+	if st.current != 2 || st.lastActive.Format("2006-01-02") != "2024-06-15" {
+		t.Errorf("Expected a 2-day streak ending June 15 in Tokyo, got %d ending %v", st.current, st.lastActive)
+	}
+}
+
+func TestStreakMilestoneBonuses(t *testing.T) {
+	if reached := reachedMilestones(6); len(reached) != 0 {
+		t.Errorf("Expected no milestone at 6 days, got %v", reached)
+	}
+	if reached := reachedMilestones(30); len(reached) != 2 || reached[1].Days != 30 {
+		t.Errorf("Expected the 7 and 30 day milestones at 30 days, got %v", reached)
+	}
+
+	startedAt := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	reward := &models.StreakReward{UserID: "test-user-123", Milestone: 7, StreakStartedAt: startedAt, Credits: 5}
+	event := streakBonusEvent(reward)
+
+	if event.Source != StreakBonusSource || event.CreditsEarned != 5 || event.UserID != "test-user-123" {
+		t.Errorf("Expected a 5 credit streak bonus for the user, got %+v", event)
+	}
+	if again := streakBonusEvent(reward); again.ActivityID != event.ActivityID {
+		t.Errorf("Expected the same milestone of a streak to keep its reference, got %s and %s", event.ActivityID, again.ActivityID)
+	}
+	next := &models.StreakReward{UserID: "test-user-123", Milestone: 7, StreakStartedAt: startedAt.AddDate(0, 1, 0), Credits: 5}
+	if streakBonusEvent(next).ActivityID == event.ActivityID {
+		t.Error("Expected a later streak to earn the milestone again")
+	}
+}
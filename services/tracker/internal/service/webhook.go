@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// ErrUnknownWebhookProvider is returned for deliveries from a provider that
+// is not registered or has no secret configured
+var ErrUnknownWebhookProvider = errors.New("unknown webhook provider")
+
+// ErrInvalidWebhookSignature is returned when a delivery's signature or
+// timestamp is missing, wrong, or too old to be accepted
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// ErrInvalidWebhookPayload is returned when a verified delivery cannot be
+// mapped to an activity
+var ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
+
+// WebhookProvider maps a third-party integration's payloads to activities.
+// Implement it and register it with a WebhookRegistry to add an integration.
+type WebhookProvider interface {
+	// Name identifies the provider in webhook URLs and configuration, and
+	// is recorded as the source of the activities it reports
+	Name() string
+
+	// ParseEvent decodes a payload whose signature was verified into the
+	// delivery's unique event ID and the activity it reports
+	ParseEvent(payload []byte) (string, *LogActivityRequest, error)
+}
+
+// webhookIntegration is a registered provider with its signing secret
+type webhookIntegration struct {
+	provider WebhookProvider
+	secret   []byte
+}
+
+// WebhookRegistry holds the webhook providers deliveries are accepted from
+type WebhookRegistry struct {
+	integrations map[string]webhookIntegration
+}
+
+// NewWebhookRegistry creates an empty webhook registry
+func NewWebhookRegistry() *WebhookRegistry {
+	return &WebhookRegistry{integrations: make(map[string]webhookIntegration)}
+}
+
+// Register accepts deliveries from provider signed with secret
+func (r *WebhookRegistry) Register(provider WebhookProvider, secret string) {
+	r.integrations[provider.Name()] = webhookIntegration{
+		provider: provider,
+		secret:   []byte(secret),
+	}
+}
+
+// WebhookResult describes how an accepted delivery was handled
+type WebhookResult struct {
+	EventID string `json:"event_id"`
+	// Duplicate is set when the event was already processed and was ignored
+	Duplicate bool              `json:"duplicate"`
+	Activity  *ActivityResponse `json:"activity,omitempty"`
+}
+
+// WebhookService verifies webhook deliveries from third-party providers and
+// logs the activities they report
+type WebhookService struct {
+	registry       *WebhookRegistry
+	eventRepo      *repository.WebhookEventRepository
+	trackerService *TrackerService
+	tolerance      time.Duration
+	logger         *logger.Logger
+}
+
+// NewWebhookService creates a new webhook service. Deliveries signed more
+// than tolerance away from the current time are rejected.
+func NewWebhookService(
+	registry *WebhookRegistry,
+	eventRepo *repository.WebhookEventRepository,
+	trackerService *TrackerService,
+	tolerance time.Duration,
+	logger *logger.Logger,
+) *WebhookService {
+	return &WebhookService{
+		registry:       registry,
+		eventRepo:      eventRepo,
+		trackerService: trackerService,
+		tolerance:      tolerance,
+		logger:         logger,
+	}
+}
+
+// HandleWebhook verifies a delivery from providerName and logs the activity
+// it reports with the provider as its source. Each event is logged once;
+// later deliveries of it are reported as duplicates.
+func (s *WebhookService) HandleWebhook(ctx context.Context, providerName, timestamp, signature string, payload []byte) (*WebhookResult, error) {
+	integration, ok := s.registry.integrations[providerName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownWebhookProvider, providerName)
+	}
+
+	now := time.Now().UTC()
+	if err := verifyWebhook(integration.secret, timestamp, signature, payload, now, s.tolerance); err != nil {
+		s.logger.LogWarn(ctx, "rejected webhook delivery",
+			logger.String("provider", providerName),
+			logger.String("reason", err.Error()))
+		return nil, err
+	}
+
+	eventID, req, err := integration.provider.ParseEvent(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, err)
+	}
+	req.Source = providerName
+
+	recorded, err := s.eventRepo.Record(ctx, providerName, eventID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		s.logger.LogInfo(ctx, "ignored duplicate webhook event",
+			logger.String("provider", providerName),
+			logger.String("event_id", eventID))
+		return &WebhookResult{EventID: eventID, Duplicate: true}, nil
+	}
+
+	activity, err := s.trackerService.LogActivity(ctx, req)
+	if err != nil {
+		// Forget the event so the provider's retry is processed
+		if deleteErr := s.eventRepo.Delete(ctx, providerName, eventID); deleteErr != nil {
+			s.logger.LogError(ctx, "failed to release webhook event", deleteErr,
+				logger.String("provider", providerName),
+				logger.String("event_id", eventID))
+		}
+		return nil, err
+	}
+
+	return &WebhookResult{EventID: eventID, Activity: activity}, nil
+}
+
+// webhookSignature returns the hex HMAC-SHA256, keyed by secret, of the
+// timestamp and payload joined by a dot
+func webhookSignature(secret []byte, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyWebhook checks a delivery's signature and that its Unix timestamp is
+// within tolerance of now. The timestamp is signed with the payload, so an
+// old delivery cannot be replayed with a fresh one.
+func verifyWebhook(secret []byte, timestamp, signature string, payload []byte, now time.Time, tolerance time.Duration) error {
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("%w: signature and timestamp are required", ErrInvalidWebhookSignature)
+	}
+
+	expected := webhookSignature(secret, timestamp, payload)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidWebhookSignature)
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp", ErrInvalidWebhookSignature)
+	}
+	skew := now.Sub(time.Unix(seconds, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > tolerance {
+		return fmt.Errorf("%w: timestamp is %s from now", ErrInvalidWebhookSignature, skew.Round(time.Second))
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/tracker/internal/models"
+)
+
+// stravaActivityTypes maps Strava sport types to the activity types they are
+// logged as. Other sport types are rejected.
+var stravaActivityTypes = map[string]string{
+	"Ride": models.ActivityBiking,
+	"Walk": models.ActivityWalking,
+	"Hike": models.ActivityWalking,
+	"Run":  models.ActivityWalking,
+}
+
+// StravaProvider maps Strava activity webhooks to activities
+type StravaProvider struct{}
+
+// stravaEvent is the payload Strava deliveries are relayed in. UserID is the
+// GreenLedger user the athlete linked their account to.
+type stravaEvent struct {
+	EventID  string `json:"event_id"`
+	UserID   string `json:"user_id"`
+	Activity struct {
+		ID         int64     `json:"id"`
+		Type       string    `json:"type"`
+		Name       string    `json:"name"`
+		Distance   float64   `json:"distance"`    // in meters
+		MovingTime int       `json:"moving_time"` // in seconds
+		StartDate  time.Time `json:"start_date"`
+	} `json:"activity"`
+}
+
+// Name returns "strava"
+func (StravaProvider) Name() string {
+	return "strava"
+}
+
+// ParseEvent decodes a Strava activity into a LogActivityRequest
+func (StravaProvider) ParseEvent(payload []byte) (string, *LogActivityRequest, error) {
+	var event stravaEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return "", nil, fmt.Errorf("malformed JSON: %w", err)
+	}
+	if event.EventID == "" || event.UserID == "" {
+		return "", nil, errors.New("event_id and user_id are required")
+	}
+
+	activityType, ok := stravaActivityTypes[event.Activity.Type]
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported activity type %q", event.Activity.Type)
+	}
+
+	description := event.Activity.Name
+	if description == "" {
+		description = fmt.Sprintf("Strava %s", event.Activity.Type)
+	}
+
+	req := &LogActivityRequest{
+		UserID:       event.UserID,
+		ActivityType: activityType,
+		Description:  description,
+		Duration:     event.Activity.MovingTime / 60,
+		Distance:     event.Activity.Distance / 1000,
+		SourceData: map[string]interface{}{
+			"strava_activity_id": event.Activity.ID,
+			"strava_type":        event.Activity.Type,
+		},
+	}
+	if !event.Activity.StartDate.IsZero() {
+		req.OccurredAt = &event.Activity.StartDate
+	}
+
+	return event.EventID, req, nil
+}
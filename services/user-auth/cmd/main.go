@@ -3,11 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,8 +15,25 @@ import (
 	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/service"
 	"github.com/sloweyyy/GreenLedger/shared/config"
 	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/events"
+	"github.com/sloweyyy/GreenLedger/shared/featureflags"
 	sharedLogger "github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/sloweyyy/GreenLedger/shared/middleware"
+	"github.com/sloweyyy/GreenLedger/shared/proto/usererasure"
+	sharedServer "github.com/sloweyyy/GreenLedger/shared/server"
+	"github.com/sloweyyy/GreenLedger/shared/version"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// outboxRelayInterval is how often unpublished outbox events are published
+const outboxRelayInterval = 5 * time.Second
+
+// Build information, injected at build time via -ldflags "-X main.Version=..."
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
 )
 
 // @title GreenLedger User Authentication Service API
@@ -62,7 +77,20 @@ func main() {
 		logger.LogError(context.Background(), "failed to connect to database", err)
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer db.Close()
+
+	// Users are only erased once the wallet and certifier report that they
+	// hold no credits or active certificates
+	dial := func(name, addr string) *grpc.ClientConn {
+		conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			logger.LogError(context.Background(), "failed to create gRPC client", err,
+				sharedLogger.String("service", name))
+			log.Fatalf("Failed to create %s gRPC client: %v", name, err)
+		}
+		return conn
+	}
+	walletConn := dial("wallet", cfg.Auth.WalletGRPCAddr)
+	certifierConn := dial("certifier", cfg.Auth.CertifierGRPCAddr)
 
 	// Run database migrations
 	if err := db.Migrate(
@@ -73,6 +101,7 @@ func main() {
 		&models.UserProfile{},
 		&models.PasswordResetToken{},
 		&models.EmailVerificationToken{},
+		&events.OutboxEvent{},
 	); err != nil {
 		logger.LogError(context.Background(), "failed to run migrations", err)
 		log.Fatalf("Failed to run migrations: %v", err)
@@ -84,25 +113,57 @@ func main() {
 	roleRepo := repository.NewRoleRepository(db, logger)
 	permissionRepo := repository.NewPermissionRepository(db, logger)
 
+	// Initialize event publisher
+	var eventPublisher events.UserEventPublisher
+	if cfg.Features.Enabled(featureflags.Kafka) {
+		eventPublisher = events.NewKafkaUserEventPublisher(cfg.Kafka.Brokers, logger)
+	} else {
+		eventPublisher = events.NewMockUserEventPublisher(logger)
+	}
+
+	// Verification emails are logged until an email provider is configured
+	emailSender := service.NewLogEmailSender(logger)
+
 	// Initialize services
-	authService := service.NewAuthService(userRepo, sessionRepo, roleRepo, cfg.Server.JWTSecret, logger)
-	userService := service.NewUserService(userRepo, logger)
+	erasureGuard := service.NewGRPCErasureGuard(
+		service.ErasureCheck{Service: "wallet", Client: usererasure.NewUserErasureClient(walletConn)},
+		service.ErasureCheck{Service: "certifier", Client: usererasure.NewUserErasureClient(certifierConn)},
+	)
+	lockout := service.LockoutPolicy{
+		MaxFailedAttempts: cfg.Auth.MaxFailedLogins,
+		Duration:          cfg.Auth.LockoutDuration,
+	}
+	authService := service.NewAuthService(userRepo, sessionRepo, roleRepo, emailSender, lockout, cfg.Server.JWTSecret, cfg.Server.JWTIssuer, cfg.Server.JWTAudience, logger)
+	userService := service.NewUserService(userRepo, sessionRepo, erasureGuard, logger)
+
+	// Seed default roles and permissions before serving so the first
+	// registrations can be assigned a role
+	if err := initializeRolesAndPermissions(context.Background(), roleRepo, permissionRepo, logger); err != nil {
+		logger.LogError(context.Background(), "failed to initialize roles and permissions", err)
+		log.Fatalf("Failed to initialize roles and permissions: %v", err)
+	}
 
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(cfg.Server.JWTSecret, logger)
+	authMiddleware := middleware.NewAuthMiddleware(cfg.Server.JWTSecret, cfg.Server.JWTIssuer, cfg.Server.JWTAudience, logger)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService, userService, logger)
 
 	// Setup Gin router
-	if cfg.Server.Environment == "production" {
+	if cfg.Features.Enabled(featureflags.ReleaseMode) {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.LogError(context.Background(), "failed to set trusted proxies", err)
+		log.Fatalf("Failed to set trusted proxies: %v", err)
+	}
 	router.Use(gin.Recovery())
-	router.Use(middleware.RequestLogger(logger))
+	router.Use(middleware.RequestLogger(logger, cfg.Server.SlowRequestThreshold))
+	router.Use(middleware.Pagination(cfg.Pagination.DefaultLimit, cfg.Pagination.MaxLimit))
 	router.Use(middleware.CORS())
+	router.Use(middleware.MaxBodySize(cfg.Server.MaxBodyBytes))
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -116,10 +177,13 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "healthy",
 			"service": "user-auth",
-			"version": "1.0.0",
+			"version": Version,
 		})
 	})
 
+	// Build info endpoint
+	router.GET("/version", version.Handler(version.New("user-auth", Version, GitCommit, BuildTime)))
+
 	// API routes
 	v1 := router.Group("/api/v1")
 	authHandler.RegisterRoutes(v1, authMiddleware)
@@ -133,39 +197,32 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	// Start server in a goroutine
-	go func() {
-		logger.LogInfo(context.Background(), "starting user-auth service",
-			sharedLogger.Int("port", cfg.Server.Port),
-			sharedLogger.String("environment", cfg.Server.Environment))
+	// Cancelled on SIGINT/SIGTERM so background consumers stop before their
+	// readers, the event publisher and the database are closed
+	ctx, stop := sharedServer.NotifyContext(context.Background())
+	defer stop()
 
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.LogError(context.Background(), "failed to start server", err)
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
-
-	// Initialize default roles and permissions
-	go func() {
-		if err := initializeRolesAndPermissions(context.Background(), roleRepo, permissionRepo, logger); err != nil {
-			logger.LogError(context.Background(), "failed to initialize roles and permissions", err)
-		}
-	}()
+	// User deleted events are written to the outbox with the erasure and
+	// published from it in the background
+	outboxRelay := events.NewOutboxRelay(db, logger)
+	outboxRelay.Register(events.EventTypeUserDeleted, events.PublishUserDeletedFromOutbox(eventPublisher))
+	go outboxRelay.Run(ctx, outboxRelayInterval)
 
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	var closers []io.Closer
 
-	logger.LogInfo(context.Background(), "shutting down user-auth service")
+	// Close the event publisher once the outbox relay has stopped
+	if closer, ok := eventPublisher.(io.Closer); ok {
+		closers = append(closers, closer)
+	}
+	closers = append(closers, walletConn, certifierConn, db)
 
-	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	logger.LogInfo(context.Background(), "starting user-auth service",
+		sharedLogger.Int("port", cfg.Server.Port),
+		sharedLogger.String("environment", cfg.Server.Environment),
+		sharedLogger.Any("features", cfg.Features.EnabledNames()))
 
-	if err := server.Shutdown(ctx); err != nil {
-		logger.LogError(context.Background(), "server forced to shutdown", err)
-		log.Fatalf("Server forced to shutdown: %v", err)
+	if err := sharedServer.RunWithGracefulShutdown(ctx, server, logger, closers...); err != nil {
+		log.Fatalf("user-auth service stopped with error: %v", err)
 	}
 
 	logger.LogInfo(context.Background(), "user-auth service stopped")
@@ -1,28 +1,67 @@
 package handler
 
 import (
+	"context"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/service"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/httperr"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/sloweyyy/GreenLedger/shared/middleware"
 )
 
+// UserService is the user management used by the handler. It is implemented
+// by *service.UserService.
+type UserService interface {
+	GetByID(ctx context.Context, userID string) (*service.UserResponse, error)
+	UpdateProfile(ctx context.Context, userID string, req *service.UpdateProfileRequest) (*service.UserResponse, error)
+	ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error
+	ListSessions(ctx context.Context, userID string) ([]*service.SessionResponse, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	ListUsers(ctx context.Context, search string, limit, offset int) ([]*service.UserResponse, int64, error)
+	SearchUsers(ctx context.Context, query string, limit, offset int) ([]*service.UserResponse, int64, error)
+	UpdateUser(ctx context.Context, userID string, req *service.UpdateUserRequest) (*service.UserResponse, error)
+	DeleteUser(ctx context.Context, userID, deletedBy string) error
+	AssignRole(ctx context.Context, userID, roleID string) error
+	RemoveRole(ctx context.Context, userID, roleID string) error
+}
+
 // AuthHandler handles HTTP requests for authentication
 type AuthHandler struct {
 	authService *service.AuthService
-	userService *service.UserService
+	userService UserService
+	errMapper   *httperr.Mapper
 	logger      *logger.Logger
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService *service.AuthService, userService *service.UserService, logger *logger.Logger) *AuthHandler {
+func NewAuthHandler(authService *service.AuthService, userService UserService, logger *logger.Logger) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
 		userService: userService,
-		logger:      logger,
+		errMapper: httperr.New(logger,
+			httperr.Mapping{Err: service.ErrUserExists, Status: http.StatusConflict, Message: "User already exists"},
+			httperr.Mapping{Err: service.ErrInvalidCredentials, Status: http.StatusUnauthorized, Message: "Invalid credentials"},
+			httperr.Mapping{Err: service.ErrAccountLocked, Status: http.StatusLocked, Message: "Account temporarily locked after too many failed logins"},
+			httperr.Mapping{Err: service.ErrAccountDeactivated, Status: http.StatusUnauthorized, Message: "Account is deactivated"},
+			httperr.Mapping{Err: service.ErrInvalidRefreshToken, Status: http.StatusUnauthorized, Message: "Invalid refresh token"},
+			httperr.Mapping{Err: service.ErrInvalidVerificationToken, Status: http.StatusBadRequest, Message: "Invalid or expired verification token"},
+			httperr.Mapping{Err: service.ErrAlreadyVerified, Status: http.StatusConflict, Message: "Email already verified"},
+			httperr.Mapping{Err: service.ErrInvalidResetToken, Status: http.StatusBadRequest, Message: "Invalid or expired password reset token"},
+			httperr.Mapping{Err: service.ErrWeakPassword, Status: http.StatusBadRequest, Message: "Password must be at least 8 characters"},
+			httperr.Mapping{Err: service.ErrInvalidPassword, Status: http.StatusBadRequest, Message: "Invalid current password"},
+			httperr.Mapping{Err: service.ErrInvalidSearchQuery, Status: http.StatusBadRequest, Message: "Invalid search query"},
+			httperr.Mapping{Err: service.ErrErasureBlocked, Status: http.StatusConflict, Message: "User cannot be deleted yet"},
+			httperr.Mapping{Err: service.ErrSessionNotFound, Status: http.StatusNotFound, Message: "Session not found"},
+			httperr.Mapping{Err: service.ErrRoleNotFound, Status: http.StatusNotFound, Message: "Role not found"},
+			httperr.Mapping{Err: service.ErrRoleNotAssigned, Status: http.StatusNotFound, Message: "User does not have this role"},
+			httperr.Mapping{Err: service.ErrRoleAlreadyAssigned, Status: http.StatusConflict, Message: "User already has this role"},
+			httperr.Mapping{Err: database.ErrNotFound, Status: http.StatusNotFound, Message: "User not found"},
+		),
+		logger: logger,
 	}
 }
 
@@ -35,6 +74,9 @@ func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *mi
 		auth.POST("/login", h.Login)
 		auth.POST("/refresh", h.RefreshToken)
 		auth.POST("/logout", h.Logout)
+		auth.POST("/verify-email", h.VerifyEmail)
+		auth.POST("/forgot-password", h.ForgotPassword)
+		auth.POST("/reset-password", h.ResetPassword)
 
 		// Protected routes
 		protected := auth.Group("")
@@ -43,6 +85,8 @@ func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *mi
 			protected.GET("/profile", h.GetProfile)
 			protected.PUT("/profile", h.UpdateProfile)
 			protected.POST("/change-password", h.ChangePassword)
+			protected.POST("/logout-all", h.LogoutAll)
+			protected.POST("/resend-verification", h.ResendVerification)
 			protected.GET("/sessions", h.GetSessions)
 			protected.DELETE("/sessions/:id", h.DeleteSession)
 		}
@@ -53,6 +97,7 @@ func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *mi
 		admin.Use(authMiddleware.RequireRole("admin"))
 		{
 			admin.GET("/users", h.ListUsers)
+			admin.GET("/users/search", h.SearchUsers)
 			admin.GET("/users/:id", h.GetUser)
 			admin.PUT("/users/:id", h.UpdateUser)
 			admin.DELETE("/users/:id", h.DeleteUser)
@@ -88,18 +133,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	response, err := h.authService.Register(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "registration failed", err,
+		h.errMapper.Respond(c, err, "Registration failed",
 			logger.String("email", req.Email))
-
-		statusCode := http.StatusInternalServerError
-		if strings.Contains(err.Error(), "already exists") {
-			statusCode = http.StatusConflict
-		}
-
-		c.JSON(statusCode, ErrorResponse{
-			Error:   "Registration failed",
-			Details: err.Error(),
-		})
 		return
 	}
 
@@ -116,6 +151,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Success 200 {object} service.AuthResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 423 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -132,19 +168,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	response, err := h.authService.Login(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "login failed", err,
+		h.errMapper.Respond(c, err, "Login failed",
 			logger.String("email", req.Email))
-
-		statusCode := http.StatusInternalServerError
-		if strings.Contains(err.Error(), "invalid credentials") ||
-			strings.Contains(err.Error(), "deactivated") {
-			statusCode = http.StatusUnauthorized
-		}
-
-		c.JSON(statusCode, ErrorResponse{
-			Error:   "Login failed",
-			Details: err.Error(),
-		})
 		return
 	}
 
@@ -176,11 +201,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 
 	response, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "token refresh failed", err)
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
-			Error:   "Token refresh failed",
-			Details: err.Error(),
-		})
+		h.errMapper.Respond(c, err, "Token refresh failed")
 		return
 	}
 
@@ -210,11 +231,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	}
 
 	if err := h.authService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
-		h.logger.LogError(c.Request.Context(), "logout failed", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Logout failed",
-			Details: err.Error(),
-		})
+		h.errMapper.Respond(c, err, "Logout failed")
 		return
 	}
 
@@ -223,6 +240,40 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	})
 }
 
+// LogoutAll godoc
+// @Summary Logout from all devices
+// @Description Invalidate every refresh token of the authenticated user. Access tokens already issued stay valid until they expire.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} SuccessResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	if err := h.authService.LogoutAll(c.Request.Context(), id); err != nil {
+		h.errMapper.Respond(c, err, "Logout failed",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Logged out from all devices",
+	})
+}
+
 // GetProfile godoc
 // @Summary Get user profile
 // @Description Get authenticated user's profile
@@ -242,12 +293,8 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 
 	user, err := h.userService.GetByID(c.Request.Context(), userID)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to get user profile", err,
+		h.errMapper.Respond(c, err, "Failed to get profile",
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to get profile",
-			Details: err.Error(),
-		})
 		return
 	}
 
@@ -285,12 +332,8 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 
 	user, err := h.userService.UpdateProfile(c.Request.Context(), userID, &req)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to update profile", err,
+		h.errMapper.Respond(c, err, "Failed to update profile",
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to update profile",
-			Details: err.Error(),
-		})
 		return
 	}
 
@@ -299,7 +342,7 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 
 // ChangePassword godoc
 // @Summary Change user password
-// @Description Change authenticated user's password
+// @Description Change authenticated user's password. Every session is signed out, so the user has to log in again.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -327,57 +370,455 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	}
 
 	if err := h.userService.ChangePassword(c.Request.Context(), userID, req.CurrentPassword, req.NewPassword); err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to change password", err,
+		h.errMapper.Respond(c, err, "Failed to change password",
 			logger.String("user_id", userID))
+		return
+	}
 
-		statusCode := http.StatusInternalServerError
-		if strings.Contains(err.Error(), "invalid password") {
-			statusCode = http.StatusBadRequest
-		}
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Password changed successfully",
+	})
+}
 
-		c.JSON(statusCode, ErrorResponse{
-			Error:   "Failed to change password",
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Verify the email address of the user a verification token was sent to. Tokens are single-use and expire after 24 hours.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyEmailRequest true "Verification token"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
 			Details: err.Error(),
 		})
 		return
 	}
 
+	if err := h.authService.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		h.errMapper.Respond(c, err, "Email verification failed")
+		return
+	}
+
 	c.JSON(http.StatusOK, SuccessResponse{
-		Message: "Password changed successfully",
+		Message: "Email verified successfully",
 	})
 }
 
-// Placeholder implementations for remaining endpoints
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Send the authenticated user a new verification email. Links from earlier emails stop working.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} SuccessResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /auth/resend-verification [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	if err := h.authService.SendVerificationEmail(c.Request.Context(), id); err != nil {
+		h.errMapper.Respond(c, err, "Failed to send verification email",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Verification email sent",
+	})
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Email a password reset token to the account with this email, if there is one. The response is the same either way.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ForgotPasswordRequest true "Account email"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		h.errMapper.Respond(c, err, "Failed to request password reset")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "If an account exists for this email, a password reset link has been sent",
+	})
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password with an emailed reset token. Tokens are single-use and expire after one hour, and every session of the user is signed out.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		h.errMapper.Respond(c, err, "Password reset failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Password reset successfully",
+	})
+}
+
+// GetSessions godoc
+// @Summary List sessions
+// @Description List the authenticated user's active sessions. Refresh tokens are not returned, only a short prefix of each.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} SessionListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /auth/sessions [get]
 func (h *AuthHandler) GetSessions(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Get sessions - to be implemented"})
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	sessions, err := h.userService.ListSessions(c.Request.Context(), userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get sessions",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, SessionListResponse{Sessions: sessions})
 }
 
+// DeleteSession godoc
+// @Summary Revoke a session
+// @Description Sign the authenticated user out of one of their sessions
+// @Tags auth
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /auth/sessions/{id} [delete]
 func (h *AuthHandler) DeleteSession(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Delete session - to be implemented"})
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	if err := h.userService.RevokeSession(c.Request.Context(), userID, c.Param("id")); err != nil {
+		h.errMapper.Respond(c, err, "Failed to revoke session",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Session revoked successfully",
+	})
 }
 
+// ListUsers godoc
+// @Summary List users
+// @Description List users newest first, optionally only those whose email or username starts with search
+// @Tags admin
+// @Produce json
+// @Param search query string false "Email or username prefix"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} UserListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /auth/admin/users [get]
 func (h *AuthHandler) ListUsers(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "List users - to be implemented"})
+	limit, offset := middleware.GetPagination(c)
+
+	users, total, err := h.userService.ListUsers(c.Request.Context(), c.Query("search"), limit, offset)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to list users")
+		return
+	}
+
+	c.JSON(http.StatusOK, UserListResponse{
+		Users:    users,
+		PageInfo: middleware.NewPageInfo(total, limit, offset),
+	})
+}
+
+// SearchUsers godoc
+// @Summary Search users
+// @Description Find users whose email or username starts with the query, ignoring case
+// @Tags admin
+// @Produce json
+// @Param q query string true "Email or username prefix"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} UserListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /auth/admin/users/search [get]
+func (h *AuthHandler) SearchUsers(c *gin.Context) {
+	limit, offset := middleware.GetPagination(c)
+
+	users, total, err := h.userService.SearchUsers(c.Request.Context(), c.Query("q"), limit, offset)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to search users")
+		return
+	}
+
+	c.JSON(http.StatusOK, UserListResponse{
+		Users:    users,
+		PageInfo: middleware.NewPageInfo(total, limit, offset),
+	})
 }
 
+// GetUser godoc
+// @Summary Get a user
+// @Description Get any user by ID
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} service.UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /auth/admin/users/{id} [get]
 func (h *AuthHandler) GetUser(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Get user - to be implemented"})
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get user",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
 }
 
+// UpdateUser godoc
+// @Summary Update a user
+// @Description Update a user's name or email, activate or deactivate them, or set whether their email is verified
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body service.UpdateUserRequest true "Fields to update"
+// @Success 200 {object} service.UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /auth/admin/users/{id} [put]
 func (h *AuthHandler) UpdateUser(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Update user - to be implemented"})
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	user, err := h.userService.UpdateUser(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to update user",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
 }
 
+// DeleteUser godoc
+// @Summary Delete a user
+// @Description Erase a user account and request erasure of their data in every service
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /auth/admin/users/{id} [delete]
 func (h *AuthHandler) DeleteUser(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Delete user - to be implemented"})
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	adminID, _ := middleware.GetUserID(c)
+
+	if err := h.userService.DeleteUser(c.Request.Context(), userID, adminID); err != nil {
+		h.errMapper.Respond(c, err, "Failed to delete user",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "User deleted successfully",
+	})
 }
 
+// AssignRole godoc
+// @Summary Assign a role
+// @Description Give a user a role
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body AssignRoleRequest true "Role to assign"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /auth/admin/users/{id}/roles [post]
 func (h *AuthHandler) AssignRole(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Assign role - to be implemented"})
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.userService.AssignRole(c.Request.Context(), userID, req.RoleID); err != nil {
+		h.errMapper.Respond(c, err, "Failed to assign role",
+			logger.String("user_id", userID),
+			logger.String("role_id", req.RoleID))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Role assigned successfully",
+	})
 }
 
+// RemoveRole godoc
+// @Summary Remove a role
+// @Description Take a role away from a user
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Param role_id path string true "Role ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /auth/admin/users/{id}/roles/{role_id} [delete]
 func (h *AuthHandler) RemoveRole(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Remove role - to be implemented"})
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	roleID := c.Param("role_id")
+	if _, err := uuid.Parse(roleID); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid role ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.userService.RemoveRole(c.Request.Context(), userID, roleID); err != nil {
+		h.errMapper.Respond(c, err, "Failed to remove role",
+			logger.String("user_id", userID),
+			logger.String("role_id", roleID))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Role removed successfully",
+	})
+}
+
+// userIDParam returns the user ID path parameter, responding 400 if it is
+// not a UUID
+func userIDParam(c *gin.Context) (string, bool) {
+	userID := c.Param("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid user ID",
+			Details: err.Error(),
+		})
+		return "", false
+	}
+	return userID, true
 }
 
 // Request/Response types
@@ -385,16 +826,40 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"current_password" binding:"required"`
 	NewPassword     string `json:"new_password" binding:"required,min=8"`
 }
 
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Details string `json:"details,omitempty"`
+type AssignRoleRequest struct {
+	RoleID string `json:"role_id" binding:"required,uuid"`
 }
 
+type SessionListResponse struct {
+	Sessions []*service.SessionResponse `json:"sessions"`
+}
+
+// UserListResponse represents a paginated list of users
+type UserListResponse struct {
+	Users []*service.UserResponse `json:"users"`
+	middleware.PageInfo
+}
+
+type ErrorResponse = httperr.ErrorResponse
+
 type SuccessResponse struct {
 	Message string `json:"message"`
 }
@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/service"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// mockUserService implements the admin operations of UserService with
+// in-memory users and role assignments
+type mockUserService struct {
+	UserService
+
+	users map[string]*service.UserResponse
+	// roles holds the role IDs assigned to each user ID
+	roles map[string]map[string]bool
+	// knownRoles holds the role IDs that exist
+	knownRoles map[string]bool
+	// lastSearch records the search passed to ListUsers
+	lastSearch string
+}
+
+func newMockUserService() *mockUserService {
+	return &mockUserService{
+		users:      map[string]*service.UserResponse{},
+		roles:      map[string]map[string]bool{},
+		knownRoles: map[string]bool{},
+	}
+}
+
+func (m *mockUserService) addUser(email string) string {
+	id := uuid.New()
+	m.users[id.String()] = &service.UserResponse{ID: id, Email: email, IsActive: true}
+	m.roles[id.String()] = map[string]bool{}
+	return id.String()
+}
+
+func (m *mockUserService) ListUsers(ctx context.Context, search string, limit, offset int) ([]*service.UserResponse, int64, error) {
+	m.lastSearch = search
+	var users []*service.UserResponse
+	for _, user := range m.users {
+		if strings.HasPrefix(user.Email, search) {
+			users = append(users, user)
+		}
+	}
+	return users, int64(len(users)), nil
+}
+
+func (m *mockUserService) GetByID(ctx context.Context, userID string) (*service.UserResponse, error) {
+	user, ok := m.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("failed to get user: %w", database.ErrNotFound)
+	}
+	return user, nil
+}
+
+func (m *mockUserService) UpdateUser(ctx context.Context, userID string, req *service.UpdateUserRequest) (*service.UserResponse, error) {
+	user, ok := m.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("failed to get user: %w", database.ErrNotFound)
+	}
+	for id, other := range m.users {
+		if id != userID && req.Email != "" && other.Email == req.Email {
+			return nil, fmt.Errorf("%w: email is taken", service.ErrUserExists)
+		}
+	}
+	if req.IsActive != nil {
+		user.IsActive = *req.IsActive
+	}
+	if req.IsVerified != nil {
+		user.IsVerified = *req.IsVerified
+	}
+	return user, nil
+}
+
+func (m *mockUserService) DeleteUser(ctx context.Context, userID, deletedBy string) error {
+	if _, ok := m.users[userID]; !ok {
+		return fmt.Errorf("failed to delete user: %w", database.ErrNotFound)
+	}
+	delete(m.users, userID)
+	return nil
+}
+
+func (m *mockUserService) AssignRole(ctx context.Context, userID, roleID string) error {
+	roles, ok := m.roles[userID]
+	if !ok {
+		return database.ErrNotFound
+	}
+	if !m.knownRoles[roleID] {
+		return service.ErrRoleNotFound
+	}
+	if roles[roleID] {
+		return service.ErrRoleAlreadyAssigned
+	}
+	roles[roleID] = true
+	return nil
+}
+
+func (m *mockUserService) RemoveRole(ctx context.Context, userID, roleID string) error {
+	roles, ok := m.roles[userID]
+	if !ok {
+		return database.ErrNotFound
+	}
+	if !m.knownRoles[roleID] {
+		return service.ErrRoleNotFound
+	}
+	if !roles[roleID] {
+		return service.ErrRoleNotAssigned
+	}
+	delete(roles, roleID)
+	return nil
+}
+
+func newAdminRouter(users UserService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	h := NewAuthHandler(nil, users, logger.New("error"))
+	router := gin.New()
+	router.GET("/users", h.ListUsers)
+	router.GET("/users/:id", h.GetUser)
+	router.PUT("/users/:id", h.UpdateUser)
+	router.DELETE("/users/:id", h.DeleteUser)
+	router.POST("/users/:id/roles", h.AssignRole)
+	router.DELETE("/users/:id/roles/:role_id", h.RemoveRole)
+	return router
+}
+
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	var request *http.Request
+	if body == "" {
+		request = httptest.NewRequest(method, path, nil)
+	} else {
+		request = httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestListUsers(t *testing.T) {
+	users := newMockUserService()
+	users.addUser("alice@example.com")
+	users.addUser("bob@example.com")
+	router := newAdminRouter(users)
+
+	recorder := serve(router, http.MethodGet, "/users?search=ali&limit=10", "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if users.lastSearch != "ali" {
+		t.Errorf("Expected search 'ali' passed to the service, got %q", users.lastSearch)
+	}
+
+	var response UserListResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Users) != 1 || response.Users[0].Email != "alice@example.com" {
+		t.Errorf("Expected only alice, got %+v", response.Users)
+	}
+	if response.Total != 1 || response.Limit != 10 {
+		t.Errorf("Expected total 1 and limit 10, got %+v", response.PageInfo)
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	users := newMockUserService()
+	id := users.addUser("alice@example.com")
+	router := newAdminRouter(users)
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"existing user", "/users/" + id, http.StatusOK},
+		{"missing user", "/users/" + uuid.New().String(), http.StatusNotFound},
+		{"invalid ID", "/users/not-a-uuid", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		if recorder := serve(router, http.MethodGet, tt.path, ""); recorder.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.want, recorder.Code)
+		}
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	users := newMockUserService()
+	id := users.addUser("alice@example.com")
+	users.addUser("bob@example.com")
+	router := newAdminRouter(users)
+
+	recorder := serve(router, http.MethodPut, "/users/"+id, `{"is_active": false, "is_verified": true}`)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if users.users[id].IsActive || !users.users[id].IsVerified {
+		t.Errorf("Expected user deactivated and verified, got %+v", users.users[id])
+	}
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{"email taken", "/users/" + id, `{"email": "bob@example.com"}`, http.StatusConflict},
+		{"invalid email", "/users/" + id, `{"email": "not-an-email"}`, http.StatusBadRequest},
+		{"missing user", "/users/" + uuid.New().String(), `{"is_active": true}`, http.StatusNotFound},
+		{"invalid ID", "/users/not-a-uuid", `{"is_active": true}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		if recorder := serve(router, http.MethodPut, tt.path, tt.body); recorder.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.want, recorder.Code)
+		}
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	users := newMockUserService()
+	id := users.addUser("alice@example.com")
+	router := newAdminRouter(users)
+
+	if recorder := serve(router, http.MethodDelete, "/users/"+id, ""); recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+	if recorder := serve(router, http.MethodDelete, "/users/"+id, ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 deleting a deleted user, got %d", recorder.Code)
+	}
+}
+
+func TestAssignRole(t *testing.T) {
+	users := newMockUserService()
+	id := users.addUser("alice@example.com")
+	roleID := uuid.New().String()
+	users.knownRoles[roleID] = true
+	router := newAdminRouter(users)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{"assign", "/users/" + id + "/roles", `{"role_id": "` + roleID + `"}`, http.StatusOK},
+		{"already assigned", "/users/" + id + "/roles", `{"role_id": "` + roleID + `"}`, http.StatusConflict},
+		{"unknown role", "/users/" + id + "/roles", `{"role_id": "` + uuid.New().String() + `"}`, http.StatusNotFound},
+		{"missing user", "/users/" + uuid.New().String() + "/roles", `{"role_id": "` + roleID + `"}`, http.StatusNotFound},
+		{"invalid role ID", "/users/" + id + "/roles", `{"role_id": "admin"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		if recorder := serve(router, http.MethodPost, tt.path, tt.body); recorder.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.want, recorder.Code)
+		}
+	}
+
+	if !users.roles[id][roleID] {
+		t.Error("Expected role to be assigned")
+	}
+}
+
+func TestRemoveRole(t *testing.T) {
+	users := newMockUserService()
+	id := users.addUser("alice@example.com")
+	roleID := uuid.New().String()
+	users.knownRoles[roleID] = true
+	users.roles[id][roleID] = true
+	router := newAdminRouter(users)
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"remove", "/users/" + id + "/roles/" + roleID, http.StatusOK},
+		{"not assigned", "/users/" + id + "/roles/" + roleID, http.StatusNotFound},
+		{"unknown role", "/users/" + id + "/roles/" + uuid.New().String(), http.StatusNotFound},
+		{"invalid role ID", "/users/" + id + "/roles/admin", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		if recorder := serve(router, http.MethodDelete, tt.path, ""); recorder.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.want, recorder.Code)
+		}
+	}
+}
@@ -19,6 +19,10 @@ type User struct {
 	IsActive    bool      `gorm:"default:true" json:"is_active"`
 	IsVerified  bool      `gorm:"default:false" json:"is_verified"`
 	LastLoginAt *time.Time `json:"last_login_at"`
+	// FailedLoginAttempts counts consecutive wrong passwords since the last
+	// successful login or lock
+	FailedLoginAttempts int        `gorm:"not null;default:0" json:"-"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	
@@ -53,7 +57,15 @@ type Permission struct {
 type Session struct {
 	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
-	Token     string    `gorm:"uniqueIndex;not null" json:"token"`
+	// Token is the SHA-256 hash of the refresh token; the token itself is
+	// only ever returned to the client
+	Token     string    `gorm:"uniqueIndex;not null" json:"-"`
+	// FamilyID is shared by a login's session and every session rotated from
+	// it by refreshing, so a replayed token can revoke all of them
+	FamilyID uuid.UUID `gorm:"type:uuid;not null;index;default:gen_random_uuid()" json:"family_id"`
+	// RotatedAt is set once the session's refresh token has been exchanged;
+	// presenting the token again afterwards means it was stolen
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
 	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
 	IsActive  bool      `gorm:"default:true" json:"is_active"`
 	IPAddress string    `json:"ip_address"`
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IncrementFailedLogins adds a failed login to the user's count and returns
+// the new count. The increment happens in the database so concurrent bad
+// passwords are all counted.
+func (r *UserRepository) IncrementFailedLogins(ctx context.Context, userID uuid.UUID) (int, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).
+		Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_login_attempts"}}}).
+		Where("id = ?", userID).
+		UpdateColumn("failed_login_attempts", gorm.Expr("failed_login_attempts + 1")).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to record failed login", err,
+			logger.String("user_id", userID.String()))
+		return 0, fmt.Errorf("failed to record failed login: %w", err)
+	}
+
+	return user.FailedLoginAttempts, nil
+}
+
+// LockAccount refuses logins for the user until the given time and starts
+// the failed login count over
+func (r *UserRepository) LockAccount(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ?", userID).
+		UpdateColumns(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          until,
+		}).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to lock account", err,
+			logger.String("user_id", userID.String()))
+		return fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	r.logger.LogWarn(ctx, "account locked after repeated failed logins",
+		logger.String("user_id", userID.String()),
+		logger.String("locked_until", until.Format(time.RFC3339)))
+
+	return nil
+}
+
+// ResetFailedLogins clears the user's failed login count and any lock
+func (r *UserRepository) ResetFailedLogins(ctx context.Context, userID uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ?", userID).
+		UpdateColumns(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
+		}).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to reset failed logins", err,
+			logger.String("user_id", userID.String()))
+		return fmt.Errorf("failed to reset failed logins: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReplaceVerificationToken stores a new email verification token for its
+// user, deleting any earlier ones so only the latest email's link works
+func (r *UserRepository) ReplaceVerificationToken(ctx context.Context, token *models.EmailVerificationToken) error {
+	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", token.UserID).Delete(&models.EmailVerificationToken{}).Error; err != nil {
+			return fmt.Errorf("failed to delete verification tokens: %w", err)
+		}
+
+		if err := tx.Omit(clause.Associations).Create(token).Error; err != nil {
+			r.logger.LogError(ctx, "failed to create verification token", err,
+				logger.String("user_id", token.UserID.String()))
+			return fmt.Errorf("failed to create verification token: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetVerificationToken retrieves an email verification token by its value
+func (r *UserRepository) GetVerificationToken(ctx context.Context, token string) (*models.EmailVerificationToken, error) {
+	var verification models.EmailVerificationToken
+
+	err := r.db.WithContext(ctx).First(&verification, "token = ?", token).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		r.logger.LogError(ctx, "failed to get verification token", err)
+		return nil, fmt.Errorf("failed to get verification token: %w", err)
+	}
+
+	return &verification, nil
+}
+
+// ConsumeVerificationToken deletes a verification token and marks its user
+// verified, atomically. A token that was already consumed returns
+// database.ErrNotFound, so each token verifies at most once.
+func (r *UserRepository) ConsumeVerificationToken(ctx context.Context, token *models.EmailVerificationToken) error {
+	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		result := tx.Delete(&models.EmailVerificationToken{}, "id = ?", token.ID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete verification token: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return database.ErrNotFound
+		}
+
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", token.UserID).
+			Update("is_verified", true).Error; err != nil {
+			return fmt.Errorf("failed to mark user verified: %w", err)
+		}
+
+		r.logger.LogInfo(ctx, "email verified",
+			logger.String("user_id", token.UserID.String()))
+
+		return nil
+	})
+}
+
+// DeleteVerificationToken deletes an email verification token
+func (r *UserRepository) DeleteVerificationToken(ctx context.Context, token *models.EmailVerificationToken) error {
+	if err := r.db.WithContext(ctx).Delete(&models.EmailVerificationToken{}, "id = ?", token.ID).Error; err != nil {
+		return fmt.Errorf("failed to delete verification token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReplacePasswordResetToken stores a new password reset token for its user,
+// deleting any unused earlier ones so only the latest email's link works
+func (r *UserRepository) ReplacePasswordResetToken(ctx context.Context, token *models.PasswordResetToken) error {
+	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND is_used = ?", token.UserID, false).
+			Delete(&models.PasswordResetToken{}).Error; err != nil {
+			return fmt.Errorf("failed to delete password reset tokens: %w", err)
+		}
+
+		if err := tx.Omit(clause.Associations).Create(token).Error; err != nil {
+			r.logger.LogError(ctx, "failed to create password reset token", err,
+				logger.String("user_id", token.UserID.String()))
+			return fmt.Errorf("failed to create password reset token: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetPasswordResetToken retrieves a password reset token by its value
+func (r *UserRepository) GetPasswordResetToken(ctx context.Context, token string) (*models.PasswordResetToken, error) {
+	var reset models.PasswordResetToken
+
+	err := r.db.WithContext(ctx).First(&reset, "token = ?", token).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		r.logger.LogError(ctx, "failed to get password reset token", err)
+		return nil, fmt.Errorf("failed to get password reset token: %w", err)
+	}
+
+	return &reset, nil
+}
+
+// ConsumePasswordResetToken marks a reset token used, sets its user's
+// password hash and deactivates all of the user's sessions, atomically. A
+// token that was already used returns database.ErrNotFound, so each token
+// resets the password at most once.
+func (r *UserRepository) ConsumePasswordResetToken(ctx context.Context, token *models.PasswordResetToken, passwordHash string) error {
+	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND is_used = ?", token.ID, false).
+			Update("is_used", true)
+		if result.Error != nil {
+			return fmt.Errorf("failed to use password reset token: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return database.ErrNotFound
+		}
+
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", token.UserID).
+			Update("password", passwordHash).Error; err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+
+		if err := deactivateSessions(tx, token.UserID).Error; err != nil {
+			return fmt.Errorf("failed to invalidate sessions: %w", err)
+		}
+
+		r.logger.LogInfo(ctx, "password reset",
+			logger.String("user_id", token.UserID.String()))
+
+		return nil
+	})
+}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,8 +13,13 @@ import (
 	"github.com/sloweyyy/GreenLedger/shared/database"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrSessionRotated is returned when rotating a session whose refresh token
+// was already exchanged or which is no longer active
+var ErrSessionRotated = errors.New("session already rotated")
+
 // SessionRepository handles session data operations
 type SessionRepository struct {
 	db     *database.PostgresDB
@@ -26,8 +34,19 @@ func NewSessionRepository(db *database.PostgresDB, logger *logger.Logger) *Sessi
 	}
 }
 
-// Create creates a new session
+// HashToken returns the form a refresh token is stored and looked up in
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create creates a new session for the refresh token in session.Token. Only
+// the token's hash is stored, and session.Token is replaced with it.
 func (r *SessionRepository) Create(ctx context.Context, session *models.Session) error {
+	session.Token = HashToken(session.Token)
+	if session.FamilyID == uuid.Nil {
+		session.FamilyID = uuid.New()
+	}
 	err := r.db.WithContext(ctx).Create(session).Error
 	if err != nil {
 		r.logger.LogError(ctx, "failed to create session", err,
@@ -42,13 +61,13 @@ func (r *SessionRepository) Create(ctx context.Context, session *models.Session)
 	return nil
 }
 
-// GetByToken retrieves a session by token
+// GetByToken retrieves a session by its refresh token
 func (r *SessionRepository) GetByToken(ctx context.Context, token string) (*models.Session, error) {
 	var session models.Session
 	
 	err := r.db.WithContext(ctx).
 		Preload("User").
-		First(&session, "token = ?", token).Error
+		First(&session, "token = ?", HashToken(token)).Error
 	
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -79,6 +98,44 @@ func (r *SessionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 	return sessions, nil
 }
 
+// GetByID retrieves a session by ID
+func (r *SessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Session, error) {
+	var session models.Session
+
+	err := r.db.WithContext(ctx).First(&session, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		r.logger.LogError(ctx, "failed to get session", err,
+			logger.String("session_id", id.String()))
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// Deactivate deactivates one of a user's sessions. It returns
+// database.ErrNotFound if the session does not belong to userID or is
+// already inactive.
+func (r *SessionRepository) Deactivate(ctx context.Context, id, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND is_active = ?", id, userID, true).
+		Update("is_active", false)
+
+	if result.Error != nil {
+		r.logger.LogError(ctx, "failed to deactivate session", result.Error,
+			logger.String("session_id", id.String()))
+		return fmt.Errorf("failed to deactivate session: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return database.ErrNotFound
+	}
+
+	return nil
+}
+
 // Update updates a session
 func (r *SessionRepository) Update(ctx context.Context, session *models.Session) error {
 	err := r.db.WithContext(ctx).Save(session).Error
@@ -91,6 +148,75 @@ func (r *SessionRepository) Update(ctx context.Context, session *models.Session)
 	return nil
 }
 
+// Rotate exchanges a session's refresh token for token: the session is
+// marked rotated and inactive, and a session in the same family is created
+// with token, expiring at expiresAt. As with Create, only the token's hash is
+// stored. Returns ErrSessionRotated if the session was rotated or deactivated
+// concurrently.
+func (r *SessionRepository) Rotate(ctx context.Context, session *models.Session, token string, expiresAt time.Time) (*models.Session, error) {
+	next := nextInFamily(session, token, expiresAt)
+
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		result := tx.Model(&models.Session{}).
+			Where("id = ? AND rotated_at IS NULL AND is_active = ?", session.ID, true).
+			Updates(map[string]interface{}{
+				"rotated_at": time.Now().UTC(),
+				"is_active":  false,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to retire session: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrSessionRotated
+		}
+
+		if err := tx.Omit(clause.Associations).Create(next).Error; err != nil {
+			return fmt.Errorf("failed to create rotated session: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, ErrSessionRotated) {
+			r.logger.LogError(ctx, "failed to rotate session token", err,
+				logger.String("session_id", session.ID.String()))
+		}
+		return nil, err
+	}
+
+	return next, nil
+}
+
+// RevokeFamily deactivates every session in a family and returns how many
+// were still active
+func (r *SessionRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Session{}).
+		Where("family_id = ? AND is_active = ?", familyID, true).
+		Update("is_active", false)
+
+	if result.Error != nil {
+		r.logger.LogError(ctx, "failed to revoke session family", result.Error,
+			logger.String("family_id", familyID.String()))
+		return 0, fmt.Errorf("failed to revoke session family: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// nextInFamily builds the session that replaces session when its refresh
+// token is exchanged for token
+func nextInFamily(session *models.Session, token string, expiresAt time.Time) *models.Session {
+	return &models.Session{
+		UserID:    session.UserID,
+		Token:     HashToken(token),
+		FamilyID:  session.FamilyID,
+		ExpiresAt: expiresAt,
+		IsActive:  true,
+		IPAddress: session.IPAddress,
+		UserAgent: session.UserAgent,
+	}
+}
+
 // Delete deletes a session
 func (r *SessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	err := r.db.WithContext(ctx).Delete(&models.Session{}, "id = ?", id).Error
@@ -103,23 +229,28 @@ func (r *SessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-// InvalidateUserSessions invalidates all sessions for a user
-func (r *SessionRepository) InvalidateUserSessions(ctx context.Context, userID uuid.UUID) error {
-	err := r.db.WithContext(ctx).
-		Model(&models.Session{}).
-		Where("user_id = ?", userID).
-		Update("is_active", false).Error
-
-	if err != nil {
-		r.logger.LogError(ctx, "failed to invalidate user sessions", err,
+// DeactivateAllByUserID deactivates every active session of a user and
+// returns how many were deactivated
+func (r *SessionRepository) DeactivateAllByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result := deactivateSessions(r.db.WithContext(ctx), userID)
+	if result.Error != nil {
+		r.logger.LogError(ctx, "failed to invalidate user sessions", result.Error,
 			logger.String("user_id", userID.String()))
-		return fmt.Errorf("failed to invalidate sessions: %w", err)
+		return 0, fmt.Errorf("failed to invalidate sessions: %w", result.Error)
 	}
 
 	r.logger.LogInfo(ctx, "user sessions invalidated",
-		logger.String("user_id", userID.String()))
+		logger.String("user_id", userID.String()),
+		logger.Int("count", int(result.RowsAffected)))
 
-	return nil
+	return result.RowsAffected, nil
+}
+
+// deactivateSessions marks every active session of userID inactive
+func deactivateSessions(db *gorm.DB, userID uuid.UUID) *gorm.DB {
+	return db.Model(&models.Session{}).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Update("is_active", false)
 }
 
 // CleanupExpiredSessions removes expired sessions
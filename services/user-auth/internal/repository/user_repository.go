@@ -2,16 +2,28 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/models"
 	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/events"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"gorm.io/gorm"
 )
 
+// ErrRoleNotFound is returned when assigning or removing a role that does not exist
+var ErrRoleNotFound = errors.New("role not found")
+
+// ErrRoleAlreadyAssigned is returned when assigning a role the user already has
+var ErrRoleAlreadyAssigned = errors.New("role already assigned")
+
+// ErrRoleNotAssigned is returned when removing a role the user does not have
+var ErrRoleNotAssigned = errors.New("role not assigned")
+
 // UserRepository handles user data operations
 type UserRepository struct {
 	db     *database.PostgresDB
@@ -138,6 +150,54 @@ func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// Erase permanently removes a user together with their profile, sessions,
+// tokens and role assignments, and writes event to the outbox in the same
+// transaction so the erasure is announced if and only if it is committed
+func (r *UserRepository) Erase(ctx context.Context, id uuid.UUID, event *events.OutboxEvent) error {
+	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		dependents := []interface{}{
+			&models.Session{},
+			&models.UserProfile{},
+			&models.PasswordResetToken{},
+			&models.EmailVerificationToken{},
+		}
+		for _, model := range dependents {
+			if err := tx.Where("user_id = ?", id).Delete(model).Error; err != nil {
+				r.logger.LogError(ctx, "failed to erase user data", err,
+					logger.String("user_id", id.String()))
+				return fmt.Errorf("failed to erase user data: %w", err)
+			}
+		}
+
+		if err := tx.Exec("DELETE FROM user_roles WHERE user_id = ?", id).Error; err != nil {
+			r.logger.LogError(ctx, "failed to erase user roles", err,
+				logger.String("user_id", id.String()))
+			return fmt.Errorf("failed to erase user roles: %w", err)
+		}
+
+		result := tx.Delete(&models.User{}, "id = ?", id)
+		if result.Error != nil {
+			r.logger.LogError(ctx, "failed to erase user", result.Error,
+				logger.String("user_id", id.String()))
+			return fmt.Errorf("failed to erase user: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return database.ErrNotFound
+		}
+
+		if err := tx.Create(event).Error; err != nil {
+			r.logger.LogError(ctx, "failed to write user deleted event", err,
+				logger.String("user_id", id.String()))
+			return fmt.Errorf("failed to write user deleted event: %w", err)
+		}
+
+		r.logger.LogInfo(ctx, "user erased successfully",
+			logger.String("user_id", id.String()))
+
+		return nil
+	})
+}
+
 // UpdateLastLogin updates the user's last login time
 func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID uuid.UUID) error {
 	now := time.Now().UTC()
@@ -155,23 +215,20 @@ func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID uuid.UUID)
 	return nil
 }
 
-// AssignRole assigns a role to a user
+// AssignRole assigns a role to a user. A missing user returns
+// database.ErrNotFound and a missing role ErrRoleNotFound.
 func (r *UserRepository) AssignRole(ctx context.Context, userID, roleID uuid.UUID) error {
 	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
-		// Check if user exists
-		var user models.User
-		if err := tx.First(&user, "id = ?", userID).Error; err != nil {
-			return fmt.Errorf("user not found: %w", err)
+		user, role, assigned, err := userRole(tx, userID, roleID)
+		if err != nil {
+			return err
 		}
-
-		// Check if role exists
-		var role models.Role
-		if err := tx.First(&role, "id = ?", roleID).Error; err != nil {
-			return fmt.Errorf("role not found: %w", err)
+		if assigned {
+			return ErrRoleAlreadyAssigned
 		}
 
 		// Assign role
-		if err := tx.Model(&user).Association("Roles").Append(&role); err != nil {
+		if err := tx.Model(user).Association("Roles").Append(role); err != nil {
 			return fmt.Errorf("failed to assign role: %w", err)
 		}
 
@@ -183,23 +240,21 @@ func (r *UserRepository) AssignRole(ctx context.Context, userID, roleID uuid.UUI
 	})
 }
 
-// RemoveRole removes a role from a user
+// RemoveRole removes a role from a user. A missing user returns
+// database.ErrNotFound, a missing role ErrRoleNotFound and a role the user
+// does not have ErrRoleNotAssigned.
 func (r *UserRepository) RemoveRole(ctx context.Context, userID, roleID uuid.UUID) error {
 	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
-		// Check if user exists
-		var user models.User
-		if err := tx.First(&user, "id = ?", userID).Error; err != nil {
-			return fmt.Errorf("user not found: %w", err)
+		user, role, assigned, err := userRole(tx, userID, roleID)
+		if err != nil {
+			return err
 		}
-
-		// Check if role exists
-		var role models.Role
-		if err := tx.First(&role, "id = ?", roleID).Error; err != nil {
-			return fmt.Errorf("role not found: %w", err)
+		if !assigned {
+			return ErrRoleNotAssigned
 		}
 
 		// Remove role
-		if err := tx.Model(&user).Association("Roles").Delete(&role); err != nil {
+		if err := tx.Model(user).Association("Roles").Delete(role); err != nil {
 			return fmt.Errorf("failed to remove role: %w", err)
 		}
 
@@ -211,6 +266,34 @@ func (r *UserRepository) RemoveRole(ctx context.Context, userID, roleID uuid.UUI
 	})
 }
 
+// userRole loads a user and a role and reports whether the user has the role
+func userRole(tx *gorm.DB, userID, roleID uuid.UUID) (*models.User, *models.Role, bool, error) {
+	var user models.User
+	if err := tx.First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, false, database.ErrNotFound
+		}
+		return nil, nil, false, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var role models.Role
+	if err := tx.First(&role, "id = ?", roleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, false, ErrRoleNotFound
+		}
+		return nil, nil, false, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	var count int64
+	if err := tx.Table("user_roles").
+		Where("user_id = ? AND role_id = ?", userID, roleID).
+		Count(&count).Error; err != nil {
+		return nil, nil, false, fmt.Errorf("failed to check user roles: %w", err)
+	}
+
+	return &user, &role, count > 0, nil
+}
+
 // List retrieves users with pagination
 func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*models.User, int64, error) {
 	var users []*models.User
@@ -273,6 +356,42 @@ func (r *UserRepository) Search(ctx context.Context, query string, limit, offset
 	return users, total, nil
 }
 
+// SearchByPrefix finds users whose email or username starts with prefix,
+// ignoring case. LIKE wildcards in prefix are matched literally.
+func (r *UserRepository) SearchByPrefix(ctx context.Context, prefix string, limit, offset int) ([]*models.User, int64, error) {
+	var users []*models.User
+	var total int64
+
+	pattern := likeEscaper.Replace(prefix) + "%"
+	where := "email ILIKE ? OR username ILIKE ?"
+
+	// Get total count
+	if err := r.db.WithContext(ctx).Model(&models.User{}).
+		Where(where, pattern, pattern).
+		Count(&total).Error; err != nil {
+		r.logger.LogError(ctx, "failed to count user search results", err)
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	// Get users
+	if err := r.db.WithContext(ctx).
+		Where(where, pattern, pattern).
+		Preload("Roles").
+		Preload("Profile").
+		Order("email ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error; err != nil {
+		r.logger.LogError(ctx, "failed to search users by prefix", err)
+		return nil, 0, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+// likeEscaper escapes the LIKE wildcards and escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 // EmailExists checks if an email already exists
 func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
 	var count int64
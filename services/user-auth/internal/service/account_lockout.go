@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// ErrAccountLocked is returned when logging in to an account that is locked
+// after too many failed logins
+var ErrAccountLocked = errors.New("account temporarily locked")
+
+// LockoutPolicy decides when repeated wrong passwords lock an account
+type LockoutPolicy struct {
+	// MaxFailedAttempts is how many consecutive failures lock the account
+	MaxFailedAttempts int
+	// Duration is how long the account stays locked
+	Duration time.Duration
+}
+
+// lockUntil returns when an account with the given number of consecutive
+// failures should be unlocked, or nil if it should not be locked yet
+func (p LockoutPolicy) lockUntil(failedAttempts int, now time.Time) *time.Time {
+	if failedAttempts < p.MaxFailedAttempts {
+		return nil
+	}
+	until := now.Add(p.Duration)
+	return &until
+}
+
+// checkLockout rejects logins to a user whose lock has not expired yet
+func checkLockout(user *models.User, now time.Time) error {
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		return fmt.Errorf("%w until %s", ErrAccountLocked, user.LockedUntil.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// recordFailedLogin counts a wrong password for the user and locks the
+// account once the policy's threshold is reached. Failures to record are
+// logged rather than returned so the caller still reports bad credentials.
+func (s *AuthService) recordFailedLogin(ctx context.Context, user *models.User) {
+	attempts, err := s.userRepo.IncrementFailedLogins(ctx, user.ID)
+	if err != nil {
+		s.logger.LogError(ctx, "failed to record failed login", err)
+		return
+	}
+
+	until := s.lockout.lockUntil(attempts, time.Now().UTC())
+	if until == nil {
+		return
+	}
+
+	if err := s.userRepo.LockAccount(ctx, user.ID, *until); err != nil {
+		s.logger.LogError(ctx, "failed to lock account", err,
+			logger.String("user_id", user.ID.String()))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,31 +12,63 @@ import (
 	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/models"
 	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/database"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 )
 
+// ErrUserExists is returned when an email or username is already taken
+var ErrUserExists = errors.New("user already exists")
+
+// ErrInvalidCredentials is returned when a login email or password is wrong.
+// The two cases are not distinguished so emails cannot be enumerated.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrAccountDeactivated is returned when a deactivated user tries to sign in
+var ErrAccountDeactivated = errors.New("user account is deactivated")
+
+// ErrInvalidRefreshToken is returned when a refresh token is unknown or expired
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// ErrRefreshTokenReused is returned when a refresh token that was already
+// exchanged is presented again. It wraps ErrInvalidRefreshToken.
+var ErrRefreshTokenReused = fmt.Errorf("%w: token reused", ErrInvalidRefreshToken)
+
 // AuthService handles authentication operations
 type AuthService struct {
 	userRepo    *repository.UserRepository
 	sessionRepo *repository.SessionRepository
 	roleRepo    *repository.RoleRepository
+	emailSender EmailSender
+	lockout     LockoutPolicy
 	jwtSecret   []byte
+	jwtIssuer   string
+	jwtAudience string
 	logger      *logger.Logger
 }
 
-// NewAuthService creates a new auth service
+// NewAuthService creates a new auth service. Issued access tokens carry
+// jwtIssuer and jwtAudience, and ValidateToken requires both. Logins are
+// refused for a while after lockout's number of consecutive wrong passwords.
 func NewAuthService(
 	userRepo *repository.UserRepository,
 	sessionRepo *repository.SessionRepository,
 	roleRepo *repository.RoleRepository,
+	emailSender EmailSender,
+	lockout LockoutPolicy,
 	jwtSecret string,
+	jwtIssuer string,
+	jwtAudience string,
 	logger *logger.Logger,
 ) *AuthService {
 	return &AuthService{
 		userRepo:    userRepo,
 		sessionRepo: sessionRepo,
 		roleRepo:    roleRepo,
+		emailSender: emailSender,
+		lockout:     lockout,
 		jwtSecret:   []byte(jwtSecret),
+		jwtIssuer:   jwtIssuer,
+		jwtAudience: jwtAudience,
 		logger:      logger,
 	}
 }
@@ -105,7 +138,7 @@ func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*Auth
 		return nil, fmt.Errorf("failed to check email existence: %w", err)
 	}
 	if emailExists {
-		return nil, fmt.Errorf("email already exists")
+		return nil, fmt.Errorf("%w: email is taken", ErrUserExists)
 	}
 
 	// Check if username already exists
@@ -114,7 +147,7 @@ func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*Auth
 		return nil, fmt.Errorf("failed to check username existence: %w", err)
 	}
 	if usernameExists {
-		return nil, fmt.Errorf("username already exists")
+		return nil, fmt.Errorf("%w: username is taken", ErrUserExists)
 	}
 
 	// Create user
@@ -172,6 +205,12 @@ func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*Auth
 		// Continue without session for now
 	}
 
+	// A failed email does not fail registration; the user can ask for another
+	if err := s.SendVerificationEmail(ctx, user.ID); err != nil {
+		s.logger.LogError(ctx, "failed to send verification email", err,
+			logger.String("user_id", user.ID.String()))
+	}
+
 	s.logger.LogInfo(ctx, "user registered successfully",
 		logger.String("user_id", user.ID.String()),
 		logger.String("email", user.Email))
@@ -192,15 +231,21 @@ func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*AuthRespon
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		if err == fmt.Errorf("record not found") {
-			return nil, fmt.Errorf("invalid credentials")
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, ErrInvalidCredentials
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	// Check if user is active
 	if !user.IsActive {
-		return nil, fmt.Errorf("user account is deactivated")
+		return nil, ErrAccountDeactivated
+	}
+
+	// Refuse locked accounts before checking the password, so guesses made
+	// during the lock neither succeed nor extend it
+	if err := checkLockout(user, time.Now().UTC()); err != nil {
+		return nil, err
 	}
 
 	// Check password
@@ -208,7 +253,14 @@ func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*AuthRespon
 		s.logger.LogWarn(ctx, "invalid password attempt",
 			logger.String("user_id", user.ID.String()),
 			logger.String("email", user.Email))
-		return nil, fmt.Errorf("invalid credentials")
+		s.recordFailedLogin(ctx, user)
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := s.userRepo.ResetFailedLogins(ctx, user.ID); err != nil {
+			s.logger.LogError(ctx, "failed to reset failed logins", err)
+		}
 	}
 
 	// Update last login
@@ -253,12 +305,17 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*A
 	// Get session by token
 	session, err := s.sessionRepo.GetByToken(ctx, refreshToken)
 	if err != nil {
-		return nil, fmt.Errorf("invalid refresh token")
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to look up refresh token: %w", err)
 	}
 
-	// Check if session is valid
-	if !session.IsSessionValid() {
-		return nil, fmt.Errorf("refresh token expired")
+	if err := checkSession(session, time.Now()); err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			s.revokeFamily(ctx, session)
+		}
+		return nil, err
 	}
 
 	// Get user
@@ -269,7 +326,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*A
 
 	// Check if user is active
 	if !user.IsActive {
-		return nil, fmt.Errorf("user account is deactivated")
+		return nil, ErrAccountDeactivated
 	}
 
 	// Generate new tokens
@@ -278,11 +335,14 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*A
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
-	// Update session with new refresh token
-	session.Token = newRefreshToken
-	session.ExpiresAt = expiresAt.Add(24 * time.Hour)
-	if err := s.sessionRepo.Update(ctx, session); err != nil {
-		s.logger.LogError(ctx, "failed to update session", err)
+	// Exchange the presented refresh token for the new one
+	if _, err := s.sessionRepo.Rotate(ctx, session, newRefreshToken, expiresAt.Add(24*time.Hour)); err != nil {
+		if errors.Is(err, repository.ErrSessionRotated) {
+			// Another request exchanged the same token first
+			s.revokeFamily(ctx, session)
+			return nil, ErrRefreshTokenReused
+		}
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
 	}
 
 	return &AuthResponse{
@@ -300,8 +360,10 @@ func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
 		return nil // Already logged out
 	}
 
-	session.IsActive = false
-	if err := s.sessionRepo.Update(ctx, session); err != nil {
+	if err := s.sessionRepo.Deactivate(ctx, session.ID, session.UserID); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil // Already logged out
+		}
 		s.logger.LogError(ctx, "failed to invalidate session", err)
 		return fmt.Errorf("failed to logout: %w", err)
 	}
@@ -312,11 +374,55 @@ func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
 	return nil
 }
 
+// LogoutAll signs the user out of every device by deactivating all of their
+// sessions, so none of their refresh tokens can be used again
+func (s *AuthService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	count, err := s.sessionRepo.DeactivateAllByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to logout: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "user logged out from all devices",
+		logger.String("user_id", userID.String()),
+		logger.Int("session_count", int(count)))
+
+	return nil
+}
+
+// revokeFamily handles a replayed refresh token as stolen by signing out
+// every session descended from the same login
+func (s *AuthService) revokeFamily(ctx context.Context, session *models.Session) {
+	s.logger.LogWarn(ctx, "security: refresh token reuse detected, revoking session family",
+		logger.String("user_id", session.UserID.String()),
+		logger.String("session_id", session.ID.String()),
+		logger.String("family_id", session.FamilyID.String()))
+
+	if _, err := s.sessionRepo.RevokeFamily(ctx, session.FamilyID); err != nil {
+		s.logger.LogError(ctx, "failed to revoke session family", err,
+			logger.String("family_id", session.FamilyID.String()))
+	}
+}
+
+// checkSession rejects refresh tokens that were already exchanged, or whose
+// session was logged out or has expired
+func checkSession(session *models.Session, now time.Time) error {
+	if session.RotatedAt != nil {
+		return ErrRefreshTokenReused
+	}
+	if !session.IsActive {
+		return fmt.Errorf("%w: session logged out", ErrInvalidRefreshToken)
+	}
+	if !now.Before(session.ExpiresAt) {
+		return fmt.Errorf("%w: expired", ErrInvalidRefreshToken)
+	}
+	return nil
+}
+
 // ValidateToken validates a JWT token and returns the user
 func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*models.User, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return s.jwtSecret, nil
-	})
+	}, jwt.WithIssuer(s.jwtIssuer), jwt.WithAudience(s.jwtAudience))
 
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
@@ -338,15 +444,16 @@ func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*m
 	}
 
 	if !user.IsActive {
-		return nil, fmt.Errorf("user account is deactivated")
+		return nil, ErrAccountDeactivated
 	}
 
 	return user, nil
 }
 
-// generateTokens generates access and refresh tokens
+// generateTokens generates access and refresh tokens. The refresh token is
+// handed to the client once; sessions only store its hash.
 func (s *AuthService) generateTokens(user *models.User) (string, string, time.Time, error) {
-	expiresAt := time.Now().Add(1 * time.Hour) // Access token expires in 1 hour
+	expiresAt := time.Now().UTC().Add(1 * time.Hour) // Access token expires in 1 hour
 
 	// Create access token claims
 	claims := &JWTClaims{
@@ -357,7 +464,8 @@ func (s *AuthService) generateTokens(user *models.User) (string, string, time.Ti
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "greenledger-auth",
+			Issuer:    s.jwtIssuer,
+			Audience:  jwt.ClaimStrings{s.jwtAudience},
 			Subject:   user.ID.String(),
 		},
 	}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -186,3 +188,130 @@ func TestEmailVerificationTokenModel_Creation(t *testing.T) {
 		t.Error("Expected email verification token to not be expired")
 	}
 }
+
+func TestCheckVerificationToken(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		token   *models.EmailVerificationToken
+		wantErr bool
+	}{
+		{"valid", &models.EmailVerificationToken{ExpiresAt: now.Add(EmailVerificationTTL)}, false},
+		{"expired", &models.EmailVerificationToken{ExpiresAt: now.Add(-time.Minute)}, true},
+		{"expires now", &models.EmailVerificationToken{ExpiresAt: now}, true},
+		{"used", &models.EmailVerificationToken{ExpiresAt: now.Add(time.Hour), IsUsed: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkVerificationToken(tt.token, now)
+			if tt.wantErr && !errors.Is(err, ErrInvalidVerificationToken) {
+				t.Errorf("Expected ErrInvalidVerificationToken, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckResetToken(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := checkResetToken(&models.PasswordResetToken{ExpiresAt: now.Add(PasswordResetTTL)}, now); err != nil {
+		t.Errorf("Expected fresh token to be accepted, got %v", err)
+	}
+	if err := checkResetToken(&models.PasswordResetToken{ExpiresAt: now.Add(-time.Second)}, now); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("Expected expired token to be rejected, got %v", err)
+	}
+	if err := checkResetToken(&models.PasswordResetToken{ExpiresAt: now.Add(time.Minute), IsUsed: true}, now); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("Expected used token to be rejected, got %v", err)
+	}
+}
+
+func TestResetPassword_RejectsShortPassword(t *testing.T) {
+	// The length check runs before any repository access
+	s := &AuthService{}
+	if err := s.ResetPassword(context.Background(), "token", "short"); !errors.Is(err, ErrWeakPassword) {
+		t.Errorf("Expected ErrWeakPassword, got %v", err)
+	}
+}
+
+func TestCheckSession_RejectsRefreshAfterLogoutAll(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	session := &models.Session{UserID: uuid.New(), IsActive: true, ExpiresAt: now.Add(time.Hour)}
+
+	if err := checkSession(session, now); err != nil {
+		t.Fatalf("Expected active session to refresh, got %v", err)
+	}
+
+	// LogoutAll deactivates the session; the refresh token must stop working
+	session.IsActive = false
+	if err := checkSession(session, now); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Expected ErrInvalidRefreshToken after logout, got %v", err)
+	}
+
+	expired := &models.Session{IsActive: true, ExpiresAt: now}
+	if err := checkSession(expired, now); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Expected ErrInvalidRefreshToken for an expired session, got %v", err)
+	}
+}
+
+func TestLockoutPolicy_LocksAfterRepeatedFailures(t *testing.T) {
+	policy := LockoutPolicy{MaxFailedAttempts: 5, Duration: 15 * time.Minute}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	user := &models.User{ID: uuid.New()}
+
+	// Four wrong passwords leave the account open
+	for attempt := 1; attempt < policy.MaxFailedAttempts; attempt++ {
+		if until := policy.lockUntil(attempt, now); until != nil {
+			t.Fatalf("Expected no lock after %d failures, got lock until %v", attempt, until)
+		}
+		if err := checkLockout(user, now); err != nil {
+			t.Fatalf("Expected login allowed after %d failures, got %v", attempt, err)
+		}
+	}
+
+	// The fifth locks it for the policy duration
+	user.LockedUntil = policy.lockUntil(policy.MaxFailedAttempts, now)
+	if user.LockedUntil == nil || !user.LockedUntil.Equal(now.Add(15*time.Minute)) {
+		t.Fatalf("Expected lock until %v, got %v", now.Add(15*time.Minute), user.LockedUntil)
+	}
+	if err := checkLockout(user, now.Add(14*time.Minute)); !errors.Is(err, ErrAccountLocked) {
+		t.Errorf("Expected ErrAccountLocked during the lock, got %v", err)
+	}
+
+	// Once the lock expires the user can log in again
+	if err := checkLockout(user, now.Add(15*time.Minute)); err != nil {
+		t.Errorf("Expected login allowed after the lock expires, got %v", err)
+	}
+}
+
+func TestCheckSession_DetectsReplayedRefreshToken(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	session := &models.Session{
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		IsActive:  true,
+		ExpiresAt: now.Add(24 * time.Hour),
+	}
+
+	if err := checkSession(session, now); err != nil {
+		t.Fatalf("Expected the current token to refresh, got %v", err)
+	}
+
+	// Refreshing rotates the token out: the session is retired
+	rotatedAt := now.Add(time.Minute)
+	session.RotatedAt = &rotatedAt
+	session.IsActive = false
+
+	// Presenting the stale token again is reuse, rejected like any invalid token
+	err := checkSession(session, now.Add(2*time.Minute))
+	if !errors.Is(err, ErrRefreshTokenReused) {
+		t.Errorf("Expected ErrRefreshTokenReused replaying a rotated token, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Expected reuse to count as an invalid refresh token, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// EmailVerificationTTL is how long an email verification token stays valid
+const EmailVerificationTTL = 24 * time.Hour
+
+// ErrInvalidVerificationToken is returned when a verification token is
+// unknown, already used or expired
+var ErrInvalidVerificationToken = errors.New("invalid verification token")
+
+// ErrAlreadyVerified is returned when requesting verification for a user
+// whose email is already verified
+var ErrAlreadyVerified = errors.New("email already verified")
+
+// EmailSender delivers account emails
+type EmailSender interface {
+	SendVerificationEmail(ctx context.Context, email, token string, expiresAt time.Time) error
+	SendPasswordResetEmail(ctx context.Context, email, token string, expiresAt time.Time) error
+}
+
+// LogEmailSender logs emails instead of delivering them. It is meant for
+// local development, where the logged tokens can be used by hand.
+type LogEmailSender struct {
+	logger *logger.Logger
+}
+
+// NewLogEmailSender creates a new log email sender
+func NewLogEmailSender(logger *logger.Logger) *LogEmailSender {
+	return &LogEmailSender{logger: logger}
+}
+
+// SendVerificationEmail logs the verification email
+func (s *LogEmailSender) SendVerificationEmail(ctx context.Context, email, token string, expiresAt time.Time) error {
+	s.logger.LogInfo(ctx, "mock: verification email sent",
+		logger.String("email", email),
+		logger.String("token", token),
+		logger.String("expires_at", expiresAt.Format(time.RFC3339)))
+	return nil
+}
+
+// SendPasswordResetEmail logs the password reset email
+func (s *LogEmailSender) SendPasswordResetEmail(ctx context.Context, email, token string, expiresAt time.Time) error {
+	s.logger.LogInfo(ctx, "mock: password reset email sent",
+		logger.String("email", email),
+		logger.String("token", token),
+		logger.String("expires_at", expiresAt.Format(time.RFC3339)))
+	return nil
+}
+
+// SendVerificationEmail issues a new single-use verification token for the
+// user and emails it to them. Earlier tokens stop working.
+func (s *AuthService) SendVerificationEmail(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.IsVerified {
+		return ErrAlreadyVerified
+	}
+
+	token, err := s.generateRandomToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	verification := &models.EmailVerificationToken{
+		UserID:    user.ID,
+		Token:     token,
+		ExpiresAt: time.Now().UTC().Add(EmailVerificationTTL),
+	}
+	if err := s.userRepo.ReplaceVerificationToken(ctx, verification); err != nil {
+		return err
+	}
+
+	if err := s.emailSender.SendVerificationEmail(ctx, user.Email, token, verification.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "verification email sent",
+		logger.String("user_id", user.ID.String()))
+
+	return nil
+}
+
+// VerifyEmail marks the token's user verified and deletes the token
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	verification, err := s.userRepo.GetVerificationToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return ErrInvalidVerificationToken
+		}
+		return err
+	}
+
+	if err := checkVerificationToken(verification, time.Now().UTC()); err != nil {
+		if deleteErr := s.userRepo.DeleteVerificationToken(ctx, verification); deleteErr != nil {
+			s.logger.LogError(ctx, "failed to delete expired verification token", deleteErr)
+		}
+		return err
+	}
+
+	if err := s.userRepo.ConsumeVerificationToken(ctx, verification); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			// Consumed by a concurrent request
+			return ErrInvalidVerificationToken
+		}
+		return err
+	}
+
+	return nil
+}
+
+// checkVerificationToken rejects used or expired verification tokens
+func checkVerificationToken(verification *models.EmailVerificationToken, now time.Time) error {
+	if verification.IsUsed {
+		return fmt.Errorf("%w: already used", ErrInvalidVerificationToken)
+	}
+	if !now.Before(verification.ExpiresAt) {
+		return fmt.Errorf("%w: expired", ErrInvalidVerificationToken)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sloweyyy/GreenLedger/shared/proto/usererasure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrErasureBlocked is returned when a user still holds assets that must be
+// settled before their account can be erased
+var ErrErasureBlocked = errors.New("user cannot be erased")
+
+// ErasureGuard decides whether a user's data may be erased
+type ErasureGuard interface {
+	CheckErasable(ctx context.Context, userID string) error
+}
+
+// ErasureCheck is a service asked whether a user still holds assets in it
+type ErasureCheck struct {
+	Service string
+	Client  usererasure.UserErasureClient
+}
+
+// GRPCErasureGuard asks the services that hold users' assets, such as the
+// wallet and certifier, whether a user can be erased
+type GRPCErasureGuard struct {
+	checks []ErasureCheck
+}
+
+// NewGRPCErasureGuard creates a new gRPC-backed erasure guard
+func NewGRPCErasureGuard(checks ...ErasureCheck) *GRPCErasureGuard {
+	return &GRPCErasureGuard{checks: checks}
+}
+
+// CheckErasable returns ErrErasureBlocked while any service reports that the
+// user still holds assets in it, such as a non-zero wallet balance or an
+// active certificate
+func (g *GRPCErasureGuard) CheckErasable(ctx context.Context, userID string) error {
+	for _, check := range g.checks {
+		_, err := check.Client.CheckErasable(ctx, &usererasure.CheckErasableRequest{UserId: userID})
+		if status.Code(err) == codes.FailedPrecondition {
+			return fmt.Errorf("%w: %s", ErrErasureBlocked, status.Convert(err).Message())
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check %s: %w", check.Service, err)
+		}
+	}
+	return nil
+}
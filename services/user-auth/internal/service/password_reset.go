@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// PasswordResetTTL is how long a password reset token stays valid
+const PasswordResetTTL = time.Hour
+
+// MinPasswordLength is the shortest password accepted
+const MinPasswordLength = 8
+
+// ErrInvalidResetToken is returned when a password reset token is unknown,
+// already used or expired
+var ErrInvalidResetToken = errors.New("invalid password reset token")
+
+// ErrWeakPassword is returned when a new password is shorter than MinPasswordLength
+var ErrWeakPassword = errors.New("password is too short")
+
+// RequestPasswordReset emails a single-use reset token to the user with the
+// given email. It reports success whether or not the email is registered, so
+// callers cannot tell which emails have accounts; failures after the lookup
+// are logged rather than returned for the same reason.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			s.logger.LogInfo(ctx, "password reset requested for unknown email")
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.IsActive {
+		s.logger.LogInfo(ctx, "password reset requested for deactivated user",
+			logger.String("user_id", user.ID.String()))
+		return nil
+	}
+
+	if err := s.sendPasswordReset(ctx, user); err != nil {
+		s.logger.LogError(ctx, "failed to send password reset", err,
+			logger.String("user_id", user.ID.String()))
+	}
+
+	return nil
+}
+
+// sendPasswordReset issues a reset token for user and emails it to them
+func (s *AuthService) sendPasswordReset(ctx context.Context, user *models.User) error {
+	token, err := s.generateRandomToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+
+	reset := &models.PasswordResetToken{
+		UserID:    user.ID,
+		Token:     token,
+		ExpiresAt: time.Now().UTC().Add(PasswordResetTTL),
+	}
+	if err := s.userRepo.ReplacePasswordResetToken(ctx, reset); err != nil {
+		return err
+	}
+
+	if err := s.emailSender.SendPasswordResetEmail(ctx, user.Email, token, reset.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "password reset email sent",
+		logger.String("user_id", user.ID.String()))
+
+	return nil
+}
+
+// ResetPassword sets a new password for the user a reset token was issued
+// to, using up the token and signing the user out of every session in the
+// same database transaction
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if len(newPassword) < MinPasswordLength {
+		return fmt.Errorf("%w: must be at least %d characters", ErrWeakPassword, MinPasswordLength)
+	}
+
+	reset, err := s.userRepo.GetPasswordResetToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if err := checkResetToken(reset, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, reset.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := user.HashPassword(newPassword); err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := s.userRepo.ConsumePasswordResetToken(ctx, reset, user.Password); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			// Used by a concurrent request
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	return nil
+}
+
+// checkResetToken rejects used or expired password reset tokens
+func checkResetToken(reset *models.PasswordResetToken, now time.Time) error {
+	if reset.IsUsed {
+		return fmt.Errorf("%w: already used", ErrInvalidResetToken)
+	}
+	if !now.Before(reset.ExpiresAt) {
+		return fmt.Errorf("%w: expired", ErrInvalidResetToken)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// ErrSessionNotFound is returned when a session does not exist or belongs to
+// another user
+var ErrSessionNotFound = errors.New("session not found")
+
+// sessionTokenHintLength is how many leading characters of a refresh token's
+// hash identify its session in API responses
+const sessionTokenHintLength = 8
+
+// SessionResponse represents an active session in API responses. The refresh
+// token is never returned, only a short prefix of its hash to tell sessions apart.
+type SessionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	TokenHint string    `json:"token_hint"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ListSessions retrieves the user's active sessions, newest first
+func (s *UserService) ListSessions(ctx context.Context, userID string) ([]*SessionResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	sessions, err := s.sessionRepo.GetByUserID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = sessionToResponse(session)
+	}
+
+	return responses, nil
+}
+
+// RevokeSession signs the user out of one of their own sessions. Sessions of
+// other users are reported as not found.
+func (s *UserService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+	sid, err := uuid.Parse(sessionID)
+	if err != nil {
+		return ErrSessionNotFound
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sid)
+	if _, err := ownedSession(session, err, id); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.Deactivate(ctx, sid, id); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+
+	s.logger.LogInfo(ctx, "session revoked",
+		logger.String("user_id", userID),
+		logger.String("session_id", sessionID))
+
+	return nil
+}
+
+// ownedSession checks the result of a session lookup against the requesting
+// user, hiding missing, inactive and other users' sessions behind
+// ErrSessionNotFound
+func ownedSession(session *models.Session, err error, userID uuid.UUID) (*models.Session, error) {
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.UserID != userID || !session.IsActive {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// sessionToResponse converts a session to its API form without the token
+func sessionToResponse(session *models.Session) *SessionResponse {
+	return &SessionResponse{
+		ID:        session.ID,
+		TokenHint: tokenHint(session.Token),
+		IPAddress: session.IPAddress,
+		UserAgent: session.UserAgent,
+		CreatedAt: session.CreatedAt,
+		ExpiresAt: session.ExpiresAt,
+	}
+}
+
+// tokenHint returns the first few characters of a token, and never more
+// than half of it
+func tokenHint(token string) string {
+	n := sessionTokenHintLength
+	if len(token) < 2*n {
+		n = len(token) / 2
+	}
+	return token[:n] + "..."
+}
@@ -2,25 +2,38 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/models"
 	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/events"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 )
 
 // UserService handles user management operations
 type UserService struct {
-	userRepo *repository.UserRepository
-	logger   *logger.Logger
+	userRepo       *repository.UserRepository
+	sessionRepo    *repository.SessionRepository
+	erasureGuard ErasureGuard
+	logger       *logger.Logger
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo *repository.UserRepository, logger *logger.Logger) *UserService {
+func NewUserService(
+	userRepo *repository.UserRepository,
+	sessionRepo *repository.SessionRepository,
+	erasureGuard ErasureGuard,
+	logger *logger.Logger,
+) *UserService {
 	return &UserService{
-		userRepo: userRepo,
-		logger:   logger,
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		erasureGuard: erasureGuard,
+		logger:       logger,
 	}
 }
 
@@ -87,6 +100,10 @@ func (s *UserService) UpdateProfile(ctx context.Context, userID string, req *Upd
 	return s.userToResponse(user), nil
 }
 
+// ErrInvalidPassword is returned when the current password given to
+// ChangePassword is wrong
+var ErrInvalidPassword = errors.New("invalid current password")
+
 // ChangePassword changes a user's password
 func (s *UserService) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error {
 	id, err := uuid.Parse(userID)
@@ -101,7 +118,7 @@ func (s *UserService) ChangePassword(ctx context.Context, userID, currentPasswor
 
 	// Verify current password
 	if !user.CheckPassword(currentPassword) {
-		return fmt.Errorf("invalid current password")
+		return ErrInvalidPassword
 	}
 
 	// Hash new password
@@ -113,6 +130,11 @@ func (s *UserService) ChangePassword(ctx context.Context, userID, currentPasswor
 		return fmt.Errorf("failed to update password: %w", err)
 	}
 
+	// Sign out every device so refresh tokens issued under the old password stop working
+	if _, err := s.sessionRepo.DeactivateAllByUserID(ctx, id); err != nil {
+		return fmt.Errorf("failed to sign out sessions: %w", err)
+	}
+
 	s.logger.LogInfo(ctx, "user password changed",
 		logger.String("user_id", userID))
 
@@ -134,6 +156,15 @@ func (s *UserService) List(ctx context.Context, limit, offset int) ([]*UserRespo
 	return responses, total, nil
 }
 
+// ListUsers lists users newest first, or, when search is not empty, the
+// users whose email or username starts with it (admin operation)
+func (s *UserService) ListUsers(ctx context.Context, search string, limit, offset int) ([]*UserResponse, int64, error) {
+	if search == "" {
+		return s.List(ctx, limit, offset)
+	}
+	return s.SearchUsers(ctx, search, limit, offset)
+}
+
 // Search searches users
 func (s *UserService) Search(ctx context.Context, query string, limit, offset int) ([]*UserResponse, int64, error) {
 	users, total, err := s.userRepo.Search(ctx, query, limit, offset)
@@ -149,6 +180,45 @@ func (s *UserService) Search(ctx context.Context, query string, limit, offset in
 	return responses, total, nil
 }
 
+// MaxSearchQueryLength bounds the prefix accepted by SearchUsers
+const MaxSearchQueryLength = 100
+
+// ErrInvalidSearchQuery is returned when a user search query is empty or too long
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// SearchUsers finds users whose email or username starts with query,
+// ignoring case (admin operation)
+func (s *UserService) SearchUsers(ctx context.Context, query string, limit, offset int) ([]*UserResponse, int64, error) {
+	prefix, err := normalizeSearchQuery(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users, total, err := s.userRepo.SearchByPrefix(ctx, prefix, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	responses := make([]*UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = s.userToResponse(user)
+	}
+
+	return responses, total, nil
+}
+
+// normalizeSearchQuery trims query and checks its length
+func normalizeSearchQuery(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return "", fmt.Errorf("%w: query is required", ErrInvalidSearchQuery)
+	}
+	if len(query) > MaxSearchQueryLength {
+		return "", fmt.Errorf("%w: query exceeds %d characters", ErrInvalidSearchQuery, MaxSearchQueryLength)
+	}
+	return query, nil
+}
+
 // UpdateUser updates a user (admin operation)
 func (s *UserService) UpdateUser(ctx context.Context, userID string, req *UpdateUserRequest) (*UserResponse, error) {
 	id, err := uuid.Parse(userID)
@@ -175,7 +245,7 @@ func (s *UserService) UpdateUser(ctx context.Context, userID string, req *Update
 			return nil, fmt.Errorf("failed to check email existence: %w", err)
 		}
 		if emailExists && req.Email != user.Email {
-			return nil, fmt.Errorf("email already exists")
+			return nil, fmt.Errorf("%w: email is taken", ErrUserExists)
 		}
 		user.Email = req.Email
 	}
@@ -196,23 +266,52 @@ func (s *UserService) UpdateUser(ctx context.Context, userID string, req *Update
 	return s.userToResponse(user), nil
 }
 
-// DeleteUser deletes a user (admin operation)
-func (s *UserService) DeleteUser(ctx context.Context, userID string) error {
+// DeleteUser erases a user and asks every other service to erase their data
+// (admin operation). Erasure is refused while the user still holds credits or
+// active certificates. The request to other services is written to the outbox
+// with the erasure and published by the outbox relay.
+func (s *UserService) DeleteUser(ctx context.Context, userID, deletedBy string) error {
 	id, err := uuid.Parse(userID)
 	if err != nil {
 		return fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	if err := s.userRepo.Delete(ctx, id); err != nil {
+	if err := s.erasureGuard.CheckErasable(ctx, userID); err != nil {
+		s.logger.LogWarn(ctx, "user erasure refused",
+			logger.String("user_id", userID),
+			logger.String("reason", err.Error()))
+		return err
+	}
+
+	event, err := events.NewUserDeletedOutboxEvent(&events.UserDeletedEvent{
+		UserID:    userID,
+		DeletedBy: deletedBy,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Erase(ctx, id, event); err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
 	s.logger.LogInfo(ctx, "user deleted by admin",
-		logger.String("user_id", userID))
+		logger.String("user_id", userID),
+		logger.String("deleted_by", deletedBy))
 
 	return nil
 }
 
+// ErrRoleNotFound is returned when assigning or removing an unknown role
+var ErrRoleNotFound = errors.New("role not found")
+
+// ErrRoleAlreadyAssigned is returned when assigning a role the user already has
+var ErrRoleAlreadyAssigned = errors.New("role already assigned")
+
+// ErrRoleNotAssigned is returned when removing a role the user does not have
+var ErrRoleNotAssigned = errors.New("role not assigned")
+
 // AssignRole assigns a role to a user (admin operation)
 func (s *UserService) AssignRole(ctx context.Context, userID, roleID string) error {
 	uid, err := uuid.Parse(userID)
@@ -226,7 +325,7 @@ func (s *UserService) AssignRole(ctx context.Context, userID, roleID string) err
 	}
 
 	if err := s.userRepo.AssignRole(ctx, uid, rid); err != nil {
-		return fmt.Errorf("failed to assign role: %w", err)
+		return roleError(err, "failed to assign role")
 	}
 
 	s.logger.LogInfo(ctx, "role assigned to user",
@@ -249,7 +348,7 @@ func (s *UserService) RemoveRole(ctx context.Context, userID, roleID string) err
 	}
 
 	if err := s.userRepo.RemoveRole(ctx, uid, rid); err != nil {
-		return fmt.Errorf("failed to remove role: %w", err)
+		return roleError(err, "failed to remove role")
 	}
 
 	s.logger.LogInfo(ctx, "role removed from user",
@@ -259,6 +358,21 @@ func (s *UserService) RemoveRole(ctx context.Context, userID, roleID string) err
 	return nil
 }
 
+// roleError translates role assignment errors from the repository into the
+// service's errors
+func roleError(err error, action string) error {
+	switch {
+	case errors.Is(err, repository.ErrRoleNotFound):
+		return ErrRoleNotFound
+	case errors.Is(err, repository.ErrRoleAlreadyAssigned):
+		return ErrRoleAlreadyAssigned
+	case errors.Is(err, repository.ErrRoleNotAssigned):
+		return ErrRoleNotAssigned
+	default:
+		return fmt.Errorf("%s: %w", action, err)
+	}
+}
+
 // userToResponse converts a user model to response format
 func (s *UserService) userToResponse(user *models.User) *UserResponse {
 	response := &UserResponse{
@@ -288,9 +402,9 @@ func (s *UserService) userToResponse(user *models.User) *UserResponse {
 
 // Additional request/response types
 type UpdateUserRequest struct {
-	FirstName  string `json:"first_name"`
-	LastName   string `json:"last_name"`
-	Email      string `json:"email"`
+	FirstName  string `json:"first_name" binding:"omitempty,max=100"`
+	LastName   string `json:"last_name" binding:"omitempty,max=100"`
+	Email      string `json:"email" binding:"omitempty,email"`
 	IsActive   *bool  `json:"is_active"`
 	IsVerified *bool  `json:"is_verified"`
 }
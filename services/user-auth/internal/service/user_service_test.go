@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/user-auth/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/proto/usererasure"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type stubErasureGuard struct {
+	err error
+}
+
+func (g *stubErasureGuard) CheckErasable(ctx context.Context, userID string) error {
+	return g.err
+}
+
+// stubErasureClient answers erasure checks with err
+type stubErasureClient struct {
+	err error
+}
+
+func (c *stubErasureClient) CheckErasable(ctx context.Context, in *usererasure.CheckErasableRequest, opts ...grpc.CallOption) (*usererasure.CheckErasableResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &usererasure.CheckErasableResponse{}, nil
+}
+
+func TestGRPCErasureGuard_CheckErasable(t *testing.T) {
+	tests := []struct {
+		name         string
+		walletErr    error
+		certifierErr error
+		blocked      bool
+		failed       bool
+	}{
+		{"no assets", nil, nil, false, false},
+		{"non-zero balance", status.Error(codes.FailedPrecondition, "wallet balance is not zero"), nil, true, false},
+		{"active certificate", nil, status.Error(codes.FailedPrecondition, "user has active certificates: 2 still active"), true, false},
+		{"wallet unavailable", status.Error(codes.Unavailable, "connection refused"), nil, false, true},
+	}
+
+	for _, tt := range tests {
+		guard := NewGRPCErasureGuard(
+			ErasureCheck{Service: "wallet", Client: &stubErasureClient{err: tt.walletErr}},
+			ErasureCheck{Service: "certifier", Client: &stubErasureClient{err: tt.certifierErr}},
+		)
+
+		err := guard.CheckErasable(context.Background(), "user-1")
+		if blocked := errors.Is(err, ErrErasureBlocked); blocked != tt.blocked {
+			t.Errorf("%s: expected blocked=%v, got %v (err: %v)", tt.name, tt.blocked, blocked, err)
+		}
+		if failed := err != nil && !errors.Is(err, ErrErasureBlocked); failed != tt.failed {
+			t.Errorf("%s: expected failed=%v, got %v", tt.name, tt.failed, err)
+		}
+	}
+}
+
+func TestUserService_DeleteUser_RefusedByGuard(t *testing.T) {
+	log := logger.New("error")
+	guard := &stubErasureGuard{err: fmt.Errorf("%w: wallet balance is not zero", ErrErasureBlocked)}
+	// A nil repository fails the test if the refused user is erased
+	userService := NewUserService(nil, nil, guard, log)
+
+	err := userService.DeleteUser(context.Background(), uuid.New().String(), "admin")
+	if !errors.Is(err, ErrErasureBlocked) {
+		t.Fatalf("Expected ErrErasureBlocked, got %v", err)
+	}
+}
+
+func TestNormalizeSearchQuery(t *testing.T) {
+	if query, err := normalizeSearchQuery("  Alice "); err != nil || query != "Alice" {
+		t.Errorf("Expected trimmed query, got %q (%v)", query, err)
+	}
+	if _, err := normalizeSearchQuery("   "); !errors.Is(err, ErrInvalidSearchQuery) {
+		t.Errorf("Expected ErrInvalidSearchQuery for blank query, got %v", err)
+	}
+	if _, err := normalizeSearchQuery(strings.Repeat("a", MaxSearchQueryLength+1)); !errors.Is(err, ErrInvalidSearchQuery) {
+		t.Errorf("Expected ErrInvalidSearchQuery for long query, got %v", err)
+	}
+}
+
+func TestOwnedSession(t *testing.T) {
+	owner := uuid.New()
+	session := &models.Session{ID: uuid.New(), UserID: owner, IsActive: true}
+
+	if got, err := ownedSession(session, nil, owner); err != nil || got != session {
+		t.Errorf("Expected owner to get their session, got %v (%v)", got, err)
+	}
+
+	// Another user revoking the session must not learn that it exists
+	if _, err := ownedSession(session, nil, uuid.New()); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound for another user, got %v", err)
+	}
+
+	if _, err := ownedSession(nil, database.ErrNotFound, owner); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound for a missing session, got %v", err)
+	}
+
+	revoked := &models.Session{ID: uuid.New(), UserID: owner, IsActive: false}
+	if _, err := ownedSession(revoked, nil, owner); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound for a revoked session, got %v", err)
+	}
+
+	lookupErr := errors.New("connection refused")
+	if _, err := ownedSession(nil, lookupErr, owner); !errors.Is(err, lookupErr) || errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected lookup errors to pass through, got %v", err)
+	}
+}
+
+func TestRevokeSession_InvalidSessionID(t *testing.T) {
+	userService := NewUserService(nil, nil, nil, logger.New("error"))
+
+	err := userService.RevokeSession(context.Background(), uuid.New().String(), "not-a-uuid")
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestSessionToResponse_HidesToken(t *testing.T) {
+	token := "0123456789abcdef0123456789abcdef"
+	response := sessionToResponse(&models.Session{ID: uuid.New(), Token: token})
+
+	if response.TokenHint != "01234567..." {
+		t.Errorf("Expected token hint 01234567..., got %s", response.TokenHint)
+	}
+	if hint := tokenHint("abcd"); hint != "ab..." {
+		t.Errorf("Expected short tokens to be half hidden, got %s", hint)
+	}
+}
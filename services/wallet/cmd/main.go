@@ -2,12 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,8 +17,24 @@ import (
 	"github.com/sloweyyy/GreenLedger/services/wallet/internal/service"
 	"github.com/sloweyyy/GreenLedger/shared/config"
 	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/events"
+	"github.com/sloweyyy/GreenLedger/shared/featureflags"
 	sharedLogger "github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/sloweyyy/GreenLedger/shared/middleware"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
+	"github.com/sloweyyy/GreenLedger/shared/proto/reportdata"
+	"github.com/sloweyyy/GreenLedger/shared/proto/usererasure"
+	"github.com/sloweyyy/GreenLedger/shared/proto/walletcredits"
+	sharedServer "github.com/sloweyyy/GreenLedger/shared/server"
+	"github.com/sloweyyy/GreenLedger/shared/version"
+	"google.golang.org/grpc"
+)
+
+// Build information, injected at build time via -ldflags "-X main.Version=..."
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
 )
 
 // @title GreenLedger Wallet Service API
@@ -57,21 +72,25 @@ func main() {
 	// Initialize logger
 	logger := sharedLogger.New(cfg.Server.LogLevel).WithService("wallet")
 
+	// Initialize metrics
+	metrics := monitoring.NewMetrics("wallet")
+
 	// Initialize database
 	db, err := database.NewPostgresDB(&cfg.Database, logger)
 	if err != nil {
 		logger.LogError(context.Background(), "failed to connect to database", err)
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer db.Close()
 
 	// Run database migrations
 	if err := db.Migrate(
 		&models.Wallet{},
 		&models.Transaction{},
+		&models.ArchivedTransaction{},
 		&models.TransactionBatch{},
 		&models.CreditReservation{},
 		&models.WalletSnapshot{},
+		&events.DeadLetter{},
 	); err != nil {
 		logger.LogError(context.Background(), "failed to run migrations", err)
 		log.Fatalf("Failed to run migrations: %v", err)
@@ -81,37 +100,55 @@ func main() {
 	walletRepo := repository.NewWalletRepository(db, logger)
 	transactionRepo := repository.NewTransactionRepository(db, logger)
 
+	// Events this service's consumers give up on
+	deadLetters := events.NewDeadLetterQueue(db, logger)
+
 	// Initialize event publisher
 	var eventPublisher service.EventPublisher
-	if cfg.Server.Environment == "production" {
+	if cfg.Features.Enabled(featureflags.Kafka) {
 		eventPublisher = service.NewKafkaEventPublisher(cfg.Kafka.Brokers, logger)
 	} else {
 		eventPublisher = service.NewMockEventPublisher(logger)
 	}
 
+	creditRounding, err := cfg.Credits.RoundingPolicy()
+	if err != nil {
+		log.Fatalf("Invalid credit rounding policy: %v", err)
+	}
+
 	// Initialize services
 	walletService := service.NewWalletService(
 		walletRepo,
 		transactionRepo,
 		eventPublisher,
+		decimal.NewFromFloat(cfg.Wallet.MinTransactionAmount),
+		creditRounding,
+		metrics,
 		logger,
 	)
 
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(cfg.Server.JWTSecret, logger)
+	authMiddleware := middleware.NewAuthMiddleware(cfg.Server.JWTSecret, cfg.Server.JWTIssuer, cfg.Server.JWTAudience, logger)
 
 	// Initialize handlers
 	walletHandler := handler.NewWalletHandler(walletService, logger)
 
 	// Setup Gin router
-	if cfg.Server.Environment == "production" {
+	if cfg.Features.Enabled(featureflags.ReleaseMode) {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.LogError(context.Background(), "failed to set trusted proxies", err)
+		log.Fatalf("Failed to set trusted proxies: %v", err)
+	}
 	router.Use(gin.Recovery())
-	router.Use(middleware.RequestLogger(logger))
+	router.Use(middleware.RequestLogger(logger, cfg.Server.SlowRequestThreshold))
+	router.Use(middleware.Pagination(cfg.Pagination.DefaultLimit, cfg.Pagination.MaxLimit))
+	router.Use(middleware.BatchLimit(cfg.Pagination.MaxBatchItems))
 	router.Use(middleware.CORS())
+	router.Use(middleware.MaxBodySize(cfg.Server.MaxBodyBytes))
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -125,13 +162,20 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "healthy",
 			"service": "wallet",
-			"version": "1.0.0",
+			"version": Version,
 		})
 	})
 
+	// Prometheus metrics endpoint
+	router.GET("/metrics", monitoring.MetricsHandler())
+
+	// Build info endpoint
+	router.GET("/version", version.Handler(version.New("wallet", Version, GitCommit, BuildTime)))
+
 	// API routes
 	v1 := router.Group("/api/v1")
 	walletHandler.RegisterRoutes(v1, authMiddleware)
+	events.NewDeadLetterHandler(deadLetters, logger).RegisterRoutes(v1.Group("/wallet"), authMiddleware)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -142,22 +186,50 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	// Start event consumer for credit earned events
-	go func() {
-		if cfg.Server.Environment == "production" {
-			consumer := service.NewEventConsumer(cfg.Kafka.Brokers, "wallet-service", logger)
-			defer consumer.Close()
+	// Cancelled on SIGINT/SIGTERM so background consumers stop before their
+	// readers, the event publisher and the database are closed
+	ctx, stop := sharedServer.NotifyContext(context.Background())
+	defer stop()
+
+	// Serve report data to the reporting service, credit debits to the
+	// certifier and erasure checks to user-auth over gRPC
+	grpcServer := grpc.NewServer()
+	reportdata.RegisterWalletDataServer(grpcServer, handler.NewReportDataServer(db, logger))
+	walletcredits.RegisterWalletCreditsServer(grpcServer, handler.NewCreditsServer(walletService, logger))
+	usererasure.RegisterUserErasureServer(grpcServer, handler.NewErasureServer(walletService, logger))
+	grpcCloser, err := sharedServer.ServeGRPC(grpcServer, fmt.Sprintf(":%d", cfg.Server.GRPCPort), logger)
+	if err != nil {
+		logger.LogError(context.Background(), "failed to start gRPC server", err)
+		log.Fatalf("Failed to start gRPC server: %v", err)
+	}
+
+	closers := []io.Closer{grpcCloser}
 
-			err := consumer.ConsumeEvents(context.Background(), func(ctx context.Context, event interface{}) error {
+	// Start event consumer for credit earned, revoked and adjusted events
+	if cfg.Features.Enabled(featureflags.Kafka) {
+		consumer := service.NewEventConsumer(cfg.Kafka.Brokers, "wallet-service", metrics, deadLetters, logger)
+		closers = append(closers, consumer)
+
+		go func() {
+			err := consumer.ConsumeEvents(ctx, func(ctx context.Context, event interface{}) error {
 				switch e := event.(type) {
 				case *service.CreditEarnedEvent:
 					// Credit user's wallet when they earn credits from activities
+					// or challenge bonuses
+					source := e.Source
+					if source == "" {
+						source = models.CreditSourceEcoActivity
+					}
 					req := &service.CreditBalanceRequest{
 						UserID:      e.UserID,
 						Amount:      decimal.NewFromFloat(e.CreditsEarned),
-						Source:      "eco_activity",
+						Source:      source,
 						Description: fmt.Sprintf("Credits earned from %s: %s", e.ActivityType, e.Description),
 						ReferenceID: e.ActivityID,
+						// Redeliveries of the event race each other; the key
+						// lets only one of them credit the wallet
+						IdempotencyKey: fmt.Sprintf("%s:%s", models.TransactionTypeCreditEarned, e.ActivityID),
+						SystemCredit:   true,
 					}
 
 					_, err := walletService.CreditBalance(ctx, req)
@@ -171,8 +243,46 @@ func main() {
 					logger.LogInfo(ctx, "wallet credited from eco activity",
 						sharedLogger.String("user_id", e.UserID),
 						sharedLogger.String("activity_id", e.ActivityID),
+						sharedLogger.String("source", source),
 						sharedLogger.Float64("credits", e.CreditsEarned))
 
+					return nil
+				case *service.CreditRevokedEvent:
+					// Take back credits earned from an activity that was deleted.
+					// An activity's credit is revoked at most once, so its ID
+					// identifies the revocation across redeliveries.
+					req := &service.ReverseCreditRequest{
+						UserID:         e.UserID,
+						ReferenceID:    e.ActivityID,
+						Description:    fmt.Sprintf("Credits reversed for %s: %s", e.ActivityType, e.Reason),
+						IdempotencyKey: fmt.Sprintf("%s:%s", models.TransactionTypeCreditReversed, e.ActivityID),
+					}
+
+					if _, err := walletService.ReverseCredit(ctx, req); err != nil {
+						logger.LogError(ctx, "failed to reverse activity credit", err,
+							sharedLogger.String("user_id", e.UserID),
+							sharedLogger.String("activity_id", e.ActivityID))
+						return err
+					}
+
+					return nil
+				case *service.CreditAdjustedEvent:
+					// Correct credits earned from an activity that was edited
+					req := &service.AdjustCreditRequest{
+						UserID:         e.UserID,
+						ReferenceID:    e.ActivityID,
+						Amount:         decimal.NewFromFloat(e.CreditsDelta),
+						Description:    fmt.Sprintf("Credits adjusted for %s: %s", e.ActivityType, e.Reason),
+						IdempotencyKey: fmt.Sprintf("%s:%s", models.TransactionTypeAdjustment, e.AdjustmentID),
+					}
+
+					if _, err := walletService.AdjustCredit(ctx, req); err != nil {
+						logger.LogError(ctx, "failed to adjust activity credit", err,
+							sharedLogger.String("user_id", e.UserID),
+							sharedLogger.String("activity_id", e.ActivityID))
+						return err
+					}
+
 					return nil
 				default:
 					logger.LogWarn(ctx, "unknown event type received")
@@ -180,43 +290,57 @@ func main() {
 				}
 			})
 
-			if err != nil {
+			if err != nil && !errors.Is(err, context.Canceled) {
 				logger.LogError(context.Background(), "event consumer error", err)
 			}
-		}
-	}()
+		}()
+	}
 
-	// Start server in a goroutine
-	go func() {
-		logger.LogInfo(context.Background(), "starting wallet service",
-			sharedLogger.Int("port", cfg.Server.Port),
-			sharedLogger.String("environment", cfg.Server.Environment))
+	// Start user event consumer for erasure requests
+	if cfg.Features.Enabled(featureflags.Kafka) {
+		userConsumer := events.NewUserEventConsumer(cfg.Kafka.Brokers, "wallet-service", deadLetters, logger)
+		closers = append(closers, userConsumer)
 
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.LogError(context.Background(), "failed to start server", err)
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
+		go func() {
+			err := userConsumer.Consume(ctx, func(ctx context.Context, event *events.UserDeletedEvent) error {
+				return walletService.EraseUserData(ctx, event.UserID)
+			})
+			if err != nil && !errors.Is(err, context.Canceled) {
+				logger.LogError(context.Background(), "user event consumer error", err)
+			}
+		}()
+	}
 
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	// Archive settled transactions past the retention period
+	if cfg.Wallet.TransactionRetention > 0 {
+		archiver := service.NewTransactionArchiver(transactionRepo, cfg.Wallet.TransactionRetention, logger)
+		go archiver.Run(ctx, cfg.Wallet.ArchiveInterval)
+	}
 
-	logger.LogInfo(context.Background(), "shutting down wallet service")
+	// Release credit reservations whose hold has expired
+	sweeper := service.NewReservationSweeper(walletService, logger)
+	go sweeper.Run(ctx, cfg.Wallet.ReservationSweepInterval)
 
-	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	// Snapshot balances of recently updated wallets for historical queries
+	if cfg.Wallet.SnapshotInterval > 0 {
+		snapshotter := service.NewWalletSnapshotter(walletService, logger)
+		go snapshotter.Run(ctx, cfg.Wallet.SnapshotInterval)
+	}
 
-	if err := server.Shutdown(ctx); err != nil {
-		logger.LogError(context.Background(), "server forced to shutdown", err)
-		log.Fatalf("Server forced to shutdown: %v", err)
+	// Close the event publisher once the consumers have stopped
+	if closer, ok := eventPublisher.(io.Closer); ok {
+		closers = append(closers, closer)
 	}
+	closers = append(closers, db)
+
+	logger.LogInfo(context.Background(), "starting wallet service",
+		sharedLogger.Int("port", cfg.Server.Port),
+		sharedLogger.Int("grpc_port", cfg.Server.GRPCPort),
+		sharedLogger.String("environment", cfg.Server.Environment),
+		sharedLogger.Any("features", cfg.Features.EnabledNames()))
 
-	// Close event publisher
-	if kafkaPublisher, ok := eventPublisher.(*service.KafkaEventPublisher); ok {
-		kafkaPublisher.Close()
+	if err := sharedServer.RunWithGracefulShutdown(ctx, server, logger, closers...); err != nil {
+		log.Fatalf("wallet service stopped with error: %v", err)
 	}
 
 	logger.LogInfo(context.Background(), "wallet service stopped")
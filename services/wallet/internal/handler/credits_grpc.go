@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/service"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/proto/walletcredits"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreditsServer lets other services spend and move users' credits over gRPC
+type CreditsServer struct {
+	walletcredits.UnimplementedWalletCreditsServer
+
+	walletService *service.WalletService
+	logger        *logger.Logger
+}
+
+// NewCreditsServer creates a new credits server
+func NewCreditsServer(walletService *service.WalletService, logger *logger.Logger) *CreditsServer {
+	return &CreditsServer{
+		walletService: walletService,
+		logger:        logger,
+	}
+}
+
+// DebitCredits debits credits from a user's wallet, once per reference. A
+// user without a wallet has no credits to spend.
+func (s *CreditsServer) DebitCredits(ctx context.Context, req *walletcredits.DebitCreditsRequest) (*walletcredits.DebitCreditsResponse, error) {
+	if req.GetUserId() == "" || req.GetReferenceId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and reference_id are required")
+	}
+
+	amount, err := decimal.NewFromString(req.GetAmount())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid amount %q", req.GetAmount())
+	}
+
+	transaction, err := s.walletService.DebitBalance(ctx, &service.DebitBalanceRequest{
+		UserID:      req.GetUserId(),
+		Amount:      amount,
+		Description: req.GetDescription(),
+		ReferenceID: req.GetReferenceId(),
+	})
+	switch {
+	case errors.Is(err, service.ErrInsufficientBalance), errors.Is(err, database.ErrNotFound):
+		return nil, status.Error(codes.FailedPrecondition, "insufficient balance")
+	case errors.Is(err, service.ErrInvalidAmount):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		s.logger.LogError(ctx, "failed to debit credits", err,
+			logger.String("user_id", req.GetUserId()),
+			logger.String("reference_id", req.GetReferenceId()))
+		return nil, status.Error(codes.Internal, "failed to debit credits")
+	}
+
+	return &walletcredits.DebitCreditsResponse{TransactionId: transaction.ID.String()}, nil
+}
+
+// TransferCredits moves credits between users' wallets, once per reference
+func (s *CreditsServer) TransferCredits(ctx context.Context, req *walletcredits.TransferCreditsRequest) (*walletcredits.TransferCreditsResponse, error) {
+	if req.GetFromUserId() == "" || req.GetToUserId() == "" || req.GetReferenceId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "from_user_id, to_user_id and reference_id are required")
+	}
+
+	amount, err := decimal.NewFromString(req.GetAmount())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid amount %q", req.GetAmount())
+	}
+
+	transfer, err := s.walletService.TransferCredits(ctx, &service.TransferCreditsRequest{
+		FromUserID:  req.GetFromUserId(),
+		ToUserID:    req.GetToUserId(),
+		Amount:      amount,
+		Description: req.GetDescription(),
+		ReferenceID: req.GetReferenceId(),
+	})
+	switch {
+	case errors.Is(err, service.ErrInsufficientBalance), errors.Is(err, database.ErrNotFound):
+		return nil, status.Error(codes.FailedPrecondition, "insufficient balance")
+	case errors.Is(err, service.ErrInvalidAmount), errors.Is(err, service.ErrSelfTransfer):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		s.logger.LogError(ctx, "failed to transfer credits", err,
+			logger.String("from_user_id", req.GetFromUserId()),
+			logger.String("reference_id", req.GetReferenceId()))
+		return nil, status.Error(codes.Internal, "failed to transfer credits")
+	}
+
+	return &walletcredits.TransferCreditsResponse{TransferId: transfer.TransferID}, nil
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/service"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/proto/usererasure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErasureServer tells the user-auth service over gRPC whether a user's
+// wallet still holds credits that block erasing their account
+type ErasureServer struct {
+	usererasure.UnimplementedUserErasureServer
+
+	walletService *service.WalletService
+	logger        *logger.Logger
+}
+
+// NewErasureServer creates a new erasure server
+func NewErasureServer(walletService *service.WalletService, logger *logger.Logger) *ErasureServer {
+	return &ErasureServer{
+		walletService: walletService,
+		logger:        logger,
+	}
+}
+
+// CheckErasable fails with FailedPrecondition while the user's wallet holds credits
+func (s *ErasureServer) CheckErasable(ctx context.Context, req *usererasure.CheckErasableRequest) (*usererasure.CheckErasableResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	err := s.walletService.CheckErasable(ctx, req.GetUserId())
+	switch {
+	case errors.Is(err, service.ErrBalanceNotZero):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		s.logger.LogError(ctx, "failed to check wallet erasure", err,
+			logger.String("user_id", req.GetUserId()))
+		return nil, status.Error(codes.Internal, "failed to check wallet")
+	}
+
+	return &usererasure.CheckErasableResponse{}, nil
+}
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/proto/reportdata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ReportDataServer serves the wallet data the reporting service needs for
+// reports over gRPC
+type ReportDataServer struct {
+	reportdata.UnimplementedWalletDataServer
+
+	db     *database.PostgresDB
+	logger *logger.Logger
+}
+
+// NewReportDataServer creates a new report data server
+func NewReportDataServer(db *database.PostgresDB, logger *logger.Logger) *ReportDataServer {
+	return &ReportDataServer{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetCredits returns a user's balance and completed transactions. A user
+// without a wallet has a zero balance.
+func (s *ReportDataServer) GetCredits(ctx context.Context, req *reportdata.PeriodRequest) (*reportdata.CreditsData, error) {
+	start, end, err := req.Period()
+	if err != nil {
+		return nil, err
+	}
+
+	data := &reportdata.CreditsData{
+		EarnedByMonth: make(map[string]float64),
+	}
+
+	var availableCredits sql.NullFloat64
+	var totalEarned sql.NullFloat64
+	var totalSpent sql.NullFloat64
+
+	walletQuery := `
+		SELECT 
+			COALESCE(available_credits, 0) as available_credits,
+			COALESCE(total_earned, 0) as total_earned,
+			COALESCE(total_spent, 0) as total_spent
+		FROM wallets 
+		WHERE user_id = $1
+	`
+
+	err = s.db.WithContext(ctx).Raw(walletQuery, req.GetUserId()).
+		Row().Scan(&availableCredits, &totalEarned, &totalSpent)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, s.internal(ctx, "failed to get wallet data", err)
+	}
+	data.CurrentBalance = availableCredits.Float64
+	data.TotalEarned = totalEarned.Float64
+	data.TotalSpent = totalSpent.Float64
+
+	groupQuery := `
+		SELECT 
+			source,
+			type,
+			COUNT(*) as count,
+			COALESCE(SUM(amount), 0) as total_amount
+		FROM transactions 
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3 AND status = 'completed'
+		GROUP BY source, type
+	`
+
+	rows, err := s.db.WithContext(ctx).Raw(groupQuery, req.GetUserId(), start, end).Rows()
+	if err != nil {
+		return nil, s.internal(ctx, "failed to get transaction data", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var group reportdata.TransactionGroup
+		var source sql.NullString
+		var amount sql.NullFloat64
+
+		if err := rows.Scan(&source, &group.Type, &group.Count, &amount); err != nil {
+			return nil, s.internal(ctx, "failed to read transaction data", err)
+		}
+
+		if source.Valid {
+			group.Source = &source.String
+		}
+		group.Amount = amount.Float64
+		data.TransactionGroups = append(data.TransactionGroups, &group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, s.internal(ctx, "failed to read transaction data", err)
+	}
+
+	monthQuery := `
+		SELECT 
+			TO_CHAR(DATE_TRUNC('month', created_at), 'YYYY-MM') as month,
+			COALESCE(SUM(CASE WHEN type IN ('credit_earned', 'transfer_in', 'refund', 'bonus') THEN amount ELSE 0 END), 0) as credits_earned
+		FROM transactions 
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3 AND status = 'completed'
+		GROUP BY 1
+	`
+
+	monthRows, err := s.db.WithContext(ctx).Raw(monthQuery, req.GetUserId(), start, end).Rows()
+	if err != nil {
+		return nil, s.internal(ctx, "failed to get monthly credits", err)
+	}
+	defer monthRows.Close()
+
+	for monthRows.Next() {
+		var month string
+		var earned sql.NullFloat64
+
+		if err := monthRows.Scan(&month, &earned); err != nil {
+			return nil, s.internal(ctx, "failed to read monthly credits", err)
+		}
+
+		data.EarnedByMonth[month] = earned.Float64
+	}
+	if err := monthRows.Err(); err != nil {
+		return nil, s.internal(ctx, "failed to read monthly credits", err)
+	}
+
+	recentQuery := `
+		SELECT id::text, type, amount, description,
+			COALESCE(NULLIF(from_user_id, ''), NULLIF(to_user_id, ''), '') as counterparty,
+			created_at
+		FROM transactions 
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3 AND status = 'completed'
+		ORDER BY created_at DESC
+		LIMIT 20
+	`
+
+	recentRows, err := s.db.WithContext(ctx).Raw(recentQuery, req.GetUserId(), start, end).Rows()
+	if err != nil {
+		return nil, s.internal(ctx, "failed to get recent transactions", err)
+	}
+	defer recentRows.Close()
+
+	for recentRows.Next() {
+		var record reportdata.TransactionRecord
+		var amount sql.NullFloat64
+		var createdAt time.Time
+
+		if err := recentRows.Scan(&record.Id, &record.Type, &amount, &record.Description, &record.Counterparty, &createdAt); err != nil {
+			return nil, s.internal(ctx, "failed to read recent transactions", err)
+		}
+
+		record.Amount = amount.Float64
+		record.CreatedAt = timestamppb.New(createdAt)
+		data.RecentTransactions = append(data.RecentTransactions, &record)
+	}
+	if err := recentRows.Err(); err != nil {
+		return nil, s.internal(ctx, "failed to read recent transactions", err)
+	}
+
+	return data, nil
+}
+
+// GetPlatformTotals returns totals across every wallet
+func (s *ReportDataServer) GetPlatformTotals(ctx context.Context, req *reportdata.PlatformTotalsRequest) (*reportdata.WalletPlatformTotals, error) {
+	var totalEarned sql.NullFloat64
+
+	query := `SELECT COALESCE(SUM(total_earned), 0) as total_earned FROM wallets`
+
+	if err := s.db.WithContext(ctx).Raw(query).Row().Scan(&totalEarned); err != nil {
+		return nil, s.internal(ctx, "failed to get total credits earned", err)
+	}
+
+	return &reportdata.WalletPlatformTotals{TotalCreditsEarned: totalEarned.Float64}, nil
+}
+
+// ListFeedEntries returns a user's most recent completed credit and
+// transfer transactions before a time
+func (s *ReportDataServer) ListFeedEntries(ctx context.Context, req *reportdata.FeedRequest) (*reportdata.FeedEntries, error) {
+	if req.GetBefore() == nil || req.GetLimit() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "before and a positive limit are required")
+	}
+
+	query := `
+		SELECT id::text, type, description, amount, created_at as occurred_at
+		FROM transactions
+		WHERE user_id = $1 AND created_at < $2 AND status = 'completed'
+			AND type IN ('credit_earned', 'bonus', 'transfer_in', 'transfer_out')
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := s.db.WithContext(ctx).Raw(query, req.GetUserId(), req.GetBefore().AsTime(), req.GetLimit()).Rows()
+	if err != nil {
+		return nil, s.internal(ctx, "failed to list feed entries", err)
+	}
+	defer rows.Close()
+
+	entries, err := reportdata.ScanFeedEntries(rows)
+	if err != nil {
+		return nil, s.internal(ctx, "failed to read feed entries", err)
+	}
+	return entries, nil
+}
+
+// ExportTransactions returns every transaction of a user, leaving out
+// idempotency keys and the admins who made changes
+func (s *ReportDataServer) ExportTransactions(ctx context.Context, req *reportdata.UserRequest) (*reportdata.ExportRows, error) {
+	query := `
+		SELECT
+			id, user_id, type, status, amount, balance_after, source,
+			description, reference_id, from_user_id, to_user_id, metadata,
+			processed_at, reversed_at, created_at, updated_at
+		FROM transactions
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	var rows []map[string]interface{}
+	if err := s.db.WithContext(ctx).Raw(query, req.GetUserId()).Scan(&rows).Error; err != nil {
+		return nil, s.internal(ctx, "failed to get transactions", err)
+	}
+
+	export, err := reportdata.NewExportRows(rows)
+	if err != nil {
+		return nil, s.internal(ctx, "failed to export transactions", err)
+	}
+	return export, nil
+}
+
+// internal logs a failed query and hides its details from the caller
+func (s *ReportDataServer) internal(ctx context.Context, message string, err error) error {
+	s.logger.LogError(ctx, message, err)
+	return status.Error(codes.Internal, message)
+}
@@ -2,12 +2,12 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/wallet/internal/service"
+	"github.com/sloweyyy/GreenLedger/shared/httperr"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/sloweyyy/GreenLedger/shared/middleware"
 	"github.com/shopspring/decimal"
@@ -16,6 +16,7 @@ import (
 // WalletHandler handles HTTP requests for wallet operations
 type WalletHandler struct {
 	walletService *service.WalletService
+	errMapper     *httperr.Mapper
 	logger        *logger.Logger
 }
 
@@ -23,7 +24,25 @@ type WalletHandler struct {
 func NewWalletHandler(walletService *service.WalletService, logger *logger.Logger) *WalletHandler {
 	return &WalletHandler{
 		walletService: walletService,
-		logger:        logger,
+		errMapper: httperr.New(logger,
+			httperr.Mapping{Err: service.ErrInvalidAmount, Status: http.StatusBadRequest, Message: "Invalid amount"},
+			httperr.Mapping{Err: service.ErrSelfTransfer, Status: http.StatusBadRequest, Message: "Cannot transfer to yourself"},
+			httperr.Mapping{Err: service.ErrNotRefundable, Status: http.StatusBadRequest, Message: "Transaction cannot be refunded"},
+			httperr.Mapping{Err: service.ErrNotReversible, Status: http.StatusBadRequest, Message: "Transaction cannot be reversed"},
+			httperr.Mapping{Err: service.ErrInvalidSnapshotInterval, Status: http.StatusBadRequest, Message: "Invalid snapshot interval"},
+			httperr.Mapping{Err: service.ErrInvalidBatchOperation, Status: http.StatusBadRequest, Message: "Invalid batch operation"},
+			httperr.Mapping{Err: service.ErrInvalidReservationExpiry, Status: http.StatusBadRequest, Message: "Invalid reservation expiry"},
+			httperr.Mapping{Err: service.ErrInvalidCursor, Status: http.StatusBadRequest, Message: "Invalid cursor"},
+			httperr.Mapping{Err: service.ErrInvalidTransactionFilter, Status: http.StatusBadRequest, Message: "Invalid transaction filter"},
+			httperr.Mapping{Err: service.ErrTransactionNotFound, Status: http.StatusNotFound, Message: "Transaction not found"},
+			httperr.Mapping{Err: service.ErrReservationNotFound, Status: http.StatusNotFound, Message: "Reservation not found"},
+			httperr.Mapping{Err: service.ErrReservationNotActive, Status: http.StatusConflict, Message: "Reservation is no longer active"},
+			httperr.Mapping{Err: service.ErrInsufficientBalance, Status: http.StatusConflict, Message: "Insufficient balance"},
+			httperr.Mapping{Err: service.ErrIdempotencyKeyReused, Status: http.StatusConflict, Message: "Idempotency key already used"},
+			httperr.Mapping{Err: service.ErrAlreadyReversed, Status: http.StatusConflict, Message: "Transaction already reversed"},
+			httperr.Mapping{Err: service.ErrRefundExceedsOriginal, Status: http.StatusConflict, Message: "Refund exceeds original amount"},
+		),
+		logger: logger,
 	}
 }
 
@@ -34,9 +53,13 @@ func (h *WalletHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *
 		// Protected routes
 		wallet.Use(authMiddleware.RequireAuth())
 		wallet.GET("/balance", h.GetBalance)
+		wallet.GET("/balance/at", h.GetBalanceAt)
 		wallet.GET("/transactions", h.GetTransactionHistory)
 		wallet.GET("/transactions/:id", h.GetTransactionByID)
 		wallet.POST("/transfer", h.TransferCredits)
+		wallet.POST("/reservations", h.ReserveCredits)
+		wallet.DELETE("/reservations/:id", h.ReleaseReservation)
+		wallet.POST("/reservations/:id/commit", h.CommitReservation)
 		wallet.GET("/stats", h.GetWalletStats)
 
 		// Admin routes
@@ -45,8 +68,13 @@ func (h *WalletHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *
 		{
 			admin.POST("/credit", h.CreditBalance)
 			admin.POST("/debit", h.DebitBalance)
+			admin.POST("/batch", h.ProcessBatch)
+			admin.POST("/transactions/:id/refund", h.RefundTransaction)
+			admin.POST("/transactions/:id/reverse", h.ReverseTransaction)
 			admin.GET("/transactions/pending", h.GetPendingTransactions)
 			admin.GET("/users/top", h.GetTopUsers)
+			admin.POST("/balances", h.GetBalances)
+			admin.POST("/snapshots/backfill", h.BackfillSnapshots)
 		}
 	}
 }
@@ -70,26 +98,66 @@ func (h *WalletHandler) GetBalance(c *gin.Context) {
 
 	balance, err := h.walletService.GetBalance(c.Request.Context(), userID)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to get wallet balance", err,
+		h.errMapper.Respond(c, err, "Failed to get balance",
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to get balance",
-			Details: err.Error(),
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// GetBalanceAt godoc
+// @Summary Get historical wallet balance
+// @Description Get the authenticated user's balance at a point in time, rebuilt from the closest wallet snapshot and the transactions since
+// @Tags wallet
+// @Produce json
+// @Param at query string true "Point in time (RFC3339)"
+// @Success 200 {object} service.BalanceAtResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wallet/balance/at [get]
+func (h *WalletHandler) GetBalanceAt(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	at, err := time.Parse(time.RFC3339, c.Query("at"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid at parameter",
+			Details: "at must be an RFC3339 timestamp",
 		})
 		return
 	}
 
+	balance, err := h.walletService.GetBalanceAt(c.Request.Context(), userID, at)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get balance",
+			logger.String("user_id", userID))
+		return
+	}
+
 	c.JSON(http.StatusOK, balance)
 }
 
 // GetTransactionHistory godoc
 // @Summary Get transaction history
-// @Description Get transaction history for the authenticated user
+// @Description Get transaction history for the authenticated user. Passing cursor (empty for the first page) switches to cursor paging, which returns a TransactionCursorResponse and ignores offset.
 // @Tags wallet
 // @Produce json
-// @Param limit query int false "Limit" default(20)
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
 // @Param offset query int false "Offset" default(0)
+// @Param cursor query string false "Cursor from the previous page's next_cursor"
+// @Param type query string false "Transaction type, e.g. transfer_in"
+// @Param source query string false "Credit source"
+// @Param start_date query string false "Earliest creation time (RFC3339)"
+// @Param end_date query string false "Latest creation time (RFC3339)"
 // @Success 200 {object} TransactionHistoryResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
@@ -101,38 +169,93 @@ func (h *WalletHandler) GetTransactionHistory(c *gin.Context) {
 		return
 	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, offset := middleware.GetPagination(c)
+
+	filter, ok := parseTransactionFilter(c)
+	if !ok {
+		return
+	}
+
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		transactions, nextCursor, err := h.walletService.GetTransactionHistoryCursor(c.Request.Context(), userID, filter, cursor, limit)
+		if err != nil {
+			h.errMapper.Respond(c, err, "Failed to get transaction history",
+				logger.String("user_id", userID))
+			return
+		}
+
+		c.JSON(http.StatusOK, TransactionCursorResponse{
+			Transactions: transactions,
+			Limit:        limit,
+			NextCursor:   nextCursor,
+			HasNext:      nextCursor != "",
+		})
+		return
+	}
 
-	transactions, total, err := h.walletService.GetTransactionHistory(c.Request.Context(), userID, limit, offset)
+	transactions, total, err := h.walletService.GetTransactionHistory(c.Request.Context(), userID, filter, limit, offset)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to get transaction history", err,
+		h.errMapper.Respond(c, err, "Failed to get transaction history",
 			logger.String("user_id", userID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to get transaction history",
-			Details: err.Error(),
-		})
 		return
 	}
 
 	response := TransactionHistoryResponse{
 		Transactions: transactions,
-		Total:        total,
-		Limit:        limit,
-		Offset:       offset,
+		PageInfo:     middleware.NewPageInfo(total, limit, offset),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// parseTransactionFilter reads the optional history filters from the query
+// string, responding with 400 on a malformed date
+func parseTransactionFilter(c *gin.Context) (service.TransactionFilter, bool) {
+	filter := service.TransactionFilter{
+		Type:   c.Query("type"),
+		Source: c.Query("source"),
+	}
+
+	var ok bool
+	if filter.StartDate, ok = parseOptionalTime(c, "start_date"); !ok {
+		return filter, false
+	}
+	if filter.EndDate, ok = parseOptionalTime(c, "end_date"); !ok {
+		return filter, false
+	}
+
+	return filter, true
+}
+
+// parseOptionalTime parses an optional RFC3339 query parameter, responding
+// with 400 when it is malformed
+func parseOptionalTime(c *gin.Context, name string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid " + name,
+			Details: name + " must be an RFC3339 timestamp",
+		})
+		return nil, false
+	}
+
+	return &parsed, true
+}
+
 // GetTransactionByID godoc
 // @Summary Get transaction by ID
-// @Description Get a specific transaction by ID
+// @Description Get one of the authenticated user's transactions by ID. Transactions belonging to other users are reported as not found.
 // @Tags wallet
 // @Produce json
 // @Param id path string true "Transaction ID"
 // @Success 200 {object} service.TransactionResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
@@ -148,11 +271,20 @@ func (h *WalletHandler) GetTransactionByID(c *gin.Context) {
 		return
 	}
 
-	// This would need to be implemented in the service
-	c.JSON(http.StatusOK, gin.H{
-		"message":        "Get transaction by ID - to be implemented",
-		"transaction_id": id.String(),
-	})
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	transaction, err := h.walletService.GetTransactionByID(c.Request.Context(), id, userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get transaction",
+			logger.String("transaction_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
 }
 
 // TransferCredits godoc
@@ -165,6 +297,7 @@ func (h *WalletHandler) GetTransactionByID(c *gin.Context) {
 // @Success 200 {object} service.TransferResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
 // @Router /wallet/transfer [post]
@@ -190,16 +323,133 @@ func (h *WalletHandler) TransferCredits(c *gin.Context) {
 
 	response, err := h.walletService.TransferCredits(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to transfer credits", err,
+		h.errMapper.Respond(c, err, "Failed to transfer credits",
 			logger.String("from_user_id", userID),
 			logger.String("to_user_id", req.ToUserID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to transfer credits",
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReserveCredits godoc
+// @Summary Reserve credits
+// @Description Hold part of the authenticated user's available credits until the reservation is committed, released or expires. Expired reservations are released automatically.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param request body ReserveCreditsRequest true "Reservation request"
+// @Success 201 {object} service.ReservationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wallet/reservations [post]
+func (h *WalletHandler) ReserveCredits(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	var req ReserveCreditsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.walletService.ReserveCredits(c.Request.Context(), userID, req.Amount, req.Reason, req.ExpiresAt)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to reserve credits",
+			logger.String("user_id", userID))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ReleaseReservation godoc
+// @Summary Release a reservation
+// @Description Return the credits held by one of the authenticated user's reservations to their available balance
+// @Tags wallet
+// @Produce json
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} service.ReservationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wallet/reservations/{id} [delete]
+func (h *WalletHandler) ReleaseReservation(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid reservation ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.walletService.ReleaseReservation(c.Request.Context(), id, userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to release reservation",
+			logger.String("reservation_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CommitReservation godoc
+// @Summary Commit a reservation
+// @Description Spend the credits held by one of the authenticated user's active reservations
+// @Tags wallet
+// @Produce json
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} service.TransactionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wallet/reservations/{id}/commit [post]
+func (h *WalletHandler) CommitReservation(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid reservation ID",
 			Details: err.Error(),
 		})
 		return
 	}
 
+	response, err := h.walletService.CommitReservation(c.Request.Context(), id, userID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to commit reservation",
+			logger.String("reservation_id", id.String()))
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -249,7 +499,7 @@ func (h *WalletHandler) GetWalletStats(c *gin.Context) {
 
 // CreditBalance godoc
 // @Summary Credit wallet balance (Admin)
-// @Description Credit a user's wallet balance (admin only)
+// @Description Credit a user's wallet balance (admin only). Repeating a request with the same idempotency_key returns the original transaction.
 // @Tags wallet
 // @Accept json
 // @Produce json
@@ -257,6 +507,7 @@ func (h *WalletHandler) GetWalletStats(c *gin.Context) {
 // @Success 200 {object} service.TransactionResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
 // @Router /wallet/admin/credit [post]
@@ -270,6 +521,9 @@ func (h *WalletHandler) CreditBalance(c *gin.Context) {
 		return
 	}
 
+	// Record the admin making the change
+	actorID, _ := middleware.GetUserID(c)
+
 	// Convert to service request
 	serviceReq := &service.CreditBalanceRequest{
 		UserID:      req.UserID,
@@ -278,16 +532,15 @@ func (h *WalletHandler) CreditBalance(c *gin.Context) {
 		Description: req.Description,
 		ReferenceID: req.ReferenceID,
 		Metadata:    req.Metadata,
+		ActorID:     actorID,
+
+		IdempotencyKey: req.IdempotencyKey,
 	}
 
 	response, err := h.walletService.CreditBalance(c.Request.Context(), serviceReq)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to credit balance", err,
+		h.errMapper.Respond(c, err, "Failed to credit balance",
 			logger.String("user_id", req.UserID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to credit balance",
-			Details: err.Error(),
-		})
 		return
 	}
 
@@ -296,7 +549,7 @@ func (h *WalletHandler) CreditBalance(c *gin.Context) {
 
 // DebitBalance godoc
 // @Summary Debit wallet balance (Admin)
-// @Description Debit a user's wallet balance (admin only)
+// @Description Debit a user's wallet balance (admin only). Repeating a request with the same idempotency_key returns the original transaction.
 // @Tags wallet
 // @Accept json
 // @Produce json
@@ -304,6 +557,7 @@ func (h *WalletHandler) CreditBalance(c *gin.Context) {
 // @Success 200 {object} service.TransactionResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
 // @Router /wallet/admin/debit [post]
@@ -317,6 +571,9 @@ func (h *WalletHandler) DebitBalance(c *gin.Context) {
 		return
 	}
 
+	// Record the admin making the change
+	actorID, _ := middleware.GetUserID(c)
+
 	// Convert to service request
 	serviceReq := &service.DebitBalanceRequest{
 		UserID:      req.UserID,
@@ -324,32 +581,288 @@ func (h *WalletHandler) DebitBalance(c *gin.Context) {
 		Description: req.Description,
 		ReferenceID: req.ReferenceID,
 		Metadata:    req.Metadata,
+		ActorID:     actorID,
+
+		IdempotencyKey: req.IdempotencyKey,
 	}
 
 	response, err := h.walletService.DebitBalance(c.Request.Context(), serviceReq)
 	if err != nil {
-		h.logger.LogError(c.Request.Context(), "failed to debit balance", err,
+		h.errMapper.Respond(c, err, "Failed to debit balance",
 			logger.String("user_id", req.UserID))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to debit balance",
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RefundTransaction godoc
+// @Summary Refund a spend (Admin)
+// @Description Refund part or all of a completed spend back to the user's wallet (admin only)
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param id path string true "Original transaction ID"
+// @Param request body RefundTransactionRequest true "Refund request"
+// @Success 200 {object} service.TransactionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wallet/admin/transactions/{id}/refund [post]
+func (h *WalletHandler) RefundTransaction(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid transaction ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req RefundTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	actorID, _ := middleware.GetUserID(c)
+
+	response, err := h.walletService.RefundTransaction(c.Request.Context(), id, req.Amount, actorID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to refund transaction",
+			logger.String("transaction_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReverseTransaction godoc
+// @Summary Reverse a transaction (Admin)
+// @Description Undo a mistaken credit or debit with a compensating transaction that references it (admin only). A transaction can be reversed once; transfers and refunded spends cannot be reversed.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param id path string true "Original transaction ID"
+// @Param request body ReverseTransactionRequest true "Reversal request"
+// @Success 200 {object} service.TransactionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wallet/admin/transactions/{id}/reverse [post]
+func (h *WalletHandler) ReverseTransaction(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid transaction ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req ReverseTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	actorID, _ := middleware.GetUserID(c)
+
+	response, err := h.walletService.ReverseTransaction(c.Request.Context(), id, req.Reason, actorID)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to reverse transaction",
+			logger.String("transaction_id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetBalances godoc
+// @Summary Get balances for multiple users (Admin)
+// @Description Get wallet balances for a batch of users in one request (admin only)
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param request body BulkBalanceRequest true "Bulk balance request"
+// @Success 200 {object} BulkBalanceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wallet/admin/balances [post]
+func (h *WalletHandler) GetBalances(c *gin.Context) {
+	var req BulkBalanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+	if !middleware.CheckBatchSize(c, len(req.UserIDs)) {
+		return
+	}
+
+	balances, err := h.walletService.GetBalances(c.Request.Context(), req.UserIDs)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get balances",
+			logger.Int("user_count", len(req.UserIDs)))
+		return
+	}
+
+	c.JSON(http.StatusOK, BulkBalanceResponse{Balances: balances})
+}
+
+// ProcessBatch godoc
+// @Summary Process a transaction batch (Admin)
+// @Description Apply several credits and debits atomically (admin only). If any operation fails, for example a debit exceeding the balance, none are applied and the batch is recorded as failed.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param request body service.BatchCreditRequest true "Batch request"
+// @Success 200 {object} service.BatchResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wallet/admin/batch [post]
+func (h *WalletHandler) ProcessBatch(c *gin.Context) {
+	var req service.BatchCreditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
 			Details: err.Error(),
 		})
 		return
 	}
+	if !middleware.CheckBatchSize(c, len(req.Operations)) {
+		return
+	}
+
+	// Record the admin submitting the batch
+	req.ActorID, _ = middleware.GetUserID(c)
+
+	response, err := h.walletService.ProcessBatch(c.Request.Context(), &req)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to process batch",
+			logger.Int("operation_count", len(req.Operations)))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// BackfillSnapshots godoc
+// @Summary Backfill wallet snapshots (Admin)
+// @Description Rebuild historical closing-balance snapshots from the transaction ledger for one wallet or all wallets (admin only)
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param request body service.BackfillSnapshotsRequest false "Backfill request"
+// @Success 200 {object} service.BackfillSnapshotsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wallet/admin/snapshots/backfill [post]
+func (h *WalletHandler) BackfillSnapshots(c *gin.Context) {
+	var req service.BackfillSnapshotsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request body",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+
+	response, err := h.walletService.BackfillSnapshots(c.Request.Context(), &req)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to backfill snapshots")
+		return
+	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-// Placeholder implementations for remaining endpoints
+// GetPendingTransactions godoc
+// @Summary List pending transactions (Admin)
+// @Description List transactions awaiting processing across all users, oldest first (admin only)
+// @Tags wallet
+// @Produce json
+// @Param limit query int false "Limit, capped at the configured maximum" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} TransactionHistoryResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wallet/admin/transactions/pending [get]
 func (h *WalletHandler) GetPendingTransactions(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Get pending transactions - to be implemented"})
+	limit, offset := middleware.GetPagination(c)
+
+	transactions, total, err := h.walletService.ListPendingTransactions(c.Request.Context(), limit, offset)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get pending transactions")
+		return
+	}
+
+	response := TransactionHistoryResponse{
+		Transactions: transactions,
+		PageInfo:     middleware.NewPageInfo(total, limit, offset),
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
+// GetTopUsers godoc
+// @Summary Get top earners (Admin)
+// @Description Rank users by the credits they have earned (admin only)
+// @Tags wallet
+// @Produce json
+// @Param limit query int false "Number of users, capped at the configured maximum" default(20)
+// @Success 200 {object} TopUsersResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wallet/admin/users/top [get]
 func (h *WalletHandler) GetTopUsers(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Get top users - to be implemented"})
+	limit, _ := middleware.GetPagination(c)
+
+	users, err := h.walletService.GetTopUsersByEarned(c.Request.Context(), limit)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get top users")
+		return
+	}
+
+	c.JSON(http.StatusOK, TopUsersResponse{Users: users})
 }
 
 // Request/Response types
+type RefundTransactionRequest struct {
+	Amount decimal.Decimal `json:"amount" binding:"required"`
+}
+
+type ReverseTransactionRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
 type CreditBalanceRequest struct {
 	UserID      string                 `json:"user_id" binding:"required"`
 	Amount      decimal.Decimal        `json:"amount" binding:"required"`
@@ -357,6 +870,9 @@ type CreditBalanceRequest struct {
 	Description string                 `json:"description" binding:"required"`
 	ReferenceID string                 `json:"reference_id"`
 	Metadata    map[string]interface{} `json:"metadata"`
+
+	// IdempotencyKey makes retries safe; a repeated key returns the original transaction
+	IdempotencyKey string `json:"idempotency_key" binding:"omitempty,max=128"`
 }
 
 type DebitBalanceRequest struct {
@@ -365,18 +881,41 @@ type DebitBalanceRequest struct {
 	Description string                 `json:"description" binding:"required"`
 	ReferenceID string                 `json:"reference_id"`
 	Metadata    map[string]interface{} `json:"metadata"`
+
+	// IdempotencyKey makes retries safe; a repeated key returns the original transaction
+	IdempotencyKey string `json:"idempotency_key" binding:"omitempty,max=128"`
 }
 
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Details string `json:"details,omitempty"`
+type ReserveCreditsRequest struct {
+	Amount    decimal.Decimal `json:"amount" binding:"required"`
+	Reason    string          `json:"reason" binding:"required,max=255"`
+	ExpiresAt time.Time       `json:"expires_at" binding:"required"`
 }
 
+type BulkBalanceRequest struct {
+	UserIDs []string `json:"user_ids" binding:"required,min=1"`
+}
+
+type BulkBalanceResponse struct {
+	Balances []*service.WalletResponse `json:"balances"`
+}
+
+type ErrorResponse = httperr.ErrorResponse
+
 type TransactionHistoryResponse struct {
 	Transactions interface{} `json:"transactions"`
-	Total        int64       `json:"total"`
+	middleware.PageInfo
+}
+
+type TopUsersResponse struct {
+	Users []*service.TopUserResponse `json:"users"`
+}
+
+type TransactionCursorResponse struct {
+	Transactions interface{} `json:"transactions"`
 	Limit        int         `json:"limit"`
-	Offset       int         `json:"offset"`
+	NextCursor   string      `json:"next_cursor,omitempty"`
+	HasNext      bool        `json:"has_next"`
 }
 
 type WalletStatsResponse struct {
@@ -14,6 +14,7 @@ type Wallet struct {
 	UserID           string          `gorm:"uniqueIndex;not null" json:"user_id"`
 	AvailableCredits decimal.Decimal `gorm:"type:decimal(15,3);not null;default:0" json:"available_credits"`
 	PendingCredits   decimal.Decimal `gorm:"type:decimal(15,3);not null;default:0" json:"pending_credits"`
+	ReservedCredits  decimal.Decimal `gorm:"type:decimal(15,3);not null;default:0" json:"reserved_credits"` // Held by active reservations, not spendable
 	TotalEarned      decimal.Decimal `gorm:"type:decimal(15,3);not null;default:0" json:"total_earned"`
 	TotalSpent       decimal.Decimal `gorm:"type:decimal(15,3);not null;default:0" json:"total_spent"`
 	LastUpdated      time.Time       `gorm:"not null;default:now()" json:"last_updated"`
@@ -38,7 +39,12 @@ type Transaction struct {
 	FromUserID    string          `gorm:"index" json:"from_user_id"`
 	ToUserID      string          `gorm:"index" json:"to_user_id"`
 	Metadata      string          `gorm:"type:jsonb" json:"metadata"`
+	ActorID       string          `gorm:"index" json:"actor_id,omitempty"` // Admin who made the change, empty for system transactions
+	// IdempotencyKey is set by callers that may retry a credit or debit; the
+	// unique index makes a concurrent retry fail instead of applying twice
+	IdempotencyKey *string `gorm:"uniqueIndex" json:"-"`
 	ProcessedAt   *time.Time      `json:"processed_at"`
+	ReversedAt    *time.Time      `json:"reversed_at,omitempty"` // Set once an admin reverses the transaction
 	CreatedAt     time.Time       `json:"created_at"`
 	UpdatedAt     time.Time       `json:"updated_at"`
 	
@@ -46,6 +52,14 @@ type Transaction struct {
 	Wallet Wallet `gorm:"foreignKey:UserID;references:UserID" json:"-"`
 }
 
+// ArchivedTransaction is a settled transaction moved out of the transactions
+// table by the retention job. It keeps every transaction column so reads can
+// span both tables.
+type ArchivedTransaction struct {
+	Transaction
+	ArchivedAt time.Time `gorm:"not null;default:now();index" json:"archived_at"`
+}
+
 // TransactionBatch represents a batch of transactions for atomic processing
 type TransactionBatch struct {
 	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
@@ -71,8 +85,12 @@ type CreditReservation struct {
 	ExpiresAt   time.Time       `gorm:"not null" json:"expires_at"`
 	IsReleased  bool            `gorm:"default:false" json:"is_released"`
 	ReleasedAt  *time.Time      `json:"released_at"`
+	CommittedAt *time.Time      `json:"committed_at"`
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
+
+	// TransactionID is the spend recorded when the reservation was committed
+	TransactionID *uuid.UUID `gorm:"type:uuid" json:"transaction_id,omitempty"`
 }
 
 // WalletSnapshot represents a point-in-time snapshot of wallet balances
@@ -81,6 +99,7 @@ type WalletSnapshot struct {
 	UserID           string          `gorm:"not null;index" json:"user_id"`
 	AvailableCredits decimal.Decimal `gorm:"type:decimal(15,3);not null" json:"available_credits"`
 	PendingCredits   decimal.Decimal `gorm:"type:decimal(15,3);not null" json:"pending_credits"`
+	ReservedCredits  decimal.Decimal `gorm:"type:decimal(15,3);not null;default:0" json:"reserved_credits"`
 	TotalEarned      decimal.Decimal `gorm:"type:decimal(15,3);not null" json:"total_earned"`
 	TotalSpent       decimal.Decimal `gorm:"type:decimal(15,3);not null" json:"total_spent"`
 	SnapshotDate     time.Time       `gorm:"not null;index" json:"snapshot_date"`
@@ -99,6 +118,10 @@ func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
 	if t.ID == uuid.Nil {
 		t.ID = uuid.New()
 	}
+	// An empty string is not valid jsonb
+	if t.Metadata == "" {
+		t.Metadata = "{}"
+	}
 	return nil
 }
 
@@ -126,6 +149,7 @@ func (ws *WalletSnapshot) BeforeCreate(tx *gorm.DB) error {
 // Table names
 func (Wallet) TableName() string            { return "wallets" }
 func (Transaction) TableName() string       { return "transactions" }
+func (ArchivedTransaction) TableName() string { return "transactions_archive" }
 func (TransactionBatch) TableName() string  { return "transaction_batches" }
 func (CreditReservation) TableName() string { return "credit_reservations" }
 func (WalletSnapshot) TableName() string    { return "wallet_snapshots" }
@@ -140,8 +164,30 @@ const (
 	TransactionTypeRefund       = "refund"
 	TransactionTypePenalty      = "penalty"
 	TransactionTypeBonus        = "bonus"
+
+	// TransactionTypeCreditReversed compensates an earlier credit whose source
+	// (such as an eco activity) was removed
+	TransactionTypeCreditReversed = "credit_reversed"
+
+	// TransactionTypeDebitReversed compensates an earlier spend or penalty
+	// that an admin reversed
+	TransactionTypeDebitReversed = "debit_reversed"
 )
 
+// IsTransactionType reports whether t is one of the known transaction types
+func IsTransactionType(t string) bool {
+	switch t {
+	case TransactionTypeCreditEarned, TransactionTypeCreditSpent,
+		TransactionTypeTransferIn, TransactionTypeTransferOut,
+		TransactionTypeAdjustment, TransactionTypeRefund,
+		TransactionTypePenalty, TransactionTypeBonus,
+		TransactionTypeCreditReversed, TransactionTypeDebitReversed:
+		return true
+	default:
+		return false
+	}
+}
+
 // Transaction statuses
 const (
 	TransactionStatusPending   = "pending"
@@ -153,16 +199,18 @@ const (
 
 // Credit sources
 const (
-	CreditSourceEcoActivity   = "eco_activity"
-	CreditSourceCarbonOffset  = "carbon_offset"
-	CreditSourcePurchase      = "purchase"
-	CreditSourceReward        = "reward"
-	CreditSourceTransfer      = "transfer"
-	CreditSourceAdjustment    = "adjustment"
-	CreditSourceRefund        = "refund"
-	CreditSourceBonus         = "bonus"
-	CreditSourceChallenge     = "challenge"
-	CreditSourceReferral      = "referral"
+	CreditSourceEcoActivity    = "eco_activity"
+	CreditSourceCarbonOffset   = "carbon_offset"
+	CreditSourcePurchase       = "purchase"
+	CreditSourceReward         = "reward"
+	CreditSourceTransfer       = "transfer"
+	CreditSourceAdjustment     = "adjustment"
+	CreditSourceRefund         = "refund"
+	CreditSourceBonus          = "bonus"
+	CreditSourceChallenge      = "challenge"
+	CreditSourceChallengeBonus = "challenge_bonus"
+	CreditSourceStreakBonus    = "streak_bonus"
+	CreditSourceReferral       = "referral"
 )
 
 // Batch statuses
@@ -175,9 +223,12 @@ const (
 
 // Helper methods for Wallet
 func (w *Wallet) GetTotalBalance() decimal.Decimal {
-	return w.AvailableCredits.Add(w.PendingCredits)
+	return w.AvailableCredits.Add(w.PendingCredits).Add(w.ReservedCredits)
 }
 
+// CanSpend reports whether amount is covered by the available balance. Credits
+// held by outstanding reservations are moved out of the available balance, so
+// they cannot be spent twice.
 func (w *Wallet) CanSpend(amount decimal.Decimal) bool {
 	return w.AvailableCredits.GreaterThanOrEqual(amount)
 }
@@ -205,14 +256,26 @@ func (t *Transaction) IsCredit() bool {
 func (t *Transaction) IsDebit() bool {
 	return t.Type == TransactionTypeCreditSpent || 
 		   t.Type == TransactionTypeTransferOut || 
-		   t.Type == TransactionTypePenalty
+		   t.Type == TransactionTypePenalty ||
+		   t.Type == TransactionTypeCreditReversed
+}
+
+// IsCreditAdjustment reports whether the transaction corrects part of an
+// earlier credit sharing its reference, rather than earning or reversing it
+func (t *Transaction) IsCreditAdjustment() bool {
+	return t.Source == CreditSourceAdjustment &&
+		(t.Type == TransactionTypeCreditEarned || t.Type == TransactionTypeCreditReversed)
 }
 
 // Helper methods for CreditReservation
 func (cr *CreditReservation) IsExpired() bool {
-	return time.Now().After(cr.ExpiresAt) && !cr.IsReleased
+	return time.Now().After(cr.ExpiresAt) && !cr.IsReleased && !cr.IsCommitted()
+}
+
+func (cr *CreditReservation) IsCommitted() bool {
+	return cr.CommittedAt != nil
 }
 
 func (cr *CreditReservation) IsActive() bool {
-	return !cr.IsReleased && !cr.IsExpired()
+	return !cr.IsReleased && !cr.IsCommitted() && !cr.IsExpired()
 }
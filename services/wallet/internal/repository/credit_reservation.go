@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm"
+)
+
+// ErrReservationSettled is returned when a reservation has already been
+// released or committed by the time it is settled
+var ErrReservationSettled = errors.New("reservation already settled")
+
+// CreateReservation locks the user's wallet, applies plan to move the credits
+// out of its available balance, and saves the wallet and the reservation
+// holding them, atomically
+func (r *WalletRepository) CreateReservation(ctx context.Context, userID string, reservation *models.CreditReservation, plan WalletPlan) (*models.Wallet, error) {
+	var wallet *models.Wallet
+
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		if wallet, err = lockWallet(tx, userID); err != nil {
+			return err
+		}
+
+		if err := plan(wallet); err != nil {
+			return err
+		}
+
+		if err := tx.Save(wallet).Error; err != nil {
+			return fmt.Errorf("failed to update wallet: %w", err)
+		}
+
+		if err := tx.Create(reservation).Error; err != nil {
+			return fmt.Errorf("failed to create reservation: %w", err)
+		}
+
+		r.logger.LogInfo(ctx, "credits reserved",
+			logger.String("wallet_id", wallet.ID.String()),
+			logger.String("reservation_id", reservation.ID.String()))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// GetReservation retrieves a credit reservation by ID
+func (r *WalletRepository) GetReservation(ctx context.Context, id uuid.UUID) (*models.CreditReservation, error) {
+	var reservation models.CreditReservation
+
+	err := r.db.WithContext(ctx).First(&reservation, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		r.logger.LogError(ctx, "failed to get reservation", err,
+			logger.String("reservation_id", id.String()))
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	return &reservation, nil
+}
+
+// SettleReservation locks the reservation owner's wallet, applies plan to it,
+// and marks the reservation released or committed, saves the wallet and, for
+// a commit, records the spend, atomically. The reservation is only settled if
+// it is still outstanding, so a release racing a commit (or the expiry
+// sweeper) applies once and the loser gets ErrReservationSettled.
+func (r *WalletRepository) SettleReservation(ctx context.Context, reservation *models.CreditReservation, transaction *models.Transaction, plan WalletPlan) (*models.Wallet, error) {
+	var wallet *models.Wallet
+
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		if wallet, err = lockWallet(tx, reservation.UserID); err != nil {
+			return err
+		}
+
+		if err := plan(wallet); err != nil {
+			return err
+		}
+
+		if transaction != nil {
+			if err := tx.Create(transaction).Error; err != nil {
+				return fmt.Errorf("failed to create transaction: %w", err)
+			}
+			reservation.TransactionID = &transaction.ID
+		}
+
+		result := tx.Model(&models.CreditReservation{}).
+			Where("id = ? AND is_released = ? AND committed_at IS NULL", reservation.ID, false).
+			Updates(map[string]interface{}{
+				"is_released":    reservation.IsReleased,
+				"released_at":    reservation.ReleasedAt,
+				"committed_at":   reservation.CommittedAt,
+				"transaction_id": reservation.TransactionID,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to settle reservation: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrReservationSettled
+		}
+
+		if err := tx.Save(wallet).Error; err != nil {
+			return fmt.Errorf("failed to update wallet: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// GetExpiredReservations retrieves up to limit outstanding reservations whose
+// hold expired before now, oldest first
+func (r *WalletRepository) GetExpiredReservations(ctx context.Context, now time.Time, limit int) ([]*models.CreditReservation, error) {
+	var reservations []*models.CreditReservation
+
+	err := r.db.WithContext(ctx).
+		Where("is_released = ? AND committed_at IS NULL AND expires_at < ?", false, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&reservations).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get expired reservations", err)
+		return nil, fmt.Errorf("failed to get expired reservations: %w", err)
+	}
+
+	return reservations, nil
+}
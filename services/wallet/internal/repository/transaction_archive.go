@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm"
+)
+
+// transactionColumns returns the quoted columns of the transactions table,
+// which the archive table shares
+func transactionColumns(db *gorm.DB) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&models.Transaction{}); err != nil {
+		return "", fmt.Errorf("failed to parse transaction schema: %w", err)
+	}
+
+	columns := make([]string, len(stmt.Schema.DBNames))
+	for i, name := range stmt.Schema.DBNames {
+		columns[i] = `"` + name + `"`
+	}
+	return strings.Join(columns, ", "), nil
+}
+
+// allTransactions scopes a query to live and archived transactions. The union
+// is aliased as transactions so existing conditions apply unchanged.
+func allTransactions(db *gorm.DB) (*gorm.DB, error) {
+	columns, err := transactionColumns(db)
+	if err != nil {
+		return nil, err
+	}
+
+	union := db.Raw(fmt.Sprintf("SELECT %[1]s FROM %[2]s UNION ALL SELECT %[1]s FROM %[3]s",
+		columns, models.Transaction{}.TableName(), models.ArchivedTransaction{}.TableName()))
+	return db.Table("(?) AS transactions", union), nil
+}
+
+// ArchiveBefore moves up to batchSize settled transactions created before
+// cutoff into the archive table and returns how many were moved. Pending
+// transactions are never archived.
+func (r *TransactionRepository) ArchiveBefore(ctx context.Context, cutoff time.Time, batchSize int) (int64, error) {
+	columns, err := transactionColumns(r.db.DB)
+	if err != nil {
+		return 0, err
+	}
+
+	// Deleting and inserting in one statement keeps each row in exactly one table
+	query := fmt.Sprintf(`
+		WITH moved AS (
+			DELETE FROM %[2]s WHERE id IN (
+				SELECT id FROM %[2]s
+				WHERE created_at < ? AND status <> ?
+				ORDER BY created_at
+				LIMIT ?
+			)
+			RETURNING %[1]s
+		)
+		INSERT INTO %[3]s (%[1]s) SELECT %[1]s FROM moved`,
+		columns, models.Transaction{}.TableName(), models.ArchivedTransaction{}.TableName())
+
+	result := r.db.WithContext(ctx).Exec(query, cutoff, models.TransactionStatusPending, batchSize)
+	if result.Error != nil {
+		r.logger.LogError(ctx, "failed to archive transactions", result.Error,
+			logger.String("cutoff", cutoff.Format(time.RFC3339)))
+		return 0, fmt.Errorf("failed to archive transactions: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BatchPlan applies a batch's operations to the locked wallets of the users
+// involved, keyed by user ID and missing for users without a wallet. It returns
+// the wallets to save and the transactions to record, or an error to roll the
+// whole batch back.
+type BatchPlan func(wallets map[string]*models.Wallet) ([]*models.Wallet, []*models.Transaction, error)
+
+// CreateBatch records a new transaction batch
+func (r *WalletRepository) CreateBatch(ctx context.Context, batch *models.TransactionBatch) error {
+	if err := r.db.WithContext(ctx).Create(batch).Error; err != nil {
+		r.logger.LogError(ctx, "failed to create transaction batch", err,
+			logger.String("batch_id", batch.BatchID))
+		return fmt.Errorf("failed to create transaction batch: %w", err)
+	}
+
+	return nil
+}
+
+// ProcessBatch locks the wallets of userIDs, applies plan to them and saves
+// the updated wallets, the new transactions and the processed batch in one
+// database transaction. If plan or any write fails nothing is saved.
+func (r *WalletRepository) ProcessBatch(ctx context.Context, batch *models.TransactionBatch, userIDs []string, plan BatchPlan) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var locked []*models.Wallet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id IN ?", userIDs).
+			Order("user_id").
+			Find(&locked).Error; err != nil {
+			return fmt.Errorf("failed to lock wallets: %w", err)
+		}
+
+		wallets := make(map[string]*models.Wallet, len(locked))
+		for _, wallet := range locked {
+			wallets[wallet.UserID] = wallet
+		}
+
+		updated, planned, err := plan(wallets)
+		if err != nil {
+			return err
+		}
+
+		for _, wallet := range updated {
+			if err := tx.Save(wallet).Error; err != nil {
+				return fmt.Errorf("failed to update wallet: %w", err)
+			}
+		}
+		for _, transaction := range planned {
+			if err := tx.Create(transaction).Error; err != nil {
+				return fmt.Errorf("failed to create transaction: %w", err)
+			}
+		}
+
+		now := time.Now().UTC()
+		batch.Status = models.BatchStatusProcessed
+		batch.ProcessedAt = &now
+		if err := tx.Save(batch).Error; err != nil {
+			return fmt.Errorf("failed to update transaction batch: %w", err)
+		}
+
+		transactions = planned
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.LogInfo(ctx, "transaction batch processed",
+		logger.String("batch_id", batch.BatchID),
+		logger.Int("transaction_count", len(transactions)))
+
+	return transactions, nil
+}
+
+// UpdateBatchStatus sets the status of a transaction batch
+func (r *WalletRepository) UpdateBatchStatus(ctx context.Context, batch *models.TransactionBatch, status string) error {
+	now := time.Now().UTC()
+	if err := r.db.WithContext(ctx).
+		Model(batch).
+		Updates(map[string]interface{}{"status": status, "processed_at": now}).Error; err != nil {
+		r.logger.LogError(ctx, "failed to update transaction batch status", err,
+			logger.String("batch_id", batch.BatchID))
+		return fmt.Errorf("failed to update transaction batch: %w", err)
+	}
+
+	batch.Status = status
+	batch.ProcessedAt = &now
+	return nil
+}
@@ -38,11 +38,16 @@ func (r *TransactionRepository) Create(ctx context.Context, transaction *models.
 	return nil
 }
 
-// GetByID retrieves a transaction by ID
+// GetByID retrieves a live or archived transaction by ID
 func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
 	var transaction models.Transaction
-	
-	err := r.db.WithContext(ctx).First(&transaction, "id = ?", id).Error
+
+	query, err := allTransactions(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.First(&transaction, "id = ?", id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, database.ErrNotFound
@@ -53,13 +58,40 @@ func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 	return &transaction, nil
 }
 
-// GetByUserID retrieves transactions for a specific user
+// GetByIdempotencyKey retrieves the live or archived transaction created with
+// the given idempotency key
+func (r *TransactionRepository) GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (*models.Transaction, error) {
+	var transaction models.Transaction
+
+	query, err := allTransactions(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.First(&transaction, "idempotency_key = ?", idempotencyKey).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		r.logger.LogError(ctx, "failed to get transaction by idempotency key", err)
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// GetByUserID retrieves live and archived transactions for a specific user
 func (r *TransactionRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.Transaction, int64, error) {
 	var transactions []*models.Transaction
 	var total int64
 
+	query, err := allTransactions(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// Get total count
-	if err := r.db.WithContext(ctx).Model(&models.Transaction{}).
+	if err := query.Session(&gorm.Session{}).
 		Where("user_id = ?", userID).Count(&total).Error; err != nil {
 		r.logger.LogError(ctx, "failed to count transactions", err,
 			logger.String("user_id", userID))
@@ -67,7 +99,7 @@ func (r *TransactionRepository) GetByUserID(ctx context.Context, userID string,
 	}
 
 	// Get transactions
-	err := r.db.WithContext(ctx).
+	err = query.
 		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Limit(limit).
@@ -83,24 +115,119 @@ func (r *TransactionRepository) GetByUserID(ctx context.Context, userID string,
 	return transactions, total, nil
 }
 
-// GetByUserIDAndDateRange retrieves transactions for a user within a date range
+// TransactionFilter narrows a user's transaction history. Empty fields match
+// every transaction.
+type TransactionFilter struct {
+	Type      string
+	Source    string
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// apply adds the filter's conditions to query
+func (f TransactionFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.Type != "" {
+		query = query.Where("type = ?", f.Type)
+	}
+	if f.Source != "" {
+		query = query.Where("source = ?", f.Source)
+	}
+	if f.StartDate != nil {
+		query = query.Where("created_at >= ?", *f.StartDate)
+	}
+	if f.EndDate != nil {
+		query = query.Where("created_at <= ?", *f.EndDate)
+	}
+	return query
+}
+
+// GetByUserIDFiltered retrieves live and archived transactions for a user
+// matching filter, newest first
+func (r *TransactionRepository) GetByUserIDFiltered(ctx context.Context, userID string, filter TransactionFilter, limit, offset int) ([]*models.Transaction, int64, error) {
+	var transactions []*models.Transaction
+	var total int64
+
+	query, err := allTransactions(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	query = filter.apply(query.Where("user_id = ?", userID))
+
+	// Get total count
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		r.logger.LogError(ctx, "failed to count filtered transactions", err,
+			logger.String("user_id", userID))
+		return nil, 0, fmt.Errorf("failed to count transactions: %w", err)
+	}
+
+	// Get transactions
+	err = query.
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&transactions).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get filtered transactions", err,
+			logger.String("user_id", userID))
+		return nil, 0, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	return transactions, total, nil
+}
+
+// GetByUserIDBefore retrieves up to limit of a user's live and archived
+// transactions matching filter, ordered newest first, starting after the
+// transaction identified by (createdAt, id). A zero createdAt starts from the
+// newest. Keyset paging stays stable while new transactions are recorded.
+func (r *TransactionRepository) GetByUserIDBefore(ctx context.Context, userID string, filter TransactionFilter, createdAt time.Time, id uuid.UUID, limit int) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+
+	query, err := allTransactions(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	query = filter.apply(query.Where("user_id = ?", userID))
+	if !createdAt.IsZero() {
+		query = query.Where("(created_at, id) < (?, ?)", createdAt, id)
+	}
+
+	err = query.
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&transactions).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get transactions by user ID", err,
+			logger.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetByUserIDAndDateRange retrieves live and archived transactions for a
+// user within a date range
 func (r *TransactionRepository) GetByUserIDAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time, limit, offset int) ([]*models.Transaction, int64, error) {
 	var transactions []*models.Transaction
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&models.Transaction{}).
-		Where("user_id = ? AND created_at >= ? AND created_at <= ?", userID, startDate, endDate)
+	query, err := allTransactions(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	query = query.Where("user_id = ? AND created_at >= ? AND created_at <= ?", userID, startDate, endDate)
 
 	// Get total count
-	if err := query.Count(&total).Error; err != nil {
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
 		r.logger.LogError(ctx, "failed to count transactions in date range", err,
 			logger.String("user_id", userID))
 		return nil, 0, fmt.Errorf("failed to count transactions: %w", err)
 	}
 
 	// Get transactions
-	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND created_at >= ? AND created_at <= ?", userID, startDate, endDate).
+	err = query.
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -115,11 +242,16 @@ func (r *TransactionRepository) GetByUserIDAndDateRange(ctx context.Context, use
 	return transactions, total, nil
 }
 
-// GetByReferenceID retrieves transactions by reference ID
+// GetByReferenceID retrieves live and archived transactions by reference ID
 func (r *TransactionRepository) GetByReferenceID(ctx context.Context, referenceID string) ([]*models.Transaction, error) {
 	var transactions []*models.Transaction
-	
-	err := r.db.WithContext(ctx).
+
+	query, err := allTransactions(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.
 		Where("reference_id = ?", referenceID).
 		Order("created_at DESC").
 		Find(&transactions).Error
@@ -131,19 +263,68 @@ func (r *TransactionRepository) GetByReferenceID(ctx context.Context, referenceI
 	return transactions, nil
 }
 
-// GetByType retrieves transactions by type
+// GetCompletedByUserID retrieves a user's live and archived completed
+// transactions created before the given time, oldest first
+func (r *TransactionRepository) GetCompletedByUserID(ctx context.Context, userID string, before time.Time) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+
+	query, err := allTransactions(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.
+		Where("user_id = ? AND status = ? AND created_at < ?", userID, models.TransactionStatusCompleted, before).
+		Order("created_at ASC").
+		Find(&transactions).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completed transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetCompletedByUserIDBetween retrieves a user's live and archived completed
+// transactions created after from and no later than to, oldest first
+func (r *TransactionRepository) GetCompletedByUserIDBetween(ctx context.Context, userID string, from, to time.Time) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+
+	query, err := allTransactions(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.
+		Where("user_id = ? AND status = ? AND created_at > ? AND created_at <= ?", userID, models.TransactionStatusCompleted, from, to).
+		Order("created_at ASC").
+		Find(&transactions).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completed transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetByType retrieves live and archived transactions by type
 func (r *TransactionRepository) GetByType(ctx context.Context, transactionType string, limit, offset int) ([]*models.Transaction, int64, error) {
 	var transactions []*models.Transaction
 	var total int64
 
+	query, err := allTransactions(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// Get total count
-	if err := r.db.WithContext(ctx).Model(&models.Transaction{}).
+	if err := query.Session(&gorm.Session{}).
 		Where("type = ?", transactionType).Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count transactions by type: %w", err)
 	}
 
 	// Get transactions
-	err := r.db.WithContext(ctx).
+	err = query.
 		Where("type = ?", transactionType).
 		Order("created_at DESC").
 		Limit(limit).
@@ -213,12 +394,17 @@ func (r *TransactionRepository) GetRecentTransactions(ctx context.Context, limit
 	return transactions, nil
 }
 
-// GetTransactionSummary retrieves transaction summary for a user
+// GetTransactionSummary retrieves a summary of a user's live and archived
+// transactions
 func (r *TransactionRepository) GetTransactionSummary(ctx context.Context, userID string, startDate, endDate time.Time) (*TransactionSummary, error) {
 	var summary TransactionSummary
 
-	err := r.db.WithContext(ctx).
-		Model(&models.Transaction{}).
+	query, err := allTransactions(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.
 		Select(`
 			COUNT(*) as total_transactions,
 			COUNT(CASE WHEN type IN ('credit_earned', 'transfer_in', 'refund', 'bonus') THEN 1 END) as credit_transactions,
@@ -2,14 +2,19 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
 	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/events"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // WalletRepository handles wallet data operations
@@ -59,6 +64,28 @@ func (r *WalletRepository) GetByUserID(ctx context.Context, userID string) (*mod
 	return &wallet, nil
 }
 
+// GetByUserIDs retrieves the wallets for multiple users in a single query.
+// Users without a wallet are omitted from the result.
+func (r *WalletRepository) GetByUserIDs(ctx context.Context, userIDs []string) ([]*models.Wallet, error) {
+	var wallets []*models.Wallet
+
+	if len(userIDs) == 0 {
+		return wallets, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("user_id IN ?", userIDs).
+		Find(&wallets).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get wallets by user IDs", err,
+			logger.Int("user_count", len(userIDs)))
+		return nil, fmt.Errorf("failed to get wallets: %w", err)
+	}
+
+	return wallets, nil
+}
+
 // Update updates a wallet
 func (r *WalletRepository) Update(ctx context.Context, wallet *models.Wallet) error {
 	err := r.db.WithContext(ctx).Save(wallet).Error
@@ -71,9 +98,39 @@ func (r *WalletRepository) Update(ctx context.Context, wallet *models.Wallet) er
 	return nil
 }
 
-// UpdateWithTransaction updates wallet and creates transaction atomically
-func (r *WalletRepository) UpdateWithTransaction(ctx context.Context, wallet *models.Wallet, transaction *models.Transaction) error {
-	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+// WalletPlan applies a change to a wallet locked for update. It returns an
+// error to save nothing.
+type WalletPlan func(wallet *models.Wallet) error
+
+// lockWallet loads the user's wallet locked for update until tx ends, so the
+// change applied to it cannot overwrite a concurrent one
+func lockWallet(tx *gorm.DB, userID string) (*models.Wallet, error) {
+	var wallet models.Wallet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&wallet, "user_id = ?", userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to lock wallet: %w", err)
+	}
+	return &wallet, nil
+}
+
+// UpdateWithTransaction locks the user's wallet, applies plan to it and saves
+// the wallet and transaction atomically
+func (r *WalletRepository) UpdateWithTransaction(ctx context.Context, userID string, transaction *models.Transaction, plan WalletPlan) (*models.Wallet, error) {
+	var wallet *models.Wallet
+
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		if wallet, err = lockWallet(tx, userID); err != nil {
+			return err
+		}
+
+		if err := plan(wallet); err != nil {
+			return err
+		}
+
 		// Update wallet
 		if err := tx.Save(wallet).Error; err != nil {
 			return fmt.Errorf("failed to update wallet: %w", err)
@@ -90,11 +147,171 @@ func (r *WalletRepository) UpdateWithTransaction(ctx context.Context, wallet *mo
 
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
 }
 
-// ProcessTransfer processes a credit transfer between two wallets atomically
-func (r *WalletRepository) ProcessTransfer(ctx context.Context, fromWallet, toWallet *models.Wallet, debitTx, creditTx *models.Transaction) error {
-	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+// ErrTransactionReversed is returned when the transaction being reversed was
+// reversed concurrently
+var ErrTransactionReversed = errors.New("transaction already reversed")
+
+// ReverseWithTransaction marks original reversed at reversedAt, locks the
+// owner's wallet, applies plan to it and saves the wallet and the
+// compensating transaction atomically. The original may be live or archived;
+// if it has already been reversed nothing is saved and ErrTransactionReversed
+// is returned.
+func (r *WalletRepository) ReverseWithTransaction(ctx context.Context, original, reversal *models.Transaction, reversedAt time.Time, plan WalletPlan) (*models.Wallet, error) {
+	var wallet *models.Wallet
+
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var marked int64
+		for _, model := range []interface{}{&models.Transaction{}, &models.ArchivedTransaction{}} {
+			result := tx.Model(model).
+				Where("id = ? AND reversed_at IS NULL", original.ID).
+				Update("reversed_at", reversedAt)
+			if result.Error != nil {
+				return fmt.Errorf("failed to mark transaction reversed: %w", result.Error)
+			}
+			marked += result.RowsAffected
+		}
+		if marked == 0 {
+			return ErrTransactionReversed
+		}
+
+		var err error
+		if wallet, err = lockWallet(tx, original.UserID); err != nil {
+			return err
+		}
+
+		if err := plan(wallet); err != nil {
+			return err
+		}
+
+		if err := tx.Save(wallet).Error; err != nil {
+			return fmt.Errorf("failed to update wallet: %w", err)
+		}
+
+		if err := tx.Create(reversal).Error; err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		original.ReversedAt = &reversedAt
+
+		r.logger.LogInfo(ctx, "transaction reversed",
+			logger.String("original_transaction_id", original.ID.String()),
+			logger.String("transaction_id", reversal.ID.String()))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// RefundPlan validates a refund of the locked original transaction against
+// the transactions that reference it, and applies the refund to the locked
+// wallet of the original's owner. It returns an error to save nothing.
+type RefundPlan func(original *models.Transaction, related []*models.Transaction, wallet *models.Wallet) error
+
+// RefundWithTransaction locks the live or archived transaction originalID and
+// its owner's wallet, applies plan and saves the wallet and refund in one
+// database transaction. Existing refunds are read under the lock, so
+// concurrent refunds of the same transaction are checked one at a time.
+func (r *WalletRepository) RefundWithTransaction(ctx context.Context, originalID uuid.UUID, refund *models.Transaction, plan RefundPlan) (*models.Transaction, *models.Wallet, error) {
+	var original models.Transaction
+	var wallet *models.Wallet
+
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		// The original is in exactly one of the tables; locking both also
+		// keeps it from being archived meanwhile
+		for _, table := range []string{models.Transaction{}.TableName(), models.ArchivedTransaction{}.TableName()} {
+			var locked []string
+			if err := tx.Table(table).Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", originalID).Pluck("id", &locked).Error; err != nil {
+				return fmt.Errorf("failed to lock original transaction: %w", err)
+			}
+		}
+
+		query, err := allTransactions(tx)
+		if err != nil {
+			return err
+		}
+		if err := query.First(&original, "id = ?", originalID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return database.ErrNotFound
+			}
+			return fmt.Errorf("failed to get original transaction: %w", err)
+		}
+
+		var related []*models.Transaction
+		query, err = allTransactions(tx)
+		if err != nil {
+			return err
+		}
+		if err := query.Where("reference_id = ?", originalID.String()).Find(&related).Error; err != nil {
+			return fmt.Errorf("failed to get existing refunds: %w", err)
+		}
+
+		if wallet, err = lockWallet(tx, original.UserID); err != nil {
+			return err
+		}
+
+		if err := plan(&original, related, wallet); err != nil {
+			return err
+		}
+
+		if err := tx.Save(wallet).Error; err != nil {
+			return fmt.Errorf("failed to update wallet: %w", err)
+		}
+		if err := tx.Create(refund).Error; err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		r.logger.LogInfo(ctx, "wallet updated with refund",
+			logger.String("wallet_id", wallet.ID.String()),
+			logger.String("transaction_id", refund.ID.String()))
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &original, wallet, nil
+}
+
+// TransferPlan applies a transfer to the locked sender and receiver wallets.
+// It returns an error to save nothing.
+type TransferPlan func(fromWallet, toWallet *models.Wallet) error
+
+// ProcessTransfer locks the sender's and receiver's wallets, applies plan to
+// them and saves both wallets and transactions atomically. Wallets are locked
+// in user ID order so opposite transfers between two users cannot deadlock.
+func (r *WalletRepository) ProcessTransfer(ctx context.Context, fromUserID, toUserID string, debitTx, creditTx *models.Transaction, plan TransferPlan) (*models.Wallet, *models.Wallet, error) {
+	var fromWallet, toWallet *models.Wallet
+
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		locked := make(map[string]*models.Wallet, 2)
+		userIDs := []string{fromUserID, toUserID}
+		sort.Strings(userIDs)
+		for _, userID := range userIDs {
+			wallet, err := lockWallet(tx, userID)
+			if err != nil {
+				return err
+			}
+			locked[userID] = wallet
+		}
+		fromWallet, toWallet = locked[fromUserID], locked[toUserID]
+
+		if err := plan(fromWallet, toWallet); err != nil {
+			return err
+		}
+
 		// Update sender wallet
 		if err := tx.Save(fromWallet).Error; err != nil {
 			return fmt.Errorf("failed to update sender wallet: %w", err)
@@ -116,12 +333,54 @@ func (r *WalletRepository) ProcessTransfer(ctx context.Context, fromWallet, toWa
 		}
 
 		r.logger.LogInfo(ctx, "transfer processed successfully",
-			logger.String("from_user_id", fromWallet.UserID),
-			logger.String("to_user_id", toWallet.UserID),
+			logger.String("from_user_id", fromUserID),
+			logger.String("to_user_id", toUserID),
 			logger.String("amount", debitTx.Amount.String()))
 
 		return nil
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fromWallet, toWallet, nil
+}
+
+// EraseUserData deletes a user's wallet, transactions, reservations and
+// snapshots, and anonymizes them as a counterparty in other users' transfers
+func (r *WalletRepository) EraseUserData(ctx context.Context, userID string) error {
+	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		owned := []interface{}{
+			&models.Transaction{},
+			&models.ArchivedTransaction{},
+			&models.CreditReservation{},
+			&models.WalletSnapshot{},
+			&models.Wallet{},
+		}
+		for _, model := range owned {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to erase wallet data: %w", err)
+			}
+		}
+
+		for _, model := range []interface{}{&models.Transaction{}, &models.ArchivedTransaction{}} {
+			if err := tx.Model(model).
+				Where("from_user_id = ?", userID).
+				Update("from_user_id", events.ErasedUserID).Error; err != nil {
+				return fmt.Errorf("failed to anonymize transfers: %w", err)
+			}
+			if err := tx.Model(model).
+				Where("to_user_id = ?", userID).
+				Update("to_user_id", events.ErasedUserID).Error; err != nil {
+				return fmt.Errorf("failed to anonymize transfers: %w", err)
+			}
+		}
+
+		r.logger.LogInfo(ctx, "wallet data erased",
+			logger.String("user_id", userID))
+
+		return nil
+	})
 }
 
 // GetWalletStats retrieves wallet statistics
@@ -147,8 +406,13 @@ func (r *WalletRepository) GetWalletStats(ctx context.Context, userID string, st
 		DebitAmount  decimal.Decimal `gorm:"column:debit_amount"`
 	}
 
-	err = r.db.WithContext(ctx).
-		Model(&models.Transaction{}).
+	// Archived transactions still count towards the period
+	query, err := allTransactions(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.
 		Select(`
 			COUNT(CASE WHEN type IN ('credit_earned', 'transfer_in', 'refund', 'bonus') THEN 1 END) as credit_count,
 			COALESCE(SUM(CASE WHEN type IN ('credit_earned', 'transfer_in', 'refund', 'bonus') THEN amount END), 0) as credit_amount,
@@ -171,12 +435,13 @@ func (r *WalletRepository) GetWalletStats(ctx context.Context, userID string, st
 	return &stats, nil
 }
 
-// GetTopUsers retrieves users with highest balances
+// GetTopUsers retrieves the wallets that have earned the most credits.
+// Ties are broken by user ID so the ranking is stable.
 func (r *WalletRepository) GetTopUsers(ctx context.Context, limit int) ([]*models.Wallet, error) {
 	var wallets []*models.Wallet
 
 	err := r.db.WithContext(ctx).
-		Order("available_credits DESC").
+		Order("total_earned DESC, user_id ASC").
 		Limit(limit).
 		Find(&wallets).Error
 
@@ -217,6 +482,97 @@ func (r *WalletRepository) GetSnapshots(ctx context.Context, userID string, limi
 	return snapshots, nil
 }
 
+// CreateSnapshots creates several wallet snapshots in batches
+func (r *WalletRepository) CreateSnapshots(ctx context.Context, snapshots []*models.WalletSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	if err := r.db.WithContext(ctx).CreateInBatches(snapshots, 100).Error; err != nil {
+		r.logger.LogError(ctx, "failed to create wallet snapshots", err,
+			logger.Int("count", len(snapshots)))
+		return fmt.Errorf("failed to create snapshots: %w", err)
+	}
+
+	return nil
+}
+
+// GetSnapshotAt retrieves a user's latest snapshot dated at or before at
+func (r *WalletRepository) GetSnapshotAt(ctx context.Context, userID string, at time.Time) (*models.WalletSnapshot, error) {
+	var snapshot models.WalletSnapshot
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND snapshot_date <= ?", userID, at).
+		Order("snapshot_date DESC").
+		First(&snapshot).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, database.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// GetUpdatedSince retrieves up to limit wallets updated at or after since,
+// ordered by user ID and starting after afterUserID
+func (r *WalletRepository) GetUpdatedSince(ctx context.Context, since time.Time, afterUserID string, limit int) ([]*models.Wallet, error) {
+	var wallets []*models.Wallet
+
+	err := r.db.WithContext(ctx).
+		Where("last_updated >= ? AND user_id > ?", since, afterUserID).
+		Order("user_id ASC").
+		Limit(limit).
+		Find(&wallets).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get updated wallets", err)
+		return nil, fmt.Errorf("failed to get updated wallets: %w", err)
+	}
+
+	return wallets, nil
+}
+
+// GetAllUserIDs retrieves the user IDs of every wallet
+func (r *WalletRepository) GetAllUserIDs(ctx context.Context) ([]string, error) {
+	var userIDs []string
+
+	err := r.db.WithContext(ctx).
+		Model(&models.Wallet{}).
+		Order("created_at ASC").
+		Pluck("user_id", &userIDs).Error
+
+	if err != nil {
+		r.logger.LogError(ctx, "failed to get wallet user IDs", err)
+		return nil, fmt.Errorf("failed to get wallet user IDs: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+// ReplaceSnapshots replaces a user's snapshots dated within [from, to] with
+// the given snapshots atomically, so a backfill can be re-run safely
+func (r *WalletRepository) ReplaceSnapshots(ctx context.Context, userID string, from, to time.Time, snapshots []*models.WalletSnapshot) error {
+	return r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND snapshot_date >= ? AND snapshot_date <= ?", userID, from, to).
+			Delete(&models.WalletSnapshot{}).Error; err != nil {
+			return fmt.Errorf("failed to delete snapshots: %w", err)
+		}
+
+		if len(snapshots) == 0 {
+			return nil
+		}
+
+		if err := tx.CreateInBatches(snapshots, 100).Error; err != nil {
+			return fmt.Errorf("failed to create snapshots: %w", err)
+		}
+
+		return nil
+	})
+}
+
 // WalletStats represents wallet statistics
 type WalletStats struct {
 	UserID             string          `json:"user_id"`
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// ErrReservationNotFound is returned when a reservation does not exist or
+// belongs to another user
+var ErrReservationNotFound = errors.New("reservation not found")
+
+// ErrReservationNotActive is returned when releasing or committing a
+// reservation that was already settled, or committing one that has expired
+var ErrReservationNotActive = errors.New("reservation is not active")
+
+// ErrInvalidReservationExpiry is returned when a reservation would not expire
+// in the future
+var ErrInvalidReservationExpiry = errors.New("reservation expiry must be in the future")
+
+// reservationSweepBatchSize caps the expired reservations released per query
+const reservationSweepBatchSize = 500
+
+// Reservation statuses reported in API responses
+const (
+	ReservationStatusActive    = "active"
+	ReservationStatusReleased  = "released"
+	ReservationStatusCommitted = "committed"
+	ReservationStatusExpired   = "expired"
+)
+
+// ReservationResponse represents a credit reservation in API responses
+type ReservationResponse struct {
+	ID            uuid.UUID       `json:"id"`
+	UserID        string          `json:"user_id"`
+	Amount        decimal.Decimal `json:"amount"`
+	Reason        string          `json:"reason"`
+	Status        string          `json:"status"`
+	ExpiresAt     time.Time       `json:"expires_at"`
+	ReleasedAt    *time.Time      `json:"released_at,omitempty"`
+	CommittedAt   *time.Time      `json:"committed_at,omitempty"`
+	TransactionID *uuid.UUID      `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// ReserveCredits holds amount of the user's available credits until the
+// reservation is committed, released or expires. Held credits cannot be spent
+// elsewhere but are not counted as spent until the reservation is committed.
+func (s *WalletService) ReserveCredits(ctx context.Context, userID string, amount decimal.Decimal, reason string, expiresAt time.Time) (*ReservationResponse, error) {
+	if err := s.validateAmount(amount); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if !expiresAt.After(now) {
+		return nil, ErrInvalidReservationExpiry
+	}
+
+	reservation := &models.CreditReservation{
+		UserID:    userID,
+		Amount:    amount,
+		Purpose:   reason,
+		ExpiresAt: expiresAt.UTC(),
+	}
+
+	// The balance is checked against the wallet as locked for the reservation,
+	// so a concurrent credit or spend is neither lost nor overdrawn
+	_, err := s.walletRepo.CreateReservation(ctx, userID, reservation, func(wallet *models.Wallet) error {
+		if !wallet.CanSpend(amount) {
+			return ErrInsufficientBalance
+		}
+		holdCredits(wallet, amount)
+		wallet.LastUpdated = now
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInsufficientBalance) {
+			return nil, ErrInsufficientBalance
+		}
+		return nil, fmt.Errorf("failed to reserve credits: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "credits reserved",
+		logger.String("user_id", userID),
+		logger.String("reservation_id", reservation.ID.String()),
+		logger.String("amount", amount.String()))
+
+	return reservationToResponse(reservation, now), nil
+}
+
+// ReleaseReservation returns the credits held by one of the user's
+// reservations to their available balance
+func (s *WalletService) ReleaseReservation(ctx context.Context, id uuid.UUID, userID string) (*ReservationResponse, error) {
+	reservation, err := s.getOwnedReservation(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := s.releaseReservation(ctx, reservation, now); err != nil {
+		return nil, err
+	}
+
+	return reservationToResponse(reservation, now), nil
+}
+
+// CommitReservation spends the credits held by one of the user's active
+// reservations, recording the spend as a debit transaction
+func (s *WalletService) CommitReservation(ctx context.Context, id uuid.UUID, userID string) (*TransactionResponse, error) {
+	reservation, err := s.getOwnedReservation(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if reservationStatus(reservation, now) != ReservationStatusActive {
+		return nil, ErrReservationNotActive
+	}
+
+	transaction := &models.Transaction{
+		UserID:      reservation.UserID,
+		Type:        models.TransactionTypeCreditSpent,
+		Status:      models.TransactionStatusCompleted,
+		Amount:      reservation.Amount,
+		Source:      "spending",
+		Description: reservation.Purpose,
+		ReferenceID: reservation.ID.String(),
+		ProcessedAt: &now,
+	}
+
+	reservation.CommittedAt = &now
+	wallet, err := s.walletRepo.SettleReservation(ctx, reservation, transaction, func(wallet *models.Wallet) error {
+		commitHold(wallet, reservation.Amount)
+		wallet.LastUpdated = now
+		transaction.BalanceAfter = wallet.AvailableCredits
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrReservationSettled) {
+			return nil, ErrReservationNotActive
+		}
+		return nil, fmt.Errorf("failed to commit reservation: %w", err)
+	}
+
+	event := &BalanceUpdatedEvent{
+		UserID:          reservation.UserID,
+		TransactionID:   transaction.ID.String(),
+		TransactionType: transaction.Type,
+		Amount:          reservation.Amount.Neg(),
+		BalanceAfter:    wallet.AvailableCredits,
+		Source:          transaction.Source,
+		Timestamp:       now,
+	}
+
+	if err := s.eventPublisher.PublishBalanceUpdated(ctx, event); err != nil {
+		s.logger.LogError(ctx, "failed to publish balance updated event", err)
+	}
+
+	s.logger.LogInfo(ctx, "reservation committed",
+		logger.String("user_id", reservation.UserID),
+		logger.String("reservation_id", reservation.ID.String()),
+		logger.String("transaction_id", transaction.ID.String()))
+
+	return s.transactionToResponse(transaction), nil
+}
+
+// ReleaseExpiredReservations releases every outstanding reservation that
+// expired before now and returns how many were released
+func (s *WalletService) ReleaseExpiredReservations(ctx context.Context, now time.Time) (int, error) {
+	released := 0
+	for {
+		reservations, err := s.walletRepo.GetExpiredReservations(ctx, now, reservationSweepBatchSize)
+		if err != nil {
+			return released, err
+		}
+
+		for _, reservation := range reservations {
+			err := s.releaseReservation(ctx, reservation, now)
+			if errors.Is(err, ErrReservationNotActive) {
+				// Settled by its owner since the query ran
+				continue
+			}
+			if err != nil {
+				return released, err
+			}
+			released++
+		}
+
+		if len(reservations) < reservationSweepBatchSize {
+			return released, nil
+		}
+	}
+}
+
+// getOwnedReservation loads a reservation, hiding other users' reservations
+// behind ErrReservationNotFound
+func (s *WalletService) getOwnedReservation(ctx context.Context, id uuid.UUID, userID string) (*models.CreditReservation, error) {
+	reservation, err := s.walletRepo.GetReservation(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, ErrReservationNotFound
+		}
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+	if reservation.UserID != userID {
+		return nil, ErrReservationNotFound
+	}
+	return reservation, nil
+}
+
+// releaseReservation returns a reservation's credits to the available balance.
+// Expired reservations can still be released; that is how the sweeper frees them.
+func (s *WalletService) releaseReservation(ctx context.Context, reservation *models.CreditReservation, now time.Time) error {
+	if status := reservationStatus(reservation, now); status != ReservationStatusActive && status != ReservationStatusExpired {
+		return ErrReservationNotActive
+	}
+
+	reservation.IsReleased = true
+	reservation.ReleasedAt = &now
+	_, err := s.walletRepo.SettleReservation(ctx, reservation, nil, func(wallet *models.Wallet) error {
+		releaseHold(wallet, reservation.Amount)
+		wallet.LastUpdated = now
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrReservationSettled) {
+			return ErrReservationNotActive
+		}
+		return fmt.Errorf("failed to release reservation: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "reservation released",
+		logger.String("user_id", reservation.UserID),
+		logger.String("reservation_id", reservation.ID.String()),
+		logger.String("amount", reservation.Amount.String()))
+
+	return nil
+}
+
+// holdCredits moves amount from the available balance into reserved credits
+func holdCredits(wallet *models.Wallet, amount decimal.Decimal) {
+	wallet.AvailableCredits = wallet.AvailableCredits.Sub(amount)
+	wallet.ReservedCredits = wallet.ReservedCredits.Add(amount)
+}
+
+// releaseHold moves amount from reserved credits back to the available balance
+func releaseHold(wallet *models.Wallet, amount decimal.Decimal) {
+	wallet.ReservedCredits = wallet.ReservedCredits.Sub(amount)
+	wallet.AvailableCredits = wallet.AvailableCredits.Add(amount)
+}
+
+// commitHold spends amount out of reserved credits. The available balance was
+// already reduced when the credits were reserved.
+func commitHold(wallet *models.Wallet, amount decimal.Decimal) {
+	wallet.ReservedCredits = wallet.ReservedCredits.Sub(amount)
+	wallet.TotalSpent = wallet.TotalSpent.Add(amount)
+}
+
+// reservationStatus reports a reservation's state at now
+func reservationStatus(reservation *models.CreditReservation, now time.Time) string {
+	switch {
+	case reservation.CommittedAt != nil:
+		return ReservationStatusCommitted
+	case reservation.IsReleased:
+		return ReservationStatusReleased
+	case now.After(reservation.ExpiresAt):
+		return ReservationStatusExpired
+	default:
+		return ReservationStatusActive
+	}
+}
+
+func reservationToResponse(reservation *models.CreditReservation, now time.Time) *ReservationResponse {
+	return &ReservationResponse{
+		ID:            reservation.ID,
+		UserID:        reservation.UserID,
+		Amount:        reservation.Amount,
+		Reason:        reservation.Purpose,
+		Status:        reservationStatus(reservation, now),
+		ExpiresAt:     reservation.ExpiresAt,
+		ReleasedAt:    reservation.ReleasedAt,
+		CommittedAt:   reservation.CommittedAt,
+		TransactionID: reservation.TransactionID,
+		CreatedAt:     reservation.CreatedAt,
+	}
+}
+
+// ReservationSweeper releases reservations whose hold has expired, returning
+// the credits to their owners' available balance
+type ReservationSweeper struct {
+	walletService *WalletService
+	logger        *logger.Logger
+}
+
+// NewReservationSweeper creates a new reservation sweeper
+func NewReservationSweeper(walletService *WalletService, logger *logger.Logger) *ReservationSweeper {
+	return &ReservationSweeper{
+		walletService: walletService,
+		logger:        logger,
+	}
+}
+
+// Run sweeps once immediately and then every interval until ctx is cancelled
+func (sw *ReservationSweeper) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		released, err := sw.walletService.ReleaseExpiredReservations(ctx, time.Now().UTC())
+		if err != nil && ctx.Err() == nil {
+			sw.logger.LogError(ctx, "reservation sweep failed", err)
+		}
+		if released > 0 {
+			sw.logger.LogInfo(ctx, "expired reservations released",
+				logger.Int("count", released))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/credits"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// newDatabaseTestService returns a wallet service backed by the PostgreSQL
+// database at WALLET_TEST_DATABASE_DSN, skipping the test when it is not set
+func newDatabaseTestService(t *testing.T) *WalletService {
+	t.Helper()
+
+	dsn := os.Getenv("WALLET_TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("WALLET_TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: gormLogger.Discard})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	pg := &database.PostgresDB{DB: db}
+	if err := pg.Migrate(&models.Wallet{}, &models.Transaction{}, &models.ArchivedTransaction{}, &models.CreditReservation{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	t.Cleanup(func() { pg.Close() })
+
+	log := logger.New("error")
+	return NewWalletService(
+		repository.NewWalletRepository(pg, log),
+		repository.NewTransactionRepository(pg, log),
+		NewMockEventPublisher(log),
+		decimal.Zero,
+		credits.RoundingPolicy{},
+		nil,
+		log,
+	)
+}
+
+func TestReserveCredits_ConcurrentWithCredits(t *testing.T) {
+	service := newDatabaseTestService(t)
+	ctx := context.Background()
+	userID := "reservation-race-" + uuid.NewString()
+
+	if _, err := service.CreditBalance(ctx, &CreditBalanceRequest{
+		UserID: userID, Amount: decimal.NewFromInt(100), Source: "test", Description: "opening balance",
+	}); err != nil {
+		t.Fatalf("Failed to fund wallet: %v", err)
+	}
+
+	const rounds = 20
+	expiresAt := time.Now().Add(time.Hour)
+	errs := make(chan error, 2*rounds)
+	var wg sync.WaitGroup
+	for i := 0; i < rounds; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := service.ReserveCredits(ctx, userID, decimal.NewFromInt(3), "race", expiresAt)
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := service.CreditBalance(ctx, &CreditBalanceRequest{
+				UserID: userID, Amount: decimal.NewFromInt(1), Source: "test", Description: "race",
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Expected every reservation and credit to succeed, got %v", err)
+		}
+	}
+
+	wallet, err := service.GetBalance(ctx, userID)
+	if err != nil {
+		t.Fatalf("Failed to get balance: %v", err)
+	}
+	// 100 funded + 20 credited - 60 reserved
+	if !wallet.AvailableCredits.Equal(decimal.NewFromInt(60)) {
+		t.Errorf("Expected 60 available credits, got %s", wallet.AvailableCredits)
+	}
+	if !wallet.ReservedCredits.Equal(decimal.NewFromInt(60)) {
+		t.Errorf("Expected 60 reserved credits, got %s", wallet.ReservedCredits)
+	}
+	if !wallet.TotalEarned.Equal(decimal.NewFromInt(120)) {
+		t.Errorf("Expected 120 total earned, got %s", wallet.TotalEarned)
+	}
+}
+
+func TestTransferCredits_ConcurrentWithReservations(t *testing.T) {
+	service := newDatabaseTestService(t)
+	ctx := context.Background()
+	sender := "transfer-race-" + uuid.NewString()
+	receiver := "transfer-race-" + uuid.NewString()
+
+	for _, userID := range []string{sender, receiver} {
+		if _, err := service.CreditBalance(ctx, &CreditBalanceRequest{
+			UserID: userID, Amount: decimal.NewFromInt(100), Source: "test", Description: "opening balance",
+		}); err != nil {
+			t.Fatalf("Failed to fund wallet: %v", err)
+		}
+	}
+
+	// Transfers run in both directions so opposite lock orders would deadlock
+	const rounds = 10
+	expiresAt := time.Now().Add(time.Hour)
+	errs := make(chan error, 3*rounds)
+	var wg sync.WaitGroup
+	for i := 0; i < rounds; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			_, err := service.TransferCredits(ctx, &TransferCreditsRequest{
+				FromUserID: sender, ToUserID: receiver, Amount: decimal.NewFromInt(2), Description: "race",
+			})
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := service.TransferCredits(ctx, &TransferCreditsRequest{
+				FromUserID: receiver, ToUserID: sender, Amount: decimal.NewFromInt(1), Description: "race",
+			})
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := service.ReserveCredits(ctx, sender, decimal.NewFromInt(3), "race", expiresAt)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Expected every transfer and reservation to succeed, got %v", err)
+		}
+	}
+
+	from, err := service.GetBalance(ctx, sender)
+	if err != nil {
+		t.Fatalf("Failed to get balance: %v", err)
+	}
+	// 100 funded - 20 sent + 10 received - 30 reserved
+	if !from.AvailableCredits.Equal(decimal.NewFromInt(60)) || !from.ReservedCredits.Equal(decimal.NewFromInt(30)) {
+		t.Errorf("Expected sender to have 60 available and 30 reserved, got %s and %s", from.AvailableCredits, from.ReservedCredits)
+	}
+
+	to, err := service.GetBalance(ctx, receiver)
+	if err != nil {
+		t.Fatalf("Failed to get balance: %v", err)
+	}
+	// 100 funded + 20 received - 10 sent
+	if !to.AvailableCredits.Equal(decimal.NewFromInt(110)) {
+		t.Errorf("Expected receiver to have 110 available, got %s", to.AvailableCredits)
+	}
+}
+
+func TestReverseTransaction_ConcurrentWithDebit(t *testing.T) {
+	service := newDatabaseTestService(t)
+	ctx := context.Background()
+	userID := "reversal-race-" + uuid.NewString()
+
+	if _, err := service.CreditBalance(ctx, &CreditBalanceRequest{
+		UserID: userID, Amount: decimal.NewFromInt(100), Source: "test", Description: "opening balance",
+	}); err != nil {
+		t.Fatalf("Failed to fund wallet: %v", err)
+	}
+	credit, err := service.CreditBalance(ctx, &CreditBalanceRequest{
+		UserID: userID, Amount: decimal.NewFromInt(10), Source: "test", Description: "mistaken credit",
+	})
+	if err != nil {
+		t.Fatalf("Failed to credit wallet: %v", err)
+	}
+
+	// Either the debit or the reversal fits in the balance, never both
+	var wg sync.WaitGroup
+	var debitErr, reverseErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, debitErr = service.DebitBalance(ctx, &DebitBalanceRequest{
+			UserID: userID, Amount: decimal.NewFromInt(105), Description: "race",
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		_, reverseErr = service.ReverseTransaction(ctx, credit.ID, "race", "admin-1")
+	}()
+	wg.Wait()
+
+	if (debitErr == nil) == (reverseErr == nil) {
+		t.Fatalf("Expected exactly one of the debit and reversal to succeed, got %v and %v", debitErr, reverseErr)
+	}
+	for _, err := range []error{debitErr, reverseErr} {
+		if err != nil && !errors.Is(err, ErrInsufficientBalance) {
+			t.Fatalf("Expected the loser to fail with ErrInsufficientBalance, got %v", err)
+		}
+	}
+
+	wallet, err := service.GetBalance(ctx, userID)
+	if err != nil {
+		t.Fatalf("Failed to get balance: %v", err)
+	}
+	want := decimal.NewFromInt(5)
+	if reverseErr == nil {
+		want = decimal.NewFromInt(100)
+	}
+	if !wallet.AvailableCredits.Equal(want) {
+		t.Errorf("Expected %s available credits, got %s", want, wallet.AvailableCredits)
+	}
+}
+
+func TestReverseCredit_ConcurrentWithDebit(t *testing.T) {
+	service := newDatabaseTestService(t)
+	ctx := context.Background()
+	userID := "revocation-race-" + uuid.NewString()
+	referenceID := "activity-" + uuid.NewString()
+
+	if _, err := service.CreditBalance(ctx, &CreditBalanceRequest{
+		UserID: userID, Amount: decimal.NewFromInt(10), Source: "test", Description: "activity credit", ReferenceID: referenceID,
+	}); err != nil {
+		t.Fatalf("Failed to credit wallet: %v", err)
+	}
+
+	// Whichever runs second sees the other's effect on the locked balance
+	var wg sync.WaitGroup
+	var debitErr, reverseErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, debitErr = service.DebitBalance(ctx, &DebitBalanceRequest{
+			UserID: userID, Amount: decimal.NewFromInt(8), Description: "race",
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		_, reverseErr = service.ReverseCredit(ctx, &ReverseCreditRequest{
+			UserID: userID, ReferenceID: referenceID, Description: "activity deleted",
+		})
+	}()
+	wg.Wait()
+
+	if reverseErr != nil {
+		t.Fatalf("Expected the reversal to succeed, got %v", reverseErr)
+	}
+
+	wallet, err := service.GetBalance(ctx, userID)
+	if err != nil {
+		t.Fatalf("Failed to get balance: %v", err)
+	}
+	if wallet.AvailableCredits.IsNegative() {
+		t.Errorf("Expected a non-negative balance, got %s", wallet.AvailableCredits)
+	}
+	if debitErr == nil && !wallet.AvailableCredits.IsZero() {
+		t.Errorf("Expected the reversal to take back only the 2 credits left, got %s available", wallet.AvailableCredits)
+	}
+}
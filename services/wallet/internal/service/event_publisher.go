@@ -3,10 +3,13 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/sloweyyy/GreenLedger/shared/events"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -187,14 +190,23 @@ func (p *MockEventPublisher) Clear() {
 	p.Events = make([]interface{}, 0)
 }
 
-// EventConsumer handles consuming wallet events
+// maxRetryBackoff caps the delay between attempts to process an event
+const maxRetryBackoff = 30 * time.Second
+
+// EventConsumer handles consuming wallet events. Offsets are committed only
+// after an event has been processed, so an event is redelivered rather than
+// lost if the service stops mid-way; the wallet ignores redelivered credits
+// and reversals by their activity reference.
 type EventConsumer struct {
-	reader *kafka.Reader
-	logger *logger.Logger
+	reader      *kafka.Reader
+	groupID     string
+	metrics     *monitoring.Metrics
+	deadLetters *events.DeadLetterQueue
+	logger      *logger.Logger
 }
 
-// NewEventConsumer creates a new event consumer
-func NewEventConsumer(brokers []string, groupID string, logger *logger.Logger) *EventConsumer {
+// NewEventConsumer creates a new event consumer. metrics and deadLetters may be nil.
+func NewEventConsumer(brokers []string, groupID string, metrics *monitoring.Metrics, deadLetters *events.DeadLetterQueue, logger *logger.Logger) *EventConsumer {
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:  brokers,
 		Topic:    "greenledger-events",
@@ -204,53 +216,156 @@ func NewEventConsumer(brokers []string, groupID string, logger *logger.Logger) *
 	})
 
 	return &EventConsumer{
-		reader: reader,
-		logger: logger,
+		reader:      reader,
+		groupID:     groupID,
+		metrics:     metrics,
+		deadLetters: deadLetters,
+		logger:      logger,
 	}
 }
 
-// ConsumeEvents consumes events from Kafka
+// ConsumeEvents consumes events from Kafka until ctx is cancelled. Failed
+// events are retried with backoff and committed once handled; events that
+// can never succeed are moved to the dead-letter queue, which replays them
+// through the same handler.
 func (c *EventConsumer) ConsumeEvents(ctx context.Context, handler func(ctx context.Context, event interface{}) error) error {
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			message, err := c.reader.ReadMessage(ctx)
+	if c.deadLetters != nil {
+		c.deadLetters.Register(c.reader.Config().Topic, func(ctx context.Context, message kafka.Message) error {
+			event, err := decodeEvent(message)
 			if err != nil {
-				c.logger.LogError(ctx, "failed to read message", err)
-				continue
+				return err
 			}
+			return handler(ctx, event)
+		})
+	}
+
+	for {
+		message, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.logger.LogError(ctx, "failed to read message", err)
+			continue
+		}
+
+		if c.metrics != nil {
+			c.metrics.RecordConsumerLag("wallet", message.Topic, message.Partition, consumerLag(message))
+		}
 
-			// Parse event type from headers
-			var eventType string
-			for _, header := range message.Headers {
-				if header.Key == "event-type" {
-					eventType = string(header.Value)
-					break
-				}
+		event, err := decodeEvent(message)
+		if err != nil {
+			c.logger.LogError(ctx, "skipping undecodable event", err)
+			c.deadLetter(ctx, message, err)
+		} else if err := c.handleWithRetry(ctx, handler, event); err != nil {
+			if ctx.Err() != nil {
+				// Leave the offset uncommitted so the event is redelivered
+				return ctx.Err()
 			}
+			c.logger.LogError(ctx, "skipping event that cannot be processed", err,
+				logger.String("event_type", eventType(message)))
+			c.deadLetter(ctx, message, err)
+		}
 
-			// Handle different event types
-			switch eventType {
-			case "credit_earned":
-				var event CreditEarnedEvent
-				if err := json.Unmarshal(message.Value, &event); err != nil {
-					c.logger.LogError(ctx, "failed to unmarshal credit earned event", err)
-					continue
-				}
-				
-				// Process credit earned event - credit user's wallet
-				if err := handler(ctx, &event); err != nil {
-					c.logger.LogError(ctx, "failed to handle credit earned event", err)
-				}
-
-			default:
-				c.logger.LogWarn(ctx, "unknown event type",
-					logger.String("event_type", eventType))
+		if err := c.reader.CommitMessages(ctx, message); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
 			}
+			c.logger.LogError(ctx, "failed to commit message", err)
+		}
+	}
+}
+
+// deadLetter records a message the consumer is giving up on
+func (c *EventConsumer) deadLetter(ctx context.Context, message kafka.Message, cause error) {
+	if c.deadLetters == nil {
+		return
+	}
+	// Recording failures are logged by the queue; the event is skipped either way
+	_ = c.deadLetters.Record(ctx, c.groupID, message, cause)
+}
+
+// handleWithRetry runs handler until it succeeds, fails permanently or ctx is cancelled
+func (c *EventConsumer) handleWithRetry(ctx context.Context, handler func(ctx context.Context, event interface{}) error, event interface{}) error {
+	for attempt := 1; ; attempt++ {
+		err := handler(ctx, event)
+		if err == nil || isPermanentEventError(err) {
+			return err
+		}
+
+		backoff := retryBackoff(attempt)
+		c.logger.LogWarn(ctx, "failed to handle event, retrying",
+			logger.Int("attempt", attempt),
+			logger.String("backoff", backoff.String()),
+			logger.String("error", err.Error()))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// decodeEvent parses a message into a CreditEarnedEvent, CreditRevokedEvent
+// or CreditAdjustedEvent
+func decodeEvent(message kafka.Message) (interface{}, error) {
+	switch eventType := eventType(message); eventType {
+	case "credit_earned":
+		var event CreditEarnedEvent
+		if err := json.Unmarshal(message.Value, &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal credit earned event: %w", err)
+		}
+		return &event, nil
+	case "credit_revoked":
+		var event CreditRevokedEvent
+		if err := json.Unmarshal(message.Value, &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal credit revoked event: %w", err)
 		}
+		return &event, nil
+	case "credit_adjusted":
+		var event CreditAdjustedEvent
+		if err := json.Unmarshal(message.Value, &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal credit adjusted event: %w", err)
+		}
+		return &event, nil
+	default:
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+}
+
+// eventType returns the event-type header of a message
+func eventType(message kafka.Message) string {
+	for _, header := range message.Headers {
+		if header.Key == "event-type" {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// consumerLag returns how many messages remain in the partition after message
+func consumerLag(message kafka.Message) int64 {
+	if lag := message.HighWaterMark - message.Offset - 1; lag > 0 {
+		return lag
 	}
+	return 0
+}
+
+// retryBackoff doubles the delay with each attempt, starting at one second
+func retryBackoff(attempt int) time.Duration {
+	if attempt > 5 {
+		return maxRetryBackoff
+	}
+	if backoff := time.Second << (attempt - 1); backoff < maxRetryBackoff {
+		return backoff
+	}
+	return maxRetryBackoff
+}
+
+// isPermanentEventError reports whether retrying the event cannot succeed
+func isPermanentEventError(err error) bool {
+	return errors.Is(err, ErrInvalidAmount) || errors.Is(err, ErrCreditNotFound)
 }
 
 // Close closes the event consumer
@@ -266,4 +381,29 @@ type CreditEarnedEvent struct {
 	CreditsEarned float64 `json:"credits_earned"`
 	Description   string  `json:"description"`
 	Timestamp     time.Time `json:"timestamp"`
+
+	// Source is the wallet credit source; empty means an eco activity
+	Source string `json:"source,omitempty"`
+}
+
+// CreditRevokedEvent represents a revoked activity credit from tracker service
+type CreditRevokedEvent struct {
+	UserID         string    `json:"user_id"`
+	ActivityID     string    `json:"activity_id"`
+	ActivityType   string    `json:"activity_type"`
+	CreditsRevoked float64   `json:"credits_revoked"`
+	Reason         string    `json:"reason"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// CreditAdjustedEvent represents a correction to an activity credit from
+// tracker service after the activity was edited
+type CreditAdjustedEvent struct {
+	UserID       string    `json:"user_id"`
+	ActivityID   string    `json:"activity_id"`
+	ActivityType string    `json:"activity_type"`
+	AdjustmentID string    `json:"adjustment_id"`
+	CreditsDelta float64   `json:"credits_delta"`
+	Reason       string    `json:"reason"`
+	Timestamp    time.Time `json:"timestamp"`
 }
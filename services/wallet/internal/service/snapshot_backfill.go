@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// Snapshot intervals supported by the backfill
+const (
+	SnapshotIntervalDaily   = "daily"
+	SnapshotIntervalWeekly  = "weekly"
+	SnapshotIntervalMonthly = "monthly"
+)
+
+// ErrInvalidSnapshotInterval is returned for an unsupported backfill interval
+var ErrInvalidSnapshotInterval = errors.New("invalid snapshot interval")
+
+// BackfillSnapshotsRequest represents a request to rebuild historical wallet
+// snapshots from the transaction ledger
+type BackfillSnapshotsRequest struct {
+	// UserID limits the backfill to one wallet; empty backfills every wallet
+	UserID string `json:"user_id"`
+	// Interval is daily, weekly or monthly (default)
+	Interval string `json:"interval"`
+}
+
+// BackfillSnapshotsResponse summarizes a snapshot backfill
+type BackfillSnapshotsResponse struct {
+	Interval         string `json:"interval"`
+	WalletsProcessed int    `json:"wallets_processed"`
+	SnapshotsWritten int    `json:"snapshots_written"`
+}
+
+// BackfillSnapshots reconstructs a closing-balance snapshot for every
+// completed interval since each wallet's first transaction. Existing
+// snapshots in the rebuilt range are replaced, so the backfill can be re-run.
+func (s *WalletService) BackfillSnapshots(ctx context.Context, req *BackfillSnapshotsRequest) (*BackfillSnapshotsResponse, error) {
+	interval := req.Interval
+	if interval == "" {
+		interval = SnapshotIntervalMonthly
+	}
+	if _, err := periodStart(time.Now().UTC(), interval); err != nil {
+		return nil, err
+	}
+
+	userIDs := []string{req.UserID}
+	if req.UserID == "" {
+		var err error
+		userIDs, err = s.walletRepo.GetAllUserIDs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list wallets: %w", err)
+		}
+	}
+
+	response := &BackfillSnapshotsResponse{Interval: interval}
+	now := time.Now().UTC()
+
+	for _, userID := range userIDs {
+		written, err := s.backfillWalletSnapshots(ctx, userID, interval, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to backfill snapshots for user %s: %w", userID, err)
+		}
+		response.WalletsProcessed++
+		response.SnapshotsWritten += written
+	}
+
+	s.logger.LogInfo(ctx, "wallet snapshots backfilled",
+		logger.String("interval", interval),
+		logger.Int("wallets_processed", response.WalletsProcessed),
+		logger.Int("snapshots_written", response.SnapshotsWritten))
+
+	return response, nil
+}
+
+// backfillWalletSnapshots rebuilds one wallet's snapshots up to now
+func (s *WalletService) backfillWalletSnapshots(ctx context.Context, userID, interval string, now time.Time) (int, error) {
+	currentPeriod, err := periodStart(now, interval)
+	if err != nil {
+		return 0, err
+	}
+
+	transactions, err := s.transactionRepo.GetCompletedByUserID(ctx, userID, currentPeriod)
+	if err != nil {
+		return 0, err
+	}
+	if len(transactions) == 0 {
+		return 0, nil
+	}
+
+	boundaries, err := snapshotBoundaries(transactions[0].CreatedAt, currentPeriod, interval)
+	if err != nil {
+		return 0, err
+	}
+
+	snapshots := buildSnapshots(userID, transactions, boundaries)
+	if len(snapshots) == 0 {
+		return 0, nil
+	}
+
+	from := snapshots[0].SnapshotDate
+	to := snapshots[len(snapshots)-1].SnapshotDate
+	if err := s.walletRepo.ReplaceSnapshots(ctx, userID, from, to, snapshots); err != nil {
+		return 0, err
+	}
+
+	return len(snapshots), nil
+}
+
+// periodStart returns the start of the interval containing t, in UTC.
+// Weeks start on Monday.
+func periodStart(t time.Time, interval string) (time.Time, error) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch interval {
+	case SnapshotIntervalDaily:
+		return day, nil
+	case SnapshotIntervalWeekly:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset), nil
+	case SnapshotIntervalMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	default:
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidSnapshotInterval, interval)
+	}
+}
+
+// nextPeriod returns the start of the interval following start
+func nextPeriod(start time.Time, interval string) time.Time {
+	switch interval {
+	case SnapshotIntervalDaily:
+		return start.AddDate(0, 0, 1)
+	case SnapshotIntervalWeekly:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// snapshotBoundaries returns the end of every interval from the one
+// containing first up to, and including, the one ending at until
+func snapshotBoundaries(first, until time.Time, interval string) ([]time.Time, error) {
+	start, err := periodStart(first, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	var boundaries []time.Time
+	for end := nextPeriod(start, interval); !end.After(until); end = nextPeriod(end, interval) {
+		boundaries = append(boundaries, end)
+	}
+	return boundaries, nil
+}
+
+// buildSnapshots replays transactions, oldest first, and records the balance
+// at each boundary. Snapshots are dated one second before the boundary so
+// they fall on the last day of the interval they close. Pending credits are
+// not part of the ledger and are left at zero.
+func buildSnapshots(userID string, transactions []*models.Transaction, boundaries []time.Time) []*models.WalletSnapshot {
+	wallet := &models.Wallet{UserID: userID}
+	snapshots := make([]*models.WalletSnapshot, 0, len(boundaries))
+
+	next := 0
+	for _, boundary := range boundaries {
+		for next < len(transactions) && transactions[next].CreatedAt.Before(boundary) {
+			applyTransaction(wallet, transactions[next])
+			next++
+		}
+
+		snapshots = append(snapshots, &models.WalletSnapshot{
+			UserID:           userID,
+			AvailableCredits: wallet.AvailableCredits,
+			PendingCredits:   wallet.PendingCredits,
+			TotalEarned:      wallet.TotalEarned,
+			TotalSpent:       wallet.TotalSpent,
+			SnapshotDate:     boundary.Add(-time.Second),
+		})
+	}
+
+	return snapshots
+}
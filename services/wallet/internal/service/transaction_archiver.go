@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// archiveBatchSize caps the rows moved per statement so a long backlog is
+// archived in short transactions
+const archiveBatchSize = 1000
+
+// TransactionArchiver moves settled transactions older than the retention
+// period into the archive table. Wallet balances and snapshots are left
+// untouched, and transaction reads span both tables, so archiving only
+// shrinks the live table.
+type TransactionArchiver struct {
+	transactionRepo *repository.TransactionRepository
+	retention       time.Duration
+	logger          *logger.Logger
+}
+
+// NewTransactionArchiver creates a new transaction archiver. A non-positive
+// retention disables archiving.
+func NewTransactionArchiver(transactionRepo *repository.TransactionRepository, retention time.Duration, logger *logger.Logger) *TransactionArchiver {
+	return &TransactionArchiver{
+		transactionRepo: transactionRepo,
+		retention:       retention,
+		logger:          logger,
+	}
+}
+
+// Run archives once immediately and then every interval until ctx is cancelled
+func (a *TransactionArchiver) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := a.Archive(ctx, time.Now().UTC()); err != nil && ctx.Err() == nil {
+			a.logger.LogError(ctx, "transaction archival failed", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Archive moves every settled transaction created before now minus the
+// retention and returns how many were moved
+func (a *TransactionArchiver) Archive(ctx context.Context, now time.Time) (int64, error) {
+	cutoff, ok := archiveCutoff(now, a.retention)
+	if !ok {
+		return 0, nil
+	}
+
+	var total int64
+	for {
+		moved, err := a.transactionRepo.ArchiveBefore(ctx, cutoff, archiveBatchSize)
+		total += moved
+		if err != nil {
+			return total, fmt.Errorf("failed to archive transactions: %w", err)
+		}
+		if moved < archiveBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		a.logger.LogInfo(ctx, "transactions archived",
+			logger.Int("count", int(total)),
+			logger.String("cutoff", cutoff.Format(time.RFC3339)))
+	}
+
+	return total, nil
+}
+
+// archiveCutoff returns the creation time before which transactions are
+// archived, and false when retention keeps everything
+func archiveCutoff(now time.Time, retention time.Duration) (time.Time, bool) {
+	if retention <= 0 {
+		return time.Time{}, false
+	}
+	return now.Add(-retention), true
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// ErrInvalidBatchOperation is returned when a batch operation has an unknown type
+var ErrInvalidBatchOperation = errors.New("invalid batch operation")
+
+// Batch operation types
+const (
+	BatchOperationCredit = "credit"
+	BatchOperationDebit  = "debit"
+)
+
+// BatchOperation is a single credit or debit in a batch
+type BatchOperation struct {
+	UserID      string          `json:"user_id" binding:"required"`
+	Type        string          `json:"type" binding:"required,oneof=credit debit"`
+	Amount      decimal.Decimal `json:"amount" binding:"required"`
+	Description string          `json:"description" binding:"required"`
+}
+
+// BatchCreditRequest represents a request to apply several credits and debits
+// as one unit
+type BatchCreditRequest struct {
+	Description string           `json:"description"`
+	Operations  []BatchOperation `json:"operations" binding:"required,min=1,dive"`
+	ActorID     string           `json:"-"` // Admin submitting the batch
+}
+
+// BatchResponse represents a processed transaction batch in API responses
+type BatchResponse struct {
+	BatchID      string                 `json:"batch_id"`
+	Status       string                 `json:"status"`
+	TotalAmount  decimal.Decimal        `json:"total_amount"`
+	Description  string                 `json:"description"`
+	ProcessedAt  *time.Time             `json:"processed_at"`
+	Transactions []*TransactionResponse `json:"transactions"`
+}
+
+// ProcessBatch applies every operation in the request in a single database
+// transaction. The batch is recorded with its outcome: if any operation fails,
+// for example a debit exceeding the balance, no operation is applied and the
+// batch is marked failed.
+func (s *WalletService) ProcessBatch(ctx context.Context, req *BatchCreditRequest) (*BatchResponse, error) {
+	// Round credits with the policy the tracker uses, as CreditBalance does
+	for i := range req.Operations {
+		if req.Operations[i].Type == BatchOperationCredit {
+			req.Operations[i].Amount = s.rounding.Round(req.Operations[i].Amount)
+		}
+	}
+
+	for i, op := range req.Operations {
+		if op.Type != BatchOperationCredit && op.Type != BatchOperationDebit {
+			return nil, fmt.Errorf("%w: operation %d has type %q", ErrInvalidBatchOperation, i, op.Type)
+		}
+		if err := s.validateAmount(op.Amount); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	batch := &models.TransactionBatch{
+		BatchID:     uuid.New().String(),
+		Status:      models.BatchStatusPending,
+		TotalAmount: batchTotal(req.Operations),
+		Description: req.Description,
+	}
+	if err := s.walletRepo.CreateBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	s.logger.LogInfo(ctx, "processing transaction batch",
+		logger.String("batch_id", batch.BatchID),
+		logger.Int("operation_count", len(req.Operations)),
+		logger.String("actor_id", req.ActorID))
+
+	transactions, err := s.walletRepo.ProcessBatch(ctx, batch, batchUserIDs(req.Operations),
+		func(wallets map[string]*models.Wallet) ([]*models.Wallet, []*models.Transaction, error) {
+			return planBatch(wallets, batch.BatchID, req.Operations, req.ActorID)
+		})
+	if err != nil {
+		if statusErr := s.walletRepo.UpdateBatchStatus(ctx, batch, models.BatchStatusFailed); statusErr != nil {
+			s.logger.LogError(ctx, "failed to mark transaction batch failed", statusErr,
+				logger.String("batch_id", batch.BatchID))
+		}
+		return nil, fmt.Errorf("batch %s failed: %w", batch.BatchID, err)
+	}
+
+	responses := make([]*TransactionResponse, len(transactions))
+	for i, transaction := range transactions {
+		responses[i] = s.transactionToResponse(transaction)
+
+		event := &BalanceUpdatedEvent{
+			UserID:          transaction.UserID,
+			TransactionID:   transaction.ID.String(),
+			TransactionType: transaction.Type,
+			Amount:          transaction.Amount,
+			BalanceAfter:    transaction.BalanceAfter,
+			Source:          transaction.Source,
+			Timestamp:       time.Now().UTC(),
+		}
+		if transaction.IsDebit() {
+			event.Amount = transaction.Amount.Neg()
+		}
+		if err := s.eventPublisher.PublishBalanceUpdated(ctx, event); err != nil {
+			s.logger.LogError(ctx, "failed to publish balance updated event", err)
+		}
+	}
+
+	return &BatchResponse{
+		BatchID:      batch.BatchID,
+		Status:       batch.Status,
+		TotalAmount:  batch.TotalAmount,
+		Description:  batch.Description,
+		ProcessedAt:  batch.ProcessedAt,
+		Transactions: responses,
+	}, nil
+}
+
+// planBatch applies operations in order to copies of wallets, creating a
+// wallet for users credited for the first time. It returns the updated
+// wallets and one transaction per operation, or an error if any debit exceeds
+// the balance left by the operations before it. wallets is never modified, so
+// a failed plan leaves nothing to undo.
+func planBatch(wallets map[string]*models.Wallet, batchID string, operations []BatchOperation, actorID string) ([]*models.Wallet, []*models.Transaction, error) {
+	now := time.Now().UTC()
+	working := make(map[string]*models.Wallet, len(wallets))
+	var updated []*models.Wallet
+	transactions := make([]*models.Transaction, 0, len(operations))
+
+	for i, op := range operations {
+		wallet, ok := working[op.UserID]
+		if !ok {
+			if original, exists := wallets[op.UserID]; exists {
+				copied := *original
+				wallet = &copied
+			} else {
+				wallet = &models.Wallet{UserID: op.UserID}
+			}
+			working[op.UserID] = wallet
+			updated = append(updated, wallet)
+		}
+
+		transaction := &models.Transaction{
+			UserID:      op.UserID,
+			Status:      models.TransactionStatusCompleted,
+			Amount:      op.Amount,
+			Description: op.Description,
+			ReferenceID: batchID,
+			ActorID:     actorID,
+		}
+		switch op.Type {
+		case BatchOperationCredit:
+			transaction.Type = models.TransactionTypeCreditEarned
+			transaction.Source = models.CreditSourceAdjustment
+		case BatchOperationDebit:
+			if !wallet.CanSpend(op.Amount) {
+				return nil, nil, fmt.Errorf("operation %d for user %s: %w", i, op.UserID, ErrInsufficientBalance)
+			}
+			transaction.Type = models.TransactionTypeCreditSpent
+			transaction.Source = "spending"
+		default:
+			return nil, nil, fmt.Errorf("%w: operation %d has type %q", ErrInvalidBatchOperation, i, op.Type)
+		}
+
+		applyTransaction(wallet, transaction)
+		wallet.LastUpdated = now
+		transaction.BalanceAfter = wallet.AvailableCredits
+		transaction.ProcessedAt = &now
+		transactions = append(transactions, transaction)
+	}
+
+	return updated, transactions, nil
+}
+
+// batchTotal is the sum of the amounts moved by the operations
+func batchTotal(operations []BatchOperation) decimal.Decimal {
+	total := decimal.Zero
+	for _, op := range operations {
+		total = total.Add(op.Amount)
+	}
+	return total
+}
+
+// batchUserIDs returns each user the operations touch once
+func batchUserIDs(operations []BatchOperation) []string {
+	seen := make(map[string]bool, len(operations))
+	userIDs := make([]string, 0, len(operations))
+	for _, op := range operations {
+		if !seen[op.UserID] {
+			seen[op.UserID] = true
+			userIDs = append(userIDs, op.UserID)
+		}
+	}
+	return userIDs
+}
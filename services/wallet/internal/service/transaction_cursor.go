@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
+)
+
+// ErrInvalidCursor is returned when a transaction history cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// GetTransactionHistoryCursor retrieves a page of the user's transactions
+// matching filter, newest first, starting after cursor. An empty cursor starts
+// from the newest transaction. The returned cursor fetches the next page and
+// is empty on the last page. Unlike offset paging, pages neither skip nor
+// repeat transactions recorded while the client is paging.
+func (s *WalletService) GetTransactionHistoryCursor(ctx context.Context, userID string, filter TransactionFilter, cursor string, limit int) ([]*TransactionResponse, string, error) {
+	if err := validateTransactionFilter(filter); err != nil {
+		return nil, "", err
+	}
+
+	var createdAt time.Time
+	var id uuid.UUID
+	if cursor != "" {
+		var err error
+		createdAt, id, err = decodeTransactionCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	transactions, err := s.transactionRepo.GetByUserIDBefore(ctx, userID, filter, createdAt, id, limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get transaction history: %w", err)
+	}
+
+	transactions, nextCursor := cursorPage(transactions, limit)
+
+	responses := make([]*TransactionResponse, len(transactions))
+	for i, transaction := range transactions {
+		responses[i] = s.transactionToResponse(transaction)
+	}
+
+	return responses, nextCursor, nil
+}
+
+// cursorPage trims transactions, fetched with one row beyond limit, to the
+// page and returns the cursor of the following page, or "" if there is none
+func cursorPage(transactions []*models.Transaction, limit int) ([]*models.Transaction, string) {
+	if len(transactions) <= limit {
+		return transactions, ""
+	}
+
+	transactions = transactions[:limit]
+	last := transactions[len(transactions)-1]
+	return transactions, encodeTransactionCursor(last.CreatedAt, last.ID)
+}
+
+// encodeTransactionCursor builds the opaque cursor for the page after the
+// transaction identified by createdAt and id
+func encodeTransactionCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeTransactionCursor reverses encodeTransactionCursor
+func decodeTransactionCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+
+	createdAtPart, idPart, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtPart)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+
+	id, err := uuid.Parse(idPart)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+
+	return createdAt, id, nil
+}
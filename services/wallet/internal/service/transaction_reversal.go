@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// ErrNotReversible is returned when reversing a transaction whose type or
+// status cannot be reversed on its own
+var ErrNotReversible = errors.New("transaction cannot be reversed")
+
+// ErrAlreadyReversed is returned when reversing a transaction a second time
+var ErrAlreadyReversed = errors.New("transaction already reversed")
+
+// ReverseTransaction undoes a mistaken credit or debit by recording a
+// compensating transaction that references the original and flips its effect
+// on the balance. The original is marked reversed so it cannot be reversed
+// twice. Transfers are not reversible because each leg belongs to a different
+// wallet; they are undone with a transfer back.
+func (s *WalletService) ReverseTransaction(ctx context.Context, originalTxID uuid.UUID, reason, actorID string) (*TransactionResponse, error) {
+	original, err := s.transactionRepo.GetByID(ctx, originalTxID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get original transaction: %w", err)
+	}
+
+	inverseType, err := reversalType(original)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkReversalHistory(ctx, original); err != nil {
+		return nil, err
+	}
+
+	metadata, err := json.Marshal(map[string]string{
+		"original_transaction_id": original.ID.String(),
+		"original_type":           original.Type,
+		"reason":                  reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal reversal metadata: %w", err)
+	}
+
+	now := time.Now().UTC()
+	transaction := &models.Transaction{
+		UserID:      original.UserID,
+		Type:        inverseType,
+		Status:      models.TransactionStatusCompleted,
+		Amount:      original.Amount,
+		Source:      original.Source,
+		Description: reason,
+		ReferenceID: original.ID.String(),
+		Metadata:    string(metadata),
+		ActorID:     actorID,
+		ProcessedAt: &now,
+	}
+
+	// The reversal is checked and applied against the wallet as locked for
+	// it, so a concurrent balance change is neither lost nor overdrawn
+	wallet, err := s.walletRepo.ReverseWithTransaction(ctx, original, transaction, now, func(wallet *models.Wallet) error {
+		// Taking back a credit must not leave the wallet negative
+		if inverseType == models.TransactionTypeCreditReversed && !wallet.CanSpend(original.Amount) {
+			return fmt.Errorf("%w: credits from transaction %s were already spent", ErrInsufficientBalance, original.ID)
+		}
+
+		applyTransaction(wallet, transaction)
+		wallet.LastUpdated = now
+		transaction.BalanceAfter = wallet.AvailableCredits
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrTransactionReversed) {
+			return nil, fmt.Errorf("%w: transaction %s", ErrAlreadyReversed, original.ID)
+		}
+		if errors.Is(err, ErrInsufficientBalance) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to reverse transaction: %w", err)
+	}
+
+	amount := transaction.Amount
+	if inverseType == models.TransactionTypeCreditReversed {
+		amount = amount.Neg()
+	}
+	event := &BalanceUpdatedEvent{
+		UserID:          original.UserID,
+		TransactionID:   transaction.ID.String(),
+		TransactionType: transaction.Type,
+		Amount:          amount,
+		BalanceAfter:    wallet.AvailableCredits,
+		Source:          transaction.Source,
+		Timestamp:       now,
+	}
+
+	if err := s.eventPublisher.PublishBalanceUpdated(ctx, event); err != nil {
+		s.logger.LogError(ctx, "failed to publish balance updated event", err)
+	}
+
+	s.logger.LogInfo(ctx, "transaction reversed",
+		logger.String("user_id", original.UserID),
+		logger.String("original_transaction_id", original.ID.String()),
+		logger.String("amount", original.Amount.String()),
+		logger.String("actor_id", actorID))
+
+	return s.transactionToResponse(transaction), nil
+}
+
+// checkReversalHistory refuses to reverse a credit that ReverseCredit already
+// took back, or a spend that has been partly or fully refunded
+func (s *WalletService) checkReversalHistory(ctx context.Context, original *models.Transaction) error {
+	var related []*models.Transaction
+	if original.Type == models.TransactionTypeCreditEarned && original.ReferenceID != "" {
+		transactions, err := s.transactionRepo.GetByReferenceID(ctx, original.ReferenceID)
+		if err != nil {
+			return fmt.Errorf("failed to get related transactions: %w", err)
+		}
+		related = append(related, transactions...)
+	}
+
+	refunds, err := s.transactionRepo.GetByReferenceID(ctx, original.ID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get related transactions: %w", err)
+	}
+	related = append(related, refunds...)
+
+	return checkReversalRelated(original, related)
+}
+
+// checkReversalRelated validates a reversal of original against the
+// transactions that reference it or share its reference
+func checkReversalRelated(original *models.Transaction, related []*models.Transaction) error {
+	for _, transaction := range related {
+		if transaction.UserID != original.UserID || transaction.IsCreditAdjustment() {
+			continue
+		}
+		switch transaction.Type {
+		case models.TransactionTypeCreditReversed:
+			if original.Type == models.TransactionTypeCreditEarned {
+				return fmt.Errorf("%w: transaction %s", ErrAlreadyReversed, original.ID)
+			}
+		case models.TransactionTypeRefund:
+			if transaction.ReferenceID == original.ID.String() {
+				return fmt.Errorf("%w: transaction %s has refunds", ErrNotReversible, original.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// reversalType returns the transaction type that undoes original
+func reversalType(original *models.Transaction) (string, error) {
+	if original.ReversedAt != nil {
+		return "", fmt.Errorf("%w: transaction %s", ErrAlreadyReversed, original.ID)
+	}
+	if original.Status != models.TransactionStatusCompleted {
+		return "", fmt.Errorf("%w: %s transaction %s", ErrNotReversible, original.Status, original.ID)
+	}
+
+	switch original.Type {
+	case models.TransactionTypeCreditEarned, models.TransactionTypeBonus, models.TransactionTypeRefund:
+		return models.TransactionTypeCreditReversed, nil
+	case models.TransactionTypeCreditSpent, models.TransactionTypePenalty:
+		return models.TransactionTypeDebitReversed, nil
+	default:
+		// Transfers move credits between two wallets, and reversals and
+		// adjustments are themselves corrections
+		return "", fmt.Errorf("%w: %s transaction %s", ErrNotReversible, original.Type, original.ID)
+	}
+}
@@ -2,35 +2,104 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
 	"github.com/sloweyyy/GreenLedger/services/wallet/internal/repository"
+	"github.com/sloweyyy/GreenLedger/shared/credits"
+	"github.com/sloweyyy/GreenLedger/shared/database"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
 	"github.com/shopspring/decimal"
 )
 
+// ErrInvalidAmount is returned when an amount is not positive or below the minimum
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// ErrCreditNotFound is returned when reversing a credit that was never recorded
+var ErrCreditNotFound = errors.New("credit to reverse not found")
+
+// ErrNotRefundable is returned when refunding a transaction that is not a spend
+var ErrNotRefundable = errors.New("transaction cannot be refunded")
+
+// ErrRefundExceedsOriginal is returned when refunds would exceed the original spend
+var ErrRefundExceedsOriginal = errors.New("refund exceeds original transaction amount")
+
+// ErrBalanceNotZero is returned when erasing a wallet that still holds credits
+var ErrBalanceNotZero = errors.New("wallet balance is not zero")
+
+// ErrInsufficientBalance is returned when a debit or transfer exceeds the available balance
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+// ErrSelfTransfer is returned when a user transfers credits to themselves
+var ErrSelfTransfer = errors.New("cannot transfer to the same user")
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is replayed
+// with a different user, transaction type or amount
+var ErrIdempotencyKeyReused = errors.New("idempotency key reused with different request")
+
+// ErrInvalidTransactionFilter is returned when filtering transaction history
+// by an unknown type or a start date after the end date
+var ErrInvalidTransactionFilter = errors.New("invalid transaction filter")
+
+// ErrTransactionNotFound is returned when a transaction does not exist or
+// belongs to another user, so callers cannot probe for other users' IDs
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// DefaultMinTransactionAmount is the smallest amount accepted for credits,
+// debits, transfers and reservations. System credits are exempt, since the
+// tracker awards fractions of a credit for small activities.
+var DefaultMinTransactionAmount = decimal.NewFromFloat(0.01)
+
+// metricsServiceName labels the business metrics recorded by the wallet
+const metricsServiceName = "wallet"
+
+// transferMetricType labels transfers in the transactions metric, which
+// counts each transfer once rather than as its two ledger transactions
+const transferMetricType = "transfer"
+
 // WalletService handles wallet operations
 type WalletService struct {
 	walletRepo      *repository.WalletRepository
 	transactionRepo *repository.TransactionRepository
 	eventPublisher  EventPublisher
+	minAmount       decimal.Decimal
+	rounding        credits.RoundingPolicy
+	metrics         *monitoring.Metrics
 	logger          *logger.Logger
 }
 
-// NewWalletService creates a new wallet service
+// NewWalletService creates a new wallet service. A non-positive minAmount
+// falls back to DefaultMinTransactionAmount and a zero rounding policy to
+// credits.DefaultRoundingPolicy. metrics may be nil to record no business
+// metrics.
 func NewWalletService(
 	walletRepo *repository.WalletRepository,
 	transactionRepo *repository.TransactionRepository,
 	eventPublisher EventPublisher,
+	minAmount decimal.Decimal,
+	rounding credits.RoundingPolicy,
+	metrics *monitoring.Metrics,
 	logger *logger.Logger,
 ) *WalletService {
+	if minAmount.LessThanOrEqual(decimal.Zero) {
+		minAmount = DefaultMinTransactionAmount
+	}
+	if rounding.Mode == "" {
+		rounding = credits.DefaultRoundingPolicy
+	}
+
 	return &WalletService{
 		walletRepo:      walletRepo,
 		transactionRepo: transactionRepo,
 		eventPublisher:  eventPublisher,
+		minAmount:       minAmount,
+		rounding:        rounding,
+		metrics:         metrics,
 		logger:          logger,
 	}
 }
@@ -43,6 +112,15 @@ type CreditBalanceRequest struct {
 	Description string                 `json:"description" binding:"required"`
 	ReferenceID string                 `json:"reference_id"`
 	Metadata    map[string]interface{} `json:"metadata"`
+	ActorID     string                 `json:"-"` // Admin making the credit, empty for system credits
+
+	// SystemCredit marks a credit awarded for an event from another service,
+	// which is exempt from the minimum amount. It cannot be set over HTTP.
+	SystemCredit bool `json:"-"`
+
+	// IdempotencyKey makes retries safe: repeating a credit with the same key
+	// returns the original transaction instead of crediting again
+	IdempotencyKey string `json:"idempotency_key" binding:"omitempty,max=128"`
 }
 
 // DebitBalanceRequest represents a request to debit a wallet
@@ -52,6 +130,11 @@ type DebitBalanceRequest struct {
 	Description string                 `json:"description" binding:"required"`
 	ReferenceID string                 `json:"reference_id"`
 	Metadata    map[string]interface{} `json:"metadata"`
+	ActorID     string                 `json:"-"` // Admin making the debit, empty for system debits
+
+	// IdempotencyKey makes retries safe: repeating a debit with the same key
+	// returns the original transaction instead of debiting again
+	IdempotencyKey string `json:"idempotency_key" binding:"omitempty,max=128"`
 }
 
 // TransferCreditsRequest represents a request to transfer credits
@@ -61,6 +144,10 @@ type TransferCreditsRequest struct {
 	Amount      decimal.Decimal `json:"amount" binding:"required"`
 	Description string          `json:"description" binding:"required"`
 	Metadata    map[string]interface{} `json:"metadata"`
+
+	// ReferenceID, when set, becomes the transfer ID and makes retries safe:
+	// repeating a transfer with the same reference returns the original one
+	ReferenceID string `json:"-"`
 }
 
 // WalletResponse represents a wallet in API responses
@@ -68,26 +155,42 @@ type WalletResponse struct {
 	UserID           string          `json:"user_id"`
 	AvailableCredits decimal.Decimal `json:"available_credits"`
 	PendingCredits   decimal.Decimal `json:"pending_credits"`
+	ReservedCredits  decimal.Decimal `json:"reserved_credits"`
 	TotalEarned      decimal.Decimal `json:"total_earned"`
 	TotalSpent       decimal.Decimal `json:"total_spent"`
 	LastUpdated      time.Time       `json:"last_updated"`
 }
 
+// TopUserResponse represents a user's place in the earnings ranking
+type TopUserResponse struct {
+	Rank        int             `json:"rank"`
+	UserID      string          `json:"user_id"`
+	TotalEarned decimal.Decimal `json:"total_earned"`
+	TotalSpent  decimal.Decimal `json:"total_spent"`
+}
+
+// TransactionFilter narrows transaction history to a type, source and date
+// range; empty fields match every transaction
+type TransactionFilter = repository.TransactionFilter
+
 // TransactionResponse represents a transaction in API responses
 type TransactionResponse struct {
-	ID           uuid.UUID       `json:"id"`
-	UserID       string          `json:"user_id"`
-	Type         string          `json:"type"`
-	Status       string          `json:"status"`
-	Amount       decimal.Decimal `json:"amount"`
-	BalanceAfter decimal.Decimal `json:"balance_after"`
-	Source       string          `json:"source"`
-	Description  string          `json:"description"`
-	ReferenceID  string          `json:"reference_id"`
-	FromUserID   string          `json:"from_user_id,omitempty"`
-	ToUserID     string          `json:"to_user_id,omitempty"`
-	ProcessedAt  *time.Time      `json:"processed_at"`
-	CreatedAt    time.Time       `json:"created_at"`
+	ID           uuid.UUID              `json:"id"`
+	UserID       string                 `json:"user_id"`
+	Type         string                 `json:"type"`
+	Status       string                 `json:"status"`
+	Amount       decimal.Decimal        `json:"amount"`
+	BalanceAfter decimal.Decimal        `json:"balance_after"`
+	Source       string                 `json:"source"`
+	Description  string                 `json:"description"`
+	ReferenceID  string                 `json:"reference_id"`
+	FromUserID   string                 `json:"from_user_id,omitempty"`
+	ToUserID     string                 `json:"to_user_id,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	ActorID      string                 `json:"actor_id,omitempty"`
+	ProcessedAt  *time.Time             `json:"processed_at"`
+	ReversedAt   *time.Time             `json:"reversed_at,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
 }
 
 // EventPublisher interface for publishing wallet events
@@ -135,21 +238,97 @@ func (s *WalletService) GetBalance(ctx context.Context, userID string) (*WalletR
 	return s.walletToResponse(wallet), nil
 }
 
+// GetBalances retrieves wallet balances for multiple users in a single query.
+// Users without a wallet are reported with a zero balance; duplicate IDs are collapsed.
+func (s *WalletService) GetBalances(ctx context.Context, userIDs []string) ([]*WalletResponse, error) {
+	uniqueIDs := make([]string, 0, len(userIDs))
+	seen := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == "" || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		uniqueIDs = append(uniqueIDs, userID)
+	}
+
+	wallets, err := s.walletRepo.GetByUserIDs(ctx, uniqueIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallets: %w", err)
+	}
+
+	walletsByUser := make(map[string]*models.Wallet, len(wallets))
+	for _, wallet := range wallets {
+		walletsByUser[wallet.UserID] = wallet
+	}
+
+	responses := make([]*WalletResponse, len(uniqueIDs))
+	for i, userID := range uniqueIDs {
+		if wallet, ok := walletsByUser[userID]; ok {
+			responses[i] = s.walletToResponse(wallet)
+			continue
+		}
+		responses[i] = &WalletResponse{
+			UserID:           userID,
+			AvailableCredits: decimal.Zero,
+			PendingCredits:   decimal.Zero,
+			ReservedCredits:  decimal.Zero,
+			TotalEarned:      decimal.Zero,
+			TotalSpent:       decimal.Zero,
+		}
+	}
+
+	return responses, nil
+}
+
 // CreditBalance credits a user's wallet
 func (s *WalletService) CreditBalance(ctx context.Context, req *CreditBalanceRequest) (*TransactionResponse, error) {
+	// Round with the policy the tracker uses so credited amounts match
+	req.Amount = s.rounding.Round(req.Amount)
+
 	s.logger.LogInfo(ctx, "crediting wallet balance",
 		logger.String("user_id", req.UserID),
 		logger.String("amount", req.Amount.String()),
-		logger.String("source", req.Source))
+		logger.String("source", req.Source),
+		logger.String("actor_id", req.ActorID))
 
 	// Validate amount
-	if req.Amount.LessThanOrEqual(decimal.Zero) {
-		return nil, fmt.Errorf("amount must be positive")
+	if err := s.validateCreditAmount(req); err != nil {
+		s.recordTransaction(models.TransactionTypeCreditEarned, err)
+		return nil, err
 	}
 
-	// Get or create wallet
-	wallet, err := s.getOrCreateWallet(ctx, req.UserID)
+	if req.IdempotencyKey != "" {
+		existing, err := s.findIdempotentTransaction(ctx, req.IdempotencyKey, req.UserID, models.TransactionTypeCreditEarned, req.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
+
+	// A repeated credit for the same reference is returned as is, so a
+	// redelivered credit_earned event does not credit the wallet twice
+	if req.ReferenceID != "" {
+		transactions, err := s.transactionRepo.GetByReferenceID(ctx, req.ReferenceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transactions: %w", err)
+		}
+		if existing, _ := findCreditAndReversal(transactions, req.UserID); existing != nil {
+			s.logger.LogInfo(ctx, "credit already processed",
+				logger.String("user_id", req.UserID),
+				logger.String("reference_id", req.ReferenceID))
+			return s.transactionToResponse(existing), nil
+		}
+	}
+
+	metadata, err := encodeMetadata(req.Metadata)
 	if err != nil {
+		return nil, err
+	}
+
+	// Get or create wallet
+	if _, err := s.getOrCreateWallet(ctx, req.UserID); err != nil {
 		return nil, fmt.Errorf("failed to get wallet: %w", err)
 	}
 
@@ -162,13 +341,31 @@ func (s *WalletService) CreditBalance(ctx context.Context, req *CreditBalanceReq
 		Source:      req.Source,
 		Description: req.Description,
 		ReferenceID: req.ReferenceID,
+		Metadata:    metadata,
+		ActorID:     req.ActorID,
+	}
+	if req.IdempotencyKey != "" {
+		transaction.IdempotencyKey = &req.IdempotencyKey
 	}
 
-	// Process transaction atomically
-	updatedWallet, err := s.processTransaction(ctx, wallet, transaction)
+	// Process transaction atomically. A concurrent retry with the same
+	// idempotency key loses on the unique index, which rolls back its wallet
+	// update, and returns the winner's transaction.
+	updatedWallet, err := s.processTransaction(ctx, transaction)
 	if err != nil {
+		if req.IdempotencyKey != "" {
+			existing, lookupErr := s.findIdempotentTransaction(ctx, req.IdempotencyKey, req.UserID, transaction.Type, req.Amount)
+			if lookupErr != nil {
+				return nil, lookupErr
+			}
+			if existing != nil {
+				return existing, nil
+			}
+		}
+		s.recordTransaction(transaction.Type, err)
 		return nil, fmt.Errorf("failed to process transaction: %w", err)
 	}
+	s.recordTransaction(transaction.Type, nil)
 
 	// Publish event
 	event := &BalanceUpdatedEvent{
@@ -196,11 +393,38 @@ func (s *WalletService) CreditBalance(ctx context.Context, req *CreditBalanceReq
 func (s *WalletService) DebitBalance(ctx context.Context, req *DebitBalanceRequest) (*TransactionResponse, error) {
 	s.logger.LogInfo(ctx, "debiting wallet balance",
 		logger.String("user_id", req.UserID),
-		logger.String("amount", req.Amount.String()))
+		logger.String("amount", req.Amount.String()),
+		logger.String("actor_id", req.ActorID))
 
 	// Validate amount
-	if req.Amount.LessThanOrEqual(decimal.Zero) {
-		return nil, fmt.Errorf("amount must be positive")
+	if err := s.validateAmount(req.Amount); err != nil {
+		s.recordTransaction(models.TransactionTypeCreditSpent, err)
+		return nil, err
+	}
+
+	if req.IdempotencyKey != "" {
+		existing, err := s.findIdempotentTransaction(ctx, req.IdempotencyKey, req.UserID, models.TransactionTypeCreditSpent, req.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
+
+	// A repeated debit for the same reference is returned as is, so callers
+	// can retry without spending the credits twice
+	if req.ReferenceID != "" {
+		transactions, err := s.transactionRepo.GetByReferenceID(ctx, req.ReferenceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transactions: %w", err)
+		}
+		if existing := findDebit(transactions, req.UserID); existing != nil {
+			s.logger.LogInfo(ctx, "debit already processed",
+				logger.String("user_id", req.UserID),
+				logger.String("reference_id", req.ReferenceID))
+			return s.transactionToResponse(existing), nil
+		}
 	}
 
 	// Get wallet
@@ -211,7 +435,13 @@ func (s *WalletService) DebitBalance(ctx context.Context, req *DebitBalanceReque
 
 	// Check if user has sufficient balance
 	if !wallet.CanSpend(req.Amount) {
-		return nil, fmt.Errorf("insufficient balance")
+		s.recordTransaction(models.TransactionTypeCreditSpent, ErrInsufficientBalance)
+		return nil, ErrInsufficientBalance
+	}
+
+	metadata, err := encodeMetadata(req.Metadata)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create transaction
@@ -223,13 +453,31 @@ func (s *WalletService) DebitBalance(ctx context.Context, req *DebitBalanceReque
 		Source:      "spending",
 		Description: req.Description,
 		ReferenceID: req.ReferenceID,
+		Metadata:    metadata,
+		ActorID:     req.ActorID,
+	}
+	if req.IdempotencyKey != "" {
+		transaction.IdempotencyKey = &req.IdempotencyKey
 	}
 
-	// Process transaction atomically
-	updatedWallet, err := s.processTransaction(ctx, wallet, transaction)
+	// Process transaction atomically. A concurrent retry with the same
+	// idempotency key loses on the unique index, which rolls back its wallet
+	// update, and returns the winner's transaction.
+	updatedWallet, err := s.processTransaction(ctx, transaction)
 	if err != nil {
+		if req.IdempotencyKey != "" {
+			existing, lookupErr := s.findIdempotentTransaction(ctx, req.IdempotencyKey, req.UserID, transaction.Type, req.Amount)
+			if lookupErr != nil {
+				return nil, lookupErr
+			}
+			if existing != nil {
+				return existing, nil
+			}
+		}
+		s.recordTransaction(transaction.Type, err)
 		return nil, fmt.Errorf("failed to process transaction: %w", err)
 	}
+	s.recordTransaction(transaction.Type, nil)
 
 	// Publish event
 	event := &BalanceUpdatedEvent{
@@ -253,6 +501,329 @@ func (s *WalletService) DebitBalance(ctx context.Context, req *DebitBalanceReque
 	return s.transactionToResponse(transaction), nil
 }
 
+// findIdempotentTransaction returns the transaction already recorded for the
+// idempotency key, or nil if the key has not been used
+func (s *WalletService) findIdempotentTransaction(ctx context.Context, idempotencyKey, userID, transactionType string, amount decimal.Decimal) (*TransactionResponse, error) {
+	transaction, err := s.transactionRepo.GetByIdempotencyKey(ctx, idempotencyKey)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to check idempotency key: %w", err)
+	}
+
+	if !matchesIdempotentRequest(transaction, userID, transactionType, amount) {
+		return nil, ErrIdempotencyKeyReused
+	}
+
+	s.logger.LogInfo(ctx, "returning transaction for repeated idempotency key",
+		logger.String("transaction_id", transaction.ID.String()),
+		logger.String("user_id", userID))
+
+	return s.transactionToResponse(transaction), nil
+}
+
+// matchesIdempotentRequest reports whether a transaction was recorded for the
+// same request, so a replayed idempotency key can be told apart from a reused one
+func matchesIdempotentRequest(transaction *models.Transaction, userID, transactionType string, amount decimal.Decimal) bool {
+	return transaction.UserID == userID &&
+		transaction.Type == transactionType &&
+		transaction.Amount.Equal(amount)
+}
+
+// ReverseCreditRequest represents a request to reverse the credit earned from a source
+type ReverseCreditRequest struct {
+	UserID      string `json:"user_id" binding:"required"`
+	ReferenceID string `json:"reference_id" binding:"required"`
+	Description string `json:"description" binding:"required"`
+	// IdempotencyKey optionally identifies the revocation, so concurrent
+	// redeliveries of it record one reversal
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// ReverseCredit records a compensating debit for the credit referenced by
+// req.ReferenceID, including any adjustments made to it since. It is
+// idempotent: an existing reversal is returned as is. If the user has since
+// spent part of the credit, only the available balance is taken back and the
+// shortfall is recorded on the reversal.
+func (s *WalletService) ReverseCredit(ctx context.Context, req *ReverseCreditRequest) (*TransactionResponse, error) {
+	transactions, err := s.transactionRepo.GetByReferenceID(ctx, req.ReferenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	original, reversal := findCreditAndReversal(transactions, req.UserID)
+	if reversal != nil {
+		s.logger.LogInfo(ctx, "credit already reversed",
+			logger.String("user_id", req.UserID),
+			logger.String("reference_id", req.ReferenceID))
+		return s.transactionToResponse(reversal), nil
+	}
+	if original == nil {
+		return nil, fmt.Errorf("%w: reference %s", ErrCreditNotFound, req.ReferenceID)
+	}
+	if original.ReversedAt != nil {
+		// An admin already took the credit back with ReverseTransaction
+		s.logger.LogInfo(ctx, "credit already reversed by an admin",
+			logger.String("user_id", req.UserID),
+			logger.String("reference_id", req.ReferenceID))
+		return s.transactionToResponse(original), nil
+	}
+
+	credited := creditedAmount(original, transactions)
+	transaction := &models.Transaction{
+		UserID:      req.UserID,
+		Type:        models.TransactionTypeCreditReversed,
+		Status:      models.TransactionStatusCompleted,
+		Source:      original.Source,
+		Description: req.Description,
+		ReferenceID: req.ReferenceID,
+	}
+	if req.IdempotencyKey != "" {
+		transaction.IdempotencyKey = &req.IdempotencyKey
+	}
+
+	// A concurrent redelivery loses on the idempotency key's unique index
+	updatedWallet, shortfall, err := s.processCreditReversal(ctx, transaction, credited, map[string]string{
+		"original_transaction_id": original.ID.String(),
+		"original_amount":         original.Amount.String(),
+		"credited_amount":         credited.String(),
+	})
+	if err != nil {
+		if req.IdempotencyKey != "" {
+			if existing, lookupErr := s.transactionRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey); lookupErr == nil {
+				return s.transactionToResponse(existing), nil
+			}
+		}
+		return nil, fmt.Errorf("failed to process transaction: %w", err)
+	}
+	amount := transaction.Amount
+	if shortfall.IsPositive() {
+		s.logger.LogWarn(ctx, "credits already spent, reversing available balance only",
+			logger.String("user_id", req.UserID),
+			logger.String("reference_id", req.ReferenceID),
+			logger.String("shortfall", shortfall.String()))
+	}
+
+	event := &BalanceUpdatedEvent{
+		UserID:          req.UserID,
+		TransactionID:   transaction.ID.String(),
+		TransactionType: transaction.Type,
+		Amount:          amount.Neg(),
+		BalanceAfter:    updatedWallet.AvailableCredits,
+		Source:          transaction.Source,
+		Timestamp:       time.Now().UTC(),
+	}
+
+	if err := s.eventPublisher.PublishBalanceUpdated(ctx, event); err != nil {
+		s.logger.LogError(ctx, "failed to publish balance updated event", err)
+	}
+
+	s.logger.LogInfo(ctx, "credit reversed",
+		logger.String("user_id", req.UserID),
+		logger.String("reference_id", req.ReferenceID),
+		logger.String("amount", amount.String()))
+
+	return s.transactionToResponse(transaction), nil
+}
+
+// AdjustCreditRequest represents a request to correct the credit earned from
+// a source whose credits changed
+type AdjustCreditRequest struct {
+	UserID      string          `json:"user_id" binding:"required"`
+	ReferenceID string          `json:"reference_id" binding:"required"`
+	Amount      decimal.Decimal `json:"amount" binding:"required"` // negative takes credits back
+	Description string          `json:"description" binding:"required"`
+	// IdempotencyKey identifies one correction, so a redelivered one is
+	// applied once
+	IdempotencyKey string `json:"idempotency_key" binding:"required"`
+}
+
+// AdjustCredit corrects the credit referenced by req.ReferenceID by a signed
+// amount, recorded as an adjustment sharing the credit's reference so a
+// later ReverseCredit takes back the adjusted amount. Like a reversal, a
+// negative adjustment takes back at most the available balance and records
+// the shortfall. A credit that was already reversed is left as is.
+func (s *WalletService) AdjustCredit(ctx context.Context, req *AdjustCreditRequest) (*TransactionResponse, error) {
+	if req.Amount.IsZero() {
+		return nil, fmt.Errorf("%w: adjustment must not be zero", ErrInvalidAmount)
+	}
+
+	existing, err := s.transactionRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey)
+	if err == nil {
+		s.logger.LogInfo(ctx, "credit adjustment already processed",
+			logger.String("user_id", req.UserID),
+			logger.String("reference_id", req.ReferenceID))
+		return s.transactionToResponse(existing), nil
+	}
+	if !errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("failed to check idempotency key: %w", err)
+	}
+
+	transactions, err := s.transactionRepo.GetByReferenceID(ctx, req.ReferenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	original, reversal := findCreditAndReversal(transactions, req.UserID)
+	if original == nil {
+		return nil, fmt.Errorf("%w: reference %s", ErrCreditNotFound, req.ReferenceID)
+	}
+	if reversal != nil || original.ReversedAt != nil {
+		s.logger.LogInfo(ctx, "credit already reversed, skipping adjustment",
+			logger.String("user_id", req.UserID),
+			logger.String("reference_id", req.ReferenceID))
+		if reversal != nil {
+			return s.transactionToResponse(reversal), nil
+		}
+		return s.transactionToResponse(original), nil
+	}
+
+	transaction := &models.Transaction{
+		UserID:         req.UserID,
+		Type:           models.TransactionTypeCreditEarned,
+		Status:         models.TransactionStatusCompleted,
+		Amount:         req.Amount,
+		Source:         models.CreditSourceAdjustment,
+		Description:    req.Description,
+		ReferenceID:    req.ReferenceID,
+		IdempotencyKey: &req.IdempotencyKey,
+	}
+	metadata := map[string]string{
+		"original_transaction_id": original.ID.String(),
+		"adjustment":              req.Amount.String(),
+	}
+
+	// A concurrent redelivery loses on the idempotency key's unique index
+	var updatedWallet *models.Wallet
+	balanceChange := req.Amount
+	if req.Amount.IsNegative() {
+		transaction.Type = models.TransactionTypeCreditReversed
+		var shortfall decimal.Decimal
+		updatedWallet, shortfall, err = s.processCreditReversal(ctx, transaction, req.Amount.Neg(), metadata)
+		if err == nil {
+			balanceChange = transaction.Amount.Neg()
+			if shortfall.IsPositive() {
+				s.logger.LogWarn(ctx, "credits already spent, adjusting available balance only",
+					logger.String("user_id", req.UserID),
+					logger.String("reference_id", req.ReferenceID),
+					logger.String("shortfall", shortfall.String()))
+			}
+		}
+	} else {
+		metadata["shortfall"] = decimal.Zero.String()
+		encoded, marshalErr := json.Marshal(metadata)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal adjustment metadata: %w", marshalErr)
+		}
+		transaction.Metadata = string(encoded)
+		updatedWallet, err = s.processTransaction(ctx, transaction)
+	}
+	if err != nil {
+		if existing, lookupErr := s.transactionRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey); lookupErr == nil {
+			return s.transactionToResponse(existing), nil
+		}
+		return nil, fmt.Errorf("failed to process transaction: %w", err)
+	}
+
+	event := &BalanceUpdatedEvent{
+		UserID:          req.UserID,
+		TransactionID:   transaction.ID.String(),
+		TransactionType: transaction.Type,
+		Amount:          balanceChange,
+		BalanceAfter:    updatedWallet.AvailableCredits,
+		Source:          transaction.Source,
+		Timestamp:       time.Now().UTC(),
+	}
+
+	if err := s.eventPublisher.PublishBalanceUpdated(ctx, event); err != nil {
+		s.logger.LogError(ctx, "failed to publish balance updated event", err)
+	}
+
+	s.logger.LogInfo(ctx, "credit adjusted",
+		logger.String("user_id", req.UserID),
+		logger.String("reference_id", req.ReferenceID),
+		logger.String("amount", balanceChange.String()))
+
+	return s.transactionToResponse(transaction), nil
+}
+
+// RefundTransaction returns part or all of a spend to the user's wallet as a
+// refund credit referencing the original transaction. Refunds are cumulative:
+// together they may not exceed the amount originally spent. The original is
+// locked while earlier refunds are totalled and the refund is saved, so
+// concurrent refunds cannot together exceed it.
+func (s *WalletService) RefundTransaction(ctx context.Context, originalTxID uuid.UUID, amount decimal.Decimal, actorID string) (*TransactionResponse, error) {
+	if err := s.validateAmount(amount); err != nil {
+		return nil, err
+	}
+
+	transaction := &models.Transaction{
+		Type:        models.TransactionTypeRefund,
+		Status:      models.TransactionStatusCompleted,
+		Amount:      amount,
+		Source:      "refund",
+		Description: fmt.Sprintf("Refund of transaction %s", originalTxID),
+		ReferenceID: originalTxID.String(),
+		ActorID:     actorID,
+	}
+
+	original, wallet, err := s.walletRepo.RefundWithTransaction(ctx, originalTxID, transaction,
+		func(original *models.Transaction, related []*models.Transaction, wallet *models.Wallet) error {
+			if err := checkRefund(original, related, amount); err != nil {
+				return err
+			}
+
+			metadata, err := json.Marshal(map[string]string{
+				"original_transaction_id": original.ID.String(),
+				"original_amount":         original.Amount.String(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to marshal refund metadata: %w", err)
+			}
+
+			transaction.UserID = original.UserID
+			transaction.Metadata = string(metadata)
+
+			applyTransaction(wallet, transaction)
+			wallet.LastUpdated = time.Now().UTC()
+			transaction.BalanceAfter = wallet.AvailableCredits
+			transaction.ProcessedAt = &wallet.LastUpdated
+			return nil
+		})
+	if err != nil {
+		if errors.Is(err, ErrNotRefundable) || errors.Is(err, ErrRefundExceedsOriginal) {
+			return nil, err
+		}
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get original transaction: %w", err)
+		}
+		return nil, fmt.Errorf("failed to process transaction: %w", err)
+	}
+
+	event := &BalanceUpdatedEvent{
+		UserID:          original.UserID,
+		TransactionID:   transaction.ID.String(),
+		TransactionType: transaction.Type,
+		Amount:          amount,
+		BalanceAfter:    wallet.AvailableCredits,
+		Source:          transaction.Source,
+		Timestamp:       time.Now().UTC(),
+	}
+
+	if err := s.eventPublisher.PublishBalanceUpdated(ctx, event); err != nil {
+		s.logger.LogError(ctx, "failed to publish balance updated event", err)
+	}
+
+	s.logger.LogInfo(ctx, "transaction refunded",
+		logger.String("user_id", original.UserID),
+		logger.String("original_transaction_id", original.ID.String()),
+		logger.String("amount", amount.String()),
+		logger.String("actor_id", actorID))
+
+	return s.transactionToResponse(transaction), nil
+}
+
 // TransferCredits transfers credits between users
 func (s *WalletService) TransferCredits(ctx context.Context, req *TransferCreditsRequest) (*TransferResponse, error) {
 	s.logger.LogInfo(ctx, "transferring credits",
@@ -261,13 +832,28 @@ func (s *WalletService) TransferCredits(ctx context.Context, req *TransferCredit
 		logger.String("amount", req.Amount.String()))
 
 	// Validate amount
-	if req.Amount.LessThanOrEqual(decimal.Zero) {
-		return nil, fmt.Errorf("amount must be positive")
+	if err := s.validateAmount(req.Amount); err != nil {
+		s.recordTransaction(transferMetricType, err)
+		return nil, err
 	}
 
 	// Validate users are different
 	if req.FromUserID == req.ToUserID {
-		return nil, fmt.Errorf("cannot transfer to the same user")
+		s.recordTransaction(transferMetricType, ErrSelfTransfer)
+		return nil, ErrSelfTransfer
+	}
+
+	if req.ReferenceID != "" {
+		existing, err := s.findTransfer(ctx, req.ReferenceID, req.FromUserID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			s.logger.LogInfo(ctx, "transfer already processed",
+				logger.String("from_user_id", req.FromUserID),
+				logger.String("reference_id", req.ReferenceID))
+			return existing, nil
+		}
 	}
 
 	// Get sender wallet
@@ -276,19 +862,28 @@ func (s *WalletService) TransferCredits(ctx context.Context, req *TransferCredit
 		return nil, fmt.Errorf("failed to get sender wallet: %w", err)
 	}
 
-	// Check if sender has sufficient balance
+	// Check if sender has sufficient balance. The check is repeated on the
+	// locked wallet when the transfer is applied.
 	if !fromWallet.CanSpend(req.Amount) {
-		return nil, fmt.Errorf("insufficient balance")
+		s.recordTransaction(transferMetricType, ErrInsufficientBalance)
+		return nil, ErrInsufficientBalance
 	}
 
-	// Get or create receiver wallet
-	toWallet, err := s.getOrCreateWallet(ctx, req.ToUserID)
-	if err != nil {
+	// Make sure the receiver has a wallet to lock
+	if _, err := s.getOrCreateWallet(ctx, req.ToUserID); err != nil {
 		return nil, fmt.Errorf("failed to get receiver wallet: %w", err)
 	}
 
+	metadata, err := encodeMetadata(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate transfer ID
 	transferID := uuid.New().String()
+	if req.ReferenceID != "" {
+		transferID = req.ReferenceID
+	}
 
 	// Create debit transaction for sender
 	debitTransaction := &models.Transaction{
@@ -300,6 +895,7 @@ func (s *WalletService) TransferCredits(ctx context.Context, req *TransferCredit
 		Description: req.Description,
 		ReferenceID: transferID,
 		ToUserID:    req.ToUserID,
+		Metadata:    metadata,
 	}
 
 	// Create credit transaction for receiver
@@ -312,13 +908,19 @@ func (s *WalletService) TransferCredits(ctx context.Context, req *TransferCredit
 		Description: req.Description,
 		ReferenceID: transferID,
 		FromUserID:  req.FromUserID,
+		Metadata:    metadata,
 	}
 
 	// Process transfer atomically
-	updatedFromWallet, updatedToWallet, err := s.processTransfer(ctx, fromWallet, toWallet, debitTransaction, creditTransaction)
+	updatedFromWallet, updatedToWallet, err := s.processTransfer(ctx, debitTransaction, creditTransaction)
 	if err != nil {
+		s.recordTransaction(transferMetricType, err)
+		if errors.Is(err, ErrInsufficientBalance) {
+			return nil, ErrInsufficientBalance
+		}
 		return nil, fmt.Errorf("failed to process transfer: %w", err)
 	}
+	s.recordTransaction(transferMetricType, nil)
 
 	// Publish transfer completed event
 	transferEvent := &TransferCompletedEvent{
@@ -347,8 +949,12 @@ func (s *WalletService) TransferCredits(ctx context.Context, req *TransferCredit
 }
 
 // GetTransactionHistory retrieves transaction history for a user
-func (s *WalletService) GetTransactionHistory(ctx context.Context, userID string, limit, offset int) ([]*TransactionResponse, int64, error) {
-	transactions, total, err := s.transactionRepo.GetByUserID(ctx, userID, limit, offset)
+func (s *WalletService) GetTransactionHistory(ctx context.Context, userID string, filter TransactionFilter, limit, offset int) ([]*TransactionResponse, int64, error) {
+	if err := validateTransactionFilter(filter); err != nil {
+		return nil, 0, err
+	}
+
+	transactions, total, err := s.transactionRepo.GetByUserIDFiltered(ctx, userID, filter, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get transaction history: %w", err)
 	}
@@ -361,12 +967,280 @@ func (s *WalletService) GetTransactionHistory(ctx context.Context, userID string
 	return responses, total, nil
 }
 
+// validateTransactionFilter rejects unknown transaction types and inverted
+// date ranges
+func validateTransactionFilter(filter TransactionFilter) error {
+	if filter.Type != "" && !models.IsTransactionType(filter.Type) {
+		return fmt.Errorf("%w: unknown transaction type %q", ErrInvalidTransactionFilter, filter.Type)
+	}
+	if filter.StartDate != nil && filter.EndDate != nil && filter.StartDate.After(*filter.EndDate) {
+		return fmt.Errorf("%w: start date is after end date", ErrInvalidTransactionFilter)
+	}
+	return nil
+}
+
+// ListPendingTransactions retrieves transactions awaiting processing across
+// all users, oldest first
+func (s *WalletService) ListPendingTransactions(ctx context.Context, limit, offset int) ([]*TransactionResponse, int64, error) {
+	transactions, total, err := s.transactionRepo.GetPendingTransactions(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get pending transactions: %w", err)
+	}
+
+	responses := make([]*TransactionResponse, len(transactions))
+	for i, transaction := range transactions {
+		responses[i] = s.transactionToResponse(transaction)
+	}
+
+	return responses, total, nil
+}
+
+// GetTopUsersByEarned ranks up to limit users by the credits they have earned
+func (s *WalletService) GetTopUsersByEarned(ctx context.Context, limit int) ([]*TopUserResponse, error) {
+	wallets, err := s.walletRepo.GetTopUsers(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return rankTopUsers(wallets), nil
+}
+
+// rankTopUsers numbers wallets, already ordered by total earned, from 1
+func rankTopUsers(wallets []*models.Wallet) []*TopUserResponse {
+	ranking := make([]*TopUserResponse, len(wallets))
+	for i, wallet := range wallets {
+		ranking[i] = &TopUserResponse{
+			Rank:        i + 1,
+			UserID:      wallet.UserID,
+			TotalEarned: wallet.TotalEarned,
+			TotalSpent:  wallet.TotalSpent,
+		}
+	}
+	return ranking
+}
+
+// GetTransactionByID retrieves one of the user's transactions
+func (s *WalletService) GetTransactionByID(ctx context.Context, id uuid.UUID, userID string) (*TransactionResponse, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, id)
+	transaction, err = ownedTransaction(transaction, err, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transactionToResponse(transaction), nil
+}
+
+// ownedTransaction checks the result of a transaction lookup against the
+// requesting user. A missing transaction and another user's transaction both
+// yield ErrTransactionNotFound.
+func ownedTransaction(transaction *models.Transaction, err error, userID string) (*models.Transaction, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if transaction.UserID != userID {
+		return nil, ErrTransactionNotFound
+	}
+	return transaction, nil
+}
+
+// CheckErasable returns ErrBalanceNotZero while the user's wallet still holds
+// credits. A user without a wallet can be erased.
+func (s *WalletService) CheckErasable(ctx context.Context, userID string) error {
+	wallet, err := s.walletRepo.GetByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("failed to get wallet: %w", err)
+	}
+	return checkWalletErasable(wallet)
+}
+
+// EraseUserData erases a deleted user's wallet data. Erasure is refused while
+// the wallet still holds credits so that no balance is silently destroyed.
+func (s *WalletService) EraseUserData(ctx context.Context, userID string) error {
+	if err := s.CheckErasable(ctx, userID); err != nil {
+		if errors.Is(err, ErrBalanceNotZero) {
+			s.logger.LogWarn(ctx, "wallet erasure refused",
+				logger.String("user_id", userID),
+				logger.String("reason", err.Error()))
+		}
+		return err
+	}
+
+	if err := s.walletRepo.EraseUserData(ctx, userID); err != nil {
+		return fmt.Errorf("failed to erase wallet data: %w", err)
+	}
+
+	s.logger.LogInfo(ctx, "wallet data erased for deleted user",
+		logger.String("user_id", userID))
+
+	return nil
+}
+
 // Helper methods
+func findCreditAndReversal(transactions []*models.Transaction, userID string) (credit, reversal *models.Transaction) {
+	for _, transaction := range transactions {
+		if transaction.UserID != userID || transaction.IsCreditAdjustment() {
+			continue
+		}
+		switch transaction.Type {
+		case models.TransactionTypeCreditEarned:
+			if credit == nil {
+				credit = transaction
+			}
+		case models.TransactionTypeCreditReversed:
+			if reversal == nil {
+				reversal = transaction
+			}
+		}
+	}
+	return credit, reversal
+}
+
+// creditedAmount is what original is worth after the adjustments sharing
+// its reference: upward adjustments add to it and downward ones subtract
+// what they took back
+func creditedAmount(original *models.Transaction, transactions []*models.Transaction) decimal.Decimal {
+	amount := original.Amount
+	for _, transaction := range transactions {
+		if transaction.UserID != original.UserID || !transaction.IsCreditAdjustment() {
+			continue
+		}
+		if transaction.Type == models.TransactionTypeCreditEarned {
+			amount = amount.Add(transaction.Amount)
+		} else {
+			amount = amount.Sub(transaction.Amount)
+		}
+	}
+	return amount
+}
+
+// findDebit returns the user's spend transaction among those sharing a reference
+func findDebit(transactions []*models.Transaction, userID string) *models.Transaction {
+	for _, transaction := range transactions {
+		if transaction.UserID == userID && transaction.Type == models.TransactionTypeCreditSpent {
+			return transaction
+		}
+	}
+	return nil
+}
+
+// findTransfer returns the transfer fromUserID made under referenceID, or
+// nil if there is none. Balances are left out of the response.
+func (s *WalletService) findTransfer(ctx context.Context, referenceID, fromUserID string) (*TransferResponse, error) {
+	transactions, err := s.transactionRepo.GetByReferenceID(ctx, referenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	response := &TransferResponse{TransferID: referenceID}
+	for _, transaction := range transactions {
+		switch {
+		case transaction.Type == models.TransactionTypeTransferOut && transaction.UserID == fromUserID:
+			response.FromTransaction = s.transactionToResponse(transaction)
+		case transaction.Type == models.TransactionTypeTransferIn && transaction.FromUserID == fromUserID:
+			response.ToTransaction = s.transactionToResponse(transaction)
+		}
+	}
+	if response.FromTransaction == nil {
+		return nil, nil
+	}
+
+	return response, nil
+}
+
+// checkRefund validates a refund of amount against the original transaction
+// and the refunds already recorded against it
+func checkRefund(original *models.Transaction, related []*models.Transaction, amount decimal.Decimal) error {
+	if original.Status != models.TransactionStatusCompleted ||
+		(original.Type != models.TransactionTypeCreditSpent && original.Type != models.TransactionTypePenalty) {
+		return fmt.Errorf("%w: %s transaction %s", ErrNotRefundable, original.Type, original.ID)
+	}
+	if original.ReversedAt != nil {
+		return fmt.Errorf("%w: transaction %s was reversed", ErrNotRefundable, original.ID)
+	}
+
+	refunded := decimal.Zero
+	for _, transaction := range related {
+		if transaction.UserID == original.UserID && transaction.Type == models.TransactionTypeRefund {
+			refunded = refunded.Add(transaction.Amount)
+		}
+	}
+
+	if remaining := original.Amount.Sub(refunded); amount.GreaterThan(remaining) {
+		return fmt.Errorf("%w: %s remaining of %s", ErrRefundExceedsOriginal, remaining, original.Amount)
+	}
+	return nil
+}
+
+// reversalAmount caps a reversal at the available balance so a wallet never
+// goes negative, returning the part that could not be taken back
+func reversalAmount(credited, available decimal.Decimal) (amount, shortfall decimal.Decimal) {
+	if available.LessThan(decimal.Zero) {
+		available = decimal.Zero
+	}
+	if credited.LessThanOrEqual(available) {
+		return credited, decimal.Zero
+	}
+	return available, credited.Sub(available)
+}
+
+func checkWalletErasable(wallet *models.Wallet) error {
+	if wallet == nil {
+		return nil
+	}
+	if !wallet.AvailableCredits.IsZero() || !wallet.PendingCredits.IsZero() || !wallet.ReservedCredits.IsZero() {
+		return fmt.Errorf("%w: available %s, pending %s, reserved %s", ErrBalanceNotZero,
+			wallet.AvailableCredits.String(), wallet.PendingCredits.String(), wallet.ReservedCredits.String())
+	}
+	return nil
+}
+
+// recordTransaction counts a credit, debit or transfer as completed, or as
+// failed when err is set. Replays of already processed requests are not
+// counted.
+func (s *WalletService) recordTransaction(txType string, err error) {
+	if s.metrics == nil {
+		return
+	}
+	status := models.TransactionStatusCompleted
+	if err != nil {
+		status = models.TransactionStatusFailed
+	}
+	s.metrics.RecordTransaction(metricsServiceName, txType, status)
+}
+
+// validateAmount validates an amount moved into or out of a wallet, which must
+// be positive and meet the minimum so the ledger is not spammed with dust
+func (s *WalletService) validateAmount(amount decimal.Decimal) error {
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return fmt.Errorf("%w: must be positive", ErrInvalidAmount)
+	}
+	if amount.LessThan(s.minAmount) {
+		return fmt.Errorf("%w: %s is below the minimum of %s", ErrInvalidAmount, amount.String(), s.minAmount.String())
+	}
+	return nil
+}
+
+// validateCreditAmount validates a credit's amount. System credits only need
+// to be positive, since small activities earn less than the minimum.
+func (s *WalletService) validateCreditAmount(req *CreditBalanceRequest) error {
+	if req.SystemCredit {
+		if req.Amount.LessThanOrEqual(decimal.Zero) {
+			return fmt.Errorf("%w: must be positive", ErrInvalidAmount)
+		}
+		return nil
+	}
+	return s.validateAmount(req.Amount)
+}
+
 func (s *WalletService) createWallet(ctx context.Context, userID string) (*models.Wallet, error) {
 	wallet := &models.Wallet{
 		UserID:           userID,
 		AvailableCredits: decimal.Zero,
 		PendingCredits:   decimal.Zero,
+		ReservedCredits:  decimal.Zero,
 		TotalEarned:      decimal.Zero,
 		TotalSpent:       decimal.Zero,
 		LastUpdated:      time.Now().UTC(),
@@ -390,49 +1264,105 @@ func (s *WalletService) getOrCreateWallet(ctx context.Context, userID string) (*
 	return wallet, nil
 }
 
-func (s *WalletService) processTransaction(ctx context.Context, wallet *models.Wallet, transaction *models.Transaction) (*models.Wallet, error) {
-	// Update wallet balance based on transaction type
+// processTransaction applies transaction to the user's wallet as locked for
+// update, so a concurrent change to the wallet is not overwritten, and saves
+// both. A spend the locked balance cannot cover fails with
+// ErrInsufficientBalance.
+func (s *WalletService) processTransaction(ctx context.Context, transaction *models.Transaction) (*models.Wallet, error) {
+	return s.walletRepo.UpdateWithTransaction(ctx, transaction.UserID, transaction, func(locked *models.Wallet) error {
+		if transaction.Type == models.TransactionTypeCreditSpent && !locked.CanSpend(transaction.Amount) {
+			return ErrInsufficientBalance
+		}
+
+		// Update wallet balance based on transaction type
+		applyTransaction(locked, transaction)
+
+		locked.LastUpdated = time.Now().UTC()
+		transaction.BalanceAfter = locked.AvailableCredits
+		transaction.ProcessedAt = &locked.LastUpdated
+		return nil
+	})
+}
+
+// processCreditReversal takes back up to credited from the user's wallet as
+// locked for update, recording transaction as the reversal. The amount is
+// capped at the locked available balance, so a concurrent spend cannot drive
+// the wallet negative; the part that was already spent is returned as the
+// shortfall and recorded in the reversal's metadata.
+func (s *WalletService) processCreditReversal(ctx context.Context, transaction *models.Transaction, credited decimal.Decimal, metadata map[string]string) (*models.Wallet, decimal.Decimal, error) {
+	shortfall := decimal.Zero
+	wallet, err := s.walletRepo.UpdateWithTransaction(ctx, transaction.UserID, transaction, func(locked *models.Wallet) error {
+		transaction.Amount, shortfall = reversalAmount(credited, locked.AvailableCredits)
+
+		metadata["shortfall"] = shortfall.String()
+		encoded, err := json.Marshal(metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal reversal metadata: %w", err)
+		}
+		transaction.Metadata = string(encoded)
+
+		applyTransaction(locked, transaction)
+		locked.LastUpdated = time.Now().UTC()
+		transaction.BalanceAfter = locked.AvailableCredits
+		transaction.ProcessedAt = &locked.LastUpdated
+		return nil
+	})
+	if err != nil {
+		return nil, decimal.Zero, err
+	}
+	return wallet, shortfall, nil
+}
+
+// applyTransaction updates a wallet's balances for a completed transaction
+func applyTransaction(wallet *models.Wallet, transaction *models.Transaction) {
 	if transaction.IsCredit() {
 		wallet.AvailableCredits = wallet.AvailableCredits.Add(transaction.Amount)
 		wallet.TotalEarned = wallet.TotalEarned.Add(transaction.Amount)
+	} else if transaction.Type == models.TransactionTypeCreditReversed {
+		// A reversal takes back earned credits rather than spending them
+		wallet.AvailableCredits = wallet.AvailableCredits.Sub(transaction.Amount)
+		wallet.TotalEarned = wallet.TotalEarned.Sub(transaction.Amount)
+	} else if transaction.Type == models.TransactionTypeDebitReversed {
+		// A debit reversal gives back spent credits rather than earning them
+		wallet.AvailableCredits = wallet.AvailableCredits.Add(transaction.Amount)
+		wallet.TotalSpent = wallet.TotalSpent.Sub(transaction.Amount)
 	} else if transaction.IsDebit() {
 		wallet.AvailableCredits = wallet.AvailableCredits.Sub(transaction.Amount)
 		wallet.TotalSpent = wallet.TotalSpent.Add(transaction.Amount)
 	}
+}
 
-	wallet.LastUpdated = time.Now().UTC()
-	transaction.BalanceAfter = wallet.AvailableCredits
-	transaction.ProcessedAt = &wallet.LastUpdated
-
-	// Save both wallet and transaction atomically
-	if err := s.walletRepo.UpdateWithTransaction(ctx, wallet, transaction); err != nil {
-		return nil, err
-	}
-
-	return wallet, nil
+// processTransfer applies a transfer to the sender's and receiver's wallets as
+// locked for update, so concurrent changes to either are not overwritten. A
+// transfer the sender's locked balance cannot cover fails with
+// ErrInsufficientBalance.
+func (s *WalletService) processTransfer(ctx context.Context, debitTx, creditTx *models.Transaction) (*models.Wallet, *models.Wallet, error) {
+	return s.walletRepo.ProcessTransfer(ctx, debitTx.UserID, creditTx.UserID, debitTx, creditTx,
+		func(fromWallet, toWallet *models.Wallet) error {
+			if !fromWallet.CanSpend(debitTx.Amount) {
+				return ErrInsufficientBalance
+			}
+			applyTransfer(fromWallet, toWallet, debitTx, creditTx, time.Now().UTC())
+			return nil
+		})
 }
 
-func (s *WalletService) processTransfer(ctx context.Context, fromWallet, toWallet *models.Wallet, debitTx, creditTx *models.Transaction) (*models.Wallet, *models.Wallet, error) {
+// applyTransfer moves a transfer's credits from the sender's wallet to the
+// receiver's, recording the resulting balances on its transactions
+func applyTransfer(fromWallet, toWallet *models.Wallet, debitTx, creditTx *models.Transaction, now time.Time) {
 	// Update sender wallet
 	fromWallet.AvailableCredits = fromWallet.AvailableCredits.Sub(debitTx.Amount)
 	fromWallet.TotalSpent = fromWallet.TotalSpent.Add(debitTx.Amount)
-	fromWallet.LastUpdated = time.Now().UTC()
+	fromWallet.LastUpdated = now
 	debitTx.BalanceAfter = fromWallet.AvailableCredits
 	debitTx.ProcessedAt = &fromWallet.LastUpdated
 
 	// Update receiver wallet
 	toWallet.AvailableCredits = toWallet.AvailableCredits.Add(creditTx.Amount)
 	toWallet.TotalEarned = toWallet.TotalEarned.Add(creditTx.Amount)
-	toWallet.LastUpdated = time.Now().UTC()
+	toWallet.LastUpdated = now
 	creditTx.BalanceAfter = toWallet.AvailableCredits
 	creditTx.ProcessedAt = &toWallet.LastUpdated
-
-	// Save transfer atomically
-	if err := s.walletRepo.ProcessTransfer(ctx, fromWallet, toWallet, debitTx, creditTx); err != nil {
-		return nil, nil, err
-	}
-
-	return fromWallet, toWallet, nil
 }
 
 func (s *WalletService) walletToResponse(wallet *models.Wallet) *WalletResponse {
@@ -440,6 +1370,7 @@ func (s *WalletService) walletToResponse(wallet *models.Wallet) *WalletResponse
 		UserID:           wallet.UserID,
 		AvailableCredits: wallet.AvailableCredits,
 		PendingCredits:   wallet.PendingCredits,
+		ReservedCredits:  wallet.ReservedCredits,
 		TotalEarned:      wallet.TotalEarned,
 		TotalSpent:       wallet.TotalSpent,
 		LastUpdated:      wallet.LastUpdated,
@@ -459,11 +1390,40 @@ func (s *WalletService) transactionToResponse(transaction *models.Transaction) *
 		ReferenceID:  transaction.ReferenceID,
 		FromUserID:   transaction.FromUserID,
 		ToUserID:     transaction.ToUserID,
+		Metadata:     decodeMetadata(transaction.Metadata),
+		ActorID:      transaction.ActorID,
 		ProcessedAt:  transaction.ProcessedAt,
+		ReversedAt:   transaction.ReversedAt,
 		CreatedAt:    transaction.CreatedAt,
 	}
 }
 
+// encodeMetadata serializes request metadata for the transaction's jsonb column
+func encodeMetadata(metadata map[string]interface{}) (string, error) {
+	if len(metadata) == 0 {
+		return "", nil
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return "", fmt.Errorf("invalid metadata: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// decodeMetadata parses stored transaction metadata, returning nil when there is none
+func decodeMetadata(metadata string) map[string]interface{} {
+	if metadata == "" {
+		return nil
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(metadata), &decoded); err != nil || len(decoded) == 0 {
+		return nil
+	}
+	return decoded
+}
+
 // TransferResponse represents a transfer response
 type TransferResponse struct {
 	TransferID      string               `json:"transfer_id"`
@@ -1,12 +1,21 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/kafka-go"
 	"github.com/shopspring/decimal"
 	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/credits"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/monitoring"
 )
 
 func TestWalletModel_Creation(t *testing.T) {
@@ -181,3 +190,841 @@ func TestCreditReservationModel_IsActive(t *testing.T) {
 		t.Error("Expected released reservation to not be active")
 	}
 }
+
+func TestWalletService_ValidateAmount(t *testing.T) {
+	service := &WalletService{minAmount: decimal.NewFromFloat(0.01)}
+
+	if err := service.validateAmount(decimal.NewFromFloat(0.0001)); err == nil {
+		t.Error("Expected dust amount below the minimum to be rejected")
+	}
+
+	if err := service.validateAmount(decimal.NewFromFloat(-5)); err == nil {
+		t.Error("Expected negative amount to be rejected")
+	}
+
+	if err := service.validateAmount(decimal.NewFromFloat(0.01)); err != nil {
+		t.Errorf("Expected amount equal to the minimum to be accepted, got %v", err)
+	}
+}
+
+func TestWalletService_ValidateCreditAmount(t *testing.T) {
+	service := &WalletService{minAmount: decimal.NewFromFloat(0.01)}
+
+	tests := []struct {
+		name         string
+		amount       decimal.Decimal
+		systemCredit bool
+		wantErr      bool
+	}{
+		{"system credit below the minimum", decimal.NewFromFloat(0.001), true, false},
+		{"system credit of zero", decimal.Zero, true, true},
+		{"negative system credit", decimal.NewFromFloat(-1), true, true},
+		{"admin credit below the minimum", decimal.NewFromFloat(0.001), false, true},
+		{"admin credit at the minimum", decimal.NewFromFloat(0.01), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.validateCreditAmount(&CreditBalanceRequest{Amount: tt.amount, SystemCredit: tt.systemCredit})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("Expected ErrInvalidAmount, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCreditBalance_AdminCreditBelowMinimumRejected(t *testing.T) {
+	service := NewWalletService(nil, nil, nil, decimal.NewFromFloat(0.01), credits.DefaultRoundingPolicy, nil, logger.New("error"))
+
+	_, err := service.CreditBalance(context.Background(), &CreditBalanceRequest{
+		UserID: "user-1", Amount: decimal.NewFromFloat(0.001), Source: "admin", Description: "dust", ActorID: "admin-1",
+	})
+	if !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount, got %v", err)
+	}
+}
+
+func TestProcessBatch_CreditBelowMinimumRejected(t *testing.T) {
+	service := NewWalletService(nil, nil, nil, decimal.NewFromFloat(0.01), credits.DefaultRoundingPolicy, nil, logger.New("error"))
+
+	_, err := service.ProcessBatch(context.Background(), &BatchCreditRequest{
+		Operations: []BatchOperation{
+			{UserID: "user-1", Type: BatchOperationCredit, Amount: decimal.NewFromInt(5), Description: "bonus"},
+			{UserID: "user-2", Type: BatchOperationCredit, Amount: decimal.NewFromFloat(0.001), Description: "dust"},
+		},
+		Description: "batch",
+	})
+	if !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount, got %v", err)
+	}
+}
+
+func TestNewWalletService_DefaultMinAmount(t *testing.T) {
+	service := NewWalletService(nil, nil, nil, decimal.Zero, credits.RoundingPolicy{}, nil, nil)
+
+	if !service.minAmount.Equal(DefaultMinTransactionAmount) {
+		t.Errorf("Expected default minimum %s, got %s", DefaultMinTransactionAmount, service.minAmount)
+	}
+	if service.rounding != credits.DefaultRoundingPolicy {
+		t.Errorf("Expected default rounding policy, got %+v", service.rounding)
+	}
+}
+
+func TestWalletService_RecordsTransactionMetrics(t *testing.T) {
+	metrics := monitoring.NewMetrics("wallet")
+	service := NewWalletService(nil, nil, nil, decimal.Zero, credits.RoundingPolicy{}, metrics, logger.New("error"))
+	ctx := context.Background()
+
+	if _, err := service.CreditBalance(ctx, &CreditBalanceRequest{UserID: "user-1", Amount: decimal.Zero}); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("Expected ErrInvalidAmount, got %v", err)
+	}
+	if _, err := service.DebitBalance(ctx, &DebitBalanceRequest{UserID: "user-1", Amount: decimal.NewFromInt(-1)}); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("Expected ErrInvalidAmount, got %v", err)
+	}
+	if _, err := service.TransferCredits(ctx, &TransferCreditsRequest{FromUserID: "user-1", ToUserID: "user-1", Amount: decimal.NewFromInt(5)}); !errors.Is(err, ErrSelfTransfer) {
+		t.Fatalf("Expected ErrSelfTransfer, got %v", err)
+	}
+
+	for _, txType := range []string{models.TransactionTypeCreditEarned, models.TransactionTypeCreditSpent, transferMetricType} {
+		counter := metrics.TransactionsTotal.WithLabelValues("wallet", txType, models.TransactionStatusFailed)
+		if got := testutil.ToFloat64(counter); got != 1 {
+			t.Errorf("Expected 1 failed %s transaction, got %v", txType, got)
+		}
+	}
+
+	// A service without metrics must not panic
+	service.metrics = nil
+	service.recordTransaction(transferMetricType, nil)
+}
+
+func TestCheckWalletErasable(t *testing.T) {
+	if err := checkWalletErasable(nil); err != nil {
+		t.Errorf("Expected missing wallet to be erasable, got %v", err)
+	}
+
+	empty := &models.Wallet{AvailableCredits: decimal.Zero, PendingCredits: decimal.Zero}
+	if err := checkWalletErasable(empty); err != nil {
+		t.Errorf("Expected empty wallet to be erasable, got %v", err)
+	}
+
+	funded := &models.Wallet{AvailableCredits: decimal.Zero, PendingCredits: decimal.NewFromFloat(2.5)}
+	if err := checkWalletErasable(funded); !errors.Is(err, ErrBalanceNotZero) {
+		t.Errorf("Expected ErrBalanceNotZero for wallet with pending credits, got %v", err)
+	}
+}
+
+func TestReversalAmount(t *testing.T) {
+	amount, shortfall := reversalAmount(decimal.NewFromFloat(5), decimal.NewFromFloat(12))
+	if !amount.Equal(decimal.NewFromFloat(5)) || !shortfall.IsZero() {
+		t.Errorf("Expected full reversal of 5 with no shortfall, got %s and %s", amount, shortfall)
+	}
+
+	amount, shortfall = reversalAmount(decimal.NewFromFloat(5), decimal.NewFromFloat(2))
+	if !amount.Equal(decimal.NewFromFloat(2)) || !shortfall.Equal(decimal.NewFromFloat(3)) {
+		t.Errorf("Expected reversal capped at 2 with shortfall 3, got %s and %s", amount, shortfall)
+	}
+
+	amount, shortfall = reversalAmount(decimal.NewFromFloat(5), decimal.Zero)
+	if !amount.IsZero() || !shortfall.Equal(decimal.NewFromFloat(5)) {
+		t.Errorf("Expected nothing reversed from an empty wallet, got %s and %s", amount, shortfall)
+	}
+}
+
+func TestFindCreditAndReversal(t *testing.T) {
+	credit := &models.Transaction{UserID: "user-1", Type: models.TransactionTypeCreditEarned, ReferenceID: "activity-1"}
+	otherUser := &models.Transaction{UserID: "user-2", Type: models.TransactionTypeCreditReversed, ReferenceID: "activity-1"}
+
+	found, reversal := findCreditAndReversal([]*models.Transaction{otherUser, credit}, "user-1")
+	if found != credit {
+		t.Error("Expected the user's original credit to be found")
+	}
+	if reversal != nil {
+		t.Error("Expected another user's reversal to be ignored")
+	}
+
+	existing := &models.Transaction{UserID: "user-1", Type: models.TransactionTypeCreditReversed, ReferenceID: "activity-1"}
+	if _, reversal := findCreditAndReversal([]*models.Transaction{existing, credit}, "user-1"); reversal != existing {
+		t.Error("Expected the existing reversal to be returned so the reversal is not repeated")
+	}
+}
+
+func TestFindCreditAndReversal_IgnoresAdjustments(t *testing.T) {
+	credit := &models.Transaction{UserID: "user-1", Type: models.TransactionTypeCreditEarned, Source: models.CreditSourceEcoActivity, ReferenceID: "activity-1"}
+	raised := &models.Transaction{UserID: "user-1", Type: models.TransactionTypeCreditEarned, Source: models.CreditSourceAdjustment, ReferenceID: "activity-1"}
+	lowered := &models.Transaction{UserID: "user-1", Type: models.TransactionTypeCreditReversed, Source: models.CreditSourceAdjustment, ReferenceID: "activity-1"}
+
+	// Newest first, as GetByReferenceID returns them
+	found, reversal := findCreditAndReversal([]*models.Transaction{lowered, raised, credit}, "user-1")
+	if found != credit {
+		t.Error("Expected the original credit rather than an adjustment")
+	}
+	if reversal != nil {
+		t.Error("Expected a downward adjustment not to count as a reversal")
+	}
+}
+
+func TestCreditedAmount(t *testing.T) {
+	credit := &models.Transaction{UserID: "user-1", Type: models.TransactionTypeCreditEarned, Amount: decimal.NewFromFloat(2.5)}
+	transactions := []*models.Transaction{
+		{UserID: "user-1", Type: models.TransactionTypeCreditReversed, Source: models.CreditSourceAdjustment, Amount: decimal.NewFromFloat(0.75)},
+		{UserID: "user-1", Type: models.TransactionTypeCreditEarned, Source: models.CreditSourceAdjustment, Amount: decimal.NewFromFloat(1.5)},
+		{UserID: "user-2", Type: models.TransactionTypeCreditEarned, Source: models.CreditSourceAdjustment, Amount: decimal.NewFromFloat(10)},
+		credit,
+	}
+
+	if amount := creditedAmount(credit, transactions); !amount.Equal(decimal.NewFromFloat(3.25)) {
+		t.Errorf("Expected 2.5 + 1.5 - 0.75 = 3.25 credited, got %s", amount)
+	}
+	if amount := creditedAmount(credit, []*models.Transaction{credit}); !amount.Equal(decimal.NewFromFloat(2.5)) {
+		t.Errorf("Expected an unadjusted credit to be worth its amount, got %s", amount)
+	}
+}
+
+func TestFindDebit(t *testing.T) {
+	debit := &models.Transaction{UserID: "user-1", Type: models.TransactionTypeCreditSpent, ReferenceID: "certificate-1"}
+	otherUser := &models.Transaction{UserID: "user-2", Type: models.TransactionTypeCreditSpent, ReferenceID: "certificate-1"}
+
+	if found := findDebit([]*models.Transaction{otherUser, debit}, "user-1"); found != debit {
+		t.Error("Expected the user's existing debit to be found")
+	}
+	if found := findDebit([]*models.Transaction{otherUser}, "user-1"); found != nil {
+		t.Error("Expected another user's debit to be ignored")
+	}
+}
+
+func TestTransactionModel_CreditReversedIsDebit(t *testing.T) {
+	transaction := &models.Transaction{Type: models.TransactionTypeCreditReversed}
+
+	if !transaction.IsDebit() {
+		t.Error("Expected credit reversal to be a debit")
+	}
+	if transaction.IsCredit() {
+		t.Error("Expected credit reversal not to be a credit")
+	}
+}
+
+func TestSnapshotBoundaries_Monthly(t *testing.T) {
+	first := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	boundaries, err := snapshotBoundaries(first, until, SnapshotIntervalMonthly)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(boundaries) != 3 {
+		t.Fatalf("Expected 3 month ends, got %d", len(boundaries))
+	}
+	if !boundaries[0].Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected first boundary at start of February, got %v", boundaries[0])
+	}
+
+	if _, err := snapshotBoundaries(first, until, "hourly"); !errors.Is(err, ErrInvalidSnapshotInterval) {
+		t.Errorf("Expected ErrInvalidSnapshotInterval, got %v", err)
+	}
+}
+
+func TestBuildSnapshots_ReplaysLedger(t *testing.T) {
+	transactions := []*models.Transaction{
+		{Type: models.TransactionTypeCreditEarned, Amount: decimal.NewFromInt(10), CreatedAt: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{Type: models.TransactionTypeCreditSpent, Amount: decimal.NewFromInt(4), CreatedAt: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{Type: models.TransactionTypeCreditReversed, Amount: decimal.NewFromInt(1), CreatedAt: time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	boundaries := []time.Time{
+		time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	snapshots := buildSnapshots("user-1", transactions, boundaries)
+	if len(snapshots) != 2 {
+		t.Fatalf("Expected 2 snapshots, got %d", len(snapshots))
+	}
+
+	if !snapshots[0].AvailableCredits.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("Expected January closing balance 10, got %s", snapshots[0].AvailableCredits)
+	}
+	if !snapshots[0].SnapshotDate.Equal(time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)) {
+		t.Errorf("Expected January snapshot dated on the last day of January, got %v", snapshots[0].SnapshotDate)
+	}
+
+	february := snapshots[1]
+	if !february.AvailableCredits.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Expected February closing balance 5, got %s", february.AvailableCredits)
+	}
+	if !february.TotalEarned.Equal(decimal.NewFromInt(9)) {
+		t.Errorf("Expected total earned 9 after reversal, got %s", february.TotalEarned)
+	}
+	if !february.TotalSpent.Equal(decimal.NewFromInt(4)) {
+		t.Errorf("Expected total spent 4, got %s", february.TotalSpent)
+	}
+}
+
+func TestCheckRefund(t *testing.T) {
+	original := &models.Transaction{
+		ID:     uuid.New(),
+		UserID: "user-1",
+		Type:   models.TransactionTypeCreditSpent,
+		Status: models.TransactionStatusCompleted,
+		Amount: decimal.NewFromInt(10),
+	}
+	earlier := &models.Transaction{UserID: "user-1", Type: models.TransactionTypeRefund, Amount: decimal.NewFromInt(6)}
+
+	if err := checkRefund(original, []*models.Transaction{earlier}, decimal.NewFromInt(4)); err != nil {
+		t.Errorf("Expected refund of the remaining amount to be allowed, got %v", err)
+	}
+
+	if err := checkRefund(original, []*models.Transaction{earlier}, decimal.NewFromInt(5)); !errors.Is(err, ErrRefundExceedsOriginal) {
+		t.Errorf("Expected ErrRefundExceedsOriginal, got %v", err)
+	}
+
+	credit := &models.Transaction{ID: uuid.New(), Type: models.TransactionTypeCreditEarned, Status: models.TransactionStatusCompleted, Amount: decimal.NewFromInt(10)}
+	if err := checkRefund(credit, nil, decimal.NewFromInt(1)); !errors.Is(err, ErrNotRefundable) {
+		t.Errorf("Expected ErrNotRefundable for a credit, got %v", err)
+	}
+}
+
+func TestConsumerLag(t *testing.T) {
+	if lag := consumerLag(kafka.Message{Offset: 10, HighWaterMark: 15}); lag != 4 {
+		t.Errorf("Expected lag of 4, got %d", lag)
+	}
+	if lag := consumerLag(kafka.Message{Offset: 14, HighWaterMark: 15}); lag != 0 {
+		t.Errorf("Expected no lag at the end of the partition, got %d", lag)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	if backoff := retryBackoff(1); backoff != time.Second {
+		t.Errorf("Expected first retry after 1s, got %s", backoff)
+	}
+	if backoff := retryBackoff(3); backoff != 4*time.Second {
+		t.Errorf("Expected third retry after 4s, got %s", backoff)
+	}
+	if backoff := retryBackoff(50); backoff != maxRetryBackoff {
+		t.Errorf("Expected backoff to be capped at %s, got %s", maxRetryBackoff, backoff)
+	}
+}
+
+func TestIsPermanentEventError(t *testing.T) {
+	if !isPermanentEventError(fmt.Errorf("%w: must be positive", ErrInvalidAmount)) {
+		t.Error("Expected an invalid amount to be permanent")
+	}
+	if isPermanentEventError(errors.New("connection refused")) {
+		t.Error("Expected a database error to be retried")
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+	message := kafka.Message{
+		Headers: []kafka.Header{{Key: "event-type", Value: []byte("credit_earned")}},
+		Value:   []byte(`{"user_id":"user-1","activity_id":"activity-1","credits_earned":2.5}`),
+	}
+
+	event, err := decodeEvent(message)
+	if err != nil {
+		t.Fatalf("Expected credit earned event to decode, got %v", err)
+	}
+	if earned, ok := event.(*CreditEarnedEvent); !ok || earned.ActivityID != "activity-1" {
+		t.Errorf("Expected credit earned event for activity-1, got %#v", event)
+	}
+
+	message.Headers[0].Value = []byte("credit_adjusted")
+	message.Value = []byte(`{"user_id":"user-1","activity_id":"activity-1","adjustment_id":"adj-1","credits_delta":-1.25}`)
+	event, err = decodeEvent(message)
+	if err != nil {
+		t.Fatalf("Expected credit adjusted event to decode, got %v", err)
+	}
+	if adjusted, ok := event.(*CreditAdjustedEvent); !ok || adjusted.CreditsDelta != -1.25 || adjusted.AdjustmentID != "adj-1" {
+		t.Errorf("Expected credit adjusted event of -1.25, got %#v", event)
+	}
+
+	message.Headers[0].Value = []byte("unknown")
+	if _, err := decodeEvent(message); err == nil {
+		t.Error("Expected unknown event type to be rejected")
+	}
+}
+
+func TestTransactionMetadata_RoundTrip(t *testing.T) {
+	metadata := map[string]interface{}{"order_id": "order-42", "device": "kiosk-7"}
+
+	encoded, err := encodeMetadata(metadata)
+	if err != nil {
+		t.Fatalf("Expected metadata to encode, got %v", err)
+	}
+
+	service := &WalletService{}
+	response := service.transactionToResponse(&models.Transaction{ID: uuid.New(), Metadata: encoded})
+	if response.Metadata["order_id"] != "order-42" || response.Metadata["device"] != "kiosk-7" {
+		t.Errorf("Expected metadata to round-trip, got %v", response.Metadata)
+	}
+
+	if encoded, _ := encodeMetadata(nil); encoded != "" {
+		t.Errorf("Expected no metadata to encode as empty, got %q", encoded)
+	}
+	if decoded := decodeMetadata("{}"); decoded != nil {
+		t.Errorf("Expected empty metadata to be omitted, got %v", decoded)
+	}
+}
+
+func TestTransactionModel_BeforeCreateDefaultsMetadata(t *testing.T) {
+	transaction := &models.Transaction{}
+	if err := transaction.BeforeCreate(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transaction.Metadata != "{}" {
+		t.Errorf("Expected empty metadata to default to {}, got %q", transaction.Metadata)
+	}
+}
+
+func TestTransactionToResponse_IncludesActor(t *testing.T) {
+	service := &WalletService{}
+
+	response := service.transactionToResponse(&models.Transaction{ID: uuid.New(), ActorID: "admin-1"})
+	if response.ActorID != "admin-1" {
+		t.Errorf("Expected actor admin-1, got %q", response.ActorID)
+	}
+
+	if response := service.transactionToResponse(&models.Transaction{ID: uuid.New()}); response.ActorID != "" {
+		t.Errorf("Expected system transaction to have no actor, got %q", response.ActorID)
+	}
+}
+
+func TestArchiveCutoff(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if _, ok := archiveCutoff(now, 0); ok {
+		t.Error("Expected zero retention to keep every transaction")
+	}
+
+	cutoff, ok := archiveCutoff(now, 90*24*time.Hour)
+	if !ok {
+		t.Fatal("Expected a cutoff for a positive retention")
+	}
+	if want := time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC); !cutoff.Equal(want) {
+		t.Errorf("Expected cutoff %s, got %s", want, cutoff)
+	}
+}
+
+func TestOwnedTransaction(t *testing.T) {
+	transaction := &models.Transaction{ID: uuid.New(), UserID: "user-1"}
+
+	got, err := ownedTransaction(transaction, nil, "user-1")
+	if err != nil || got != transaction {
+		t.Fatalf("Expected owner to get the transaction, got %v, %v", got, err)
+	}
+
+	if _, err := ownedTransaction(nil, database.ErrNotFound, "user-1"); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("Expected ErrTransactionNotFound for a missing transaction, got %v", err)
+	}
+
+	if _, err := ownedTransaction(transaction, nil, "user-2"); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("Expected ErrTransactionNotFound for another user's transaction, got %v", err)
+	}
+
+	dbErr := errors.New("connection refused")
+	if _, err := ownedTransaction(nil, dbErr, "user-1"); !errors.Is(err, dbErr) || errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("Expected the lookup error to be passed through, got %v", err)
+	}
+}
+
+func TestMatchesIdempotentRequest(t *testing.T) {
+	transaction := &models.Transaction{
+		UserID: "user-1",
+		Type:   models.TransactionTypeCreditEarned,
+		Amount: decimal.NewFromFloat(2.5),
+	}
+
+	if !matchesIdempotentRequest(transaction, "user-1", models.TransactionTypeCreditEarned, decimal.RequireFromString("2.500")) {
+		t.Error("Expected a replay of the same credit to match")
+	}
+	if matchesIdempotentRequest(transaction, "user-2", models.TransactionTypeCreditEarned, decimal.NewFromFloat(2.5)) {
+		t.Error("Expected a different user not to match")
+	}
+	if matchesIdempotentRequest(transaction, "user-1", models.TransactionTypeCreditSpent, decimal.NewFromFloat(2.5)) {
+		t.Error("Expected a debit not to match a credit")
+	}
+	if matchesIdempotentRequest(transaction, "user-1", models.TransactionTypeCreditEarned, decimal.NewFromFloat(3)) {
+		t.Error("Expected a different amount not to match")
+	}
+}
+
+func TestReservationHolds(t *testing.T) {
+	wallet := &models.Wallet{
+		AvailableCredits: decimal.NewFromInt(100),
+		TotalSpent:       decimal.NewFromInt(10),
+	}
+
+	holdCredits(wallet, decimal.NewFromInt(30))
+	if !wallet.AvailableCredits.Equal(decimal.NewFromInt(70)) || !wallet.ReservedCredits.Equal(decimal.NewFromInt(30)) {
+		t.Fatalf("Expected 70 available and 30 reserved, got %s and %s", wallet.AvailableCredits, wallet.ReservedCredits)
+	}
+	if wallet.CanSpend(decimal.NewFromInt(80)) {
+		t.Error("Expected reserved credits not to be spendable")
+	}
+	if !wallet.TotalSpent.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("Expected reserving not to count as spent, got %s", wallet.TotalSpent)
+	}
+	if !wallet.GetTotalBalance().Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected total balance to include reserved credits, got %s", wallet.GetTotalBalance())
+	}
+
+	releaseHold(wallet, decimal.NewFromInt(10))
+	if !wallet.AvailableCredits.Equal(decimal.NewFromInt(80)) || !wallet.ReservedCredits.Equal(decimal.NewFromInt(20)) {
+		t.Fatalf("Expected 80 available and 20 reserved after release, got %s and %s", wallet.AvailableCredits, wallet.ReservedCredits)
+	}
+
+	commitHold(wallet, decimal.NewFromInt(20))
+	if !wallet.AvailableCredits.Equal(decimal.NewFromInt(80)) || !wallet.ReservedCredits.IsZero() {
+		t.Fatalf("Expected commit to leave 80 available and nothing reserved, got %s and %s", wallet.AvailableCredits, wallet.ReservedCredits)
+	}
+	if !wallet.TotalSpent.Equal(decimal.NewFromInt(30)) {
+		t.Errorf("Expected committed credits to count as spent, got %s", wallet.TotalSpent)
+	}
+}
+
+func TestApplyTransfer(t *testing.T) {
+	from := &models.Wallet{UserID: "user-1", AvailableCredits: decimal.NewFromInt(50), ReservedCredits: decimal.NewFromInt(20)}
+	to := &models.Wallet{UserID: "user-2", AvailableCredits: decimal.NewFromInt(5)}
+	debit := &models.Transaction{UserID: "user-1", Type: models.TransactionTypeTransferOut, Amount: decimal.NewFromInt(30)}
+	credit := &models.Transaction{UserID: "user-2", Type: models.TransactionTypeTransferIn, Amount: decimal.NewFromInt(30)}
+	now := time.Now().UTC()
+
+	applyTransfer(from, to, debit, credit, now)
+
+	if !from.AvailableCredits.Equal(decimal.NewFromInt(20)) || !from.TotalSpent.Equal(decimal.NewFromInt(30)) {
+		t.Errorf("Expected sender to have 20 available and 30 spent, got %s and %s", from.AvailableCredits, from.TotalSpent)
+	}
+	if !from.ReservedCredits.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Expected the sender's reservations to be kept, got %s reserved", from.ReservedCredits)
+	}
+	if !to.AvailableCredits.Equal(decimal.NewFromInt(35)) || !to.TotalEarned.Equal(decimal.NewFromInt(30)) {
+		t.Errorf("Expected receiver to have 35 available and 30 earned, got %s and %s", to.AvailableCredits, to.TotalEarned)
+	}
+	if !debit.BalanceAfter.Equal(from.AvailableCredits) || !credit.BalanceAfter.Equal(to.AvailableCredits) {
+		t.Errorf("Expected transactions to record the balances after the transfer, got %s and %s", debit.BalanceAfter, credit.BalanceAfter)
+	}
+	if debit.ProcessedAt == nil || !debit.ProcessedAt.Equal(now) || credit.ProcessedAt == nil || !credit.ProcessedAt.Equal(now) {
+		t.Error("Expected both transactions to be marked processed")
+	}
+}
+
+func TestReservationStatus(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	committedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		reservation *models.CreditReservation
+		expected    string
+	}{
+		{"active", &models.CreditReservation{ExpiresAt: now.Add(time.Hour)}, ReservationStatusActive},
+		{"expired", &models.CreditReservation{ExpiresAt: now.Add(-time.Hour)}, ReservationStatusExpired},
+		{"released", &models.CreditReservation{ExpiresAt: now.Add(time.Hour), IsReleased: true}, ReservationStatusReleased},
+		{"committed after expiry", &models.CreditReservation{ExpiresAt: now.Add(-time.Hour), CommittedAt: &committedAt}, ReservationStatusCommitted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reservationStatus(tt.reservation, now); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckWalletErasable_ReservedCredits(t *testing.T) {
+	held := &models.Wallet{ReservedCredits: decimal.NewFromInt(5)}
+	if err := checkWalletErasable(held); !errors.Is(err, ErrBalanceNotZero) {
+		t.Errorf("Expected ErrBalanceNotZero for wallet with reserved credits, got %v", err)
+	}
+}
+
+func TestPlanBatch_AppliesOperationsInOrder(t *testing.T) {
+	wallets := map[string]*models.Wallet{
+		"user-1": {UserID: "user-1", AvailableCredits: decimal.NewFromInt(10)},
+	}
+	operations := []BatchOperation{
+		{UserID: "user-1", Type: BatchOperationCredit, Amount: decimal.NewFromInt(5), Description: "bonus"},
+		{UserID: "user-1", Type: BatchOperationDebit, Amount: decimal.NewFromInt(15), Description: "spend"},
+		{UserID: "user-2", Type: BatchOperationCredit, Amount: decimal.NewFromInt(3), Description: "first credit"},
+	}
+
+	updated, transactions, err := planBatch(wallets, "batch-1", operations, "admin-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(updated) != 2 || len(transactions) != 3 {
+		t.Fatalf("Expected 2 wallets and 3 transactions, got %d and %d", len(updated), len(transactions))
+	}
+	if !updated[0].AvailableCredits.IsZero() {
+		t.Errorf("Expected the debit to use the credit before it, got balance %s", updated[0].AvailableCredits)
+	}
+	if updated[1].UserID != "user-2" || !updated[1].AvailableCredits.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("Expected a new wallet for user-2 with 3 credits, got %+v", updated[1])
+	}
+	for _, transaction := range transactions {
+		if transaction.ReferenceID != "batch-1" || transaction.ActorID != "admin-1" {
+			t.Errorf("Expected transactions to reference the batch and actor, got %q and %q", transaction.ReferenceID, transaction.ActorID)
+		}
+	}
+	if !wallets["user-1"].AvailableCredits.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("Expected the locked wallet to be left untouched, got %s", wallets["user-1"].AvailableCredits)
+	}
+}
+
+func TestPlanBatch_FailsWholeBatchOnInsufficientBalance(t *testing.T) {
+	wallets := map[string]*models.Wallet{
+		"user-1": {UserID: "user-1", AvailableCredits: decimal.NewFromInt(10)},
+		"user-2": {UserID: "user-2", AvailableCredits: decimal.NewFromInt(1)},
+	}
+	operations := []BatchOperation{
+		{UserID: "user-1", Type: BatchOperationDebit, Amount: decimal.NewFromInt(4), Description: "spend"},
+		{UserID: "user-2", Type: BatchOperationDebit, Amount: decimal.NewFromInt(2), Description: "overspend"},
+	}
+
+	updated, transactions, err := planBatch(wallets, "batch-1", operations, "")
+	if !errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("Expected ErrInsufficientBalance, got %v", err)
+	}
+	if updated != nil || transactions != nil {
+		t.Error("Expected nothing to be saved for a failed batch")
+	}
+	if !wallets["user-1"].AvailableCredits.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("Expected the earlier debit not to touch the wallet, got %s", wallets["user-1"].AvailableCredits)
+	}
+}
+
+func TestBatchUserIDs(t *testing.T) {
+	userIDs := batchUserIDs([]BatchOperation{{UserID: "b"}, {UserID: "a"}, {UserID: "b"}})
+	if len(userIDs) != 2 || userIDs[0] != "b" || userIDs[1] != "a" {
+		t.Errorf("Expected [b a], got %v", userIDs)
+	}
+}
+
+func TestReversalType(t *testing.T) {
+	reversedAt := time.Now()
+
+	tests := []struct {
+		name        string
+		transaction *models.Transaction
+		expected    string
+		err         error
+	}{
+		{"credit", &models.Transaction{Type: models.TransactionTypeCreditEarned, Status: models.TransactionStatusCompleted}, models.TransactionTypeCreditReversed, nil},
+		{"spend", &models.Transaction{Type: models.TransactionTypeCreditSpent, Status: models.TransactionStatusCompleted}, models.TransactionTypeDebitReversed, nil},
+		{"penalty", &models.Transaction{Type: models.TransactionTypePenalty, Status: models.TransactionStatusCompleted}, models.TransactionTypeDebitReversed, nil},
+		{"already reversed", &models.Transaction{Type: models.TransactionTypeCreditEarned, Status: models.TransactionStatusCompleted, ReversedAt: &reversedAt}, "", ErrAlreadyReversed},
+		{"transfer out", &models.Transaction{Type: models.TransactionTypeTransferOut, Status: models.TransactionStatusCompleted}, "", ErrNotReversible},
+		{"transfer in", &models.Transaction{Type: models.TransactionTypeTransferIn, Status: models.TransactionStatusCompleted}, "", ErrNotReversible},
+		{"reversal", &models.Transaction{Type: models.TransactionTypeDebitReversed, Status: models.TransactionStatusCompleted}, "", ErrNotReversible},
+		{"pending", &models.Transaction{Type: models.TransactionTypeCreditSpent, Status: models.TransactionStatusPending}, "", ErrNotReversible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reversalType(tt.transaction)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Expected %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil || got != tt.expected {
+				t.Errorf("Expected %s, got %s (%v)", tt.expected, got, err)
+			}
+		})
+	}
+}
+
+func TestCheckReversalRelated(t *testing.T) {
+	credit := &models.Transaction{ID: uuid.New(), UserID: "user-1", Type: models.TransactionTypeCreditEarned, ReferenceID: "activity-1"}
+	activityReversal := &models.Transaction{UserID: "user-1", Type: models.TransactionTypeCreditReversed, ReferenceID: "activity-1"}
+	if err := checkReversalRelated(credit, []*models.Transaction{credit, activityReversal}); !errors.Is(err, ErrAlreadyReversed) {
+		t.Errorf("Expected a credit reversed by its activity to count as reversed, got %v", err)
+	}
+
+	spend := &models.Transaction{ID: uuid.New(), UserID: "user-1", Type: models.TransactionTypeCreditSpent}
+	refund := &models.Transaction{UserID: "user-1", Type: models.TransactionTypeRefund, ReferenceID: spend.ID.String()}
+	if err := checkReversalRelated(spend, []*models.Transaction{refund}); !errors.Is(err, ErrNotReversible) {
+		t.Errorf("Expected a refunded spend not to be reversible, got %v", err)
+	}
+
+	if err := checkReversalRelated(spend, nil); err != nil {
+		t.Errorf("Expected an untouched spend to be reversible, got %v", err)
+	}
+}
+
+func TestApplyTransaction_DebitReversed(t *testing.T) {
+	wallet := &models.Wallet{AvailableCredits: decimal.NewFromInt(5), TotalSpent: decimal.NewFromInt(20)}
+
+	applyTransaction(wallet, &models.Transaction{Type: models.TransactionTypeDebitReversed, Amount: decimal.NewFromInt(8)})
+
+	if !wallet.AvailableCredits.Equal(decimal.NewFromInt(13)) || !wallet.TotalSpent.Equal(decimal.NewFromInt(12)) {
+		t.Errorf("Expected 13 available and 12 spent, got %s and %s", wallet.AvailableCredits, wallet.TotalSpent)
+	}
+	if !wallet.TotalEarned.IsZero() {
+		t.Errorf("Expected a debit reversal not to count as earned, got %s", wallet.TotalEarned)
+	}
+}
+
+func TestCheckRefund_ReversedSpend(t *testing.T) {
+	reversedAt := time.Now()
+	spend := &models.Transaction{ID: uuid.New(), Type: models.TransactionTypeCreditSpent, Status: models.TransactionStatusCompleted, Amount: decimal.NewFromInt(5), ReversedAt: &reversedAt}
+	if err := checkRefund(spend, nil, decimal.NewFromInt(1)); !errors.Is(err, ErrNotRefundable) {
+		t.Errorf("Expected a reversed spend not to be refundable, got %v", err)
+	}
+}
+
+func TestReplaySinceSnapshot(t *testing.T) {
+	snapshot := &models.WalletSnapshot{
+		UserID:           "user-1",
+		AvailableCredits: decimal.NewFromInt(6),
+		ReservedCredits:  decimal.NewFromInt(4),
+		TotalEarned:      decimal.NewFromInt(12),
+		TotalSpent:       decimal.NewFromInt(2),
+		SnapshotDate:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	transactions := []*models.Transaction{
+		{Type: models.TransactionTypeCreditEarned, Amount: decimal.NewFromInt(5)},
+		// Commit of the reservation held at snapshot time
+		{Type: models.TransactionTypeCreditSpent, Amount: decimal.NewFromInt(4)},
+	}
+
+	wallet := replaySinceSnapshot("user-1", snapshot, transactions)
+	if !wallet.AvailableCredits.Equal(decimal.NewFromInt(11)) {
+		t.Errorf("Expected available credits 11, got %s", wallet.AvailableCredits)
+	}
+	if !wallet.TotalEarned.Equal(decimal.NewFromInt(17)) {
+		t.Errorf("Expected total earned 17, got %s", wallet.TotalEarned)
+	}
+	if !wallet.TotalSpent.Equal(decimal.NewFromInt(6)) {
+		t.Errorf("Expected total spent 6, got %s", wallet.TotalSpent)
+	}
+}
+
+func TestReplaySinceSnapshot_WithoutSnapshot(t *testing.T) {
+	transactions := []*models.Transaction{
+		{Type: models.TransactionTypeCreditEarned, Amount: decimal.NewFromInt(3)},
+	}
+
+	wallet := replaySinceSnapshot("user-1", nil, transactions)
+	if !wallet.AvailableCredits.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("Expected available credits 3, got %s", wallet.AvailableCredits)
+	}
+	if !wallet.TotalSpent.IsZero() {
+		t.Errorf("Expected no spending, got %s", wallet.TotalSpent)
+	}
+}
+
+func TestTransactionCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC)
+	id := uuid.New()
+
+	gotCreatedAt, gotID, err := decodeTransactionCursor(encodeTransactionCursor(createdAt, id))
+	if err != nil {
+		t.Fatalf("Expected cursor to decode, got %v", err)
+	}
+	if !gotCreatedAt.Equal(createdAt) {
+		t.Errorf("Expected created_at %v, got %v", createdAt, gotCreatedAt)
+	}
+	if gotID != id {
+		t.Errorf("Expected id %s, got %s", id, gotID)
+	}
+}
+
+func TestDecodeTransactionCursor_Invalid(t *testing.T) {
+	for _, cursor := range []string{"not base64!", "bm8tc2VwYXJhdG9y", encodeTransactionCursor(time.Now(), uuid.Nil)[:10]} {
+		if _, _, err := decodeTransactionCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor for %q, got %v", cursor, err)
+		}
+	}
+}
+
+func TestCursorPage(t *testing.T) {
+	transactions := []*models.Transaction{
+		{ID: uuid.New(), CreatedAt: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{ID: uuid.New(), CreatedAt: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{ID: uuid.New(), CreatedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	page, next := cursorPage(transactions, 2)
+	if len(page) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d", len(page))
+	}
+	createdAt, id, err := decodeTransactionCursor(next)
+	if err != nil {
+		t.Fatalf("Expected a next cursor, got %v", err)
+	}
+	if id != transactions[1].ID || !createdAt.Equal(transactions[1].CreatedAt) {
+		t.Errorf("Expected next cursor to point at the last transaction on the page")
+	}
+
+	if page, next := cursorPage(transactions, 3); len(page) != 3 || next != "" {
+		t.Errorf("Expected last page without next cursor, got %d transactions and %q", len(page), next)
+	}
+}
+
+func TestValidateTransactionFilter(t *testing.T) {
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 4, 30, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filter  TransactionFilter
+		wantErr bool
+	}{
+		{"empty filter", TransactionFilter{}, false},
+		{"combined filters", TransactionFilter{Type: models.TransactionTypeTransferIn, Source: models.CreditSourceTransfer, StartDate: &start, EndDate: &end}, false},
+		{"open-ended range", TransactionFilter{StartDate: &start}, false},
+		{"single instant", TransactionFilter{StartDate: &start, EndDate: &start}, false},
+		{"unknown type", TransactionFilter{Type: "gift"}, true},
+		{"inverted range", TransactionFilter{Type: models.TransactionTypeBonus, StartDate: &end, EndDate: &start}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTransactionFilter(tt.filter)
+			if tt.wantErr && !errors.Is(err, ErrInvalidTransactionFilter) {
+				t.Errorf("Expected ErrInvalidTransactionFilter, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestIsTransactionType(t *testing.T) {
+	for _, transactionType := range []string{models.TransactionTypeTransferIn, models.TransactionTypeDebitReversed} {
+		if !models.IsTransactionType(transactionType) {
+			t.Errorf("Expected %s to be a known transaction type", transactionType)
+		}
+	}
+	if models.IsTransactionType("TRANSFER_IN") {
+		t.Error("Expected transaction types to be case-sensitive")
+	}
+}
+
+func TestRankTopUsers(t *testing.T) {
+	wallets := []*models.Wallet{
+		{UserID: "user-a", TotalEarned: decimal.NewFromInt(50), TotalSpent: decimal.NewFromInt(5)},
+		{UserID: "user-b", TotalEarned: decimal.NewFromInt(30)},
+	}
+
+	ranking := rankTopUsers(wallets)
+	if len(ranking) != 2 {
+		t.Fatalf("Expected 2 ranked users, got %d", len(ranking))
+	}
+	if ranking[0].Rank != 1 || ranking[0].UserID != "user-a" || !ranking[0].TotalEarned.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("Expected user-a ranked first with 50 earned, got %+v", ranking[0])
+	}
+	if ranking[1].Rank != 2 || ranking[1].UserID != "user-b" {
+		t.Errorf("Expected user-b ranked second, got %+v", ranking[1])
+	}
+
+	if empty := rankTopUsers(nil); empty == nil || len(empty) != 0 {
+		t.Errorf("Expected an empty, non-nil ranking, got %v", empty)
+	}
+}
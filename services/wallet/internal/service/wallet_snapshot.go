@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sloweyyy/GreenLedger/services/wallet/internal/models"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// snapshotBatchSize caps the wallets snapshotted per query
+const snapshotBatchSize = 500
+
+// BalanceAtResponse represents a wallet balance reconstructed for a point in time
+type BalanceAtResponse struct {
+	UserID           string          `json:"user_id"`
+	At               time.Time       `json:"at"`
+	AvailableCredits decimal.Decimal `json:"available_credits"`
+	TotalEarned      decimal.Decimal `json:"total_earned"`
+	TotalSpent       decimal.Decimal `json:"total_spent"`
+	// SnapshotDate is the snapshot the balance was rebuilt from, if any
+	SnapshotDate        *time.Time `json:"snapshot_date,omitempty"`
+	TransactionsApplied int        `json:"transactions_applied"`
+}
+
+// TakeSnapshots records the current balances of every wallet updated at or
+// after since and returns how many snapshots were written
+func (s *WalletService) TakeSnapshots(ctx context.Context, now, since time.Time) (int, error) {
+	written := 0
+	afterUserID := ""
+	for {
+		wallets, err := s.walletRepo.GetUpdatedSince(ctx, since, afterUserID, snapshotBatchSize)
+		if err != nil {
+			return written, err
+		}
+
+		snapshots := make([]*models.WalletSnapshot, len(wallets))
+		for i, wallet := range wallets {
+			snapshots[i] = liveSnapshot(wallet, now)
+		}
+		if err := s.walletRepo.CreateSnapshots(ctx, snapshots); err != nil {
+			return written, err
+		}
+		written += len(snapshots)
+
+		if len(wallets) < snapshotBatchSize {
+			return written, nil
+		}
+		afterUserID = wallets[len(wallets)-1].UserID
+	}
+}
+
+// GetBalanceAt reconstructs the user's balance at a point in time from the
+// closest snapshot at or before it and the completed transactions since.
+// Credits held by reservations at the time count as available, because holds
+// are not part of the ledger. Users without history have a zero balance.
+func (s *WalletService) GetBalanceAt(ctx context.Context, userID string, at time.Time) (*BalanceAtResponse, error) {
+	at = at.UTC()
+
+	snapshot, err := s.walletRepo.GetSnapshotAt(ctx, userID, at)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	var from time.Time
+	if snapshot != nil {
+		from = snapshot.SnapshotDate
+	}
+
+	transactions, err := s.transactionRepo.GetCompletedByUserIDBetween(ctx, userID, from, at)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet := replaySinceSnapshot(userID, snapshot, transactions)
+
+	response := &BalanceAtResponse{
+		UserID:              userID,
+		At:                  at,
+		AvailableCredits:    wallet.AvailableCredits,
+		TotalEarned:         wallet.TotalEarned,
+		TotalSpent:          wallet.TotalSpent,
+		TransactionsApplied: len(transactions),
+	}
+	if snapshot != nil {
+		response.SnapshotDate = &snapshot.SnapshotDate
+	}
+
+	return response, nil
+}
+
+// liveSnapshot captures a wallet's balances at now
+func liveSnapshot(wallet *models.Wallet, now time.Time) *models.WalletSnapshot {
+	return &models.WalletSnapshot{
+		UserID:           wallet.UserID,
+		AvailableCredits: wallet.AvailableCredits,
+		PendingCredits:   wallet.PendingCredits,
+		ReservedCredits:  wallet.ReservedCredits,
+		TotalEarned:      wallet.TotalEarned,
+		TotalSpent:       wallet.TotalSpent,
+		SnapshotDate:     now,
+	}
+}
+
+// replaySinceSnapshot applies transactions, oldest first, on top of snapshot,
+// or on top of an empty wallet when there is none. Reserved credits are folded
+// back into the available balance so the snapshot matches the ledger, which
+// only records a hold once it is committed as a spend.
+func replaySinceSnapshot(userID string, snapshot *models.WalletSnapshot, transactions []*models.Transaction) *models.Wallet {
+	wallet := &models.Wallet{UserID: userID}
+	if snapshot != nil {
+		wallet.AvailableCredits = snapshot.AvailableCredits.Add(snapshot.ReservedCredits)
+		wallet.TotalEarned = snapshot.TotalEarned
+		wallet.TotalSpent = snapshot.TotalSpent
+	}
+
+	for _, transaction := range transactions {
+		applyTransaction(wallet, transaction)
+	}
+
+	return wallet
+}
+
+// WalletSnapshotter periodically snapshots the balances of wallets that
+// changed since its previous run, so historical balances can be rebuilt
+// without replaying the whole ledger
+type WalletSnapshotter struct {
+	walletService *WalletService
+	logger        *logger.Logger
+}
+
+// NewWalletSnapshotter creates a new wallet snapshotter
+func NewWalletSnapshotter(walletService *WalletService, logger *logger.Logger) *WalletSnapshotter {
+	return &WalletSnapshotter{
+		walletService: walletService,
+		logger:        logger,
+	}
+}
+
+// Run snapshots once immediately and then every interval until ctx is
+// cancelled. Each run covers the wallets updated during the interval before it.
+func (sn *WalletSnapshotter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now().UTC()
+		written, err := sn.walletService.TakeSnapshots(ctx, now, now.Add(-interval))
+		if err != nil && ctx.Err() == nil {
+			sn.logger.LogError(ctx, "wallet snapshot failed", err)
+		}
+		if written > 0 {
+			sn.logger.LogInfo(ctx, "wallet snapshots taken",
+				logger.Int("count", written))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
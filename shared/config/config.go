@@ -2,8 +2,15 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/shared/credits"
+	"github.com/sloweyyy/GreenLedger/shared/featureflags"
 )
 
 // DatabaseConfig holds database configuration
@@ -30,7 +37,16 @@ type ServerConfig struct {
 	GRPCPort    int
 	Environment string
 	JWTSecret   string
-	LogLevel    string
+	// JWTIssuer and JWTAudience are set on issued tokens and required on
+	// every token a service accepts, so tokens from another deployment are
+	// rejected
+	JWTIssuer            string
+	JWTAudience          string
+	LogLevel             string
+	SlowRequestThreshold time.Duration
+	TrustedProxies       []string
+	// MaxBodyBytes caps the size of request bodies; larger requests get 413
+	MaxBodyBytes int64
 }
 
 // KafkaConfig holds Kafka configuration
@@ -39,12 +55,153 @@ type KafkaConfig struct {
 	GroupID string
 }
 
+// WalletConfig holds wallet service configuration
+type WalletConfig struct {
+	MinTransactionAmount float64
+
+	// TransactionRetention is how long transactions stay in the live table
+	// before they are archived; zero keeps everything live
+	TransactionRetention time.Duration
+	// ArchiveInterval is how often the archival job runs
+	ArchiveInterval time.Duration
+	// ReservationSweepInterval is how often expired credit reservations are
+	// released back to available balances
+	ReservationSweepInterval time.Duration
+	// SnapshotInterval is how often balances of recently updated wallets are
+	// snapshotted for historical balance queries; zero disables snapshots
+	SnapshotInterval time.Duration
+}
+
+// CalculatorConfig holds calculator service configuration
+type CalculatorConfig struct {
+	// FactorCacheTTL is how long emission factors are served from memory
+	// before they are reloaded; zero disables the cache
+	FactorCacheTTL time.Duration
+}
+
+// ReportingConfig holds reporting service configuration
+type ReportingConfig struct {
+	MaxDateRange time.Duration
+	// QueryTimeout bounds each report data collection; zero disables it
+	QueryTimeout time.Duration
+	// Storage is where generated report files are kept: "local" or "s3"
+	Storage string
+	// StoragePath is the directory report files are written to with local storage
+	StoragePath string
+	// S3 settings used with s3 storage; S3Endpoint is only needed for
+	// S3-compatible stores such as MinIO
+	S3Bucket          string
+	S3Region          string
+	S3Endpoint        string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3SessionToken    string
+	// DataSource is how report data is collected: "database" reads each
+	// service's database directly, "grpc" calls the services' gRPC APIs
+	DataSource string
+	// gRPC addresses of the services used with the grpc data source
+	CalculatorGRPCAddr string
+	TrackerGRPCAddr    string
+	WalletGRPCAddr     string
+}
+
+// TrackerConfig holds tracker service configuration
+type TrackerConfig struct {
+	// UserActivitySources are the sources a user may set when logging an
+	// activity through the authenticated API; iot and webhook are reserved
+	// for device and webhook routes
+	UserActivitySources []string
+
+	// MaxBackdating is how long after an activity occurred it may be logged
+	// and still earn credits; zero means no limit
+	MaxBackdating time.Duration
+
+	// WebhookSecrets maps each enabled webhook provider to the secret its
+	// payloads are signed with; providers without a secret are disabled
+	WebhookSecrets map[string]string
+	// WebhookTolerance is how far a webhook's signed timestamp may be from
+	// the current time before the delivery is rejected as a replay
+	WebhookTolerance time.Duration
+}
+
+// CreditsConfig holds the rounding policy shared by the tracker and wallet
+type CreditsConfig struct {
+	RoundingPlaces int
+	RoundingMode   string
+}
+
+// CertifierConfig holds certifier service configuration
+type CertifierConfig struct {
+	// WalletGRPCAddr is the wallet service's gRPC address, used to debit the
+	// credits certificates are issued for
+	WalletGRPCAddr string
+	// Minter is how issued certificates are minted as NFTs: "none", "mock"
+	// or "ethereum"
+	Minter string
+	// MintInterval is how often queued mints are submitted and submitted
+	// mints are checked
+	MintInterval time.Duration
+	// ExpiryInterval is how often certificates past their expiry are moved
+	// to the expired status
+	ExpiryInterval time.Duration
+	// PendingInterval is how often certificates left pending by a failed
+	// issuance are resumed or rolled back
+	PendingInterval time.Duration
+	// EthereumRPCURL is the JSON-RPC endpoint of a node holding the unlocked
+	// MintFromAddress account
+	EthereumRPCURL string
+	// BlockchainNetwork names the chain certificates are minted on
+	BlockchainNetwork string
+	// MintContractAddress is the ERC-721 certificate contract
+	MintContractAddress string
+	// MintFromAddress sends mint transactions and holds the minted tokens
+	MintFromAddress string
+	// IPFSAPIURL is the IPFS node certificate metadata is added to
+	IPFSAPIURL string
+	// PublicBaseURL is the externally reachable base of the certifier API,
+	// used to build the verification links encoded in certificate QR codes
+	PublicBaseURL string
+}
+
+// AuthConfig holds user-auth service configuration
+type AuthConfig struct {
+	// MaxFailedLogins is how many consecutive wrong passwords lock an account
+	MaxFailedLogins int
+	// LockoutDuration is how long a locked account refuses logins
+	LockoutDuration time.Duration
+	// WalletGRPCAddr and CertifierGRPCAddr are the gRPC addresses of the
+	// services asked whether a user still holds credits or certificates
+	// before their account is erased
+	WalletGRPCAddr    string
+	CertifierGRPCAddr string
+}
+
+// PaginationConfig holds list endpoint paging limits and the batch size cap
+type PaginationConfig struct {
+	DefaultLimit int
+	MaxLimit     int
+	// MaxBatchItems caps the number of items accepted in one batch request
+	MaxBatchItems int
+}
+
 // Config holds all configuration
 type Config struct {
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Server   ServerConfig
-	Kafka    KafkaConfig
+	Database   DatabaseConfig
+	Redis      RedisConfig
+	Server     ServerConfig
+	Kafka      KafkaConfig
+	Wallet     WalletConfig
+	Tracker    TrackerConfig
+	Calculator CalculatorConfig
+	Reporting  ReportingConfig
+	Certifier  CertifierConfig
+	Auth       AuthConfig
+	Pagination PaginationConfig
+	Credits    CreditsConfig
+
+	// Features gates optional subsystems such as Kafka; defaults depend on
+	// Server.Environment and FEATURE_<NAME> variables override them
+	Features *featureflags.Flags
 }
 
 // LoadConfig loads configuration from environment variables
@@ -65,21 +222,212 @@ func LoadConfig() (*Config, error) {
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
 		Server: ServerConfig{
-			Port:        getEnvAsInt("SERVER_PORT", 8080),
-			GRPCPort:    getEnvAsInt("GRPC_PORT", 9090),
-			Environment: getEnv("ENVIRONMENT", "development"),
-			JWTSecret:   getEnv("JWT_SECRET", "your-secret-key"),
-			LogLevel:    getEnv("LOG_LEVEL", "info"),
+			Port:                 getEnvAsInt("SERVER_PORT", 8080),
+			GRPCPort:             getEnvAsInt("GRPC_PORT", 9090),
+			Environment:          getEnv("ENVIRONMENT", "development"),
+			JWTSecret:            getEnv("JWT_SECRET", "your-secret-key"),
+			JWTIssuer:            getEnv("JWT_ISSUER", "greenledger-auth"),
+			LogLevel:             getEnv("LOG_LEVEL", "info"),
+			SlowRequestThreshold: getEnvAsDuration("SLOW_REQUEST_THRESHOLD", time.Second),
+			TrustedProxies:       getEnvAsSlice("TRUSTED_PROXIES", nil),
+			MaxBodyBytes:         int64(getEnvAsInt("MAX_REQUEST_BODY_BYTES", 1<<20)),
 		},
 		Kafka: KafkaConfig{
 			Brokers: []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
 			GroupID: getEnv("KAFKA_GROUP_ID", "greenledger"),
 		},
+		Wallet: WalletConfig{
+			MinTransactionAmount: getEnvAsFloat("WALLET_MIN_TRANSACTION_AMOUNT", 0.01),
+			TransactionRetention: getEnvAsDuration("WALLET_TRANSACTION_RETENTION", 0),
+			ArchiveInterval:      getEnvAsDuration("WALLET_ARCHIVE_INTERVAL", 24*time.Hour),
+
+			ReservationSweepInterval: getEnvAsDuration("WALLET_RESERVATION_SWEEP_INTERVAL", time.Minute),
+			SnapshotInterval:         getEnvAsDuration("WALLET_SNAPSHOT_INTERVAL", 24*time.Hour),
+		},
+		Tracker: TrackerConfig{
+			UserActivitySources: getEnvAsSlice("TRACKER_USER_ACTIVITY_SOURCES", []string{"manual"}),
+			MaxBackdating:       getEnvAsDuration("TRACKER_MAX_BACKDATING", 0),
+			WebhookSecrets:      getEnvAsMap("TRACKER_WEBHOOK_SECRETS"),
+			WebhookTolerance:    getEnvAsDuration("TRACKER_WEBHOOK_TOLERANCE", 5*time.Minute),
+		},
+		Calculator: CalculatorConfig{
+			FactorCacheTTL: getEnvAsDuration("CALCULATOR_FACTOR_CACHE_TTL", 5*time.Minute),
+		},
+		Reporting: ReportingConfig{
+			MaxDateRange: getEnvAsDuration("REPORTING_MAX_DATE_RANGE", 365*24*time.Hour),
+			QueryTimeout: getEnvAsDuration("REPORTING_QUERY_TIMEOUT", 10*time.Second),
+			Storage:      getEnv("REPORTING_STORAGE", "local"),
+			StoragePath:  getEnv("REPORTING_STORAGE_PATH", "./data/reports"),
+
+			S3Bucket:          getEnv("REPORTING_S3_BUCKET", ""),
+			S3Region:          getEnv("REPORTING_S3_REGION", getEnv("AWS_REGION", "")),
+			S3Endpoint:        getEnv("REPORTING_S3_ENDPOINT", ""),
+			S3AccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			S3SessionToken:    getEnv("AWS_SESSION_TOKEN", ""),
+
+			DataSource:         getEnv("REPORTING_DATA_SOURCE", "database"),
+			CalculatorGRPCAddr: getEnv("REPORTING_CALCULATOR_GRPC_ADDR", "localhost:9081"),
+			TrackerGRPCAddr:    getEnv("REPORTING_TRACKER_GRPC_ADDR", "localhost:9082"),
+			WalletGRPCAddr:     getEnv("REPORTING_WALLET_GRPC_ADDR", "localhost:9083"),
+		},
+		Certifier: CertifierConfig{
+			WalletGRPCAddr:      getEnv("CERTIFIER_WALLET_GRPC_ADDR", "localhost:9083"),
+			Minter:              getEnv("CERTIFIER_MINTER", "none"),
+			MintInterval:        getEnvAsDuration("CERTIFIER_MINT_INTERVAL", 30*time.Second),
+			ExpiryInterval:      getEnvAsDuration("CERTIFIER_EXPIRY_INTERVAL", time.Hour),
+			PendingInterval:     getEnvAsDuration("CERTIFIER_PENDING_INTERVAL", 5*time.Minute),
+			EthereumRPCURL:      getEnv("CERTIFIER_ETHEREUM_RPC_URL", ""),
+			BlockchainNetwork:   getEnv("CERTIFIER_BLOCKCHAIN_NETWORK", "celo-alfajores"),
+			MintContractAddress: getEnv("CERTIFIER_MINT_CONTRACT_ADDRESS", ""),
+			MintFromAddress:     getEnv("CERTIFIER_MINT_FROM_ADDRESS", ""),
+			IPFSAPIURL:          getEnv("CERTIFIER_IPFS_API_URL", "http://localhost:5001"),
+			PublicBaseURL:       getEnv("CERTIFIER_PUBLIC_BASE_URL", "http://localhost:8080/api/v1"),
+		},
+		Auth: AuthConfig{
+			MaxFailedLogins:   getEnvAsInt("AUTH_MAX_FAILED_LOGINS", 5),
+			LockoutDuration:   getEnvAsDuration("AUTH_LOCKOUT_DURATION", 15*time.Minute),
+			WalletGRPCAddr:    getEnv("AUTH_WALLET_GRPC_ADDR", "localhost:9083"),
+			CertifierGRPCAddr: getEnv("AUTH_CERTIFIER_GRPC_ADDR", "localhost:9086"),
+		},
+		Pagination: PaginationConfig{
+			DefaultLimit:  getEnvAsInt("PAGINATION_DEFAULT_LIMIT", 20),
+			MaxLimit:      getEnvAsInt("PAGINATION_MAX_LIMIT", 200),
+			MaxBatchItems: getEnvAsInt("MAX_BATCH_ITEMS", 500),
+		},
+		Credits: CreditsConfig{
+			RoundingPlaces: getEnvAsInt("CREDIT_ROUNDING_PLACES", credits.MaxPlaces),
+			RoundingMode:   getEnv("CREDIT_ROUNDING_MODE", string(credits.RoundHalfUp)),
+		},
+	}
+
+	// Tokens are only accepted in the environment they were issued for
+	// unless an audience is configured explicitly
+	config.Server.JWTAudience = getEnv("JWT_AUDIENCE", "greenledger-"+config.Server.Environment)
+
+	features, err := featureflags.Load(config.Server.Environment, os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	config.Features = features
+
+	if config.Server.JWTIssuer == "" || config.Server.JWTAudience == "" {
+		return nil, fmt.Errorf("JWT issuer and audience must not be empty")
+	}
+
+	if err := validateTrustedProxies(config.Server.TrustedProxies); err != nil {
+		return nil, err
+	}
+
+	if config.Server.MaxBodyBytes <= 0 {
+		return nil, fmt.Errorf("max request body bytes must be positive")
+	}
+
+	if err := validatePagination(config.Pagination); err != nil {
+		return nil, err
+	}
+
+	if config.Tracker.MaxBackdating < 0 {
+		return nil, fmt.Errorf("tracker max backdating must not be negative")
+	}
+
+	if config.Tracker.WebhookTolerance <= 0 {
+		return nil, fmt.Errorf("tracker webhook tolerance must be positive")
+	}
+
+	if config.Reporting.QueryTimeout < 0 {
+		return nil, fmt.Errorf("reporting query timeout must not be negative")
+	}
+	if config.Reporting.Storage != "local" && config.Reporting.Storage != "s3" {
+		return nil, fmt.Errorf("reporting storage must be local or s3, got %q", config.Reporting.Storage)
+	}
+	if config.Reporting.DataSource != "database" && config.Reporting.DataSource != "grpc" {
+		return nil, fmt.Errorf("reporting data source must be database or grpc, got %q", config.Reporting.DataSource)
+	}
+
+	switch config.Certifier.Minter {
+	case "none", "mock", "ethereum":
+	default:
+		return nil, fmt.Errorf("certifier minter must be none, mock or ethereum, got %q", config.Certifier.Minter)
+	}
+	if config.Certifier.MintInterval <= 0 {
+		return nil, fmt.Errorf("certifier mint interval must be positive")
+	}
+	if config.Certifier.ExpiryInterval <= 0 {
+		return nil, fmt.Errorf("certifier expiry interval must be positive")
+	}
+	if config.Certifier.PendingInterval <= 0 {
+		return nil, fmt.Errorf("certifier pending interval must be positive")
+	}
+	if publicURL, err := url.Parse(config.Certifier.PublicBaseURL); err != nil ||
+		(publicURL.Scheme != "http" && publicURL.Scheme != "https") || publicURL.Host == "" {
+		return nil, fmt.Errorf("certifier public base URL must be an absolute http or https URL, got %q", config.Certifier.PublicBaseURL)
+	}
+
+	if config.Wallet.TransactionRetention < 0 {
+		return nil, fmt.Errorf("wallet transaction retention must not be negative")
+	}
+	if config.Wallet.TransactionRetention > 0 && config.Wallet.ArchiveInterval <= 0 {
+		return nil, fmt.Errorf("wallet archive interval must be positive")
+	}
+	if config.Wallet.ReservationSweepInterval <= 0 {
+		return nil, fmt.Errorf("wallet reservation sweep interval must be positive")
+	}
+	if config.Wallet.SnapshotInterval < 0 {
+		return nil, fmt.Errorf("wallet snapshot interval must not be negative")
+	}
+
+	if config.Auth.MaxFailedLogins <= 0 {
+		return nil, fmt.Errorf("auth max failed logins must be positive")
+	}
+	if config.Auth.LockoutDuration <= 0 {
+		return nil, fmt.Errorf("auth lockout duration must be positive")
+	}
+
+	if _, err := config.Credits.RoundingPolicy(); err != nil {
+		return nil, err
 	}
 
 	return config, nil
 }
 
+// RoundingPolicy returns the credit rounding policy described by the config
+func (c CreditsConfig) RoundingPolicy() (credits.RoundingPolicy, error) {
+	return credits.NewRoundingPolicy(c.RoundingPlaces, c.RoundingMode)
+}
+
+// validatePagination checks that the default page size fits under the maximum
+// and that the batch size cap is positive
+func validatePagination(pagination PaginationConfig) error {
+	if pagination.DefaultLimit <= 0 || pagination.MaxLimit <= 0 {
+		return fmt.Errorf("pagination limits must be positive")
+	}
+	if pagination.DefaultLimit > pagination.MaxLimit {
+		return fmt.Errorf("pagination default limit %d exceeds max limit %d",
+			pagination.DefaultLimit, pagination.MaxLimit)
+	}
+	if pagination.MaxBatchItems <= 0 {
+		return fmt.Errorf("max batch items must be positive")
+	}
+	return nil
+}
+
+// validateTrustedProxies checks that every trusted proxy is an IP or CIDR
+func validateTrustedProxies(proxies []string) error {
+	for _, proxy := range proxies {
+		if strings.Contains(proxy, "/") {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("invalid trusted proxy CIDR %q: %w", proxy, err)
+			}
+			continue
+		}
+		if net.ParseIP(proxy) == nil {
+			return fmt.Errorf("invalid trusted proxy IP %q", proxy)
+		}
+	}
+	return nil
+}
+
 // GetDatabaseURL returns the database connection URL
 func (c *Config) GetDatabaseURL() string {
 	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
@@ -125,3 +473,51 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsSlice splits a comma-separated variable, ignoring empty entries
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// getEnvAsMap parses a comma-separated list of key=value pairs, ignoring
+// entries without a key or value
+func getEnvAsMap(key string) map[string]string {
+	values := make(map[string]string)
+	for _, item := range getEnvAsSlice(key, nil) {
+		k, v, ok := strings.Cut(item, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if ok && k != "" && v != "" {
+			values[k] = v
+		}
+	}
+	return values
+}
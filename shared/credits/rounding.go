@@ -0,0 +1,67 @@
+package credits
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxPlaces is the precision of the credit columns, which are decimal(15,3)
+const MaxPlaces = 3
+
+// RoundingMode selects how credit amounts are rounded
+type RoundingMode string
+
+// Rounding modes
+const (
+	RoundHalfUp   RoundingMode = "half_up"
+	RoundHalfEven RoundingMode = "half_even"
+	RoundDown     RoundingMode = "down"
+	RoundUp       RoundingMode = "up"
+)
+
+// RoundingPolicy rounds credit amounts before they are awarded or stored.
+// The tracker and the wallet share a policy so the credits an activity earns
+// are exactly the credits the wallet records.
+type RoundingPolicy struct {
+	Places int32
+	Mode   RoundingMode
+}
+
+// DefaultRoundingPolicy rounds half up to the precision of the credit columns
+var DefaultRoundingPolicy = RoundingPolicy{Places: MaxPlaces, Mode: RoundHalfUp}
+
+// NewRoundingPolicy validates and builds a rounding policy
+func NewRoundingPolicy(places int, mode string) (RoundingPolicy, error) {
+	if places < 0 || places > MaxPlaces {
+		return RoundingPolicy{}, fmt.Errorf("credit rounding places must be between 0 and %d, got %d", MaxPlaces, places)
+	}
+
+	switch RoundingMode(mode) {
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+	default:
+		return RoundingPolicy{}, fmt.Errorf("invalid credit rounding mode %q", mode)
+	}
+
+	return RoundingPolicy{Places: int32(places), Mode: RoundingMode(mode)}, nil
+}
+
+// Round rounds amount according to the policy. Down and up round toward and
+// away from zero respectively.
+func (p RoundingPolicy) Round(amount decimal.Decimal) decimal.Decimal {
+	switch p.Mode {
+	case RoundHalfEven:
+		return amount.RoundBank(p.Places)
+	case RoundDown:
+		return amount.RoundDown(p.Places)
+	case RoundUp:
+		return amount.RoundUp(p.Places)
+	default:
+		return amount.Round(p.Places)
+	}
+}
+
+// RoundFloat rounds a float64 amount according to the policy
+func (p RoundingPolicy) RoundFloat(amount float64) float64 {
+	return p.Round(decimal.NewFromFloat(amount)).InexactFloat64()
+}
@@ -0,0 +1,62 @@
+package credits
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRoundingPolicy_Round(t *testing.T) {
+	amount := decimal.RequireFromString("0.3335")
+
+	tests := []struct {
+		mode RoundingMode
+		want string
+	}{
+		{RoundHalfUp, "0.334"},
+		{RoundHalfEven, "0.334"},
+		{RoundDown, "0.333"},
+		{RoundUp, "0.334"},
+	}
+
+	for _, tt := range tests {
+		policy := RoundingPolicy{Places: 3, Mode: tt.mode}
+		if got := policy.Round(amount); !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("%s: expected %s, got %s", tt.mode, tt.want, got)
+		}
+	}
+
+	halfEven := RoundingPolicy{Places: 3, Mode: RoundHalfEven}
+	if got := halfEven.Round(decimal.RequireFromString("0.3345")); !got.Equal(decimal.RequireFromString("0.334")) {
+		t.Errorf("expected half_even to round 0.3345 to 0.334, got %s", got)
+	}
+}
+
+func TestRoundingPolicy_RoundFloatMatchesRound(t *testing.T) {
+	credits := 1.0 / 3.0
+
+	got := DefaultRoundingPolicy.RoundFloat(credits)
+	if got != 0.333 {
+		t.Errorf("expected 0.333, got %v", got)
+	}
+	if !decimal.NewFromFloat(got).Equal(DefaultRoundingPolicy.Round(decimal.NewFromFloat(credits))) {
+		t.Error("expected the float and decimal roundings to agree")
+	}
+}
+
+func TestNewRoundingPolicy(t *testing.T) {
+	if _, err := NewRoundingPolicy(4, string(RoundHalfUp)); err == nil {
+		t.Error("expected places beyond the column precision to be rejected")
+	}
+	if _, err := NewRoundingPolicy(2, "nearest"); err == nil {
+		t.Error("expected unknown mode to be rejected")
+	}
+
+	policy, err := NewRoundingPolicy(2, string(RoundDown))
+	if err != nil {
+		t.Fatalf("expected valid policy, got %v", err)
+	}
+	if policy.Places != 2 || policy.Mode != RoundDown {
+		t.Errorf("unexpected policy %+v", policy)
+	}
+}
@@ -23,7 +23,8 @@ type PostgresDB struct {
 
 // NewPostgresDB creates a new PostgreSQL database connection
 func NewPostgresDB(cfg *config.DatabaseConfig, log *logger.Logger) (*PostgresDB, error) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
+	// TimeZone=UTC makes timestamps read back in UTC, matching how they are stored
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=UTC",
 		cfg.Host, cfg.User, cfg.Password, cfg.DBName, cfg.Port, cfg.SSLMode)
 
 	// Configure GORM logger
@@ -44,6 +45,10 @@ func NewPostgresDB(cfg *config.DatabaseConfig, log *logger.Logger) (*PostgresDB,
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := RegisterUTCCallbacks(db); err != nil {
+		return nil, fmt.Errorf("failed to register UTC timestamp callbacks: %w", err)
+	}
+
 	// Get underlying sql.DB for connection pool configuration
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// All timestamps are stored in UTC. GORM's auto timestamps use NowFunc,
+// which returns UTC, and the utcTimestamps callback converts any time.Time
+// set explicitly by service code before it is written, so created_at and
+// values such as processed_at or expires_at never mix zones.
+
+// RegisterUTCCallbacks converts time.Time fields to UTC before creates and updates
+func RegisterUTCCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").
+		Register("greenledger:utc_timestamps", utcTimestamps); err != nil {
+		return err
+	}
+	return db.Callback().Update().Before("gorm:update").
+		Register("greenledger:utc_timestamps", utcTimestamps)
+}
+
+// utcTimestamps converts the statement's time fields to UTC
+func utcTimestamps(db *gorm.DB) {
+	if db.Statement.Schema == nil || !db.Statement.ReflectValue.IsValid() {
+		return
+	}
+
+	switch db.Statement.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < db.Statement.ReflectValue.Len(); i++ {
+			utcFields(db, reflect.Indirect(db.Statement.ReflectValue.Index(i)))
+		}
+	case reflect.Struct:
+		utcFields(db, db.Statement.ReflectValue)
+	}
+}
+
+// utcFields converts every time.Time and *time.Time field of one record to UTC
+func utcFields(db *gorm.DB, record reflect.Value) {
+	ctx := db.Statement.Context
+	for _, field := range db.Statement.Schema.Fields {
+		if field.FieldType != reflect.TypeOf(time.Time{}) && field.FieldType != reflect.TypeOf(&time.Time{}) {
+			continue
+		}
+
+		value, isZero := field.ValueOf(ctx, record)
+		if isZero {
+			continue
+		}
+
+		switch t := value.(type) {
+		case time.Time:
+			if t.Location() != time.UTC {
+				setField(db, field, record, t.UTC())
+			}
+		case *time.Time:
+			if t != nil && t.Location() != time.UTC {
+				utc := t.UTC()
+				setField(db, field, record, &utc)
+			}
+		}
+	}
+}
+
+func setField(db *gorm.DB, field *schema.Field, record reflect.Value, value interface{}) {
+	if err := field.Set(db.Statement.Context, record, value); err != nil {
+		db.AddError(err)
+	}
+}
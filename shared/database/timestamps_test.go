@@ -0,0 +1,77 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type timestampRecord struct {
+	ID          uint
+	ProcessedAt time.Time
+	ExpiresAt   *time.Time
+	CreatedAt   time.Time
+}
+
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry-run database: %v", err)
+	}
+	if err := RegisterUTCCallbacks(db); err != nil {
+		t.Fatalf("failed to register callbacks: %v", err)
+	}
+	return db
+}
+
+func TestUTCCallbacks_StoreTimestampsInUTC(t *testing.T) {
+	db := newDryRunDB(t)
+	local := time.FixedZone("UTC+7", 7*60*60)
+	processedAt := time.Date(2024, 3, 1, 9, 0, 0, 0, local)
+	expiresAt := processedAt.Add(time.Hour)
+
+	record := &timestampRecord{ProcessedAt: processedAt, ExpiresAt: &expiresAt}
+	if err := db.Create(record).Error; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if record.ProcessedAt.Location() != time.UTC || !record.ProcessedAt.Equal(processedAt) {
+		t.Errorf("Expected ProcessedAt converted to UTC, got %v", record.ProcessedAt)
+	}
+	if record.ExpiresAt.Location() != time.UTC || !record.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Expected ExpiresAt converted to UTC, got %v", record.ExpiresAt)
+	}
+	if record.CreatedAt.Location() != time.UTC {
+		t.Errorf("Expected CreatedAt in UTC, got %v", record.CreatedAt.Location())
+	}
+}
+
+func TestUTCCallbacks_BatchCreate(t *testing.T) {
+	db := newDryRunDB(t)
+	local := time.FixedZone("UTC-5", -5*60*60)
+
+	records := []*timestampRecord{
+		{ProcessedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, local)},
+		{ProcessedAt: time.Date(2024, 3, 2, 9, 0, 0, 0, local)},
+	}
+	if err := db.Create(&records).Error; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i, record := range records {
+		if record.ProcessedAt.Location() != time.UTC {
+			t.Errorf("Expected record %d ProcessedAt in UTC, got %v", i, record.ProcessedAt.Location())
+		}
+	}
+}
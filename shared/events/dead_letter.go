@@ -0,0 +1,226 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrDeadLetterReplayed is returned when replaying an entry that was already replayed successfully
+	ErrDeadLetterReplayed = errors.New("dead letter already replayed")
+
+	// ErrNoReplayHandler is returned when no consumer in this service handles the entry's topic
+	ErrNoReplayHandler = errors.New("no replay handler registered for topic")
+)
+
+// DeadLetter is an event a consumer gave up on. The original message is kept
+// so it can be inspected and replayed once the cause has been fixed.
+type DeadLetter struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Consumer       string     `gorm:"not null;index" json:"consumer"`
+	Topic          string     `gorm:"not null;index" json:"topic"`
+	Partition      int        `json:"partition"`
+	Offset         int64      `json:"offset"`
+	EventType      string     `gorm:"index" json:"event_type"`
+	Key            string     `json:"key,omitempty"`
+	Payload        string     `gorm:"type:text" json:"payload"`
+	Error          string     `gorm:"type:text" json:"error"`
+	ReplayAttempts int        `gorm:"default:0" json:"replay_attempts"`
+	ReplayedAt     *time.Time `json:"replayed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName returns the table name for DeadLetter
+func (DeadLetter) TableName() string {
+	return "dead_letter_events"
+}
+
+// Message rebuilds the Kafka message the entry was recorded from
+func (d *DeadLetter) Message() kafka.Message {
+	return kafka.Message{
+		Topic:     d.Topic,
+		Partition: d.Partition,
+		Offset:    d.Offset,
+		Key:       []byte(d.Key),
+		Value:     []byte(d.Payload),
+		Headers:   []kafka.Header{{Key: "event-type", Value: []byte(d.EventType)}},
+	}
+}
+
+// newDeadLetter captures a failed message and the error that stopped it
+func newDeadLetter(consumer string, message kafka.Message, cause error) *DeadLetter {
+	entry := &DeadLetter{
+		Consumer:  consumer,
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+		EventType: headerValue(message.Headers, "event-type"),
+		Key:       string(message.Key),
+		Payload:   string(message.Value),
+	}
+	if cause != nil {
+		entry.Error = cause.Error()
+	}
+	return entry
+}
+
+// ReplayFunc processes a replayed message through a consumer's normal path.
+// It runs once per replay request, so handlers must be idempotent.
+type ReplayFunc func(ctx context.Context, message kafka.Message) error
+
+// DeadLetterQueue stores events that consumers could not process in the
+// service's own database and replays them on request
+type DeadLetterQueue struct {
+	db     *database.PostgresDB
+	logger *logger.Logger
+
+	mu        sync.RWMutex
+	replayers map[string]ReplayFunc
+}
+
+// NewDeadLetterQueue creates a new dead-letter queue
+func NewDeadLetterQueue(db *database.PostgresDB, logger *logger.Logger) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		db:        db,
+		logger:    logger,
+		replayers: make(map[string]ReplayFunc),
+	}
+}
+
+// Register sets the function that replays entries from topic
+func (q *DeadLetterQueue) Register(topic string, replay ReplayFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.replayers[topic] = replay
+}
+
+// Record stores a message that consumer gave up on
+func (q *DeadLetterQueue) Record(ctx context.Context, consumer string, message kafka.Message, cause error) error {
+	entry := newDeadLetter(consumer, message, cause)
+	if err := q.db.WithContext(ctx).Create(entry).Error; err != nil {
+		q.logger.LogError(ctx, "failed to record dead letter", err,
+			logger.String("consumer", consumer),
+			logger.String("topic", message.Topic),
+			logger.String("event_type", entry.EventType))
+		return fmt.Errorf("failed to record dead letter: %w", err)
+	}
+
+	q.logger.LogWarn(ctx, "event moved to dead-letter queue",
+		logger.String("id", entry.ID.String()),
+		logger.String("consumer", consumer),
+		logger.String("event_type", entry.EventType),
+		logger.String("error", entry.Error))
+	return nil
+}
+
+// List returns dead letters newest first, optionally filtered by event type
+// and whether they have been replayed
+func (q *DeadLetterQueue) List(ctx context.Context, eventType string, replayed *bool, limit, offset int) ([]*DeadLetter, int64, error) {
+	query := q.db.WithContext(ctx).Model(&DeadLetter{})
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+	if replayed != nil {
+		if *replayed {
+			query = query.Where("replayed_at IS NOT NULL")
+		} else {
+			query = query.Where("replayed_at IS NULL")
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		q.logger.LogError(ctx, "failed to count dead letters", err)
+		return nil, 0, fmt.Errorf("failed to count dead letters: %w", err)
+	}
+
+	var entries []*DeadLetter
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		q.logger.LogError(ctx, "failed to list dead letters", err)
+		return nil, 0, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// Get returns a dead letter by ID
+func (q *DeadLetterQueue) Get(ctx context.Context, id uuid.UUID) (*DeadLetter, error) {
+	var entry DeadLetter
+	if err := q.db.WithContext(ctx).First(&entry, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, database.ErrNotFound
+		}
+		q.logger.LogError(ctx, "failed to get dead letter", err,
+			logger.String("id", id.String()))
+		return nil, fmt.Errorf("failed to get dead letter: %w", err)
+	}
+	return &entry, nil
+}
+
+// Replay runs a dead letter through its consumer's handler once. An entry
+// is only marked replayed after the handler succeeds, and an entry that has
+// already been replayed is never processed again.
+func (q *DeadLetterQueue) Replay(ctx context.Context, id uuid.UUID) (*DeadLetter, error) {
+	entry, err := q.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.ReplayedAt != nil {
+		return entry, ErrDeadLetterReplayed
+	}
+
+	q.mu.RLock()
+	replay, ok := q.replayers[entry.Topic]
+	q.mu.RUnlock()
+	if !ok {
+		return entry, fmt.Errorf("%w: %s", ErrNoReplayHandler, entry.Topic)
+	}
+
+	replayErr := replay(ctx, entry.Message())
+
+	updates := map[string]interface{}{
+		"replay_attempts": gorm.Expr("replay_attempts + 1"),
+	}
+	if replayErr != nil {
+		updates["error"] = replayErr.Error()
+	} else {
+		updates["replayed_at"] = time.Now().UTC()
+	}
+
+	// Only the first successful replay marks the entry, so concurrent replays
+	// cannot both report success
+	result := q.db.WithContext(ctx).Model(&DeadLetter{}).
+		Where("id = ? AND replayed_at IS NULL", id).
+		Updates(updates)
+	if result.Error != nil {
+		q.logger.LogError(ctx, "failed to update dead letter after replay", result.Error,
+			logger.String("id", id.String()))
+		return nil, fmt.Errorf("failed to update dead letter: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entry, ErrDeadLetterReplayed
+	}
+
+	if replayErr != nil {
+		q.logger.LogError(ctx, "dead letter replay failed", replayErr,
+			logger.String("id", id.String()),
+			logger.String("event_type", entry.EventType))
+		return nil, fmt.Errorf("replay failed: %w", replayErr)
+	}
+
+	q.logger.LogInfo(ctx, "dead letter replayed",
+		logger.String("id", id.String()),
+		logger.String("event_type", entry.EventType))
+
+	return q.Get(ctx, id)
+}
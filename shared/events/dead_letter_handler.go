@@ -0,0 +1,164 @@
+package events
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/httperr"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/middleware"
+)
+
+// DeadLetterHandler exposes a service's dead-letter queue to admins
+type DeadLetterHandler struct {
+	queue     *DeadLetterQueue
+	errMapper *httperr.Mapper
+	logger    *logger.Logger
+}
+
+// NewDeadLetterHandler creates a new dead-letter handler
+func NewDeadLetterHandler(queue *DeadLetterQueue, logger *logger.Logger) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		queue: queue,
+		errMapper: httperr.New(logger,
+			httperr.Mapping{Err: ErrDeadLetterReplayed, Status: http.StatusConflict, Message: "Dead letter already replayed"},
+			httperr.Mapping{Err: ErrNoReplayHandler, Status: http.StatusServiceUnavailable, Message: "No consumer is running for this event"},
+			httperr.Mapping{Err: database.ErrNotFound, Status: http.StatusNotFound, Message: "Dead letter not found"},
+		),
+		logger: logger,
+	}
+}
+
+// RegisterRoutes registers the admin dead-letter routes under router, which
+// should be the service's own route group such as /api/v1/wallet
+func (h *DeadLetterHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	admin := router.Group("/admin/dead-letters")
+	{
+		admin.Use(authMiddleware.RequireAuth())
+		admin.Use(authMiddleware.RequireRole("admin"))
+		admin.GET("", h.ListDeadLetters)
+		admin.GET("/:id", h.GetDeadLetter)
+		admin.POST("/:id/replay", h.ReplayDeadLetter)
+	}
+}
+
+// DeadLetterListResponse is a page of dead letters
+type DeadLetterListResponse struct {
+	DeadLetters []*DeadLetter `json:"dead_letters"`
+	middleware.PageInfo
+}
+
+// ListDeadLetters godoc
+// @Summary List dead-lettered events
+// @Description List events this service's consumers gave up on, newest first (admin only)
+// @Tags admin
+// @Produce json
+// @Param event_type query string false "Filter by event type"
+// @Param replayed query bool false "Filter by whether the event has been replayed"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} DeadLetterListResponse
+// @Failure 400 {object} httperr.ErrorResponse
+// @Failure 401 {object} httperr.ErrorResponse
+// @Failure 403 {object} httperr.ErrorResponse
+// @Router /admin/dead-letters [get]
+// @Security BearerAuth
+func (h *DeadLetterHandler) ListDeadLetters(c *gin.Context) {
+	limit, offset := middleware.GetPagination(c)
+
+	var replayed *bool
+	if raw := c.Query("replayed"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httperr.ErrorResponse{
+				Error:   "Invalid replayed filter",
+				Details: "replayed must be true or false",
+			})
+			return
+		}
+		replayed = &value
+	}
+
+	entries, total, err := h.queue.List(c.Request.Context(), c.Query("event_type"), replayed, limit, offset)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to list dead letters")
+		return
+	}
+
+	c.JSON(http.StatusOK, DeadLetterListResponse{
+		DeadLetters: entries,
+		PageInfo:    middleware.NewPageInfo(total, limit, offset),
+	})
+}
+
+// GetDeadLetter godoc
+// @Summary Get a dead-lettered event
+// @Description Get a dead-lettered event with its payload and last error (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} DeadLetter
+// @Failure 400 {object} httperr.ErrorResponse
+// @Failure 404 {object} httperr.ErrorResponse
+// @Router /admin/dead-letters/{id} [get]
+// @Security BearerAuth
+func (h *DeadLetterHandler) GetDeadLetter(c *gin.Context) {
+	id, ok := parseDeadLetterID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.queue.Get(c.Request.Context(), id)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to get dead letter",
+			logger.String("id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// ReplayDeadLetter godoc
+// @Summary Replay a dead-lettered event
+// @Description Run a dead-lettered event through its consumer again. Entries that were already replayed are not processed twice. (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} DeadLetter
+// @Failure 400 {object} httperr.ErrorResponse
+// @Failure 404 {object} httperr.ErrorResponse
+// @Failure 409 {object} httperr.ErrorResponse
+// @Failure 503 {object} httperr.ErrorResponse
+// @Router /admin/dead-letters/{id}/replay [post]
+// @Security BearerAuth
+func (h *DeadLetterHandler) ReplayDeadLetter(c *gin.Context) {
+	id, ok := parseDeadLetterID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.queue.Replay(c.Request.Context(), id)
+	if err != nil {
+		h.errMapper.Respond(c, err, "Failed to replay dead letter",
+			logger.String("id", id.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// parseDeadLetterID reads the :id path parameter, writing a 400 if it is not a UUID
+func parseDeadLetterID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, httperr.ErrorResponse{
+			Error:   "Invalid dead letter ID",
+			Details: err.Error(),
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,80 @@
+package events
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/segmentio/kafka-go"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+func TestDeadLetter_MessageRoundTrip(t *testing.T) {
+	message := kafka.Message{
+		Topic:     UserEventsTopic,
+		Partition: 2,
+		Offset:    41,
+		Key:       []byte("user-123"),
+		Value:     []byte(`{"user_id":"user-123"}`),
+		Headers:   []kafka.Header{{Key: "event-type", Value: []byte(EventTypeUserDeleted)}},
+	}
+
+	entry := newDeadLetter("wallet-service", message, errors.New("database unavailable"))
+	if entry.EventType != EventTypeUserDeleted {
+		t.Errorf("expected event type %q, got %q", EventTypeUserDeleted, entry.EventType)
+	}
+	if entry.Error != "database unavailable" {
+		t.Errorf("expected error to be recorded, got %q", entry.Error)
+	}
+
+	replayed := entry.Message()
+	if replayed.Topic != message.Topic || replayed.Partition != message.Partition || replayed.Offset != message.Offset {
+		t.Errorf("expected position %s/%d/%d, got %s/%d/%d",
+			message.Topic, message.Partition, message.Offset,
+			replayed.Topic, replayed.Partition, replayed.Offset)
+	}
+	if string(replayed.Key) != "user-123" || string(replayed.Value) != string(message.Value) {
+		t.Errorf("expected key and payload to round-trip, got %q / %q", replayed.Key, replayed.Value)
+	}
+	if got := headerValue(replayed.Headers, "event-type"); got != EventTypeUserDeleted {
+		t.Errorf("expected event-type header %q, got %q", EventTypeUserDeleted, got)
+	}
+
+	event, err := decodeUserDeletedEvent(replayed)
+	if err != nil {
+		t.Fatalf("expected replayed message to decode, got %v", err)
+	}
+	if event.UserID != "user-123" {
+		t.Errorf("expected user id user-123, got %q", event.UserID)
+	}
+}
+
+func TestDeadLetterHandler_RejectsInvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewDeadLetterHandler(NewDeadLetterQueue(nil, logger.New("error")), logger.New("error"))
+	router := gin.New()
+	router.GET("/admin/dead-letters", handler.ListDeadLetters)
+	router.POST("/admin/dead-letters/:id/replay", handler.ReplayDeadLetter)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+	}{
+		{"invalid replayed filter", http.MethodGet, "/admin/dead-letters?replayed=maybe"},
+		{"invalid id", http.MethodPost, "/admin/dead-letters/not-a-uuid/replay"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, recorder.Code)
+			}
+		})
+	}
+}
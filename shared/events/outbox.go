@@ -0,0 +1,171 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// outboxBatchSize is how many outbox entries a relay locks and publishes at a time
+const outboxBatchSize = 100
+
+// OutboxEvent is an event written in the same database transaction as the
+// change it announces and published afterwards by an OutboxRelay, so the
+// event is published exactly when the change is committed. Entries may be
+// published more than once, so consumers must be idempotent.
+type OutboxEvent struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EventType   string     `gorm:"not null;index" json:"event_type"`
+	Key         string     `json:"key,omitempty"`
+	Payload     string     `gorm:"type:text" json:"payload"`
+	Attempts    int        `gorm:"default:0" json:"attempts"`
+	LastError   string     `gorm:"type:text" json:"last_error,omitempty"`
+	PublishedAt *time.Time `gorm:"index" json:"published_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for OutboxEvent
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// NewOutboxEvent creates an outbox entry holding event as JSON
+func NewOutboxEvent(eventType, key string, event interface{}) (*OutboxEvent, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	return &OutboxEvent{
+		ID:        uuid.New(),
+		EventType: eventType,
+		Key:       key,
+		Payload:   string(payload),
+	}, nil
+}
+
+// OutboxPublishFunc publishes the event held in an outbox entry
+type OutboxPublishFunc func(ctx context.Context, entry *OutboxEvent) error
+
+// OutboxRelay publishes the outbox entries in a service's own database
+type OutboxRelay struct {
+	db     *database.PostgresDB
+	logger *logger.Logger
+
+	mu         sync.RWMutex
+	publishers map[string]OutboxPublishFunc
+}
+
+// NewOutboxRelay creates a new outbox relay
+func NewOutboxRelay(db *database.PostgresDB, logger *logger.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		db:         db,
+		logger:     logger,
+		publishers: make(map[string]OutboxPublishFunc),
+	}
+}
+
+// Register sets the function that publishes entries of eventType
+func (r *OutboxRelay) Register(eventType string, publish OutboxPublishFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.publishers[eventType] = publish
+}
+
+// RelayPending publishes unpublished entries oldest first and returns how
+// many were published. It stops at the first entry that fails to publish,
+// recording the error on it, so later entries are not published ahead of it.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		n, err := r.relayBatch(ctx)
+		published += n
+		if err != nil || n < outboxBatchSize {
+			return published, err
+		}
+	}
+}
+
+// relayBatch publishes one batch of entries. The entries stay locked until
+// they are marked published, so concurrent relays publish each entry once
+// unless the commit fails.
+func (r *OutboxRelay) relayBatch(ctx context.Context) (int, error) {
+	published := 0
+	var publishErr error
+	err := r.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var entries []*OutboxEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("published_at IS NULL").
+			Order("created_at ASC").
+			Limit(outboxBatchSize).
+			Find(&entries).Error; err != nil {
+			return fmt.Errorf("failed to load outbox events: %w", err)
+		}
+
+		for _, entry := range entries {
+			if publishErr = r.publish(ctx, entry); publishErr != nil {
+				return tx.Model(entry).Updates(map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": publishErr.Error(),
+				}).Error
+			}
+
+			if err := tx.Model(entry).Update("published_at", time.Now().UTC()).Error; err != nil {
+				return fmt.Errorf("failed to mark outbox event published: %w", err)
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.LogError(ctx, "failed to relay outbox events", err)
+		return 0, err
+	}
+	if publishErr != nil {
+		return published, fmt.Errorf("failed to publish outbox event: %w", publishErr)
+	}
+	return published, nil
+}
+
+// publish hands an entry to the function registered for its event type
+func (r *OutboxRelay) publish(ctx context.Context, entry *OutboxEvent) error {
+	r.mu.RLock()
+	publish, ok := r.publishers[entry.EventType]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no publisher registered for event type %s", entry.EventType)
+	}
+	return publish(ctx, entry)
+}
+
+// Run relays pending entries once immediately and then every interval until
+// ctx is cancelled
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		published, err := r.RelayPending(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.logger.LogError(ctx, "outbox relay failed", err)
+		}
+		if published > 0 {
+			r.logger.LogInfo(ctx, "published outbox events",
+				logger.Int("published", published))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
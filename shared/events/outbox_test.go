@@ -0,0 +1,46 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+func TestUserDeletedOutboxEvent_PublishesEvent(t *testing.T) {
+	event := &UserDeletedEvent{
+		UserID:    "user-123",
+		DeletedBy: "admin-1",
+		Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	entry, err := NewUserDeletedOutboxEvent(event)
+	if err != nil {
+		t.Fatalf("expected outbox event to be created, got %v", err)
+	}
+	if entry.EventType != EventTypeUserDeleted || entry.Key != "user-123" {
+		t.Errorf("expected a user deleted entry keyed by user, got %q / %q", entry.EventType, entry.Key)
+	}
+
+	publisher := NewMockUserEventPublisher(logger.New("error"))
+	if err := PublishUserDeletedFromOutbox(publisher)(context.Background(), entry); err != nil {
+		t.Fatalf("expected outbox event to be published, got %v", err)
+	}
+	if len(publisher.Events) != 1 {
+		t.Fatalf("expected 1 published event, got %d", len(publisher.Events))
+	}
+	if published := publisher.Events[0]; published.UserID != event.UserID ||
+		published.DeletedBy != event.DeletedBy || !published.Timestamp.Equal(event.Timestamp) {
+		t.Errorf("expected %+v to be published, got %+v", event, published)
+	}
+}
+
+func TestDeadLetterDelay(t *testing.T) {
+	if delay := deadLetterDelay(1); delay != time.Second {
+		t.Errorf("expected 1s after the first attempt, got %s", delay)
+	}
+	if delay := deadLetterDelay(100); delay != maxDeadLetterDelay {
+		t.Errorf("expected delays to be capped at %s, got %s", maxDeadLetterDelay, delay)
+	}
+}
@@ -0,0 +1,288 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+const (
+	// UserEventsTopic carries user lifecycle events published by the user-auth service
+	UserEventsTopic = "greenledger-user-events"
+
+	// EventTypeUserDeleted is emitted once a user account has been erased
+	EventTypeUserDeleted = "user_deleted"
+
+	// ErasedUserID replaces an erased user's id where a record is kept for
+	// other users, such as the counterparty of a transfer
+	ErasedUserID = "erased-user"
+
+	maxHandlerAttempts = 3
+
+	// maxDeadLetterDelay caps the wait between attempts to record a dead letter
+	maxDeadLetterDelay = 30 * time.Second
+)
+
+// UserDeletedEvent asks every service to erase or anonymize the data it holds for a user
+type UserDeletedEvent struct {
+	UserID    string    `json:"user_id"`
+	DeletedBy string    `json:"deleted_by"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// UserEventPublisher publishes user lifecycle events
+type UserEventPublisher interface {
+	PublishUserDeleted(ctx context.Context, event *UserDeletedEvent) error
+}
+
+// KafkaUserEventPublisher implements UserEventPublisher using Kafka
+type KafkaUserEventPublisher struct {
+	writer *kafka.Writer
+	logger *logger.Logger
+}
+
+// NewKafkaUserEventPublisher creates a new Kafka user event publisher
+func NewKafkaUserEventPublisher(brokers []string, logger *logger.Logger) *KafkaUserEventPublisher {
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        UserEventsTopic,
+		Balancer:     &kafka.LeastBytes{},
+		RequiredAcks: kafka.RequireAll,
+		Async:        false,
+	}
+
+	return &KafkaUserEventPublisher{
+		writer: writer,
+		logger: logger,
+	}
+}
+
+// PublishUserDeleted publishes a user deleted event
+func (p *KafkaUserEventPublisher) PublishUserDeleted(ctx context.Context, event *UserDeletedEvent) error {
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	message := kafka.Message{
+		Key:   []byte(event.UserID),
+		Value: eventData,
+		Headers: []kafka.Header{
+			{Key: "event-type", Value: []byte(EventTypeUserDeleted)},
+			{Key: "user-id", Value: []byte(event.UserID)},
+			{Key: "source", Value: []byte("user-auth-service")},
+		},
+	}
+
+	if err := p.writer.WriteMessages(ctx, message); err != nil {
+		p.logger.LogError(ctx, "failed to publish user deleted event", err,
+			logger.String("user_id", event.UserID))
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	p.logger.LogInfo(ctx, "user deleted event published",
+		logger.String("user_id", event.UserID))
+
+	return nil
+}
+
+// Close closes the publisher
+func (p *KafkaUserEventPublisher) Close() error {
+	return p.writer.Close()
+}
+
+// MockUserEventPublisher records user events instead of publishing them
+type MockUserEventPublisher struct {
+	Events []*UserDeletedEvent
+	logger *logger.Logger
+}
+
+// NewMockUserEventPublisher creates a new mock user event publisher
+func NewMockUserEventPublisher(logger *logger.Logger) *MockUserEventPublisher {
+	return &MockUserEventPublisher{
+		Events: make([]*UserDeletedEvent, 0),
+		logger: logger,
+	}
+}
+
+// PublishUserDeleted records a user deleted event (mock)
+func (p *MockUserEventPublisher) PublishUserDeleted(ctx context.Context, event *UserDeletedEvent) error {
+	p.Events = append(p.Events, event)
+	p.logger.LogInfo(ctx, "mock: user deleted event published",
+		logger.String("user_id", event.UserID))
+	return nil
+}
+
+// NewUserDeletedOutboxEvent creates the outbox entry that announces a user's
+// erasure, to be written in the erasing transaction
+func NewUserDeletedOutboxEvent(event *UserDeletedEvent) (*OutboxEvent, error) {
+	return NewOutboxEvent(EventTypeUserDeleted, event.UserID, event)
+}
+
+// PublishUserDeletedFromOutbox returns an OutboxPublishFunc that publishes
+// user deleted outbox entries with publisher
+func PublishUserDeletedFromOutbox(publisher UserEventPublisher) OutboxPublishFunc {
+	return func(ctx context.Context, entry *OutboxEvent) error {
+		var event UserDeletedEvent
+		if err := json.Unmarshal([]byte(entry.Payload), &event); err != nil {
+			return fmt.Errorf("failed to unmarshal user deleted event: %w", err)
+		}
+		return publisher.PublishUserDeleted(ctx, &event)
+	}
+}
+
+// UserDeletedHandler erases a user's data in the consuming service
+type UserDeletedHandler func(ctx context.Context, event *UserDeletedEvent) error
+
+// UserEventConsumer consumes user lifecycle events
+type UserEventConsumer struct {
+	reader      *kafka.Reader
+	groupID     string
+	deadLetters *DeadLetterQueue
+	logger      *logger.Logger
+}
+
+// NewUserEventConsumer creates a new user event consumer for the given
+// consumer group. Events that cannot be handled are kept in deadLetters,
+// which is required.
+func NewUserEventConsumer(brokers []string, groupID string, deadLetters *DeadLetterQueue, logger *logger.Logger) *UserEventConsumer {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:  brokers,
+		Topic:    UserEventsTopic,
+		GroupID:  groupID,
+		MinBytes: 1,
+		MaxBytes: 10e6, // 10MB
+	})
+
+	return &UserEventConsumer{
+		reader:      reader,
+		groupID:     groupID,
+		deadLetters: deadLetters,
+		logger:      logger,
+	}
+}
+
+// Consume reads user events until ctx is cancelled. Handler failures, such
+// as a service refusing to erase a user who still holds assets, are retried a
+// few times before the event is moved to the dead-letter queue, which replays
+// entries through the same handler. An event is only committed once it has
+// been handled or recorded as a dead letter.
+func (c *UserEventConsumer) Consume(ctx context.Context, handler UserDeletedHandler) error {
+	if c.deadLetters == nil {
+		return fmt.Errorf("user event consumer %s has no dead-letter queue", c.groupID)
+	}
+	c.deadLetters.Register(UserEventsTopic, func(ctx context.Context, message kafka.Message) error {
+		event, err := decodeUserDeletedEvent(message)
+		if err != nil {
+			return err
+		}
+		return handler(ctx, event)
+	})
+
+	for {
+		message, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.logger.LogError(ctx, "failed to read user event", err)
+			continue
+		}
+
+		if eventType := headerValue(message.Headers, "event-type"); eventType != EventTypeUserDeleted {
+			c.logger.LogWarn(ctx, "unknown user event type",
+				logger.String("event_type", eventType))
+		} else if event, err := decodeUserDeletedEvent(message); err != nil {
+			c.logger.LogError(ctx, "failed to unmarshal user deleted event", err)
+			if err := c.deadLetter(ctx, message, err); err != nil {
+				return err
+			}
+		} else if err := c.handleWithRetry(ctx, handler, event); err != nil {
+			if ctx.Err() != nil {
+				// Leave the offset uncommitted so the event is redelivered
+				return ctx.Err()
+			}
+			c.logger.LogError(ctx, "failed to handle user deleted event", err,
+				logger.String("user_id", event.UserID))
+			if err := c.deadLetter(ctx, message, err); err != nil {
+				return err
+			}
+		}
+
+		if err := c.reader.CommitMessages(ctx, message); err != nil {
+			c.logger.LogError(ctx, "failed to commit user event", err)
+		}
+	}
+}
+
+// deadLetter records a message the consumer is giving up on, retrying until
+// the record is stored so the message is never committed without a copy to
+// replay. It returns ctx's error, leaving the message uncommitted, if the
+// consumer stops first.
+func (c *UserEventConsumer) deadLetter(ctx context.Context, message kafka.Message, cause error) error {
+	for attempt := 1; ; attempt++ {
+		// Recording failures are logged by the queue
+		if err := c.deadLetters.Record(ctx, c.groupID, message, cause); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(deadLetterDelay(attempt)):
+		}
+	}
+}
+
+// deadLetterDelay is how long to wait after a failed attempt to record a
+// dead letter, growing with each attempt up to maxDeadLetterDelay
+func deadLetterDelay(attempt int) time.Duration {
+	if delay := time.Duration(attempt) * time.Second; delay < maxDeadLetterDelay {
+		return delay
+	}
+	return maxDeadLetterDelay
+}
+
+// decodeUserDeletedEvent parses a user deleted event from a message
+func decodeUserDeletedEvent(message kafka.Message) (*UserDeletedEvent, error) {
+	var event UserDeletedEvent
+	if err := json.Unmarshal(message.Value, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user deleted event: %w", err)
+	}
+	return &event, nil
+}
+
+func (c *UserEventConsumer) handleWithRetry(ctx context.Context, handler UserDeletedHandler, event *UserDeletedEvent) error {
+	var err error
+	for attempt := 1; attempt <= maxHandlerAttempts; attempt++ {
+		if err = handler(ctx, event); err == nil {
+			return nil
+		}
+		if attempt == maxHandlerAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * time.Second):
+		}
+	}
+	return err
+}
+
+// Close closes the consumer
+func (c *UserEventConsumer) Close() error {
+	return c.reader.Close()
+}
+
+func headerValue(headers []kafka.Header, key string) string {
+	for _, header := range headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
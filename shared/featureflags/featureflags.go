@@ -0,0 +1,97 @@
+package featureflags
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Flag names an optional subsystem that can be switched on or off per environment
+type Flag string
+
+// Known flags. Add new optional subsystems here with their production default.
+const (
+	// Kafka publishes and consumes events through Kafka; when off, services
+	// use in-memory publishers and start no consumers
+	Kafka Flag = "kafka"
+	// ReleaseMode runs the HTTP router in release mode, without debug logging
+	ReleaseMode Flag = "release_mode"
+)
+
+// productionDefaults lists each known flag and whether it is on in
+// production. Every other environment starts with all flags off.
+var productionDefaults = map[Flag]bool{
+	Kafka:       true,
+	ReleaseMode: true,
+}
+
+// Flags holds the resolved state of every known flag
+type Flags struct {
+	enabled map[Flag]bool
+}
+
+// New returns flags with the defaults for environment. Unknown flags in
+// overrides are ignored; use Load to reject them.
+func New(environment string, overrides map[Flag]bool) *Flags {
+	enabled := make(map[Flag]bool, len(productionDefaults))
+	for flag, onInProduction := range productionDefaults {
+		enabled[flag] = onInProduction && environment == "production"
+	}
+	for flag, on := range overrides {
+		if _, known := productionDefaults[flag]; known {
+			enabled[flag] = on
+		}
+	}
+	return &Flags{enabled: enabled}
+}
+
+// Load resolves flags for environment, letting FEATURE_<NAME> variables
+// (e.g. FEATURE_KAFKA=false) override the defaults. lookup is normally
+// os.LookupEnv.
+func Load(environment string, lookup func(key string) (string, bool)) (*Flags, error) {
+	overrides := make(map[Flag]bool)
+	for flag := range productionDefaults {
+		key := EnvVar(flag)
+		value, ok := lookup(key)
+		if !ok || value == "" {
+			continue
+		}
+
+		on, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s: expected true or false", value, key)
+		}
+		overrides[flag] = on
+	}
+
+	return New(environment, overrides), nil
+}
+
+// EnvVar returns the environment variable that overrides flag
+func EnvVar(flag Flag) string {
+	return "FEATURE_" + strings.ToUpper(string(flag))
+}
+
+// Enabled reports whether flag is on. Unknown flags are always off.
+func (f *Flags) Enabled(flag Flag) bool {
+	if f == nil {
+		return false
+	}
+	return f.enabled[flag]
+}
+
+// EnabledNames returns the names of the flags that are on, sorted, for logging
+func (f *Flags) EnabledNames() []string {
+	var names []string
+	if f == nil {
+		return names
+	}
+	for flag, on := range f.enabled {
+		if on {
+			names = append(names, string(flag))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,58 @@
+package featureflags
+
+import (
+	"reflect"
+	"testing"
+)
+
+func lookupFrom(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		env         map[string]string
+		expected    []string
+	}{
+		{"production defaults", "production", nil, []string{"kafka", "release_mode"}},
+		{"development defaults", "development", nil, nil},
+		{"enable in development", "development", map[string]string{"FEATURE_KAFKA": "true"}, []string{"kafka"}},
+		{"disable in production", "production", map[string]string{"FEATURE_KAFKA": "false"}, []string{"release_mode"}},
+		{"empty value keeps default", "production", map[string]string{"FEATURE_RELEASE_MODE": ""}, []string{"kafka", "release_mode"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := Load(tt.environment, lookupFrom(tt.env))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := flags.EnabledNames(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected enabled flags %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoad_RejectsInvalidValue(t *testing.T) {
+	if _, err := Load("production", lookupFrom(map[string]string{"FEATURE_KAFKA": "sometimes"})); err == nil {
+		t.Error("expected an invalid flag value to be rejected")
+	}
+}
+
+func TestEnabled_UnknownAndNil(t *testing.T) {
+	flags := New("production", map[Flag]bool{"unknown": true})
+	if flags.Enabled("unknown") {
+		t.Error("expected unknown flags to be off")
+	}
+
+	var missing *Flags
+	if missing.Enabled(Kafka) {
+		t.Error("expected nil flags to be off")
+	}
+}
@@ -0,0 +1,80 @@
+package httperr
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/timeout"
+)
+
+// ErrorResponse is the error body returned by every service
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Details string `json:"details,omitempty"`
+}
+
+// Mapping maps a sentinel error, and any error wrapping it, to a status code.
+// An empty Message keeps the message passed to Respond.
+type Mapping struct {
+	Err     error
+	Status  int
+	Message string
+}
+
+// DefaultMappings are checked after a Mapper's own mappings
+var DefaultMappings = []Mapping{
+	{Err: database.ErrNotFound, Status: http.StatusNotFound, Message: "Not found"},
+	{Err: timeout.ErrExceeded, Status: http.StatusGatewayTimeout, Message: "Operation timed out"},
+}
+
+// Mapper turns errors returned by a service into HTTP error responses
+type Mapper struct {
+	mappings []Mapping
+	logger   *logger.Logger
+}
+
+// New creates a mapper that checks mappings in order, then DefaultMappings.
+// Errors that match nothing are server errors.
+func New(logger *logger.Logger, mappings ...Mapping) *Mapper {
+	all := make([]Mapping, 0, len(mappings)+len(DefaultMappings))
+	all = append(all, mappings...)
+	all = append(all, DefaultMappings...)
+
+	return &Mapper{
+		mappings: all,
+		logger:   logger,
+	}
+}
+
+// Status returns the status code and message for err. message is returned
+// when the matching mapping has none, or when nothing matches.
+func (m *Mapper) Status(err error, message string) (int, string) {
+	for _, mapping := range m.mappings {
+		if errors.Is(err, mapping.Err) {
+			if mapping.Message != "" {
+				message = mapping.Message
+			}
+			return mapping.Status, message
+		}
+	}
+	return http.StatusInternalServerError, message
+}
+
+// Respond writes err as an ErrorResponse with its mapped status. message
+// describes the failed operation, e.g. "Failed to get report". Server errors,
+// including timeouts, are logged with attrs; client errors are not.
+func (m *Mapper) Respond(c *gin.Context, err error, message string, attrs ...slog.Attr) {
+	status, message := m.Status(err, message)
+	if status >= http.StatusInternalServerError && m.logger != nil {
+		m.logger.LogError(c.Request.Context(), message, err, attrs...)
+	}
+
+	c.JSON(status, ErrorResponse{
+		Error:   message,
+		Details: err.Error(),
+	})
+}
@@ -0,0 +1,65 @@
+package httperr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sloweyyy/GreenLedger/shared/database"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"github.com/sloweyyy/GreenLedger/shared/timeout"
+)
+
+var errInvalidThing = errors.New("invalid thing")
+
+func TestMapper_Respond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mapper := New(logger.New("error"),
+		Mapping{Err: errInvalidThing, Status: http.StatusBadRequest},
+		Mapping{Err: database.ErrNotFound, Status: http.StatusNotFound, Message: "Thing not found"},
+	)
+
+	tests := []struct {
+		name           string
+		mapper         *Mapper
+		err            error
+		expectedStatus int
+		expectedError  string
+	}{
+		{"wrapped sentinel keeps message", mapper, fmt.Errorf("%w: size is negative", errInvalidThing), http.StatusBadRequest, "Failed to save thing"},
+		{"own mapping overrides default", mapper, fmt.Errorf("failed to get thing: %w", database.ErrNotFound), http.StatusNotFound, "Thing not found"},
+		{"default not found", New(nil), database.ErrNotFound, http.StatusNotFound, "Not found"},
+		{"default timeout", New(nil), fmt.Errorf("%w after 10s: context deadline exceeded", timeout.ErrExceeded), http.StatusGatewayTimeout, "Operation timed out"},
+		{"unmapped error", mapper, errors.New("connection refused"), http.StatusInternalServerError, "Failed to save thing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+			tt.mapper.Respond(c, tt.err, "Failed to save thing")
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, recorder.Code)
+			}
+
+			var body ErrorResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if body.Error != tt.expectedError {
+				t.Errorf("expected error %q, got %q", tt.expectedError, body.Error)
+			}
+			if body.Details != tt.err.Error() {
+				t.Errorf("expected details %q, got %q", tt.err.Error(), body.Details)
+			}
+		})
+	}
+}
@@ -13,13 +13,18 @@ import (
 // AuthMiddleware provides JWT authentication middleware
 type AuthMiddleware struct {
 	jwtSecret []byte
+	issuer    string
+	audience  string
 	logger    *logger.Logger
 }
 
-// NewAuthMiddleware creates a new auth middleware instance
-func NewAuthMiddleware(jwtSecret string, logger *logger.Logger) *AuthMiddleware {
+// NewAuthMiddleware creates a new auth middleware instance. Tokens must carry
+// the given issuer and audience; an empty value skips that check.
+func NewAuthMiddleware(jwtSecret, issuer, audience string, logger *logger.Logger) *AuthMiddleware {
 	return &AuthMiddleware{
 		jwtSecret: []byte(jwtSecret),
+		issuer:    issuer,
+		audience:  audience,
 		logger:    logger,
 	}
 }
@@ -150,9 +155,17 @@ func (a *AuthMiddleware) extractToken(c *gin.Context) string {
 
 // validateToken validates JWT token and returns claims
 func (a *AuthMiddleware) validateToken(tokenString string) (*Claims, error) {
+	var options []jwt.ParserOption
+	if a.issuer != "" {
+		options = append(options, jwt.WithIssuer(a.issuer))
+	}
+	if a.audience != "" {
+		options = append(options, jwt.WithAudience(a.audience))
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return a.jwtSecret, nil
-	})
+	}, options...)
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+func TestRequireAuth_IssuerAndAudience(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	auth := NewAuthMiddleware("secret", "greenledger-auth", "greenledger-production", logger.New("error"))
+	router := gin.New()
+	router.GET("/me", auth.RequireAuth(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	sign := func(issuer, audience string) string {
+		claims := &Claims{
+			UserID: "user-1",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				Issuer:    issuer,
+				Audience:  jwt.ClaimStrings{audience},
+			},
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"matching claims", sign("greenledger-auth", "greenledger-production"), http.StatusOK},
+		{"other environment", sign("greenledger-auth", "greenledger-staging"), http.StatusUnauthorized},
+		{"other issuer", sign("someone-else", "greenledger-production"), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
@@ -6,11 +6,25 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/sloweyyy/GreenLedger/shared/logger"
 )
 
-// RequestLogger creates a logging middleware for Gin
-func RequestLogger(log *logger.Logger) gin.HandlerFunc {
+// slowRequestDuration tracks the latency of requests that exceeded the slow request threshold
+var slowRequestDuration = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "http_slow_request_duration_seconds",
+		Help:    "Duration of HTTP requests that exceeded the slow request threshold",
+		Buckets: []float64{0.5, 1.0, 2.5, 5.0, 10.0, 30.0},
+	},
+	[]string{"method", "endpoint"},
+)
+
+// RequestLogger creates a logging middleware for Gin. Requests taking longer than
+// slowThreshold are additionally logged at warn level and recorded in a histogram;
+// a non-positive threshold disables slow request tracking.
+func RequestLogger(log *logger.Logger, slowThreshold time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Generate request ID
 		requestID := uuid.New().String()
@@ -37,6 +51,21 @@ func RequestLogger(log *logger.Logger) gin.HandlerFunc {
 			logger.String("user_agent", c.Request.UserAgent()),
 			logger.String("request_id", requestID),
 		)
+
+		if slowThreshold > 0 && latency > slowThreshold {
+			slowRequestDuration.With(prometheus.Labels{
+				"method":   c.Request.Method,
+				"endpoint": c.FullPath(),
+			}).Observe(latency.Seconds())
+
+			log.LogWarn(c.Request.Context(), "slow HTTP request",
+				logger.String("method", c.Request.Method),
+				logger.String("path", c.Request.URL.Path),
+				logger.String("latency", latency.String()),
+				logger.String("threshold", slowThreshold.String()),
+				logger.String("request_id", requestID),
+			)
+		}
 	}
 }
 
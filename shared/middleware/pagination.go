@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultPageLimit and MaxPageLimit apply when no Pagination middleware is installed
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 200
+)
+
+// Pagination creates a middleware that parses the limit and offset query
+// parameters for every request. A missing or invalid limit falls back to
+// defaultLimit and anything above maxLimit is clamped to it.
+func Pagination(defaultLimit, maxLimit int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, offset := ParsePagination(c, defaultLimit, maxLimit)
+		c.Set("pagination_limit", limit)
+		c.Set("pagination_offset", offset)
+		c.Next()
+	}
+}
+
+// ParsePagination reads limit and offset from the query string, clamping the
+// limit to (0, maxLimit] and the offset to zero or more
+func ParsePagination(c *gin.Context, defaultLimit, maxLimit int) (int, int) {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
+
+// GetPagination returns the clamped limit and offset for the request
+func GetPagination(c *gin.Context) (int, int) {
+	limit, limitOK := c.Get("pagination_limit")
+	offset, offsetOK := c.Get("pagination_offset")
+	if !limitOK || !offsetOK {
+		return ParsePagination(c, DefaultPageLimit, MaxPageLimit)
+	}
+
+	return limit.(int), offset.(int)
+}
+
+// PageInfo is the pagination metadata embedded in every list response, so
+// clients can tell whether another page exists without doing the arithmetic
+type PageInfo struct {
+	Total      int64 `json:"total"`
+	Limit      int   `json:"limit"`
+	Offset     int   `json:"offset"`
+	HasNext    bool  `json:"has_next"`
+	TotalPages int   `json:"total_pages"`
+}
+
+// NewPageInfo builds the metadata for a page of limit items starting at offset
+// out of total
+func NewPageInfo(total int64, limit, offset int) PageInfo {
+	info := PageInfo{Total: total, Limit: limit, Offset: offset}
+	if limit > 0 {
+		info.TotalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+	info.HasNext = int64(offset)+int64(limit) < total
+	return info
+}
+
+// DefaultMaxBatchItems applies when no BatchLimit middleware is installed
+const DefaultMaxBatchItems = 500
+
+// BatchLimit creates a middleware that records the maximum number of items a
+// batch request may carry. Handlers enforce it with CheckBatchSize.
+func BatchLimit(maxItems int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("batch_max_items", maxItems)
+		c.Next()
+	}
+}
+
+// GetMaxBatchItems returns the batch size limit for the request
+func GetMaxBatchItems(c *gin.Context) int {
+	if maxItems, ok := c.Get("batch_max_items"); ok {
+		return maxItems.(int)
+	}
+	return DefaultMaxBatchItems
+}
+
+// CheckBatchSize responds with 413 and returns false when count exceeds the
+// batch size limit. Call it right after binding, before any processing.
+func CheckBatchSize(c *gin.Context, count int) bool {
+	maxItems := GetMaxBatchItems(c)
+	if count <= maxItems {
+		return true
+	}
+
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error":   "Too many items in batch",
+		"details": fmt.Sprintf("batch has %d items, the limit is %d", count, maxItems),
+	})
+	return false
+}
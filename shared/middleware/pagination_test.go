@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCheckBatchSize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(BatchLimit(2))
+	router.GET("/batch/:count", func(c *gin.Context) {
+		count := len(c.Param("count"))
+		if !CheckBatchSize(c, count) {
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/batch/xx", http.StatusOK},
+		{"/batch/xxx", http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if recorder.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.want, recorder.Code)
+		}
+	}
+}
+
+func TestGetMaxBatchItems_DefaultsWithoutMiddleware(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	if got := GetMaxBatchItems(c); got != DefaultMaxBatchItems {
+		t.Errorf("expected default of %d, got %d", DefaultMaxBatchItems, got)
+	}
+}
+
+func TestNewPageInfo(t *testing.T) {
+	tests := []struct {
+		name          string
+		total         int64
+		limit, offset int
+		hasNext       bool
+		totalPages    int
+	}{
+		{name: "empty", total: 0, limit: 20, offset: 0, hasNext: false, totalPages: 0},
+		{name: "first of several", total: 45, limit: 20, offset: 0, hasNext: true, totalPages: 3},
+		{name: "last partial page", total: 45, limit: 20, offset: 40, hasNext: false, totalPages: 3},
+		{name: "exact fit", total: 40, limit: 20, offset: 20, hasNext: false, totalPages: 2},
+		{name: "past the end", total: 5, limit: 20, offset: 100, hasNext: false, totalPages: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := NewPageInfo(tt.total, tt.limit, tt.offset)
+			if info.HasNext != tt.hasNext {
+				t.Errorf("HasNext = %v, want %v", info.HasNext, tt.hasNext)
+			}
+			if info.TotalPages != tt.totalPages {
+				t.Errorf("TotalPages = %d, want %d", info.TotalPages, tt.totalPages)
+			}
+			if info.Total != tt.total || info.Limit != tt.limit || info.Offset != tt.offset {
+				t.Errorf("unexpected page info %+v", info)
+			}
+		})
+	}
+}
@@ -33,6 +33,9 @@ type Metrics struct {
 	CertificatesIssued *prometheus.CounterVec
 	ReportsGenerated   *prometheus.CounterVec
 
+	// Event metrics
+	ConsumerLag *prometheus.GaugeVec
+
 	// System metrics
 	GoroutinesActive prometheus.Gauge
 	MemoryUsage      prometheus.Gauge
@@ -153,6 +156,15 @@ func NewMetrics(serviceName string) *Metrics {
 			[]string{"service", "type", "format", "status"},
 		),
 
+		// Event metrics
+		ConsumerLag: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "kafka_consumer_lag",
+				Help: "Number of messages a consumer is behind the end of its partition",
+			},
+			[]string{"service", "topic", "partition"},
+		),
+
 		// System metrics
 		GoroutinesActive: promauto.NewGauge(
 			prometheus.GaugeOpts{
@@ -284,6 +296,15 @@ func (m *Metrics) RecordReportGenerated(serviceName, reportType, format, status
 	}).Inc()
 }
 
+// RecordConsumerLag records how far a consumer is behind on a partition
+func (m *Metrics) RecordConsumerLag(serviceName, topic string, partition int, lag int64) {
+	m.ConsumerLag.With(prometheus.Labels{
+		"service":   serviceName,
+		"topic":     topic,
+		"partition": strconv.Itoa(partition),
+	}).Set(float64(lag))
+}
+
 // RecordDBQuery records a database query metric
 func (m *Metrics) RecordDBQuery(serviceName, operation, table, status string, duration time.Duration) {
 	labels := prometheus.Labels{
@@ -0,0 +1,105 @@
+// Package reportdata holds the gRPC contract through which the reporting
+// service collects data from the services that own it. The .pb.go files are
+// generated from proto/reportdata.proto; this file adds helpers shared by
+// the servers and the reporting client.
+package reportdata
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Map keys of per-month and per-day breakdowns
+const (
+	MonthFormat = "2006-01"
+	DayFormat   = "2006-01-02"
+)
+
+// NewPeriodRequest creates a request for a user's data between start and end
+func NewPeriodRequest(userID string, start, end time.Time) *PeriodRequest {
+	return &PeriodRequest{
+		UserId: userID,
+		Start:  timestamppb.New(start),
+		End:    timestamppb.New(end),
+	}
+}
+
+// Period returns the bounds of the request, or an InvalidArgument error
+// when either is missing
+func (r *PeriodRequest) Period() (time.Time, time.Time, error) {
+	if r.GetStart() == nil || r.GetEnd() == nil {
+		return time.Time{}, time.Time{}, status.Error(codes.InvalidArgument, "start and end are required")
+	}
+	return r.GetStart().AsTime(), r.GetEnd().AsTime(), nil
+}
+
+// ScanFeedEntries reads feed entries from rows of id, type, description,
+// amount and occurred_at columns
+func ScanFeedEntries(rows *sql.Rows) (*FeedEntries, error) {
+	entries := &FeedEntries{}
+	for rows.Next() {
+		var entry FeedEntry
+		var amount sql.NullFloat64
+		var occurredAt time.Time
+
+		if err := rows.Scan(&entry.Id, &entry.Type, &entry.Description, &amount, &occurredAt); err != nil {
+			return nil, err
+		}
+
+		entry.Amount = amount.Float64
+		entry.OccurredAt = timestamppb.New(occurredAt)
+		entries.Entries = append(entries.Entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// NewExportRows converts database rows to export rows. Times become RFC 3339
+// strings and byte slices strings, as they would in JSON.
+func NewExportRows(rows []map[string]interface{}) (*ExportRows, error) {
+	export := &ExportRows{Rows: make([]*structpb.Struct, 0, len(rows))}
+	for _, row := range rows {
+		fields := make(map[string]interface{}, len(row))
+		for key, value := range row {
+			switch v := value.(type) {
+			case time.Time:
+				fields[key] = v.Format(time.RFC3339Nano)
+			case *time.Time:
+				if v != nil {
+					fields[key] = v.Format(time.RFC3339Nano)
+				} else {
+					fields[key] = nil
+				}
+			case []byte:
+				fields[key] = string(v)
+			case fmt.Stringer:
+				fields[key] = v.String()
+			default:
+				fields[key] = v
+			}
+		}
+
+		record, err := structpb.NewStruct(fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert export row: %w", err)
+		}
+		export.Rows = append(export.Rows, record)
+	}
+
+	return export, nil
+}
+
+// Maps converts export rows back to plain maps
+func (r *ExportRows) Maps() []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0, len(r.GetRows()))
+	for _, row := range r.GetRows() {
+		rows = append(rows, row.AsMap())
+	}
+	return rows
+}
@@ -0,0 +1,1324 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        v5.29.3
+// source: reportdata.proto
+
+package reportdata
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PeriodRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeriodRequest) Reset() {
+	*x = PeriodRequest{}
+	mi := &file_reportdata_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeriodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeriodRequest) ProtoMessage() {}
+
+func (x *PeriodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeriodRequest.ProtoReflect.Descriptor instead.
+func (*PeriodRequest) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PeriodRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PeriodRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *PeriodRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type UserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserRequest) Reset() {
+	*x = UserRequest{}
+	mi := &file_reportdata_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserRequest) ProtoMessage() {}
+
+func (x *UserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserRequest.ProtoReflect.Descriptor instead.
+func (*UserRequest) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type PlatformTotalsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlatformTotalsRequest) Reset() {
+	*x = PlatformTotalsRequest{}
+	mi := &file_reportdata_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlatformTotalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlatformTotalsRequest) ProtoMessage() {}
+
+func (x *PlatformTotalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlatformTotalsRequest.ProtoReflect.Descriptor instead.
+func (*PlatformTotalsRequest) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{2}
+}
+
+type FeedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Before        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeedRequest) Reset() {
+	*x = FeedRequest{}
+	mi := &file_reportdata_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedRequest) ProtoMessage() {}
+
+func (x *FeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedRequest.ProtoReflect.Descriptor instead.
+func (*FeedRequest) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FeedRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *FeedRequest) GetBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *FeedRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Totals of one activity type
+type ActivityTotal struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActivityType  string                 `protobuf:"bytes,1,opt,name=activity_type,json=activityType,proto3" json:"activity_type,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Co2Kg         float64                `protobuf:"fixed64,3,opt,name=co2_kg,json=co2Kg,proto3" json:"co2_kg,omitempty"`
+	Credits       float64                `protobuf:"fixed64,4,opt,name=credits,proto3" json:"credits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivityTotal) Reset() {
+	*x = ActivityTotal{}
+	mi := &file_reportdata_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivityTotal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivityTotal) ProtoMessage() {}
+
+func (x *ActivityTotal) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivityTotal.ProtoReflect.Descriptor instead.
+func (*ActivityTotal) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ActivityTotal) GetActivityType() string {
+	if x != nil {
+		return x.ActivityType
+	}
+	return ""
+}
+
+func (x *ActivityTotal) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ActivityTotal) GetCo2Kg() float64 {
+	if x != nil {
+		return x.Co2Kg
+	}
+	return 0
+}
+
+func (x *ActivityTotal) GetCredits() float64 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+type FootprintData struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TotalCo2Kg        float64                `protobuf:"fixed64,1,opt,name=total_co2_kg,json=totalCo2Kg,proto3" json:"total_co2_kg,omitempty"`
+	TotalCalculations int64                  `protobuf:"varint,2,opt,name=total_calculations,json=totalCalculations,proto3" json:"total_calculations,omitempty"`
+	ByActivityType    []*ActivityTotal       `protobuf:"bytes,3,rep,name=by_activity_type,json=byActivityType,proto3" json:"by_activity_type,omitempty"` // most CO2 first
+	ByCategory        map[string]float64     `protobuf:"bytes,4,rep,name=by_category,json=byCategory,proto3" json:"by_category,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	ByMonth           map[string]float64     `protobuf:"bytes,5,rep,name=by_month,json=byMonth,proto3" json:"by_month,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`                              // keyed by month, e.g. 2024-01
+	DailyCalculations map[string]int64       `protobuf:"bytes,6,rep,name=daily_calculations,json=dailyCalculations,proto3" json:"daily_calculations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // keyed by UTC day, e.g. 2024-01-31
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *FootprintData) Reset() {
+	*x = FootprintData{}
+	mi := &file_reportdata_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FootprintData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FootprintData) ProtoMessage() {}
+
+func (x *FootprintData) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FootprintData.ProtoReflect.Descriptor instead.
+func (*FootprintData) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FootprintData) GetTotalCo2Kg() float64 {
+	if x != nil {
+		return x.TotalCo2Kg
+	}
+	return 0
+}
+
+func (x *FootprintData) GetTotalCalculations() int64 {
+	if x != nil {
+		return x.TotalCalculations
+	}
+	return 0
+}
+
+func (x *FootprintData) GetByActivityType() []*ActivityTotal {
+	if x != nil {
+		return x.ByActivityType
+	}
+	return nil
+}
+
+func (x *FootprintData) GetByCategory() map[string]float64 {
+	if x != nil {
+		return x.ByCategory
+	}
+	return nil
+}
+
+func (x *FootprintData) GetByMonth() map[string]float64 {
+	if x != nil {
+		return x.ByMonth
+	}
+	return nil
+}
+
+func (x *FootprintData) GetDailyCalculations() map[string]int64 {
+	if x != nil {
+		return x.DailyCalculations
+	}
+	return nil
+}
+
+type AverageUserFootprint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AverageCo2Kg  float64                `protobuf:"fixed64,1,opt,name=average_co2_kg,json=averageCo2Kg,proto3" json:"average_co2_kg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AverageUserFootprint) Reset() {
+	*x = AverageUserFootprint{}
+	mi := &file_reportdata_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AverageUserFootprint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AverageUserFootprint) ProtoMessage() {}
+
+func (x *AverageUserFootprint) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AverageUserFootprint.ProtoReflect.Descriptor instead.
+func (*AverageUserFootprint) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AverageUserFootprint) GetAverageCo2Kg() float64 {
+	if x != nil {
+		return x.AverageCo2Kg
+	}
+	return 0
+}
+
+type CalculatorPlatformTotals struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TotalCo2Kg        float64                `protobuf:"fixed64,1,opt,name=total_co2_kg,json=totalCo2Kg,proto3" json:"total_co2_kg,omitempty"`
+	TotalCalculations int64                  `protobuf:"varint,2,opt,name=total_calculations,json=totalCalculations,proto3" json:"total_calculations,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CalculatorPlatformTotals) Reset() {
+	*x = CalculatorPlatformTotals{}
+	mi := &file_reportdata_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculatorPlatformTotals) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculatorPlatformTotals) ProtoMessage() {}
+
+func (x *CalculatorPlatformTotals) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculatorPlatformTotals.ProtoReflect.Descriptor instead.
+func (*CalculatorPlatformTotals) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CalculatorPlatformTotals) GetTotalCo2Kg() float64 {
+	if x != nil {
+		return x.TotalCo2Kg
+	}
+	return 0
+}
+
+func (x *CalculatorPlatformTotals) GetTotalCalculations() int64 {
+	if x != nil {
+		return x.TotalCalculations
+	}
+	return 0
+}
+
+type ActivitySummaryData struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	TotalActivities      int64                  `protobuf:"varint,1,opt,name=total_activities,json=totalActivities,proto3" json:"total_activities,omitempty"`
+	TopEarningActivities []*ActivityTotal       `protobuf:"bytes,2,rep,name=top_earning_activities,json=topEarningActivities,proto3" json:"top_earning_activities,omitempty"`                                                           // verified activities, most credits first
+	DailyActivities      map[string]int64       `protobuf:"bytes,3,rep,name=daily_activities,json=dailyActivities,proto3" json:"daily_activities,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // keyed by UTC day, e.g. 2024-01-31
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ActivitySummaryData) Reset() {
+	*x = ActivitySummaryData{}
+	mi := &file_reportdata_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivitySummaryData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivitySummaryData) ProtoMessage() {}
+
+func (x *ActivitySummaryData) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivitySummaryData.ProtoReflect.Descriptor instead.
+func (*ActivitySummaryData) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ActivitySummaryData) GetTotalActivities() int64 {
+	if x != nil {
+		return x.TotalActivities
+	}
+	return 0
+}
+
+func (x *ActivitySummaryData) GetTopEarningActivities() []*ActivityTotal {
+	if x != nil {
+		return x.TopEarningActivities
+	}
+	return nil
+}
+
+func (x *ActivitySummaryData) GetDailyActivities() map[string]int64 {
+	if x != nil {
+		return x.DailyActivities
+	}
+	return nil
+}
+
+type FeedEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // the activity type name for tracker, the transaction type for wallet
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Amount        float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeedEntry) Reset() {
+	*x = FeedEntry{}
+	mi := &file_reportdata_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeedEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedEntry) ProtoMessage() {}
+
+func (x *FeedEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedEntry.ProtoReflect.Descriptor instead.
+func (*FeedEntry) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FeedEntry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FeedEntry) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *FeedEntry) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *FeedEntry) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *FeedEntry) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+type FeedEntries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*FeedEntry           `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeedEntries) Reset() {
+	*x = FeedEntries{}
+	mi := &file_reportdata_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeedEntries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedEntries) ProtoMessage() {}
+
+func (x *FeedEntries) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedEntries.ProtoReflect.Descriptor instead.
+func (*FeedEntries) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FeedEntries) GetEntries() []*FeedEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// Completed transactions of one source and type
+type TransactionGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        *string                `protobuf:"bytes,1,opt,name=source,proto3,oneof" json:"source,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Count         int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Amount        float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionGroup) Reset() {
+	*x = TransactionGroup{}
+	mi := &file_reportdata_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionGroup) ProtoMessage() {}
+
+func (x *TransactionGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionGroup.ProtoReflect.Descriptor instead.
+func (*TransactionGroup) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TransactionGroup) GetSource() string {
+	if x != nil && x.Source != nil {
+		return *x.Source
+	}
+	return ""
+}
+
+func (x *TransactionGroup) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TransactionGroup) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *TransactionGroup) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type TransactionRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Amount        float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Counterparty  string                 `protobuf:"bytes,5,opt,name=counterparty,proto3" json:"counterparty,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionRecord) Reset() {
+	*x = TransactionRecord{}
+	mi := &file_reportdata_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionRecord) ProtoMessage() {}
+
+func (x *TransactionRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionRecord.ProtoReflect.Descriptor instead.
+func (*TransactionRecord) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TransactionRecord) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TransactionRecord) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TransactionRecord) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *TransactionRecord) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TransactionRecord) GetCounterparty() string {
+	if x != nil {
+		return x.Counterparty
+	}
+	return ""
+}
+
+func (x *TransactionRecord) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreditsData struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	CurrentBalance     float64                `protobuf:"fixed64,1,opt,name=current_balance,json=currentBalance,proto3" json:"current_balance,omitempty"`
+	TotalEarned        float64                `protobuf:"fixed64,2,opt,name=total_earned,json=totalEarned,proto3" json:"total_earned,omitempty"`
+	TotalSpent         float64                `protobuf:"fixed64,3,opt,name=total_spent,json=totalSpent,proto3" json:"total_spent,omitempty"`
+	TransactionGroups  []*TransactionGroup    `protobuf:"bytes,4,rep,name=transaction_groups,json=transactionGroups,proto3" json:"transaction_groups,omitempty"`
+	EarnedByMonth      map[string]float64     `protobuf:"bytes,5,rep,name=earned_by_month,json=earnedByMonth,proto3" json:"earned_by_month,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"` // keyed by month, e.g. 2024-01
+	RecentTransactions []*TransactionRecord   `protobuf:"bytes,6,rep,name=recent_transactions,json=recentTransactions,proto3" json:"recent_transactions,omitempty"`                                                                // newest first
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CreditsData) Reset() {
+	*x = CreditsData{}
+	mi := &file_reportdata_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreditsData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreditsData) ProtoMessage() {}
+
+func (x *CreditsData) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreditsData.ProtoReflect.Descriptor instead.
+func (*CreditsData) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreditsData) GetCurrentBalance() float64 {
+	if x != nil {
+		return x.CurrentBalance
+	}
+	return 0
+}
+
+func (x *CreditsData) GetTotalEarned() float64 {
+	if x != nil {
+		return x.TotalEarned
+	}
+	return 0
+}
+
+func (x *CreditsData) GetTotalSpent() float64 {
+	if x != nil {
+		return x.TotalSpent
+	}
+	return 0
+}
+
+func (x *CreditsData) GetTransactionGroups() []*TransactionGroup {
+	if x != nil {
+		return x.TransactionGroups
+	}
+	return nil
+}
+
+func (x *CreditsData) GetEarnedByMonth() map[string]float64 {
+	if x != nil {
+		return x.EarnedByMonth
+	}
+	return nil
+}
+
+func (x *CreditsData) GetRecentTransactions() []*TransactionRecord {
+	if x != nil {
+		return x.RecentTransactions
+	}
+	return nil
+}
+
+type WalletPlatformTotals struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	TotalCreditsEarned float64                `protobuf:"fixed64,1,opt,name=total_credits_earned,json=totalCreditsEarned,proto3" json:"total_credits_earned,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *WalletPlatformTotals) Reset() {
+	*x = WalletPlatformTotals{}
+	mi := &file_reportdata_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletPlatformTotals) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletPlatformTotals) ProtoMessage() {}
+
+func (x *WalletPlatformTotals) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletPlatformTotals.ProtoReflect.Descriptor instead.
+func (*WalletPlatformTotals) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WalletPlatformTotals) GetTotalCreditsEarned() float64 {
+	if x != nil {
+		return x.TotalCreditsEarned
+	}
+	return 0
+}
+
+type ExportRows struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          []*structpb.Struct     `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRows) Reset() {
+	*x = ExportRows{}
+	mi := &file_reportdata_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRows) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRows) ProtoMessage() {}
+
+func (x *ExportRows) ProtoReflect() protoreflect.Message {
+	mi := &file_reportdata_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRows.ProtoReflect.Descriptor instead.
+func (*ExportRows) Descriptor() ([]byte, []int) {
+	return file_reportdata_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExportRows) GetRows() []*structpb.Struct {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+var File_reportdata_proto protoreflect.FileDescriptor
+
+var file_reportdata_proto_rawDesc = string([]byte{
+	0x0a, 0x10, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x1c,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x88, 0x01,
+	0x0a, 0x0d, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x2c, 0x0a, 0x03, 0x65, 0x6e,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x26, 0x0a, 0x0b, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64,
+	0x22, 0x17, 0x0a, 0x15, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x54, 0x6f, 0x74, 0x61,
+	0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x70, 0x0a, 0x0b, 0x46, 0x65, 0x65,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x64, 0x12, 0x32, 0x0a, 0x06, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x62,
+	0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x7b, 0x0a, 0x0d, 0x41,
+	0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x23, 0x0a, 0x0d,
+	0x61, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x63, 0x6f, 0x32, 0x5f, 0x6b,
+	0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x63, 0x6f, 0x32, 0x4b, 0x67, 0x12, 0x18,
+	0x0a, 0x07, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x07, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x73, 0x22, 0xd6, 0x04, 0x0a, 0x0d, 0x46, 0x6f, 0x6f,
+	0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x20, 0x0a, 0x0c, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x32, 0x5f, 0x6b, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x32, 0x4b, 0x67, 0x12, 0x2d, 0x0a, 0x12,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43,
+	0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x43, 0x0a, 0x10, 0x62,
+	0x79, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61,
+	0x74, 0x61, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x54, 0x6f, 0x74, 0x61, 0x6c,
+	0x52, 0x0e, 0x62, 0x79, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x4a, 0x0a, 0x0b, 0x62, 0x79, 0x5f, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61,
+	0x74, 0x61, 0x2e, 0x46, 0x6f, 0x6f, 0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61,
+	0x2e, 0x42, 0x79, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0a, 0x62, 0x79, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x41, 0x0a, 0x08,
+	0x62, 0x79, 0x5f, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26,
+	0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x46, 0x6f, 0x6f, 0x74,
+	0x70, 0x72, 0x69, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x2e, 0x42, 0x79, 0x4d, 0x6f, 0x6e, 0x74,
+	0x68, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x62, 0x79, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x12,
+	0x5f, 0x0a, 0x12, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x5f, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x72, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x46, 0x6f, 0x6f, 0x74, 0x70, 0x72, 0x69,
+	0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x69, 0x6c, 0x79, 0x43, 0x61, 0x6c, 0x63,
+	0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x11, 0x64,
+	0x61, 0x69, 0x6c, 0x79, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x1a, 0x3d, 0x0a, 0x0f, 0x42, 0x79, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a,
+	0x3a, 0x0a, 0x0c, 0x42, 0x79, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x44, 0x0a, 0x16, 0x44,
+	0x61, 0x69, 0x6c, 0x79, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x3c, 0x0a, 0x14, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x55, 0x73, 0x65, 0x72,
+	0x46, 0x6f, 0x6f, 0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x61, 0x76, 0x65,
+	0x72, 0x61, 0x67, 0x65, 0x5f, 0x63, 0x6f, 0x32, 0x5f, 0x6b, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0c, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x32, 0x4b, 0x67, 0x22,
+	0x6b, 0x0a, 0x18, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x50, 0x6c, 0x61,
+	0x74, 0x66, 0x6f, 0x72, 0x6d, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x73, 0x12, 0x20, 0x0a, 0x0c, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x32, 0x5f, 0x6b, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x32, 0x4b, 0x67, 0x12, 0x2d, 0x0a,
+	0x12, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xb6, 0x02, 0x0a,
+	0x13, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x29, 0x0a, 0x10, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x63,
+	0x74, 0x69, 0x76, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12,
+	0x4f, 0x0a, 0x16, 0x74, 0x6f, 0x70, 0x5f, 0x65, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x61,
+	0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x41, 0x63, 0x74,
+	0x69, 0x76, 0x69, 0x74, 0x79, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x14, 0x74, 0x6f, 0x70, 0x45,
+	0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x12, 0x5f, 0x0a, 0x10, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x72, 0x65, 0x70,
+	0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79,
+	0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x44, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x69, 0x6c,
+	0x79, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x69, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0f, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x1a, 0x42, 0x0a, 0x14, 0x44, 0x61, 0x69, 0x6c, 0x79, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xa6, 0x01, 0x0a, 0x09, 0x46, 0x65, 0x65, 0x64, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x3b, 0x0a, 0x0b, 0x6f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0a, 0x6f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65, 0x64, 0x41, 0x74, 0x22, 0x3e,
+	0x0a, 0x0b, 0x46, 0x65, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x2f, 0x0a,
+	0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x46, 0x65, 0x65, 0x64,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x7c,
+	0x0a, 0x10, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x12, 0x1b, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0xd0, 0x01, 0x0a,
+	0x11, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x20,
+	0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x22, 0x0a, 0x0c, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x61, 0x72, 0x74, 0x79,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x70,
+	0x61, 0x72, 0x74, 0x79, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22,
+	0xad, 0x03, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x64, 0x69, 0x74, 0x73, 0x44, 0x61, 0x74, 0x61, 0x12,
+	0x27, 0x0a, 0x0f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x5f, 0x65, 0x61, 0x72, 0x6e, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x45, 0x61, 0x72, 0x6e, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x70, 0x65, 0x6e, 0x74, 0x12, 0x4b, 0x0a, 0x12,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72,
+	0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x52, 0x0a, 0x0f, 0x65, 0x61, 0x72,
+	0x6e, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x5f, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e,
+	0x43, 0x72, 0x65, 0x64, 0x69, 0x74, 0x73, 0x44, 0x61, 0x74, 0x61, 0x2e, 0x45, 0x61, 0x72, 0x6e,
+	0x65, 0x64, 0x42, 0x79, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d,
+	0x65, 0x61, 0x72, 0x6e, 0x65, 0x64, 0x42, 0x79, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x12, 0x4e, 0x0a,
+	0x13, 0x72, 0x65, 0x63, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x72, 0x65, 0x70,
+	0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x12, 0x72, 0x65, 0x63, 0x65, 0x6e,
+	0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x40, 0x0a,
+	0x12, 0x45, 0x61, 0x72, 0x6e, 0x65, 0x64, 0x42, 0x79, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0x48, 0x0a, 0x14, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72,
+	0x6d, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x73, 0x5f, 0x65, 0x61, 0x72, 0x6e, 0x65, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x72, 0x65, 0x64,
+	0x69, 0x74, 0x73, 0x45, 0x61, 0x72, 0x6e, 0x65, 0x64, 0x22, 0x39, 0x0a, 0x0a, 0x45, 0x78, 0x70,
+	0x6f, 0x72, 0x74, 0x52, 0x6f, 0x77, 0x73, 0x12, 0x2b, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x04,
+	0x72, 0x6f, 0x77, 0x73, 0x32, 0xd3, 0x02, 0x0a, 0x0e, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61,
+	0x74, 0x6f, 0x72, 0x44, 0x61, 0x74, 0x61, 0x12, 0x44, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x46, 0x6f,
+	0x6f, 0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x12, 0x19, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x64, 0x61, 0x74, 0x61, 0x2e, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e,
+	0x46, 0x6f, 0x6f, 0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x56, 0x0a,
+	0x17, 0x47, 0x65, 0x74, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x55, 0x73, 0x65, 0x72, 0x46,
+	0x6f, 0x6f, 0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x12, 0x19, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72,
+	0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61,
+	0x2e, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x55, 0x73, 0x65, 0x72, 0x46, 0x6f, 0x6f, 0x74,
+	0x70, 0x72, 0x69, 0x6e, 0x74, 0x12, 0x5c, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x61, 0x74,
+	0x66, 0x6f, 0x72, 0x6d, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x73, 0x12, 0x21, 0x2e, 0x72, 0x65, 0x70,
+	0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d,
+	0x54, 0x6f, 0x74, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e,
+	0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x75,
+	0x6c, 0x61, 0x74, 0x6f, 0x72, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x54, 0x6f, 0x74,
+	0x61, 0x6c, 0x73, 0x12, 0x45, 0x0a, 0x12, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x61, 0x6c,
+	0x63, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x17, 0x2e, 0x72, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e,
+	0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x6f, 0x77, 0x73, 0x32, 0xe9, 0x01, 0x0a, 0x0b, 0x54,
+	0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x12, 0x50, 0x0a, 0x12, 0x47, 0x65,
+	0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79,
+	0x12, 0x19, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x50, 0x65,
+	0x72, 0x69, 0x6f, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x72, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74,
+	0x79, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x44, 0x61, 0x74, 0x61, 0x12, 0x43, 0x0a, 0x0f,
+	0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12,
+	0x17, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x46, 0x65, 0x65,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72,
+	0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65,
+	0x73, 0x12, 0x43, 0x0a, 0x10, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x17, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61,
+	0x74, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x52, 0x6f, 0x77, 0x73, 0x32, 0xb4, 0x02, 0x0a, 0x0a, 0x57, 0x61, 0x6c, 0x6c, 0x65,
+	0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x40, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x43, 0x72, 0x65, 0x64,
+	0x69, 0x74, 0x73, 0x12, 0x19, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61,
+	0x2e, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17,
+	0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x43, 0x72, 0x65, 0x64,
+	0x69, 0x74, 0x73, 0x44, 0x61, 0x74, 0x61, 0x12, 0x58, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x50, 0x6c,
+	0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x73, 0x12, 0x21, 0x2e, 0x72,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f,
+	0x72, 0x6d, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x20, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x57, 0x61, 0x6c,
+	0x6c, 0x65, 0x74, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x54, 0x6f, 0x74, 0x61, 0x6c,
+	0x73, 0x12, 0x43, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x65, 0x64, 0x45, 0x6e, 0x74,
+	0x72, 0x69, 0x65, 0x73, 0x12, 0x17, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74,
+	0x61, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e,
+	0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x45,
+	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x45, 0x0a, 0x12, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x17, 0x2e, 0x72,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x64, 0x61,
+	0x74, 0x61, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x6f, 0x77, 0x73, 0x42, 0x39, 0x5a,
+	0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x6c, 0x6f, 0x77,
+	0x65, 0x79, 0x79, 0x79, 0x2f, 0x47, 0x72, 0x65, 0x65, 0x6e, 0x4c, 0x65, 0x64, 0x67, 0x65, 0x72,
+	0x2f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x64, 0x61, 0x74, 0x61, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_reportdata_proto_rawDescOnce sync.Once
+	file_reportdata_proto_rawDescData []byte
+)
+
+func file_reportdata_proto_rawDescGZIP() []byte {
+	file_reportdata_proto_rawDescOnce.Do(func() {
+		file_reportdata_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reportdata_proto_rawDesc), len(file_reportdata_proto_rawDesc)))
+	})
+	return file_reportdata_proto_rawDescData
+}
+
+var file_reportdata_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_reportdata_proto_goTypes = []any{
+	(*PeriodRequest)(nil),            // 0: reportdata.PeriodRequest
+	(*UserRequest)(nil),              // 1: reportdata.UserRequest
+	(*PlatformTotalsRequest)(nil),    // 2: reportdata.PlatformTotalsRequest
+	(*FeedRequest)(nil),              // 3: reportdata.FeedRequest
+	(*ActivityTotal)(nil),            // 4: reportdata.ActivityTotal
+	(*FootprintData)(nil),            // 5: reportdata.FootprintData
+	(*AverageUserFootprint)(nil),     // 6: reportdata.AverageUserFootprint
+	(*CalculatorPlatformTotals)(nil), // 7: reportdata.CalculatorPlatformTotals
+	(*ActivitySummaryData)(nil),      // 8: reportdata.ActivitySummaryData
+	(*FeedEntry)(nil),                // 9: reportdata.FeedEntry
+	(*FeedEntries)(nil),              // 10: reportdata.FeedEntries
+	(*TransactionGroup)(nil),         // 11: reportdata.TransactionGroup
+	(*TransactionRecord)(nil),        // 12: reportdata.TransactionRecord
+	(*CreditsData)(nil),              // 13: reportdata.CreditsData
+	(*WalletPlatformTotals)(nil),     // 14: reportdata.WalletPlatformTotals
+	(*ExportRows)(nil),               // 15: reportdata.ExportRows
+	nil,                              // 16: reportdata.FootprintData.ByCategoryEntry
+	nil,                              // 17: reportdata.FootprintData.ByMonthEntry
+	nil,                              // 18: reportdata.FootprintData.DailyCalculationsEntry
+	nil,                              // 19: reportdata.ActivitySummaryData.DailyActivitiesEntry
+	nil,                              // 20: reportdata.CreditsData.EarnedByMonthEntry
+	(*timestamppb.Timestamp)(nil),    // 21: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),          // 22: google.protobuf.Struct
+}
+var file_reportdata_proto_depIdxs = []int32{
+	21, // 0: reportdata.PeriodRequest.start:type_name -> google.protobuf.Timestamp
+	21, // 1: reportdata.PeriodRequest.end:type_name -> google.protobuf.Timestamp
+	21, // 2: reportdata.FeedRequest.before:type_name -> google.protobuf.Timestamp
+	4,  // 3: reportdata.FootprintData.by_activity_type:type_name -> reportdata.ActivityTotal
+	16, // 4: reportdata.FootprintData.by_category:type_name -> reportdata.FootprintData.ByCategoryEntry
+	17, // 5: reportdata.FootprintData.by_month:type_name -> reportdata.FootprintData.ByMonthEntry
+	18, // 6: reportdata.FootprintData.daily_calculations:type_name -> reportdata.FootprintData.DailyCalculationsEntry
+	4,  // 7: reportdata.ActivitySummaryData.top_earning_activities:type_name -> reportdata.ActivityTotal
+	19, // 8: reportdata.ActivitySummaryData.daily_activities:type_name -> reportdata.ActivitySummaryData.DailyActivitiesEntry
+	21, // 9: reportdata.FeedEntry.occurred_at:type_name -> google.protobuf.Timestamp
+	9,  // 10: reportdata.FeedEntries.entries:type_name -> reportdata.FeedEntry
+	21, // 11: reportdata.TransactionRecord.created_at:type_name -> google.protobuf.Timestamp
+	11, // 12: reportdata.CreditsData.transaction_groups:type_name -> reportdata.TransactionGroup
+	20, // 13: reportdata.CreditsData.earned_by_month:type_name -> reportdata.CreditsData.EarnedByMonthEntry
+	12, // 14: reportdata.CreditsData.recent_transactions:type_name -> reportdata.TransactionRecord
+	22, // 15: reportdata.ExportRows.rows:type_name -> google.protobuf.Struct
+	0,  // 16: reportdata.CalculatorData.GetFootprint:input_type -> reportdata.PeriodRequest
+	0,  // 17: reportdata.CalculatorData.GetAverageUserFootprint:input_type -> reportdata.PeriodRequest
+	2,  // 18: reportdata.CalculatorData.GetPlatformTotals:input_type -> reportdata.PlatformTotalsRequest
+	1,  // 19: reportdata.CalculatorData.ExportCalculations:input_type -> reportdata.UserRequest
+	0,  // 20: reportdata.TrackerData.GetActivitySummary:input_type -> reportdata.PeriodRequest
+	3,  // 21: reportdata.TrackerData.ListFeedEntries:input_type -> reportdata.FeedRequest
+	1,  // 22: reportdata.TrackerData.ExportActivities:input_type -> reportdata.UserRequest
+	0,  // 23: reportdata.WalletData.GetCredits:input_type -> reportdata.PeriodRequest
+	2,  // 24: reportdata.WalletData.GetPlatformTotals:input_type -> reportdata.PlatformTotalsRequest
+	3,  // 25: reportdata.WalletData.ListFeedEntries:input_type -> reportdata.FeedRequest
+	1,  // 26: reportdata.WalletData.ExportTransactions:input_type -> reportdata.UserRequest
+	5,  // 27: reportdata.CalculatorData.GetFootprint:output_type -> reportdata.FootprintData
+	6,  // 28: reportdata.CalculatorData.GetAverageUserFootprint:output_type -> reportdata.AverageUserFootprint
+	7,  // 29: reportdata.CalculatorData.GetPlatformTotals:output_type -> reportdata.CalculatorPlatformTotals
+	15, // 30: reportdata.CalculatorData.ExportCalculations:output_type -> reportdata.ExportRows
+	8,  // 31: reportdata.TrackerData.GetActivitySummary:output_type -> reportdata.ActivitySummaryData
+	10, // 32: reportdata.TrackerData.ListFeedEntries:output_type -> reportdata.FeedEntries
+	15, // 33: reportdata.TrackerData.ExportActivities:output_type -> reportdata.ExportRows
+	13, // 34: reportdata.WalletData.GetCredits:output_type -> reportdata.CreditsData
+	14, // 35: reportdata.WalletData.GetPlatformTotals:output_type -> reportdata.WalletPlatformTotals
+	10, // 36: reportdata.WalletData.ListFeedEntries:output_type -> reportdata.FeedEntries
+	15, // 37: reportdata.WalletData.ExportTransactions:output_type -> reportdata.ExportRows
+	27, // [27:38] is the sub-list for method output_type
+	16, // [16:27] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_reportdata_proto_init() }
+func file_reportdata_proto_init() {
+	if File_reportdata_proto != nil {
+		return
+	}
+	file_reportdata_proto_msgTypes[11].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reportdata_proto_rawDesc), len(file_reportdata_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   21,
+			NumExtensions: 0,
+			NumServices:   3,
+		},
+		GoTypes:           file_reportdata_proto_goTypes,
+		DependencyIndexes: file_reportdata_proto_depIdxs,
+		MessageInfos:      file_reportdata_proto_msgTypes,
+	}.Build()
+	File_reportdata_proto = out.File
+	file_reportdata_proto_goTypes = nil
+	file_reportdata_proto_depIdxs = nil
+}
@@ -0,0 +1,663 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: reportdata.proto
+
+package reportdata
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CalculatorData_GetFootprint_FullMethodName            = "/reportdata.CalculatorData/GetFootprint"
+	CalculatorData_GetAverageUserFootprint_FullMethodName = "/reportdata.CalculatorData/GetAverageUserFootprint"
+	CalculatorData_GetPlatformTotals_FullMethodName       = "/reportdata.CalculatorData/GetPlatformTotals"
+	CalculatorData_ExportCalculations_FullMethodName      = "/reportdata.CalculatorData/ExportCalculations"
+)
+
+// CalculatorDataClient is the client API for CalculatorData service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Calculator data for reports
+type CalculatorDataClient interface {
+	// Totals and breakdowns of a user's calculations over a period
+	GetFootprint(ctx context.Context, in *PeriodRequest, opts ...grpc.CallOption) (*FootprintData, error)
+	// Average total CO2 of the users with calculations in a period; user_id is ignored
+	GetAverageUserFootprint(ctx context.Context, in *PeriodRequest, opts ...grpc.CallOption) (*AverageUserFootprint, error)
+	// Totals across every user's calculations
+	GetPlatformTotals(ctx context.Context, in *PlatformTotalsRequest, opts ...grpc.CallOption) (*CalculatorPlatformTotals, error)
+	// Every calculation of a user, one row per activity, for data exports
+	ExportCalculations(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*ExportRows, error)
+}
+
+type calculatorDataClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCalculatorDataClient(cc grpc.ClientConnInterface) CalculatorDataClient {
+	return &calculatorDataClient{cc}
+}
+
+func (c *calculatorDataClient) GetFootprint(ctx context.Context, in *PeriodRequest, opts ...grpc.CallOption) (*FootprintData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FootprintData)
+	err := c.cc.Invoke(ctx, CalculatorData_GetFootprint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorDataClient) GetAverageUserFootprint(ctx context.Context, in *PeriodRequest, opts ...grpc.CallOption) (*AverageUserFootprint, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AverageUserFootprint)
+	err := c.cc.Invoke(ctx, CalculatorData_GetAverageUserFootprint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorDataClient) GetPlatformTotals(ctx context.Context, in *PlatformTotalsRequest, opts ...grpc.CallOption) (*CalculatorPlatformTotals, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CalculatorPlatformTotals)
+	err := c.cc.Invoke(ctx, CalculatorData_GetPlatformTotals_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorDataClient) ExportCalculations(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*ExportRows, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportRows)
+	err := c.cc.Invoke(ctx, CalculatorData_ExportCalculations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CalculatorDataServer is the server API for CalculatorData service.
+// All implementations must embed UnimplementedCalculatorDataServer
+// for forward compatibility.
+//
+// Calculator data for reports
+type CalculatorDataServer interface {
+	// Totals and breakdowns of a user's calculations over a period
+	GetFootprint(context.Context, *PeriodRequest) (*FootprintData, error)
+	// Average total CO2 of the users with calculations in a period; user_id is ignored
+	GetAverageUserFootprint(context.Context, *PeriodRequest) (*AverageUserFootprint, error)
+	// Totals across every user's calculations
+	GetPlatformTotals(context.Context, *PlatformTotalsRequest) (*CalculatorPlatformTotals, error)
+	// Every calculation of a user, one row per activity, for data exports
+	ExportCalculations(context.Context, *UserRequest) (*ExportRows, error)
+	mustEmbedUnimplementedCalculatorDataServer()
+}
+
+// UnimplementedCalculatorDataServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCalculatorDataServer struct{}
+
+func (UnimplementedCalculatorDataServer) GetFootprint(context.Context, *PeriodRequest) (*FootprintData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFootprint not implemented")
+}
+func (UnimplementedCalculatorDataServer) GetAverageUserFootprint(context.Context, *PeriodRequest) (*AverageUserFootprint, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAverageUserFootprint not implemented")
+}
+func (UnimplementedCalculatorDataServer) GetPlatformTotals(context.Context, *PlatformTotalsRequest) (*CalculatorPlatformTotals, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlatformTotals not implemented")
+}
+func (UnimplementedCalculatorDataServer) ExportCalculations(context.Context, *UserRequest) (*ExportRows, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportCalculations not implemented")
+}
+func (UnimplementedCalculatorDataServer) mustEmbedUnimplementedCalculatorDataServer() {}
+func (UnimplementedCalculatorDataServer) testEmbeddedByValue()                        {}
+
+// UnsafeCalculatorDataServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CalculatorDataServer will
+// result in compilation errors.
+type UnsafeCalculatorDataServer interface {
+	mustEmbedUnimplementedCalculatorDataServer()
+}
+
+func RegisterCalculatorDataServer(s grpc.ServiceRegistrar, srv CalculatorDataServer) {
+	// If the following call pancis, it indicates UnimplementedCalculatorDataServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CalculatorData_ServiceDesc, srv)
+}
+
+func _CalculatorData_GetFootprint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeriodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorDataServer).GetFootprint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorData_GetFootprint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorDataServer).GetFootprint(ctx, req.(*PeriodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorData_GetAverageUserFootprint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeriodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorDataServer).GetAverageUserFootprint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorData_GetAverageUserFootprint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorDataServer).GetAverageUserFootprint(ctx, req.(*PeriodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorData_GetPlatformTotals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlatformTotalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorDataServer).GetPlatformTotals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorData_GetPlatformTotals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorDataServer).GetPlatformTotals(ctx, req.(*PlatformTotalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorData_ExportCalculations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorDataServer).ExportCalculations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorData_ExportCalculations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorDataServer).ExportCalculations(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CalculatorData_ServiceDesc is the grpc.ServiceDesc for CalculatorData service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CalculatorData_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "reportdata.CalculatorData",
+	HandlerType: (*CalculatorDataServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFootprint",
+			Handler:    _CalculatorData_GetFootprint_Handler,
+		},
+		{
+			MethodName: "GetAverageUserFootprint",
+			Handler:    _CalculatorData_GetAverageUserFootprint_Handler,
+		},
+		{
+			MethodName: "GetPlatformTotals",
+			Handler:    _CalculatorData_GetPlatformTotals_Handler,
+		},
+		{
+			MethodName: "ExportCalculations",
+			Handler:    _CalculatorData_ExportCalculations_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reportdata.proto",
+}
+
+const (
+	TrackerData_GetActivitySummary_FullMethodName = "/reportdata.TrackerData/GetActivitySummary"
+	TrackerData_ListFeedEntries_FullMethodName    = "/reportdata.TrackerData/ListFeedEntries"
+	TrackerData_ExportActivities_FullMethodName   = "/reportdata.TrackerData/ExportActivities"
+)
+
+// TrackerDataClient is the client API for TrackerData service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Tracker data for reports
+type TrackerDataClient interface {
+	// Counts and top credit earners of a user's eco activities over a period
+	GetActivitySummary(ctx context.Context, in *PeriodRequest, opts ...grpc.CallOption) (*ActivitySummaryData, error)
+	// A user's most recent eco activities before a time, newest first
+	ListFeedEntries(ctx context.Context, in *FeedRequest, opts ...grpc.CallOption) (*FeedEntries, error)
+	// Every eco activity of a user, for data exports
+	ExportActivities(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*ExportRows, error)
+}
+
+type trackerDataClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTrackerDataClient(cc grpc.ClientConnInterface) TrackerDataClient {
+	return &trackerDataClient{cc}
+}
+
+func (c *trackerDataClient) GetActivitySummary(ctx context.Context, in *PeriodRequest, opts ...grpc.CallOption) (*ActivitySummaryData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActivitySummaryData)
+	err := c.cc.Invoke(ctx, TrackerData_GetActivitySummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerDataClient) ListFeedEntries(ctx context.Context, in *FeedRequest, opts ...grpc.CallOption) (*FeedEntries, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FeedEntries)
+	err := c.cc.Invoke(ctx, TrackerData_ListFeedEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerDataClient) ExportActivities(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*ExportRows, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportRows)
+	err := c.cc.Invoke(ctx, TrackerData_ExportActivities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TrackerDataServer is the server API for TrackerData service.
+// All implementations must embed UnimplementedTrackerDataServer
+// for forward compatibility.
+//
+// Tracker data for reports
+type TrackerDataServer interface {
+	// Counts and top credit earners of a user's eco activities over a period
+	GetActivitySummary(context.Context, *PeriodRequest) (*ActivitySummaryData, error)
+	// A user's most recent eco activities before a time, newest first
+	ListFeedEntries(context.Context, *FeedRequest) (*FeedEntries, error)
+	// Every eco activity of a user, for data exports
+	ExportActivities(context.Context, *UserRequest) (*ExportRows, error)
+	mustEmbedUnimplementedTrackerDataServer()
+}
+
+// UnimplementedTrackerDataServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTrackerDataServer struct{}
+
+func (UnimplementedTrackerDataServer) GetActivitySummary(context.Context, *PeriodRequest) (*ActivitySummaryData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActivitySummary not implemented")
+}
+func (UnimplementedTrackerDataServer) ListFeedEntries(context.Context, *FeedRequest) (*FeedEntries, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFeedEntries not implemented")
+}
+func (UnimplementedTrackerDataServer) ExportActivities(context.Context, *UserRequest) (*ExportRows, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportActivities not implemented")
+}
+func (UnimplementedTrackerDataServer) mustEmbedUnimplementedTrackerDataServer() {}
+func (UnimplementedTrackerDataServer) testEmbeddedByValue()                     {}
+
+// UnsafeTrackerDataServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TrackerDataServer will
+// result in compilation errors.
+type UnsafeTrackerDataServer interface {
+	mustEmbedUnimplementedTrackerDataServer()
+}
+
+func RegisterTrackerDataServer(s grpc.ServiceRegistrar, srv TrackerDataServer) {
+	// If the following call pancis, it indicates UnimplementedTrackerDataServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TrackerData_ServiceDesc, srv)
+}
+
+func _TrackerData_GetActivitySummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeriodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerDataServer).GetActivitySummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerData_GetActivitySummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerDataServer).GetActivitySummary(ctx, req.(*PeriodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerData_ListFeedEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerDataServer).ListFeedEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerData_ListFeedEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerDataServer).ListFeedEntries(ctx, req.(*FeedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerData_ExportActivities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerDataServer).ExportActivities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerData_ExportActivities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerDataServer).ExportActivities(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TrackerData_ServiceDesc is the grpc.ServiceDesc for TrackerData service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TrackerData_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "reportdata.TrackerData",
+	HandlerType: (*TrackerDataServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetActivitySummary",
+			Handler:    _TrackerData_GetActivitySummary_Handler,
+		},
+		{
+			MethodName: "ListFeedEntries",
+			Handler:    _TrackerData_ListFeedEntries_Handler,
+		},
+		{
+			MethodName: "ExportActivities",
+			Handler:    _TrackerData_ExportActivities_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reportdata.proto",
+}
+
+const (
+	WalletData_GetCredits_FullMethodName         = "/reportdata.WalletData/GetCredits"
+	WalletData_GetPlatformTotals_FullMethodName  = "/reportdata.WalletData/GetPlatformTotals"
+	WalletData_ListFeedEntries_FullMethodName    = "/reportdata.WalletData/ListFeedEntries"
+	WalletData_ExportTransactions_FullMethodName = "/reportdata.WalletData/ExportTransactions"
+)
+
+// WalletDataClient is the client API for WalletData service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Wallet data for reports
+type WalletDataClient interface {
+	// A user's balance and completed transactions over a period
+	GetCredits(ctx context.Context, in *PeriodRequest, opts ...grpc.CallOption) (*CreditsData, error)
+	// Totals across every wallet
+	GetPlatformTotals(ctx context.Context, in *PlatformTotalsRequest, opts ...grpc.CallOption) (*WalletPlatformTotals, error)
+	// A user's most recent credit and transfer transactions before a time, newest first
+	ListFeedEntries(ctx context.Context, in *FeedRequest, opts ...grpc.CallOption) (*FeedEntries, error)
+	// Every transaction of a user, for data exports
+	ExportTransactions(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*ExportRows, error)
+}
+
+type walletDataClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWalletDataClient(cc grpc.ClientConnInterface) WalletDataClient {
+	return &walletDataClient{cc}
+}
+
+func (c *walletDataClient) GetCredits(ctx context.Context, in *PeriodRequest, opts ...grpc.CallOption) (*CreditsData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreditsData)
+	err := c.cc.Invoke(ctx, WalletData_GetCredits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletDataClient) GetPlatformTotals(ctx context.Context, in *PlatformTotalsRequest, opts ...grpc.CallOption) (*WalletPlatformTotals, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WalletPlatformTotals)
+	err := c.cc.Invoke(ctx, WalletData_GetPlatformTotals_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletDataClient) ListFeedEntries(ctx context.Context, in *FeedRequest, opts ...grpc.CallOption) (*FeedEntries, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FeedEntries)
+	err := c.cc.Invoke(ctx, WalletData_ListFeedEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletDataClient) ExportTransactions(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*ExportRows, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportRows)
+	err := c.cc.Invoke(ctx, WalletData_ExportTransactions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WalletDataServer is the server API for WalletData service.
+// All implementations must embed UnimplementedWalletDataServer
+// for forward compatibility.
+//
+// Wallet data for reports
+type WalletDataServer interface {
+	// A user's balance and completed transactions over a period
+	GetCredits(context.Context, *PeriodRequest) (*CreditsData, error)
+	// Totals across every wallet
+	GetPlatformTotals(context.Context, *PlatformTotalsRequest) (*WalletPlatformTotals, error)
+	// A user's most recent credit and transfer transactions before a time, newest first
+	ListFeedEntries(context.Context, *FeedRequest) (*FeedEntries, error)
+	// Every transaction of a user, for data exports
+	ExportTransactions(context.Context, *UserRequest) (*ExportRows, error)
+	mustEmbedUnimplementedWalletDataServer()
+}
+
+// UnimplementedWalletDataServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWalletDataServer struct{}
+
+func (UnimplementedWalletDataServer) GetCredits(context.Context, *PeriodRequest) (*CreditsData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCredits not implemented")
+}
+func (UnimplementedWalletDataServer) GetPlatformTotals(context.Context, *PlatformTotalsRequest) (*WalletPlatformTotals, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlatformTotals not implemented")
+}
+func (UnimplementedWalletDataServer) ListFeedEntries(context.Context, *FeedRequest) (*FeedEntries, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFeedEntries not implemented")
+}
+func (UnimplementedWalletDataServer) ExportTransactions(context.Context, *UserRequest) (*ExportRows, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportTransactions not implemented")
+}
+func (UnimplementedWalletDataServer) mustEmbedUnimplementedWalletDataServer() {}
+func (UnimplementedWalletDataServer) testEmbeddedByValue()                    {}
+
+// UnsafeWalletDataServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WalletDataServer will
+// result in compilation errors.
+type UnsafeWalletDataServer interface {
+	mustEmbedUnimplementedWalletDataServer()
+}
+
+func RegisterWalletDataServer(s grpc.ServiceRegistrar, srv WalletDataServer) {
+	// If the following call pancis, it indicates UnimplementedWalletDataServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WalletData_ServiceDesc, srv)
+}
+
+func _WalletData_GetCredits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeriodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletDataServer).GetCredits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletData_GetCredits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletDataServer).GetCredits(ctx, req.(*PeriodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletData_GetPlatformTotals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlatformTotalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletDataServer).GetPlatformTotals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletData_GetPlatformTotals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletDataServer).GetPlatformTotals(ctx, req.(*PlatformTotalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletData_ListFeedEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletDataServer).ListFeedEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletData_ListFeedEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletDataServer).ListFeedEntries(ctx, req.(*FeedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletData_ExportTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletDataServer).ExportTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletData_ExportTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletDataServer).ExportTransactions(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WalletData_ServiceDesc is the grpc.ServiceDesc for WalletData service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WalletData_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "reportdata.WalletData",
+	HandlerType: (*WalletDataServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCredits",
+			Handler:    _WalletData_GetCredits_Handler,
+		},
+		{
+			MethodName: "GetPlatformTotals",
+			Handler:    _WalletData_GetPlatformTotals_Handler,
+		},
+		{
+			MethodName: "ListFeedEntries",
+			Handler:    _WalletData_ListFeedEntries_Handler,
+		},
+		{
+			MethodName: "ExportTransactions",
+			Handler:    _WalletData_ExportTransactions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reportdata.proto",
+}
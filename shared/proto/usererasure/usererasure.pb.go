@@ -0,0 +1,176 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        v5.29.3
+// source: usererasure.proto
+
+package usererasure
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CheckErasableRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckErasableRequest) Reset() {
+	*x = CheckErasableRequest{}
+	mi := &file_usererasure_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckErasableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckErasableRequest) ProtoMessage() {}
+
+func (x *CheckErasableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_usererasure_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckErasableRequest.ProtoReflect.Descriptor instead.
+func (*CheckErasableRequest) Descriptor() ([]byte, []int) {
+	return file_usererasure_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CheckErasableRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type CheckErasableResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckErasableResponse) Reset() {
+	*x = CheckErasableResponse{}
+	mi := &file_usererasure_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckErasableResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckErasableResponse) ProtoMessage() {}
+
+func (x *CheckErasableResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_usererasure_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckErasableResponse.ProtoReflect.Descriptor instead.
+func (*CheckErasableResponse) Descriptor() ([]byte, []int) {
+	return file_usererasure_proto_rawDescGZIP(), []int{1}
+}
+
+var File_usererasure_proto protoreflect.FileDescriptor
+
+var file_usererasure_proto_rawDesc = string([]byte{
+	0x0a, 0x11, 0x75, 0x73, 0x65, 0x72, 0x65, 0x72, 0x61, 0x73, 0x75, 0x72, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x75, 0x73, 0x65, 0x72, 0x65, 0x72, 0x61, 0x73, 0x75, 0x72, 0x65,
+	0x22, 0x2f, 0x0a, 0x14, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x45, 0x72, 0x61, 0x73, 0x61, 0x62, 0x6c,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x64, 0x22, 0x17, 0x0a, 0x15, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x45, 0x72, 0x61, 0x73, 0x61, 0x62,
+	0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x65, 0x0a, 0x0b, 0x55, 0x73,
+	0x65, 0x72, 0x45, 0x72, 0x61, 0x73, 0x75, 0x72, 0x65, 0x12, 0x56, 0x0a, 0x0d, 0x43, 0x68, 0x65,
+	0x63, 0x6b, 0x45, 0x72, 0x61, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x21, 0x2e, 0x75, 0x73, 0x65,
+	0x72, 0x65, 0x72, 0x61, 0x73, 0x75, 0x72, 0x65, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x45, 0x72,
+	0x61, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e,
+	0x75, 0x73, 0x65, 0x72, 0x65, 0x72, 0x61, 0x73, 0x75, 0x72, 0x65, 0x2e, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x45, 0x72, 0x61, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x42, 0x3a, 0x5a, 0x38, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x73, 0x6c, 0x6f, 0x77, 0x65, 0x79, 0x79, 0x79, 0x2f, 0x47, 0x72, 0x65, 0x65, 0x6e, 0x4c, 0x65,
+	0x64, 0x67, 0x65, 0x72, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x65, 0x72, 0x61, 0x73, 0x75, 0x72, 0x65, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_usererasure_proto_rawDescOnce sync.Once
+	file_usererasure_proto_rawDescData []byte
+)
+
+func file_usererasure_proto_rawDescGZIP() []byte {
+	file_usererasure_proto_rawDescOnce.Do(func() {
+		file_usererasure_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_usererasure_proto_rawDesc), len(file_usererasure_proto_rawDesc)))
+	})
+	return file_usererasure_proto_rawDescData
+}
+
+var file_usererasure_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_usererasure_proto_goTypes = []any{
+	(*CheckErasableRequest)(nil),  // 0: usererasure.CheckErasableRequest
+	(*CheckErasableResponse)(nil), // 1: usererasure.CheckErasableResponse
+}
+var file_usererasure_proto_depIdxs = []int32{
+	0, // 0: usererasure.UserErasure.CheckErasable:input_type -> usererasure.CheckErasableRequest
+	1, // 1: usererasure.UserErasure.CheckErasable:output_type -> usererasure.CheckErasableResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_usererasure_proto_init() }
+func file_usererasure_proto_init() {
+	if File_usererasure_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_usererasure_proto_rawDesc), len(file_usererasure_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_usererasure_proto_goTypes,
+		DependencyIndexes: file_usererasure_proto_depIdxs,
+		MessageInfos:      file_usererasure_proto_msgTypes,
+	}.Build()
+	File_usererasure_proto = out.File
+	file_usererasure_proto_goTypes = nil
+	file_usererasure_proto_depIdxs = nil
+}
@@ -0,0 +1,137 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: usererasure.proto
+
+package usererasure
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UserErasure_CheckErasable_FullMethodName = "/usererasure.UserErasure/CheckErasable"
+)
+
+// UserErasureClient is the client API for UserErasure service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserErasure lets the user-auth service ask the services that hold a user's
+// assets whether the user's account can be erased, instead of reading their
+// databases. It is internal and served on the wallet and certifier services'
+// gRPC ports.
+type UserErasureClient interface {
+	// Checks that a user holds nothing in the serving service that must be
+	// settled before their account is erased. Fails with FAILED_PRECONDITION,
+	// describing what is still held, when they do.
+	CheckErasable(ctx context.Context, in *CheckErasableRequest, opts ...grpc.CallOption) (*CheckErasableResponse, error)
+}
+
+type userErasureClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserErasureClient(cc grpc.ClientConnInterface) UserErasureClient {
+	return &userErasureClient{cc}
+}
+
+func (c *userErasureClient) CheckErasable(ctx context.Context, in *CheckErasableRequest, opts ...grpc.CallOption) (*CheckErasableResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckErasableResponse)
+	err := c.cc.Invoke(ctx, UserErasure_CheckErasable_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserErasureServer is the server API for UserErasure service.
+// All implementations must embed UnimplementedUserErasureServer
+// for forward compatibility.
+//
+// UserErasure lets the user-auth service ask the services that hold a user's
+// assets whether the user's account can be erased, instead of reading their
+// databases. It is internal and served on the wallet and certifier services'
+// gRPC ports.
+type UserErasureServer interface {
+	// Checks that a user holds nothing in the serving service that must be
+	// settled before their account is erased. Fails with FAILED_PRECONDITION,
+	// describing what is still held, when they do.
+	CheckErasable(context.Context, *CheckErasableRequest) (*CheckErasableResponse, error)
+	mustEmbedUnimplementedUserErasureServer()
+}
+
+// UnimplementedUserErasureServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserErasureServer struct{}
+
+func (UnimplementedUserErasureServer) CheckErasable(context.Context, *CheckErasableRequest) (*CheckErasableResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckErasable not implemented")
+}
+func (UnimplementedUserErasureServer) mustEmbedUnimplementedUserErasureServer() {}
+func (UnimplementedUserErasureServer) testEmbeddedByValue()                     {}
+
+// UnsafeUserErasureServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserErasureServer will
+// result in compilation errors.
+type UnsafeUserErasureServer interface {
+	mustEmbedUnimplementedUserErasureServer()
+}
+
+func RegisterUserErasureServer(s grpc.ServiceRegistrar, srv UserErasureServer) {
+	// If the following call pancis, it indicates UnimplementedUserErasureServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserErasure_ServiceDesc, srv)
+}
+
+func _UserErasure_CheckErasable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckErasableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserErasureServer).CheckErasable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserErasure_CheckErasable_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserErasureServer).CheckErasable(ctx, req.(*CheckErasableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserErasure_ServiceDesc is the grpc.ServiceDesc for UserErasure service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserErasure_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "usererasure.UserErasure",
+	HandlerType: (*UserErasureServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CheckErasable",
+			Handler:    _UserErasure_CheckErasable_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "usererasure.proto",
+}
@@ -0,0 +1,177 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: walletcredits.proto
+
+package walletcredits
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WalletCredits_DebitCredits_FullMethodName    = "/walletcredits.WalletCredits/DebitCredits"
+	WalletCredits_TransferCredits_FullMethodName = "/walletcredits.WalletCredits/TransferCredits"
+)
+
+// WalletCreditsClient is the client API for WalletCredits service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WalletCredits lets other services spend a user's credits. It is internal
+// and served on the wallet service's gRPC port.
+type WalletCreditsClient interface {
+	// Debits credits from a user's wallet. Debits are idempotent per
+	// reference_id: repeating one returns the original transaction. Fails with
+	// FAILED_PRECONDITION when the user's available balance is too low.
+	DebitCredits(ctx context.Context, in *DebitCreditsRequest, opts ...grpc.CallOption) (*DebitCreditsResponse, error)
+	// Moves credits from one user's wallet to another's. Transfers are
+	// idempotent per reference_id like debits. Fails with FAILED_PRECONDITION
+	// when the sender's available balance is too low.
+	TransferCredits(ctx context.Context, in *TransferCreditsRequest, opts ...grpc.CallOption) (*TransferCreditsResponse, error)
+}
+
+type walletCreditsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWalletCreditsClient(cc grpc.ClientConnInterface) WalletCreditsClient {
+	return &walletCreditsClient{cc}
+}
+
+func (c *walletCreditsClient) DebitCredits(ctx context.Context, in *DebitCreditsRequest, opts ...grpc.CallOption) (*DebitCreditsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DebitCreditsResponse)
+	err := c.cc.Invoke(ctx, WalletCredits_DebitCredits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletCreditsClient) TransferCredits(ctx context.Context, in *TransferCreditsRequest, opts ...grpc.CallOption) (*TransferCreditsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferCreditsResponse)
+	err := c.cc.Invoke(ctx, WalletCredits_TransferCredits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WalletCreditsServer is the server API for WalletCredits service.
+// All implementations must embed UnimplementedWalletCreditsServer
+// for forward compatibility.
+//
+// WalletCredits lets other services spend a user's credits. It is internal
+// and served on the wallet service's gRPC port.
+type WalletCreditsServer interface {
+	// Debits credits from a user's wallet. Debits are idempotent per
+	// reference_id: repeating one returns the original transaction. Fails with
+	// FAILED_PRECONDITION when the user's available balance is too low.
+	DebitCredits(context.Context, *DebitCreditsRequest) (*DebitCreditsResponse, error)
+	// Moves credits from one user's wallet to another's. Transfers are
+	// idempotent per reference_id like debits. Fails with FAILED_PRECONDITION
+	// when the sender's available balance is too low.
+	TransferCredits(context.Context, *TransferCreditsRequest) (*TransferCreditsResponse, error)
+	mustEmbedUnimplementedWalletCreditsServer()
+}
+
+// UnimplementedWalletCreditsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWalletCreditsServer struct{}
+
+func (UnimplementedWalletCreditsServer) DebitCredits(context.Context, *DebitCreditsRequest) (*DebitCreditsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DebitCredits not implemented")
+}
+func (UnimplementedWalletCreditsServer) TransferCredits(context.Context, *TransferCreditsRequest) (*TransferCreditsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferCredits not implemented")
+}
+func (UnimplementedWalletCreditsServer) mustEmbedUnimplementedWalletCreditsServer() {}
+func (UnimplementedWalletCreditsServer) testEmbeddedByValue()                       {}
+
+// UnsafeWalletCreditsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WalletCreditsServer will
+// result in compilation errors.
+type UnsafeWalletCreditsServer interface {
+	mustEmbedUnimplementedWalletCreditsServer()
+}
+
+func RegisterWalletCreditsServer(s grpc.ServiceRegistrar, srv WalletCreditsServer) {
+	// If the following call pancis, it indicates UnimplementedWalletCreditsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WalletCredits_ServiceDesc, srv)
+}
+
+func _WalletCredits_DebitCredits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DebitCreditsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletCreditsServer).DebitCredits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletCredits_DebitCredits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletCreditsServer).DebitCredits(ctx, req.(*DebitCreditsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletCredits_TransferCredits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferCreditsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletCreditsServer).TransferCredits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletCredits_TransferCredits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletCreditsServer).TransferCredits(ctx, req.(*TransferCreditsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WalletCredits_ServiceDesc is the grpc.ServiceDesc for WalletCredits service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WalletCredits_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "walletcredits.WalletCredits",
+	HandlerType: (*WalletCreditsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DebitCredits",
+			Handler:    _WalletCredits_DebitCredits_Handler,
+		},
+		{
+			MethodName: "TransferCredits",
+			Handler:    _WalletCredits_TransferCredits_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "walletcredits.proto",
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"google.golang.org/grpc"
+)
+
+// ServeGRPC serves srv on addr in the background. Closing the returned
+// closer stops it, letting in-flight calls finish within ShutdownTimeout, so
+// pass it to RunWithGracefulShutdown ahead of the resources its handlers use.
+func ServeGRPC(srv *grpc.Server, addr string, log *logger.Logger) (io.Closer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for gRPC on %s: %w", addr, err)
+	}
+
+	go func() {
+		if err := srv.Serve(listener); err != nil {
+			log.LogError(context.Background(), "gRPC server stopped", err)
+		}
+	}()
+
+	return &grpcCloser{srv: srv, timeout: ShutdownTimeout}, nil
+}
+
+// grpcCloser stops a gRPC server gracefully, forcing it after a timeout
+type grpcCloser struct {
+	srv     *grpc.Server
+	timeout time.Duration
+}
+
+func (c *grpcCloser) Close() error {
+	stopped := make(chan struct{})
+	go func() {
+		c.srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(c.timeout):
+		c.srv.Stop()
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+	"google.golang.org/grpc"
+)
+
+func TestServeGRPC_StopsOnClose(t *testing.T) {
+	closer, err := ServeGRPC(grpc.NewServer(), "127.0.0.1:0", logger.New("error"))
+	if err != nil {
+		t.Fatalf("expected server to start, got %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- closer.Close() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected clean stop, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("gRPC server did not stop")
+	}
+}
+
+func TestServeGRPC_ReturnsListenError(t *testing.T) {
+	if _, err := ServeGRPC(grpc.NewServer(), "invalid-address", logger.New("error")); err == nil {
+		t.Fatal("expected listen error")
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+// ShutdownTimeout bounds how long in-flight requests get to drain
+const ShutdownTimeout = 30 * time.Second
+
+// ShutdownSignals are the signals that trigger a graceful shutdown
+var ShutdownSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// NotifyContext returns a context that is cancelled when the process receives
+// one of the shutdown signals. Background workers such as event consumers
+// should run with this context so they stop before their resources are closed.
+func NotifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(parent, ShutdownSignals...)
+}
+
+// RunWithGracefulShutdown serves srv until ctx is cancelled, then drains it
+// within ShutdownTimeout and closes closers in the order given. Pass
+// consumers before publishers and the database last so nothing is still
+// writing to a resource that has already been released. It returns the error
+// that stopped the server, if any, joined with any errors from the closers.
+func RunWithGracefulShutdown(ctx context.Context, srv *http.Server, log *logger.Logger, closers ...io.Closer) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+		close(serveErr)
+	}()
+
+	var errs []error
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			log.LogError(context.Background(), "failed to start server", err)
+			errs = append(errs, fmt.Errorf("failed to start server: %w", err))
+		}
+	case <-ctx.Done():
+		log.LogInfo(context.Background(), "shutting down server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.LogError(context.Background(), "server forced to shutdown", err)
+			errs = append(errs, fmt.Errorf("server forced to shutdown: %w", err))
+		}
+	}
+
+	for _, closer := range closers {
+		if closer == nil {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			log.LogError(context.Background(), "failed to close resource", err)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sloweyyy/GreenLedger/shared/logger"
+)
+
+type recordingCloser struct {
+	name  string
+	order *[]string
+	err   error
+}
+
+func (c *recordingCloser) Close() error {
+	*c.order = append(*c.order, c.name)
+	return c.err
+}
+
+func TestRunWithGracefulShutdown_ClosesResourcesInOrder(t *testing.T) {
+	var order []string
+	closeErr := errors.New("close failed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := &http.Server{Addr: "127.0.0.1:0", Handler: http.NotFoundHandler()}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- RunWithGracefulShutdown(ctx, srv, logger.New("error"),
+			&recordingCloser{name: "consumer", order: &order},
+			nil,
+			&recordingCloser{name: "publisher", order: &order, err: closeErr},
+			&recordingCloser{name: "db", order: &order},
+		)
+	}()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, closeErr) {
+			t.Fatalf("expected close error to be returned, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+
+	want := []string{"consumer", "publisher", "db"}
+	if len(order) != len(want) {
+		t.Fatalf("expected closers %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected closers %v, got %v", want, order)
+		}
+	}
+}
+
+func TestRunWithGracefulShutdown_ReturnsListenError(t *testing.T) {
+	var order []string
+	srv := &http.Server{Addr: "invalid-address", Handler: http.NotFoundHandler()}
+
+	err := RunWithGracefulShutdown(context.Background(), srv, logger.New("error"),
+		&recordingCloser{name: "db", order: &order})
+	if err == nil {
+		t.Fatal("expected listen error")
+	}
+	if len(order) != 1 {
+		t.Fatalf("expected resources to be closed after a listen error, got %v", order)
+	}
+}
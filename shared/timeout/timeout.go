@@ -0,0 +1,35 @@
+package timeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrExceeded is returned when an operation run through Do overruns its timeout
+var ErrExceeded = errors.New("operation timed out")
+
+// WithTimeout derives a context that is cancelled after d. A non-positive d
+// adds no deadline, so a timeout can be disabled through configuration.
+func WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// Do runs fn with a context bounded by d, so database queries and service
+// calls made with it are cancelled when it overruns. An error caused by the
+// timeout wraps ErrExceeded; errors from the caller's own cancellation or
+// deadline are returned unchanged.
+func Do[T any](ctx context.Context, d time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	bounded, cancel := WithTimeout(ctx, d)
+	defer cancel()
+
+	result, err := fn(bounded)
+	if err != nil && ctx.Err() == nil && errors.Is(bounded.Err(), context.DeadlineExceeded) && !errors.Is(err, ErrExceeded) {
+		return result, fmt.Errorf("%w after %s: %w", ErrExceeded, d, err)
+	}
+	return result, err
+}
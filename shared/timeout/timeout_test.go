@@ -0,0 +1,40 @@
+package timeout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDo(t *testing.T) {
+	wait := func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+
+	if _, err := Do(context.Background(), time.Millisecond, wait); !errors.Is(err, ErrExceeded) {
+		t.Errorf("expected ErrExceeded for an overrun, got %v", err)
+	}
+
+	parent, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Do(parent, time.Minute, wait); errors.Is(err, ErrExceeded) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the caller's cancellation unchanged, got %v", err)
+	}
+
+	failure := errors.New("query failed")
+	if _, err := Do(context.Background(), time.Minute, func(ctx context.Context) (int, error) { return 0, failure }); err != failure {
+		t.Errorf("expected errors within the timeout unchanged, got %v", err)
+	}
+
+	value, err := Do(context.Background(), 0, func(ctx context.Context) (int, error) {
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("expected a zero timeout to add no deadline")
+		}
+		return 42, nil
+	})
+	if err != nil || value != 42 {
+		t.Errorf("expected 42 with no error, got %d and %v", value, err)
+	}
+}
@@ -0,0 +1,35 @@
+package version
+
+import (
+	"net/http"
+	"runtime"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Info describes the build that is currently running
+type Info struct {
+	Service   string `json:"service"`
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// New creates build info for a service from values injected via ldflags
+func New(service, version, gitCommit, buildTime string) Info {
+	return Info{
+		Service:   service,
+		Version:   version,
+		GitCommit: gitCommit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// Handler returns a Gin handler for the /version endpoint
+func Handler(info Info) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, info)
+	}
+}